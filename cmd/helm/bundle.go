@@ -0,0 +1,351 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/bundle"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/downloader"
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/manifest"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+const bundleDesc = `
+Create and install bundles, archives holding everything needed to install a
+chart without network access, for air-gapped and edge environments.
+
+A bundle holds the archives of a chart and of its dependencies, the index of a
+chart repository describing them, and the list of the container images the
+chart uses. Create it where the chart repositories are reachable:
+
+    $ helm bundle create stable/mariadb --version 5.5.0
+
+copy it to the environment, mirror its images into a registry the cluster can
+pull from, and install it from there:
+
+    $ helm bundle load mariadb-5.5.0.bundle.tgz --name my-db
+`
+
+const bundleCreateDesc = `
+This command writes a bundle of a chart, given as a path or as a chart
+reference like 'helm fetch', to CHART-VERSION.bundle.tgz.
+
+The dependencies of the chart are taken from its 'charts/' directory; use
+'--dependency-update' to update them from 'requirements.yaml' first. The
+images are found by rendering the chart with the given values and looking at
+the containers and init containers of every pod template, including those of
+CronJobs. Images the chart only uses with other values can be added with
+'--image'.
+`
+
+const bundleLoadDesc = `
+This command installs the chart of a bundle, without any network access but to
+Tiller. The digests of the charts of the bundle are checked against its index
+first. It takes the same values as 'helm install'.
+`
+
+const bundleExtractDesc = `
+This command writes the files of a bundle to a directory: 'bundle.yaml', the
+chart archives in 'charts/' and their 'index.yaml'. The directory can then be
+served as a chart repository, for example by 'helm serve --repo-path DIR'.
+`
+
+func newBundleCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle create|load|extract",
+		Short: "Create and install bundles of charts for air-gapped environments",
+		Long:  bundleDesc,
+	}
+
+	cmd.AddCommand(newBundleCreateCmd(out))
+	cmd.AddCommand(newBundleLoadCmd(client, out))
+	cmd.AddCommand(newBundleExtractCmd(out))
+
+	return cmd
+}
+
+type bundleCreateCmd struct {
+	chart        string
+	version      string
+	repoURL      string
+	verify       bool
+	keyring      string
+	destination  string
+	depUp        bool
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+	images       []string
+
+	out io.Writer
+}
+
+func newBundleCreateCmd(out io.Writer) *cobra.Command {
+	bc := &bundleCreateCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "create [flags] CHART",
+		Short: "Create a bundle of a chart, its dependencies and images",
+		Long:  bundleCreateDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart name"); err != nil {
+				return err
+			}
+			bc.chart = args[0]
+			return bc.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&bc.version, "version", "", "Specify the exact chart version to bundle. If this is not specified, the latest version is bundled")
+	f.StringVar(&bc.repoURL, "repo", "", "Chart repository url where to locate the requested chart")
+	f.BoolVar(&bc.verify, "verify", false, "Verify the package before bundling it")
+	f.StringVar(&bc.keyring, "keyring", defaultKeyring(), "Location of public keys used for verification")
+	f.StringVarP(&bc.destination, "destination", "d", ".", "Location to write the bundle to")
+	f.BoolVarP(&bc.depUp, "dependency-update", "u", false, `Update dependencies from "requirements.yaml" to dir "charts/" before bundling`)
+	f.VarP(&bc.valueFiles, "values", "f", "Specify values in a YAML file or a URL(can specify multiple)")
+	f.StringArrayVar(&bc.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&bc.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&bc.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	f.StringArrayVar(&bc.images, "image", []string{}, "Add an image to the images of the bundle (can specify multiple)")
+
+	return cmd
+}
+
+func (b *bundleCreateCmd) run() error {
+	path, err := locateChartPath(b.repoURL, "", "", b.chart, b.version, b.verify, b.keyring, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() && b.depUp {
+		man := &downloader.Manager{
+			Out:       b.out,
+			ChartPath: path,
+			HelmHome:  settings.Home,
+			Keyring:   b.keyring,
+			Getters:   getter.All(settings),
+			Debug:     settings.Debug,
+		}
+		if err := man.Update(); err != nil {
+			return err
+		}
+	}
+
+	ch, err := chartutil.Load(path)
+	if err != nil {
+		return prettyError(err)
+	}
+	if reqs, err := chartutil.LoadRequirements(ch); err == nil {
+		if err := renderutil.CheckDependencies(ch, reqs); err != nil {
+			return err
+		}
+	} else if err != chartutil.ErrRequirementsNotFound {
+		return fmt.Errorf("cannot load requirements: %v", err)
+	}
+
+	rawVals, err := vals(b.valueFiles, b.values, b.stringValues, b.fileValues, "", "", "")
+	if err != nil {
+		return err
+	}
+	images, err := chartImages(ch, rawVals)
+	if err != nil {
+		return err
+	}
+	for _, image := range b.images {
+		images = append(images, releaseutil.ImageReference{Image: image})
+	}
+
+	dest := b.destination
+	if dest == "." {
+		if dest, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+	filename, err := bundle.Create(ch, releaseutil.UniqueImages(images), dest)
+	if err != nil {
+		return fmt.Errorf("Failed to save: %s", err)
+	}
+	fmt.Fprintf(b.out, "Successfully bundled chart and saved it to: %s\n", filename)
+	writeBundleImages(b.out, releaseutil.UniqueImages(images))
+	return nil
+}
+
+// chartImages renders a chart as for an install and returns the images its
+// manifests refer to, in the order of the templates.
+func chartImages(c *chart.Chart, rawVals []byte) ([]releaseutil.ImageReference, error) {
+	rendered, err := renderutil.Render(c, &chart.Config{Raw: string(rawVals)}, renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      "release-name",
+			IsInstall: true,
+			Revision:  1,
+			Time:      timeconv.Now(),
+			Namespace: defaultNamespace(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := manifest.SplitManifests(rendered)
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].Name < manifests[j].Name })
+
+	var refs []releaseutil.ImageReference
+	for _, m := range manifests {
+		b := filepath.Base(m.Name)
+		if b == "NOTES.txt" || isOutputsFile(m.Name) || strings.HasPrefix(b, "_") {
+			continue
+		}
+		found, err := releaseutil.FindImages(m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", m.Name, err)
+		}
+		refs = append(refs, found...)
+	}
+	return refs, nil
+}
+
+func writeBundleImages(out io.Writer, images []string) {
+	if len(images) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "Images:")
+	for _, image := range images {
+		fmt.Fprintf(out, "  %s\n", image)
+	}
+}
+
+type bundleLoadCmd struct {
+	installCmd
+	bundle string
+}
+
+func newBundleLoadCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	bl := &bundleLoadCmd{installCmd: installCmd{out: out, client: c}}
+
+	cmd := &cobra.Command{
+		Use:     "load [flags] BUNDLE",
+		Short:   "Install the chart of a bundle",
+		Long:    bundleLoadDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnectionIn(bl.namespace) },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "bundle path"); err != nil {
+				return err
+			}
+			bl.bundle = args[0]
+			bl.client = ensureHelmClient(bl.client)
+			bl.wait = bl.wait || bl.atomic
+			return bl.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.VarP(&bl.valueFiles, "values", "f", "Specify values in a YAML file or a URL(can specify multiple)")
+	f.StringVarP(&bl.name, "name", "n", "", "The release name. If unspecified, it will autogenerate one for you")
+	f.StringVar(&bl.namespace, "namespace", "", "Namespace to install the release into. Defaults to the current kube config namespace.")
+	f.BoolVar(&bl.dryRun, "dry-run", false, "Simulate an install")
+	f.BoolVar(&bl.disableHooks, "no-hooks", false, "Prevent hooks from running during install")
+	f.BoolVar(&bl.replace, "replace", false, "Re-use the given name, even if that name is already used. This is unsafe in production")
+	f.StringArrayVar(&bl.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&bl.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&bl.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	helm_env.TimeoutVar(f, &bl.timeout, "timeout", 300, "Time to wait for any individual Kubernetes operation (like Jobs for hooks), as a duration such as 5m30s or in seconds")
+	f.BoolVar(&bl.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+	f.BoolVar(&bl.atomic, "atomic", false, "If set, installation process purges chart on fail, also sets --wait flag")
+	f.BoolVar(&bl.createNs, "create-namespace", false, "Create the release namespace if not present")
+	f.StringVar(&bl.description, "description", "", "Specify a description for the release")
+	f.BoolVar(&bl.quiet, "quiet", false, "Print only the name of the release on success")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (b *bundleLoadCmd) run() error {
+	bl, err := bundle.Load(b.bundle)
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "helm-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := bl.Extract(dir); err != nil {
+		return err
+	}
+
+	b.chartPath = filepath.Join(dir, filepath.FromSlash(bl.Metadata.Chart))
+	return b.installCmd.run()
+}
+
+type bundleExtractCmd struct {
+	bundle string
+	dir    string
+	out    io.Writer
+}
+
+func newBundleExtractCmd(out io.Writer) *cobra.Command {
+	be := &bundleExtractCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "extract [flags] BUNDLE DIR",
+		Short: "Extract the charts and index of a bundle to a directory",
+		Long:  bundleExtractDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "bundle path", "directory"); err != nil {
+				return err
+			}
+			be.bundle, be.dir = args[0], args[1]
+			return be.run()
+		},
+	}
+	return cmd
+}
+
+func (e *bundleExtractCmd) run() error {
+	b, err := bundle.Load(e.bundle)
+	if err != nil {
+		return err
+	}
+	if err := b.Extract(e.dir); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "Extracted bundle of %s %s to %s\n", b.Metadata.Name, b.Metadata.Version, e.dir)
+	writeBundleImages(e.out, b.Metadata.Images)
+	return nil
+}
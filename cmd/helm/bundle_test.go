@@ -0,0 +1,94 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestBundleCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-bundle-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bundlePath := filepath.Join(dir, "alpine-0.1.0.bundle.tgz")
+
+	createTests := []releaseCase{
+		{
+			name:     "create a bundle",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    []string{"--destination", dir, "--set", "test.Name=bundle", "--image", "busybox:1.30"},
+			expected: "Successfully bundled chart and saved it to: .*alpine-0.1.0.bundle.tgz\nImages:\n  alpine:3.3\n  busybox:1.30\n",
+		},
+		{
+			name: "create requires a chart",
+			err:  true,
+		},
+		{
+			name:  "create with missing dependencies",
+			args:  []string{"testdata/testcharts/chart-missing-deps"},
+			flags: []string{"--destination", dir},
+			err:   true,
+		},
+	}
+	runReleaseCases(t, createTests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newBundleCreateCmd(out)
+	})
+
+	loadTests := []releaseCase{
+		{
+			name:     "load a bundle",
+			args:     []string{bundlePath},
+			flags:    []string{"--name", "aeneas"},
+			expected: "aeneas",
+		},
+		{
+			name: "load a missing bundle",
+			args: []string{filepath.Join(dir, "missing.bundle.tgz")},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, loadTests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newBundleLoadCmd(c, out)
+	})
+
+	extractDir := filepath.Join(dir, "repo")
+	extractTests := []releaseCase{
+		{
+			name:     "extract a bundle",
+			args:     []string{bundlePath, extractDir},
+			expected: "Extracted bundle of alpine 0.1.0 to .*\nImages:\n  alpine:3.3\n  busybox:1.30\n",
+		},
+	}
+	runReleaseCases(t, extractTests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newBundleExtractCmd(out)
+	})
+	for _, name := range []string{"bundle.yaml", "index.yaml", "charts/alpine-0.1.0.tgz"} {
+		if _, err := os.Stat(filepath.Join(extractDir, name)); err != nil {
+			t.Errorf("expected %s to be extracted: %s", name, err)
+		}
+	}
+}
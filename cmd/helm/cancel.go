@@ -0,0 +1,85 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const cancelDesc = `
+This command cancels the install, upgrade or rollback in progress on a release.
+
+Tiller stops waiting on the resources and hooks of the operation, which then
+fails like any other failed operation: the release is marked as failed, and
+'--atomic' or '--cleanup-on-fail' apply as usual. Resources already applied to
+the cluster are left as they are.
+
+An operation is also cancelled when the helm command that started it is
+interrupted, for example with Ctrl-C, so that Tiller does not keep working on
+an abandoned operation.
+`
+
+type cancelCmd struct {
+	release string
+	quiet   bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newCancelCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	c := &cancelCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "cancel [flags] RELEASE_NAME",
+		Short:   "Cancel the operation in progress on a release",
+		Long:    cancelDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			c.release = args[0]
+			c.client = ensureHelmClient(c.client)
+			return c.run()
+		},
+	}
+
+	flags := cmd.Flags()
+	settings.AddFlagsTLS(flags)
+	flags.BoolVar(&c.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(flags)
+
+	return cmd
+}
+
+func (c *cancelCmd) run() error {
+	res, err := c.client.CancelRelease(c.release)
+	if err != nil {
+		return prettyError(err)
+	}
+	printMessage(c.out, c.quiet, msgCancelled, res.Operation, c.release, res.Started)
+	return nil
+}
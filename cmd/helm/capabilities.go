@@ -0,0 +1,122 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const capabilitiesDesc = `
+This command shows the capabilities of the cluster Tiller renders charts for:
+its Kubernetes version and the API versions it serves, as seen by the
+'.Capabilities' object of the templates.
+
+Tiller discovers the capabilities once and caches them for the time given by
+its '--capabilities-ttl' flag, five minutes by default, instead of running the
+discovery for every release. Since each Tiller manages a single cluster, the
+cache is kept per Kubernetes context. After installing new custom resource
+definitions, refresh the cache so that the next releases see them:
+
+	$ helm capabilities refresh
+`
+
+const capabilitiesRefreshDesc = `
+This command makes Tiller discover the capabilities of the cluster again,
+instead of using the cached ones.
+`
+
+type capabilitiesCmd struct {
+	refresh bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newCapabilitiesCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	c := &capabilitiesCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "capabilities",
+		Short:   "Show the capabilities of the cluster",
+		Long:    capabilitiesDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c.client = ensureHelmClient(c.client)
+			return c.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	cmd.AddCommand(newCapabilitiesRefreshCmd(client, out))
+
+	return cmd
+}
+
+func newCapabilitiesRefreshCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	c := &capabilitiesCmd{
+		refresh: true,
+		out:     out,
+		client:  client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "refresh",
+		Short:   "Discover the capabilities of the cluster again",
+		Long:    capabilitiesRefreshDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c.client = ensureHelmClient(c.client)
+			return c.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (c *capabilitiesCmd) run() error {
+	res, err := c.client.GetCapabilities(helm.CapabilitiesRefresh(c.refresh))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	fmt.Fprintf(c.out, "KUBERNETES VERSION: %s\n", res.KubeVersion)
+	fmt.Fprintf(c.out, "DISCOVERED: %s ago\n", time.Duration(res.Age)*time.Second)
+	fmt.Fprintln(c.out, "API VERSIONS:")
+	for _, v := range res.ApiVersions {
+		fmt.Fprintf(c.out, "  %s\n", v)
+	}
+	return nil
+}
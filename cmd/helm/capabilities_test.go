@@ -0,0 +1,41 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestCapabilitiesCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "show the capabilities of the cluster",
+			expected: "^KUBERNETES VERSION: v1.14.0\nDISCOVERED: 0s ago\nAPI VERSIONS:\n  apps/v1\n  v1\n$",
+		},
+	}
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newCapabilitiesCmd(c, out)
+	})
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newCapabilitiesRefreshCmd(c, out)
+	})
+}
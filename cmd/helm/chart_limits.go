@@ -0,0 +1,193 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/tiller/config"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+const chartLimitsDesc = `
+This command renders a chart locally, and compares the size of its manifest and
+hooks, the number of objects and the number of hooks with the limits Tiller
+enforces for the chart.
+
+Operators set the limits in the 'limits' section of the Tiller configuration
+file. The limits of a chart can be raised or lowered by name:
+
+	limits:
+	  maxManifestBytes: 1048576
+	  maxObjects: 200
+	  maxHooks: 20
+	  charts:
+	    big-chart:
+	      maxObjects: 1000
+
+Tiller fails the install or upgrade of a chart exceeding a limit before
+anything is applied to the cluster. This command fails in the same case, so it
+can be used to check a chart before releasing it. The size is measured the way
+Tiller measures it, but may differ slightly for charts whose output depends on
+the cluster.
+`
+
+type chartLimitsCmd struct {
+	chartPath    string
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+	out          io.Writer
+	client       helm.Interface
+}
+
+// chartSize is what a chart renders, as measured by Tiller's limits.
+type chartSize struct {
+	manifestBytes int64
+	objects       int64
+	hooks         int64
+}
+
+func newChartLimitsCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	l := &chartLimitsCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "limits [flags] CHART",
+		Short:   "Compare what a chart renders with the limits of Tiller",
+		Long:    chartLimitsDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("chart is required")
+			}
+			l.chartPath = args[0]
+			l.client = ensureHelmClient(l.client)
+			return l.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.VarP(&l.valueFiles, "values", "f", "Specify values in a YAML file (can specify multiple)")
+	f.StringArrayVar(&l.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&l.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&l.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	settings.AddFlagsTLS(f)
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (l *chartLimitsCmd) run() error {
+	c, err := chartutil.Load(l.chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	rawVals, err := vals(l.valueFiles, l.values, l.stringValues, l.fileValues, "", "", "")
+	if err != nil {
+		return err
+	}
+	files, err := renderutil.Render(c, &chart.Config{Raw: string(rawVals)}, renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      "release-name",
+			IsInstall: true,
+			Revision:  1,
+			Time:      timeconv.Now(),
+			Namespace: defaultNamespace(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	size, err := measureChart(files)
+	if err != nil {
+		return err
+	}
+
+	res, err := l.client.GetCapabilities(helm.CapabilitiesChartName(c.Metadata.Name))
+	if err != nil {
+		return prettyError(err)
+	}
+	limits := config.ChartLimits{
+		MaxManifestBytes: res.MaxManifestBytes,
+		MaxObjects:       res.MaxObjects,
+		MaxHooks:         res.MaxHooks,
+	}
+
+	table := uitable.New()
+	table.AddRow("LIMIT", "RENDERED", "MAXIMUM")
+	table.AddRow("manifest bytes", size.manifestBytes, formatLimit(limits.MaxManifestBytes))
+	table.AddRow("objects", size.objects, formatLimit(limits.MaxObjects))
+	table.AddRow("hooks", size.hooks, formatLimit(limits.MaxHooks))
+	fmt.Fprintf(l.out, "CHART: %s\n%s\n", c.Metadata.Name, table)
+
+	return limits.Check(c.Metadata.Name, size.manifestBytes, size.objects, size.hooks)
+}
+
+// measureChart counts the objects and hooks of rendered templates, and the
+// size of the manifest and hooks Tiller would build from them.
+func measureChart(files map[string]string) (chartSize, error) {
+	var size chartSize
+	for name, content := range files {
+		if strings.HasPrefix(path.Base(name), "_") || strings.HasSuffix(name, "NOTES.txt") || isOutputsFile(name) {
+			continue
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		for _, doc := range releaseutil.SplitManifests(content) {
+			var head releaseutil.SimpleHead
+			if err := yaml.Unmarshal([]byte(doc), &head); err != nil {
+				return size, fmt.Errorf("YAML parse error on %s: %s", name, err)
+			}
+			if head.Metadata != nil && head.Metadata.Annotations[hooks.HookAnno] != "" {
+				size.hooks++
+				size.manifestBytes += int64(len(doc))
+				continue
+			}
+			size.objects++
+			size.manifestBytes += int64(len("\n---\n# Source: " + name + "\n" + doc))
+		}
+	}
+	return size, nil
+}
+
+func formatLimit(limit int64) string {
+	if limit == 0 {
+		return "none"
+	}
+	return fmt.Sprint(limit)
+}
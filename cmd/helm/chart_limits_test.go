@@ -0,0 +1,69 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	rls "k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// limitedClient is a fake client whose Tiller limits charts to 100 bytes.
+type limitedClient struct {
+	*helm.FakeClient
+}
+
+func (c limitedClient) GetCapabilities(opts ...helm.CapabilitiesOption) (*rls.GetCapabilitiesResponse, error) {
+	return &rls.GetCapabilitiesResponse{MaxManifestBytes: 100, MaxObjects: 10}, nil
+}
+
+func TestChartLimitsCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "chart within the limits",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    []string{"--set", "test.Name=limits"},
+			expected: "^CHART: alpine\nLIMIT (.*)\tRENDERED\tMAXIMUM\nmanifest bytes\t[0-9]+ (.*)\tnone(.*)\nobjects (.*)\t1 (.*)\tnone(.*)\nhooks (.*)\t0 (.*)\tnone(.*)\n$",
+		},
+		{
+			name:     "chart is required",
+			args:     []string{},
+			expected: "",
+			err:      true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newChartLimitsCmd(c, out)
+	})
+
+	tests = []releaseCase{
+		{
+			name:     "chart exceeding a limit",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    []string{"--set", "test.Name=limits"},
+			expected: "manifest bytes\t[0-9]+ (.*)\t100(.*)\nobjects (.*)\t1 (.*)\t10(.*)\n",
+			err:      true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newChartLimitsCmd(limitedClient{c}, out)
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/config"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// localTiller is the release service run inside the helm process in
+// client-only mode.
+var localTiller *grpc.Server
+
+// startLocalTiller runs the release service inside the helm process, and
+// points the helm client to it. Releases are applied with the credentials of
+// the kube config, and their records are stored as Secrets in namespace. The
+// operations are recorded in the audit log of the helm home.
+func startLocalTiller(namespace string) error {
+	kubeClient := kube.New(localConfigFlags(nil))
+	kubeClient.Log = debug
+	clientset, err := kubeClient.KubernetesClientSet()
+	if err != nil {
+		return fmt.Errorf("could not get Kubernetes client: %s", err)
+	}
+
+	storageDriver, err := driver.New("secret", driver.Options{
+		Namespace: namespace,
+		Clientset: clientset,
+		Log:       debug,
+	})
+	if err != nil {
+		return err
+	}
+
+	env := environment.New()
+	env.Releases = storage.Init(storageDriver)
+	env.Releases.Log = debug
+	env.KubeClient = kubeClient
+	env.Impersonate = func(user string, groups []string) environment.KubeClient {
+		c := kube.New(localConfigFlags(&user, groups...))
+		c.Log = debug
+		return c
+	}
+	env.Config.Set(&config.Config{
+		Audit: config.Audit{
+			Sinks: []config.AuditSink{{Type: config.AuditFile, Path: settings.Home.AuditLog()}},
+		},
+	})
+
+	lstn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	svc := tiller.NewReleaseServer(env, clientset, false)
+	svc.Log = debug
+	svc.AuditUser = kubeUser()
+	localTiller = tiller.NewServer()
+	services.RegisterReleaseServiceServer(localTiller, svc)
+	go localTiller.Serve(lstn)
+
+	// The local release service is reached in plain text over the loopback
+	// interface, whatever the TLS settings for a remote Tiller are.
+	settings.TillerHost = lstn.Addr().String()
+	settings.TLSEnable = false
+	settings.TLSVerify = false
+	debug("Client-only mode: storing releases in namespace %q", namespace)
+	return nil
+}
+
+// kubeUser returns the name of the kubeconfig user, recorded in the audit log
+// as the user of the operations.
+func kubeUser() string {
+	raw, err := kube.GetConfig(settings.KubeContext, settings.KubeConfig).RawConfig()
+	if err != nil {
+		return ""
+	}
+	name := settings.KubeContext
+	if name == "" {
+		name = raw.CurrentContext
+	}
+	if c, ok := raw.Contexts[name]; ok {
+		return c.AuthInfo
+	}
+	return ""
+}
+
+// localConfigFlags returns the kube config flags of the helm client, acting as
+// user, member of groups, if it is not nil.
+func localConfigFlags(user *string, groups ...string) *genericclioptions.ConfigFlags {
+	flags := genericclioptions.NewConfigFlags(true)
+	if settings.KubeContext != "" {
+		flags.Context = &settings.KubeContext
+	}
+	if settings.KubeConfig != "" {
+		flags.KubeConfig = &settings.KubeConfig
+	}
+	if user != nil {
+		flags.Impersonate = user
+		flags.ImpersonateGroup = &groups
+	}
+	return flags
+}
@@ -19,12 +19,26 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/repo"
 )
 
 const completionDesc = `
-Generate autocompletions script for Helm for the specified shell (bash or zsh).
+Generate autocompletions script for Helm for the specified shell (bash, zsh or fish).
 
 This command can generate shell autocompletions. e.g.
 
@@ -33,12 +47,18 @@ This command can generate shell autocompletions. e.g.
 Can be sourced as such
 
 	$ source <(helm completion bash)
+
+Release names, repository names, chart names from the repository caches and
+the values of flags such as --namespace are completed by asking helm itself,
+so the completion follows the flags given on the command line, e.g.
+--kube-context or --home.
 `
 
 var (
 	completionShells = map[string]func(out io.Writer, cmd *cobra.Command) error{
 		"bash": runCompletionBash,
 		"zsh":  runCompletionZsh,
+		"fish": runCompletionFish,
 	}
 )
 
@@ -50,7 +70,7 @@ func newCompletionCmd(out io.Writer) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "completion SHELL",
-		Short: "Generate autocompletions script for the specified shell (bash or zsh)",
+		Short: "Generate autocompletions script for the specified shell (bash, zsh or fish)",
 		Long:  completionDesc,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runCompletion(out, cmd, args)
@@ -230,3 +250,320 @@ __helm_bash_source <(__helm_convert_bash_to_zsh)
 	out.Write([]byte(zshTail))
 	return nil
 }
+
+func runCompletionFish(out io.Writer, cmd *cobra.Command) error {
+	_, err := io.WriteString(out, `# fish completion for helm
+
+function __helm_complete
+    set -l args (commandline -opc)
+    set -e args[1]
+    helm __complete $args (commandline -ct) 2>/dev/null
+end
+
+complete -c helm -f -a '(__helm_complete)'
+`)
+	return err
+}
+
+// The kinds of values the completion looks up.
+const (
+	completeReleases   = "releases"
+	completeCharts     = "charts"
+	completeRepos      = "repos"
+	completePlugins    = "plugins"
+	completeNamespaces = "namespaces"
+	completeContexts   = "contexts"
+	completeProfiles   = "profiles"
+)
+
+// argCompletions maps a command path to what its positional arguments are.
+// The last kind is used for all remaining arguments unless it is empty.
+// Subcommands without an entry use the one of their parent.
+var argCompletions = map[string][]string{
+	"helm delete":             {completeReleases},
+	"helm freeze":             {completeReleases},
+	"helm get":                {completeReleases},
+	"helm history":            {completeReleases},
+	"helm mapkubeapis":        {completeReleases},
+	"helm release annotate":   {completeReleases, ""},
+	"helm release label":      {completeReleases, ""},
+	"helm rollback":           {completeReleases, ""},
+	"helm status":             {completeReleases},
+	"helm test":               {completeReleases},
+	"helm timeline":           {completeReleases},
+	"helm unfreeze":           {completeReleases},
+	"helm upgrade":            {completeReleases, completeCharts, ""},
+	"helm window":             {completeReleases},
+	"helm bundle create":      {completeCharts},
+	"helm chart limits":       {completeCharts},
+	"helm dependency explain": {"", completeCharts},
+	"helm dependency build":   {completeCharts},
+	"helm dependency list":    {completeCharts},
+	"helm dependency update":  {completeCharts},
+	"helm fetch":              {completeCharts},
+	"helm inspect":            {completeCharts},
+	"helm install":            {completeCharts},
+	"helm lint":               {completeCharts},
+	"helm package":            {completeCharts},
+	"helm push":               {completeCharts, completeRepos, ""},
+	"helm resolve":            {completeCharts},
+	"helm template":           {completeCharts},
+	"helm verify":             {completeCharts},
+	"helm verify-compat":      {completeCharts},
+	"helm repo remove":        {completeRepos},
+	"helm repo update":        {completeRepos},
+	"helm profile remove":     {completeProfiles},
+	"helm profile set":        {completeProfiles},
+	"helm profile use":        {completeProfiles},
+	"helm plugin remove":      {completePlugins},
+	"helm plugin update":      {completePlugins},
+}
+
+// flagCompletions maps a flag name to the kind of its values.
+var flagCompletions = map[string]string{
+	"namespace":        completeNamespaces,
+	"tiller-namespace": completeNamespaces,
+	"kube-context":     completeContexts,
+	"profile":          completeProfiles,
+}
+
+// addFlagCompletions makes the bash completion of the flags in
+// flagCompletions call back into helm, for cmd and all its subcommands.
+func addFlagCompletions(cmd *cobra.Command) {
+	for name := range flagCompletions {
+		for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+			if flags.Lookup(name) != nil {
+				flags.SetAnnotation(name, cobra.BashCompCustom, []string{"__helm_complete"})
+			}
+		}
+	}
+	for _, c := range cmd.Commands() {
+		addFlagCompletions(c)
+	}
+}
+
+type completeCmd struct {
+	root   *cobra.Command
+	client helm.Interface
+	out    io.Writer
+}
+
+// newCompleteCmd is the hidden command the shell completion scripts call
+// with the words before the cursor and the word being completed.
+func newCompleteCmd(root *cobra.Command, out io.Writer) *cobra.Command {
+	c := &completeCmd{root: root, out: out}
+	cmd := &cobra.Command{
+		Use:                "__complete [ARGS] CURRENT",
+		Hidden:             true,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return nil
+			}
+			for _, s := range c.complete(args[:len(args)-1], args[len(args)-1]) {
+				fmt.Fprintln(c.out, s)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// complete returns the candidates for the word toComplete following args.
+func (c *completeCmd) complete(args []string, toComplete string) []string {
+	cmd, rest, err := c.root.Find(args)
+	if err != nil {
+		return nil
+	}
+	// The flags given so far select where candidates are looked up, e.g.
+	// --kube-context or --home. A value missing for the last flag is
+	// completed below, so the error is of no interest.
+	cmd.ParseFlags(rest)
+
+	if strings.HasPrefix(toComplete, "-") {
+		if i := strings.Index(toComplete, "="); i > 0 {
+			kind := flagCompletions[strings.TrimLeft(toComplete[:i], "-")]
+			var res []string
+			for _, s := range c.candidates(kind, toComplete[i+1:]) {
+				res = append(res, toComplete[:i+1]+s)
+			}
+			return res
+		}
+		return flagNames(cmd, toComplete)
+	}
+	if n := len(rest); n > 0 {
+		if f := valueFlag(cmd, rest[n-1]); f != nil {
+			return c.candidates(flagCompletions[f.Name], toComplete)
+		}
+	}
+
+	positional := cmd.Flags().Args()
+	var res []string
+	if len(positional) == 0 {
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() && strings.HasPrefix(sub.Name(), toComplete) {
+				res = append(res, sub.Name())
+			}
+		}
+	}
+	return append(res, c.candidates(argKind(cmd, len(positional)), toComplete)...)
+}
+
+// argKind returns what the positional argument at index i of cmd is.
+func argKind(cmd *cobra.Command, i int) string {
+	kinds, ok := argCompletions[cmd.CommandPath()]
+	if !ok && cmd.HasParent() {
+		kinds = argCompletions[cmd.Parent().CommandPath()]
+	}
+	if len(kinds) == 0 {
+		return ""
+	}
+	if i >= len(kinds) {
+		i = len(kinds) - 1
+	}
+	return kinds[i]
+}
+
+// valueFlag returns the flag of cmd that word names if the flag takes a
+// value as the next word.
+func valueFlag(cmd *cobra.Command, word string) *pflag.Flag {
+	if !strings.HasPrefix(word, "-") || strings.Contains(word, "=") {
+		return nil
+	}
+	var f *pflag.Flag
+	if strings.HasPrefix(word, "--") {
+		f = cmd.Flags().Lookup(word[2:])
+	} else if len(word) == 2 {
+		f = cmd.Flags().ShorthandLookup(word[1:])
+	}
+	if f == nil || f.NoOptDefVal != "" {
+		return nil
+	}
+	return f
+}
+
+// flagNames returns the visible long flags of cmd starting with prefix.
+func flagNames(cmd *cobra.Command, prefix string) []string {
+	var res []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if name := "--" + f.Name; !f.Hidden && strings.HasPrefix(name, prefix) {
+			res = append(res, name)
+		}
+	})
+	sort.Strings(res)
+	return res
+}
+
+// candidates looks up the values of kind starting with prefix. Lookup
+// errors are ignored as there is nowhere to report them during completion.
+func (c *completeCmd) candidates(kind, prefix string) []string {
+	var all []string
+	switch kind {
+	case completeReleases:
+		all = c.releases(prefix)
+	case completeCharts:
+		all = charts(prefix)
+	case completeRepos:
+		if f, err := repo.LoadRepositoriesFile(settings.Home.RepositoryFile()); err == nil {
+			for _, r := range f.Repositories {
+				all = append(all, r.Name)
+			}
+		}
+	case completePlugins:
+		if plugins, err := findPlugins(settings.PluginDirs()); err == nil {
+			for _, p := range plugins {
+				all = append(all, p.Metadata.Name)
+			}
+		}
+	case completeProfiles:
+		if f, err := helm_env.LoadProfilesFile(settings.ProfilesFile()); err == nil {
+			for _, p := range f.Profiles {
+				all = append(all, p.Name)
+			}
+		}
+	case completeNamespaces:
+		if _, client, err := getKubeClient(settings.KubeContext, settings.KubeConfig); err == nil {
+			if list, err := client.CoreV1().Namespaces().List(metav1.ListOptions{}); err == nil {
+				for _, ns := range list.Items {
+					all = append(all, ns.Name)
+				}
+			}
+		}
+	case completeContexts:
+		if config, err := kube.GetConfig("", settings.KubeConfig).RawConfig(); err == nil {
+			for name := range config.Contexts {
+				all = append(all, name)
+			}
+		}
+	}
+
+	var res []string
+	for _, s := range all {
+		if strings.HasPrefix(s, prefix) {
+			res = append(res, s)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (c *completeCmd) releases(prefix string) []string {
+	if c.client == nil {
+		if err := setupConnection(); err != nil {
+			return nil
+		}
+		defer teardown()
+		c.client = newClient()
+	}
+	res, err := c.client.ListReleases(
+		helm.ReleaseListFilter("^"+regexp.QuoteMeta(prefix)),
+		helm.ReleaseListStatuses([]release.Status_Code{
+			release.Status_UNKNOWN,
+			release.Status_DEPLOYED,
+			release.Status_DELETED,
+			release.Status_DELETING,
+			release.Status_FAILED,
+			release.Status_PENDING_INSTALL,
+			release.Status_PENDING_UPGRADE,
+			release.Status_PENDING_ROLLBACK,
+		}),
+	)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, r := range filterList(res.GetReleases()) {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// charts returns the charts of the cached repository indexes as
+// "repo/name", and the chart directories and archives matching prefix.
+func charts(prefix string) []string {
+	var res []string
+	if f, err := repo.LoadRepositoriesFile(settings.Home.RepositoryFile()); err == nil {
+		for _, r := range f.Repositories {
+			index, err := repo.LoadIndexFile(settings.Home.CacheIndex(r.Name))
+			if err != nil {
+				continue
+			}
+			for name := range index.Entries {
+				res = append(res, r.Name+"/"+name)
+			}
+		}
+	}
+	matches, _ := filepath.Glob(prefix + "*")
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		if fi.IsDir() {
+			res = append(res, m+string(filepath.Separator))
+		} else if chartutil.IsArchive(m) {
+			res = append(res, m)
+		}
+	}
+	return res
+}
@@ -0,0 +1,125 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/repo"
+)
+
+func TestComplete(t *testing.T) {
+	hh, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := resetEnv()
+	defer func() {
+		os.RemoveAll(hh.String())
+		cleanup()
+	}()
+
+	i := repo.NewIndexFile()
+	i.Add(&chart.Metadata{Name: "alpine", Version: "0.1.0"}, "alpine-0.1.0.tgz", "http://example.com/foo", "sha256:1234567890")
+	if err := i.WriteFile(hh.CacheIndex("charts"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newRootCmd(nil)
+	settings.Home = hh
+	c := &completeCmd{
+		root: root,
+		client: &helm.FakeClient{Rels: []*release.Release{
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas"}),
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		expected   []string
+	}{
+		{
+			name:       "commands",
+			toComplete: "sta",
+			expected:   []string{"starter", "status"},
+		},
+		{
+			name:       "releases",
+			args:       []string{"status"},
+			toComplete: "a",
+			expected:   []string{"aeneas", "atlas"},
+		},
+		{
+			name:       "releases with prefix",
+			args:       []string{"delete", "--purge", "aeneas"},
+			toComplete: "at",
+			expected:   []string{"atlas"},
+		},
+		{
+			name:       "subcommands and releases",
+			args:       []string{"get"},
+			toComplete: "at",
+			expected:   []string{"atlas"},
+		},
+		{
+			name:       "subcommands",
+			args:       []string{"get"},
+			toComplete: "va",
+			expected:   []string{"values"},
+		},
+		{
+			name:       "chart of upgrade",
+			args:       []string{"upgrade", "atlas"},
+			toComplete: "charts/",
+			expected:   []string{"charts/alpine"},
+		},
+		{
+			name:       "revision of rollback",
+			args:       []string{"rollback", "atlas"},
+			toComplete: "",
+		},
+		{
+			name:       "repositories",
+			args:       []string{"repo", "remove"},
+			toComplete: "",
+			expected:   []string{"charts", "local"},
+		},
+		{
+			name:       "flags",
+			args:       []string{"status"},
+			toComplete: "--tiller-n",
+			expected:   []string{"--tiller-namespace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.complete(tt.args, tt.toComplete)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm/helmpath"
@@ -59,6 +63,17 @@ will be overwritten, but other files will be left alone.
 The chart that is created by invoking this command contains a Deployment, Ingress
 and a Service. To use other Kubernetes resources with your chart, refer to
 [The Chart Template Developer's Guide](https://helm.sh/docs/chart_template_guide).
+
+With '--starter', the chart is scaffolded from a starter instead: the name of a
+starter in $HELM_HOME/starters, an absolute path, or a chart in a repository
+('stable/starter') or at a URL. A starter from a repository or a URL is fetched
+and cached in $HELM_HOME/starters, see 'helm starter'.
+
+A starter may declare variables in its starter.yaml, replaced like <CHARTNAME>
+in its templates and values. They are given with '--set NAME=VALUE', and asked
+for on a terminal otherwise:
+
+	$ helm create --starter stable/web-starter --set PORT=8080 mysite
 `
 
 type createCmd struct {
@@ -66,10 +81,20 @@ type createCmd struct {
 	name    string
 	out     io.Writer
 	starter string
+	vars    []string
+
+	// interactive is whether the variables of the starter that are not set
+	// can be asked for on in.
+	interactive bool
+	in          io.Reader
 }
 
 func newCreateCmd(out io.Writer) *cobra.Command {
-	cc := &createCmd{out: out}
+	cc := &createCmd{
+		out:         out,
+		in:          os.Stdin,
+		interactive: terminal.IsTerminal(int(os.Stdin.Fd())),
+	}
 
 	cmd := &cobra.Command{
 		Use:   "create NAME",
@@ -88,7 +113,8 @@ func newCreateCmd(out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&cc.starter, "starter", "p", "", "The name or absolute path to Helm starter scaffold")
+	cmd.Flags().StringVarP(&cc.starter, "starter", "p", "", "The name or absolute path to Helm starter scaffold, or a chart reference or URL to fetch it from")
+	cmd.Flags().StringArrayVar(&cc.vars, "set", []string{}, "Set a variable of the starter, as NAME=VALUE (can specify multiple)")
 	return cmd
 }
 
@@ -105,14 +131,93 @@ func (c *createCmd) run() error {
 
 	if c.starter != "" {
 		// Create from the starter
-		lstarter := filepath.Join(c.home.Starters(), c.starter)
-		// If path is absolute, we dont want to prefix it with helm starters folder
-		if filepath.IsAbs(c.starter) {
-			lstarter = c.starter
+		lstarter, err := c.starterPath()
+		if err != nil {
+			return err
+		}
+		vars, err := c.starterVariables(lstarter)
+		if err != nil {
+			return err
 		}
-		return chartutil.CreateFrom(cfile, filepath.Dir(c.name), lstarter)
+		return chartutil.CreateFromStarter(cfile, filepath.Dir(c.name), lstarter, vars)
 	}
 
+	if len(c.vars) > 0 {
+		return errors.New("--set requires --starter")
+	}
 	_, err := chartutil.Create(cfile, filepath.Dir(c.name))
 	return err
 }
+
+// starterPath returns the path of the starter, fetching it first if it is a
+// remote starter that is not cached yet.
+func (c *createCmd) starterPath() (string, error) {
+	// If path is absolute, we dont want to prefix it with helm starters folder
+	if filepath.IsAbs(c.starter) {
+		return c.starter, nil
+	}
+	lstarter := filepath.Join(c.home.Starters(), c.starter)
+	if _, err := os.Stat(lstarter); err == nil || !remoteStarter(c.starter) {
+		return lstarter, nil
+	}
+	if name := remoteStarterName(c.starter); name != "" {
+		cached := filepath.Join(c.home.Starters(), name)
+		if _, err := os.Stat(cached); err == nil {
+			debug("using starter %s cached in %s", c.starter, cached)
+			return cached, nil
+		}
+	}
+	fmt.Fprintf(c.out, "Fetching starter %s\n", c.starter)
+	name, _, err := installStarter(c.home, c.starter, "", false, c.out)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(c.home.Starters(), name), nil
+}
+
+// starterVariables returns the variables set with --set, after asking for the
+// other variables of the starter when possible.
+func (c *createCmd) starterVariables(lstarter string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, s := range c.vars {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid starter variable %q, expected NAME=VALUE", s)
+		}
+		vars[kv[0]] = kv[1]
+	}
+	if !c.interactive {
+		return vars, nil
+	}
+
+	schart, err := chartutil.Load(lstarter)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %s", lstarter, err)
+	}
+	sf, err := chartutil.LoadStarterfile(schart)
+	if err != nil {
+		return nil, err
+	}
+	answers := bufio.NewReader(c.in)
+	for _, v := range sf.Variables {
+		if _, ok := vars[v.Name]; ok {
+			continue
+		}
+		prompt := v.Name
+		if v.Description != "" {
+			prompt = fmt.Sprintf("%s (%s)", v.Description, v.Name)
+		}
+		if v.Default != "" {
+			prompt += fmt.Sprintf(" [%s]", v.Default)
+		}
+		fmt.Fprintf(c.out, "%s: ", prompt)
+		answer, err := readAnswer(answers)
+		if err != nil {
+			return nil, err
+		}
+		if answer != "" {
+			vars[v.Name] = answer
+		}
+	}
+	return vars, nil
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"k8s.io/helm/pkg/chartutil"
@@ -255,3 +257,57 @@ func TestCreateStarterAbsoluteCmd(t *testing.T) {
 	}
 
 }
+
+func TestCreateStarterVariables(t *testing.T) {
+	tdir, err := ioutil.TempDir("", "helm-create-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tdir)
+
+	starter, err := chartutil.Create(&chart.Metadata{Name: "starterchart"}, tdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	starterfile := "variables:\n- name: PORT\n  description: Port of the service\n- name: IMAGE\n  default: nginx\n- name: TAG\n  default: latest\n"
+	if err := ioutil.WriteFile(filepath.Join(starter, chartutil.StarterfileName), []byte(starterfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tpl := "image: <IMAGE>:<TAG>\nport: <PORT>\n"
+	if err := ioutil.WriteFile(filepath.Join(starter, "templates", "vars.yaml"), []byte(tpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	cc := &createCmd{
+		name:        filepath.Join(tdir, "testchart"),
+		starter:     starter,
+		vars:        []string{"TAG=1.17"},
+		out:         out,
+		in:          strings.NewReader("8080\n\n"),
+		interactive: true,
+	}
+	if err := cc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Port of the service (PORT): ") || !strings.Contains(out.String(), "IMAGE [nginx]: ") {
+		t.Errorf("expected the variables to be asked for, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "TAG") {
+		t.Errorf("expected the variable set with --set not to be asked for, got %q", out.String())
+	}
+	data, err := ioutil.ReadFile(filepath.Join(tdir, "testchart", "templates", "vars.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "image: nginx:1.17\nport: 8080\n"; string(data) != expect {
+		t.Errorf("expected %q, got %q", expect, data)
+	}
+
+	// Without a terminal, a variable without a default must be set.
+	cc.name = filepath.Join(tdir, "otherchart")
+	cc.interactive = false
+	if err := cc.run(); err == nil || !strings.Contains(err.Error(), "PORT is not set") {
+		t.Errorf("expected an error for the missing variable, got %v", err)
+	}
+}
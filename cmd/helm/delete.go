@@ -20,10 +20,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/kube"
 )
 
 const deleteDesc = `
@@ -32,22 +35,54 @@ It removes all of the resources associated with the last release of the chart.
 
 Use the '--dry-run' flag to see which releases will be deleted without actually
 deleting them.
+
+The history of the release is kept, so that it can be rolled back, unless the
+'--purge' flag is given. The '--cascade' flag controls what happens to the
+dependents of the deleted resources, such as the pods of a deployment: they are
+deleted in the background (the default), deleted before the resources
+themselves ('foreground'), or left in the cluster ('orphan').
+
+Resources annotated with "helm.sh/resource-policy": keep are not deleted.
+
+A release providing capabilities, listed in its "helm.sh/provides" annotation,
+is not deleted while other releases list one of them in their
+"helm.sh/requires" annotation, unless another release provides it too. Use
+'--force' to delete it anyway.
+
+Instead of release names, '--selector' deletes every release whose labels match
+a label query, optionally only in the namespace given with '--namespace'. The
+matching releases are listed, and deleted once the deletion is confirmed, or
+right away with '--yes'. With '--dry-run', they are only listed:
+
+	$ helm delete --selector team=legacy --namespace old --dry-run
 `
 
 type deleteCmd struct {
-	name         string
-	dryRun       bool
-	disableHooks bool
-	purge        bool
-	timeout      int64
-	description  string
-
+	name           string
+	dryRun         bool
+	disableHooks   bool
+	purge          bool
+	keepHistory    bool
+	cascade        string
+	force          bool
+	timeout        int64
+	hookTimeout    int64
+	description    string
+	overrideWindow string
+	quiet          bool
+	selector       string
+	namespace      string
+	yes            bool
+	concurrency    int
+
+	in     io.Reader
 	out    io.Writer
 	client helm.Interface
 }
 
 func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	del := &deleteCmd{
+		in:     os.Stdin,
 		out:    out,
 		client: c,
 	}
@@ -60,9 +95,22 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 		Long:       deleteDesc,
 		PreRunE:    func(_ *cobra.Command, _ []string) error { return setupConnection() },
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if del.selector != "" {
+				if len(args) > 0 {
+					return errors.New("cannot use release names and --selector together")
+				}
+				if err := del.validate(); err != nil {
+					return err
+				}
+				del.client = ensureHelmClient(del.client)
+				return del.runSelector()
+			}
 			if len(args) == 0 {
 				return errors.New("command 'delete' requires a release name")
 			}
+			if del.namespace != "" {
+				return errors.New("--namespace can only be used with --selector")
+			}
 			del.client = ensureHelmClient(del.client)
 
 			for i := 0; i < len(args); i++ {
@@ -71,7 +119,7 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 					return err
 				}
 
-				fmt.Fprintf(out, "release \"%s\" deleted\n", del.name)
+				printMessage(out, del.quiet, msgDeleted, del.name)
 			}
 			return nil
 		},
@@ -82,8 +130,18 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&del.dryRun, "dry-run", false, "Simulate a delete")
 	f.BoolVar(&del.disableHooks, "no-hooks", false, "Prevent hooks from running during deletion")
 	f.BoolVar(&del.purge, "purge", false, "Remove the release from the store and make its name free for later use")
-	f.Int64Var(&del.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	f.BoolVar(&del.keepHistory, "keep-history", false, "Keep the history of the release so that it can be rolled back. This is the default unless --purge is given")
+	f.StringVar(&del.cascade, "cascade", kube.CascadeBackground, "How the dependents of the deleted resources are handled: background, foreground or orphan")
+	f.BoolVar(&del.force, "force", false, "Delete the release even when other releases require a capability it provides")
+	helm_env.TimeoutVar(f, &del.timeout, "timeout", 300, "Time to wait for any individual Kubernetes operation (like Jobs for hooks), as a duration such as 5m30s or in seconds")
+	helm_env.TimeoutVar(f, &del.hookTimeout, "hook-timeout", 0, "Time hooks may run, as a duration such as 10m or in seconds. Defaults to --timeout")
 	f.StringVar(&del.description, "description", "", "Specify a description for the release")
+	f.StringVar(&del.overrideWindow, "override-window", "", "Delete outside the maintenance windows of the release, giving the reason recorded in the Tiller audit log")
+	f.BoolVar(&del.quiet, "quiet", false, "Print nothing on success")
+	f.StringVarP(&del.selector, "selector", "l", "", "Delete the releases whose labels match this selector (label query), such as 'team=legacy'")
+	f.StringVar(&del.namespace, "namespace", "", "Only delete the releases of this namespace matching --selector")
+	f.BoolVarP(&del.yes, "yes", "y", false, "Delete the releases matching --selector without asking for a confirmation")
+	f.IntVar(&del.concurrency, "concurrency", 4, "Maximum number of releases matching --selector deleted at the same time")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -91,16 +149,35 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	return cmd
 }
 
-func (d *deleteCmd) run() error {
-	opts := []helm.DeleteOption{
+// validate checks the flags shared by the deletions by name and by selector.
+func (d *deleteCmd) validate() error {
+	if d.purge && d.keepHistory {
+		return errors.New("cannot use --purge and --keep-history together")
+	}
+	_, err := kube.PropagationPolicy(d.cascade)
+	return err
+}
+
+func (d *deleteCmd) deleteOptions() []helm.DeleteOption {
+	return []helm.DeleteOption{
 		helm.DeleteDryRun(d.dryRun),
 		helm.DeleteDisableHooks(d.disableHooks),
 		helm.DeletePurge(d.purge),
 		helm.DeleteTimeout(d.timeout),
+		helm.DeleteHookTimeout(d.hookTimeout),
 		helm.DeleteDescription(d.description),
+		helm.DeleteOverrideWindow(d.overrideWindow),
+		helm.DeleteCascade(d.cascade),
+		helm.DeleteForce(d.force),
+	}
+}
+
+func (d *deleteCmd) run() error {
+	if err := d.validate(); err != nil {
+		return err
 	}
-	res, err := d.client.DeleteRelease(d.name, opts...)
-	if res != nil && res.Info != "" {
+	res, err := d.client.DeleteRelease(d.name, d.deleteOptions()...)
+	if res != nil && res.Info != "" && !d.quiet {
 		fmt.Fprintln(d.out, res.Info)
 	}
 
@@ -0,0 +1,186 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gosuri/uitable"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// deleteSelectorPageSize is the number of releases listed per request when
+// selecting the releases to delete.
+const deleteSelectorPageSize = 256
+
+// deleteResult is the outcome of deleting one of the selected releases.
+type deleteResult struct {
+	name string
+	info string
+	err  error
+}
+
+// runSelector deletes the releases matching the selector, after printing them
+// and asking for a confirmation.
+func (d *deleteCmd) runSelector() error {
+	rels, err := d.selectReleases()
+	if err != nil {
+		return prettyError(err)
+	}
+	if len(rels) == 0 {
+		fmt.Fprintf(d.out, "No release matches the selector %q\n", d.selector)
+		return nil
+	}
+
+	fmt.Fprintf(d.out, "The following %d release(s) match the selector %q:\n", len(rels), d.selector)
+	table := uitable.New()
+	table.AddRow("NAME", "NAMESPACE", "REVISION", "STATUS", "CHART")
+	for _, r := range rels {
+		md := r.GetChart().GetMetadata()
+		table.AddRow(r.GetName(), r.GetNamespace(), r.GetVersion(), r.GetInfo().GetStatus().GetCode(), fmt.Sprintf("%s-%s", md.GetName(), md.GetVersion()))
+	}
+	fmt.Fprintln(d.out, table)
+
+	if d.dryRun {
+		fmt.Fprintln(d.out, "Dry run: no release was deleted")
+		return nil
+	}
+	if !d.yes {
+		ok, err := confirm(d.in, d.out, fmt.Sprintf("Delete these %d release(s)?", len(rels)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("deletion cancelled, no release was deleted")
+		}
+	}
+
+	results := d.deleteAll(rels)
+	return d.printDeleteSummary(results)
+}
+
+// selectReleases lists the latest revisions of the releases matching the
+// selector in the namespace. Deleted releases are only selected to be purged.
+func (d *deleteCmd) selectReleases() ([]*release.Release, error) {
+	statuses := []release.Status_Code{
+		release.Status_UNKNOWN,
+		release.Status_DEPLOYED,
+		release.Status_DELETING,
+		release.Status_FAILED,
+		release.Status_PENDING_INSTALL,
+		release.Status_PENDING_UPGRADE,
+		release.Status_PENDING_ROLLBACK,
+	}
+	if d.purge {
+		statuses = append(statuses, release.Status_DELETED)
+	}
+
+	var rels []*release.Release
+	offset := ""
+	for {
+		res, err := d.client.ListReleases(
+			helm.ReleaseListLimit(deleteSelectorPageSize),
+			helm.ReleaseListOffset(offset),
+			helm.ReleaseListStatuses(statuses),
+			helm.ReleaseListNamespace(d.namespace),
+			helm.ReleaseListSelector(d.selector),
+		)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, res.GetReleases()...)
+		if res.GetNext() == "" {
+			return filterList(rels), nil
+		}
+		offset = res.GetNext()
+	}
+}
+
+// deleteAll deletes the releases, running at most d.concurrency deletions at
+// a time. The results are in the order of rels.
+func (d *deleteCmd) deleteAll(rels []*release.Release) []deleteResult {
+	concurrency := d.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]deleteResult, len(rels))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, r := range rels {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res, err := d.client.DeleteRelease(name, d.deleteOptions()...)
+			results[i] = deleteResult{name: name, info: res.GetInfo(), err: err}
+		}(i, r.GetName())
+	}
+	wg.Wait()
+	return results
+}
+
+// printDeleteSummary prints the outcome of every deletion, and returns an
+// error if any of them failed.
+func (d *deleteCmd) printDeleteSummary(results []deleteResult) error {
+	failed := 0
+	table := uitable.New()
+	table.AddRow("RELEASE", "RESULT")
+	for _, r := range results {
+		result := "deleted"
+		if r.err != nil {
+			failed++
+			result = "failed: " + prettyError(r.err).Error()
+		}
+		table.AddRow(r.name, result)
+	}
+	fmt.Fprintln(d.out, table)
+	for _, r := range results {
+		if r.info != "" {
+			fmt.Fprintf(d.out, "%s: %s\n", r.name, r.info)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d releases could not be deleted", failed, len(results))
+	}
+	return nil
+}
+
+// confirm asks a yes or no question, and returns true if the answer read from
+// in is yes.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
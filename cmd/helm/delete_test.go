@@ -17,7 +17,9 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -69,6 +71,64 @@ func TestDelete(t *testing.T) {
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"})},
 		},
+		{
+			name:     "delete orphaning dependents",
+			args:     []string{"aeneas"},
+			flags:    []string{"--cascade", "orphan", "--keep-history"},
+			expected: "",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"})},
+		},
+		{
+			name:     "force delete",
+			args:     []string{"aeneas"},
+			flags:    []string{"--force"},
+			expected: "",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"})},
+		},
+		{
+			name:  "delete with invalid cascade",
+			args:  []string{"aeneas"},
+			flags: []string{"--cascade", "sideways"},
+			err:   true,
+		},
+		{
+			name:  "purge and keep history",
+			args:  []string{"aeneas"},
+			flags: []string{"--purge", "--keep-history"},
+			err:   true,
+		},
+		{
+			name:     "list the releases matching a selector",
+			flags:    []string{"--selector", "team=legacy", "--namespace", "old", "--dry-run"},
+			expected: `(?s)The following 2 release\(s\) match the selector "team=legacy".*aeneas\s+default.*dido\s+default.*Dry run: no release was deleted`,
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "dido"}),
+			},
+		},
+		{
+			name:     "delete the releases matching a selector",
+			flags:    []string{"--selector", "team=legacy", "--yes", "--concurrency", "2"},
+			expected: `(?s)RELEASE\s+RESULT.*aeneas\s+deleted.*dido\s+deleted`,
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "dido"}),
+			},
+		},
+		{
+			name:  "delete by name and selector",
+			args:  []string{"aeneas"},
+			flags: []string{"--selector", "team=legacy"},
+			err:   true,
+		},
+		{
+			name:  "namespace without selector",
+			args:  []string{"aeneas"},
+			flags: []string{"--namespace", "old"},
+			err:   true,
+		},
 		{
 			name: "delete without release",
 			args: []string{},
@@ -79,3 +139,31 @@ func TestDelete(t *testing.T) {
 		return newDeleteCmd(c, out)
 	})
 }
+
+func TestDeleteSelectorConfirmation(t *testing.T) {
+	for _, answer := range []string{"n\n", "yes\n"} {
+		c := &helm.FakeClient{Rels: []*release.Release{
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+		}}
+		var out bytes.Buffer
+		del := &deleteCmd{
+			selector:    "team=legacy",
+			concurrency: 1,
+			in:          strings.NewReader(answer),
+			out:         &out,
+			client:      c,
+		}
+
+		err := del.runSelector()
+		if !strings.Contains(out.String(), "Delete these 1 release(s)? [y/N]") {
+			t.Errorf("Expected a confirmation to be asked, got %q", out.String())
+		}
+		confirmed := answer == "yes\n"
+		if confirmed != (err == nil) {
+			t.Errorf("Answering %q: unexpected error %v", answer, err)
+		}
+		if confirmed != (len(c.Rels) == 0) {
+			t.Errorf("Answering %q: expected the release to be deleted only if confirmed, %d release(s) left", answer, len(c.Rels))
+		}
+	}
+}
@@ -33,7 +33,8 @@ Manage the dependencies of a chart.
 
 Helm charts store their dependencies in 'charts/'. For chart developers, it is
 often easier to manage a single dependency file ('requirements.yaml')
-which declares all dependencies.
+which declares all dependencies. They can also be declared in the
+'dependencies' block of 'Chart.yaml', which takes the same entries.
 
 The dependency commands operate on that file, making it easy to synchronize
 between the desired dependencies and the actual dependencies stored in the
@@ -85,11 +86,16 @@ the contents of a chart.
 
 This will produce an error if the chart cannot be loaded. It will emit a warning
 if it cannot find a requirements.yaml.
+
+With '--values', it also lists the values copied between the chart and its
+dependencies by their 'import-values' and 'export-values', in the order they
+are applied. Wildcard imports are expanded against the exports of the
+dependencies found in 'charts/'.
 `
 
 func newDependencyCmd(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "dependency update|build|list",
+		Use:     "dependency update|build|list|explain",
 		Aliases: []string{"dep", "dependencies"},
 		Short:   "Manage a chart's dependencies",
 		Long:    dependencyDesc,
@@ -98,6 +104,7 @@ func newDependencyCmd(out io.Writer) *cobra.Command {
 	cmd.AddCommand(newDependencyListCmd(out))
 	cmd.AddCommand(newDependencyUpdateCmd(out))
 	cmd.AddCommand(newDependencyBuildCmd(out))
+	cmd.AddCommand(newDependencyExplainCmd(out))
 
 	return cmd
 }
@@ -105,6 +112,7 @@ func newDependencyCmd(out io.Writer) *cobra.Command {
 type dependencyListCmd struct {
 	out       io.Writer
 	chartpath string
+	values    bool
 }
 
 func newDependencyListCmd(out io.Writer) *cobra.Command {
@@ -129,6 +137,9 @@ func newDependencyListCmd(out io.Writer) *cobra.Command {
 			return dlc.run()
 		},
 	}
+
+	f := cmd.Flags()
+	f.BoolVar(&dlc.values, "values", false, "Also list the values imported from and exported to the dependencies")
 	return cmd
 }
 
@@ -150,15 +161,28 @@ func (l *dependencyListCmd) run() error {
 	l.printRequirements(r, l.out)
 	fmt.Fprintln(l.out)
 	l.printMissing(r)
+
+	if l.values {
+		mappings, err := chartutil.RequirementsValuesMappings(c)
+		if err != nil {
+			return err
+		}
+		l.printValuesMappings(mappings, l.out)
+	}
 	return nil
 }
 
 func (l *dependencyListCmd) dependencyStatus(dep *chartutil.Dependency) string {
-	filename := fmt.Sprintf("%s-%s.tgz", dep.Name, "*")
-	archives, err := filepath.Glob(filepath.Join(l.chartpath, "charts", filename))
-	if err != nil {
-		return "bad pattern"
-	} else if len(archives) > 1 {
+	var archives []string
+	for _, ext := range []string{"tgz", "zip"} {
+		filename := fmt.Sprintf("%s-%s.%s", dep.Name, "*", ext)
+		matches, err := filepath.Glob(filepath.Join(l.chartpath, "charts", filename))
+		if err != nil {
+			return "bad pattern"
+		}
+		archives = append(archives, matches...)
+	}
+	if len(archives) > 1 {
 		return "too many matches"
 	} else if len(archives) == 1 {
 		archive := archives[0]
@@ -238,6 +262,25 @@ func (l *dependencyListCmd) printRequirements(reqs *chartutil.Requirements, out
 	fmt.Fprintln(out, table)
 }
 
+// printValuesMappings prints the values copied between the chart and its dependencies.
+func (l *dependencyListCmd) printValuesMappings(mappings []chartutil.ValuesMapping, out io.Writer) {
+	if len(mappings) == 0 {
+		fmt.Fprintln(out, "No values are imported from or exported to the dependencies.")
+		return
+	}
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.AddRow("DEPENDENCY", "DIRECTION", "FROM", "TO")
+	for _, m := range mappings {
+		direction := "import"
+		if m.Export {
+			direction = "export"
+		}
+		table.AddRow(m.Dependency, direction, m.From, m.To)
+	}
+	fmt.Fprintln(out, table)
+}
+
 // printMissing prints warnings about charts that are present on disk, but are not in the requirements.
 func (l *dependencyListCmd) printMissing(reqs *chartutil.Requirements) {
 	folder := filepath.Join(l.chartpath, "charts/*")
@@ -252,8 +295,8 @@ func (l *dependencyListCmd) printMissing(reqs *chartutil.Requirements) {
 		if err != nil {
 			fmt.Fprintf(l.out, "Warning: %s\n", err)
 		}
-		// Skip anything that is not a directory and not a tgz file.
-		if !fi.IsDir() && filepath.Ext(f) != ".tgz" {
+		// Skip anything that is not a directory and not a chart archive.
+		if !fi.IsDir() && !chartutil.IsArchive(f) {
 			continue
 		}
 		c, err := chartutil.Load(f)
@@ -0,0 +1,184 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const dependencyExplainDesc = `
+Explain whether a dependency of a chart is enabled under the given values, and
+why.
+
+Dependencies are enabled by default. The 'tags' of a dependency in
+'requirements.yaml' enable it if one of them is true in the 'tags' values,
+and disable it if all the ones set are false. Its 'condition' then decides, if
+one of its value paths is set, overriding the tags. This command shows the tags
+and condition paths that were looked up, their values, and the value to set to
+enable a disabled dependency or to disable an enabled one.
+
+	$ helm dependency explain redis ./mychart --set tags.cache=false
+
+The dependencies of dependencies are named by their path, e.g. 'backend.redis',
+and can also be given by their own name. The dependencies of disabled
+dependencies are not explained, as they are not deployed either.
+
+The conditions depending on the capabilities of the cluster are left
+undecided, as no cluster is contacted.
+`
+
+type dependencyExplainCmd struct {
+	out          io.Writer
+	dependency   string
+	chartpath    string
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+	outputFormat string
+}
+
+func newDependencyExplainCmd(out io.Writer) *cobra.Command {
+	dec := &dependencyExplainCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "explain [flags] DEPENDENCY [CHART]",
+		Short: "Explain why a dependency is enabled or disabled",
+		Long:  dependencyExplainDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 || len(args) > 2 {
+				return fmt.Errorf("This command needs a dependency and optionally a chart")
+			}
+			dec.dependency = args[0]
+			cp := "."
+			if len(args) > 1 {
+				cp = args[1]
+			}
+
+			var err error
+			dec.chartpath, err = filepath.Abs(cp)
+			if err != nil {
+				return err
+			}
+			return dec.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.VarP(&dec.valueFiles, "values", "f", "Specify values in a YAML file (can specify multiple)")
+	f.StringArrayVar(&dec.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&dec.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&dec.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	f.StringVarP(&dec.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+	return cmd
+}
+
+func (d *dependencyExplainCmd) run() error {
+	c, err := chartutil.Load(d.chartpath)
+	if err != nil {
+		return err
+	}
+	rawVals, err := vals(d.valueFiles, d.values, d.stringValues, d.fileValues, "", "", "")
+	if err != nil {
+		return err
+	}
+	all, err := chartutil.ExplainRequirements(c, &chart.Config{Raw: string(rawVals)}, nil)
+	if err != nil {
+		return err
+	}
+
+	var res []*chartutil.DependencyExplanation
+	for _, e := range all {
+		if e.Name == d.dependency || strings.HasSuffix(e.Name, "."+d.dependency) || e.Chart == d.dependency {
+			res = append(res, e)
+		}
+	}
+	if len(res) == 0 {
+		return fmt.Errorf("no dependency %q in %s or in its enabled dependencies", d.dependency, c.Metadata.Name)
+	}
+
+	var out []byte
+	switch d.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(res)
+	case "json":
+		out, err = json.Marshal(res)
+	case "table":
+		out = formatDependencyExplanations(res)
+	default:
+		return fmt.Errorf("unknown output format %q", d.outputFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(d.out, strings.TrimSuffix(string(out), "\n"))
+	return nil
+}
+
+func formatDependencyExplanations(res []*chartutil.DependencyExplanation) []byte {
+	var b strings.Builder
+	for i, e := range res {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "DEPENDENCY:\t%s\n", e.Name)
+		fmt.Fprintf(&b, "CHART:\t%s\n", e.Chart)
+		fmt.Fprintf(&b, "ENABLED:\t%s\n", yesNo(e.Enabled))
+		fmt.Fprintf(&b, "REASON:\t%s\n", e.Reason)
+		if e.Condition != "" {
+			fmt.Fprintf(&b, "CONDITION:\t%s\n", e.Condition)
+		}
+		if len(e.Tags) > 0 {
+			fmt.Fprintf(&b, "TAGS:\t%s\n", strings.Join(e.Tags, ", "))
+		}
+		if len(e.Values) > 0 {
+			table := uitable.New()
+			table.MaxColWidth = 80
+			table.AddRow("VALUE", "SETTING")
+			for _, v := range e.Values {
+				setting := "<not set>"
+				if v.Set {
+					setting = fmt.Sprint(v.Value)
+				}
+				table.AddRow(v.Path, setting)
+			}
+			fmt.Fprintf(&b, "%s\n", table)
+		}
+		if e.Toggle != "" {
+			action := "enable"
+			if e.Enabled {
+				action = "disable"
+			}
+			fmt.Fprintf(&b, "To %s it: --set %s\n", action, e.Toggle)
+		}
+		if e.Missing {
+			b.WriteString("WARNING: the chart of the dependency is not in 'charts/', run 'helm dependency build' to explain its own dependencies\n")
+		}
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,74 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestDependencyExplainCmd(t *testing.T) {
+	subpop := "./../../pkg/chartutil/testdata/subpop"
+
+	tests := []releaseCase{
+		{
+			name: "No dependency",
+			args: []string{},
+			err:  true,
+		},
+		{
+			name: "Unknown dependency",
+			args: []string{"redis", subpop},
+			err:  true,
+		},
+		{
+			name: "Disabled by tags",
+			args: []string{"subchart2", subpop},
+			expected: "DEPENDENCY:\tsubchart2\n" +
+				"CHART:\tsubchart2\n" +
+				"ENABLED:\tno\n" +
+				"REASON:\tits tags are false\n" +
+				"CONDITION:\tsubchart2.enabled\n" +
+				"TAGS:\tback-end, subchart2\n" +
+				`VALUE\s*\tSETTING\s*\n` +
+				`tags\.back-end\s*\tfalse\s*\n` +
+				`tags\.subchart2\s*\t<not set>\s*\n` +
+				`subchart2\.enabled\s*\t<not set>\s*\n` +
+				`To enable it: --set subchart2\.enabled=true\n`,
+		},
+		{
+			name:     "Enabled by condition",
+			args:     []string{"subchart2", subpop},
+			flags:    []string{"--set", "subchart2.enabled=true"},
+			expected: `ENABLED:\tyes\nREASON:\tits condition subchart2\.enabled is true\n`,
+		},
+		{
+			name:     "Nested dependency by name",
+			args:     []string{"subcharta", subpop},
+			flags:    []string{"-o", "json"},
+			expected: `\[\{"name":"subchart1\.subcharta","chart":"subcharta","enabled":true,"reason":"its tag \\"front-end\\" is true"`,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newDependencyExplainCmd(out)
+	})
+}
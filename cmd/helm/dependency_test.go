@@ -50,6 +50,16 @@ func TestDependencyListCmd(t *testing.T) {
 			args:     []string{"testdata/testcharts/reqtest-0.1.0.tgz"},
 			expected: "NAME        \tVERSION\tREPOSITORY                \tSTATUS \nreqsubchart \t0.1.0  \thttps://example.com/charts\tmissing\nreqsubchart2\t0.2.0  \thttps://example.com/charts\tmissing\n",
 		},
+		{
+			name:  "Imported and exported values",
+			args:  []string{"testdata/testcharts/reqvalues"},
+			flags: []string{"--values"},
+			expected: `DEPENDENCY\s*\tDIRECTION\s*\tFROM\s*\tTO\s*\n` +
+				`common\s*\timport\s*\tcommon\.exports\.labelsApp\s*\t\.\s*\n` +
+				`common\s*\timport\s*\tcommon\.exports\.labelsTier\s*\t\.\s*\n` +
+				`common\s*\timport\s*\tcommon\.image\.tag\s*\timageTag\s*\n` +
+				`common\s*\texport\s*\tdb\.host\s*\tcommon\.database\.host\s*\n`,
+		},
 	}
 
 	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
@@ -0,0 +1,166 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/kube"
+)
+
+const envDesc = `
+Print the settings Helm resolves from its flags, environment variables and
+profile, and where each one comes from.
+
+	$ helm env
+	$ helm env --profile prod
+
+Settings coming from a profile show the source 'profile'. See 'helm profile'.
+`
+
+type envCmd struct {
+	out          io.Writer
+	outputFormat string
+}
+
+type envSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+type envOutput struct {
+	Profile      string       `json:"profile,omitempty"`
+	ProfilesFile string       `json:"profilesFile"`
+	Settings     []envSetting `json:"settings"`
+}
+
+func newEnvCmd(out io.Writer) *cobra.Command {
+	e := &envCmd{out: out}
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Print the resolved Helm environment",
+		Long:  envDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.run(cmd.Flags())
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&e.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+	settings.AddFlagsTLS(f)
+	settings.InitTLS(f)
+	return cmd
+}
+
+func (e *envCmd) run(fs *pflag.FlagSet) error {
+	res := envOutput{ProfilesFile: settings.ProfilesFile()}
+	if activeProfile != nil {
+		res.Profile = activeProfile.Name
+	}
+	for _, name := range helm_env.ProfileFlags {
+		res.Settings = append(res.Settings, envSetting{
+			Name:   name,
+			Value:  settingValue(name),
+			Source: helm_env.Source(name, fs, activeProfile),
+		})
+	}
+
+	var out []byte
+	var err error
+	switch e.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(res)
+	case "json":
+		out, err = json.Marshal(res)
+	case "table":
+		out = formatEnv(res)
+	default:
+		return fmt.Errorf("unknown output format %q", e.outputFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.out, strings.TrimSuffix(string(out), "\n"))
+	return nil
+}
+
+// settingValue returns the resolved value of the setting of a flag. The
+// namespace and kube context fall back to the ones of the kubeconfig.
+func settingValue(name string) string {
+	switch name {
+	case "home":
+		return settings.Home.String()
+	case "kubeconfig":
+		return settings.KubeConfig
+	case "kube-context":
+		if settings.KubeContext != "" {
+			return settings.KubeContext
+		}
+		if config, err := kube.GetConfig("", settings.KubeConfig).RawConfig(); err == nil {
+			return config.CurrentContext
+		}
+		return ""
+	case "namespace":
+		return defaultNamespace()
+	case "tiller-namespace":
+		return settings.TillerNamespace
+	case "host":
+		return settings.TillerHost
+	case "tls":
+		return strconv.FormatBool(settings.TLSEnable)
+	case "tls-verify":
+		return strconv.FormatBool(settings.TLSVerify)
+	case "tls-hostname":
+		return settings.TLSServerName
+	case "tls-ca-cert":
+		return settings.TLSCaCertFile
+	case "tls-cert":
+		return settings.TLSCertFile
+	case "tls-key":
+		return settings.TLSKeyFile
+	}
+	return ""
+}
+
+func formatEnv(res envOutput) []byte {
+	var b strings.Builder
+	profile := res.Profile
+	if profile == "" {
+		profile = "<none>"
+	}
+	fmt.Fprintf(&b, "PROFILE:\t%s\n", profile)
+	fmt.Fprintf(&b, "PROFILES FILE:\t%s\n", res.ProfilesFile)
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.AddRow("SETTING", "VALUE", "SOURCE")
+	for _, s := range res.Settings {
+		table.AddRow(s.Name, s.Value, s.Source)
+	}
+	fmt.Fprintf(&b, "%s\n", table)
+	return []byte(b.String())
+}
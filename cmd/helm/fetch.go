@@ -17,17 +17,19 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
+	"k8s.io/helm/pkg/action"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/downloader"
-	"k8s.io/helm/pkg/getter"
-	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/provenance"
 )
 
 const fetchDesc = `
@@ -37,12 +39,27 @@ This is useful for fetching packages to inspect, modify, or repackage. It can
 also be used to perform cryptographic verification of a chart without installing
 the chart.
 
+The chart reference may be pinned to a version ('stable/mariadb@5.1.0') or to
+the SHA256 digest of the chart archive ('stable/mariadb@sha256:<digest>'), also
+given with --digest. The chart is not saved if its archive does not match the
+digest.
+
 There are options for unpacking the chart after download. This will create a
 directory for the chart and uncompress into that directory.
 
 If the --verify flag is specified, the requested chart MUST have a provenance
 file, and MUST pass the verification process. Failure in any part of this will
-result in an error, and the chart will not be saved locally.
+result in an error, and the chart will not be saved locally. The digest of the
+verified archive is printed, so that later fetches can be pinned to it.
+
+With --verify-report, a report of the verification is written to a file, or to
+the standard output if the file is '-'. The report holds a JSON document per
+chart, with the signer and the fingerprint of its key, the digest of the chart,
+the time of the verification and its result. A report is written for the
+charts that failed the verification too, so that pipelines can archive the
+evidence of their supply-chain checks:
+
+    $ helm fetch --verify --verify-report report.json stable/mariadb
 `
 
 type fetchCmd struct {
@@ -51,13 +68,15 @@ type fetchCmd struct {
 	chartRef string
 	destdir  string
 	version  string
+	digest   string
 	repoURL  string
 	username string
 	password string
 
-	verify      bool
-	verifyLater bool
-	keyring     string
+	verify       bool
+	verifyLater  bool
+	verifyReport string
+	keyring      string
 
 	certFile string
 	keyFile  string
@@ -65,7 +84,8 @@ type fetchCmd struct {
 
 	devel bool
 
-	out io.Writer
+	reports []*provenance.Report
+	out     io.Writer
 }
 
 func newFetchCmd(out io.Writer) *cobra.Command {
@@ -79,19 +99,24 @@ func newFetchCmd(out io.Writer) *cobra.Command {
 			if len(args) == 0 {
 				return fmt.Errorf("need at least one argument, url or repo/name of the chart")
 			}
-
-			if fch.version == "" && fch.devel {
-				debug("setting version to >0.0.0-0")
-				fch.version = ">0.0.0-0"
+			if fch.digest != "" && len(args) > 1 {
+				return fmt.Errorf("--digest pins a single chart, got %d", len(args))
+			}
+			if fch.verifyReport != "" && !fch.verify {
+				return fmt.Errorf("--verify-report requires --verify")
 			}
 
-			for i := 0; i < len(args); i++ {
+			var err error
+			for i := 0; i < len(args) && err == nil; i++ {
 				fch.chartRef = args[i]
-				if err := fch.run(); err != nil {
-					return err
+				err = fch.run()
+			}
+			if fch.verifyReport != "" {
+				if rerr := fch.writeReports(); rerr != nil && err == nil {
+					err = rerr
 				}
 			}
-			return nil
+			return err
 		},
 	}
 
@@ -100,7 +125,9 @@ func newFetchCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&fch.untardir, "untardir", ".", "If untar is specified, this flag specifies the name of the directory into which the chart is expanded")
 	f.BoolVar(&fch.verify, "verify", false, "Verify the package against its signature")
 	f.BoolVar(&fch.verifyLater, "prov", false, "Fetch the provenance file, but don't perform verification")
+	f.StringVar(&fch.verifyReport, "verify-report", "", "Write a JSON report of the verification to this file, or to stdout if '-'. Requires --verify")
 	f.StringVar(&fch.version, "version", "", "Specific version of a chart. Without this, the latest version is fetched")
+	f.StringVar(&fch.digest, "digest", "", "SHA256 digest of the chart archive, as sha256:<digest>. The fetch fails if the archive does not match")
 	f.StringVar(&fch.keyring, "keyring", defaultKeyring(), "Keyring containing public keys")
 	f.StringVarP(&fch.destdir, "destination", "d", ".", "Location to write the chart. If this and tardir are specified, tardir is appended to this")
 	f.StringVar(&fch.repoURL, "repo", "", "Chart repository url where to locate the requested chart")
@@ -115,20 +142,34 @@ func newFetchCmd(out io.Writer) *cobra.Command {
 }
 
 func (f *fetchCmd) run() error {
-	c := downloader.ChartDownloader{
-		HelmHome: settings.Home,
-		Out:      f.out,
-		Keyring:  f.keyring,
-		Verify:   downloader.VerifyNever,
-		Getters:  getter.All(settings),
+	if f.digest != "" {
+		if pinnedChartReference(f.chartRef) {
+			return fmt.Errorf("chart reference %q is already pinned, it cannot be used with --digest", f.chartRef)
+		}
+		f.chartRef += "@" + f.digest
+	}
+
+	version := f.version
+	if version == "" && f.devel && !pinnedChartReference(f.chartRef) {
+		debug("setting version to >0.0.0-0")
+		version = ">0.0.0-0"
+	}
+
+	opts := action.ChartPathOptions{
+		Version:  version,
+		RepoURL:  f.repoURL,
 		Username: f.username,
 		Password: f.password,
+		CertFile: f.certFile,
+		KeyFile:  f.keyFile,
+		CAFile:   f.caFile,
+		Keyring:  f.keyring,
+		Verify:   downloader.VerifyNever,
 	}
-
 	if f.verify {
-		c.Verify = downloader.VerifyAlways
+		opts.Verify = downloader.VerifyAlways
 	} else if f.verifyLater {
-		c.Verify = downloader.VerifyLater
+		opts.Verify = downloader.VerifyLater
 	}
 
 	// If untar is set, we fetch to a tempdir, then untar and copy after
@@ -143,21 +184,18 @@ func (f *fetchCmd) run() error {
 		defer os.RemoveAll(dest)
 	}
 
-	if f.repoURL != "" {
-		chartURL, err := repo.FindChartInAuthRepoURL(f.repoURL, f.username, f.password, f.chartRef, f.version, f.certFile, f.keyFile, f.caFile, getter.All(settings))
-		if err != nil {
-			return err
-		}
-		f.chartRef = chartURL
+	saved, v, err := newActionConfig(f.out).DownloadChart(f.chartRef, opts, dest)
+	if f.verifyReport != "" && saved != "" {
+		f.reports = append(f.reports, provenance.NewReport(saved, v, err))
 	}
-
-	saved, v, err := c.DownloadTo(f.chartRef, f.version, dest)
 	if err != nil {
 		return err
 	}
 
-	if f.verify {
-		fmt.Fprintf(f.out, "Verification: %v\n", v)
+	// The report is the only output when it is written to stdout.
+	if f.verify && f.verifyReport != "-" {
+		printVerification(f.out, v)
+		fmt.Fprintf(f.out, "Digest: %s\n", v.FileHash)
 	}
 
 	// After verification, untar the chart into the requested directory.
@@ -180,6 +218,43 @@ func (f *fetchCmd) run() error {
 	return nil
 }
 
+// printVerification prints the identities of the signer of a verified chart
+// and the hash the chart was verified with.
+func printVerification(out io.Writer, v *provenance.Verification) {
+	if v.SignedBy != nil {
+		names := make([]string, 0, len(v.SignedBy.Identities))
+		for name := range v.SignedBy.Identities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(out, "Signed by: %s\n", name)
+		}
+		fmt.Fprintf(out, "Using Key With Fingerprint: %X\n", v.SignedBy.PrimaryKey.Fingerprint)
+	}
+	fmt.Fprintf(out, "Chart Hash Verified: %s\n", v.FileHash)
+}
+
+// writeReports writes the verification reports, one JSON document per chart.
+func (f *fetchCmd) writeReports() error {
+	out := f.out
+	if f.verifyReport != "-" {
+		file, err := os.Create(f.verifyReport)
+		if err != nil {
+			return fmt.Errorf("failed to write the verification report: %s", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	enc := json.NewEncoder(out)
+	for _, r := range f.reports {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to write the verification report: %s", err)
+		}
+	}
+	return nil
+}
+
 // defaultKeyring returns the expanded path to the default keyring.
 func defaultKeyring() string {
 	return os.ExpandEnv("$HOME/.gnupg/pubring.gpg")
@@ -18,12 +18,14 @@ package main
 
 import (
 	"bytes"
-	"fmt"
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
+	"strings"
 	"testing"
 
+	"k8s.io/helm/pkg/provenance"
 	"k8s.io/helm/pkg/repo/repotest"
 )
 
@@ -42,6 +44,13 @@ func TestFetchCmd(t *testing.T) {
 
 	settings.Home = hh
 
+	digest, err := provenance.DigestFile("testdata/testcharts/signtest-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := filepath.Join(hh.String(), "report.json")
+
 	// all flags will get "--home=TMDIR -d outdir" appended.
 	tests := []struct {
 		name         string
@@ -52,6 +61,7 @@ func TestFetchCmd(t *testing.T) {
 		expectFile   string
 		expectDir    bool
 		expectVerify bool
+		expectReport string
 	}{
 		{
 			name:       "Basic chart fetch",
@@ -91,6 +101,49 @@ func TestFetchCmd(t *testing.T) {
 			failExpect: "Failed to fetch provenance",
 			fail:       true,
 		},
+		{
+			name:         "Fetch, verify and report",
+			chart:        "test/signtest",
+			flags:        []string{"--verify", "--keyring", "testdata/helm-test-key.pub", "--verify-report", report},
+			expectFile:   "./signtest-0.1.0.tgz",
+			expectVerify: true,
+			expectReport: provenance.ResultVerified,
+		},
+		{
+			name:         "Fetch, fail verify and report",
+			chart:        "test/reqtest",
+			flags:        []string{"--verify", "--keyring", "testdata/helm-test-key.pub", "--verify-report", report},
+			failExpect:   "Failed to fetch provenance",
+			fail:         true,
+			expectReport: provenance.ResultFailed,
+		},
+		{
+			name:       "Fail report without verify",
+			chart:      "test/signtest",
+			flags:      []string{"--verify-report", report},
+			failExpect: "requires --verify",
+			fail:       true,
+		},
+		{
+			name:       "Chart fetch pinned to a digest",
+			chart:      "test/signtest",
+			flags:      []string{"--digest", "sha256:" + digest},
+			expectFile: "./signtest-0.1.0.tgz",
+		},
+		{
+			name:       "Fail chart fetch pinned to another digest",
+			chart:      "test/signtest",
+			flags:      []string{"--digest", "sha256:" + strings.Repeat("0", 64)},
+			failExpect: "not found",
+			fail:       true,
+		},
+		{
+			name:       "Fail chart fetch pinned twice",
+			chart:      "test/signtest@0.1.0",
+			flags:      []string{"--digest", "sha256:" + digest},
+			failExpect: "already pinned",
+			fail:       true,
+		},
 		{
 			name:       "Fetch and untar",
 			chart:      "test/signtest",
@@ -145,12 +198,17 @@ func TestFetchCmd(t *testing.T) {
 		outdir := filepath.Join(hh.String(), "testout")
 		os.RemoveAll(outdir)
 		os.Mkdir(outdir, 0755)
+		os.Remove(report)
 
 		buf := bytes.NewBuffer(nil)
 		cmd := newFetchCmd(buf)
 		tt.flags = append(tt.flags, "-d", outdir)
 		cmd.ParseFlags(tt.flags)
-		if err := cmd.RunE(cmd, []string{tt.chart}); err != nil {
+		err := cmd.RunE(cmd, []string{tt.chart})
+		if tt.expectReport != "" {
+			checkVerifyReport(t, tt.name, report, tt.expectReport)
+		}
+		if err != nil {
 			if tt.fail {
 				continue
 			}
@@ -158,12 +216,14 @@ func TestFetchCmd(t *testing.T) {
 			continue
 		}
 		if tt.expectVerify {
-			pointerAddressPattern := "0[xX][A-Fa-f0-9]+"
-			sha256Pattern := "[A-Fa-f0-9]{64}"
-			verificationRegex := regexp.MustCompile(
-				fmt.Sprintf("Verification: &{%s sha256:%s signtest-0.1.0.tgz}\n", pointerAddressPattern, sha256Pattern))
-			if !verificationRegex.MatchString(buf.String()) {
-				t.Errorf("%q: expected match for regex %s, got %s", tt.name, verificationRegex, buf.String())
+			expect := "Signed by: Helm Testing (This key should only be used for testing. DO NOT TRUST.) <helm-testing@helm.sh>\n" +
+				"Using Key With Fingerprint: 5E615389B53CA37F0EE60BD3843BBF981FC18762\n" +
+				"Chart Hash Verified: sha256:" + digest + "\n"
+			if !strings.Contains(buf.String(), expect) {
+				t.Errorf("%q: expected the verification\n%s\ngot\n%s", tt.name, expect, buf.String())
+			}
+			if !strings.Contains(buf.String(), "Digest: sha256:"+digest+"\n") {
+				t.Errorf("%q: expected the digest of the chart, got %s", tt.name, buf.String())
 			}
 		}
 
@@ -177,3 +237,25 @@ func TestFetchCmd(t *testing.T) {
 		}
 	}
 }
+
+func checkVerifyReport(t *testing.T, name, filename, result string) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Errorf("%q: expected a verification report: %s", name, err)
+		return
+	}
+	var r provenance.Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Errorf("%q: failed to parse the verification report: %s", name, err)
+		return
+	}
+	if r.Result != result {
+		t.Errorf("%q: expected result %q, got %q (%s)", name, result, r.Result, r.Error)
+	}
+	if !strings.HasPrefix(r.Digest, "sha256:") {
+		t.Errorf("%q: expected the digest of the chart, got %q", name, r.Digest)
+	}
+	if result == provenance.ResultVerified && (r.Signer == "" || r.Fingerprint == "") {
+		t.Errorf("%q: expected the signer and its fingerprint, got %q %q", name, r.Signer, r.Fingerprint)
+	}
+}
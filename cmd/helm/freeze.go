@@ -0,0 +1,185 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+const freezeDesc = `
+This command freezes a release. While a release is frozen, Tiller refuses to
+upgrade, roll back or delete it, so change freezes are enforced by the server
+rather than by process alone.
+
+The freeze lasts until 'helm unfreeze' is run or, if '--until' is given, until
+the given time. '--until' takes either an RFC 3339 time or a duration from now:
+
+	$ helm freeze my-release --reason "release week" --until 72h
+	$ helm freeze my-release --until 2019-05-03T00:00:00Z
+
+Freezing a frozen release replaces its reason and expiry.
+`
+
+const unfreezeDesc = `
+This command lifts the freeze of a release, allowing it to be upgraded, rolled
+back or deleted again.
+`
+
+type freezeCmd struct {
+	release string
+	reason  string
+	until   string
+	quiet   bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newFreezeCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	f := &freezeCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "freeze [flags] RELEASE_NAME",
+		Short:   "Prevent a release from being upgraded, rolled back or deleted",
+		Long:    freezeDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			f.release = args[0]
+			f.client = ensureHelmClient(f.client)
+			return f.run()
+		},
+	}
+
+	flags := cmd.Flags()
+	settings.AddFlagsTLS(flags)
+	flags.StringVar(&f.reason, "reason", "", "Reason for the freeze, shown when an operation is refused")
+	flags.StringVar(&f.until, "until", "", "Time the freeze expires, as an RFC 3339 time or a duration from now")
+	flags.BoolVar(&f.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(flags)
+
+	return cmd
+}
+
+func (f *freezeCmd) run() error {
+	now := time.Now()
+	freeze := &relutil.Freeze{At: now, Reason: f.reason}
+	if f.until != "" {
+		until, err := parseFreezeUntil(f.until, now)
+		if err != nil {
+			return err
+		}
+		freeze.Until = until
+	}
+
+	// clear any previous freeze so a new one without an expiry does not
+	// inherit the old one
+	_, err := f.client.UpdateReleaseMetadata(f.release,
+		helm.MetadataAnnotations(freeze.Annotations(), relutil.FreezeAnnotations),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+
+	if freeze.Until.IsZero() {
+		printMessage(f.out, f.quiet, msgFrozen, f.release)
+	} else {
+		printMessage(f.out, f.quiet, msgFrozenUntil, f.release, freeze.Until.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// parseFreezeUntil reads the expiry of a freeze, given either as an RFC 3339
+// time or as a duration from now.
+func parseFreezeUntil(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("--until must be in the future, got %q", value)
+		}
+		return now.Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --until %q, expected an RFC 3339 time or a duration", value)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("--until must be in the future, got %q", value)
+	}
+	return t, nil
+}
+
+type unfreezeCmd struct {
+	release string
+	quiet   bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newUnfreezeCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	u := &unfreezeCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "unfreeze [flags] RELEASE_NAME",
+		Short:   "Lift the freeze of a release",
+		Long:    unfreezeDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			u.release = args[0]
+			u.client = ensureHelmClient(u.client)
+			return u.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.BoolVar(&u.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (u *unfreezeCmd) run() error {
+	_, err := u.client.UpdateReleaseMetadata(u.release,
+		helm.MetadataAnnotations(nil, relutil.FreezeAnnotations),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+	printMessage(u.out, u.quiet, msgUnfrozen, u.release)
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+func TestFreezeCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "freeze until unfrozen",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--reason", "release week"},
+			expected: "Release \"thomas-guide\" has been frozen until it is unfrozen.\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name:     "freeze until a time",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--until", "2999-05-03T00:00:00Z"},
+			expected: "Release \"thomas-guide\" has been frozen until 2999-05-03T00:00:00Z.\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name:  "freeze until the past",
+			args:  []string{"thomas-guide"},
+			flags: []string{"--until", "2001-05-03T00:00:00Z"},
+			err:   true,
+			rels:  []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name: "freeze missing release",
+			args: []string{"no-such-release"},
+			err:  true,
+		},
+		{
+			name: "release required",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newFreezeCmd(c, out)
+	})
+}
+
+func TestUnfreezeCmd(t *testing.T) {
+	frozen := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})
+	frozen.Annotations = (&relutil.Freeze{At: time.Now(), Reason: "release week"}).Annotations()
+	frozen.Annotations["owner"] = "alice"
+
+	tests := []releaseCase{
+		{
+			name:     "unfreeze",
+			args:     []string{"thomas-guide"},
+			expected: "Release \"thomas-guide\" has been unfrozen.\n",
+			rels:     []*release.Release{frozen},
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newUnfreezeCmd(c, out)
+	})
+
+	if relutil.GetFreeze(frozen) != nil {
+		t.Errorf("expected release to be unfrozen, got annotations %v", frozen.Annotations)
+	}
+	if frozen.Annotations["owner"] != "alice" {
+		t.Errorf("expected other annotations to be kept, got %v", frozen.Annotations)
+	}
+}
+
+func TestParseFreezeUntil(t *testing.T) {
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{"72h", now.Add(72 * time.Hour), false},
+		{"2019-05-03T00:00:00Z", time.Date(2019, 5, 3, 0, 0, 0, 0, time.UTC), false},
+		{"-1h", time.Time{}, true},
+		{"2019-04-30T00:00:00Z", time.Time{}, true},
+		{"next tuesday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseFreezeUntil(tt.value, now)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: expected error to be %t, got %v", tt.value, tt.err, err)
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("%q: expected %s, got %s", tt.value, tt.expected, got)
+		}
+	}
+}
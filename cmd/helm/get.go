@@ -35,7 +35,11 @@ It can be used to get extended information about the release, including:
   - The generated manifest file
 
 By default, this prints a human readable collection of information about the
-chart, the supplied values, and the generated manifest file.
+chart, the supplied values, and the generated manifest file. With '--output json'
+or '--output yaml', it prints the same document as 'helm get all'.
+
+Every get subcommand accepts '--revision' to look at an earlier revision of the
+release, and '--output json' to print a structured document.
 `
 
 var errReleaseRequired = errors.New("release name is required")
@@ -46,6 +50,7 @@ type getCmd struct {
 	client   helm.Interface
 	version  int32
 	template string
+	output   string
 }
 
 func newGetCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -75,11 +80,15 @@ func newGetCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	settings.AddFlagsTLS(f)
 	f.Int32Var(&get.version, "revision", 0, "Get the named release with revision")
 	f.StringVar(&get.template, "template", "", "Go template for formatting the output, eg: {{.Release.Name}}")
+	f.StringVarP(&get.output, "output", "o", "", "Output the specified format (json or yaml)")
 
+	cmd.AddCommand(newGetAllCmd(nil, out))
 	cmd.AddCommand(newGetValuesCmd(nil, out))
 	cmd.AddCommand(newGetManifestCmd(nil, out))
 	cmd.AddCommand(newGetHooksCmd(nil, out))
 	cmd.AddCommand(newGetNotesCmd(nil, out))
+	cmd.AddCommand(newGetOutputsCmd(nil, out))
+	cmd.AddCommand(newGetTestsCmd(nil, out))
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -97,5 +106,12 @@ func (g *getCmd) run() error {
 	if g.template != "" {
 		return tpl(g.template, res, g.out)
 	}
+	if g.output != "" {
+		doc, err := newAllDocument(res.Release)
+		if err != nil {
+			return err
+		}
+		return printDocument(g.out, g.output, doc)
+	}
 	return printRelease(g.out, res.Release)
 }
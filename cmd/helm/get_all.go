@@ -0,0 +1,256 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+var getAllHelp = `
+This command prints everything recorded for a revision of a release in a single
+document: its values, computed values, manifest, hooks, notes and the results
+of its last test run.
+
+The document is printed as YAML, or as JSON with '--output json'. The other get
+subcommands print the same fields of the document with '--output json' or
+'--output yaml', so the revisions of a release can be compared section by
+section:
+
+    $ diff <(helm get all --revision 3 my-release) <(helm get all --revision 4 my-release)
+`
+
+// releaseDocument is the structured form of a revision of a release. 'helm
+// get all' prints all of it, the other get subcommands only their section.
+type releaseDocument struct {
+	Name           string                 `json:"name"`
+	Namespace      string                 `json:"namespace,omitempty"`
+	Revision       int32                  `json:"revision"`
+	Status         string                 `json:"status,omitempty"`
+	Updated        string                 `json:"updated,omitempty"`
+	Chart          string                 `json:"chart,omitempty"`
+	Values         map[string]interface{} `json:"values,omitempty"`
+	ComputedValues map[string]interface{} `json:"computedValues,omitempty"`
+	Manifest       string                 `json:"manifest,omitempty"`
+	Hooks          []hookDocument         `json:"hooks,omitempty"`
+	Notes          string                 `json:"notes,omitempty"`
+	Tests          *testsDocument         `json:"tests,omitempty"`
+}
+
+// hookDocument is a hook of a release.
+type hookDocument struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"`
+	Path     string   `json:"path"`
+	Events   []string `json:"events"`
+	Weight   int32    `json:"weight,omitempty"`
+	LastRun  string   `json:"lastRun,omitempty"`
+	Manifest string   `json:"manifest"`
+	Logs     string   `json:"logs,omitempty"`
+}
+
+// testsDocument is the last test run of a release.
+type testsDocument struct {
+	Started   string         `json:"started"`
+	Completed string         `json:"completed"`
+	Results   []testDocument `json:"results"`
+}
+
+// testDocument is the result of a test of a release.
+type testDocument struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Info      string `json:"info,omitempty"`
+	Started   string `json:"started,omitempty"`
+	Completed string `json:"completed,omitempty"`
+	Logs      string `json:"logs,omitempty"`
+}
+
+// newReleaseDocument returns the fields identifying a revision of a release,
+// without any of its sections.
+func newReleaseDocument(rel *release.Release) *releaseDocument {
+	d := &releaseDocument{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+		Chart:     formatChartname(rel.Chart),
+	}
+	if rel.Info != nil {
+		d.Status = rel.Info.GetStatus().GetCode().String()
+		d.Updated = timeconv.String(rel.Info.LastDeployed)
+	}
+	return d
+}
+
+// hookDocuments returns the hooks of a release.
+func hookDocuments(hooks []*release.Hook) []hookDocument {
+	docs := make([]hookDocument, 0, len(hooks))
+	for _, h := range hooks {
+		events := make([]string, 0, len(h.Events))
+		for _, e := range h.Events {
+			events = append(events, e.String())
+		}
+		d := hookDocument{
+			Name:     h.Name,
+			Kind:     h.Kind,
+			Path:     h.Path,
+			Events:   events,
+			Weight:   h.Weight,
+			Manifest: h.Manifest,
+			Logs:     h.Logs,
+		}
+		if h.LastRun != nil {
+			d.LastRun = timeconv.String(h.LastRun)
+		}
+		docs = append(docs, d)
+	}
+	return docs
+}
+
+// newTestsDocument returns the last test run of a release, or nil if it has not
+// been tested.
+func newTestsDocument(suite *release.TestSuite) *testsDocument {
+	if suite == nil {
+		return nil
+	}
+	d := &testsDocument{
+		Started:   timeconv.String(suite.StartedAt),
+		Completed: timeconv.String(suite.CompletedAt),
+		Results:   make([]testDocument, 0, len(suite.Results)),
+	}
+	for _, r := range suite.Results {
+		d.Results = append(d.Results, testDocument{
+			Name:      r.Name,
+			Status:    r.Status.String(),
+			Info:      r.Info,
+			Started:   timeconv.String(r.StartedAt),
+			Completed: timeconv.String(r.CompletedAt),
+			Logs:      r.Logs,
+		})
+	}
+	return d
+}
+
+// printDocument prints v in a structured format, json or yaml.
+func printDocument(out io.Writer, format string, v interface{}) error {
+	var b []byte
+	var err error
+	switch format {
+	case "json":
+		b, err = json.Marshal(v)
+	case "yaml":
+		b, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, strings.TrimSpace(string(b)))
+	return nil
+}
+
+type getAllCmd struct {
+	release  string
+	out      io.Writer
+	client   helm.Interface
+	version  int32
+	output   string
+	template string
+}
+
+func newGetAllCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	get := &getAllCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "all [flags] RELEASE_NAME",
+		Short:   "Download everything recorded for a named release",
+		Long:    getAllHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			get.release = args[0]
+			get.client = ensureHelmClient(get.client)
+			return get.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.Int32Var(&get.version, "revision", 0, "Get the named release with revision")
+	f.StringVarP(&get.output, "output", "o", "yaml", "Output the specified format (json or yaml)")
+	f.StringVar(&get.template, "template", "", "Go template for formatting the document, eg: {{.Name}} {{.Revision}}")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (g *getAllCmd) run() error {
+	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	if err != nil {
+		return prettyError(err)
+	}
+	doc, err := newAllDocument(res.Release)
+	if err != nil {
+		return err
+	}
+	if g.template != "" {
+		return tpl(g.template, doc, g.out)
+	}
+	return printDocument(g.out, g.output, doc)
+}
+
+// newAllDocument returns the document of a revision of a release with all
+// of its sections.
+func newAllDocument(rel *release.Release) (*releaseDocument, error) {
+	doc := newReleaseDocument(rel)
+
+	values, err := chartutil.ReadValues([]byte(rel.Config.GetRaw()))
+	if err != nil {
+		return nil, err
+	}
+	computed, err := chartutil.CoalesceValues(rel.Chart, rel.Config)
+	if err != nil {
+		return nil, err
+	}
+	doc.Values, doc.ComputedValues = values, computed
+
+	doc.Manifest = rel.Manifest
+	doc.Hooks = hookDocuments(rel.Hooks)
+	if rel.Info != nil {
+		doc.Notes = rel.Info.GetStatus().GetNotes()
+		doc.Tests = newTestsDocument(rel.Info.GetStatus().GetLastTestSuiteRun())
+	}
+	return doc, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestGetAllCmd(t *testing.T) {
+	revisions := func() []*release.Release {
+		return []*release.Release{
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", Version: 2}),
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		}
+	}
+
+	tests := []releaseCase{
+		{
+			name:     "get all as yaml",
+			args:     []string{"thomas-guide"},
+			expected: "(?s)^chart: foo-0.1.0-beta.1\ncomputedValues:\n  name: value\nhooks:\n- events:\n  - PRE_INSTALL\n  kind: Job\n.*\nname: thomas-guide\nnamespace: default\nrevision: 2\nstatus: DEPLOYED\nupdated: .*\nvalues:\n  name: value\n$",
+			rels:     revisions(),
+		},
+		{
+			name:     "get all of a revision as json",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--revision", "1", "-o", "json"},
+			expected: `^\{"name":"thomas-guide","namespace":"default","revision":1,"status":"SUPERSEDED","updated":"[^"]*","chart":"foo-0.1.0-beta.1","values":\{"name":"value"\},"computedValues":\{"name":"value"\},"manifest":"apiVersion: v1\\nkind: Secret\\nmetadata:\\n  name: fixture\\n","hooks":\[\{"name":"pre-install-hook","kind":"Job","path":"pre-install-hook.yaml","events":\["PRE_INSTALL"\],"lastRun":"[^"]*","manifest":".*"\}\]\}` + "\n$",
+			rels:     revisions(),
+		},
+		{
+			name:     "get all with a template",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--template", "{{.Name}} {{.Revision}} {{.Status}}"},
+			expected: "^thomas-guide 2 DEPLOYED$",
+			rels:     revisions(),
+		},
+		{
+			name:  "unknown output format",
+			args:  []string{"thomas-guide"},
+			flags: []string{"-o", "table"},
+			err:   true,
+			rels:  revisions(),
+		},
+		{
+			name:  "missing revision",
+			args:  []string{"thomas-guide"},
+			flags: []string{"--revision", "3"},
+			err:   true,
+			rels:  revisions(),
+		},
+		{
+			name: "get all requires release name arg",
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newGetAllCmd(c, out)
+	})
+}
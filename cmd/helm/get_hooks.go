@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -29,6 +30,13 @@ const getHooksHelp = `
 This command downloads hooks for a given release.
 
 Hooks are formatted in YAML and separated by the YAML '---\n' separator.
+
+With '--logs', the logs of the last run of the Pod and Job hooks follow their
+manifests as YAML comments. They are kept in the release, so they can be read
+after the hook has been deleted.
+
+With '--output json' or '--output yaml', the hooks are printed as a list, with
+their events, weight, last run and logs.
 `
 
 type getHooksCmd struct {
@@ -36,6 +44,8 @@ type getHooksCmd struct {
 	out     io.Writer
 	client  helm.Interface
 	version int32
+	logs    bool
+	output  string
 }
 
 func newGetHooksCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -60,6 +70,8 @@ func newGetHooksCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f := cmd.Flags()
 	settings.AddFlagsTLS(f)
 	f.Int32Var(&ghc.version, "revision", 0, "Get the named release with revision")
+	f.BoolVar(&ghc.logs, "logs", false, "Show the logs of the last run of the hooks as well")
+	f.StringVarP(&ghc.output, "output", "o", "", "Output the specified format (json or yaml)")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -74,8 +86,16 @@ func (g *getHooksCmd) run() error {
 		return prettyError(err)
 	}
 
+	if g.output != "" {
+		doc := newReleaseDocument(res.Release)
+		doc.Hooks = hookDocuments(res.Release.Hooks)
+		return printDocument(g.out, g.output, doc)
+	}
 	for _, hook := range res.Release.Hooks {
 		fmt.Fprintf(g.out, "---\n# %s\n%s\n", hook.Name, hook.Manifest)
+		if g.logs && hook.Logs != "" {
+			fmt.Fprintf(g.out, "# LOGS:\n# %s\n", strings.Replace(strings.TrimSuffix(hook.Logs, "\n"), "\n", "\n# ", -1))
+		}
 	}
 	return nil
 }
@@ -36,6 +36,14 @@ func TestGetHooks(t *testing.T) {
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"})},
 		},
+		{
+			name:     "get hooks with logs",
+			args:     []string{"aeneas"},
+			flags:    []string{"--logs"},
+			expected: fmt.Sprintf("---\n# %s\n%s\n# LOGS:\n# migrating\n# done\n", "pre-install-hook", helm.MockHookTemplate),
+			resp:     releaseWithHookLogs("aeneas", "migrating\ndone\n"),
+			rels:     []*release.Release{releaseWithHookLogs("aeneas", "migrating\ndone\n")},
+		},
 		{
 			name: "get hooks without args",
 			args: []string{},
@@ -46,3 +54,9 @@ func TestGetHooks(t *testing.T) {
 		return newGetHooksCmd(c, out)
 	})
 }
+
+func releaseWithHookLogs(name, logs string) *release.Release {
+	rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: name})
+	rel.Hooks[0].Logs = logs
+	return rel
+}
@@ -31,6 +31,16 @@ This command fetches the generated manifest for a given release.
 A manifest is a YAML-encoded representation of the Kubernetes resources that
 were generated from this release's chart(s). If a chart is dependent on other
 charts, those resources will also be included in the manifest.
+
+With '--live', the objects of the release are read from the cluster instead,
+showing exactly what is running. Status and fields populated by the cluster are
+left out, and documents come in the same order as in the stored manifest, so the
+two can be compared with diff:
+
+	$ diff <(helm get manifest my-release) <(helm get manifest my-release --live)
+
+With '--output json' or '--output yaml', the manifest is printed in a document
+identifying the revision it belongs to.
 `
 
 type getManifestCmd struct {
@@ -38,6 +48,8 @@ type getManifestCmd struct {
 	out     io.Writer
 	client  helm.Interface
 	version int32
+	live    bool
+	output  string
 }
 
 func newGetManifestCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -63,6 +75,8 @@ func newGetManifestCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f := cmd.Flags()
 	settings.AddFlagsTLS(f)
 	f.Int32Var(&get.version, "revision", 0, "Get the named release with revision")
+	f.BoolVar(&get.live, "live", false, "Get the current state of the objects of the release from the cluster")
+	f.StringVarP(&get.output, "output", "o", "", "Output the specified format (json or yaml)")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -72,10 +86,22 @@ func newGetManifestCmd(client helm.Interface, out io.Writer) *cobra.Command {
 
 // getManifest implements 'helm get manifest'
 func (g *getManifestCmd) run() error {
-	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	res, err := g.client.ReleaseContent(g.release,
+		helm.ContentReleaseVersion(g.version),
+		helm.ContentLiveManifest(g.live),
+	)
 	if err != nil {
 		return prettyError(err)
 	}
-	fmt.Fprintln(g.out, res.Release.Manifest)
+	manifest := res.Release.Manifest
+	if g.live {
+		manifest = res.LiveManifest
+	}
+	if g.output != "" {
+		doc := newReleaseDocument(res.Release)
+		doc.Manifest = manifest
+		return printDocument(g.out, g.output, doc)
+	}
+	fmt.Fprintln(g.out, manifest)
 	return nil
 }
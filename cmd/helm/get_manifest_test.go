@@ -35,6 +35,24 @@ func TestGetManifest(t *testing.T) {
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "juno"}),
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "juno"})},
 		},
+		{
+			name:     "get live manifest with release",
+			args:     []string{"juno"},
+			flags:    []string{"--live"},
+			expected: helm.MockManifest,
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "juno"}),
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "juno"})},
+		},
+		{
+			name:     "get manifest of a revision as json",
+			args:     []string{"juno"},
+			flags:    []string{"--revision", "2", "-o", "json"},
+			expected: `^\{"name":"juno","namespace":"default","revision":2,.*"manifest":"apiVersion: v1\\nkind: Secret\\nmetadata:\\n  name: fixture\\n"\}` + "\n$",
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "juno", Version: 3}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "juno", Version: 2}),
+			},
+		},
 		{
 			name: "get manifest without args",
 			args: []string{},
@@ -27,6 +27,9 @@ import (
 
 var getNotesHelp = `
 This command shows notes provided by the chart of a named release.
+
+With '--output json' or '--output yaml', the notes are printed in a document
+identifying the revision they belong to.
 `
 
 type getNotesCmd struct {
@@ -34,6 +37,7 @@ type getNotesCmd struct {
 	out     io.Writer
 	client  helm.Interface
 	version int32
+	output  string
 }
 
 func newGetNotesCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -62,6 +66,7 @@ func newGetNotesCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f := cmd.Flags()
 	settings.AddFlagsTLS(f)
 	f.Int32Var(&get.version, "revision", 0, "Get the notes of the named release with revision")
+	f.StringVarP(&get.output, "output", "o", "", "Output the specified format (json or yaml)")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -70,13 +75,19 @@ func newGetNotesCmd(client helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (n *getNotesCmd) run() error {
-	res, err := n.client.ReleaseStatus(n.release, helm.StatusReleaseVersion(n.version))
+	res, err := n.client.ReleaseContent(n.release, helm.ContentReleaseVersion(n.version))
 	if err != nil {
 		return prettyError(err)
 	}
 
-	if len(res.Info.Status.Notes) > 0 {
-		fmt.Fprintf(n.out, "NOTES:\n%s\n", res.Info.Status.Notes)
+	notes := res.Release.Info.GetStatus().GetNotes()
+	if n.output != "" {
+		doc := newReleaseDocument(res.Release)
+		doc.Notes = notes
+		return printDocument(n.out, n.output, doc)
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(n.out, "NOTES:\n%s\n", notes)
 	}
 	return nil
 }
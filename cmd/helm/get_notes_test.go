@@ -39,6 +39,18 @@ func TestGetNotesCmd(t *testing.T) {
 				}),
 			},
 		},
+		{
+			name:     "get notes as json",
+			args:     []string{"flummoxed-chickadee"},
+			flags:    []string{"-o", "json"},
+			expected: `"notes":"release notes"\}`,
+			rels: []*release.Release{
+				releaseMockWithStatus(&release.Status{
+					Code:  release.Status_DEPLOYED,
+					Notes: "release notes",
+				}),
+			},
+		},
 		{
 			name: "get notes requires release name arg",
 			err:  true,
@@ -0,0 +1,95 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+)
+
+var getOutputsHelp = `
+This command shows the outputs of a named release.
+
+The outputs are rendered from the templates/outputs.yaml template of the chart,
+which must be a map. Unlike the notes, they are meant to be read by tools, for
+instance to find the URL of a service or the name of a generated secret:
+
+    $ helm get outputs -o json my-release
+`
+
+type getOutputsCmd struct {
+	release string
+	out     io.Writer
+	client  helm.Interface
+	version int32
+	output  string
+}
+
+func newGetOutputsCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	get := &getOutputsCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "outputs [flags] RELEASE_NAME",
+		Short:   "Displays the outputs of the named release",
+		Long:    getOutputsHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			get.release = args[0]
+			get.client = ensureHelmClient(get.client)
+			return get.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.Int32Var(&get.version, "revision", 0, "Get the outputs of the named release with revision")
+	f.StringVarP(&get.output, "output", "o", "yaml", "Output the specified format (json or yaml)")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (g *getOutputsCmd) run() error {
+	res, err := g.client.ReleaseStatus(g.release, helm.StatusReleaseVersion(g.version))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	outputs, err := chartutil.ReadValues([]byte(res.Info.Status.Outputs))
+	if err != nil {
+		return err
+	}
+	result, err := formatValues(g.output, outputs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(g.out, result)
+	return nil
+}
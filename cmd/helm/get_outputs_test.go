@@ -0,0 +1,68 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestGetOutputsCmd(t *testing.T) {
+	withOutputs := func() []*release.Release {
+		return []*release.Release{
+			releaseMockWithStatus(&release.Status{
+				Code:    release.Status_DEPLOYED,
+				Outputs: "url: http://example.com\nreplicas: 3\n",
+			}),
+		}
+	}
+	tests := []releaseCase{
+		{
+			name:     "get outputs of a deployed release",
+			args:     []string{"flummoxed-chickadee"},
+			expected: "^replicas: 3\nurl: http://example.com\n\n$",
+			rels:     withOutputs(),
+		},
+		{
+			name:     "get outputs as json",
+			args:     []string{"flummoxed-chickadee"},
+			flags:    []string{"--output", "json"},
+			expected: `^\{"replicas":3,"url":"http://example.com"\}\n$`,
+			rels:     withOutputs(),
+		},
+		{
+			name:     "get outputs of a release without outputs",
+			args:     []string{"flummoxed-chickadee"},
+			flags:    []string{"--output", "json"},
+			expected: `^\{\}\n$`,
+			rels:     []*release.Release{releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})},
+		},
+		{
+			name: "get outputs requires release name arg",
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newGetOutputsCmd(c, out)
+	})
+}
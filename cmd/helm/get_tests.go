@@ -0,0 +1,109 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+const getTestsHelp = `
+This command shows the results of the last test run of a named release,
+including the logs captured from each test pod.
+
+Use '--output junit' to print the results as a JUnit XML report, or
+'--output json' or '--output yaml' to print them as a structured document.
+`
+
+type getTestsCmd struct {
+	release string
+	out     io.Writer
+	client  helm.Interface
+	version int32
+	output  string
+}
+
+func newGetTestsCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	gtc := &getTestsCmd{
+		out:    out,
+		client: client,
+	}
+	cmd := &cobra.Command{
+		Use:     "tests [flags] RELEASE_NAME",
+		Short:   "Display the results and logs of the last test run of a named release",
+		Long:    getTestsHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			gtc.release = args[0]
+			gtc.client = ensureHelmClient(gtc.client)
+			return gtc.run()
+		},
+	}
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.Int32Var(&gtc.version, "revision", 0, "Get the named release with revision")
+	f.StringVarP(&gtc.output, "output", "o", "", "Output the test results in the specified format (json, junit or yaml)")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (g *getTestsCmd) run() error {
+	switch g.output {
+	case "", "json", "junit", "yaml":
+	default:
+		return fmt.Errorf("unknown output format %q", g.output)
+	}
+
+	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	suite := res.Release.Info.Status.LastTestSuiteRun
+	if suite == nil {
+		return fmt.Errorf("release %q has not been tested", g.release)
+	}
+
+	switch g.output {
+	case "junit":
+		return writeJUnit(g.out, g.release, suite)
+	case "json", "yaml":
+		doc := newReleaseDocument(res.Release)
+		doc.Tests = newTestsDocument(suite)
+		return printDocument(g.out, g.output, doc)
+	}
+
+	fmt.Fprintf(g.out, "Last Started: %s\nLast Completed: %s\n\n%s\n",
+		timeconv.String(suite.StartedAt),
+		timeconv.String(suite.CompletedAt),
+		formatTestResults(suite.Results))
+	if logs := formatTestLogs(suite.Results); logs != "" {
+		fmt.Fprintf(g.out, "\n%s", logs)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func testedReleaseMock(name string) *release.Release {
+	rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: name})
+	rel.Info.Status.LastTestSuiteRun = &release.TestSuite{
+		StartedAt:   &timestamp.Timestamp{Seconds: 242085845},
+		CompletedAt: &timestamp.Timestamp{Seconds: 242085850},
+		Results: []*release.TestRun{
+			{
+				Name:        "finding-nemo",
+				Status:      release.TestRun_SUCCESS,
+				StartedAt:   &timestamp.Timestamp{Seconds: 242085845},
+				CompletedAt: &timestamp.Timestamp{Seconds: 242085847},
+				Logs:        "just keep swimming\n",
+			},
+			{
+				Name:        "gold-rush",
+				Status:      release.TestRun_FAILURE,
+				Info:        "no gold found",
+				StartedAt:   &timestamp.Timestamp{Seconds: 242085845},
+				CompletedAt: &timestamp.Timestamp{Seconds: 242085850},
+			},
+		},
+	}
+	return rel
+}
+
+func TestGetTests(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "get tests with release",
+			args:     []string{"aeneas"},
+			expected: "finding-nemo\\s+SUCCESS.*\n.*gold-rush\\s+FAILURE\\s+no gold found.*\n+LOGS: finding-nemo\n    just keep swimming\n",
+			rels:     []*release.Release{testedReleaseMock("aeneas")},
+		},
+		{
+			name:     "get tests as junit",
+			args:     []string{"aeneas"},
+			flags:    []string{"--output", "junit"},
+			expected: `<testsuite name="aeneas" tests="2" failures="1" errors="0" time="5.000"`,
+			rels:     []*release.Release{testedReleaseMock("aeneas")},
+		},
+		{
+			name: "get tests of untested release",
+			args: []string{"aeneas"},
+			rels: []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"})},
+			err:  true,
+		},
+		{
+			name: "get tests without args",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newGetTestsCmd(c, out)
+	})
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJUnit(&buf, "aeneas", testedReleaseMock("aeneas").Info.Status.LastTestSuiteRun); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testcase name="finding-nemo" classname="aeneas" time="2.000">`,
+		`<system-out>just keep swimming&#xA;</system-out>`,
+		`<failure message="test gold-rush failed">no gold found</failure>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -20,7 +20,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/chartutil"
@@ -29,6 +33,17 @@ import (
 
 var getValuesHelp = `
 This command downloads a values file for a given release.
+
+With '--diff', the values of the release are compared with those of another
+revision instead. The comparison goes from the revision given to '--diff' to
+the one given to '--revision' (the current revision by default), for example
+
+    $ helm get values --revision 5 --diff 3 my-release
+
+shows what changed in the values between revisions 3 and 5. By default the
+comparison is a unified diff of both values files. With
+'--diff-format structured' the added, removed and changed keys are listed
+instead, each by its dotted path; '--output json' prints that list as JSON.
 `
 
 type getValuesCmd struct {
@@ -38,6 +53,8 @@ type getValuesCmd struct {
 	client    helm.Interface
 	version   int32
 	output    string
+	diff      int32
+	diffFmt   string
 }
 
 func newGetValuesCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -64,7 +81,9 @@ func newGetValuesCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	settings.AddFlagsTLS(f)
 	f.Int32Var(&get.version, "revision", 0, "Get the named release with revision")
 	f.BoolVarP(&get.allValues, "all", "a", false, "Dump all (computed) values")
-	f.StringVar(&get.output, "output", "yaml", "Output the specified format (json or yaml)")
+	f.StringVarP(&get.output, "output", "o", "yaml", "Output the specified format (json or yaml)")
+	f.Int32Var(&get.diff, "diff", 0, "Compare the values with those of this revision")
+	f.StringVar(&get.diffFmt, "diff-format", "unified", "Format of the comparison made by --diff (unified or structured)")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -74,32 +93,170 @@ func newGetValuesCmd(client helm.Interface, out io.Writer) *cobra.Command {
 
 // getValues implements 'helm get values'
 func (g *getValuesCmd) run() error {
-	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	if g.diff != 0 {
+		return g.runDiff()
+	}
+
+	values, _, err := g.values(g.version)
 	if err != nil {
-		return prettyError(err)
+		return err
 	}
 
-	values, err := chartutil.ReadValues([]byte(res.Release.Config.Raw))
+	result, err := formatValues(g.output, values)
 	if err != nil {
 		return err
 	}
+	fmt.Fprintln(g.out, result)
+	return nil
+}
+
+// values returns the values of a revision of the release, and the number of
+// that revision.
+func (g *getValuesCmd) values(version int32) (chartutil.Values, int32, error) {
+	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(version))
+	if err != nil {
+		return nil, 0, prettyError(err)
+	}
+
+	values, err := chartutil.ReadValues([]byte(res.Release.Config.Raw))
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// If the user wants all values, compute the values and return.
 	if g.allValues {
 		values, err = chartutil.CoalesceValues(res.Release.Chart, res.Release.Config)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, res.Release.Version, nil
+}
+
+// valuesDiff is the structured comparison of the values of two revisions.
+type valuesDiff struct {
+	Release string        `json:"release"`
+	From    int32         `json:"from"`
+	To      int32         `json:"to"`
+	Added   []valueChange `json:"added"`
+	Removed []valueChange `json:"removed"`
+	Changed []valueChange `json:"changed"`
+}
+
+// valueChange is a key whose value was added, removed or changed.
+type valueChange struct {
+	Key string      `json:"key"`
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// runDiff implements 'helm get values --diff'
+func (g *getValuesCmd) runDiff() error {
+	switch g.diffFmt {
+	case "unified", "structured":
+	default:
+		return fmt.Errorf("Unknown diff format %q", g.diffFmt)
+	}
+	switch g.output {
+	case "", "yaml", "json":
+	default:
+		return fmt.Errorf("Unknown output format %q", g.output)
+	}
+
+	to, toVersion, err := g.values(g.version)
+	if err != nil {
+		return err
+	}
+	from, fromVersion, err := g.values(g.diff)
+	if err != nil {
+		return err
+	}
+
+	if g.output == "json" || g.diffFmt == "structured" {
+		d := diffValues(from, to)
+		d.Release, d.From, d.To = g.release, fromVersion, toVersion
+		var out []byte
+		if g.output == "json" {
+			out, err = json.Marshal(d)
+		} else {
+			out, err = yaml.Marshal(d)
+		}
 		if err != nil {
 			return err
 		}
+		fmt.Fprintln(g.out, strings.TrimSpace(string(out)))
+		return nil
 	}
 
-	result, err := formatValues(g.output, values)
+	a, err := from.YAML()
 	if err != nil {
 		return err
 	}
-	fmt.Fprintln(g.out, result)
+	b, err := to.YAML()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(g.out, "--- %s (revision %d)\n+++ %s (revision %d)\n", g.release, fromVersion, g.release, toVersion)
+	lines := diffLines(strings.Split(strings.TrimSpace(a), "\n"), strings.Split(strings.TrimSpace(b), "\n"), 3)
+	if len(lines) == 0 {
+		fmt.Fprintln(g.out, "The values are the same.")
+		return nil
+	}
+	for _, l := range lines {
+		fmt.Fprintln(g.out, l)
+	}
 	return nil
 }
 
+// diffValues compares two sets of values key by key. Nested maps are
+// compared by their keys; any other value, lists included, is compared as
+// a whole.
+func diffValues(from, to chartutil.Values) valuesDiff {
+	a, b := map[string]interface{}{}, map[string]interface{}{}
+	flattenValues("", from, a)
+	flattenValues("", to, b)
+
+	d := valuesDiff{Added: []valueChange{}, Removed: []valueChange{}, Changed: []valueChange{}}
+	for k, v := range a {
+		nv, ok := b[k]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, valueChange{Key: k, Old: v})
+		case !reflect.DeepEqual(v, nv):
+			d.Changed = append(d.Changed, valueChange{Key: k, Old: v, New: nv})
+		}
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok {
+			d.Added = append(d.Added, valueChange{Key: k, New: v})
+		}
+	}
+	for _, c := range [][]valueChange{d.Added, d.Removed, d.Changed} {
+		sort.Slice(c, func(i, j int) bool { return c[i].Key < c[j].Key })
+	}
+	return d
+}
+
+// flattenValues records the values of v by their dotted path. Empty maps are
+// kept as values so that adding or removing one is not lost.
+func flattenValues(prefix string, v map[string]interface{}, into map[string]interface{}) {
+	for k, val := range v {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		m, ok := val.(map[string]interface{})
+		if vals, isValues := val.(chartutil.Values); isValues {
+			m, ok = vals, true
+		}
+		if !ok || len(m) == 0 {
+			into[key] = val
+			continue
+		}
+		flattenValues(key, m, into)
+	}
+}
+
 func formatValues(format string, values chartutil.Values) (string, error) {
 	switch format {
 	case "", "yaml":
@@ -37,6 +37,17 @@ func TestGetValuesCmd(t *testing.T) {
 		Config: &chart.Config{Raw: `foo: "bar"`},
 	})
 
+	revisionOne := helm.ReleaseMock(&helm.MockReleaseOptions{
+		Name:    "thomas-guide",
+		Version: 1,
+		Config:  &chart.Config{Raw: "foo: bar\nimage:\n  tag: \"1.0\"\n  pullPolicy: Always\n"},
+	})
+	revisionTwo := helm.ReleaseMock(&helm.MockReleaseOptions{
+		Name:    "thomas-guide",
+		Version: 2,
+		Config:  &chart.Config{Raw: "image:\n  tag: \"1.1\"\n  pullPolicy: Always\nreplicas: 3\n"},
+	})
+
 	tests := []releaseCase{
 		{
 			name:     "get values with a release",
@@ -73,6 +84,34 @@ func TestGetValuesCmd(t *testing.T) {
 			rels:  []*release.Release{releaseWithValues},
 			err:   true,
 		},
+		{
+			name:     "get values diff between revisions",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--diff", "1"},
+			expected: `--- thomas-guide \(revision 1\)\n\+\+\+ thomas-guide \(revision 2\)\n- foo: bar\n  image:\n    pullPolicy: Always\n-   tag: "1.0"\n\+   tag: "1.1"\n\+ replicas: 3\n`,
+			rels:     []*release.Release{revisionTwo, revisionOne},
+		},
+		{
+			name:     "get values structured diff in json",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--revision", "2", "--diff", "1", "--output", "json"},
+			expected: `{"release":"thomas-guide","from":1,"to":2,"added":\[{"key":"replicas","new":3}\],"removed":\[{"key":"foo","old":"bar"}\],"changed":\[{"key":"image.tag","old":"1.0","new":"1.1"}\]}`,
+			rels:     []*release.Release{revisionTwo, revisionOne},
+		},
+		{
+			name:     "get values structured diff of the same values",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--revision", "1", "--diff", "1", "--diff-format", "structured"},
+			expected: "added: \\[\\]\nchanged: \\[\\]\nfrom: 1\nrelease: thomas-guide\nremoved: \\[\\]\nto: 1",
+			rels:     []*release.Release{revisionTwo, revisionOne},
+		},
+		{
+			name:  "get values diff with an unknown revision",
+			args:  []string{"thomas-guide"},
+			flags: []string{"--diff", "7"},
+			rels:  []*release.Release{revisionTwo, revisionOne},
+			err:   true,
+		},
 	}
 	cmd := func(c *helm.FakeClient, out io.Writer) *cobra.Command {
 		return newGetValuesCmd(c, out)
@@ -18,8 +18,10 @@ package main // import "k8s.io/helm/cmd/helm"
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"strings"
 
@@ -32,96 +34,33 @@ import (
 	// Import to initialize client auth plugins.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/helm/pkg/action"
 	"k8s.io/helm/pkg/helm"
 	helm_env "k8s.io/helm/pkg/helm/environment"
 	"k8s.io/helm/pkg/helm/portforwarder"
 	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/tlsutil"
+	"k8s.io/helm/pkg/trace"
 )
 
 const (
 	bashCompletionFunc = `
-__helm_override_flag_list=(--kubeconfig --kube-context --host --tiller-namespace --home)
-__helm_override_flags()
+__helm_complete()
 {
-    local ${__helm_override_flag_list[*]##*-} two_word_of of var
-    for w in "${words[@]}"; do
-        if [ -n "${two_word_of}" ]; then
-            eval "${two_word_of##*-}=\"${two_word_of}=\${w}\""
-            two_word_of=
-            continue
-        fi
-        for of in "${__helm_override_flag_list[@]}"; do
-            case "${w}" in
-                ${of}=*)
-                    eval "${of##*-}=\"${w}\""
-                    ;;
-                ${of})
-                    two_word_of="${of}"
-                    ;;
-            esac
-        done
-    done
-    for var in "${__helm_override_flag_list[@]##*-}"; do
-        if eval "test -n \"\$${var}\""; then
-            eval "echo \${${var}}"
-        fi
-    done
-}
-
-__helm_list_releases()
-{
-    __helm_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
-    local out filter
-    # Use ^ to map from the start of the release name
-    filter="^${words[c]}"
-    if out=$(helm list $(__helm_override_flags) -a -q ${filter} 2>/dev/null); then
-        COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
-    fi
-}
-
-__helm_list_repos()
-{
-    __helm_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
-    local out oflags
-    oflags=$(__helm_override_flags)
-    __helm_debug "${FUNCNAME[0]}: __helm_override_flags are ${oflags}"
-    if out=$(helm repo list ${oflags} | tail +2 | cut -f1 2>/dev/null); then
-        COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
+    __helm_debug "${FUNCNAME[0]}: cur is ${cur} words[@] is ${words[@]}"
+    local out args=( "${words[@]:1:$((cword-1))}" )
+    # cur is only the value when completing --flag=value
+    if [[ ${words[cword]} == -*=* ]]; then
+        args+=( "${words[cword]%%=*}" )
     fi
-}
-
-__helm_list_plugins()
-{
-    __helm_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
-    local out oflags
-    oflags=$(__helm_override_flags)
-    __helm_debug "${FUNCNAME[0]}: __helm_override_flags are ${oflags}"
-    if out=$(helm plugin list ${oflags} | tail +2 | cut -f1 2>/dev/null); then
+    if out=$(helm __complete "${args[@]}" "${cur}" 2>/dev/null); then
         COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
     fi
 }
 
 __helm_custom_func()
 {
-    __helm_debug "${FUNCNAME[0]}: c is $c words[@] is ${words[@]}"
-    case ${last_command} in
-        helm_delete | helm_history | helm_status | helm_test |\
-        helm_upgrade | helm_rollback | helm_get_*)
-            __helm_list_releases
-            return
-            ;;
-        helm_repo_remove | helm_repo_update)
-            __helm_list_repos
-            return
-            ;;
-        helm_plugin_remove | helm_plugin_update)
-            __helm_list_plugins
-            return
-            ;;
-        *)
-            ;;
-    esac
+    __helm_complete
 }
 `
 )
@@ -129,6 +68,8 @@ __helm_custom_func()
 var (
 	tillerTunnel *kube.Tunnel
 	settings     helm_env.EnvSettings
+	// activeProfile is the profile applied to the settings, if any.
+	activeProfile *helm_env.Profile
 )
 
 var globalUsage = `The Kubernetes package manager
@@ -153,6 +94,10 @@ Environment:
 - $HELM_HOST:           Set an alternative Tiller host. The format is host:port
 - $HELM_NO_PLUGINS:     Disable plugins. Set HELM_NO_PLUGINS=1 to disable plugins.
 - $TILLER_NAMESPACE:    Set an alternative Tiller namespace (default "kube-system")
+- $HELM_CLIENT_ONLY:    Manage releases without Tiller. Set HELM_CLIENT_ONLY=true to enable it.
+- $HELM_LOG_FORMAT:     Format of the logs written to stderr: text or json (default "text")
+- $HELM_LOG_LEVEL:      Lowest level of the logs: debug, info, warn or error (default "info")
+- $HELM_EXPLAIN:        Log the file and network access of commands. Set HELM_EXPLAIN=true to enable it.
 - $KUBECONFIG:          Set an alternative Kubernetes configuration file (default "~/.kube/config")
 - $HELM_TLS_CA_CERT:    Path to TLS CA certificate used to verify the Helm client and Tiller server certificates (default "$HELM_HOME/ca.pem")
 - $HELM_TLS_CERT:       Path to TLS client certificate file for authenticating to Tiller (default "$HELM_HOME/cert.pem")
@@ -160,6 +105,10 @@ Environment:
 - $HELM_TLS_ENABLE:     Enable TLS connection between Helm and Tiller (default "false")
 - $HELM_TLS_VERIFY:     Enable TLS connection between Helm and Tiller and verify Tiller server certificate (default "false")
 - $HELM_TLS_HOSTNAME:   The hostname or IP address used to verify the Tiller server certificate (default "127.0.0.1")
+- $HELM_REPO_TIMEOUT:   The duration each request to a chart repository may take, such as 1m (default no limit)
+- $HELM_PROFILE:        Name of the profile to use instead of the current one of the profiles file
+- $HELM_PROFILES:       Set an alternative location for the profiles file (default "~/.helm/profiles.yaml")
+- $HELM_ENABLE_DECRYPTION: Decrypt the values files encrypted with SOPS. Set HELM_ENABLE_DECRYPTION=true to enable it.
 - $HELM_KEY_PASSPHRASE: Set HELM_KEY_PASSPHRASE to the passphrase of your PGP private key. If set, you will not be prompted for the passphrase while signing helm charts
 
 `
@@ -170,7 +119,13 @@ func newRootCmd(args []string) *cobra.Command {
 		Short:        "The Helm package manager for Kubernetes.",
 		Long:         globalUsage,
 		SilenceUsage: true,
-		PersistentPreRun: func(*cobra.Command, []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := applyProfile(cmd.Flags()); err != nil && !isProfileCmd(cmd) {
+				return err
+			}
+			if err := setupLogging(); err != nil {
+				logger.Warnf("%s", err)
+			}
 			if settings.TLSCaCertFile == helm_env.DefaultTLSCaCert || settings.TLSCaCertFile == "" {
 				settings.TLSCaCertFile = settings.Home.TLSCaCert()
 			} else {
@@ -186,6 +141,11 @@ func newRootCmd(args []string) *cobra.Command {
 			} else {
 				settings.TLSKeyFile = os.ExpandEnv(settings.TLSKeyFile)
 			}
+			enableAutoTLS(cmd.Flags(), settings.Home)
+			if err := loadMessages(settings.Home, messageLocale()); err != nil {
+				logger.Warnf("%s", err)
+			}
+			return nil
 		},
 		PersistentPostRun: func(*cobra.Command, []string) {
 			teardown()
@@ -206,27 +166,48 @@ func newRootCmd(args []string) *cobra.Command {
 		newInspectCmd(out),
 		newLintCmd(out),
 		newPackageCmd(out),
+		newSnapshotCmd(out),
+		newPushCmd(out),
 		newRepoCmd(out),
-		newSearchCmd(out),
+		newResolveCmd(out),
+		newSearchCmd(nil, out),
 		newServeCmd(out),
+		newStarterCmd(out),
 		newVerifyCmd(out),
+		newVerifyCompatCmd(out),
 
 		// release commands
+		newBundleCmd(nil, out),
+		newCancelCmd(nil, out),
+		newCapabilitiesCmd(nil, out),
+		newChartCmd(nil, out),
 		newDeleteCmd(nil, out),
+		newFreezeCmd(nil, out),
 		newGetCmd(nil, out),
 		newHistoryCmd(nil, out),
 		newInstallCmd(nil, out),
 		newListCmd(nil, out),
+		newMapKubeAPIsCmd(nil, out),
+		newReleaseCmd(nil, out),
+		newResourcesCmd(nil, out),
 		newRollbackCmd(nil, out),
 		newStatusCmd(nil, out),
+		newTimelineCmd(nil, out),
+		newTopCmd(nil, out),
+		newUnfreezeCmd(nil, out),
 		newUpgradeCmd(nil, out),
+		newWindowCmd(nil, out),
 
 		newReleaseTestCmd(nil, out),
 		newResetCmd(nil, out),
+		newStorageCmd(out),
 		newVersionCmd(nil, out),
 
 		newCompletionCmd(out),
+		newCompleteCmd(cmd, out),
+		newEnvCmd(out),
 		newHomeCmd(out),
+		newProfileCmd(out),
 		newInitCmd(out),
 		newPluginCmd(out),
 		newTemplateCmd(out),
@@ -242,10 +223,17 @@ func newRootCmd(args []string) *cobra.Command {
 
 	// set defaults from environment
 	settings.Init(flags)
+	// the home of the profile is needed to find its plugins, errors are
+	// reported once the flags of the command are parsed
+	applyProfile(flags)
+	// the plugins are loaded before the flags of the command are parsed
+	setupLogging()
 
 	// Find and add plugins
 	loadPlugins(cmd, out)
 
+	addFlagCompletions(cmd)
+
 	return cmd
 }
 
@@ -272,6 +260,19 @@ func markDeprecated(cmd *cobra.Command, notice string) *cobra.Command {
 }
 
 func setupConnection() error {
+	return setupConnectionIn("")
+}
+
+// setupConnectionIn connects to Tiller, or in client-only mode starts the
+// release service in-process with the release records of namespace. An empty
+// namespace is the one of the current kube context.
+func setupConnectionIn(namespace string) error {
+	if settings.ClientOnly {
+		if namespace == "" {
+			namespace = defaultNamespace()
+		}
+		return startLocalTiller(namespace)
+	}
 	if settings.TillerHost == "" {
 		config, client, err := getKubeClient(settings.KubeContext, settings.KubeConfig)
 		if err != nil {
@@ -298,6 +299,9 @@ func teardown() {
 	if tillerTunnel != nil {
 		tillerTunnel.Close()
 	}
+	if localTiller != nil {
+		localTiller.Stop()
+	}
 }
 
 func checkArgsLength(argsReceived int, requiredArgs ...string) error {
@@ -332,6 +336,15 @@ func configForContext(context string, kubeconfig string) (*rest.Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not get Kubernetes config for context %q: %s", context, err)
 	}
+	if settings.Explain {
+		wrap := config.WrapTransport
+		config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			if wrap != nil {
+				rt = wrap(rt)
+			}
+			return trace.Transport(rt)
+		}
+	}
 	return config, nil
 }
 
@@ -348,6 +361,15 @@ func getKubeClient(context string, kubeconfig string) (*rest.Config, kubernetes.
 	return config, client, nil
 }
 
+// newActionConfig returns the configuration of the client side actions for
+// the current settings, writing their messages to out.
+func newActionConfig(out io.Writer) *action.Configuration {
+	cfg := action.NewConfiguration(settings)
+	cfg.Out = out
+	cfg.Log = debug
+	return cfg
+}
+
 // ensureHelmClient returns a new helm client impl. if h is not nil.
 func ensureHelmClient(h helm.Interface) helm.Interface {
 	if h != nil {
@@ -378,5 +400,35 @@ func newClient() helm.Interface {
 		}
 		options = append(options, helm.WithTLS(tlscfg))
 	}
+	if settings.UserImpersonate && !settings.ClientOnly {
+		token, err := userToken(settings.KubeContext, settings.KubeConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		options = append(options, helm.UserToken(token))
+	}
 	return helm.NewClient(options...)
 }
+
+// userToken returns the bearer token authenticating the user of a kubeconfig
+// context, sent to a Tiller impersonating its callers.
+func userToken(context, kubeconfig string) (string, error) {
+	config, err := configForContext(context, kubeconfig)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case config.BearerToken != "":
+		return config.BearerToken, nil
+	case config.BearerTokenFile != "":
+		b, err := ioutil.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("could not read the user token: %s", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	case config.AuthProvider != nil && config.AuthProvider.Config["id-token"] != "":
+		return config.AuthProvider.Config["id-token"], nil
+	}
+	return "", fmt.Errorf("--user-impersonate requires the kubeconfig user to authenticate with a bearer token")
+}
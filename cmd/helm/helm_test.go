@@ -258,6 +258,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -277,6 +279,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               true,
@@ -296,6 +300,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -315,6 +321,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -334,6 +342,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -353,6 +363,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -372,6 +384,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -392,6 +406,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               true,
@@ -412,6 +428,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -432,6 +450,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -452,6 +472,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -472,6 +494,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -492,6 +516,8 @@ func TestTLSFlags(t *testing.T) {
 				TillerNamespace:         "kube-system",
 				Home:                    home,
 				Debug:                   false,
+				LogFormat:               "text",
+				LogLevel:                "info",
 				KubeContext:             "",
 				KubeConfig:              "",
 				TLSEnable:               false,
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/gosuri/uitable"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/timeconv"
 )
 
@@ -55,6 +57,11 @@ The historical release set is printed as a formatted table, e.g:
     2           Mon Oct 3 10:15:13 2016     SUPERSEDED      alpine-0.1.0  Upgraded successfully
     3           Mon Oct 3 10:15:13 2016     SUPERSEDED      alpine-0.1.0  Rolled back to 2
     4           Mon Oct 3 10:15:13 2016     DEPLOYED        alpine-0.1.0  Upgraded successfully
+
+With '--audit', the operations on the release recorded in Tiller's audit log
+are printed instead: who ran each install, upgrade, rollback and deletion, with
+the chart, a digest of the supplied values and the outcome. Tiller must be
+configured with a file or events audit sink.
 `
 
 type historyCmd struct {
@@ -64,6 +71,7 @@ type historyCmd struct {
 	helmc        helm.Interface
 	colWidth     uint
 	outputFormat string
+	audit        bool
 }
 
 func newHistoryCmd(c helm.Interface, w io.Writer) *cobra.Command {
@@ -92,6 +100,7 @@ func newHistoryCmd(c helm.Interface, w io.Writer) *cobra.Command {
 	f.Int32Var(&his.max, "max", 256, "Maximum number of revisions to include in history")
 	f.UintVar(&his.colWidth, "col-width", 60, "Specifies the max column width of output")
 	f.StringVarP(&his.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+	f.BoolVar(&his.audit, "audit", false, "Print the operations on the release recorded in the audit log")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -100,6 +109,9 @@ func newHistoryCmd(c helm.Interface, w io.Writer) *cobra.Command {
 }
 
 func (cmd *historyCmd) run() error {
+	if cmd.audit {
+		return cmd.runAudit()
+	}
 	r, err := cmd.helmc.ReleaseHistory(cmd.rls, helm.WithMaxHistory(cmd.max))
 	if err != nil {
 		return prettyError(err)
@@ -132,6 +144,49 @@ func (cmd *historyCmd) run() error {
 	return nil
 }
 
+func (cmd *historyCmd) runAudit() error {
+	r, err := cmd.helmc.GetAuditLog(cmd.rls, helm.AuditMax(cmd.max))
+	if err != nil {
+		return prettyError(err)
+	}
+	if len(r.Entries) == 0 {
+		return nil
+	}
+
+	var out []byte
+	switch cmd.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(r.Entries)
+	case "json":
+		out, err = json.Marshal(r.Entries)
+	case "table":
+		out = formatAuditTable(r.Entries, cmd.colWidth)
+	default:
+		return fmt.Errorf("unknown output format %q", cmd.outputFormat)
+	}
+	if err != nil {
+		return prettyError(err)
+	}
+
+	fmt.Fprintln(cmd.out, string(out))
+	return nil
+}
+
+func formatAuditTable(entries []*services.AuditEntry, colWidth uint) []byte {
+	tbl := uitable.New()
+
+	tbl.MaxColWidth = colWidth
+	tbl.AddRow("TIME", "OPERATION", "REVISION", "USER", "CHART", "VALUES", "OUTCOME", "ERROR")
+	for _, e := range entries {
+		digest := strings.TrimPrefix(e.ValuesDigest, "sha256:")
+		if len(digest) > 12 {
+			digest = digest[:12]
+		}
+		tbl.AddRow(e.Time, e.Operation, e.Revision, e.User, e.Chart, digest, e.Outcome, e.Error)
+	}
+	return tbl.Bytes()
+}
+
 func getReleaseHistory(rls []*release.Release) (history releaseHistory) {
 	for i := len(rls) - 1; i >= 0; i-- {
 		r := rls[i]
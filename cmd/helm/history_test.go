@@ -77,6 +77,26 @@ func TestHistoryCmd(t *testing.T) {
 			},
 			expected: `[{"revision":3,"updated":".*","status":"SUPERSEDED","chart":"foo\-0.1.0-beta.1","description":"Release mock"},{"revision":4,"updated":".*","status":"DEPLOYED","chart":"foo\-0.1.0-beta.1","description":"Release mock"}]\n`,
 		},
+		{
+			name:  "get the audit log of a release",
+			args:  []string{"angry-bird"},
+			flags: []string{"--audit"},
+			rels: []*rpb.Release{
+				mk("angry-bird", 1, rpb.Status_SUPERSEDED),
+				mk("angry-bird", 2, rpb.Status_FAILED),
+			},
+			expected: `TIME\s*\tOPERATION\tREVISION\tUSER\tCHART\s*\tVALUES\tOUTCOME\tERROR\s*\n2016-01-16T00:00:00Z\tinstall\s*\t1\s*\t\s*\tfoo-0.1.0-beta.1\t\s*\tsuccess\s*\t\s*\n2016-01-16T00:00:00Z\tupgrade\s*\t2\s*\t\s*\tfoo-0.1.0-beta.1\t\s*\tfailure\s*\tRelease mock`,
+		},
+		{
+			name:  "get the audit log of a release with max limit set",
+			args:  []string{"angry-bird"},
+			flags: []string{"--audit", "--max", "1", "--output", "json"},
+			rels: []*rpb.Release{
+				mk("angry-bird", 1, rpb.Status_SUPERSEDED),
+				mk("angry-bird", 2, rpb.Status_DEPLOYED),
+			},
+			expected: `^\[{"time":"2016-01-16T00:00:00Z","operation":"upgrade","release":"angry-bird","namespace":"default","revision":2,"chart":"foo-0.1.0-beta.1","outcome":"success"}\]\n$`,
+		},
 	}
 
 	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
@@ -54,6 +54,13 @@ frequently working on the latest code, the flag '--canary-image' will install
 the latest pre-release version of Tiller (e.g. the HEAD commit in the GitHub
 repository on the master branch).
 
+To secure the connection between Helm and Tiller with mutual TLS, use
+'--tls-auto'. It generates a CA and the certificates of Tiller and of the
+client in $HELM_HOME, and installs Tiller with them. From then on, commands
+connecting to Tiller verify it with TLS without further flags. Running it
+again reuses the certificates. An existing Tiller must be removed with
+'helm reset' first, as '--upgrade' does not change its TLS configuration.
+
 To dump a manifest containing the Tiller deployment YAML, combine the
 '--dry-run' and '--debug' flags.
 `
@@ -89,6 +96,7 @@ type initCmd struct {
 	maxHistory     int
 	replicas       int
 	wait           bool
+	tlsAuto        bool
 }
 
 func newInitCmd(out io.Writer) *cobra.Command {
@@ -130,6 +138,7 @@ func newInitCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&tlsCertFile, "tiller-tls-cert", "", "Path to TLS certificate file to install with Tiller")
 	f.StringVar(&tlsCaCertFile, "tls-ca-cert", "", "Path to CA root certificate")
 	f.StringVar(&tlsServerName, "tiller-tls-hostname", settings.TillerHost, "The server name used to verify the hostname on the returned certificates from Tiller")
+	f.BoolVar(&i.tlsAuto, "tls-auto", false, "Generate TLS certificates in $HELM_HOME and install Tiller with TLS enabled and verifying remote certificates")
 
 	f.StringVar(&stableRepositoryURL, "stable-repo-url", stableRepositoryURL, "URL for stable repository")
 	f.StringVar(&localRepositoryURL, "local-repo-url", localRepositoryURL, "URL for local repository")
@@ -150,6 +159,19 @@ func newInitCmd(out io.Writer) *cobra.Command {
 // tlsOptions sanitizes the tls flags as well as checks for the existence of required
 // tls files indicated by those flags, if any.
 func (i *initCmd) tlsOptions() error {
+	if i.tlsAuto {
+		if tlsKeyFile != "" || tlsCertFile != "" || tlsCaCertFile != "" {
+			return errors.New("--tls-auto cannot be used with --tiller-tls-key, --tiller-tls-cert or --tls-ca-cert")
+		}
+		if err := provisionTLS(i.home, i.namespace); err != nil {
+			return fmt.Errorf("could not generate TLS certificates: %s", err)
+		}
+		tlsEnable, tlsVerify = true, true
+		tlsKeyFile = i.home.TLS(tlsAutoServerKey)
+		tlsCertFile = i.home.TLS(tlsAutoServerCert)
+		tlsCaCertFile = i.home.TLSCaCert()
+	}
+
 	i.opts.EnableTLS = tlsEnable || tlsVerify
 	i.opts.VerifyTLS = tlsVerify
 
@@ -177,12 +199,21 @@ func (i *initCmd) tlsOptions() error {
 		settings.TLSCaCertFile = tlsCaCertFile
 		settings.TLSCertFile = tlsCertFile
 		settings.TLSKeyFile = tlsKeyFile
+		if i.tlsAuto {
+			// Tiller only accepts the client certificate from Helm.
+			settings.TLSCertFile = i.home.TLSCert()
+			settings.TLSKeyFile = i.home.TLSKey()
+		}
 	}
 	return nil
 }
 
 // run initializes local config and installs Tiller to Kubernetes cluster.
 func (i *initCmd) run() error {
+	// There is no Tiller to install when releases are managed by the client.
+	if settings.ClientOnly {
+		i.clientOnly = true
+	}
 	if err := i.tlsOptions(); err != nil {
 		return err
 	}
@@ -27,6 +27,7 @@ import (
 	"testing"
 
 	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
@@ -39,6 +40,7 @@ import (
 
 	"k8s.io/helm/cmd/helm/installer"
 	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/tlsutil"
 )
 
 func TestInitCmd(t *testing.T) {
@@ -261,6 +263,106 @@ func TestInitCmd_tlsOptions(t *testing.T) {
 	}
 }
 
+func TestInitCmd_tlsAuto(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm_home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	defer func() {
+		tlsCaCertFile, tlsCertFile, tlsKeyFile = "", "", ""
+		tlsEnable, tlsVerify = false, false
+	}()
+	hh := helmpath.Home(home)
+
+	cmd := &initCmd{home: hh, namespace: v1.NamespaceDefault, tlsAuto: true}
+	if err := cmd.tlsOptions(); err != nil {
+		t.Fatal(err)
+	}
+	expect := installer.Options{
+		TLSCaCertFile: hh.TLSCaCert(),
+		TLSCertFile:   hh.TLS(tlsAutoServerCert),
+		TLSKeyFile:    hh.TLS(tlsAutoServerKey),
+		VerifyTLS:     true,
+		EnableTLS:     true,
+	}
+	if !reflect.DeepEqual(cmd.opts, expect) {
+		t.Errorf("got %#+v, want %#+v", cmd.opts, expect)
+	}
+	if settings.TLSCertFile != hh.TLSCert() || settings.TLSKeyFile != hh.TLSKey() {
+		t.Errorf("expected the client to use %s and %s, got %s and %s", hh.TLSCert(), hh.TLSKey(), settings.TLSCertFile, settings.TLSKeyFile)
+	}
+	if _, err := tlsutil.ClientConfig(tlsutil.Options{CaCertFile: hh.TLSCaCert(), CertFile: hh.TLSCert(), KeyFile: hh.TLSKey()}); err != nil {
+		t.Errorf("invalid client certificates: %s", err)
+	}
+	ca, err := ioutil.ReadFile(hh.TLSCaCert())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the certificates are reused
+	tlsCaCertFile, tlsCertFile, tlsKeyFile = "", "", ""
+	cmd = &initCmd{home: hh, namespace: v1.NamespaceDefault, tlsAuto: true}
+	if err := cmd.tlsOptions(); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := ioutil.ReadFile(hh.TLSCaCert()); !bytes.Equal(ca, again) {
+		t.Error("expected the CA certificate to be reused")
+	}
+
+	// certificates of the user are not overwritten
+	other := helmpath.Home(filepath.Join(home, "other"))
+	if err := os.MkdirAll(other.String(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(other.TLSCaCert(), []byte("ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tlsCaCertFile, tlsCertFile, tlsKeyFile = "", "", ""
+	cmd = &initCmd{home: other, namespace: v1.NamespaceDefault, tlsAuto: true}
+	if err := cmd.tlsOptions(); err == nil {
+		t.Error("expected an error when ca.pem already exists")
+	}
+}
+
+func TestEnableAutoTLS(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm_home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	hh := helmpath.Home(home)
+	defer func() {
+		settings.TLSEnable, settings.TLSVerify = false, false
+	}()
+
+	newFlags := func(args ...string) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		settings.AddFlagsTLS(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return fs
+	}
+
+	enableAutoTLS(newFlags(), hh)
+	if settings.TLSEnable || settings.TLSVerify {
+		t.Error("expected TLS to stay disabled without generated certificates")
+	}
+
+	if err := provisionTLS(hh, v1.NamespaceDefault); err != nil {
+		t.Fatal(err)
+	}
+	enableAutoTLS(newFlags("--tls=false"), hh)
+	if settings.TLSEnable || settings.TLSVerify {
+		t.Error("expected --tls=false to disable TLS")
+	}
+	enableAutoTLS(newFlags(), hh)
+	if !settings.TLSEnable || !settings.TLSVerify {
+		t.Error("expected TLS to be enabled with generated certificates")
+	}
+}
+
 // TestInitCmd_output tests that init -o can be decoded
 func TestInitCmd_output(t *testing.T) {
 	// This is purely defensive in this case.
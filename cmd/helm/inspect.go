@@ -17,6 +17,8 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ghodss/yaml"
 	"github.com/golang/protobuf/ptypes/any"
@@ -24,7 +26,10 @@ import (
 	"io"
 	"strings"
 
+	"k8s.io/client-go/util/jsonpath"
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/strvals"
 )
 
 const (
@@ -37,14 +42,59 @@ Inspect prints the contents of the Chart.yaml file and the values.yaml file.
 	inspectValuesDesc = `
 This command inspects a chart (directory, file, or URL) and displays the contents
 of the values.yaml file
+
+With '--output json', the values are printed as a JSON document. With '--flatten',
+every leaf value is printed on its own line as 'path.to.key=value', in the form
+taken by '--set':
+
+    $ helm inspect values stable/mariadb --flatten | grep image
+    image.pullPolicy=IfNotPresent
+    image.registry=docker.io
+    image.repository=bitnami/mariadb
+    image.tag=10.1.38
+
+With '--jsonpath', only the values matched by a JSONPath expression are printed.
+A plain path such as 'image.tag' is read as '{.image.tag}'. Scalars are printed
+as they are, tables and lists in the format given by '--output':
+
+    $ helm inspect values stable/mariadb --jsonpath image.tag
+    10.1.38
+
+With '--output markdown', the values are printed as a Markdown table of their
+paths, types, defaults and descriptions, taken from the comments above each
+value in values.yaml, so that the table can be added to the chart's README.
 `
 	inspectChartDesc = `
 This command inspects a chart (directory, file, or URL) and displays the contents
 of the Charts.yaml file
+
+The dependencies of the chart are printed in the 'dependencies' block, whether
+they are declared in Chart.yaml or in requirements.yaml.
 `
 	readmeChartDesc = `
 This command inspects a chart (directory, file, or URL) and displays the contents
 of the README file
+`
+	apisChartDesc = `
+This command renders a chart (directory, file, or URL) with its default values
+and lists the resources using an apiVersion that Kubernetes deprecates, with the
+apiVersion to use instead.
+`
+	imagesChartDesc = `
+This command renders a chart (directory, file, or URL) with the given values and
+lists the container images its manifests refer to, so that they can be scanned
+or mirrored before the chart is installed. The containers and init containers
+of every pod template are looked at, including those of the job templates of
+CronJobs and of custom resources embedding pod templates.
+
+By default, every image is printed once on its own line:
+
+    $ helm inspect images stable/mariadb --set metrics.enabled=true
+    docker.io/bitnami/mariadb:10.1.38
+    prom/mysqld-exporter:v0.10.0
+
+With '--output table', the resources and containers using each image are listed
+as well; '--output json' prints them as JSON.
 `
 )
 
@@ -60,6 +110,16 @@ type inspectCmd struct {
 	password  string
 	devel     bool
 
+	valuesFormat string
+	flatten      bool
+	jsonpath     string
+
+	imagesFormat string
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+
 	certFile string
 	keyFile  string
 	caFile   string
@@ -69,6 +129,8 @@ const (
 	chartOnly  = "chart"
 	valuesOnly = "values"
 	readmeOnly = "readme"
+	apisOnly   = "apis"
+	imagesOnly = "images"
 	all        = "all"
 )
 
@@ -81,9 +143,10 @@ func newInspectCmd(out io.Writer) *cobra.Command {
 	}
 
 	inspectCommand := &cobra.Command{
-		Use:   "inspect [CHART]",
-		Short: "Inspect a chart",
-		Long:  inspectDesc,
+		Use:     "inspect [CHART]",
+		Aliases: []string{"show"},
+		Short:   "Inspect a chart",
+		Long:    inspectDesc,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := checkArgsLength(len(args), "chart name"); err != nil {
 				return err
@@ -104,6 +167,12 @@ func newInspectCmd(out io.Writer) *cobra.Command {
 			if err := checkArgsLength(len(args), "chart name"); err != nil {
 				return err
 			}
+			if insp.flatten && insp.valuesFormat != "yaml" {
+				return fmt.Errorf("--flatten cannot be used with --output %s", insp.valuesFormat)
+			}
+			if insp.jsonpath != "" && (insp.flatten || insp.valuesFormat == "markdown") {
+				return errors.New("--jsonpath cannot be used with --flatten or --output markdown")
+			}
 			if err := insp.prepare(args[0]); err != nil {
 				return err
 			}
@@ -143,7 +212,39 @@ func newInspectCmd(out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmds := []*cobra.Command{inspectCommand, readmeSubCmd, valuesSubCmd, chartSubCmd}
+	apisSubCmd := &cobra.Command{
+		Use:   "apis [CHART]",
+		Short: "shows deprecated apiVersions used by the chart",
+		Long:  apisChartDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			insp.output = apisOnly
+			if err := checkArgsLength(len(args), "chart name"); err != nil {
+				return err
+			}
+			if err := insp.prepare(args[0]); err != nil {
+				return err
+			}
+			return insp.run()
+		},
+	}
+
+	imagesSubCmd := &cobra.Command{
+		Use:   "images [CHART]",
+		Short: "shows the container images used by the chart",
+		Long:  imagesChartDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			insp.output = imagesOnly
+			if err := checkArgsLength(len(args), "chart name"); err != nil {
+				return err
+			}
+			if err := insp.prepare(args[0]); err != nil {
+				return err
+			}
+			return insp.run()
+		},
+	}
+
+	cmds := []*cobra.Command{inspectCommand, readmeSubCmd, valuesSubCmd, chartSubCmd, apisSubCmd, imagesSubCmd}
 	vflag := "verify"
 	vdesc := "Verify the provenance data for this chart"
 	for _, subCmd := range cmds {
@@ -174,12 +275,16 @@ func newInspectCmd(out io.Writer) *cobra.Command {
 	inspectCommand.Flags().StringVar(&insp.username, username, "", usernamedesc)
 	valuesSubCmd.Flags().StringVar(&insp.username, username, "", usernamedesc)
 	chartSubCmd.Flags().StringVar(&insp.username, username, "", usernamedesc)
+	apisSubCmd.Flags().StringVar(&insp.username, username, "", usernamedesc)
+	imagesSubCmd.Flags().StringVar(&insp.username, username, "", usernamedesc)
 
 	password := "password"
 	passworddesc := "Chart repository password where to locate the requested chart"
 	inspectCommand.Flags().StringVar(&insp.password, password, "", passworddesc)
 	valuesSubCmd.Flags().StringVar(&insp.password, password, "", passworddesc)
 	chartSubCmd.Flags().StringVar(&insp.password, password, "", passworddesc)
+	apisSubCmd.Flags().StringVar(&insp.password, password, "", passworddesc)
+	imagesSubCmd.Flags().StringVar(&insp.password, password, "", passworddesc)
 
 	develFlag := "devel"
 	develDesc := "Use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored."
@@ -205,6 +310,17 @@ func newInspectCmd(out io.Writer) *cobra.Command {
 		subCmd.Flags().StringVar(&insp.caFile, caFile, "", caFiledesc)
 	}
 
+	valuesSubCmd.Flags().StringVarP(&insp.valuesFormat, "output", "o", "yaml", "Output the values in the specified format (json, yaml or markdown)")
+	valuesSubCmd.Flags().StringVar(&insp.valuesFormat, "format", "yaml", "Alias for --output")
+	valuesSubCmd.Flags().BoolVar(&insp.flatten, "flatten", false, "Print every value on its own line as path.to.key=value")
+	valuesSubCmd.Flags().StringVar(&insp.jsonpath, "jsonpath", "", "Print only the values matched by a JSONPath expression, such as '{.image.tag}' or image.tag")
+
+	imagesSubCmd.Flags().StringVarP(&insp.imagesFormat, "output", "o", "text", "Output the images in the specified format (text, table or json)")
+	imagesSubCmd.Flags().VarP(&insp.valueFiles, "values", "f", "Specify values in a YAML file or a URL(can specify multiple)")
+	imagesSubCmd.Flags().StringArrayVar(&insp.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	imagesSubCmd.Flags().StringArrayVar(&insp.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	imagesSubCmd.Flags().StringArrayVar(&insp.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+
 	for _, subCmd := range cmds[1:] {
 		inspectCommand.AddCommand(subCmd)
 	}
@@ -214,7 +330,7 @@ func newInspectCmd(out io.Writer) *cobra.Command {
 
 func (i *inspectCmd) prepare(chart string) error {
 	debug("Original chart version: %q", i.version)
-	if i.version == "" && i.devel {
+	if i.version == "" && i.devel && !pinnedChartReference(chart) {
 		debug("setting version to >0.0.0-0")
 		i.version = ">0.0.0-0"
 	}
@@ -233,7 +349,13 @@ func (i *inspectCmd) run() error {
 	if err != nil {
 		return err
 	}
-	cf, err := yaml.Marshal(chrt.Metadata)
+	if i.output == apisOnly {
+		return i.showAPIs(chrt)
+	}
+	if i.output == imagesOnly {
+		return i.showImages(chrt)
+	}
+	cf, err := chartfileYAML(chrt)
 	if err != nil {
 		return err
 	}
@@ -246,7 +368,9 @@ func (i *inspectCmd) run() error {
 		if i.output == all {
 			fmt.Fprintln(i.out, "---")
 		}
-		fmt.Fprintln(i.out, chrt.Values.Raw)
+		if err := i.showValues(chrt.Values.Raw); err != nil {
+			return err
+		}
 	}
 
 	if i.output == readmeOnly || i.output == all {
@@ -262,6 +386,164 @@ func (i *inspectCmd) run() error {
 	return nil
 }
 
+// chartfileYAML returns the metadata of a chart as YAML, along with its
+// dependencies, whether they are declared in Chart.yaml or requirements.yaml.
+func chartfileYAML(c *chart.Chart) ([]byte, error) {
+	reqs, err := chartutil.LoadRequirements(c)
+	if err != nil || len(reqs.Dependencies) == 0 {
+		return yaml.Marshal(c.Metadata)
+	}
+	return yaml.Marshal(struct {
+		*chart.Metadata
+		Dependencies []*chartutil.Dependency `json:"dependencies"`
+	}{c.Metadata, reqs.Dependencies})
+}
+
+// showValues prints the values of the chart. They are kept as they are written
+// in values.yaml, comments included, unless another format is asked for.
+func (i *inspectCmd) showValues(raw string) error {
+	if i.valuesFormat == "markdown" {
+		return i.showValuesDoc(raw)
+	}
+	if !i.flatten && i.jsonpath == "" && (i.valuesFormat == "" || i.valuesFormat == "yaml") {
+		fmt.Fprintln(i.out, raw)
+		return nil
+	}
+	vals, err := chartutil.ReadValues([]byte(raw))
+	if err != nil {
+		return err
+	}
+	if i.jsonpath != "" {
+		return i.showValuesPath(vals)
+	}
+	if i.flatten {
+		for _, line := range strvals.Flatten(vals) {
+			fmt.Fprintln(i.out, line)
+		}
+		return nil
+	}
+	out, err := formatValues(i.valuesFormat, vals)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(i.out, out)
+	return nil
+}
+
+// showValuesPath prints the values matched by the --jsonpath expression, one
+// per line.
+func (i *inspectCmd) showValuesPath(vals chartutil.Values) error {
+	expr := i.jsonpath
+	if !strings.HasPrefix(expr, "{") {
+		if !strings.HasPrefix(expr, ".") {
+			expr = "." + expr
+		}
+		expr = "{" + expr + "}"
+	}
+	j := jsonpath.New("values")
+	if err := j.Parse(expr); err != nil {
+		return fmt.Errorf("invalid --jsonpath %q: %s", i.jsonpath, err)
+	}
+	results, err := j.FindResults(map[string]interface{}(vals))
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		for _, r := range result {
+			v := r.Interface()
+			switch v := v.(type) {
+			case map[string]interface{}:
+				out, err := formatValues(i.valuesFormat, v)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(i.out, strings.TrimSuffix(out, "\n"))
+			case []interface{}:
+				out, err := formatList(i.valuesFormat, v)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(i.out, out)
+			case nil:
+				fmt.Fprintln(i.out, "null")
+			default:
+				fmt.Fprintln(i.out, v)
+			}
+		}
+	}
+	return nil
+}
+
+// formatList formats a list matched by --jsonpath like formatValues formats
+// a table.
+func formatList(format string, list []interface{}) (string, error) {
+	var (
+		out []byte
+		err error
+	)
+	if format == "json" {
+		out, err = json.Marshal(list)
+	} else {
+		out, err = yaml.Marshal(list)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// showValuesDoc prints the documented values of the chart as a Markdown table.
+func (i *inspectCmd) showValuesDoc(raw string) error {
+	docs, err := chartutil.DocumentValues(raw)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(i.out, "| Key | Type | Default | Description |")
+	fmt.Fprintln(i.out, "|-----|------|---------|-------------|")
+	for _, d := range docs {
+		def, err := json.Marshal(d.Default)
+		if err != nil {
+			return err
+		}
+		desc := d.Description
+		if len(d.Enum) > 0 {
+			desc = strings.TrimSpace(desc + " One of: " + strings.Join(d.Enum, ", ") + ".")
+		}
+		if d.Required {
+			desc = strings.TrimSpace(desc + " Required.")
+		}
+		fmt.Fprintf(i.out, "| %s | %s | `%s` | %s |\n",
+			markdownCell(d.Path), valueType(d.Default), markdownCell(string(def)), markdownCell(desc))
+	}
+	return nil
+}
+
+// valueType names the type of a default value in the values documentation.
+func valueType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "int"
+		}
+		return "float"
+	case int, int64:
+		return "int"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// markdownCell escapes the pipes of a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.Replace(s, "|", "\\|", -1)
+}
+
 func findReadme(files []*any.Any) (file *any.Any) {
 	for _, file := range files {
 		if containsString(readmeFileNames, strings.ToLower(file.TypeUrl), nil) {
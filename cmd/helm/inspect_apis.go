@@ -0,0 +1,86 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kubeschema"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// showAPIs renders the chart with its default values and lists the resources
+// using deprecated apiVersions.
+func (i *inspectCmd) showAPIs(chrt *chart.Chart) error {
+	renderOpts := renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      "release-name",
+			IsInstall: true,
+			Revision:  1,
+			Time:      timeconv.Now(),
+			Namespace: defaultNamespace(),
+		},
+	}
+	rendered, err := renderutil.Render(chrt, &chart.Config{Raw: "{}"}, renderOpts)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	table := uitable.New()
+	table.AddRow("SOURCE", "KIND", "NAME", "API VERSION", "DEPRECATED", "REMOVED", "REPLACEMENT")
+	found := 0
+	for _, source := range names {
+		docs := releaseutil.SplitManifests(rendered[source])
+		for d := 0; d < len(docs); d++ {
+			var head releaseutil.SimpleHead
+			if err := yaml.Unmarshal([]byte(docs[fmt.Sprintf("manifest-%d", d)]), &head); err != nil {
+				continue
+			}
+			l, ok := kubeschema.LookupLifecycle(head.Version, head.Kind)
+			if !ok {
+				continue
+			}
+			name := ""
+			if head.Metadata != nil {
+				name = head.Metadata.Name
+			}
+			table.AddRow(source, head.Kind, name, head.Version, l.Deprecated, l.Removed, l.Replacement)
+			found++
+		}
+	}
+
+	if found == 0 {
+		fmt.Fprintln(i.out, "No deprecated apiVersions found")
+		return nil
+	}
+	fmt.Fprintln(i.out, table)
+	return nil
+}
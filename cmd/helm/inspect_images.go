@@ -0,0 +1,79 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gosuri/uitable"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+// showImages renders the chart with the given values and lists the container
+// images of its manifests.
+func (i *inspectCmd) showImages(chrt *chart.Chart) error {
+	switch i.imagesFormat {
+	case "", "text", "table", "json":
+	default:
+		return fmt.Errorf("unknown output format %q", i.imagesFormat)
+	}
+
+	rawVals, err := vals(i.valueFiles, i.values, i.stringValues, i.fileValues, i.certFile, i.keyFile, i.caFile)
+	if err != nil {
+		return err
+	}
+	refs, err := chartImages(chrt, rawVals)
+	if err != nil {
+		return err
+	}
+
+	switch i.imagesFormat {
+	case "json":
+		if refs == nil {
+			refs = []releaseutil.ImageReference{}
+		}
+		out, err := json.Marshal(refs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(i.out, string(out))
+	case "table":
+		if len(refs) == 0 {
+			fmt.Fprintln(i.out, "No images found")
+			return nil
+		}
+		table := uitable.New()
+		table.MaxColWidth = 80
+		table.AddRow("IMAGE", "KIND", "NAME", "CONTAINER")
+		for _, r := range refs {
+			container := r.Container
+			if r.Init {
+				container += " (init)"
+			}
+			table.AddRow(r.Image, r.Kind, r.Name, container)
+		}
+		fmt.Fprintln(i.out, table)
+	default:
+		for _, image := range releaseutil.UniqueImages(refs) {
+			fmt.Fprintln(i.out, image)
+		}
+	}
+	return nil
+}
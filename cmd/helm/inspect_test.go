@@ -82,6 +82,198 @@ func TestInspect(t *testing.T) {
 	}
 }
 
+func TestInspectChartDependencies(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	insp := &inspectCmd{
+		chartpath: "testdata/testcharts/reqtest",
+		output:    chartOnly,
+		out:       b,
+	}
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"name: reqtest\n", "dependencies:\n", "- name: reqsubchart3\n", ">=0.1.0"} {
+		if !strings.Contains(b.String(), expect) {
+			t.Errorf("expected %q in the chart metadata, got %s", expect, b.String())
+		}
+	}
+}
+
+func TestInspectValuesFormats(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	insp := &inspectCmd{
+		chartpath: "testdata/testcharts/alpine",
+		output:    valuesOnly,
+		flatten:   true,
+		out:       b,
+	}
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "Name=my-alpine\n" {
+		t.Errorf("expected flattened values, got %q", got)
+	}
+
+	b.Reset()
+	insp.flatten = false
+	insp.valuesFormat = "json"
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != `{"Name":"my-alpine"}`+"\n" {
+		t.Errorf("expected JSON values, got %q", got)
+	}
+
+	cmd := newInspectCmd(ioutil.Discard)
+	values, _, err := cmd.Find([]string{"values"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values.ParseFlags([]string{"--flatten", "-o", "json"})
+	if err := values.RunE(values, []string{"testdata/testcharts/alpine"}); err == nil {
+		t.Error("expected --flatten with --output json to fail")
+	}
+}
+
+func TestInspectValuesPath(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	insp := &inspectCmd{
+		chartpath: "testdata/testcharts/alpine",
+		output:    valuesOnly,
+		out:       b,
+	}
+	for _, expr := range []string{"Name", ".Name", "{.Name}"} {
+		b.Reset()
+		insp.jsonpath = expr
+		if err := insp.run(); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != "my-alpine\n" {
+			t.Errorf("expected %q to match the name, got %q", expr, got)
+		}
+	}
+
+	insp.jsonpath = "missing"
+	if err := insp.run(); err == nil {
+		t.Error("expected a missing value to fail")
+	}
+
+	cmd := newInspectCmd(ioutil.Discard)
+	values, _, err := cmd.Find([]string{"values"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values.ParseFlags([]string{"--jsonpath", "Name", "--flatten"})
+	if err := values.RunE(values, []string{"testdata/testcharts/alpine"}); err == nil {
+		t.Error("expected --jsonpath with --flatten to fail")
+	}
+}
+
+func TestInspectValuesMarkdown(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	insp := &inspectCmd{
+		chartpath:    "testdata/testcharts/alpine",
+		output:       valuesOnly,
+		valuesFormat: "markdown",
+		out:          b,
+	}
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	expect := "| Key | Type | Default | Description |\n" +
+		"|-----|------|---------|-------------|\n" +
+		"| Name | string | `\"my-alpine\"` | The pod name |\n"
+	if got := b.String(); got != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, got)
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if got := markdownCell("a|b"); got != `a\|b` {
+		t.Errorf("expected the pipe to be escaped, got %q", got)
+	}
+	for v, expect := range map[interface{}]string{
+		"x": "string", 1.0: "int", 1.5: "float", true: "bool", nil: "null",
+	} {
+		if got := valueType(v); got != expect {
+			t.Errorf("expected %v to be a %s, got %s", v, expect, got)
+		}
+	}
+}
+
+func TestInspectAPIs(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	insp := &inspectCmd{
+		chartpath: "../../pkg/lint/rules/testdata/outdated",
+		output:    apisOnly,
+		out:       b,
+	}
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two resources, got\n%s", b.String())
+	}
+	for i, expect := range []string{"Deployment", "Ingress"} {
+		fields := strings.Fields(lines[i+1])
+		if fields[1] != expect || fields[3] != "extensions/v1beta1" {
+			t.Errorf("unexpected row %q", lines[i+1])
+		}
+	}
+
+	b.Reset()
+	insp.chartpath = "testdata/testcharts/novals"
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(b.String()) != "No deprecated apiVersions found" {
+		t.Errorf("expected no deprecated apiVersions, got %q", b.String())
+	}
+}
+
+func TestInspectImages(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	insp := &inspectCmd{
+		chartpath: "testdata/testcharts/alpine",
+		output:    imagesOnly,
+		values:    []string{"test.Name=images"},
+		out:       b,
+	}
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "alpine:3.3\n" {
+		t.Errorf("expected the image of the chart, got %q", got)
+	}
+
+	b.Reset()
+	insp.imagesFormat = "json"
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	expect := `[{"image":"alpine:3.3","kind":"Pod","name":"release-name-my-alpine","container":"waiter"}]`
+	if got := strings.TrimSpace(b.String()); got != expect {
+		t.Errorf("expected %s, got %s", expect, got)
+	}
+
+	b.Reset()
+	insp.imagesFormat = "table"
+	insp.values = []string{"Name=other", "test.Name=images"}
+	if err := insp.run(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[1]), " ") != "alpine:3.3 Pod release-name-other waiter" {
+		t.Errorf("expected a header and the pod, got\n%s", b.String())
+	}
+
+	insp.imagesFormat = "yaml"
+	if err := insp.run(); err == nil {
+		t.Error("expected an unknown output format to fail")
+	}
+}
+
 func TestInspectPreReleaseChart(t *testing.T) {
 	hh, err := tempHelmHome(t)
 	if err != nil {
@@ -18,29 +18,28 @@ package main
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/helm/pkg/action"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/downloader"
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm"
-	"k8s.io/helm/pkg/kube"
+	helm_env "k8s.io/helm/pkg/helm/environment"
 	"k8s.io/helm/pkg/proto/hapi/release"
-	"k8s.io/helm/pkg/renderutil"
-	"k8s.io/helm/pkg/repo"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 	"k8s.io/helm/pkg/strvals"
 )
 
@@ -86,9 +85,28 @@ To check the generated manifests of a release without installing the chart,
 the '--debug' and '--dry-run' flags can be combined. This will still require a
 round-trip to the Tiller server.
 
+With '--interactive', helm asks on the terminal for the values of the chart
+that are not set yet, from the comments of its values.yaml: first the values
+marked '@required', then, if you want, the other documented values. The answers
+are saved to '--save-values' (CHART-values.yaml by default) to be passed with
+'-f' next time.
+
 If --verify is set, the chart MUST have a provenance file, and the provenance
 file MUST pass all verification steps.
 
+Use '--create-namespace' to have the namespace of the release created when it
+does not exist; Tillers configured with 'requireCreateNamespace' refuse to
+install into missing namespaces otherwise. Resources of the chart that already
+exist in the cluster make the install fail, unless '--take-ownership' is set:
+they are then updated to match the chart and annotated as belonging to the
+release.
+
+The custom resource definitions in the 'crds/' directory of the chart and of
+its dependencies are installed first, and Tiller waits for them to be
+established before rendering the templates that use them. Definitions that
+already exist are left as they are, and upgrades and deletions of the release
+never change them. Use '--skip-crds' to not install them.
+
 There are five different ways you can express the chart you want to install:
 
 1. By chart reference: helm install stable/mariadb
@@ -107,6 +125,11 @@ chart in that repository whose name is 'mariadb'. It will install the latest
 version of that chart unless you also supply a version number with the
 '--version' flag.
 
+The version can also be pinned in the chart reference itself ('stable/mariadb@5.1.0'),
+as can the SHA256 digest of the chart archive listed in the repository index
+('stable/mariadb@sha256:<digest>'). A chart pinned to a digest is only installed if the
+downloaded archive matches the digest.
+
 To see the list of chart repositories, use 'helm repo list'. To search for
 charts in a repository, use 'helm search'.
 `
@@ -130,15 +153,36 @@ type installCmd struct {
 	nameTemplate   string
 	version        string
 	timeout        int64
+	hookTimeout    int64
 	wait           bool
 	atomic         bool
+	cleanupOnFail  bool
 	repoURL        string
 	username       string
 	password       string
 	devel          bool
 	depUp          bool
 	subNotes       bool
+	strict         bool
 	description    string
+	overrideWindow string
+	serviceAccount string
+	createNs       bool
+	takeOwnership  bool
+	skipCRDs       bool
+	sortOrderFile  string
+	waitTimeouts   waitTimeouts
+	waitForJobs    bool
+	waitConditions waitConditions
+	labels         labelsFlag
+	output         string
+	quiet          bool
+	interactive    bool
+	saveValues     string
+
+	// in is where --interactive reads the answers, if inTerminal.
+	in         io.Reader
+	inTerminal bool
 
 	certFile string
 	keyFile  string
@@ -164,22 +208,24 @@ func (v *valueFiles) Set(value string) error {
 
 func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	inst := &installCmd{
-		out:    out,
-		client: c,
+		out:        out,
+		client:     c,
+		in:         os.Stdin,
+		inTerminal: terminal.IsTerminal(int(os.Stdin.Fd())),
 	}
 
 	cmd := &cobra.Command{
 		Use:     "install [CHART]",
 		Short:   "Install a chart archive",
 		Long:    installDesc,
-		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnectionIn(inst.namespace) },
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := checkArgsLength(len(args), "chart name"); err != nil {
 				return err
 			}
 
 			debug("Original chart version: %q", inst.version)
-			if inst.version == "" && inst.devel {
+			if inst.version == "" && inst.devel && !pinnedChartReference(args[0]) {
 				debug("setting version to >0.0.0-0")
 				inst.version = ">0.0.0-0"
 			}
@@ -213,9 +259,17 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&inst.verify, "verify", false, "Verify the package before installing it")
 	f.StringVar(&inst.keyring, "keyring", defaultKeyring(), "Location of public keys used for verification")
 	f.StringVar(&inst.version, "version", "", "Specify the exact chart version to install. If this is not specified, the latest version is installed")
-	f.Int64Var(&inst.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	helm_env.TimeoutVar(f, &inst.timeout, "timeout", 300, "Time to wait for any individual Kubernetes operation (like Jobs for hooks), as a duration such as 5m30s or in seconds")
+	helm_env.TimeoutVar(f, &inst.hookTimeout, "hook-timeout", 0, "Time hooks may run, as a duration such as 10m or in seconds. Defaults to --timeout")
 	f.BoolVar(&inst.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 	f.BoolVar(&inst.atomic, "atomic", false, "If set, installation process purges chart on fail, also sets --wait flag")
+	f.BoolVar(&inst.cleanupOnFail, "cleanup-on-fail", false, "Allow deletion of new resources created in this installation when installation failed")
+	f.Var(&inst.labels, "labels", "Labels of the release, also set on its resources as release-label.helm.sh/KEY (can specify multiple or separate values with commas: team=web,env=prod)")
+	f.Var(&inst.waitTimeouts, "wait-timeout", "Override --timeout for resources of a kind while waiting, as KIND=DURATION (can specify multiple or separate values with commas: Deployment=10m,Service=60s)")
+	f.BoolVar(&inst.waitForJobs, "wait-for-jobs", false, "If set with --wait, will also wait until all Jobs have completed. A failed Job fails the wait")
+	f.Var(&inst.waitConditions, "wait-condition", "Wait for resources of a kind until a field of theirs has a value, as KIND=PATH==VALUE, such as 'Certificate=status.conditions[?(@.type==\"Ready\")].status==True' (can specify multiple)")
+	f.StringVar(&inst.output, "output", "", "Output the readiness reports and status in the specified format (json)")
+	f.BoolVar(&inst.quiet, "quiet", false, "Print only the name of the release on success")
 	f.StringVar(&inst.repoURL, "repo", "", "Chart repository url where to locate the requested chart")
 	f.StringVar(&inst.username, "username", "", "Chart repository username where to locate the requested chart")
 	f.StringVar(&inst.password, "password", "", "Chart repository password where to locate the requested chart")
@@ -225,7 +279,16 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&inst.devel, "devel", false, "Use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored.")
 	f.BoolVar(&inst.depUp, "dep-up", false, "Run helm dependency update before installing the chart")
 	f.BoolVar(&inst.subNotes, "render-subchart-notes", false, "Render subchart notes along with the parent")
+	f.BoolVar(&inst.strict, "strict", false, "Fail rendering on values used by the templates but not set, instead of rendering them empty")
 	f.StringVar(&inst.description, "description", "", "Specify a description for the release")
+	f.StringVar(&inst.overrideWindow, "override-window", "", "Install outside the maintenance windows set by Tiller, giving the reason recorded in its audit log")
+	f.StringVar(&inst.serviceAccount, "service-account-for-apply", "", "Have Tiller apply the release as this service account of the release namespace")
+	f.BoolVar(&inst.createNs, "create-namespace", false, "Create the release namespace if not present")
+	f.BoolVar(&inst.takeOwnership, "take-ownership", false, "Adopt the resources of the chart that already exist, instead of failing")
+	f.BoolVar(&inst.skipCRDs, "skip-crds", false, "Do not install the custom resource definitions of the crds directory of the chart")
+	f.StringVar(&inst.sortOrderFile, "sort-order-file", "", "YAML file listing the kinds of resources in the order to install and uninstall them in")
+	f.BoolVar(&inst.interactive, "interactive", false, "Ask for the values of the chart that are not set, from the documentation of its values.yaml")
+	f.StringVar(&inst.saveValues, "save-values", "", "File to save the values given with --interactive to. Defaults to CHART-values.yaml")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -240,6 +303,10 @@ func (i *installCmd) run() error {
 		i.namespace = defaultNamespace()
 	}
 
+	if i.output != "" && i.output != "json" {
+		return fmt.Errorf("unknown output format %q", i.output)
+	}
+
 	rawVals, err := vals(i.valueFiles, i.values, i.stringValues, i.fileValues, i.certFile, i.keyFile, i.caFile)
 	if err != nil {
 		return err
@@ -252,51 +319,39 @@ func (i *installCmd) run() error {
 			return err
 		}
 		// Print the final name so the user knows what the final name of the release is.
-		fmt.Printf("FINAL NAME: %s\n", i.name)
+		printMessage(i.out, i.quiet, msgFinalName, i.name)
 	}
 
 	if msgs := validation.IsDNS1123Subdomain(i.name); i.name != "" && len(msgs) > 0 {
 		return fmt.Errorf("release name %s is invalid: %s", i.name, strings.Join(msgs, ";"))
 	}
 
+	installOrder, uninstallOrder, err := readSortOrderFile(i.sortOrderFile)
+	if err != nil {
+		return err
+	}
+
 	// Check chart requirements to make sure all dependencies are present in /charts
-	chartRequested, err := chartutil.Load(i.chartPath)
+	chartRequested, err := newActionConfig(i.out).LoadChart(i.chartPath, i.depUp, defaultKeyring())
 	if err != nil {
 		return prettyError(err)
 	}
 
-	if req, err := chartutil.LoadRequirements(chartRequested); err == nil {
-		// If checkDependencies returns an error, we have unfulfilled dependencies.
-		// As of Helm 2.4.0, this is treated as a stopping condition:
-		// https://github.com/kubernetes/helm/issues/2209
-		if err := renderutil.CheckDependencies(chartRequested, req); err != nil {
-			if i.depUp {
-				man := &downloader.Manager{
-					Out:        i.out,
-					ChartPath:  i.chartPath,
-					HelmHome:   settings.Home,
-					Keyring:    defaultKeyring(),
-					SkipUpdate: false,
-					Getters:    getter.All(settings),
-				}
-				if err := man.Update(); err != nil {
-					return prettyError(err)
-				}
-
-				// Update all dependencies which are present in /charts.
-				chartRequested, err = chartutil.Load(i.chartPath)
-				if err != nil {
-					return prettyError(err)
-				}
-			} else {
-				return prettyError(err)
-			}
+	if err := chartutil.IsChartInstallable(chartRequested); err != nil {
+		return err
+	}
 
+	if i.interactive {
+		if rawVals, err = i.askValues(chartRequested, rawVals); err != nil {
+			return err
 		}
-	} else if err != chartutil.ErrRequirementsNotFound {
-		return fmt.Errorf("cannot load requirements: %v", err)
 	}
 
+	// Without a name, the release cannot be watched before Tiller names it.
+	var stream *readinessStream
+	if i.output == "json" && i.wait && i.name != "" && !i.dryRun {
+		stream = streamReadiness(i.out, i.client, i.name)
+	}
 	res, err := i.client.InstallReleaseFromChart(
 		chartRequested,
 		i.namespace,
@@ -307,26 +362,29 @@ func (i *installCmd) run() error {
 		helm.InstallDisableHooks(i.disableHooks),
 		helm.InstallDisableCRDHook(i.disableCRDHook),
 		helm.InstallSubNotes(i.subNotes),
+		helm.InstallStrict(i.strict),
+		helm.InstallServiceAccount(i.serviceAccount),
+		helm.InstallCreateNamespace(i.createNs),
+		helm.InstallTakeOwnership(i.takeOwnership),
+		helm.InstallSkipCRDs(i.skipCRDs),
+		helm.InstallSortOrder(installOrder, uninstallOrder),
 		helm.InstallTimeout(i.timeout),
+		helm.InstallHookTimeout(i.hookTimeout),
 		helm.InstallWait(i.wait),
+		helm.InstallWaitTimeouts(i.waitTimeouts),
+		helm.InstallWaitForJobs(i.waitForJobs),
+		helm.InstallWaitConditions(i.waitConditions),
+		helm.InstallLabels(i.labels),
+		helm.InstallCleanupOnFail(i.cleanupOnFail),
+		helm.InstallOverrideWindow(i.overrideWindow),
 		helm.InstallDescription(i.description))
+	printed := stream.close()
 	if err != nil {
-		if i.atomic {
-			fmt.Fprintf(os.Stdout, "INSTALL FAILED\nPURGING CHART\nError: %v\n", prettyError(err))
-			deleteSideEffects := &deleteCmd{
-				name:         i.name,
-				disableHooks: i.disableHooks,
-				purge:        true,
-				timeout:      i.timeout,
-				description:  "",
-				dryRun:       i.dryRun,
-				out:          i.out,
-				client:       i.client,
-			}
-			if err := deleteSideEffects.run(); err != nil {
+		if i.atomic && !i.dryRun {
+			printMessage(i.out, i.quiet, msgInstallFailed, prettyError(err))
+			if err := i.purge(); err != nil {
 				return err
 			}
-			fmt.Fprintf(os.Stdout, "Successfully purged a chart!\n")
 		}
 		return prettyError(err)
 	}
@@ -335,13 +393,20 @@ func (i *installCmd) run() error {
 	if rel == nil {
 		return nil
 	}
+	if i.output == "json" && !i.dryRun {
+		return printReadinessAndStatus(i.out, i.client, rel, printed)
+	}
+	if i.quiet {
+		fmt.Fprintln(i.out, rel.Name)
+		return nil
+	}
 	i.printRelease(rel)
 
 	// If this is a dry run, we can't display status.
 	if i.dryRun {
 		// This is special casing to avoid breaking backward compatibility:
 		if res.Release.Info.Description != "Dry run complete" {
-			fmt.Fprintf(os.Stdout, "WARNING: %s\n", res.Release.Info.Description)
+			printMessage(i.out, i.quiet, msgInstallWarning, res.Release.Info.Description)
 		}
 		return nil
 	}
@@ -402,6 +467,9 @@ func vals(valueFiles valueFiles, values []string, stringValues []string, fileVal
 		if err != nil {
 			return []byte{}, err
 		}
+		if bytes, err = decryptValues(filePath, bytes); err != nil {
+			return []byte{}, err
+		}
 
 		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
 			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
@@ -438,13 +506,44 @@ func vals(valueFiles valueFiles, values []string, stringValues []string, fileVal
 	return yaml.Marshal(base)
 }
 
+// purge deletes the release after a failed atomic install. A release that
+// failed before it was recorded, for example in a pre-install hook, leaves
+// nothing to purge.
+func (i *installCmd) purge() error {
+	if i.name == "" {
+		printMessage(i.out, i.quiet, msgPurgeManually)
+		return nil
+	}
+	deleteSideEffects := &deleteCmd{
+		name:           i.name,
+		disableHooks:   i.disableHooks,
+		purge:          true,
+		timeout:        i.timeout,
+		hookTimeout:    i.hookTimeout,
+		description:    "",
+		overrideWindow: i.overrideWindow,
+		out:            i.out,
+		client:         i.client,
+		quiet:          i.quiet,
+	}
+	if err := deleteSideEffects.run(); err != nil {
+		if strings.Contains(err.Error(), storageerrors.ErrReleaseNotFound(i.name).Error()) {
+			printMessage(i.out, i.quiet, msgNothingToPurge, i.name)
+			return nil
+		}
+		return err
+	}
+	printMessage(i.out, i.quiet, msgPurged)
+	return nil
+}
+
 // printRelease prints info about a release if the Debug is true.
 func (i *installCmd) printRelease(rel *release.Release) {
 	if rel == nil {
 		return
 	}
 	// TODO: Switch to text/template like everything else.
-	fmt.Fprintf(i.out, "NAME:   %s\n", rel.Name)
+	printMessage(i.out, i.quiet, msgReleaseName, rel.Name)
 	if settings.Debug {
 		printRelease(i.out, rel)
 	}
@@ -453,79 +552,32 @@ func (i *installCmd) printRelease(rel *release.Release) {
 // locateChartPath looks for a chart directory in known places, and returns either the full path or an error.
 //
 // This does not ensure that the chart is well-formed; only that the requested filename exists.
-//
-// Order of resolution:
-// - current working directory
-// - if path is absolute or begins with '.', error out here
-// - chart repos in $HELM_HOME
-// - URL
+// See action.Configuration.LocateChart for the order of resolution.
 //
 // If 'verify' is true, this will attempt to also verify the chart.
 func locateChartPath(repoURL, username, password, name, version string, verify bool, keyring,
 	certFile, keyFile, caFile string) (string, error) {
-	name = strings.TrimSpace(name)
-	version = strings.TrimSpace(version)
-	if fi, err := os.Stat(name); err == nil {
-		abs, err := filepath.Abs(name)
-		if err != nil {
-			return abs, err
-		}
-		if verify {
-			if fi.IsDir() {
-				return "", errors.New("cannot verify a directory")
-			}
-			if _, err := downloader.VerifyChart(abs, keyring); err != nil {
-				return "", err
-			}
-		}
-		return abs, nil
-	}
-	if filepath.IsAbs(name) || strings.HasPrefix(name, ".") {
-		return name, fmt.Errorf("path %q not found", name)
-	}
-
-	crepo := filepath.Join(settings.Home.Repository(), name)
-	if _, err := os.Stat(crepo); err == nil {
-		return filepath.Abs(crepo)
-	}
-
-	dl := downloader.ChartDownloader{
-		HelmHome: settings.Home,
-		Out:      os.Stdout,
-		Keyring:  keyring,
-		Getters:  getter.All(settings),
+	opts := action.ChartPathOptions{
+		Version:  version,
+		RepoURL:  repoURL,
 		Username: username,
 		Password: password,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+		Keyring:  keyring,
 	}
 	if verify {
-		dl.Verify = downloader.VerifyAlways
-	}
-	if repoURL != "" {
-		chartURL, err := repo.FindChartInAuthRepoURL(repoURL, username, password, name, version,
-			certFile, keyFile, caFile, getter.All(settings))
-		if err != nil {
-			return "", err
-		}
-		name = chartURL
-	}
-
-	if _, err := os.Stat(settings.Home.Archive()); os.IsNotExist(err) {
-		os.MkdirAll(settings.Home.Archive(), 0744)
-	}
-
-	filename, _, err := dl.DownloadTo(name, version, settings.Home.Archive())
-	if err == nil {
-		lname, err := filepath.Abs(filename)
-		if err != nil {
-			return filename, err
-		}
-		debug("Fetched %s to %s\n", name, filename)
-		return lname, nil
-	} else if settings.Debug {
-		return filename, err
+		opts.Verify = downloader.VerifyAlways
 	}
+	return newActionConfig(os.Stdout).LocateChart(name, opts)
+}
 
-	return filename, fmt.Errorf("failed to download %q (hint: running `helm repo update` may help)", name)
+// pinnedChartReference returns true if a chart reference is pinned to a
+// version or a digest, in which case --devel does not apply.
+func pinnedChartReference(ref string) bool {
+	_, version, digest, err := downloader.ParseChartReference(ref, "")
+	return err == nil && (version != "" || digest != "")
 }
 
 func generateName(nameTemplate string) (string, error) {
@@ -542,10 +594,23 @@ func generateName(nameTemplate string) (string, error) {
 }
 
 func defaultNamespace() string {
-	if ns, _, err := kube.GetConfig(settings.KubeContext, settings.KubeConfig).Namespace(); err == nil {
-		return ns
+	return action.DefaultNamespace(settings)
+}
+
+// decryptionProviders decrypt the values files with --enable-decryption.
+var decryptionProviders = chartutil.DefaultDecryptionProviders()
+
+// decryptValues decrypts the data of an encrypted values file if decryption
+// is enabled, and refuses the encrypted file otherwise, rather than reading
+// its ciphertext as values.
+func decryptValues(filePath string, data []byte) ([]byte, error) {
+	if settings.EnableDecryption {
+		return decryptionProviders.Decrypt(filePath, data)
+	}
+	if p := decryptionProviders.For(data); p != nil {
+		return nil, fmt.Errorf("%s is encrypted with %s, use --enable-decryption to decrypt it", filePath, p.Name())
 	}
-	return "default"
+	return data, nil
 }
 
 //readFile load a file from the local directory or a remote file with a url.
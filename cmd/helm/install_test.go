@@ -18,12 +18,16 @@ package main
 
 import (
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
 )
 
@@ -37,6 +41,14 @@ func TestInstall(t *testing.T) {
 			expected: "aeneas",
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
 		},
+		// Install, quiet
+		{
+			name:     "quiet install",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name aeneas --quiet", " "),
+			expected: "^aeneas\n$",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "aeneas"}),
+		},
 		// Install, no hooks
 		{
 			name:     "install without hooks",
@@ -105,6 +117,13 @@ func TestInstall(t *testing.T) {
 			expected: "foobar",
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "foobar"}),
 		},
+		{
+			name:     "install with duration timeouts",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name foobar --timeout 5m30s --hook-timeout 15m", " "),
+			expected: "foobar",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "foobar"}),
+		},
 		// Install, with wait
 		{
 			name:     "install with a wait",
@@ -121,6 +140,14 @@ func TestInstall(t *testing.T) {
 			expected: "apollo",
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "apollo"}),
 		},
+		// Install, with cleanup-on-fail
+		{
+			name:     "install with cleanup-on-fail",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name apollo --cleanup-on-fail", " "),
+			expected: "apollo",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "apollo"}),
+		},
 		// Install, using the name-template
 		{
 			name:     "install with name-template",
@@ -327,3 +354,48 @@ func TestMergeValues(t *testing.T) {
 		t.Errorf("Expected a map with different keys to merge properly with another map. Expected: %v, got %v", expectedMap, testMap)
 	}
 }
+
+type fakeDecryptionProvider struct{}
+
+func (fakeDecryptionProvider) Name() string { return "fake" }
+
+func (fakeDecryptionProvider) Encrypted(data []byte) bool {
+	return strings.HasPrefix(string(data), "#fake\n")
+}
+
+func (fakeDecryptionProvider) Decrypt(name string, data []byte) ([]byte, error) {
+	return []byte(strings.Replace(string(data), "ENC", "plain", -1)), nil
+}
+
+func TestValsDecryption(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-vals-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	secrets := filepath.Join(tmp, "secrets.enc.yaml")
+	if err := ioutil.WriteFile(secrets, []byte("#fake\npassword: ENC\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(p chartutil.DecryptionProviders, enabled bool) {
+		decryptionProviders = p
+		settings.EnableDecryption = enabled
+	}(decryptionProviders, settings.EnableDecryption)
+	decryptionProviders = chartutil.DecryptionProviders{fakeDecryptionProvider{}}
+
+	settings.EnableDecryption = false
+	_, err = vals([]string{secrets}, nil, nil, nil, "", "", "")
+	if err == nil || !strings.Contains(err.Error(), "is encrypted with fake, use --enable-decryption to decrypt it") {
+		t.Errorf("Expected encrypted values to be refused, got %v", err)
+	}
+
+	settings.EnableDecryption = true
+	out, err := vals([]string{secrets}, nil, nil, nil, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "password: plain\n"; string(out) != expect {
+		t.Errorf("Expected %q, got %q", expect, out)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// askValues runs the values wizard for --interactive. The answers are saved
+// to be reused, and added to the values of the release.
+func (i *installCmd) askValues(c *chart.Chart, rawVals []byte) ([]byte, error) {
+	if !i.inTerminal {
+		return nil, errors.New("--interactive requires a terminal")
+	}
+	if c.Values == nil {
+		return rawVals, nil
+	}
+	docs, err := chartutil.DocumentValues(c.Values.Raw)
+	if err != nil {
+		return nil, err
+	}
+	given, err := chartutil.ReadValues(rawVals)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &valuesWizard{in: bufio.NewReader(i.in), out: i.out}
+	answers, err := w.run(docs, given)
+	if err != nil {
+		return nil, err
+	}
+	if len(answers) == 0 {
+		return rawVals, nil
+	}
+
+	data, err := answers.YAML()
+	if err != nil {
+		return nil, err
+	}
+	path := i.saveValues
+	if path == "" {
+		path = c.Metadata.Name + "-values.yaml"
+	}
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(i.out, "\nSaved the values to %s, pass '-f %s' to reuse them.\n\n", path, path)
+
+	return yaml.Marshal(mergeValues(answers, given))
+}
+
+// valuesWizard asks on a terminal for the values of a chart, from the
+// documentation in its values.yaml. See chartutil.ValueDoc.
+type valuesWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// run asks for the required values, then offers to go through the other
+// documented values. The values already given are not asked for. It returns
+// the answers that differ from the defaults.
+func (w *valuesWizard) run(docs []*chartutil.ValueDoc, given chartutil.Values) (chartutil.Values, error) {
+	var required, optional []*chartutil.ValueDoc
+	for _, d := range docs {
+		if _, err := given.PathValue(d.Path); err == nil {
+			continue
+		}
+		switch {
+		case d.Required:
+			required = append(required, d)
+		case d.Description != "":
+			optional = append(optional, d)
+		}
+	}
+
+	answers := chartutil.Values{}
+	for _, d := range required {
+		if err := w.ask(d, answers); err != nil {
+			return nil, err
+		}
+	}
+	if len(optional) == 0 {
+		return answers, nil
+	}
+	fmt.Fprintf(w.out, "Configure the %d other documented values? [y/N]: ", len(optional))
+	line, err := w.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if a := strings.ToLower(strings.TrimSpace(line)); a != "y" && a != "yes" {
+		return answers, nil
+	}
+	for _, d := range optional {
+		if err := w.ask(d, answers); err != nil {
+			return nil, err
+		}
+	}
+	return answers, nil
+}
+
+// ask asks for a value until the answer is valid. An empty answer keeps the
+// default.
+func (w *valuesWizard) ask(d *chartutil.ValueDoc, answers chartutil.Values) error {
+	if d.Description != "" {
+		fmt.Fprintf(w.out, "\n# %s\n", d.Description)
+	}
+	def := formatDefault(d.Default)
+	prompt := d.Path
+	if len(d.Enum) > 0 {
+		prompt += " (" + strings.Join(d.Enum, "|") + ")"
+	}
+	if def != "" {
+		prompt += " [" + def + "]"
+	}
+
+	for {
+		fmt.Fprintf(w.out, "%s: ", prompt)
+		line, err := w.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		verr := d.Validate(answer)
+		var v interface{}
+		if verr == nil {
+			v, verr = typedAnswer(d.Path, answer, d.Default)
+		}
+		if verr == nil {
+			if answer != def {
+				setValue(answers, d.Path, v)
+			}
+			return nil
+		}
+		if err == io.EOF {
+			return verr
+		}
+		fmt.Fprintf(w.out, "Invalid value: %s\n", verr)
+	}
+}
+
+func formatDefault(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// typedAnswer converts an answer to the type of the default value. Numbers
+// read from a values file are json.Numbers.
+func typedAnswer(path, s string, def interface{}) (interface{}, error) {
+	switch def.(type) {
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", path)
+		}
+		return b, nil
+	case json.Number, float64, int64, int:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", path)
+		}
+		return f, nil
+	default:
+		return s, nil
+	}
+}
+
+// setValue sets the value at a dotted path, creating the tables on the way.
+func setValue(vals chartutil.Values, path string, v interface{}) {
+	keys := strings.Split(path, ".")
+	m := map[string]interface{}(vals)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = v
+}
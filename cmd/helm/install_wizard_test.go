@@ -0,0 +1,118 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestValuesWizard(t *testing.T) {
+	raw := `# Number of pods
+replicaCount: 1
+image:
+  # Tag of the image
+  tag: stable
+  # Pull policy of the image
+  # @enum Always IfNotPresent Never
+  pullPolicy: IfNotPresent
+# Name of the database
+# @required
+# @pattern ^[a-z]+$
+database: ""
+`
+	docs, err := chartutil.DocumentValues(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	given := chartutil.Values{"image": map[string]interface{}{"tag": "1.17"}}
+
+	var out bytes.Buffer
+	w := &valuesWizard{
+		in:  bufio.NewReader(strings.NewReader("\nSales\nsales\ny\nthree\n3\n\n")),
+		out: &out,
+	}
+	answers, err := w.run(docs, given)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := chartutil.Values{"database": "sales", "replicaCount": int64(3)}
+	if !reflect.DeepEqual(answers, expect) {
+		t.Errorf("expected %v, got %v", expect, answers)
+	}
+
+	for _, s := range []string{
+		"# Name of the database\ndatabase: ",
+		"Invalid value: database is required",
+		"Invalid value: database must match ^[a-z]+$",
+		"Configure the 2 other documented values? [y/N]: ",
+		"Invalid value: replicaCount must be a number",
+		"image.pullPolicy (Always|IfNotPresent|Never) [IfNotPresent]: ",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected the output to contain %q, got\n%s", s, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "image.tag") {
+		t.Errorf("expected the given value not to be asked for, got\n%s", out.String())
+	}
+}
+
+func TestInstallInteractive(t *testing.T) {
+	tdir, err := ioutil.TempDir("", "helm-wizard-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tdir)
+	saved := filepath.Join(tdir, "alpine-values.yaml")
+
+	var out bytes.Buffer
+	inst := &installCmd{
+		chartPath:   "testdata/testcharts/alpine",
+		namespace:   "default",
+		client:      &helm.FakeClient{},
+		out:         &out,
+		interactive: true,
+		saveValues:  saved,
+		in:          strings.NewReader("y\nyour-alpine\n"),
+		inTerminal:  true,
+	}
+	if err := inst.run(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Name: your-alpine\n" {
+		t.Errorf("expected the answers to be saved, got %q", data)
+	}
+
+	inst.inTerminal = false
+	if err := inst.run(); err == nil {
+		t.Error("expected --interactive to fail without a terminal")
+	}
+}
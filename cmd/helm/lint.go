@@ -30,7 +30,9 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/lint"
+	"k8s.io/helm/pkg/lint/rules"
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/plugin"
 	"k8s.io/helm/pkg/strvals"
 )
 
@@ -41,17 +43,67 @@ the chart is well-formed.
 If the linter encounters things that will cause the chart to fail installation,
 it will emit [ERROR] messages. If it encounters issues that break with convention
 or recommendation, it will emit [WARNING] messages.
+
+Rules can be disabled, or given another severity, by a '.helmlint.yaml' file at
+the root of the chart:
+
+	rules:
+	  chart-icon:
+	    severity: warning
+	  template-extension:
+	    disabled: true
+
+A template can suppress rules for itself with a comment:
+
+	{{/* helmlint:disable template-yaml */}}
+
+The rendered manifests are validated against the Kubernetes API schemas
+compiled into Helm, and against the API versions served by the Kubernetes
+version given by '--kube-version'. No cluster is contacted to do so. Use
+'--deprecations' to also be warned about apiVersions that later Kubernetes
+versions deprecate.
+
+The files of the chart are scanned for likely credentials, such as AWS keys,
+private keys and high-entropy strings. Findings that are not credentials can be
+allowed in '.helmlint.yaml', as for 'helm package':
+
+	secrets:
+	  allow:
+	    - path: values.yaml
+	      match: EXAMPLE
+
+Plugins can add rules of their own, which are reported as PLUGIN/RULE.
+
+With '--with-subcharts' (or its alias '--lint-dependencies'), the subcharts of
+the 'charts/' directory, unpacked or archived, are linted too, each with the
+values the chart gives it. Subcharts disabled by the conditions or tags of the
+chart are skipped. Their findings are listed with the chart, with paths such as
+'charts/mysql/values.yaml'.
+
+Use '--format json' or '--format sarif' to get the results in a form other
+tools can read.
+
+Some findings are mechanical, such as trailing whitespace in templates, and
+can be fixed by Helm: '--fix' rewrites the files of the chart and reports what
+changed, and '--diff' shows the changes as a diff. The chart is linted again
+once it is fixed.
 `
 
 type lintCmd struct {
-	valueFiles valueFiles
-	values     []string
-	sValues    []string
-	fValues    []string
-	namespace  string
-	strict     bool
-	paths      []string
-	out        io.Writer
+	valueFiles   valueFiles
+	values       []string
+	sValues      []string
+	fValues      []string
+	namespace    string
+	strict       bool
+	format       string
+	kubeVersion  string
+	deprecations bool
+	fix          bool
+	diff         bool
+	subcharts    bool
+	paths        []string
+	out          io.Writer
 }
 
 func newLintCmd(out io.Writer) *cobra.Command {
@@ -77,6 +129,13 @@ func newLintCmd(out io.Writer) *cobra.Command {
 	cmd.Flags().StringArrayVar(&l.fValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
 	cmd.Flags().StringVar(&l.namespace, "namespace", "default", "Namespace to put the release into")
 	cmd.Flags().BoolVar(&l.strict, "strict", false, "Fail on lint warnings")
+	cmd.Flags().StringVar(&l.format, "format", "", "Output the results in the specified format (json, sarif)")
+	cmd.Flags().StringVar(&l.kubeVersion, "kube-version", defaultKubeVersion, "Kubernetes version the rendered manifests are validated against")
+	cmd.Flags().BoolVar(&l.deprecations, "deprecations", false, "Also warn about apiVersions deprecated by Kubernetes versions later than --kube-version")
+	cmd.Flags().BoolVar(&l.fix, "fix", false, "Rewrite the chart files to fix the mechanical findings")
+	cmd.Flags().BoolVar(&l.diff, "diff", false, "Show the changes fixing the mechanical findings as a diff")
+	cmd.Flags().BoolVar(&l.subcharts, "with-subcharts", false, "Also lint the enabled subcharts, with the values the chart gives them")
+	cmd.Flags().BoolVar(&l.subcharts, "lint-dependencies", false, "Alias of --with-subcharts")
 
 	return cmd
 }
@@ -84,6 +143,20 @@ func newLintCmd(out io.Writer) *cobra.Command {
 var errLintNoChart = errors.New("No chart found for linting (missing Chart.yaml)")
 
 func (l *lintCmd) run() error {
+	if l.format != "" && l.format != "json" && l.format != "sarif" {
+		return fmt.Errorf("unknown format %q", l.format)
+	}
+	if (l.fix || l.diff) && l.format != "" {
+		return errors.New("--fix and --diff cannot be used with --format")
+	}
+	if l.fix {
+		for _, path := range l.paths {
+			if chartutil.IsArchive(path) {
+				return fmt.Errorf("cannot fix the packaged chart %s", path)
+			}
+		}
+	}
+
 	var lowestTolerance int
 	if l.strict {
 		lowestTolerance = support.WarningSev
@@ -97,32 +170,50 @@ func (l *lintCmd) run() error {
 		return err
 	}
 
+	external := pluginLintRules()
+
 	var total int
 	var failures int
+	var results []lintResult
 	for _, path := range l.paths {
-		if linter, err := lintChart(path, rvals, l.namespace, l.strict); err != nil {
-			fmt.Println("==> Skipping", path)
-			fmt.Println(err)
-			if err == errLintNoChart {
-				failures = failures + 1
-			}
-		} else {
-			fmt.Println("==> Linting", path)
-
-			if len(linter.Messages) == 0 {
-				fmt.Println("Lint OK")
+		opts := lint.Options{
+			KubeVersion:   l.kubeVersion,
+			Deprecations:  l.deprecations,
+			External:      external,
+			WithSubcharts: l.subcharts,
+		}
+		linter, err := lintChart(path, rvals, l.namespace, l.strict, opts)
+		if err == nil && len(linter.Fixes) > 0 && (l.fix || l.diff) {
+			if err = applyLintFixes(l.out, linter.ChartDir, linter.Fixes, l.fix, l.diff); err != nil {
+				return err
 			}
-
-			for _, msg := range linter.Messages {
-				fmt.Println(msg)
+			if l.fix {
+				linter, err = lintChart(path, rvals, l.namespace, l.strict, opts)
 			}
-
-			total = total + 1
-			if linter.HighestSeverity >= lowestTolerance {
+		}
+		results = append(results, lintResult{path: path, linter: linter, err: err})
+		if err != nil {
+			if err == errLintNoChart {
 				failures = failures + 1
 			}
+			continue
+		}
+		total = total + 1
+		if linter.HighestSeverity >= lowestTolerance {
+			failures = failures + 1
 		}
-		fmt.Println("")
+	}
+
+	switch l.format {
+	case "json":
+		err = writeLintJSON(l.out, results)
+	case "sarif":
+		err = writeLintSARIF(l.out, results, external)
+	default:
+		writeLintText(results)
+	}
+	if err != nil {
+		return err
 	}
 
 	msg := fmt.Sprintf("%d chart(s) linted", total)
@@ -130,16 +221,54 @@ func (l *lintCmd) run() error {
 		return fmt.Errorf("%s, %d chart(s) failed", msg, failures)
 	}
 
-	fmt.Fprintf(l.out, "%s, no failures\n", msg)
+	if l.format == "" {
+		fmt.Fprintf(l.out, "%s, no failures\n", msg)
+	}
 
 	return nil
 }
 
-func lintChart(path string, vals []byte, namespace string, strict bool) (support.Linter, error) {
+// pluginLintRules returns the lint rules shipped by the installed plugins.
+// Rules that cannot be used are reported and skipped.
+func pluginLintRules() []rules.ExternalRule {
+	found, err := findPlugins(settings.PluginDirs())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load plugins: %s\n", err)
+		return nil
+	}
+
+	var external []rules.ExternalRule
+	for _, p := range found {
+		if len(p.Metadata.LintRules) == 0 || checkPluginCompatibility(p) != nil {
+			continue
+		}
+		plugin.SetupPluginEnv(settings, p.Metadata.Name, p.Dir)
+		for _, r := range p.Metadata.LintRules {
+			id := p.Metadata.Name + "/" + r.ID
+			severity := support.WarningSev
+			if r.Severity != "" {
+				if severity, err = support.ParseSeverity(r.Severity); err != nil {
+					fmt.Fprintf(os.Stderr, "Skipped lint rule %s: %s\n", id, err)
+					continue
+				}
+			}
+			parts := strings.Split(os.ExpandEnv(r.Command), " ")
+			external = append(external, rules.ExternalRule{
+				Rule:    support.Rule{ID: id, Severity: severity, Description: r.Description},
+				Command: parts[0],
+				Args:    parts[1:],
+				Env:     os.Environ(),
+			})
+		}
+	}
+	return external
+}
+
+func lintChart(path string, vals []byte, namespace string, strict bool, opts lint.Options) (support.Linter, error) {
 	var chartPath string
 	linter := support.Linter{}
 
-	if strings.HasSuffix(path, ".tgz") {
+	if chartutil.IsArchive(path) {
 		tempDir, err := ioutil.TempDir("", "helm-lint")
 		if err != nil {
 			return linter, err
@@ -171,7 +300,7 @@ func lintChart(path string, vals []byte, namespace string, strict bool) (support
 		return linter, errLintNoChart
 	}
 
-	return lint.All(chartPath, vals, namespace, strict), nil
+	return lint.AllWithOptions(chartPath, vals, namespace, strict, opts), nil
 }
 
 // vals merges values from files specified via -f/--values and
@@ -190,6 +319,9 @@ func (l *lintCmd) vals() ([]byte, error) {
 		if err != nil {
 			return []byte{}, err
 		}
+		if bytes, err = decryptValues(filePath, bytes); err != nil {
+			return []byte{}, err
+		}
 
 		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
 			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
@@ -0,0 +1,90 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/helm/pkg/lint/support"
+)
+
+// applyLintFixes prints the diff of the fixes of the chart in chartDir if
+// diff is set, and writes them to the chart if write is set.
+func applyLintFixes(out io.Writer, chartDir string, fixes []support.Fix, write, diff bool) error {
+	for _, f := range fixes {
+		if diff {
+			writeFixDiff(out, f)
+		}
+		if !write {
+			continue
+		}
+		name := filepath.Join(chartDir, filepath.FromSlash(f.Path))
+		fi, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(name, f.Fixed, fi.Mode()); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Fixed %s:\n", f.Path)
+		for _, c := range f.Changes {
+			fmt.Fprintf(out, "  %s\n", c)
+		}
+	}
+	return nil
+}
+
+// writeFixDiff writes the changes of a fix as a unified diff without
+// context. Fixes rewrite lines in place, so the lines of both versions of
+// the file match one to one; otherwise the whole file is shown as changed.
+func writeFixDiff(out io.Writer, f support.Fix) {
+	before := strings.Split(string(f.Original), "\n")
+	after := strings.Split(string(f.Fixed), "\n")
+
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", path.Join("a", f.Path), path.Join("b", f.Path))
+	if len(before) != len(after) {
+		writeHunk(out, 0, before, after)
+		return
+	}
+	for i := 0; i < len(before); i++ {
+		if before[i] == after[i] {
+			continue
+		}
+		j := i
+		for j < len(before) && before[j] != after[j] {
+			j++
+		}
+		writeHunk(out, i, before[i:j], after[i:j])
+		i = j
+	}
+}
+
+func writeHunk(out io.Writer, start int, before, after []string) {
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", start+1, len(before), start+1, len(after))
+	for _, l := range before {
+		fmt.Fprintf(out, "-%s\n", l)
+	}
+	for _, l := range after {
+		fmt.Fprintf(out, "+%s\n", l)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	"k8s.io/helm/pkg/lint/rules"
+	"k8s.io/helm/pkg/lint/support"
+)
+
+// lintResult is the outcome of linting one chart.
+type lintResult struct {
+	path   string
+	linter support.Linter
+	// err is set if the chart could not be linted at all.
+	err error
+}
+
+func writeLintText(results []lintResult) {
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Println("==> Skipping", r.path)
+			fmt.Println(r.err)
+		} else {
+			fmt.Println("==> Linting", r.path)
+
+			if len(r.linter.Messages) == 0 {
+				fmt.Println("Lint OK")
+			}
+
+			for _, msg := range r.linter.Messages {
+				fmt.Println(msg)
+			}
+		}
+		fmt.Println("")
+	}
+}
+
+type lintChartJSON struct {
+	Chart    string            `json:"chart"`
+	Error    string            `json:"error,omitempty"`
+	Messages []lintMessageJSON `json:"messages"`
+}
+
+type lintMessageJSON struct {
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+func writeLintJSON(out io.Writer, results []lintResult) error {
+	charts := make([]lintChartJSON, 0, len(results))
+	for _, r := range results {
+		c := lintChartJSON{Chart: r.path, Messages: []lintMessageJSON{}}
+		if r.err != nil {
+			c.Error = r.err.Error()
+		}
+		for _, msg := range r.linter.Messages {
+			c.Messages = append(c.Messages, lintMessageJSON{
+				Rule:     msg.Rule,
+				Severity: support.SeverityName(msg.Severity),
+				Path:     msg.Path,
+				Message:  msg.Err.Error(),
+			})
+		}
+		charts = append(charts, c)
+	}
+
+	data, err := json.MarshalIndent(charts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to Marshal JSON output: %s", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+// The subset of SARIF 2.1.0 used to report lint results.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func writeLintSARIF(out io.Writer, results []lintResult, external []rules.ExternalRule) error {
+	driver := sarifDriver{
+		Name:           "helm lint",
+		InformationURI: "https://helm.sh",
+	}
+	for _, r := range rules.Builtin {
+		driver.Rules = append(driver.Rules, sarifRule{ID: r.ID, ShortDescription: sarifText{Text: r.Description}})
+	}
+	for _, r := range external {
+		driver.Rules = append(driver.Rules, sarifRule{ID: r.ID, ShortDescription: sarifText{Text: r.Description}})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, r := range results {
+		if r.err != nil {
+			run.Results = append(run.Results, sarifResult{
+				Level:     "error",
+				Message:   sarifText{Text: r.err.Error()},
+				Locations: sarifLocations(r.path, "."),
+			})
+			continue
+		}
+		for _, msg := range r.linter.Messages {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    msg.Rule,
+				Level:     sarifLevel(msg.Severity),
+				Message:   sarifText{Text: msg.Err.Error()},
+				Locations: sarifLocations(r.path, msg.Path),
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://schemastore.azurewebsites.net/schemas/json/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to Marshal SARIF output: %s", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+func sarifLocations(chartPath, file string) []sarifLocation {
+	uri := path.Join(filepath.ToSlash(chartPath), file)
+	return []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}}}
+}
+
+func sarifLevel(severity int) string {
+	switch severity {
+	case support.ErrorSev:
+		return "error"
+	case support.WarningSev:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -17,7 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"k8s.io/helm/pkg/lint"
+	"k8s.io/helm/pkg/lint/support"
 )
 
 var (
@@ -32,23 +41,116 @@ var (
 )
 
 func TestLintChart(t *testing.T) {
-	if _, err := lintChart(chartDirPath, values, namespace, strict); err != nil {
+	if _, err := lintChart(chartDirPath, values, namespace, strict, lint.Options{}); err != nil {
 		t.Errorf("%s", err)
 	}
 
-	if _, err := lintChart(archivedChartPath, values, namespace, strict); err != nil {
+	if _, err := lintChart(archivedChartPath, values, namespace, strict, lint.Options{}); err != nil {
 		t.Errorf("%s", err)
 	}
 
-	if _, err := lintChart(archivedChartPathWithHyphens, values, namespace, strict); err != nil {
+	if _, err := lintChart(archivedChartPathWithHyphens, values, namespace, strict, lint.Options{}); err != nil {
 		t.Errorf("%s", err)
 	}
 
-	if _, err := lintChart(invalidArchivedChartPath, values, namespace, strict); err == nil {
+	if _, err := lintChart(invalidArchivedChartPath, values, namespace, strict, lint.Options{}); err == nil {
 		t.Errorf("Expected a chart parsing error")
 	}
 
-	if _, err := lintChart(chartMissingManifest, values, namespace, strict); err == nil {
+	if _, err := lintChart(chartMissingManifest, values, namespace, strict, lint.Options{}); err == nil {
 		t.Errorf("Expected a chart parsing error")
 	}
 }
+
+func TestLintFormats(t *testing.T) {
+	linter := support.Linter{}
+	linter.RunRule(support.Rule{ID: "chart-icon", Severity: support.InfoSev}, "Chart.yaml", errors.New("icon is recommended"))
+	results := []lintResult{
+		{path: "mychart", linter: linter},
+		{path: "missing", err: errLintNoChart},
+	}
+
+	var out bytes.Buffer
+	if err := writeLintJSON(&out, results); err != nil {
+		t.Fatal(err)
+	}
+	var charts []lintChartJSON
+	if err := json.Unmarshal(out.Bytes(), &charts); err != nil {
+		t.Fatalf("invalid JSON output: %s", err)
+	}
+	if len(charts) != 2 || len(charts[0].Messages) != 1 || charts[1].Error == "" {
+		t.Fatalf("unexpected JSON output: %s", out.String())
+	}
+	if m := charts[0].Messages[0]; m.Rule != "chart-icon" || m.Severity != "INFO" || m.Path != "Chart.yaml" {
+		t.Errorf("unexpected JSON message: %#v", m)
+	}
+
+	out.Reset()
+	if err := writeLintSARIF(&out, results, nil); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF output: %s", err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 2 {
+		t.Fatalf("unexpected SARIF output: %s", out.String())
+	}
+	r := log.Runs[0].Results[0]
+	if r.RuleID != "chart-icon" || r.Level != "note" || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "mychart/Chart.yaml" {
+		t.Errorf("unexpected SARIF result: %#v", r)
+	}
+}
+
+func TestApplyLintFixes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-lint-fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(dir, "templates", "pod.yaml")
+	original := "kind: Pod\nmetadata:\n  name: a \n  labels:\n    heritage: {{ .Release.Service }}\n"
+	if err := ioutil.WriteFile(name, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fixes := []support.Fix{{
+		Path:     "templates/pod.yaml",
+		Original: []byte(original),
+		Fixed:    []byte("kind: Pod\nmetadata:\n  name: a\n  labels:\n    app.kubernetes.io/managed-by: {{ .Release.Service }}\n"),
+		Changes:  []string{"template-whitespace: line 3: trailing whitespace"},
+	}}
+
+	var out bytes.Buffer
+	if err := applyLintFixes(&out, dir, fixes, false, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := `--- a/templates/pod.yaml
++++ b/templates/pod.yaml
+@@ -3,1 +3,1 @@
+-  name: a 
++  name: a
+@@ -5,1 +5,1 @@
+-    heritage: {{ .Release.Service }}
++    app.kubernetes.io/managed-by: {{ .Release.Service }}
+`
+	if out.String() != expected {
+		t.Errorf("Expected diff:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if b, _ := ioutil.ReadFile(name); string(b) != original {
+		t.Errorf("Expected --diff not to write the chart, got %q", b)
+	}
+
+	out.Reset()
+	if err := applyLintFixes(&out, dir, fixes, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(name); string(b) != string(fixes[0].Fixed) {
+		t.Errorf("Expected the fix to be written, got %q", b)
+	}
+	if expected := "Fixed templates/pod.yaml:\n  template-whitespace: line 3: trailing whitespace\n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
@@ -50,6 +50,23 @@ Only items that match the filter will be returned.
 	NAME            	UPDATED                 	CHART
 	maudlin-arachnid	Mon May  9 16:07:08 2016	alpine-0.1.0
 
+The failed releases recorded by Tiller tell in which phase of the operation
+they failed (hooks, apply or wait) and the error they failed with. They are
+shown in the PHASE and LAST ERROR columns, and in the 'FailurePhase' and
+'LastError' fields of the json and yaml output:
+
+	$ helm list --failed --output json
+
+Releases can also be selected by the labels set with 'helm release label':
+
+	$ helm list --selector frozen=true
+
+The '--chart', '--app-version' and '--namespace' flags take globs, and are
+matched by Tiller against the chart name, app version and namespace of the
+releases:
+
+	$ helm list --chart 'nginx*' --namespace 'team-*'
+
 If no results are found, 'helm list' will exit 0, but with no output (or in
 the case of no '-q' flag, only headers).
 
@@ -79,6 +96,9 @@ type listCmd struct {
 	colWidth    uint
 	output      string
 	byChartName bool
+	selector    string
+	chart       string
+	appVersion  string
 }
 
 type listResult struct {
@@ -94,6 +114,9 @@ type listRelease struct {
 	Chart      string
 	AppVersion string
 	Namespace  string
+	// FailurePhase and LastError tell why a FAILED release failed.
+	FailurePhase string `json:",omitempty"`
+	LastError    string `json:",omitempty"`
 }
 
 func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -132,10 +155,13 @@ func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&list.deployed, "deployed", false, "Show deployed releases. If no other is specified, this will be automatically enabled")
 	f.BoolVar(&list.failed, "failed", false, "Show failed releases")
 	f.BoolVar(&list.pending, "pending", false, "Show pending releases")
-	f.StringVar(&list.namespace, "namespace", "", "Show releases within a specific namespace")
+	f.StringVar(&list.namespace, "namespace", "", "Show releases within the namespaces matching this glob")
 	f.UintVar(&list.colWidth, "col-width", 60, "Specifies the max column width of output")
 	f.StringVar(&list.output, "output", "", "Output the specified format (json or yaml)")
 	f.BoolVarP(&list.byChartName, "chart-name", "c", false, "Sort by chart name")
+	f.StringVar(&list.chart, "chart", "", "Show releases of the charts whose name matches this glob")
+	f.StringVar(&list.appVersion, "app-version", "", "Show releases of the charts whose app version matches this glob")
+	f.StringVarP(&list.selector, "selector", "l", "", "Selector (label query) to filter releases on, supports '=', '==', '!=', 'in' and 'notin' (e.g. -l key1=value1,key2=value2)")
 
 	// TODO: Do we want this as a feature of 'helm list'?
 	//f.BoolVar(&list.superseded, "history", true, "show historical releases")
@@ -170,6 +196,9 @@ func (l *listCmd) run() error {
 		helm.ReleaseListOrder(int32(sortOrder)),
 		helm.ReleaseListStatuses(stats),
 		helm.ReleaseListNamespace(l.namespace),
+		helm.ReleaseListSelector(l.selector),
+		helm.ReleaseListChartName(l.chart),
+		helm.ReleaseListAppVersion(l.appVersion),
 	)
 
 	if err != nil {
@@ -276,6 +305,10 @@ func getListResult(rels []*release.Release, next string) listResult {
 			AppVersion: md.GetAppVersion(),
 			Namespace:  r.GetNamespace(),
 		}
+		if r.GetInfo().GetStatus().GetCode() == release.Status_FAILED {
+			lr.FailurePhase = r.GetInfo().GetFailurePhase()
+			lr.LastError = r.GetInfo().GetLastError()
+		}
 		listReleases = append(listReleases, lr)
 	}
 
@@ -340,11 +373,28 @@ func formatText(result listResult, colWidth uint) string {
 		nextOutput = fmt.Sprintf("\tnext: %s\n", result.Next)
 	}
 
+	// the reasons of the failures are only shown if a listed release failed
+	failures := false
+	for _, lr := range result.Releases {
+		if lr.FailurePhase != "" || lr.LastError != "" {
+			failures = true
+			break
+		}
+	}
+
 	table := uitable.New()
 	table.MaxColWidth = colWidth
-	table.AddRow("NAME", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "NAMESPACE")
+	if failures {
+		table.AddRow("NAME", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "NAMESPACE", "PHASE", "LAST ERROR")
+	} else {
+		table.AddRow("NAME", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "NAMESPACE")
+	}
 	for _, lr := range result.Releases {
-		table.AddRow(lr.Name, lr.Revision, lr.Updated, lr.Status, lr.Chart, lr.AppVersion, lr.Namespace)
+		if failures {
+			table.AddRow(lr.Name, lr.Revision, lr.Updated, lr.Status, lr.Chart, lr.AppVersion, lr.Namespace, lr.FailurePhase, lr.LastError)
+		} else {
+			table.AddRow(lr.Name, lr.Revision, lr.Updated, lr.Status, lr.Chart, lr.AppVersion, lr.Namespace)
+		}
 	}
 
 	return fmt.Sprintf("%s%s", nextOutput, table.String())
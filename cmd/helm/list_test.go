@@ -74,6 +74,24 @@ func TestListCmd(t *testing.T) {
 			},
 			expected: "NAME \tREVISION\tUPDATED                 \tSTATUS  \tCHART           \tAPP VERSION\tNAMESPACE\natlas\t1       \t(.*)\tDEPLOYED\tfoo-0.1.0-beta.1\t2.X.A      \tdefault  \n",
 		},
+		{
+			name:  "filtered by app version",
+			flags: []string{"-q", "--output", "json", "--app-version", "2.*"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas", Chart: ch}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"}),
+			},
+			expected: regexp.QuoteMeta(`["atlas"]
+`),
+		},
+		{
+			name:  "filtered by chart",
+			flags: []string{"-q", "--chart", "bar*"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas", Chart: ch}),
+			},
+			expected: "^\n$",
+		},
 		{
 			name:  "with json output",
 			flags: []string{"--max", "1", "--output", "json"},
@@ -223,9 +241,39 @@ Releases: []
 			},
 			expected: "thomas-guide",
 		},
+		{
+			name:  "with a failed release",
+			flags: []string{"--failed"},
+			rels: []*release.Release{
+				failedReleaseMock("thomas-guide", "wait", "timed out waiting for the condition"),
+			},
+			expected: `NAMESPACE\s+PHASE\s+LAST ERROR\s*\n.*FAILED.*\twait\s+timed out waiting for the condition`,
+		},
+		{
+			name:  "with a failed release in json",
+			flags: []string{"--failed", "--output", "json"},
+			rels: []*release.Release{
+				failedReleaseMock("thomas-guide", "wait", "timed out waiting for the condition"),
+			},
+			expected: regexp.QuoteMeta(`"Status":"FAILED",`) + ".*" + regexp.QuoteMeta(`"FailurePhase":"wait","LastError":"timed out waiting for the condition"}`),
+		},
+		{
+			name: "without failed releases",
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"}),
+			},
+			expected: `NAMESPACE\s*\n`,
+		},
 	}
 
 	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
 		return newListCmd(c, out)
 	})
 }
+
+func failedReleaseMock(name, phase, lastError string) *release.Release {
+	r := helm.ReleaseMock(&helm.MockReleaseOptions{Name: name, StatusCode: release.Status_FAILED})
+	r.Info.FailurePhase = phase
+	r.Info.LastError = lastError
+	return r
+}
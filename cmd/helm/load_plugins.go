@@ -49,7 +49,7 @@ func loadPlugins(baseCmd *cobra.Command, out io.Writer) {
 	// debug("HELM_PLUGIN_DIRS=%s", settings.PluginDirs())
 	found, err := findPlugins(settings.PluginDirs())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to load plugins: %s", err)
+		logger.Warnf("failed to load plugins: %s", err)
 		return
 	}
 
@@ -65,6 +65,10 @@ func loadPlugins(baseCmd *cobra.Command, out io.Writer) {
 	for _, plug := range found {
 		plug := plug
 		md := plug.Metadata
+		if err := checkPluginCompatibility(plug); err != nil {
+			logger.Warnf("skipping plugin: %s", err)
+			continue
+		}
 		if md.Usage == "" {
 			md.Usage = fmt.Sprintf("the %q plugin", md.Name)
 		}
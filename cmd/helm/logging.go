@@ -0,0 +1,75 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/trace"
+)
+
+// logger writes the logs of the client to stderr, leaving stdout to the
+// output of the commands.
+var logger = logging.New(os.Stderr, logging.TextFormat, logging.InfoLevel)
+
+// setupLogging applies the log settings, and sends the output of the standard
+// logger through logger.
+func setupLogging() error {
+	format, err := logging.ParseFormat(settings.LogFormat)
+	if err != nil {
+		return err
+	}
+	level, err := logging.ParseLevel(settings.LogLevel)
+	if err != nil {
+		return err
+	}
+	if settings.Debug {
+		level = logging.DebugLevel
+	}
+	logger.SetFormat(format)
+	logger.SetLevel(level)
+
+	log.SetOutput(logger)
+	log.SetFlags(0)
+
+	if settings.Explain {
+		trace.Enable(settings.Home.String(), explain)
+	} else {
+		trace.Disable()
+	}
+	return nil
+}
+
+// explain logs a file access or a network request for --explain.
+func explain(e trace.Event) {
+	fields := logging.Fields{
+		"bytes":    e.Bytes,
+		"duration": e.Duration.Round(time.Microsecond).String(),
+	}
+	if e.Status != 0 {
+		fields["status"] = e.Status
+	}
+	l := logger.Component("explain").With(fields)
+	if e.Err != nil {
+		l.Warnf("%s %s: %s", e.Op, e.Path, e.Err)
+		return
+	}
+	l.Infof("%s %s", e.Op, e.Path)
+}
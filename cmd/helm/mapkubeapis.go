@@ -0,0 +1,106 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const mapKubeAPIsDesc = `
+This command replaces the apiVersions Kubernetes no longer serves in the
+stored manifest of the latest revision of a release.
+
+Once a cluster is upgraded to a Kubernetes version that stops serving an
+apiVersion, such as extensions/v1beta1 Deployments in 1.16, releases whose
+manifest still uses it can no longer be upgraded. This command rewrites the
+stored manifest to use the replacement apiVersions, so the next upgrade can
+find the resources. The resources in the cluster are not changed.
+
+The apiVersions are mapped for the Kubernetes version of the cluster, unless
+'--kube-version' is given. Use '--dry-run' to see what would be mapped:
+
+	$ helm mapkubeapis my-release --kube-version 1.16 --dry-run
+`
+
+type mapKubeAPIsCmd struct {
+	release     string
+	kubeVersion string
+	dryRun      bool
+	out         io.Writer
+	client      helm.Interface
+}
+
+func newMapKubeAPIsCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	m := &mapKubeAPIsCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "mapkubeapis [flags] RELEASE_NAME",
+		Short:   "Replace apiVersions Kubernetes no longer serves in a release",
+		Long:    mapKubeAPIsDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			m.release = args[0]
+			m.client = ensureHelmClient(m.client)
+			return m.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.StringVar(&m.kubeVersion, "kube-version", "", "Kubernetes version to map the apiVersions for. Defaults to the version of the cluster")
+	f.BoolVar(&m.dryRun, "dry-run", false, "Show the apiVersions that would be mapped without changing the release")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (m *mapKubeAPIsCmd) run() error {
+	res, err := m.client.MapReleaseAPIs(m.release,
+		helm.MapAPIsKubeVersion(m.kubeVersion),
+		helm.MapAPIsDryRun(m.dryRun),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+
+	if len(res.Mappings) == 0 {
+		fmt.Fprintf(m.out, "Release %q uses no apiVersions to map.\n", m.release)
+		return nil
+	}
+	for _, mapping := range res.Mappings {
+		fmt.Fprintln(m.out, mapping)
+	}
+	if m.dryRun {
+		fmt.Fprintf(m.out, "Release %q was not changed (dry run).\n", m.release)
+		return nil
+	}
+	fmt.Fprintf(m.out, "Release %q has been mapped.\n", m.release)
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func outdatedReleaseMock() *release.Release {
+	rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})
+	rel.Manifest = "---\n# Source: foo/templates/deployment.yaml\napiVersion: extensions/v1beta1\nkind: Deployment\nmetadata:\n  name: web\n"
+	return rel
+}
+
+func TestMapKubeAPIsCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "map",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--kube-version", "1.16"},
+			expected: "Deployment \"web\": extensions/v1beta1 -> apps/v1\nRelease \"thomas-guide\" has been mapped.\n",
+			rels:     []*release.Release{outdatedReleaseMock()},
+		},
+		{
+			name:     "dry run",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--kube-version", "1.16", "--dry-run"},
+			expected: "Deployment \"web\": extensions/v1beta1 -> apps/v1\nRelease \"thomas-guide\" was not changed \\(dry run\\).\n",
+			rels:     []*release.Release{outdatedReleaseMock()},
+		},
+		{
+			name:     "nothing to map",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--kube-version", "1.15"},
+			expected: "Release \"thomas-guide\" uses no apiVersions to map.\n",
+			rels:     []*release.Release{outdatedReleaseMock()},
+		},
+		{
+			name: "missing release",
+			args: []string{"no-such-release"},
+			err:  true,
+		},
+		{
+			name: "release required",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newMapKubeAPIsCmd(c, out)
+	})
+}
@@ -0,0 +1,161 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/trace"
+)
+
+// messageID identifies a message printed by the commands changing releases.
+//
+// The messages are looked up in the messages catalog, so that they can be
+// translated or stripped without touching the commands. A distribution of Helm
+// can change the catalog below, and users can override messages in
+// $HELM_HOME/messages/<locale>.yaml, see loadMessages.
+type messageID string
+
+const (
+	msgFinalName          messageID = "install.final-name"
+	msgInstallFailed      messageID = "install.failed"
+	msgInstallWarning     messageID = "install.warning"
+	msgReleaseName        messageID = "install.release-name"
+	msgPurgeManually      messageID = "install.purge-manually"
+	msgNothingToPurge     messageID = "install.nothing-to-purge"
+	msgPurged             messageID = "install.purged"
+	msgNamespaceMismatch  messageID = "upgrade.namespace-mismatch"
+	msgInstallingRelease  messageID = "upgrade.installing"
+	msgUpgradeFailed      messageID = "upgrade.failed"
+	msgUpgraded           messageID = "upgrade.upgraded"
+	msgPurgingRelease     messageID = "upgrade.purging"
+	msgRollingBack        messageID = "upgrade.rolling-back"
+	msgRolledBack         messageID = "rollback.rolled-back"
+	msgRollbackFailed     messageID = "rollback.failed"
+	msgDeleted            messageID = "delete.deleted"
+	msgFrozen             messageID = "freeze.frozen"
+	msgFrozenUntil        messageID = "freeze.frozen-until"
+	msgUnfrozen           messageID = "unfreeze.unfrozen"
+	msgWindowSet          messageID = "window.set"
+	msgWindowCleared      messageID = "window.cleared"
+	msgReleaseMetadataSet messageID = "release.metadata-updated"
+	msgCancelled          messageID = "cancel.cancelled"
+	msgUnlocked           messageID = "release.unlocked"
+	msgReleaseExported    messageID = "release.exported"
+	msgReleaseImported    messageID = "release.imported"
+	msgStorageMigrated    messageID = "storage.migrated"
+)
+
+// messages maps the messages to their format, as given to fmt.Sprintf. A
+// message with an empty format is not printed.
+var messages = map[messageID]string{
+	msgFinalName:          "FINAL NAME: %s",
+	msgInstallFailed:      "INSTALL FAILED\nPURGING CHART\nError: %v",
+	msgInstallWarning:     "WARNING: %s",
+	msgReleaseName:        "NAME:   %s",
+	msgPurgeManually:      "The release name was generated by Tiller, the release must be purged manually",
+	msgNothingToPurge:     "Release %q was not recorded, nothing to purge",
+	msgPurged:             "Successfully purged a chart!",
+	msgNamespaceMismatch:  "WARNING: Namespace %q doesn't match with previous. Release will be deployed to %s",
+	msgInstallingRelease:  "Release %q does not exist. Installing it now.",
+	msgUpgradeFailed:      "UPGRADE FAILED\nError: %v",
+	msgUpgraded:           "Release %q has been upgraded.",
+	msgPurgingRelease:     "PURGING RELEASE",
+	msgRollingBack:        "ROLLING BACK to revision %d",
+	msgRolledBack:         "Rollback was a success.",
+	msgRollbackFailed:     "ROLLBACK FAILED\nError: %v",
+	msgDeleted:            "release %q deleted",
+	msgFrozen:             "Release %q has been frozen until it is unfrozen.",
+	msgFrozenUntil:        "Release %q has been frozen until %s.",
+	msgUnfrozen:           "Release %q has been unfrozen.",
+	msgWindowSet:          "Release %q may now only be changed during %s.",
+	msgWindowCleared:      "Release %q no longer has its own maintenance window.",
+	msgReleaseMetadataSet: "Release %q has been updated.",
+	msgCancelled:          "The %s of release %q, started at %s, has been cancelled.",
+	msgUnlocked:           "Release %q has been unlocked, it was locked for %s by %s since %s.",
+	msgReleaseExported:    "Release %q has been exported to %s with its %d revisions.",
+	msgReleaseImported:    "Release %q has been imported in namespace %q with its %d revisions, revision %d is %s.",
+	msgStorageMigrated:    "%d release records have been copied from the %s driver to the %s driver, %d were there already.",
+}
+
+// printMessage prints a message of the catalog on its own line, unless the
+// command is quiet.
+func printMessage(out io.Writer, quiet bool, id messageID, args ...interface{}) {
+	format := messages[id]
+	if quiet || format == "" {
+		return
+	}
+	fmt.Fprintf(out, format+"\n", args...)
+}
+
+// messageLocale returns the locale the messages are printed in, following
+// the precedence of LC_ALL, LC_MESSAGES and LANG.
+func messageLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// loadMessages overrides the messages of the catalog with the ones of the
+// locale, read from $HELM_HOME/messages. For the locale "de_DE.UTF-8", the
+// files de_DE.yaml and de.yaml are read, the former taking precedence. Each
+// file maps message identifiers to their format; a message set to an empty
+// string is stripped. The catalog is kept as is if no file exists.
+func loadMessages(home helmpath.Home, locale string) error {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return nil
+	}
+
+	names := []string{locale}
+	if i := strings.Index(locale, "_"); i > 0 {
+		names = append([]string{locale[:i]}, names...)
+	}
+	for _, name := range names {
+		path := filepath.Join(home.Messages(), name+".yaml")
+		b, err := trace.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		overrides := map[messageID]string{}
+		if err := yaml.Unmarshal(b, &overrides); err != nil {
+			return fmt.Errorf("cannot load messages from %s: %s", path, err)
+		}
+		for id, format := range overrides {
+			if _, ok := messages[id]; !ok {
+				return fmt.Errorf("cannot load messages from %s: unknown message %q", path, id)
+			}
+			messages[id] = format
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMessages(t *testing.T) {
+	home, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home.String())
+
+	defaults := map[messageID]string{}
+	for id, format := range messages {
+		defaults[id] = format
+	}
+	defer func() { messages = defaults }()
+
+	if err := os.MkdirAll(home.Messages(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"de.yaml":    "rollback.rolled-back: Rollback erfolgreich.\nupgrade.upgraded: Release %q wurde aktualisiert.\n",
+		"de_AT.yaml": "upgrade.upgraded: \"\"\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(home.Messages(), name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := loadMessages(home, "de_AT.UTF-8"); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	printMessage(&b, false, msgRolledBack)
+	printMessage(&b, false, msgUpgraded, "funny-bunny")
+	printMessage(&b, false, msgUnfrozen, "funny-bunny")
+	printMessage(&b, true, msgRolledBack)
+	expect := "Rollback erfolgreich.\nRelease \"funny-bunny\" has been unfrozen.\n"
+	if b.String() != expect {
+		t.Errorf("Expected %q, got %q", expect, b.String())
+	}
+
+	// locales without translations keep the catalog
+	if err := loadMessages(home, "fr_FR.UTF-8"); err != nil {
+		t.Errorf("Expected no error without translations, got %s", err)
+	}
+	if err := loadMessages(home, "C"); err != nil {
+		t.Errorf("Expected no error for the C locale, got %s", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(home.Messages(), "es.yaml"), []byte("unknown.message: hola\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadMessages(home, "es"); err == nil {
+		t.Error("Expected an error for an unknown message")
+	}
+}
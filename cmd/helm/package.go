@@ -23,7 +23,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/spf13/cobra"
@@ -33,10 +36,13 @@ import (
 	"k8s.io/helm/pkg/downloader"
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/lint/support"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/provenance"
 	"k8s.io/helm/pkg/renderutil"
 	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/secretscan"
+	"k8s.io/helm/pkg/sympath"
 )
 
 const packageDesc = `
@@ -47,12 +53,40 @@ Chart.yaml file) and then package that directory.
 If no path is given, this will look in the present working directory for a
 Chart.yaml file, and (if found) build the current directory into a chart.
 
-Versioned chart archives are used by Helm package repositories.
+Versioned chart archives are used by Helm package repositories. They are
+gzipped tar archives, or zip archives with '--format zip' for the artifact
+stores that only take zip files. Helm loads, verifies and indexes both alike.
+
+Packaging the same chart always creates the same archive: the files are sorted
+and their permissions and modification times are normalized. The modification
+time defaults to the Unix epoch, or to $SOURCE_DATE_EPOCH if it is set, and is
+overridden by '--source-date-epoch'.
+
+The files of the chart are scanned for likely credentials, such as AWS keys,
+private keys and high-entropy strings, and the chart is not packaged if any is
+found. Findings that are not credentials can be allowed in the '.helmlint.yaml'
+file of the chart, or by a 'helmlint:allow-secret' comment on their line:
+
+	secrets:
+	  allow:
+	    - path: values.yaml
+	      match: EXAMPLE
+
+Use '--allow-secrets' to package the chart anyway.
+
+Files matching the rules of the '.helmignore' file of the chart are left out of
+the package. Use '--show-ignored' to list every file of the chart with whether
+it is included, and the rule that decided it.
+
+A package signed with '--sign' gets a provenance file. Since the archive is
+reproducible, several signers can package the same chart and add their
+signature to the same provenance file with '--append-signature'.
 `
 
 type packageCmd struct {
 	save             bool
 	sign             bool
+	appendSignature  bool
 	path             string
 	key              string
 	keyring          string
@@ -60,6 +94,10 @@ type packageCmd struct {
 	appVersion       string
 	destination      string
 	dependencyUpdate bool
+	sourceDateEpoch  int64
+	allowSecrets     bool
+	showIgnored      bool
+	format           string
 
 	out  io.Writer
 	home helmpath.Home
@@ -77,6 +115,16 @@ func newPackageCmd(out io.Writer) *cobra.Command {
 			if len(args) == 0 {
 				return fmt.Errorf("need at least one argument, the path to the chart")
 			}
+			if v := os.Getenv("SOURCE_DATE_EPOCH"); v != "" && !cmd.Flags().Changed("source-date-epoch") {
+				epoch, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %s", v, err)
+				}
+				pkg.sourceDateEpoch = epoch
+			}
+			if pkg.format != string(chartutil.FormatTgz) && pkg.format != string(chartutil.FormatZip) {
+				return fmt.Errorf("unknown format %q, must be %s or %s", pkg.format, chartutil.FormatTgz, chartutil.FormatZip)
+			}
 			if pkg.sign {
 				if pkg.key == "" {
 					return errors.New("--key is required for signing a package")
@@ -99,11 +147,16 @@ func newPackageCmd(out io.Writer) *cobra.Command {
 	f.BoolVar(&pkg.save, "save", true, "Save packaged chart to local chart repository")
 	f.BoolVar(&pkg.sign, "sign", false, "Use a PGP private key to sign this package")
 	f.StringVar(&pkg.key, "key", "", "Name of the key to use when signing. Used if --sign is true")
+	f.BoolVar(&pkg.appendSignature, "append-signature", false, "Add the signature to the existing provenance file of the package, if any, instead of replacing it. Used if --sign is true")
 	f.StringVar(&pkg.keyring, "keyring", defaultKeyring(), "Location of a public keyring")
 	f.StringVar(&pkg.version, "version", "", "Set the version on the chart to this semver version")
 	f.StringVar(&pkg.appVersion, "app-version", "", "Set the appVersion on the chart to this version")
 	f.StringVarP(&pkg.destination, "destination", "d", ".", "Location to write the chart.")
 	f.BoolVarP(&pkg.dependencyUpdate, "dependency-update", "u", false, `Update dependencies from "requirements.yaml" to dir "charts/" before packaging`)
+	f.BoolVar(&pkg.allowSecrets, "allow-secrets", false, "Package the chart even if likely credentials are found in its files")
+	f.BoolVar(&pkg.showIgnored, "show-ignored", false, "List the files of the chart with whether they are ignored, and the .helmignore rule that decided it")
+	f.StringVar(&pkg.format, "format", string(chartutil.FormatTgz), "Format of the chart archive: tgz or zip")
+	f.Int64Var(&pkg.sourceDateEpoch, "source-date-epoch", 0, "Modification time of the files in the archive, in seconds since the Unix epoch. Defaults to $SOURCE_DATE_EPOCH")
 
 	return cmd
 }
@@ -129,6 +182,12 @@ func (p *packageCmd) run() error {
 		}
 	}
 
+	if p.showIgnored {
+		if err := showIgnored(p.out, path); err != nil {
+			return err
+		}
+	}
+
 	ch, err := chartutil.LoadDir(path)
 	if err != nil {
 		return err
@@ -147,6 +206,13 @@ func (p *packageCmd) run() error {
 		debug("Setting appVersion to %s", p.appVersion)
 	}
 
+	if err := chartutil.ValidateChartType(ch.Metadata); err != nil {
+		return err
+	}
+	if err := chartutil.ValidateAnnotations(ch.Metadata); err != nil {
+		return err
+	}
+
 	if filepath.Base(path) != ch.Metadata.Name {
 		return fmt.Errorf("directory name (%s) and Chart.yaml name (%s) must match", filepath.Base(path), ch.Metadata.Name)
 	}
@@ -161,6 +227,12 @@ func (p *packageCmd) run() error {
 		}
 	}
 
+	if !p.allowSecrets {
+		if err := scanSecrets(p.out, path, ch); err != nil {
+			return err
+		}
+	}
+
 	var dest string
 	if p.destination == "." {
 		// Save to the current working directory.
@@ -173,7 +245,10 @@ func (p *packageCmd) run() error {
 		dest = p.destination
 	}
 
-	name, err := chartutil.Save(ch, dest)
+	name, err := chartutil.SaveWithOptions(ch, dest, chartutil.SaveOptions{
+		ModTime: time.Unix(p.sourceDateEpoch, 0),
+		Format:  chartutil.ArchiveFormat(p.format),
+	})
 	if err == nil {
 		fmt.Fprintf(p.out, "Successfully packaged chart and saved it to: %s\n", name)
 	} else {
@@ -197,6 +272,63 @@ func (p *packageCmd) run() error {
 	return err
 }
 
+// showIgnored lists the files of the chart directory at path, with whether
+// they are packaged and the ignore rule that decided it. The contents of an
+// ignored directory are not listed.
+func showIgnored(out io.Writer, path string) error {
+	rules, err := chartutil.LoadIgnoreRules(path)
+	if err != nil {
+		return err
+	}
+	topdir := path + string(filepath.Separator)
+	return sympath.Walk(topdir, func(name string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		n := filepath.ToSlash(strings.TrimPrefix(name, topdir))
+		if n == "" {
+			return nil
+		}
+		ignored, rule := rules.Explain(n, fi)
+		if fi.IsDir() {
+			if !ignored {
+				return nil
+			}
+			n += "/"
+		}
+		state := "included"
+		if ignored {
+			state = "ignored"
+		}
+		if rule == "" {
+			fmt.Fprintf(out, "%-8s  %s\n", state, n)
+		} else {
+			fmt.Fprintf(out, "%-8s  %s  (%s)\n", state, n, rule)
+		}
+		if fi.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// scanSecrets fails if likely credentials are found in the files of the
+// chart loaded from path, printing each of them.
+func scanSecrets(out io.Writer, path string, ch *chart.Chart) error {
+	config, err := support.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	findings := secretscan.ScanChart(ch, config.Secrets.Allow)
+	if len(findings) == 0 {
+		return nil
+	}
+	for _, f := range findings {
+		fmt.Fprintf(out, "%s: %s\n", f.Path, f)
+	}
+	return fmt.Errorf("found %d likely credential(s) in chart %s, use --allow-secrets to package it anyway", len(findings), ch.Metadata.Name)
+}
+
 func setVersion(ch *chart.Chart, ver string) error {
 	// Verify that version is a SemVer, and error out if it is not.
 	if _, err := semver.NewVersion(ver); err != nil {
@@ -226,7 +358,18 @@ func (p *packageCmd) clearsign(filename string) error {
 
 	debug(sig)
 
-	return ioutil.WriteFile(filename+".prov", []byte(sig), 0755)
+	provfile := filename + ".prov"
+	if p.appendSignature {
+		prov, err := ioutil.ReadFile(provfile)
+		if err == nil {
+			if sig, err = provenance.AppendSignature(prov, sig); err != nil {
+				return err
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return ioutil.WriteFile(provfile, []byte(sig), 0755)
 }
 
 // passphraseFetcher implements provenance.PassphraseFetcher
@@ -20,8 +20,11 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
 
+	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/plugin"
+	"k8s.io/helm/pkg/version"
 
 	"github.com/spf13/cobra"
 )
@@ -70,3 +73,19 @@ func runHook(p *plugin.Plugin, event string) error {
 	}
 	return nil
 }
+
+// checkPluginCompatibility returns an error if a plugin does not support this
+// version of Helm or this platform.
+func checkPluginCompatibility(p *plugin.Plugin) error {
+	return p.Metadata.CheckCompatibility(version.GetVersion(), runtime.GOOS, runtime.GOARCH)
+}
+
+// updatePluginsLock applies fn to the plugins lock file and writes it back.
+func updatePluginsLock(home helmpath.Home, fn func(*plugin.LockFile)) error {
+	lock, err := plugin.LoadLockFile(home.PluginsLockFile())
+	if err != nil {
+		return err
+	}
+	fn(lock)
+	return lock.WriteFile(home.PluginsLockFile(), 0644)
+}
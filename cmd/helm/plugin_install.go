@@ -18,6 +18,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"os"
 
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/plugin"
@@ -27,15 +28,23 @@ import (
 )
 
 type pluginInstallCmd struct {
-	source  string
-	version string
-	home    helmpath.Home
-	out     io.Writer
+	source   string
+	version  string
+	checksum string
+	home     helmpath.Home
+	out      io.Writer
 }
 
 const pluginInstallDesc = `
 This command allows you to install a plugin from a url to a VCS repo or a local path.
 
+Plugins that declare a 'helmVersion' constraint or a list of 'platforms' in
+their plugin.yaml are only installed on a matching version of Helm and platform.
+
+Installed plugins are recorded in $HELM_HOME/plugins/plugins.lock along with
+their source and the digest of their files. Pass '--checksum' with a digest
+from a lock file to verify a plugin before its install hook runs.
+
 Example usage:
     $ helm plugin install https://github.com/technosophos/helm-template
 `
@@ -54,6 +63,7 @@ func newPluginInstallCmd(out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&pcmd.version, "version", "", "Specify a version constraint. If this is not specified, the latest version is installed")
+	cmd.Flags().StringVar(&pcmd.checksum, "checksum", "", "Verify that the installed plugin has this digest (sha256:...) before enabling it")
 	return cmd
 }
 
@@ -83,6 +93,26 @@ func (pcmd *pluginInstallCmd) run() error {
 		return err
 	}
 
+	digest, err := pcmd.verify(p)
+	if err != nil {
+		if rmErr := os.RemoveAll(i.Path()); rmErr != nil {
+			debug("failed to remove %s: %s", i.Path(), rmErr)
+		}
+		return err
+	}
+
+	err = updatePluginsLock(pcmd.home, func(lock *plugin.LockFile) {
+		lock.Set(&plugin.LockEntry{
+			Name:    p.Metadata.Name,
+			Version: p.Metadata.Version,
+			Source:  pcmd.source,
+			Digest:  digest,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
 	if err := runHook(p, plugin.Install); err != nil {
 		return err
 	}
@@ -90,3 +120,19 @@ func (pcmd *pluginInstallCmd) run() error {
 	fmt.Fprintf(pcmd.out, "Installed plugin: %s\n", p.Metadata.Name)
 	return nil
 }
+
+// verify checks that an installed plugin is compatible and matches the
+// expected checksum, and returns its digest.
+func (pcmd *pluginInstallCmd) verify(p *plugin.Plugin) (string, error) {
+	if err := checkPluginCompatibility(p); err != nil {
+		return "", err
+	}
+	digest, err := plugin.Digest(p.Dir)
+	if err != nil {
+		return "", err
+	}
+	if pcmd.checksum != "" && pcmd.checksum != digest {
+		return "", fmt.Errorf("plugin %q has digest %s, expected %s", p.Metadata.Name, digest, pcmd.checksum)
+	}
+	return digest, nil
+}
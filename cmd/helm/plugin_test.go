@@ -185,3 +185,43 @@ func TestSetupEnv(t *testing.T) {
 		}
 	}
 }
+
+func TestPluginInstallChecksum(t *testing.T) {
+	home, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home.String())
+
+	source, err := filepath.Abs("testdata/helmhome/plugins/echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := plugin.Digest(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	pcmd := &pluginInstallCmd{source: source, checksum: "sha256:0000", home: home, out: out}
+	if err := pcmd.run(); err == nil || !strings.Contains(err.Error(), "expected sha256:0000") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home.Plugins(), "echo")); !os.IsNotExist(err) {
+		t.Errorf("expected rejected plugin to be removed, got %v", err)
+	}
+
+	pcmd.checksum = digest
+	if err := pcmd.run(); err != nil {
+		t.Fatalf("expected plugin to be installed, got %v", err)
+	}
+
+	lock, err := plugin.LoadLockFile(home.PluginsLockFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := lock.Get("echo")
+	if entry == nil || entry.Source != source || entry.Digest != digest {
+		t.Errorf("expected lock entry for echo from %s with digest %s, got %v", source, digest, entry)
+	}
+}
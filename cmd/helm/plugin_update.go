@@ -31,15 +31,25 @@ import (
 
 type pluginUpdateCmd struct {
 	names []string
+	all   bool
 	home  helmpath.Home
 	out   io.Writer
 }
 
+const pluginUpdateDesc = `
+This command updates plugins installed from a VCS repository to their latest
+version, and records their new digest in $HELM_HOME/plugins/plugins.lock.
+
+Use '--all' to update every installed plugin. Plugins whose source cannot be
+updated, such as local directories, are then skipped.
+`
+
 func newPluginUpdateCmd(out io.Writer) *cobra.Command {
 	pcmd := &pluginUpdateCmd{out: out}
 	cmd := &cobra.Command{
 		Use:   "update <plugin>...",
 		Short: "Update one or more Helm plugins",
+		Long:  pluginUpdateDesc,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return pcmd.complete(args)
 		},
@@ -47,13 +57,17 @@ func newPluginUpdateCmd(out io.Writer) *cobra.Command {
 			return pcmd.run()
 		},
 	}
+	cmd.Flags().BoolVar(&pcmd.all, "all", false, "Update all installed plugins")
 	return cmd
 }
 
 func (pcmd *pluginUpdateCmd) complete(args []string) error {
-	if len(args) == 0 {
+	if len(args) == 0 && !pcmd.all {
 		return errors.New("please provide plugin name to update")
 	}
+	if len(args) > 0 && pcmd.all {
+		return errors.New("plugin names cannot be given with --all")
+	}
 	pcmd.names = args
 	pcmd.home = settings.Home
 	return nil
@@ -68,9 +82,20 @@ func (pcmd *pluginUpdateCmd) run() error {
 	}
 	var errorPlugins []string
 
+	if pcmd.all {
+		for _, p := range plugins {
+			pcmd.names = append(pcmd.names, p.Metadata.Name)
+		}
+	}
+
 	for _, name := range pcmd.names {
 		if found := findPlugin(plugins, name); found != nil {
-			if err := updatePlugin(found, pcmd.home); err != nil {
+			err := updatePlugin(found, pcmd.home)
+			if err == installer.ErrUnknownSource && pcmd.all {
+				fmt.Fprintf(pcmd.out, "Skipped plugin: %s (%v)\n", name, err)
+				continue
+			}
+			if err != nil {
 				errorPlugins = append(errorPlugins, fmt.Sprintf("Failed to update plugin %s, got error (%v)", name, err))
 			} else {
 				fmt.Fprintf(pcmd.out, "Updated plugin: %s\n", name)
@@ -109,5 +134,30 @@ func updatePlugin(p *plugin.Plugin, home helmpath.Home) error {
 		return err
 	}
 
+	// the update cannot be undone, but loadPlugins skips incompatible plugins
+	if err := checkPluginCompatibility(updatedPlugin); err != nil {
+		return err
+	}
+
+	digest, err := plugin.Digest(updatedPlugin.Dir)
+	if err != nil {
+		return err
+	}
+	err = updatePluginsLock(home, func(lock *plugin.LockFile) {
+		entry := &plugin.LockEntry{
+			Name:    updatedPlugin.Metadata.Name,
+			Version: updatedPlugin.Metadata.Version,
+			Digest:  digest,
+		}
+		if old := lock.Get(p.Metadata.Name); old != nil {
+			entry.Source = old.Source
+			lock.Remove(old.Name)
+		}
+		lock.Set(entry)
+	})
+	if err != nil {
+		return err
+	}
+
 	return runHook(updatedPlugin, plugin.Update)
 }
@@ -0,0 +1,238 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	helm_env "k8s.io/helm/pkg/helm/environment"
+)
+
+const profileDesc = `
+Manage the profiles of Helm.
+
+A profile is a named set of settings used together: the kubeconfig file and
+context of a cluster, the namespace of releases, the namespace and address of
+Tiller, the TLS files to connect to it and the Helm home. Instead of juggling
+$HELM_HOME, $TILLER_NAMESPACE and other environment variables, switch between
+profiles with 'helm profile use', or pick one for a single command with
+--profile or $HELM_PROFILE.
+
+The profiles are stored in ~/.helm/profiles.yaml, $HELM_PROFILES overrides
+this location. Flags and their environment variables take precedence over the
+settings of the profile. 'helm env' shows the resulting settings.
+`
+
+const profileSetDesc = `
+Create or update a profile with the settings given on the command line:
+
+	$ helm profile set prod --kube-context gke-prod --namespace apps \
+	    --tiller-namespace tiller --tls-verify --home ~/.helm-prod
+
+The settings of an existing profile that are not given are kept.
+`
+
+func newProfileCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile list|use|set|remove [ARGS]",
+		Short: "Manage named sets of settings for clusters and Helm homes",
+		Long:  profileDesc,
+	}
+
+	cmd.AddCommand(
+		newProfileListCmd(out),
+		newProfileUseCmd(out),
+		newProfileSetCmd(out),
+		newProfileRemoveCmd(out),
+	)
+	return cmd
+}
+
+func newProfileListCmd(out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := helm_env.LoadProfilesFile(settings.ProfilesFile())
+			if err != nil {
+				return err
+			}
+			if len(f.Profiles) == 0 {
+				fmt.Fprintln(out, "No profiles. Create one with 'helm profile set'.")
+				return nil
+			}
+			table := uitable.New()
+			table.AddRow("CURRENT", "NAME", "KUBE CONTEXT", "NAMESPACE", "TILLER NAMESPACE", "HOME")
+			for _, p := range f.Profiles {
+				current := ""
+				if p.Name == f.Current {
+					current = "*"
+				}
+				table.AddRow(current, p.Name, p.KubeContext, p.Namespace, p.TillerNamespace, p.Home)
+			}
+			fmt.Fprintln(out, table)
+			return nil
+		},
+	}
+}
+
+func newProfileUseCmd(out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use NAME",
+		Short: "Make a profile the current one",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "profile name"); err != nil {
+				return err
+			}
+			path := settings.ProfilesFile()
+			f, err := helm_env.LoadProfilesFile(path)
+			if err != nil {
+				return err
+			}
+			if f.Get(args[0]) == nil {
+				return fmt.Errorf("no profile named %q", args[0])
+			}
+			f.Current = args[0]
+			if err := f.WriteFile(path, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Switched to profile %q.\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newProfileSetCmd(out io.Writer) *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "set NAME",
+		Short: "Create or update a profile",
+		Long:  profileSetDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "profile name"); err != nil {
+				return err
+			}
+			path := settings.ProfilesFile()
+			f, err := helm_env.LoadProfilesFile(path)
+			if err != nil {
+				return err
+			}
+			p := f.Get(args[0])
+			if p == nil {
+				p = &helm_env.Profile{Name: args[0]}
+			}
+			if err := setProfileFlags(p, cmd.Flags()); err != nil {
+				return err
+			}
+			f.Set(p)
+			if err := f.WriteFile(path, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Profile %q saved to %s.\n", p.Name, path)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&namespace, "namespace", "", "Namespace of releases when none is given")
+	// The TLS flags are only read back by name, so they are bound to settings
+	// of their own rather than to the ones of the other commands.
+	var tls helm_env.EnvSettings
+	tls.AddFlagsTLS(f)
+	return cmd
+}
+
+// setProfileFlags sets the settings of a profile given as flags on the command
+// line, leaving out the ones coming from environment variables.
+func setProfileFlags(p *helm_env.Profile, fs *pflag.FlagSet) error {
+	for _, name := range helm_env.ProfileFlags {
+		if helm_env.Source(name, fs, nil) != "flag" {
+			continue
+		}
+		if err := p.SetFlag(name, fs.Lookup(name).Value.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newProfileRemoveCmd(out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove NAME",
+		Aliases: []string{"rm"},
+		Short:   "Remove a profile",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "profile name"); err != nil {
+				return err
+			}
+			path := settings.ProfilesFile()
+			f, err := helm_env.LoadProfilesFile(path)
+			if err != nil {
+				return err
+			}
+			if !f.Remove(args[0]) {
+				return fmt.Errorf("no profile named %q", args[0])
+			}
+			if err := f.WriteFile(path, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Profile %q removed.\n", args[0])
+			return nil
+		},
+	}
+}
+
+// applyProfile applies to the settings the profile named by --profile or
+// $HELM_PROFILE, or else the current one of the profiles file, if any.
+func applyProfile(fs *pflag.FlagSet) error {
+	activeProfile = nil
+	path := settings.ProfilesFile()
+	f, err := helm_env.LoadProfilesFile(path)
+	if err != nil {
+		return err
+	}
+	name := settings.Profile
+	if name == "" {
+		name = f.Current
+	}
+	if name == "" {
+		return nil
+	}
+	p := f.Get(name)
+	if p == nil {
+		if settings.Profile == "" {
+			return fmt.Errorf("the current profile %q is not in %s, run 'helm profile use' to change it", name, path)
+		}
+		return fmt.Errorf("no profile named %q in %s", name, path)
+	}
+	settings.ApplyProfile(p, fs)
+	activeProfile = p
+	return nil
+}
+
+// isProfileCmd returns true for the commands managing the profiles, which work
+// even if the current profile is broken.
+func isProfileCmd(cmd *cobra.Command) bool {
+	return strings.HasPrefix(cmd.CommandPath(), "helm profile")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestProfileCmds(t *testing.T) {
+	cleanup := resetEnv()
+	defer cleanup()
+	dir, err := ioutil.TempDir("", "helm-profiles-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("HELM_PROFILES", filepath.Join(dir, "profiles.yaml"))
+
+	var buf bytes.Buffer
+	run := func(cmd *cobra.Command, flags []string, args ...string) string {
+		t.Helper()
+		buf.Reset()
+		if err := cmd.ParseFlags(flags); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.RunE(cmd, args); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	expect := func(expected, got string) {
+		t.Helper()
+		if !regexp.MustCompile(expected).MatchString(got) {
+			t.Errorf("expected\n%q\ngot\n%q", expected, got)
+		}
+	}
+
+	expect(`No profiles`, run(newProfileListCmd(&buf), nil))
+	expect(`Profile "prod" saved`, run(newProfileSetCmd(&buf), []string{"--namespace", "apps", "--tls-verify"}, "prod"))
+	expect(`Profile "dev" saved`, run(newProfileSetCmd(&buf), []string{"--namespace", "sandbox"}, "dev"))
+	if err := newProfileUseCmd(&buf).RunE(nil, []string{"staging"}); err == nil {
+		t.Error("expected an error using a missing profile")
+	}
+	expect(`Switched to profile "prod"`, run(newProfileUseCmd(&buf), nil, "prod"))
+	expect(`\*\s*\tprod\s*\t\s*\tapps\s*\t`, run(newProfileListCmd(&buf), nil))
+
+	fs := pflag.NewFlagSet("testing", pflag.ContinueOnError)
+	settings.AddFlags(fs)
+	if err := applyProfile(fs); err != nil {
+		t.Fatal(err)
+	}
+	if activeProfile == nil || activeProfile.Name != "prod" {
+		t.Fatalf("expected the current profile prod to be applied, got %+v", activeProfile)
+	}
+	if settings.Namespace != "apps" || !settings.TLSVerify {
+		t.Errorf("expected the settings of prod, got namespace %q and TLS verification %t", settings.Namespace, settings.TLSVerify)
+	}
+	expect(`PROFILE:\tprod\n(.|\n)*namespace\s*\tapps\s*\tprofile\s*\n`, run(newEnvCmd(&buf), nil))
+
+	settings.Profile = "dev"
+	if err := applyProfile(fs); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Namespace != "sandbox" {
+		t.Errorf("expected --profile to select dev, got namespace %q", settings.Namespace)
+	}
+	settings.Profile = ""
+
+	expect(`Profile "prod" removed`, run(newProfileRemoveCmd(&buf), nil, "prod"))
+	if err := applyProfile(fs); err != nil || activeProfile != nil {
+		t.Errorf("expected no profile once the current one is removed, got %+v (%v)", activeProfile, err)
+	}
+}
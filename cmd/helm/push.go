@@ -0,0 +1,205 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/repo"
+)
+
+const pushDesc = `
+This command uploads a chart to a chart repository.
+
+The chart is either a packaged chart, or a chart directory which is packaged
+first. The repository is either the name of a repository added with
+'helm repo add', whose credentials and certificates are used, or a URL.
+
+The provenance file of a packaged chart, such as 'mychart-0.1.0.tgz.prov', is
+uploaded along with it. Since the provenance file signs the archive, it is not
+uploaded when the chart is packaged again with '--version'.
+
+Two kinds of repositories are supported, selected with '--kind':
+
+  - chartmuseum (default): the chart is posted to the API of ChartMuseum.
+  - http: the chart is uploaded with HTTP PUT requests, as supported by WebDAV
+    servers, and added to the index.yaml of the repository.
+
+A chart version that already exists in the repository is not replaced, unless
+'--force' is given:
+
+	$ helm push mychart-0.1.0.tgz myrepo --force
+`
+
+type pushCmd struct {
+	chartPath string
+	repo      string
+	kind      string
+	version   string
+	force     bool
+	username  string
+	password  string
+	certFile  string
+	keyFile   string
+	caFile    string
+
+	out  io.Writer
+	home helmpath.Home
+}
+
+func newPushCmd(out io.Writer) *cobra.Command {
+	p := &pushCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "push [flags] CHART REPO",
+		Short: "Upload a chart to a chart repository",
+		Long:  pushDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart", "repository name or URL"); err != nil {
+				return err
+			}
+			p.chartPath = args[0]
+			p.repo = args[1]
+			p.home = settings.Home
+			return p.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&p.kind, "kind", repo.UploaderChartMuseum, "Kind of the repository, chartmuseum or http")
+	f.StringVar(&p.version, "version", "", "Set the version of the chart to this semver version before uploading it")
+	f.BoolVar(&p.force, "force", false, "Replace the chart version if it already exists in the repository")
+	f.StringVar(&p.username, "username", "", "Chart repository username")
+	f.StringVar(&p.password, "password", "", "Chart repository password")
+	f.StringVar(&p.certFile, "cert-file", "", "Identify HTTPS client using this SSL certificate file")
+	f.StringVar(&p.keyFile, "key-file", "", "Identify HTTPS client using this SSL key file")
+	f.StringVar(&p.caFile, "ca-file", "", "Verify certificates of HTTPS-enabled servers using this CA bundle")
+
+	return cmd
+}
+
+func (p *pushCmd) run() error {
+	entry, err := p.repoEntry()
+	if err != nil {
+		return err
+	}
+	uploader, err := repo.NewUploader(p.kind, entry)
+	if err != nil {
+		return err
+	}
+
+	archive, provfile, cleanup, err := p.archive()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := uploader.Upload(archive, provfile, p.force); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "Pushed %s to %s\n", filepath.Base(archive), p.repo)
+	return nil
+}
+
+// repoEntry returns the repository to upload the chart to, with the
+// credentials given by the flags.
+func (p *pushCmd) repoEntry() (*repo.Entry, error) {
+	entry := &repo.Entry{URL: p.repo}
+	if !strings.Contains(p.repo, "://") {
+		f, err := repo.LoadRepositoriesFile(p.home.RepositoryFile())
+		if err != nil {
+			return nil, err
+		}
+		var found bool
+		for _, r := range f.Repositories {
+			if r.Name == p.repo {
+				copied := *r
+				entry, found = &copied, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no repository named %q, add it with 'helm repo add' or give its URL", p.repo)
+		}
+	}
+
+	if p.username != "" {
+		entry.Username = p.username
+	}
+	if p.password != "" {
+		entry.Password = p.password
+	}
+	if p.certFile != "" {
+		entry.CertFile = p.certFile
+	}
+	if p.keyFile != "" {
+		entry.KeyFile = p.keyFile
+	}
+	if p.caFile != "" {
+		entry.CAFile = p.caFile
+	}
+	return entry, nil
+}
+
+// archive returns the chart archive to upload and its provenance file, if
+// any. Chart directories, and archives whose version is overridden, are
+// packaged in a temporary directory removed by the returned function.
+func (p *pushCmd) archive() (string, string, func(), error) {
+	noop := func() {}
+	fi, err := os.Stat(p.chartPath)
+	if err != nil {
+		return "", "", noop, err
+	}
+
+	if !fi.IsDir() && p.version == "" {
+		provfile := p.chartPath + ".prov"
+		if _, err := os.Stat(provfile); err != nil {
+			provfile = ""
+		}
+		return p.chartPath, provfile, noop, nil
+	}
+
+	ch, err := chartutil.Load(p.chartPath)
+	if err != nil {
+		return "", "", noop, err
+	}
+	if p.version != "" {
+		if err := setVersion(ch, p.version); err != nil {
+			return "", "", noop, err
+		}
+	}
+	dir, err := ioutil.TempDir("", "helm-push-")
+	if err != nil {
+		return "", "", noop, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	name, err := chartutil.Save(ch, dir)
+	if err != nil {
+		cleanup()
+		return "", "", noop, err
+	}
+	return name, "", cleanup, nil
+}
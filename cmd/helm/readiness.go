@@ -0,0 +1,209 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// waitTimeouts is a flag holding per-kind wait timeouts, in seconds, given as
+// KIND=DURATION, such as Deployment=10m, or as KIND=SECONDS.
+type waitTimeouts map[string]int64
+
+func (w *waitTimeouts) String() string {
+	pairs := make([]string, 0, len(*w))
+	for k, v := range *w {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (w *waitTimeouts) Type() string {
+	return "waitTimeouts"
+}
+
+func (w *waitTimeouts) Set(value string) error {
+	if *w == nil {
+		*w = waitTimeouts{}
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid wait timeout %q, expected KIND=DURATION", pair)
+		}
+		secs, err := timeconv.ParseSeconds(kv[1])
+		if err != nil {
+			return fmt.Errorf("invalid wait timeout %q, expected KIND=DURATION", pair)
+		}
+		(*w)[kv[0]] = secs
+	}
+	return nil
+}
+
+// waitConditions is a flag holding per-kind wait conditions, given as
+// KIND=PATH==VALUE. Conditions are not split on commas, which JSONPath
+// expressions may hold.
+type waitConditions map[string]string
+
+func (w *waitConditions) String() string {
+	pairs := make([]string, 0, len(*w))
+	for k, v := range *w {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (w *waitConditions) Type() string {
+	return "waitConditions"
+}
+
+func (w *waitConditions) Set(value string) error {
+	if *w == nil {
+		*w = waitConditions{}
+	}
+	kv := strings.SplitN(value, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("invalid wait condition %q, expected KIND=PATH==VALUE", value)
+	}
+	if _, err := kube.ParseWaitCondition(kv[1]); err != nil {
+		return err
+	}
+	(*w)[kv[0]] = kv[1]
+	return nil
+}
+
+// readinessEvent is the JSON form of a readiness report.
+type readinessEvent struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+	Message   string `json:"message"`
+	Time      string `json:"time"`
+}
+
+// printReadinessJSON writes every readiness event as a single line of JSON.
+func printReadinessJSON(out io.Writer, events []*release.ResourceReadiness) error {
+	enc := json.NewEncoder(out)
+	for _, e := range events {
+		ev := readinessEvent{
+			Kind:      e.Kind,
+			Namespace: e.Namespace,
+			Name:      e.Name,
+			Ready:     e.Ready,
+			Message:   e.Message,
+		}
+		if e.Time != nil {
+			ev.Time = timeconv.String(e.Time)
+		}
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readinessStream prints the readiness reports of a release as lines of JSON
+// while Tiller waits on it.
+type readinessStream struct {
+	stop    chan struct{}
+	done    chan struct{}
+	printed int
+}
+
+// streamReadiness starts printing the readiness reports of the operation on
+// a release. A Tiller that cannot stream them is ignored: the reports are
+// then all printed once the operation is over.
+func streamReadiness(out io.Writer, client helm.Interface, rlsName string) *readinessStream {
+	s := &readinessStream{stop: make(chan struct{}), done: make(chan struct{})}
+	reports, errc := client.WatchReadiness(rlsName, s.stop)
+	go func() {
+		defer close(s.done)
+		for r := range reports {
+			if printReadinessJSON(out, []*release.ResourceReadiness{r}) == nil {
+				s.printed++
+			}
+		}
+		<-errc
+	}()
+	return s
+}
+
+// close stops the stream and returns the number of reports it printed.
+func (s *readinessStream) close() int {
+	if s == nil {
+		return 0
+	}
+	close(s.stop)
+	<-s.done
+	return s.printed
+}
+
+// formatReadiness renders the last reported state of each resource.
+func formatReadiness(events []*release.ResourceReadiness) string {
+	latest := map[string]*release.ResourceReadiness{}
+	var keys []string
+	for _, e := range events {
+		key := e.Kind + "/" + e.Namespace + "/" + e.Name
+		if _, ok := latest[key]; !ok {
+			keys = append(keys, key)
+		}
+		latest[key] = e
+	}
+
+	table := uitable.New()
+	table.AddRow("KIND", "NAME", "READY", "MESSAGE")
+	for _, k := range keys {
+		e := latest[k]
+		table.AddRow(e.Kind, e.Name, e.Ready, e.Message)
+	}
+	return table.String()
+}
+
+// printReadinessAndStatus writes the readiness reports of a release that were
+// not printed while waiting as JSON lines, followed by the release status as a
+// single JSON document.
+func printReadinessAndStatus(out io.Writer, client helm.Interface, rel *release.Release, printed int) error {
+	if reports := rel.Info.Readiness; printed < len(reports) {
+		if err := printReadinessJSON(out, reports[printed:]); err != nil {
+			return err
+		}
+	}
+	status, err := client.ReleaseStatus(rel.Name)
+	if err != nil {
+		return prettyError(err)
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("Failed to Marshal JSON output: %s", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestWaitTimeoutsSet(t *testing.T) {
+	var w waitTimeouts
+	if err := w.Set("Deployment=600,Service=60"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Set("Job=30s"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.String(), "Deployment=600,Job=30,Service=60"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	for _, bad := range []string{"Deployment", "=60", "Service=soon", "Service=-1"} {
+		if err := w.Set(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestWaitConditionsSet(t *testing.T) {
+	var w waitConditions
+	if err := w.Set(`Certificate=status.conditions[?(@.type=="Ready")].status==True`); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Set("Database=status.phase==Running"); err != nil {
+		t.Fatal(err)
+	}
+	if got := w["Certificate"]; got != `status.conditions[?(@.type=="Ready")].status==True` {
+		t.Errorf("expected the condition to be kept whole, got %q", got)
+	}
+	if got, want := w.String(), `Certificate=status.conditions[?(@.type=="Ready")].status==True,Database=status.phase==Running`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	for _, bad := range []string{"Certificate", "=status.phase==Running", "Database=status.phase", "Database===Running"} {
+		if err := w.Set(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestFormatReadiness(t *testing.T) {
+	events := []*release.ResourceReadiness{
+		{Kind: "Deployment", Namespace: "default", Name: "web", Message: "0/2 pods ready"},
+		{Kind: "Service", Namespace: "default", Name: "web", Ready: true},
+		{Kind: "Deployment", Namespace: "default", Name: "web", Ready: true, Message: "2/2 pods ready"},
+	}
+	out := formatReadiness(events)
+	if strings.Contains(out, "0/2 pods ready") {
+		t.Errorf("expected only the last state of each resource, got:\n%s", out)
+	}
+	if strings.Count(out, "\n") != 2 {
+		t.Errorf("expected a header and two rows, got:\n%s", out)
+	}
+
+	var buf bytes.Buffer
+	if err := printReadinessJSON(&buf, events); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("expected 3 JSON lines, got %d", lines)
+	}
+}
+
+func TestStreamReadiness(t *testing.T) {
+	rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "flummoxed-chickadee"})
+	rel.Info.Readiness = []*release.ResourceReadiness{
+		{Kind: "Deployment", Namespace: "default", Name: "web", Message: "0/2 pods ready"},
+		{Kind: "Service", Namespace: "default", Name: "web", Ready: true},
+		{Kind: "Deployment", Namespace: "default", Name: "web", Ready: true, Message: "2/2 pods ready"},
+	}
+	c := &helm.FakeClient{Rels: []*release.Release{rel}, Readiness: rel.Info.Readiness[:2]}
+
+	var buf bytes.Buffer
+	printed := streamReadiness(&buf, c, rel.Name).close()
+	if err := printReadinessAndStatus(&buf, c, rel, printed); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 readiness lines and the status, got:\n%s", buf.String())
+	}
+	for i, s := range []string{"0/2 pods ready", `"kind":"Service"`, "2/2 pods ready", `"name":"flummoxed-chickadee"`} {
+		if !strings.Contains(lines[i], s) {
+			t.Errorf("expected line %d to contain %q, got %s", i+1, s, lines[i])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+var releaseHelp = `
+This command consists of multiple subcommands to manage the metadata and the
+locks of releases, and to move them between clusters or storage backends.
+
+Example usage:
+    $ helm release label [RELEASE] frozen=true
+    $ helm release annotate [RELEASE] incident=INC-1234
+    $ helm release unlock [RELEASE]
+    $ helm release export [RELEASE] > release.tgz
+    $ helm release import release.tgz
+`
+
+func newReleaseCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release [FLAGS] annotate|label|unlock|export|import [ARGS]",
+		Short: "Manage the labels, annotations and locks of releases, export and import them",
+		Long:  releaseHelp,
+	}
+
+	cmd.AddCommand(newReleaseMetadataCmd(client, out, labelMetadata))
+	cmd.AddCommand(newReleaseMetadataCmd(client, out, annotationMetadata))
+	cmd.AddCommand(newReleaseUnlockCmd(client, out))
+	cmd.AddCommand(newReleaseExportCmd(client, out))
+	cmd.AddCommand(newReleaseImportCmd(client, out))
+
+	return cmd
+}
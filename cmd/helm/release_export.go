@@ -0,0 +1,116 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+const releaseExportHelp = `
+This command writes every revision of a release, with its chart, values and
+manifest, to a gzipped tar archive. Unless --file is given, the archive is
+written to standard output:
+
+    $ helm release export happy-panda > happy-panda.tgz
+
+The archive can be restored with 'helm release import', by another Tiller or
+on top of another storage backend. The resources of the release are not part
+of the archive.
+`
+
+type releaseExportCmd struct {
+	release string
+	file    string
+	quiet   bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newReleaseExportCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	e := &releaseExportCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "export [flags] RELEASE_NAME",
+		Short:   "Write the history of a release to an archive",
+		Long:    releaseExportHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			e.release = args[0]
+			e.client = ensureHelmClient(e.client)
+			return e.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.StringVar(&e.file, "file", "", "Write the archive to this file instead of standard output")
+	f.BoolVar(&e.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (e *releaseExportCmd) run() error {
+	res, err := e.client.ReleaseHistory(e.release, helm.WithMaxHistory(math.MaxInt32))
+	if err != nil {
+		return prettyError(err)
+	}
+	var rels []*release.Release
+	for _, r := range res.Releases {
+		if r.Name == e.release {
+			rels = append(rels, r)
+		}
+	}
+	if len(rels) == 0 {
+		return fmt.Errorf("release %q has no revisions", e.release)
+	}
+
+	if e.file == "" {
+		return releaseutil.WriteArchive(e.out, rels)
+	}
+	f, err := os.Create(e.file)
+	if err != nil {
+		return err
+	}
+	err = releaseutil.WriteArchive(f, rels)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(e.file)
+		return err
+	}
+	printMessage(e.out, e.quiet, msgReleaseExported, e.release, e.file, len(rels))
+	return nil
+}
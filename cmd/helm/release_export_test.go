@@ -0,0 +1,120 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestReleaseExportCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name: "missing release",
+			args: []string{"no-such-release"},
+			err:  true,
+		},
+		{
+			name: "release required",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newReleaseExportCmd(c, out)
+	})
+}
+
+func TestReleaseExportImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-release-export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "thomas-guide.tgz")
+
+	src := &helm.FakeClient{Rels: []*release.Release{
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", Version: 2}),
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas-guide", Version: 1}),
+	}}
+	var buf bytes.Buffer
+	cmd := newReleaseExportCmd(src, &buf)
+	cmd.ParseFlags([]string{"--file", archive})
+	if err := cmd.RunE(cmd, []string{"thomas-guide"}); err != nil {
+		t.Fatalf("Failed export: %s", err)
+	}
+	if want := "Release \"thomas-guide\" has been exported to " + archive + " with its 2 revisions.\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	dst := &helm.FakeClient{}
+	buf.Reset()
+	cmd = newReleaseImportCmd(dst, &buf)
+	if err := cmd.RunE(cmd, []string{archive}); err != nil {
+		t.Fatalf("Failed import: %s", err)
+	}
+	if want := "Release \"thomas-guide\" has been imported in namespace \"default\" with its 2 revisions, revision 2 is DEPLOYED.\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+	if len(dst.Rels) != 2 || dst.Rels[0].Version != 1 || dst.Rels[1].Version != 2 {
+		t.Errorf("expected revisions 1 and 2 to be imported, got %v", dst.Rels)
+	}
+	if dst.Rels[1].Manifest != helm.MockManifest {
+		t.Errorf("expected the manifest to be imported, got %q", dst.Rels[1].Manifest)
+	}
+
+	// the release now exists
+	if err := cmd.RunE(cmd, []string{archive}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected importing the release again to fail, got %v", err)
+	}
+}
+
+func TestReleaseImportCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name: "missing archive",
+			args: []string{"testdata/no-such-archive.tgz"},
+			err:  true,
+		},
+		{
+			name: "not an archive",
+			args: []string{"testdata/repositories.yaml"},
+			err:  true,
+		},
+		{
+			name: "archive required",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newReleaseImportCmd(c, out)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+const releaseImportHelp = `
+This command restores a release from an archive written by 'helm release
+export', keeping the numbers of its revisions. Use '-' to read the archive from
+standard input:
+
+    $ helm release import happy-panda.tgz
+
+Only the records of the release are restored, in the namespace it was exported
+from: its resources are neither created nor changed. They are expected to exist
+in the cluster already, or to be created by the next upgrade or rollback of
+the release. A release that already exists cannot be imported.
+`
+
+type releaseImportCmd struct {
+	file   string
+	quiet  bool
+	out    io.Writer
+	client helm.Interface
+}
+
+func newReleaseImportCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	i := &releaseImportCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "import [flags] FILE",
+		Short:   "Restore the history of a release from an archive",
+		Long:    releaseImportHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "archive file"); err != nil {
+				return err
+			}
+			i.file = args[0]
+			i.client = ensureHelmClient(i.client)
+			return i.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.BoolVar(&i.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (i *releaseImportCmd) run() error {
+	in := os.Stdin
+	if i.file != "-" {
+		f, err := os.Open(i.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	rels, err := releaseutil.ReadArchive(in)
+	if err != nil {
+		return err
+	}
+
+	name := rels[0].Name
+	res, err := i.client.ImportRelease(name, rels)
+	if err != nil {
+		return prettyError(err)
+	}
+	r := res.Release
+	printMessage(i.out, i.quiet, msgReleaseImported, name, r.Namespace, len(rels), r.Version, r.Info.Status.Code)
+	return nil
+}
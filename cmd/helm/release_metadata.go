@@ -0,0 +1,204 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const (
+	labelMetadata      = "label"
+	annotationMetadata = "annotation"
+)
+
+// metadataVerbs maps the kinds of metadata to their subcommand names.
+var metadataVerbs = map[string]string{
+	labelMetadata:      "label",
+	annotationMetadata: "annotate",
+}
+
+var releaseMetadataHelp = `
+This command adds, updates or removes the %[1]ss of a release.
+
+Each argument after the release name is either KEY=VALUE, which sets the %[1]s,
+or KEY-, which removes it. Without arguments, the current %[1]ss are printed.
+
+The %[1]ss are stored with the release record, without creating a new revision,
+and are kept by later upgrades and rollbacks. Use '--propagate' to apply them to
+the Kubernetes resources of the release as well.
+
+	$ helm release %[2]s my-release frozen=true owner-
+`
+
+type releaseMetadataCmd struct {
+	kind      string
+	release   string
+	set       map[string]string
+	remove    []string
+	revision  int32
+	propagate bool
+	quiet     bool
+	out       io.Writer
+	client    helm.Interface
+}
+
+func newReleaseMetadataCmd(client helm.Interface, out io.Writer, kind string) *cobra.Command {
+	m := &releaseMetadataCmd{
+		kind:   kind,
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     fmt.Sprintf("%s [flags] RELEASE_NAME [KEY=VALUE|KEY-]...", metadataVerbs[kind]),
+		Short:   fmt.Sprintf("Add, update or remove %ss of a release", kind),
+		Long:    fmt.Sprintf(releaseMetadataHelp, kind, metadataVerbs[kind]),
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			m.release = args[0]
+			var err error
+			if m.set, m.remove, err = parseMetadataArgs(args[1:]); err != nil {
+				return err
+			}
+			m.client = ensureHelmClient(m.client)
+			return m.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.Int32Var(&m.revision, "revision", 0, fmt.Sprintf("Change the %ss of the named release revision instead of the latest", kind))
+	f.BoolVar(&m.propagate, "propagate", false, fmt.Sprintf("Apply the %ss to the resources of the release as well", kind))
+	f.BoolVar(&m.quiet, "quiet", false, fmt.Sprintf("Print nothing after changing the %ss", kind))
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (m *releaseMetadataCmd) run() error {
+	var opt helm.MetadataOption
+	if m.kind == labelMetadata {
+		opt = helm.MetadataLabels(m.set, m.remove)
+	} else {
+		opt = helm.MetadataAnnotations(m.set, m.remove)
+	}
+	res, err := m.client.UpdateReleaseMetadata(m.release,
+		opt,
+		helm.MetadataReleaseVersion(m.revision),
+		helm.MetadataPropagate(m.propagate),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+
+	current := res.Release.Labels
+	if m.kind == annotationMetadata {
+		current = res.Release.Annotations
+	}
+	if len(m.set) > 0 || len(m.remove) > 0 {
+		if m.quiet {
+			return nil
+		}
+		printMessage(m.out, m.quiet, msgReleaseMetadataSet, m.release)
+	}
+	fmt.Fprint(m.out, formatMetadata(current))
+	return nil
+}
+
+// parseMetadataArgs splits KEY=VALUE arguments from KEY- arguments.
+func parseMetadataArgs(args []string) (map[string]string, []string, error) {
+	set := map[string]string{}
+	var remove []string
+	for _, arg := range args {
+		switch {
+		case strings.Contains(arg, "="):
+			kv := strings.SplitN(arg, "=", 2)
+			if kv[0] == "" {
+				return nil, nil, fmt.Errorf("invalid argument %q, expected KEY=VALUE or KEY-", arg)
+			}
+			set[kv[0]] = kv[1]
+		case strings.HasSuffix(arg, "-") && len(arg) > 1:
+			remove = append(remove, strings.TrimSuffix(arg, "-"))
+		default:
+			return nil, nil, fmt.Errorf("invalid argument %q, expected KEY=VALUE or KEY-", arg)
+		}
+	}
+	for _, k := range remove {
+		if _, ok := set[k]; ok {
+			return nil, nil, errors.New("cannot both set and remove " + k)
+		}
+	}
+	return set, remove, nil
+}
+
+// labelsFlag is a flag holding release labels given as KEY=VALUE.
+type labelsFlag map[string]string
+
+func (l *labelsFlag) String() string {
+	pairs := make([]string, 0, len(*l))
+	for k, v := range *l {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (l *labelsFlag) Type() string {
+	return "labels"
+}
+
+func (l *labelsFlag) Set(value string) error {
+	if *l == nil {
+		*l = labelsFlag{}
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid label %q, expected KEY=VALUE", pair)
+		}
+		(*l)[kv[0]] = kv[1]
+	}
+	return nil
+}
+
+// formatMetadata renders metadata as sorted KEY=VALUE lines.
+func formatMetadata(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, m[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestReleaseLabelCmd(t *testing.T) {
+	labeled := func() *release.Release {
+		r := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})
+		r.Labels = map[string]string{"owner": "alice"}
+		return r
+	}
+
+	tests := []releaseCase{
+		{
+			name:     "print labels",
+			args:     []string{"thomas-guide"},
+			expected: "^owner=alice\n$",
+			rels:     []*release.Release{labeled()},
+		},
+		{
+			name:     "set and remove labels",
+			args:     []string{"thomas-guide", "frozen=true", "owner-"},
+			expected: "Release \"thomas-guide\" has been updated.\nfrozen=true\n$",
+			rels:     []*release.Release{labeled()},
+		},
+		{
+			name: "invalid argument",
+			args: []string{"thomas-guide", "frozen"},
+			err:  true,
+			rels: []*release.Release{labeled()},
+		},
+		{
+			name: "release required",
+			args: []string{},
+			err:  true,
+		},
+		{
+			name: "release not found",
+			args: []string{"no-such-release", "frozen=true"},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newReleaseMetadataCmd(c, out, labelMetadata)
+	})
+}
+
+func TestReleaseAnnotateCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "set annotation",
+			args:     []string{"thomas-guide", "incident=INC-1234"},
+			expected: "incident=INC-1234\n$",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newReleaseMetadataCmd(c, out, annotationMetadata)
+	})
+}
+
+func TestParseMetadataArgs(t *testing.T) {
+	if _, _, err := parseMetadataArgs([]string{"a=b", "a-"}); err == nil {
+		t.Error("expected setting and removing the same key to fail")
+	}
+	set, remove, err := parseMetadataArgs([]string{"a=b=c", "d-"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set["a"] != "b=c" || len(remove) != 1 || remove[0] != "d" {
+		t.Errorf("unexpected result: %v %v", set, remove)
+	}
+}
+
+func TestLabelsFlag(t *testing.T) {
+	var l labelsFlag
+	if err := l.Set("team=web,env=prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Set("tier=frontend"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := l.String(), "env=prod,team=web,tier=frontend"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	for _, bad := range []string{"team", "=web"} {
+		if err := l.Set(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
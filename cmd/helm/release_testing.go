@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	helm_env "k8s.io/helm/pkg/helm/environment"
 	"k8s.io/helm/pkg/proto/hapi/release"
 )
 
@@ -31,6 +32,15 @@ The test command runs the tests for a release.
 
 The argument this command takes is the name of a deployed release.
 The tests to be run are defined in the chart that was installed.
+
+Tests run one at a time unless '--parallel' is given. '--parallel=N' runs up to
+N test pods at once, while '--parallel' alone runs as many as Tiller allows.
+Hooks annotated with 'test-setup' run before the tests, and hooks annotated
+with 'test-teardown' run after them, even if a test fails.
+
+The logs of each test pod are stored with the release and can be shown with
+'helm get tests'. Use '--logs' to print them as each test completes, and
+'--output junit' to print the results as a JUnit XML report.
 `
 
 type releaseTestCmd struct {
@@ -39,7 +49,9 @@ type releaseTestCmd struct {
 	client   helm.Interface
 	timeout  int64
 	cleanup  bool
-	parallel bool
+	parallel int
+	logs     bool
+	output   string
 }
 
 func newReleaseTestCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -66,9 +78,12 @@ func newReleaseTestCmd(c helm.Interface, out io.Writer) *cobra.Command {
 
 	f := cmd.Flags()
 	settings.AddFlagsTLS(f)
-	f.Int64Var(&rlsTest.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	helm_env.TimeoutVar(f, &rlsTest.timeout, "timeout", 300, "Time to wait for any individual Kubernetes operation (like Jobs for hooks), as a duration such as 5m30s or in seconds")
 	f.BoolVar(&rlsTest.cleanup, "cleanup", false, "Delete test pods upon completion")
-	f.BoolVar(&rlsTest.parallel, "parallel", false, "Run test pods in parallel")
+	f.IntVar(&rlsTest.parallel, "parallel", 1, "Number of test pods to run at once. Without a value, runs as many as Tiller allows")
+	f.Lookup("parallel").NoOptDefVal = "0"
+	f.BoolVar(&rlsTest.logs, "logs", false, "Print the logs of each test pod when it completes")
+	f.StringVarP(&rlsTest.output, "output", "o", "", "Output the test results in the specified format (junit)")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -77,17 +92,37 @@ func newReleaseTestCmd(c helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (t *releaseTestCmd) run() (err error) {
+	if t.parallel < 0 {
+		return fmt.Errorf("--parallel must not be negative")
+	}
+	if t.output != "" && t.output != "junit" {
+		return fmt.Errorf("unknown output format %q", t.output)
+	}
+	junit := t.output == "junit"
+
+	var parallelism uint32
+	if t.parallel > 1 {
+		parallelism = uint32(t.parallel)
+	}
+
 	c, errc := t.client.RunReleaseTest(
 		t.name,
 		helm.ReleaseTestTimeout(t.timeout),
 		helm.ReleaseTestCleanup(t.cleanup),
-		helm.ReleaseTestParallel(t.parallel),
+		helm.ReleaseTestParallel(t.parallel != 1),
+		helm.ReleaseTestParallelism(parallelism),
+		helm.ReleaseTestLogs(t.logs && !junit),
 	)
 	testErr := &testErr{}
 
 	for {
 		select {
 		case err := <-errc:
+			if prettyError(err) == nil && junit {
+				if err := t.report(); err != nil {
+					return err
+				}
+			}
 			if prettyError(err) == nil && testErr.failed > 0 {
 				return testErr.Error()
 			}
@@ -101,13 +136,31 @@ func (t *releaseTestCmd) run() (err error) {
 				testErr.failed++
 			}
 
+			if junit {
+				continue
+			}
 			fmt.Fprintf(t.out, res.Msg+"\n")
-
+			if res.Logs != "" {
+				fmt.Fprintln(t.out, indentLogs(res.Logs))
+			}
 		}
 	}
 
 }
 
+// report prints the stored results of the test run as a JUnit XML report.
+func (t *releaseTestCmd) report() error {
+	res, err := t.client.ReleaseContent(t.name)
+	if err != nil {
+		return prettyError(err)
+	}
+	suite := res.Release.Info.Status.LastTestSuiteRun
+	if suite == nil {
+		suite = &release.TestSuite{}
+	}
+	return writeJUnit(t.out, t.name, suite)
+}
+
 type testErr struct {
 	failed int
 }
@@ -75,6 +75,35 @@ func TestReleaseTesting(t *testing.T) {
 				"PASSED: feel free to party again":            release.TestRun_SUCCESS},
 			err: true,
 		},
+		{
+			name:      "parallel test with a limit",
+			args:      []string{"example-release"},
+			flags:     []string{"--parallel=2"},
+			responses: map[string]release.TestRun_Status{"PASSED: green lights everywhere": release.TestRun_SUCCESS},
+			expected:  "PASSED: green lights everywhere",
+		},
+		{
+			name:      "negative parallelism",
+			args:      []string{"example-release"},
+			flags:     []string{"--parallel=-1"},
+			responses: map[string]release.TestRun_Status{"PASSED: green lights everywhere": release.TestRun_SUCCESS},
+			err:       true,
+		},
+		{
+			name:      "junit output",
+			args:      []string{"aeneas"},
+			flags:     []string{"--output", "junit"},
+			responses: map[string]release.TestRun_Status{"PASSED: green lights everywhere": release.TestRun_SUCCESS},
+			rels:      []*release.Release{testedReleaseMock("aeneas")},
+			expected:  `^<\?xml version="1.0" encoding="UTF-8"\?>\n<testsuites>`,
+		},
+		{
+			name:      "unknown output format",
+			args:      []string{"example-release"},
+			flags:     []string{"--output", "tap"},
+			responses: map[string]release.TestRun_Status{"PASSED: green lights everywhere": release.TestRun_SUCCESS},
+			err:       true,
+		},
 	}
 
 	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
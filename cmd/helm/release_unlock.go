@@ -0,0 +1,84 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const releaseUnlockHelp = `
+This command removes the lock on a release.
+
+Tiller locks a release for the duration of an install, upgrade, rollback or
+delete, so that a concurrent operation on the release fails right away instead
+of corrupting its history. The lock is removed when the operation finishes and
+expires on its own once the timeouts of the operation have passed.
+
+If Tiller stopped in the middle of an operation, its lock can be removed before
+it expires with this command. Make sure the operation is really over first.
+`
+
+type releaseUnlockCmd struct {
+	release string
+	quiet   bool
+	out     io.Writer
+	client  helm.Interface
+}
+
+func newReleaseUnlockCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	u := &releaseUnlockCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "unlock [flags] RELEASE_NAME",
+		Short:   "Remove the lock left on a release by an unfinished operation",
+		Long:    releaseUnlockHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			u.release = args[0]
+			u.client = ensureHelmClient(u.client)
+			return u.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.BoolVar(&u.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (u *releaseUnlockCmd) run() error {
+	res, err := u.client.UnlockRelease(u.release)
+	if err != nil {
+		return prettyError(err)
+	}
+	printMessage(u.out, u.quiet, msgUnlocked, u.release, res.Operation, res.Holder, res.Acquired)
+	return nil
+}
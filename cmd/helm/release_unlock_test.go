@@ -0,0 +1,60 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestReleaseUnlockCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "unlock a stuck upgrade",
+			args:     []string{"thomas-guide"},
+			expected: "Release \"thomas-guide\" has been unlocked, it was locked for upgrade by tiller-deploy-0a1b2c3d since 2016-01-16T00:00:00Z.\n",
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", StatusCode: release.Status_PENDING_UPGRADE}),
+			},
+		},
+		{
+			name: "not locked",
+			args: []string{"thomas-guide"},
+			err:  true,
+			rels: []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name: "missing release",
+			args: []string{"no-such-release"},
+			err:  true,
+		},
+		{
+			name: "release required",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newReleaseUnlockCmd(c, out)
+	})
+}
@@ -17,43 +17,69 @@ limitations under the License.
 package main
 
 import (
-	"context"
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 	"syscall"
 	"time"
 
 	"golang.org/x/crypto/ssh/terminal"
 
-	"github.com/gofrs/flock"
 	"github.com/spf13/cobra"
 
-	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/action"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/repo"
 )
 
+const repoAddDesc = `
+Add a chart repository under the given name.
+
+When a repository with the same name but another URL already exists, the
+command asks whether to overwrite it, add the new repository under another
+name, or abort. Without a terminal to ask on, it fails unless '--force-update'
+is given to overwrite the existing repository.
+`
+
 type repoAddCmd struct {
-	name     string
-	url      string
-	username string
-	password string
-	home     helmpath.Home
-	noupdate bool
+	name        string
+	url         string
+	username    string
+	password    string
+	home        helmpath.Home
+	noupdate    bool
+	forceUpdate bool
 
 	certFile string
 	keyFile  string
 	caFile   string
 
-	out io.Writer
+	proxy          string
+	connectTimeout time.Duration
+	readTimeout    time.Duration
+	retries        int
+
+	// interactive is whether a conflicting repository can be resolved by
+	// asking on in.
+	interactive bool
+	in          io.Reader
+	out         io.Writer
 }
 
 func newRepoAddCmd(out io.Writer) *cobra.Command {
-	add := &repoAddCmd{out: out}
+	add := &repoAddCmd{
+		in:          os.Stdin,
+		out:         out,
+		interactive: terminal.IsTerminal(int(os.Stdin.Fd())),
+	}
 
 	cmd := &cobra.Command{
 		Use:   "add [flags] [NAME] [URL]",
 		Short: "Add a chart repository",
+		Long:  repoAddDesc,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := checkArgsLength(len(args), "name for the chart repository", "the url of the chart repository"); err != nil {
 				return err
@@ -71,14 +97,26 @@ func newRepoAddCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&add.username, "username", "", "Chart repository username")
 	f.StringVar(&add.password, "password", "", "Chart repository password")
 	f.BoolVar(&add.noupdate, "no-update", false, "Raise error if repo is already registered")
+	f.BoolVar(&add.forceUpdate, "force-update", false, "Replace a repository of the same name with another URL without asking")
 	f.StringVar(&add.certFile, "cert-file", "", "Identify HTTPS client using this SSL certificate file")
 	f.StringVar(&add.keyFile, "key-file", "", "Identify HTTPS client using this SSL key file")
 	f.StringVar(&add.caFile, "ca-file", "", "Verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.StringVar(&add.proxy, "proxy", "", "URL of the proxy to reach the repository through, instead of the one of $HTTPS_PROXY")
+	f.DurationVar(&add.connectTimeout, "connect-timeout", 0, "Time to connect to the repository before failing, such as 10s (default 30s)")
+	f.DurationVar(&add.readTimeout, "read-timeout", 0, "Time to wait for the responses of the repository before failing, such as 1m (default no limit)")
+	f.IntVar(&add.retries, "retries", 0, "Number of times failed requests to the repository are retried, -1 to never retry (default 3)")
 
 	return cmd
 }
 
 func (a *repoAddCmd) run() error {
+	if a.noupdate && a.forceUpdate {
+		return errors.New("--no-update and --force-update cannot be used together")
+	}
+	if err := a.resolveConflict(); err != nil {
+		return err
+	}
+
 	if a.username != "" && a.password == "" {
 		fmt.Fprint(a.out, "Password:")
 		password, err := readPassword()
@@ -89,13 +127,93 @@ func (a *repoAddCmd) run() error {
 		a.password = password
 	}
 
-	if err := addRepository(a.name, a.url, a.username, a.password, a.home, a.certFile, a.keyFile, a.caFile, a.noupdate); err != nil {
+	c := repo.Entry{
+		Name:     a.name,
+		URL:      a.url,
+		Username: a.username,
+		Password: a.password,
+		CertFile: a.certFile,
+		KeyFile:  a.keyFile,
+		CAFile:   a.caFile,
+		Proxy:    a.proxy,
+		Retries:  a.retries,
+	}
+	if a.connectTimeout > 0 {
+		c.ConnectTimeout = a.connectTimeout.String()
+	}
+	if a.readTimeout > 0 {
+		c.ReadTimeout = a.readTimeout.String()
+	}
+	cfg := newActionConfig(a.out)
+	cfg.Home = a.home
+	if err := cfg.AddRepository(&c, a.noupdate); err != nil {
 		return err
 	}
 	fmt.Fprintf(a.out, "%q has been added to your repositories\n", a.name)
 	return nil
 }
 
+// resolveConflict handles an existing repository of the same name with
+// another URL. It returns nil when the repository can be added as a.name,
+// possibly after renaming it.
+func (a *repoAddCmd) resolveConflict() error {
+	// --no-update fails on any existing repository of the same name
+	if a.noupdate || a.forceUpdate {
+		return nil
+	}
+	f, err := repo.LoadRepositoriesFile(a.home.RepositoryFile())
+	if err != nil {
+		return err
+	}
+
+	answers := bufio.NewReader(a.in)
+	for {
+		existing := f.Get(a.name)
+		if existing == nil || sameURL(existing.URL, a.url) {
+			return nil
+		}
+		if !a.interactive {
+			return fmt.Errorf("repository %q already exists with URL %s, use --force-update to replace it", a.name, existing.URL)
+		}
+
+		fmt.Fprintf(a.out, "Repository %q already exists with URL %s.\n[o]verwrite, [r]ename or [a]bort? ", a.name, existing.URL)
+		answer, err := readAnswer(answers)
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(answer) {
+		case "o", "overwrite":
+			return nil
+		case "r", "rename":
+			fmt.Fprint(a.out, "New name: ")
+			name, err := readAnswer(answers)
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				return errors.New("aborted: no repository name given")
+			}
+			a.name = name
+		default:
+			return fmt.Errorf("aborted: repository %q was not changed", a.name)
+		}
+	}
+}
+
+// sameURL compares the URLs of repositories, ignoring a trailing slash.
+func sameURL(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// readAnswer reads a line answering a prompt.
+func readAnswer(r *bufio.Reader) (string, error) {
+	answer, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
 func readPassword() (string, error) {
 	password, err := terminal.ReadPassword(int(syscall.Stdin))
 	if err != nil {
@@ -105,56 +223,15 @@ func readPassword() (string, error) {
 }
 
 func addRepository(name, url, username, password string, home helmpath.Home, certFile, keyFile, caFile string, noUpdate bool) error {
-	f, err := repo.LoadRepositoriesFile(home.RepositoryFile())
-	if err != nil {
-		return err
-	}
-
-	if noUpdate && f.Has(name) {
-		return fmt.Errorf("repository name (%s) already exists, please specify a different name", name)
-	}
-
-	cif := home.CacheIndex(name)
-	c := repo.Entry{
+	cfg := action.NewConfiguration(settings)
+	cfg.Home = home
+	return cfg.AddRepository(&repo.Entry{
 		Name:     name,
-		Cache:    cif,
 		URL:      url,
 		Username: username,
 		Password: password,
 		CertFile: certFile,
 		KeyFile:  keyFile,
 		CAFile:   caFile,
-	}
-
-	r, err := repo.NewChartRepository(&c, getter.All(settings))
-	if err != nil {
-		return err
-	}
-
-	if err := r.DownloadIndexFile(home.Cache()); err != nil {
-		return fmt.Errorf("Looks like %q is not a valid chart repository or cannot be reached: %s", url, err.Error())
-	}
-
-	// Lock the repository file for concurrent goroutines or processes synchronization
-	fileLock := flock.New(home.RepositoryFile())
-	lockCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	locked, err := fileLock.TryLockContext(lockCtx, time.Second)
-	if err == nil && locked {
-		defer fileLock.Unlock()
-	}
-	if err != nil {
-		return err
-	}
-
-	// Re-read the repositories file before updating it as its content may have been changed
-	// by a concurrent execution after the first read and before being locked
-	f, err = repo.LoadRepositoriesFile(home.RepositoryFile())
-	if err != nil {
-		return err
-	}
-
-	f.Update(&c)
-
-	return f.WriteFile(home.RepositoryFile(), 0644)
+	}, noUpdate)
 }
@@ -106,6 +106,102 @@ func TestRepoAdd(t *testing.T) {
 		t.Errorf("Duplicate repository name was added")
 	}
 }
+func TestRepoAddConflict(t *testing.T) {
+	ts, thome, err := repotest.NewTempServer("testdata/testserver/*.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cleanup := resetEnv()
+	defer func() {
+		ts.Stop()
+		os.RemoveAll(thome.String())
+		cleanup()
+	}()
+	if err := ensureTestHome(thome, t); err != nil {
+		t.Fatal(err)
+	}
+	settings.Home = thome
+
+	const otherURL = "https://example.com/other"
+	tests := []struct {
+		name        string
+		interactive bool
+		forceUpdate bool
+		answers     string
+		err         string
+		// URLs of the repositories after the command
+		urls map[string]string
+	}{
+		{
+			name: "fails without a terminal",
+			err:  "use --force-update",
+			urls: map[string]string{testName: otherURL},
+		},
+		{
+			name:        "overwrites with --force-update",
+			forceUpdate: true,
+			urls:        map[string]string{testName: ts.URL()},
+		},
+		{
+			name:        "aborts",
+			interactive: true,
+			answers:     "a\n",
+			err:         "aborted",
+			urls:        map[string]string{testName: otherURL},
+		},
+		{
+			name:        "overwrites",
+			interactive: true,
+			answers:     "o\n",
+			urls:        map[string]string{testName: ts.URL()},
+		},
+		{
+			name:        "renames",
+			interactive: true,
+			answers:     "r\nRenamed\n",
+			urls:        map[string]string{testName: otherURL, "Renamed": ts.URL()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := repo.NewRepoFile()
+			f.Add(&repo.Entry{Name: testName, URL: otherURL, Cache: thome.CacheIndex(testName)})
+			if err := f.WriteFile(thome.RepositoryFile(), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out strings.Builder
+			add := &repoAddCmd{
+				name:        testName,
+				url:         ts.URL(),
+				home:        thome,
+				forceUpdate: tt.forceUpdate,
+				interactive: tt.interactive,
+				in:          strings.NewReader(tt.answers),
+				out:         &out,
+			}
+			err := add.run()
+			if tt.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("Expected error containing %q, got %v", tt.err, err)
+			}
+
+			if f, err = repo.LoadRepositoriesFile(thome.RepositoryFile()); err != nil {
+				t.Fatal(err)
+			}
+			for name, url := range tt.urls {
+				if e := f.Get(name); e == nil || e.URL != url {
+					t.Errorf("Expected repository %s with URL %s, got %v", name, url, e)
+				}
+			}
+		})
+	}
+}
+
 func TestRepoAddConcurrentGoRoutines(t *testing.T) {
 	ts, thome, err := repotest.NewTempServer("testdata/testserver/*.*")
 	if err != nil {
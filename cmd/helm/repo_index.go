@@ -36,13 +36,27 @@ set an absolute URL to the charts, use '--url' flag.
 To merge the generated index with an existing index file, use the '--merge'
 flag. In this case, the charts found in the current directory will be merged
 into the existing index, with local charts taking priority over existing charts.
+Only the charts that are new, or that changed since the existing index was
+generated, are read, so updating the index of a large repository stays fast.
+
+The index is written in YAML, or in JSON with the '--json' flag. JSON is a
+subset of YAML that every Helm client reads, and loads much faster for large
+repositories.
+
+With the '--shard' flag, every version of each chart is written to an index
+file of its own, in the 'index' directory. The index.yaml file then only lists
+the latest version of each chart, and references the index files of the charts
+by digest, so that clients only download the ones that changed. Serve the
+'index' directory along with index.yaml.
 `
 
 type repoIndexCmd struct {
-	dir   string
-	url   string
-	out   io.Writer
-	merge string
+	dir    string
+	url    string
+	out    io.Writer
+	merge  string
+	asJSON bool
+	shard  bool
 }
 
 func newRepoIndexCmd(out io.Writer) *cobra.Command {
@@ -66,6 +80,8 @@ func newRepoIndexCmd(out io.Writer) *cobra.Command {
 	f := cmd.Flags()
 	f.StringVar(&index.url, "url", "", "URL of the chart repository")
 	f.StringVar(&index.merge, "merge", "", "Merge the generated index into the given index")
+	f.BoolVar(&index.asJSON, "json", false, "Write the index in JSON")
+	f.BoolVar(&index.shard, "shard", false, "Write the versions of each chart to an index file of its own")
 
 	return cmd
 }
@@ -76,19 +92,15 @@ func (i *repoIndexCmd) run() error {
 		return err
 	}
 
-	return index(path, i.url, i.merge)
+	return index(path, i.url, i.merge, i.asJSON, i.shard)
 }
 
-func index(dir, url, mergeTo string) error {
+func index(dir, url, mergeTo string, asJSON, shard bool) error {
 	out := filepath.Join(dir, "index.yaml")
 
-	i, err := repo.IndexDirectory(dir, url)
-	if err != nil {
-		return err
-	}
+	// if index.yaml is missing then create an empty one to merge into
+	var i2 *repo.IndexFile
 	if mergeTo != "" {
-		// if index.yaml is missing then create an empty one to merge into
-		var i2 *repo.IndexFile
 		if _, err := os.Stat(mergeTo); os.IsNotExist(err) {
 			i2 = repo.NewIndexFile()
 			i2.WriteFile(mergeTo, 0644)
@@ -98,8 +110,21 @@ func index(dir, url, mergeTo string) error {
 				return fmt.Errorf("Merge failed: %s", err)
 			}
 		}
+	}
+
+	i, err := repo.UpdateIndexDirectory(dir, url, i2)
+	if err != nil {
+		return err
+	}
+	if i2 != nil {
 		i.Merge(i2)
 	}
 	i.SortEntries()
+	switch {
+	case shard:
+		return i.WriteShardedFile(out, 0644, asJSON)
+	case asJSON:
+		return i.WriteJSONFile(out, 0644)
+	}
 	return i.WriteFile(out, 0644)
 }
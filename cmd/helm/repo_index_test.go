@@ -142,6 +142,35 @@ func TestRepoIndexCmd(t *testing.T) {
 	if vs[0].Version != expectedVersion {
 		t.Errorf("expected %q, got %q", expectedVersion, vs[0].Version)
 	}
+
+	// test that a sharded index keeps the versions of each chart in its own file
+	//
+	// The index was regenerated above from the latest version alone, so the
+	// earlier versions are added back to the repository first.
+	if err := linkOrCopy("testdata/testcharts/compressedchart-0.1.0.tgz", comp); err != nil {
+		t.Fatal(err)
+	}
+	if err := linkOrCopy("testdata/testcharts/compressedchart-0.2.0.tgz", comp2); err != nil {
+		t.Fatal(err)
+	}
+	c.ParseFlags([]string{"--merge", destIndex, "--shard", "--json"})
+	if err := c.RunE(c, []string{dir}); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "index", "compressedchart.json")); err != nil {
+		t.Errorf("expected the index of compressedchart to be written: %s", err)
+	}
+
+	index, err = repo.LoadIndexFile(destIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs = index.Entries["compressedchart"]
+	if len(vs) != 3 {
+		t.Errorf("expected 3 versions, got %d: %#v", len(vs), vs)
+	}
 }
 
 func linkOrCopy(old, new string) error {
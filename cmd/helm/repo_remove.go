@@ -19,12 +19,10 @@ package main
 import (
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm/helmpath"
-	"k8s.io/helm/pkg/repo"
 )
 
 type repoRemoveCmd struct {
@@ -64,20 +62,9 @@ func (r *repoRemoveCmd) run() error {
 }
 
 func removeRepoLine(out io.Writer, name string, home helmpath.Home) error {
-	repoFile := home.RepositoryFile()
-	r, err := repo.LoadRepositoriesFile(repoFile)
-	if err != nil {
-		return err
-	}
-
-	if !r.Remove(name) {
-		return fmt.Errorf("no repo named %q found", name)
-	}
-	if err := r.WriteFile(repoFile, 0644); err != nil {
-		return err
-	}
-
-	if err := removeRepoCache(name, home); err != nil {
+	cfg := newActionConfig(out)
+	cfg.Home = home
+	if err := cfg.RemoveRepository(name); err != nil {
 		return err
 	}
 
@@ -85,13 +72,3 @@ func removeRepoLine(out io.Writer, name string, home helmpath.Home) error {
 
 	return nil
 }
-
-func removeRepoCache(name string, home helmpath.Home) error {
-	if _, err := os.Stat(home.CacheIndex(name)); err == nil {
-		err = os.Remove(home.CacheIndex(name))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
@@ -17,15 +17,16 @@ limitations under the License.
 package main
 
 import (
-	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/cmd/helm/installer"
-	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/repo"
 )
@@ -43,17 +44,27 @@ You can specify the name of a repository you want to update.
 
 To update all the repositories, use 'helm repo update'.
 
+A repository that cannot be reached does not stop the update of the others. It
+is tried again '--retries' times, waiting longer before each attempt, and the
+outcome of every repository is summarized once all of them are done. The command
+only fails on repositories that could not be updated if '--strict' is given.
+
+The index of a repository is downloaded again, or resumed where it stopped, when
+the connection fails in the middle. The cached index is only replaced once the
+new one is complete and valid.
 `
 
-var errNoRepositories = errors.New("no repositories found. You must add one before updating")
-var errNoRepositoriesMatchingRepoName = errors.New("no repositories found matching the provided name. Verify if the repo exists")
+// repoUpdateBackoff is how long the update of a repository waits before its
+// first retry. The wait doubles before each further retry.
+var repoUpdateBackoff = time.Second
 
 type repoUpdateCmd struct {
-	update func([]*repo.ChartRepository, io.Writer, helmpath.Home, bool) error
-	home   helmpath.Home
-	out    io.Writer
-	strict bool
-	name   string
+	update  func([]*repo.ChartRepository, io.Writer, helmpath.Home, bool, int) error
+	home    helmpath.Home
+	out     io.Writer
+	strict  bool
+	retries int
+	name    string
 }
 
 func newRepoUpdateCmd(out io.Writer) *cobra.Command {
@@ -77,80 +88,101 @@ func newRepoUpdateCmd(out io.Writer) *cobra.Command {
 
 	f := cmd.Flags()
 	f.BoolVar(&u.strict, "strict", false, "Fail on update warnings")
+	f.IntVar(&u.retries, "retries", 2, "Number of times to retry a repository that failed to update")
 
 	return cmd
 }
 
 func (u *repoUpdateCmd) run() error {
-	f, err := repo.LoadRepositoriesFile(u.home.RepositoryFile())
+	cfg := newActionConfig(u.out)
+	cfg.Home = u.home
+	repos, err := cfg.ChartRepositories(u.name)
 	if err != nil {
 		return err
 	}
+	return u.update(repos, u.out, u.home, u.strict, u.retries)
+}
 
-	if len(f.Repositories) == 0 {
-		return errNoRepositories
-	}
-	var repos []*repo.ChartRepository
-	for _, cfg := range f.Repositories {
-		r, err := repo.NewChartRepository(cfg, getter.All(settings))
-		if err != nil {
-			return err
-		}
-		if len(u.name) != 0 {
-			if cfg.Name == u.name {
-				repos = append(repos, r)
-				break
-			} else {
-				continue
-			}
-		} else {
-			repos = append(repos, r)
-		}
-	}
-
-	if len(repos) == 0 {
-		return errNoRepositoriesMatchingRepoName
-	}
-
-	return u.update(repos, u.out, u.home, u.strict)
+// repoUpdateResult is the outcome of the update of a repository.
+type repoUpdateResult struct {
+	name     string
+	skipped  bool
+	attempts int
+	err      error
 }
 
-func updateCharts(repos []*repo.ChartRepository, out io.Writer, home helmpath.Home, strict bool) error {
+func updateCharts(repos []*repo.ChartRepository, out io.Writer, home helmpath.Home, strict bool, retries int) error {
 	fmt.Fprintln(out, "Hang tight while we grab the latest from your chart repositories...")
 	var (
-		errorCounter int
-		wg           sync.WaitGroup
-		mu           sync.Mutex
+		wg sync.WaitGroup
+		mu sync.Mutex
 	)
-	for _, re := range repos {
+	results := make([]repoUpdateResult, len(repos))
+	for i, re := range repos {
 		wg.Add(1)
-		go func(re *repo.ChartRepository) {
+		go func(re *repo.ChartRepository, res *repoUpdateResult) {
 			defer wg.Done()
+			res.name = re.Config.Name
 			if re.Config.Name == installer.LocalRepository {
+				res.skipped = true
 				mu.Lock()
 				fmt.Fprintf(out, "...Skip %s chart repository\n", re.Config.Name)
 				mu.Unlock()
 				return
 			}
-			err := re.DownloadIndexFile(home.Cache())
-			if err != nil {
+			backoff := repoUpdateBackoff
+			for {
+				res.attempts++
+				res.err = re.DownloadIndexFile(home.Cache())
+				if res.err == nil || res.attempts > retries {
+					break
+				}
 				mu.Lock()
-				errorCounter++
-				fmt.Fprintf(out, "...Unable to get an update from the %q chart repository (%s):\n\t%s\n", re.Config.Name, re.Config.URL, err)
+				fmt.Fprintf(out, "...Retrying the %q chart repository in %s: %s\n", re.Config.Name, backoff, res.err)
 				mu.Unlock()
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+			mu.Lock()
+			if res.err != nil {
+				fmt.Fprintf(out, "...Unable to get an update from the %q chart repository (%s):\n\t%s\n", re.Config.Name, re.Config.URL, res.err)
 			} else {
-				mu.Lock()
 				fmt.Fprintf(out, "...Successfully got an update from the %q chart repository\n", re.Config.Name)
-				mu.Unlock()
 			}
-		}(re)
+			mu.Unlock()
+		}(re, &results[i])
 	}
 	wg.Wait()
 
-	if errorCounter != 0 && strict {
-		return errors.New("Update Failed. Check log for details")
+	failed := printUpdateSummary(out, results)
+	if failed != 0 && strict {
+		return fmt.Errorf("Update Failed. %d of %d repositories could not be updated, check log for details", failed, len(results))
 	}
 
 	fmt.Fprintln(out, "Update Complete.")
 	return nil
 }
+
+// printUpdateSummary prints the outcome of the update of each repository, and
+// returns the number of repositories that could not be updated.
+func printUpdateSummary(out io.Writer, results []repoUpdateResult) int {
+	sort.Slice(results, func(i, j int) bool { return results[i].name < results[j].name })
+
+	failed := 0
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.AddRow("REPOSITORY", "STATUS", "ATTEMPTS")
+	for _, res := range results {
+		status := "updated"
+		switch {
+		case res.skipped:
+			status = "skipped"
+		case res.err != nil:
+			status = "failed"
+			failed++
+		}
+		table.AddRow(res.name, status, res.attempts)
+	}
+	fmt.Fprintf(out, "\n%s\n\n", table)
+	return failed
+}
@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
@@ -46,7 +48,7 @@ func TestUpdateCmd(t *testing.T) {
 	out := bytes.NewBuffer(nil)
 	// Instead of using the HTTP updater, we provide our own for this test.
 	// The TestUpdateCharts test verifies the HTTP behavior independently.
-	updater := func(repos []*repo.ChartRepository, out io.Writer, hh helmpath.Home, strict bool) error {
+	updater := func(repos []*repo.ChartRepository, out io.Writer, hh helmpath.Home, strict bool, retries int) error {
 		for _, re := range repos {
 			fmt.Fprintln(out, re.Config.Name)
 		}
@@ -95,7 +97,7 @@ func TestUpdateCharts(t *testing.T) {
 	}
 
 	b := bytes.NewBuffer(nil)
-	updateCharts([]*repo.ChartRepository{r}, b, hh, false)
+	updateCharts([]*repo.ChartRepository{r}, b, hh, false, 0)
 
 	got := b.String()
 	if strings.Contains(got, "Unable to get an update") {
@@ -106,6 +108,67 @@ func TestUpdateCharts(t *testing.T) {
 	}
 }
 
+func TestUpdateChartsRetriesAndSummary(t *testing.T) {
+	ts, thome, err := repotest.NewTempServer("testdata/testserver/*.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hh := helmpath.Home(thome)
+	cleanup := resetEnv()
+	defer func() {
+		ts.Stop()
+		os.RemoveAll(thome.String())
+		cleanup()
+	}()
+	if err := ensureTestHome(hh, t); err != nil {
+		t.Fatal(err)
+	}
+
+	settings.Home = thome
+
+	defer func(backoff time.Duration) { repoUpdateBackoff = backoff }(repoUpdateBackoff)
+	repoUpdateBackoff = 0
+
+	var repos []*repo.ChartRepository
+	for name, url := range map[string]string{"good": ts.URL(), "broken": ts.URL() + "/missing"} {
+		r, err := repo.NewChartRepository(&repo.Entry{
+			Name:  name,
+			URL:   url,
+			Cache: hh.CacheIndex(name),
+		}, getter.All(settings))
+		if err != nil {
+			t.Fatal(err)
+		}
+		repos = append(repos, r)
+	}
+
+	b := bytes.NewBuffer(nil)
+	if err := updateCharts(repos, b, hh, false, 2); err != nil {
+		t.Fatalf("expected no error without strict, got %s", err)
+	}
+	got := b.String()
+	for _, expect := range []string{
+		`Successfully got an update from the "good" chart repository`,
+		`Unable to get an update from the "broken" chart repository`,
+		`Retrying the "broken" chart repository`,
+		`broken\s+failed\s+3`,
+		`good\s+updated\s+1`,
+		"Update Complete.",
+	} {
+		if !regexp.MustCompile(expect).MatchString(got) {
+			t.Errorf("expected %q in output, got %q", expect, got)
+		}
+	}
+
+	b.Reset()
+	if err := updateCharts(repos, b, hh, true, 0); err == nil {
+		t.Error("expected an error with strict")
+	} else if !strings.Contains(err.Error(), "1 of 2 repositories") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
 func TestUpdateCmdStrictFlag(t *testing.T) {
 	thome, err := tempHelmHome(t)
 	if err != nil {
@@ -120,6 +183,9 @@ func TestUpdateCmdStrictFlag(t *testing.T) {
 
 	settings.Home = thome
 
+	defer func(backoff time.Duration) { repoUpdateBackoff = backoff }(repoUpdateBackoff)
+	repoUpdateBackoff = 0
+
 	out := bytes.NewBuffer(nil)
 	cmd := newRepoUpdateCmd(out)
 	cmd.ParseFlags([]string{"--strict"})
@@ -60,6 +60,9 @@ func newResetCmd(client helm.Interface, out io.Writer) *cobra.Command {
 		Short: "Uninstalls Tiller from a cluster",
 		Long:  resetDesc,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if settings.ClientOnly {
+				return errors.New("there is no Tiller to uninstall in client-only mode")
+			}
 			err := setupConnection()
 			if !d.force && err != nil {
 				return err
@@ -0,0 +1,226 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/downloader"
+	"k8s.io/helm/pkg/getter"
+)
+
+const resolveDesc = `
+This command shows how a chart reference resolves, as 'helm install', 'helm
+upgrade' and 'helm fetch' would resolve it: the repositories consulted and the
+age of their cached indexes, the versions of the chart matching the version
+constraint, the version chosen and the URL of its archive, and the credentials
+and TLS files used to download it. Passwords are never printed.
+
+	$ helm resolve stable/mariadb --version '^5'
+
+Local charts take precedence over repositories: if the reference is a path to
+a chart, no repository is consulted.
+
+The indexes are the ones cached by 'helm repo update', no repository is
+contacted.
+`
+
+type resolveCmd struct {
+	ref          string
+	version      string
+	devel        bool
+	username     string
+	password     string
+	outputFormat string
+	out          io.Writer
+}
+
+func newResolveCmd(out io.Writer) *cobra.Command {
+	r := &resolveCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "resolve [flags] [chart URL | repo/chartname]",
+		Short: "Show how a chart reference resolves to a chart version and URL",
+		Long:  resolveDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart reference"); err != nil {
+				return err
+			}
+			r.ref = args[0]
+			return r.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&r.version, "version", "", "Version constraint of the chart. Without this, the latest version is chosen")
+	f.BoolVar(&r.devel, "devel", false, "Use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored.")
+	f.StringVar(&r.username, "username", "", "Chart repository username")
+	f.StringVar(&r.password, "password", "", "Chart repository password")
+	f.StringVarP(&r.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+
+	return cmd
+}
+
+func (r *resolveCmd) run() error {
+	if path := localChartPath(r.ref); path != "" {
+		fmt.Fprintf(r.out, "REFERENCE:\t%s\nLOCAL CHART:\t%s\n", r.ref, path)
+		return nil
+	}
+
+	version := r.version
+	if version == "" && r.devel && !pinnedChartReference(r.ref) {
+		version = ">0.0.0-0"
+	}
+	dl := downloader.ChartDownloader{
+		HelmHome: settings.Home,
+		Out:      r.out,
+		Getters:  getter.All(settings),
+		Username: r.username,
+		Password: r.password,
+	}
+	res, resolveErr := dl.Explain(r.ref, version)
+
+	var out []byte
+	var err error
+	switch r.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(res)
+	case "json":
+		out, err = json.Marshal(res)
+	case "table":
+		out = formatResolution(res, time.Now())
+	default:
+		return fmt.Errorf("unknown output format %q", r.outputFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, string(out))
+	return resolveErr
+}
+
+// localChartPath returns the path of the local chart a reference names, the
+// way locateChartPath finds it, or an empty string.
+func localChartPath(ref string) string {
+	for _, path := range []string{ref, filepath.Join(settings.Home.Repository(), ref)} {
+		if _, err := os.Stat(path); err == nil {
+			abs, _ := filepath.Abs(path)
+			return abs
+		}
+	}
+	return ""
+}
+
+func formatResolution(res *downloader.Resolution, now time.Time) []byte {
+	var b strings.Builder
+
+	fields := uitable.New()
+	fields.AddRow("REFERENCE:", res.Reference)
+	if res.Constraint != "" {
+		fields.AddRow("CONSTRAINT:", res.Constraint)
+	}
+	if res.Digest != "" {
+		fields.AddRow("DIGEST:", "sha256:"+res.Digest)
+	}
+	fmt.Fprintln(&b, fields)
+
+	if len(res.Repositories) > 0 {
+		fmt.Fprintln(&b, "REPOSITORIES:")
+		repos := uitable.New()
+		repos.AddRow("NAME", "URL", "INDEX GENERATED", "INDEX FETCHED", "ERROR")
+		for _, rc := range res.Repositories {
+			repos.AddRow(rc.Name, rc.URL, formatAge(rc.Generated, now), formatAge(rc.Fetched, now), rc.Error)
+		}
+		fmt.Fprintln(&b, repos)
+	}
+
+	if len(res.Candidates) > 0 {
+		fmt.Fprintln(&b, "CANDIDATES:")
+		candidates := uitable.New()
+		candidates.AddRow("VERSION", "APP VERSION", "CREATED", "MATCHES", "CHOSEN")
+		for _, c := range res.Candidates {
+			chosen := ""
+			if c.Chosen {
+				chosen = "*"
+			}
+			candidates.AddRow(c.Version, c.AppVersion, formatAge(c.Created, now), yesNo(c.Matches), chosen)
+		}
+		fmt.Fprintln(&b, candidates)
+	}
+
+	download := uitable.New()
+	if res.Version != "" {
+		download.AddRow("VERSION:", res.Version)
+	}
+	if res.URL != "" {
+		download.AddRow("URL:", res.URL)
+	}
+	repository := res.Repository
+	if repository == "" {
+		repository = "none"
+	}
+	download.AddRow("SETTINGS FROM:", repository)
+	credentials := "none"
+	if res.Username != "" {
+		credentials = fmt.Sprintf("user %q from the %s", res.Username, res.CredentialsFrom)
+	}
+	if res.Password {
+		credentials += ", with a password"
+	}
+	download.AddRow("CREDENTIALS:", credentials)
+	var tls []string
+	for _, f := range []struct{ name, path string }{{"cert-file", res.CertFile}, {"key-file", res.KeyFile}, {"ca-file", res.CAFile}} {
+		if f.path != "" {
+			tls = append(tls, f.name+" "+f.path)
+		}
+	}
+	if len(tls) == 0 {
+		tls = []string{"system defaults"}
+	}
+	download.AddRow("TLS:", strings.Join(tls, ", "))
+	if res.Error != "" {
+		download.AddRow("ERROR:", res.Error)
+	}
+	fmt.Fprint(&b, download)
+
+	return []byte(b.String())
+}
+
+// formatAge prints a time with how long ago it was.
+func formatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s ago)", t.UTC().Format(time.RFC3339), now.Sub(t).Round(time.Second))
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
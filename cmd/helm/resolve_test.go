@@ -0,0 +1,73 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestResolveCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "resolve a version constraint",
+			args:     []string{"testing/alpine"},
+			flags:    []string{"--version", "<0.2.0"},
+			expected: `(?s)REPOSITORIES:.*testing\s+http://example\.com/charts.*CANDIDATES:.*0\.2\.0\s+2\.3\.4\s+no\s*\n0\.1\.0\s+1\.2\.3\s+yes\s+\*.*VERSION:\s+0\.1\.0`,
+			// the index of the test repository has no chart URLs
+			err: true,
+		},
+		{
+			name:     "resolve with credentials",
+			args:     []string{"testing/alpine"},
+			flags:    []string{"--username", "bob", "--password", "secret"},
+			expected: `SETTINGS FROM:\s+testing\s*\nCREDENTIALS:\s+user "bob" from the flags, with a password\s*\nTLS:\s+system defaults`,
+			err:      true,
+		},
+		{
+			name:     "resolve in an unknown repository",
+			args:     []string{"nosuch/alpine"},
+			expected: `SETTINGS FROM:\s+none(?s).*ERROR:\s+repo nosuch not found`,
+			err:      true,
+		},
+		{
+			name:     "resolve as json",
+			args:     []string{"testing/alpine@0.1.0"},
+			flags:    []string{"--output", "json"},
+			expected: `\{"reference":"testing/alpine","constraint":"0\.1\.0","repositories":\[\{"name":"testing"`,
+			err:      true,
+		},
+		{
+			name:     "resolve a local chart",
+			args:     []string{"testdata/testcharts/alpine"},
+			expected: `LOCAL CHART:\s+.*testdata/testcharts/alpine`,
+		},
+	}
+
+	cleanup := resetEnv()
+	defer cleanup()
+
+	settings.Home = "testdata/helmhome"
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newResolveCmd(out)
+	})
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+)
+
+const resourcesHelp = `
+This command lists the live resources of a release, with their namespace, age
+and health. Unlike 'kubectl get all', it lists every kind of resource of the
+release, and only the resources of the release:
+
+- the resources of its manifest. Those missing from the cluster are listed as
+  not found
+- the resources created by its hooks that still exist
+- the resources owned by the ones above, following their owner references, such
+  as the pods of a deployment or the resources an operator created for a custom
+  resource. Disable it with '--owned=false'
+- the resources labelled with the name of the release, and the ones they own.
+  The label selector is 'app.kubernetes.io/instance=RELEASE_NAME' unless another
+  one is given with '--selector'. Pass an empty selector to disable it
+
+Finding the owned and labelled resources lists every kind of resource in the
+namespaces of the release, which may take a while on large clusters.
+
+    $ helm resources angry-bird
+    KIND        NAMESPACE  NAME                        SOURCE    AGE  READY  MESSAGE
+    Deployment  default    angry-bird                  manifest  2d   true   1/1 replicas ready, 1 required
+    Service     default    angry-bird                  manifest  2d   true   cluster IP 10.0.0.12
+    Pod         default    angry-bird-5d8f7c9b4-x7k2p  owned     2d   true   Running
+    ReplicaSet  default    angry-bird-5d8f7c9b4        owned     2d   true   1/1 pods ready
+`
+
+type resourcesCmd struct {
+	release      string
+	revision     int32
+	owned        bool
+	selector     string
+	outputFormat string
+	colWidth     uint
+	out          io.Writer
+	client       helm.Interface
+	// resources returns the live resources of a release, see
+	// kube.Client.ReleaseResources.
+	resources func(namespace, manifest string, opts kube.ResourcesOptions) ([]kube.ReleaseResource, error)
+}
+
+func newResourcesCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	rc := &resourcesCmd{out: out, client: client}
+
+	cmd := &cobra.Command{
+		Use:     "resources [flags] RELEASE_NAME",
+		Short:   "List the live resources of a release",
+		Long:    resourcesHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			rc.release = args[0]
+			if !cmd.Flags().Changed("selector") {
+				rc.selector = "app.kubernetes.io/instance=" + rc.release
+			}
+			rc.client = ensureHelmClient(rc.client)
+			return rc.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.Int32Var(&rc.revision, "revision", 0, "List the resources of this revision of the release instead of the latest one")
+	f.BoolVar(&rc.owned, "owned", true, "List the resources owned by the resources of the release")
+	f.StringVarP(&rc.selector, "selector", "l", "", "List the resources matching this label selector (default \"app.kubernetes.io/instance=RELEASE_NAME\")")
+	f.UintVar(&rc.colWidth, "col-width", 60, "Specifies the max column width of output")
+	f.StringVarP(&rc.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (r *resourcesCmd) run() error {
+	res, err := r.client.ReleaseContent(r.release, helm.ContentReleaseVersion(r.revision))
+	if err != nil {
+		return prettyError(err)
+	}
+	rel := res.GetRelease()
+
+	var hooks bytes.Buffer
+	for _, h := range rel.GetHooks() {
+		fmt.Fprintf(&hooks, "---\n%s\n", h.Manifest)
+	}
+
+	if r.resources == nil {
+		kc := kube.New(localConfigFlags(nil))
+		kc.Log = debug
+		r.resources = func(namespace, manifest string, opts kube.ResourcesOptions) ([]kube.ReleaseResource, error) {
+			return kc.ReleaseResources(namespace, strings.NewReader(manifest), opts)
+		}
+	}
+	resources, err := r.resources(rel.GetNamespace(), rel.GetManifest(), kube.ResourcesOptions{
+		Hooks:    &hooks,
+		Owned:    r.owned,
+		Selector: r.selector,
+	})
+	if err != nil {
+		return fmt.Errorf("could not list the resources of %q: %s", r.release, err)
+	}
+
+	var out []byte
+	switch r.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(resources)
+	case "json":
+		out, err = json.Marshal(resources)
+	case "table":
+		out = formatResources(resources, time.Now(), r.colWidth)
+	default:
+		return fmt.Errorf("unknown output format %q", r.outputFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, string(out))
+	return nil
+}
+
+func formatResources(resources []kube.ReleaseResource, now time.Time, colWidth uint) []byte {
+	tbl := uitable.New()
+	tbl.MaxColWidth = colWidth
+	tbl.AddRow("KIND", "NAMESPACE", "NAME", "SOURCE", "AGE", "READY", "MESSAGE")
+	for _, r := range resources {
+		tbl.AddRow(r.Kind, r.Namespace, r.Name, r.Source, resourceAge(r.Created, now), r.Ready, r.Message)
+	}
+	return tbl.Bytes()
+}
+
+// resourceAge returns the age of a resource in the largest unit, as kubectl
+// does.
+func resourceAge(created, now time.Time) string {
+	if created.IsZero() {
+		return "-"
+	}
+	d := now.Sub(created)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
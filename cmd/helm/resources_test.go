@@ -0,0 +1,129 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestResourcesCmd(t *testing.T) {
+	rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "angry-bird", Namespace: "birds"})
+	created := time.Now().Add(-3 * time.Hour)
+
+	var (
+		gotNamespace, gotManifest, gotHooks string
+		gotOpts                             kube.ResourcesOptions
+	)
+	fake := func(namespace, manifest string, opts kube.ResourcesOptions) ([]kube.ReleaseResource, error) {
+		gotNamespace, gotManifest, gotOpts = namespace, manifest, opts
+		hooks, err := ioutil.ReadAll(opts.Hooks)
+		if err != nil {
+			return nil, err
+		}
+		gotHooks = string(hooks)
+		return []kube.ReleaseResource{
+			{Kind: "Deployment", Namespace: "birds", Name: "angry-bird", Source: kube.SourceManifest, Created: created, Ready: true, Message: "1/1 replicas ready, 1 required"},
+			{Kind: "Secret", Namespace: "birds", Name: "angry-bird-tls", Source: kube.SourceManifest, Message: "not found"},
+			{Kind: "Pod", Namespace: "birds", Name: "angry-bird-x7k2p", Source: kube.SourceOwned, Owner: "ReplicaSet/angry-bird-5d8f", Created: created, Ready: true, Message: "Running"},
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	rc := &resourcesCmd{
+		release:      "angry-bird",
+		owned:        true,
+		selector:     "app.kubernetes.io/instance=angry-bird",
+		outputFormat: "table",
+		colWidth:     60,
+		out:          &buf,
+		client:       &helm.FakeClient{Rels: []*release.Release{rel}},
+		resources:    fake,
+	}
+	if err := rc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if gotNamespace != "birds" || gotManifest != helm.MockManifest {
+		t.Errorf("expected the namespace and manifest of the release, got %q and %q", gotNamespace, gotManifest)
+	}
+	if !strings.Contains(gotHooks, helm.MockHookTemplate) {
+		t.Errorf("expected the manifests of the hooks, got %q", gotHooks)
+	}
+	if !gotOpts.Owned || gotOpts.Selector != "app.kubernetes.io/instance=angry-bird" {
+		t.Errorf("unexpected options %+v", gotOpts)
+	}
+	expected := regexp.MustCompile(`KIND\s+NAMESPACE\s+NAME\s+SOURCE\s+AGE\s+READY\s+MESSAGE\s*
+Deployment\s+birds\s+angry-bird\s+manifest\s+3h\s+true\s+1/1 replicas ready, 1 required\s*
+Secret\s+birds\s+angry-bird-tls\s+manifest\s+-\s+false\s+not found\s*
+Pod\s+birds\s+angry-bird-x7k2p\s+owned\s+3h\s+true\s+Running`)
+	if !expected.MatchString(buf.String()) {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	rc.outputFormat = "json"
+	if err := rc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"source":"owned","owner":"ReplicaSet/angry-bird-5d8f"`) {
+		t.Errorf("expected the owner of the pod in the JSON output, got %s", buf.String())
+	}
+}
+
+func TestResourcesCmdArgs(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name: "resources requires a release name",
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newResourcesCmd(c, out)
+	})
+}
+
+func TestResourceAge(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		age    time.Duration
+		expect string
+	}{
+		{30 * time.Second, "30s"},
+		{5 * time.Minute, "5m"},
+		{30 * time.Hour, "30h"},
+		{72 * time.Hour, "3d"},
+	}
+	for _, tt := range tests {
+		if got := resourceAge(now.Add(-tt.age), now); got != tt.expect {
+			t.Errorf("age %s: expected %q, got %q", tt.age, tt.expect, got)
+		}
+	}
+	if got := resourceAge(time.Time{}, now); got != "-" {
+		t.Errorf("expected no age for a missing resource, got %q", got)
+	}
+}
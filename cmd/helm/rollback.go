@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	helm_env "k8s.io/helm/pkg/helm/environment"
 )
 
 const rollbackDesc = `
@@ -33,21 +35,42 @@ The first argument of the rollback command is the name of a release, and the
 second is a revision (version) number. To see revision numbers, run
 'helm history RELEASE'. If you'd like to rollback to the previous release use
 'helm rollback [RELEASE] 0'.
+
+Use '--values-only' to roll back the values only: the current chart is rendered
+again with the values of the revision. To roll back part of the release only,
+use '--only' as for 'helm upgrade', or '--selector' to select the resources by
+their labels. Every other resource is left as it is:
+
+	$ helm rollback --values-only my-release 3
+	$ helm rollback --selector tier=frontend my-release 3
+
+With '--atomic', a failed rollback is undone by rolling the release back to its
+last deployed revision.
 `
 
 type rollbackCmd struct {
-	name          string
-	revision      int32
-	dryRun        bool
-	recreate      bool
-	force         bool
-	disableHooks  bool
-	out           io.Writer
-	client        helm.Interface
-	timeout       int64
-	wait          bool
-	description   string
-	cleanupOnFail bool
+	name           string
+	revision       int32
+	dryRun         bool
+	recreate       bool
+	force          bool
+	disableHooks   bool
+	out            io.Writer
+	client         helm.Interface
+	timeout        int64
+	hookTimeout    int64
+	wait           bool
+	description    string
+	overrideWindow string
+	cleanupOnFail  bool
+	waitTimeouts   waitTimeouts
+	waitForJobs    bool
+	waitConditions waitConditions
+	quiet          bool
+	atomic         bool
+	valuesOnly     bool
+	only           []string
+	selector       string
 }
 
 func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -74,6 +97,7 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 			}
 
 			rollback.revision = int32(v64)
+			rollback.wait = rollback.wait || rollback.atomic
 			rollback.client = ensureHelmClient(rollback.client)
 			return rollback.run()
 		},
@@ -85,10 +109,20 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&rollback.recreate, "recreate-pods", false, "Performs pods restart for the resource if applicable")
 	f.BoolVar(&rollback.force, "force", false, "Force resource update through delete/recreate if needed")
 	f.BoolVar(&rollback.disableHooks, "no-hooks", false, "Prevent hooks from running during rollback")
-	f.Int64Var(&rollback.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	helm_env.TimeoutVar(f, &rollback.timeout, "timeout", 300, "Time to wait for any individual Kubernetes operation (like Jobs for hooks), as a duration such as 5m30s or in seconds")
+	helm_env.TimeoutVar(f, &rollback.hookTimeout, "hook-timeout", 0, "Time hooks may run, as a duration such as 10m or in seconds. Defaults to --timeout")
 	f.BoolVar(&rollback.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 	f.StringVar(&rollback.description, "description", "", "Specify a description for the release")
+	f.StringVar(&rollback.overrideWindow, "override-window", "", "Roll back outside the maintenance windows of the release, giving the reason recorded in the Tiller audit log")
 	f.BoolVar(&rollback.cleanupOnFail, "cleanup-on-fail", false, "Allow deletion of new resources created in this rollback when rollback failed")
+	f.BoolVar(&rollback.quiet, "quiet", false, "Print nothing on success")
+	f.BoolVar(&rollback.atomic, "atomic", false, "If set, a failed rollback is undone by rolling back to the last deployed revision, also sets --wait flag")
+	f.BoolVar(&rollback.valuesOnly, "values-only", false, "Roll back the values only, rendering the current chart again with the values of the revision")
+	f.StringArrayVar(&rollback.only, "only", []string{}, "Only roll back the resources rendered from this template path, of this kind with kind=KIND, or matching labels=SELECTOR (can specify multiple)")
+	f.StringVarP(&rollback.selector, "selector", "l", "", "Only roll back the resources matching this label selector")
+	f.Var(&rollback.waitTimeouts, "wait-timeout", "Override --timeout for resources of a kind while waiting, as KIND=DURATION (can specify multiple or separate values with commas: Deployment=10m,Service=60s)")
+	f.BoolVar(&rollback.waitForJobs, "wait-for-jobs", false, "If set with --wait, will also wait until all Jobs have completed. A failed Job fails the wait")
+	f.Var(&rollback.waitConditions, "wait-condition", "Wait for resources of a kind until a field of theirs has a value, as KIND=PATH==VALUE, such as 'Certificate=status.conditions[?(@.type==\"Ready\")].status==True' (can specify multiple)")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -97,6 +131,23 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (r *rollbackCmd) run() error {
+	only := r.only
+	if r.selector != "" {
+		only = append(only, "labels="+r.selector)
+	}
+	if len(only) > 0 && r.force {
+		return errors.New("--only and --selector cannot be used with --force")
+	}
+
+	var deployed int32
+	if r.atomic && !r.dryRun {
+		history, err := r.client.ReleaseHistory(r.name, helm.WithMaxHistory(256))
+		if err != nil {
+			return prettyError(err)
+		}
+		deployed = lastDeployedRevision(history.Releases)
+	}
+
 	_, err := r.client.RollbackRelease(
 		r.name,
 		helm.RollbackDryRun(r.dryRun),
@@ -105,14 +156,46 @@ func (r *rollbackCmd) run() error {
 		helm.RollbackDisableHooks(r.disableHooks),
 		helm.RollbackVersion(r.revision),
 		helm.RollbackTimeout(r.timeout),
+		helm.RollbackHookTimeout(r.hookTimeout),
 		helm.RollbackWait(r.wait),
+		helm.RollbackWaitTimeouts(r.waitTimeouts),
+		helm.RollbackWaitForJobs(r.waitForJobs),
+		helm.RollbackWaitConditions(r.waitConditions),
 		helm.RollbackDescription(r.description),
-		helm.RollbackCleanupOnFail(r.cleanupOnFail))
+		helm.RollbackOverrideWindow(r.overrideWindow),
+		helm.RollbackCleanupOnFail(r.cleanupOnFail),
+		helm.RollbackOnly(only),
+		helm.RollbackValuesOnly(r.valuesOnly))
 	if err != nil {
-		return prettyError(err)
+		if !r.atomic || r.dryRun || deployed == 0 {
+			return prettyError(err)
+		}
+		printMessage(r.out, r.quiet, msgRollbackFailed, prettyError(err))
+		printMessage(r.out, r.quiet, msgRollingBack, deployed)
+		revert := &rollbackCmd{
+			out:            r.out,
+			client:         r.client,
+			name:           r.name,
+			revision:       deployed,
+			recreate:       r.recreate,
+			timeout:        r.timeout,
+			hookTimeout:    r.hookTimeout,
+			wait:           r.wait,
+			disableHooks:   r.disableHooks,
+			cleanupOnFail:  r.cleanupOnFail,
+			waitTimeouts:   r.waitTimeouts,
+			waitForJobs:    r.waitForJobs,
+			waitConditions: r.waitConditions,
+			overrideWindow: r.overrideWindow,
+			quiet:          r.quiet,
+		}
+		if err := revert.run(); err != nil {
+			return err
+		}
+		return fmt.Errorf("ROLLBACK FAILED: %v", prettyError(err))
 	}
 
-	fmt.Fprintf(r.out, "Rollback was a success.\n")
+	printMessage(r.out, r.quiet, msgRolledBack)
 
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
 )
 
 func TestRollbackCmd(t *testing.T) {
@@ -33,6 +34,12 @@ func TestRollbackCmd(t *testing.T) {
 			args:     []string{"funny-honey", "1"},
 			expected: "Rollback was a success.",
 		},
+		{
+			name:     "quiet rollback",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--quiet"},
+			expected: "^$",
+		},
 		{
 			name:     "rollback a release with timeout",
 			args:     []string{"funny-honey", "1"},
@@ -51,6 +58,31 @@ func TestRollbackCmd(t *testing.T) {
 			flags:    []string{"--description", "foo"},
 			expected: "Rollback was a success.",
 		},
+		{
+			name:     "rollback the values of a release",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--values-only"},
+			expected: "Rollback was a success.",
+		},
+		{
+			name:     "rollback the resources of a release matching a selector",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--selector", "tier=frontend", "--only", "kind=ConfigMap"},
+			expected: "Rollback was a success.",
+		},
+		{
+			name:  "rollback part of a release with force",
+			args:  []string{"funny-honey", "1"},
+			flags: []string{"--selector", "tier=frontend", "--force"},
+			err:   true,
+		},
+		{
+			name:     "atomic rollback",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--atomic"},
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey"})},
+			expected: "Rollback was a success.",
+		},
 		{
 			name: "rollback a release without revision",
 			args: []string{"funny-honey"},
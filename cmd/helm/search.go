@@ -26,7 +26,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/cmd/helm/search"
+	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/repo"
 )
 
@@ -35,30 +38,55 @@ Search reads through all of the repositories configured on the system, and
 looks for matches.
 
 Repositories are managed with 'helm repo' commands.
+
+With '--installed', the deployed releases are listed next to the charts they
+were installed from, matched by chart name. A release of another version than
+the one shown is listed with its version:
+
+	$ helm search --installed mariadb
+	NAME            CHART VERSION  APP VERSION  DESCRIPTION        INSTALLED
+	stable/mariadb  5.2.3          10.1.37      Fast, reliable...  db (prod), legacy-db (dev, 4.0.1)
+
+Tiller does not record the repository a chart came from, so a release is listed
+for every repository with a chart of the same name.
 `
 
+// searchPageSize is the number of releases fetched at a time by --installed.
+const searchPageSize = 256
+
 // searchMaxScore suggests that any score higher than this is not considered a match.
 const searchMaxScore = 25
 
 type searchCmd struct {
 	out      io.Writer
+	client   helm.Interface
 	helmhome helmpath.Home
 
-	versions bool
-	regexp   bool
-	version  string
-	colWidth uint
+	versions  bool
+	regexp    bool
+	version   string
+	colWidth  uint
+	installed bool
 }
 
-func newSearchCmd(out io.Writer) *cobra.Command {
-	sc := &searchCmd{out: out}
+func newSearchCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	sc := &searchCmd{out: out, client: client}
 
 	cmd := &cobra.Command{
 		Use:   "search [keyword]",
 		Short: "Search for a keyword in charts",
 		Long:  searchDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if !sc.installed {
+				return nil
+			}
+			return setupConnection()
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sc.helmhome = settings.Home
+			if sc.installed {
+				sc.client = ensureHelmClient(sc.client)
+			}
 			return sc.run(args)
 		},
 	}
@@ -68,6 +96,11 @@ func newSearchCmd(out io.Writer) *cobra.Command {
 	f.BoolVarP(&sc.versions, "versions", "l", false, "Show the long listing, with each version of each chart on its own line")
 	f.StringVarP(&sc.version, "version", "v", "", "Search using semantic versioning constraints")
 	f.UintVar(&sc.colWidth, "col-width", 60, "Specifies the max column width of output")
+	f.BoolVar(&sc.installed, "installed", false, "Show the deployed releases of each chart, fetched from Tiller")
+	settings.AddFlagsTLS(f)
+
+	// set defaults from environment
+	settings.InitTLS(f)
 
 	return cmd
 }
@@ -95,11 +128,42 @@ func (s *searchCmd) run(args []string) error {
 		return err
 	}
 
-	fmt.Fprintln(s.out, s.formatSearchResults(data, s.colWidth))
-
+	if !s.installed {
+		fmt.Fprintln(s.out, s.formatSearchResults(data, s.colWidth))
+		return nil
+	}
+	installed, err := s.installedReleases()
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintln(s.out, s.formatInstalledResults(data, installed, s.colWidth))
 	return nil
 }
 
+// installedReleases returns the deployed releases, by chart name.
+func (s *searchCmd) installedReleases() (map[string][]*release.Release, error) {
+	installed := map[string][]*release.Release{}
+	offset := ""
+	for {
+		res, err := s.client.ListReleases(
+			helm.ReleaseListLimit(searchPageSize),
+			helm.ReleaseListOffset(offset),
+			helm.ReleaseListSort(int32(services.ListSort_NAME)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res.GetReleases() {
+			name := r.GetChart().GetMetadata().GetName()
+			installed[name] = append(installed[name], r)
+		}
+		if res.GetNext() == "" {
+			return installed, nil
+		}
+		offset = res.GetNext()
+	}
+}
+
 func (s *searchCmd) applyConstraint(res []*search.Result) ([]*search.Result, error) {
 	if len(s.version) == 0 {
 		return res, nil
@@ -141,6 +205,32 @@ func (s *searchCmd) formatSearchResults(res []*search.Result, colWidth uint) str
 	return table.String()
 }
 
+// formatInstalledResults renders the search results with the releases
+// installed from each chart. With --versions, a release is only listed next
+// to the version it runs.
+func (s *searchCmd) formatInstalledResults(res []*search.Result, installed map[string][]*release.Release, colWidth uint) string {
+	if len(res) == 0 {
+		return "No results found"
+	}
+	table := uitable.New()
+	table.MaxColWidth = colWidth
+	table.AddRow("NAME", "CHART VERSION", "APP VERSION", "DESCRIPTION", "INSTALLED")
+	for _, r := range res {
+		var rels []string
+		for _, rel := range installed[r.Chart.Name] {
+			version := rel.Chart.Metadata.Version
+			switch {
+			case version == r.Chart.Version:
+				rels = append(rels, fmt.Sprintf("%s (%s)", rel.Name, rel.Namespace))
+			case !s.versions:
+				rels = append(rels, fmt.Sprintf("%s (%s, %s)", rel.Name, rel.Namespace, version))
+			}
+		}
+		table.AddRow(r.Name, r.Chart.Version, r.Chart.AppVersion, r.Chart.Description, strings.Join(rels, ", "))
+	}
+	return table.String()
+}
+
 func (s *searchCmd) buildIndex() (*search.Index, error) {
 	// Load the repositories.yaml
 	rf, err := repo.LoadRepositoriesFile(s.helmhome.RepositoryFile())
@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
 )
 
 func TestSearchCmd(t *testing.T) {
@@ -78,6 +80,27 @@ func TestSearchCmd(t *testing.T) {
 			flags:    []string{"--regexp"},
 			expected: "NAME          \tCHART VERSION\tAPP VERSION\tDESCRIPTION                    \ntesting/alpine\t0.2.0        \t2.3.4      \tDeploy a basic Alpine Linux pod",
 		},
+		{
+			name:  "search for 'alpine' with installed releases",
+			args:  []string{"alpine"},
+			flags: []string{"--installed"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "web", Namespace: "default", Chart: alpineChart("0.2.0")}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "old", Namespace: "dev", Chart: alpineChart("0.1.0")}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "db", Namespace: "prod"}),
+			},
+			expected: `INSTALLED(.*)\ntesting/alpine(.*)0\.2\.0(.*)web \(default\), old \(dev, 0\.1\.0\)`,
+		},
+		{
+			name:  "search for 'alpine' with versions and installed releases",
+			args:  []string{"alpine"},
+			flags: []string{"--versions", "--installed"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "web", Namespace: "default", Chart: alpineChart("0.2.0")}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "old", Namespace: "dev", Chart: alpineChart("0.1.0")}),
+			},
+			expected: `0\.2\.0(.*)web \(default\)\s*\n(.*)0\.1\.0(.*)old \(dev\)\s*$`,
+		},
 		{
 			name:  "search for 'alp[', expect failure to compile regexp",
 			args:  []string{"alp["},
@@ -92,6 +115,10 @@ func TestSearchCmd(t *testing.T) {
 	settings.Home = "testdata/helmhome"
 
 	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
-		return newSearchCmd(out)
+		return newSearchCmd(c, out)
 	})
 }
+
+func alpineChart(version string) *chart.Chart {
+	return &chart.Chart{Metadata: &chart.Metadata{Name: "alpine", Version: version}}
+}
@@ -89,9 +89,9 @@ func (s *serveCmd) run() error {
 
 	fmt.Fprintln(s.out, "Regenerating index. This may take a moment.")
 	if len(s.url) > 0 {
-		err = index(repoPath, s.url, "")
+		err = index(repoPath, s.url, "", false, false)
 	} else {
-		err = index(repoPath, "http://"+s.address, "")
+		err = index(repoPath, "http://"+s.address, "", false, false)
 	}
 	if err != nil {
 		return err
@@ -0,0 +1,419 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+const snapshotDesc = `
+Record the rendered manifests of a chart as golden files and verify that the
+chart still renders them, to catch unintended changes to the manifests when
+the templates are changed.
+
+The snapshots are kept in the 'snapshots' directory of the chart. Each values
+file in it, such as 'snapshots/ingress.yaml', is a case whose manifests are
+recorded in 'snapshots/ingress.golden'. Without values files, the chart is
+recorded with its default values in 'snapshots/default.golden'.
+
+Charts are rendered the same way on every machine: as the release
+'release-name' in the namespace 'default', at a fixed time and with a fixed
+seed for the random template functions. NOTES.txt is not recorded.
+
+	$ helm snapshot record mychart
+	$ helm snapshot verify mychart
+
+Add 'snapshots/' to the .helmignore file of the chart to leave the snapshots
+out of its package.
+`
+
+const snapshotVerifyDesc = `
+Render the snapshot cases of a chart and compare them with their golden files.
+The command fails if a manifest differs, and prints the differences. With
+'--update', the golden files of the cases that differ are recorded again.
+`
+
+const (
+	// snapshotDir is the directory of the chart holding the snapshots.
+	snapshotDir = "snapshots"
+	// snapshotExt is the extension of the golden files.
+	snapshotExt = ".golden"
+	// snapshotDefaultCase is the case of a chart without values files.
+	snapshotDefaultCase = "default"
+)
+
+// snapshotTime is the render time of the snapshots.
+var snapshotTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type snapshotCmd struct {
+	chartPath   string
+	kubeVersion string
+	update      bool
+	out         io.Writer
+}
+
+// snapshotCase is a set of values the chart is recorded with.
+type snapshotCase struct {
+	name       string
+	valuesFile string
+}
+
+func newSnapshotCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Record and verify the rendered manifests of a chart",
+		Long:  snapshotDesc,
+	}
+	cmd.AddCommand(
+		newSnapshotRecordCmd(out),
+		newSnapshotVerifyCmd(out),
+	)
+	return cmd
+}
+
+func newSnapshotRecordCmd(out io.Writer) *cobra.Command {
+	s := &snapshotCmd{out: out}
+	cmd := &cobra.Command{
+		Use:   "record [flags] CHART",
+		Short: "Record the golden files of the snapshot cases of a chart",
+		Long:  snapshotDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart path"); err != nil {
+				return err
+			}
+			s.chartPath = args[0]
+			return s.run(true)
+		},
+	}
+	cmd.Flags().StringVar(&s.kubeVersion, "kube-version", defaultKubeVersion, "Kubernetes version used as Capabilities.KubeVersion.Major/Minor")
+	return cmd
+}
+
+func newSnapshotVerifyCmd(out io.Writer) *cobra.Command {
+	s := &snapshotCmd{out: out}
+	cmd := &cobra.Command{
+		Use:   "verify [flags] CHART",
+		Short: "Verify that a chart renders its golden files",
+		Long:  snapshotVerifyDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart path"); err != nil {
+				return err
+			}
+			s.chartPath = args[0]
+			return s.run(false)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&s.update, "update", false, "Record the golden files of the cases that differ")
+	f.StringVar(&s.kubeVersion, "kube-version", defaultKubeVersion, "Kubernetes version used as Capabilities.KubeVersion.Major/Minor")
+	return cmd
+}
+
+// run renders every case of the chart. If record is set, all golden files are
+// written; otherwise the cases are compared with their golden files.
+func (s *snapshotCmd) run(record bool) error {
+	c, err := chartutil.Load(s.chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	if err := chartutil.IsChartInstallable(c); err != nil {
+		return err
+	}
+	cases, err := snapshotCases(s.chartPath)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, sc := range cases {
+		docs, err := renderSnapshot(c, sc.valuesFile, s.kubeVersion)
+		if err != nil {
+			return fmt.Errorf("snapshot %s: %s", sc.name, err)
+		}
+		golden := filepath.Join(s.chartPath, snapshotDir, sc.name+snapshotExt)
+		got := formatSnapshot(docs)
+
+		if !record {
+			want, err := ioutil.ReadFile(golden)
+			switch {
+			case os.IsNotExist(err):
+				fmt.Fprintf(s.out, "FAIL %s: no golden file %s\n", sc.name, golden)
+			case err != nil:
+				return err
+			case bytes.Equal(want, got):
+				fmt.Fprintf(s.out, "PASS %s\n", sc.name)
+				continue
+			default:
+				fmt.Fprintf(s.out, "FAIL %s\n", sc.name)
+				writeSnapshotDiff(s.out, parseSnapshot(want), docs)
+			}
+			if !s.update {
+				failed = append(failed, sc.name)
+				continue
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Recorded %s\n", golden)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d snapshots do not match: %s (use 'helm snapshot verify --update' to record them again)", len(failed), len(cases), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// snapshotCases returns the cases of the chart at chartPath, sorted by name.
+func snapshotCases(chartPath string) ([]snapshotCase, error) {
+	files, err := filepath.Glob(filepath.Join(chartPath, snapshotDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	var cases []snapshotCase
+	for _, f := range files {
+		ext := filepath.Ext(f)
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		cases = append(cases, snapshotCase{
+			name:       strings.TrimSuffix(filepath.Base(f), ext),
+			valuesFile: f,
+		})
+	}
+	if len(cases) == 0 {
+		return []snapshotCase{{name: snapshotDefaultCase}}, nil
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].name < cases[j].name })
+	for i := 1; i < len(cases); i++ {
+		if cases[i].name == cases[i-1].name {
+			return nil, fmt.Errorf("snapshot %s has more than one values file", cases[i].name)
+		}
+	}
+	return cases, nil
+}
+
+// renderSnapshot renders the chart with the values of valuesFile, if set, and
+// returns the normalized manifests by template.
+func renderSnapshot(c *chart.Chart, valuesFile, kubeVersion string) (map[string]string, error) {
+	var files valueFiles
+	if valuesFile != "" {
+		files = valueFiles{valuesFile}
+	}
+	rawVals, err := vals(files, nil, nil, nil, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	var seed int64 = 1
+	opts := renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      "release-name",
+			IsInstall: true,
+			Revision:  1,
+			Time:      timeconv.Timestamp(snapshotTime),
+			Namespace: "default",
+		},
+		KubeVersion: kubeVersion,
+		RenderTime:  snapshotTime,
+		RandomSeed:  &seed,
+	}
+	rendered, err := renderutil.Render(c, &chart.Config{Raw: string(rawVals)}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := map[string]string{}
+	for name, content := range rendered {
+		b := filepath.Base(name)
+		if b == "NOTES.txt" || isOutputsFile(name) || strings.HasPrefix(b, "_") {
+			continue
+		}
+		if content = normalizeManifest(content); content != "" {
+			docs[name] = content
+		}
+	}
+	return docs, nil
+}
+
+// normalizeManifest drops the trailing spaces of the lines of a manifest and
+// its leading and trailing blank lines.
+func normalizeManifest(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+const snapshotSourcePrefix = "# Source: "
+
+// formatSnapshot writes the manifests sorted by template, in the format of
+// 'helm template'.
+func formatSnapshot(docs map[string]string) []byte {
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&b, "---\n%s%s\n%s\n", snapshotSourcePrefix, name, docs[name])
+	}
+	return b.Bytes()
+}
+
+// parseSnapshot reads the manifests of a golden file written by formatSnapshot.
+func parseSnapshot(data []byte) map[string]string {
+	docs := map[string]string{}
+	var name string
+	var lines []string
+	flush := func() {
+		if name != "" {
+			docs[name] = strings.Join(lines, "\n")
+		}
+	}
+	all := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i := 0; i < len(all); i++ {
+		if all[i] == "---" && i+1 < len(all) && strings.HasPrefix(all[i+1], snapshotSourcePrefix) {
+			flush()
+			name, lines = strings.TrimPrefix(all[i+1], snapshotSourcePrefix), nil
+			i++
+			continue
+		}
+		lines = append(lines, all[i])
+	}
+	flush()
+	return docs
+}
+
+// writeSnapshotDiff writes the templates whose manifests differ, with the
+// changed lines of each.
+func writeSnapshotDiff(out io.Writer, want, got map[string]string) {
+	names := map[string]bool{}
+	for name := range want {
+		names[name] = true
+	}
+	for name := range got {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		w, inWant := want[name]
+		g, inGot := got[name]
+		switch {
+		case !inGot:
+			fmt.Fprintf(out, "  %s: no longer rendered\n", name)
+		case !inWant:
+			fmt.Fprintf(out, "  %s: not in the golden file\n", name)
+		case w != g:
+			fmt.Fprintf(out, "  %s:\n", name)
+			for _, l := range diffLines(strings.Split(w, "\n"), strings.Split(g, "\n"), 2) {
+				fmt.Fprintf(out, "    %s\n", l)
+			}
+		}
+	}
+}
+
+// diffLines returns the lines removed from a, prefixed with '-', and added in
+// b, prefixed with '+', with up to context unchanged lines around them. Runs
+// of unchanged lines that are left out are replaced by '...'.
+func diffLines(a, b []string, context int) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, "- "+a[i])
+			i++
+		default:
+			ops = append(ops, "+ "+b[j])
+			j++
+		}
+	}
+
+	keep := make([]bool, len(ops))
+	for k, op := range ops {
+		if op[0] == ' ' {
+			continue
+		}
+		for l := k - context; l <= k+context; l++ {
+			if l >= 0 && l < len(ops) {
+				keep[l] = true
+			}
+		}
+	}
+	var lines []string
+	skipped := false
+	for k, op := range ops {
+		if !keep[k] {
+			skipped = true
+			continue
+		}
+		if skipped && len(lines) > 0 {
+			lines = append(lines, "...")
+		}
+		skipped = false
+		lines = append(lines, op)
+	}
+	return lines
+}
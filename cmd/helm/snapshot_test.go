@@ -0,0 +1,175 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeSnapshotChart(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func runSnapshot(t *testing.T, args ...string) (string, error) {
+	var buf bytes.Buffer
+	cmd := newSnapshotCmd(&buf)
+	cmd.SetArgs(args)
+	cmd.SetOutput(ioutil.Discard)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-snapshot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeSnapshotChart(t, dir, map[string]string{
+		"Chart.yaml":  "apiVersion: v1\nname: snap\nversion: 0.1.0\n",
+		"values.yaml": "replicas: 1\nport: 80\n",
+		"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}   
+  annotations:
+    created: {{ now | date "2006-01-02" }}
+spec:
+  replicas: {{ .Values.replicas }}
+`,
+		"templates/service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\nspec:\n  ports:\n  - port: {{ .Values.port }}\n",
+		"templates/NOTES.txt":    "Installed {{ .Release.Name }}",
+		"snapshots/default.yaml": "",
+		"snapshots/scaled.yaml":  "replicas: 3\n",
+	})
+
+	out, err := runSnapshot(t, "record", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Recorded "+filepath.Join(dir, "snapshots", "scaled.golden")) {
+		t.Errorf("unexpected output %q", out)
+	}
+	golden, err := ioutil.ReadFile(filepath.Join(dir, "snapshots", "scaled.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `---
+# Source: snap/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: release-name
+  annotations:
+    created: 2000-01-01
+spec:
+  replicas: 3
+---
+# Source: snap/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: release-name
+spec:
+  ports:
+  - port: 80
+`
+	if string(golden) != expect {
+		t.Errorf("expected golden file\n%s\ngot\n%s", expect, golden)
+	}
+
+	out, err = runSnapshot(t, "verify", dir)
+	if err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+	if out != "PASS default\nPASS scaled\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	writeSnapshotChart(t, dir, map[string]string{"values.yaml": "replicas: 2\nport: 80\n"})
+	out, err = runSnapshot(t, "verify", dir)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 snapshots do not match: default") {
+		t.Errorf("expected the default snapshot to fail, got %v", err)
+	}
+	if !strings.Contains(out, "FAIL default\n  snap/templates/deployment.yaml:\n") || !strings.Contains(out, "      spec:\n    -   replicas: 1\n    +   replicas: 2\n") {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	if _, err = runSnapshot(t, "verify", "--update", dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = runSnapshot(t, "verify", dir); err != nil {
+		t.Errorf("expected the updated snapshots to pass, got %v", err)
+	}
+}
+
+func TestSnapshotDefaultCase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-snapshot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeSnapshotChart(t, dir, map[string]string{
+		"Chart.yaml":           "apiVersion: v1\nname: snap\nversion: 0.1.0\n",
+		"templates/cm.yaml":    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n",
+		"templates/_help.tpl":  "{{ define \"x\" }}x{{ end }}",
+		"templates/empty.yaml": "{{- if false }}\nkind: Secret\n{{- end }}\n",
+	})
+
+	out, err := runSnapshot(t, "verify", dir)
+	if err == nil || !strings.Contains(out, "FAIL default: no golden file") {
+		t.Errorf("expected a missing golden file, got %v: %q", err, out)
+	}
+	if _, err := runSnapshot(t, "record", dir); err != nil {
+		t.Fatal(err)
+	}
+	golden, err := ioutil.ReadFile(filepath.Join(dir, "snapshots", "default.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "---\n# Source: snap/templates/cm.yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: release-name\n"; string(golden) != expect {
+		t.Errorf("expected golden file %q, got %q", expect, golden)
+	}
+	if !reflect.DeepEqual(parseSnapshot(golden), map[string]string{"snap/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: release-name"}) {
+		t.Errorf("unexpected parsed snapshot %v", parseSnapshot(golden))
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	a := strings.Split("a\nb\nc\nd\ne\nf\ng\nh\ni\nj", "\n")
+	b := strings.Split("a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk", "\n")
+	expect := []string{"  b", "  c", "- d", "+ D", "  e", "  f", "...", "  i", "  j", "+ k"}
+	if got := diffLines(a, b, 2); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
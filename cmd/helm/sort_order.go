@@ -0,0 +1,57 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// sortOrderFile is the file given to --sort-order-file, listing the kinds of
+// resources in the order they are installed and uninstalled in.
+type sortOrderFile struct {
+	Install   []string `json:"install"`
+	Uninstall []string `json:"uninstall"`
+}
+
+// readSortOrderFile returns the install and uninstall orders of the file at
+// path as comma separated lists of kinds. An empty path gives empty orders.
+func readSortOrderFile(path string) (install, uninstall string, err error) {
+	if path == "" {
+		return "", "", nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	var f sortOrderFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return "", "", fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+	if len(f.Install) == 0 && len(f.Uninstall) == 0 {
+		return "", "", fmt.Errorf("%s has neither an install nor an uninstall order", path)
+	}
+	for _, kind := range append(f.Install, f.Uninstall...) {
+		if strings.TrimSpace(kind) == "" || strings.Contains(kind, ",") {
+			return "", "", fmt.Errorf("%s: invalid kind %q", path, kind)
+		}
+	}
+	return strings.Join(f.Install, ","), strings.Join(f.Uninstall, ","), nil
+}
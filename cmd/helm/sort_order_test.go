@@ -0,0 +1,61 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSortOrderFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-sort-order-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		name, data         string
+		install, uninstall string
+		fails              bool
+	}{
+		{"both", "install: [Namespace, Secret, Deployment]\nuninstall: [Deployment, Secret]\n", "Namespace,Secret,Deployment", "Deployment,Secret", false},
+		{"install", "install:\n- Service\n- Deployment\n", "Service,Deployment", "", false},
+		{"empty", "other: [Service]\n", "", "", true},
+		{"comma", "install: [\"Service,Deployment\"]\n", "", "", true},
+	} {
+		path := filepath.Join(dir, test.name+".yaml")
+		if err := ioutil.WriteFile(path, []byte(test.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		install, uninstall, err := readSortOrderFile(path)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+		if install != test.install || uninstall != test.uninstall {
+			t.Errorf("%s: expected %q and %q, got %q and %q", test.name, test.install, test.uninstall, install, uninstall)
+		}
+	}
+}
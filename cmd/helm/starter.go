@@ -0,0 +1,147 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/downloader"
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/helmpath"
+)
+
+const starterHelp = `
+This command consists of multiple subcommands to manage the starters that
+'helm create --starter' scaffolds new charts from.
+
+Starters are kept in $HELM_HOME/starters. They can be installed from a chart
+in a repository or at a URL:
+
+    $ helm starter install stable/web-starter
+    $ helm create --starter web-starter mysite
+`
+
+func newStarterCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "starter install|list|remove [ARGS]",
+		Short: "Install, list, and remove chart starters",
+		Long:  starterHelp,
+	}
+
+	cmd.AddCommand(newStarterInstallCmd(out))
+	cmd.AddCommand(newStarterListCmd(out))
+	cmd.AddCommand(newStarterRemoveCmd(out))
+
+	return cmd
+}
+
+// remoteStarter tells whether a starter is given as a chart reference or URL
+// rather than as the name of an installed starter.
+func remoteStarter(ref string) bool {
+	return strings.Contains(ref, "://") || strings.Contains(ref, "/")
+}
+
+// remoteStarterName returns the name a starter given as a chart reference is
+// installed under, or "" if it cannot be known before fetching the starter.
+func remoteStarterName(ref string) string {
+	if strings.Contains(ref, "://") {
+		return ""
+	}
+	name, _, _, err := downloader.ParseChartReference(ref, "")
+	if err != nil {
+		return ""
+	}
+	return path.Base(name)
+}
+
+// checkStarterName returns an error if name cannot be the name of a starter,
+// as it would not be a directory of its own in the starters.
+func checkStarterName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid starter name %q", name)
+	}
+	return nil
+}
+
+// installStarter fetches the chart ref to the starters, under the name of the
+// chart, which it returns. An installed starter of that name is replaced if
+// replace is set, and kept otherwise, in which case installed is false.
+func installStarter(home helmpath.Home, ref, version string, replace bool, out io.Writer) (name string, installed bool, err error) {
+	if err := os.MkdirAll(home.Starters(), 0755); err != nil {
+		return "", false, err
+	}
+	tmp, err := ioutil.TempDir("", "helm-starter-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(tmp)
+
+	dl := downloader.ChartDownloader{
+		HelmHome: home,
+		Out:      out,
+		Verify:   downloader.VerifyNever,
+		Getters:  getter.All(settings),
+	}
+	saved, _, err := dl.DownloadTo(ref, version, tmp)
+	if err != nil {
+		return "", false, err
+	}
+	c, err := chartutil.Load(saved)
+	if err != nil {
+		return "", false, err
+	}
+	if _, err := chartutil.LoadStarterfile(c); err != nil {
+		return "", false, err
+	}
+
+	name = c.Metadata.Name
+	if err := checkStarterName(name); err != nil {
+		return "", false, err
+	}
+	dest := filepath.Join(home.Starters(), name)
+	if _, err := os.Stat(dest); err == nil && !replace {
+		return name, false, nil
+	}
+
+	// The chart is expanded next to the starters first, so that it is only
+	// moved to dest once it is complete, and only the directory of the chart
+	// named name is moved.
+	staging, err := ioutil.TempDir(home.Starters(), ".install-")
+	if err != nil {
+		return name, false, err
+	}
+	defer os.RemoveAll(staging)
+	if err := chartutil.ExpandFile(staging, saved); err != nil {
+		return name, false, fmt.Errorf("cannot install starter %s: %s", name, err)
+	}
+	if err := os.RemoveAll(dest); err != nil {
+		return name, false, err
+	}
+	if err := os.Rename(filepath.Join(staging, name), dest); err != nil {
+		return name, false, fmt.Errorf("cannot install starter %s: %s", name, err)
+	}
+	return name, true, nil
+}
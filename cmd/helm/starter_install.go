@@ -0,0 +1,69 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+)
+
+type starterInstallCmd struct {
+	ref     string
+	version string
+	force   bool
+	home    helmpath.Home
+	out     io.Writer
+}
+
+func newStarterInstallCmd(out io.Writer) *cobra.Command {
+	inst := &starterInstallCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "install [flags] [chart URL | repo/chartname]",
+		Short: "Install a starter from a chart",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart reference"); err != nil {
+				return err
+			}
+			inst.ref = args[0]
+			inst.home = settings.Home
+			return inst.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&inst.version, "version", "", "Specific version of the chart. Without this, the latest version is installed")
+	f.BoolVar(&inst.force, "force", false, "Replace the starter if it is already installed")
+
+	return cmd
+}
+
+func (i *starterInstallCmd) run() error {
+	name, installed, err := installStarter(i.home, i.ref, i.version, i.force, i.out)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return fmt.Errorf("starter %q is already installed, use --force to replace it", name)
+	}
+	fmt.Fprintf(i.out, "Installed starter %q\n", name)
+	return nil
+}
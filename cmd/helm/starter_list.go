@@ -0,0 +1,87 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm/helmpath"
+)
+
+type starterListCmd struct {
+	out  io.Writer
+	home helmpath.Home
+}
+
+func newStarterListCmd(out io.Writer) *cobra.Command {
+	list := &starterListCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "list [flags]",
+		Short: "List the installed starters",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list.home = settings.Home
+			return list.run()
+		},
+	}
+
+	return cmd
+}
+
+func (s *starterListCmd) run() error {
+	dirs, err := ioutil.ReadDir(s.home.Starters())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	table := uitable.New()
+	table.AddRow("NAME", "VERSION", "VARIABLES", "DESCRIPTION")
+	found := false
+	for _, d := range dirs {
+		// Starters being installed are expanded to hidden directories.
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		c, err := chartutil.LoadDir(filepath.Join(s.home.Starters(), d.Name()))
+		if err != nil {
+			debug("skipping starter %s: %s", d.Name(), err)
+			continue
+		}
+		var vars []string
+		if sf, err := chartutil.LoadStarterfile(c); err == nil {
+			for _, v := range sf.Variables {
+				vars = append(vars, v.Name)
+			}
+		}
+		table.AddRow(d.Name(), c.Metadata.Version, strings.Join(vars, ","), c.Metadata.Description)
+		found = true
+	}
+	if !found {
+		return errors.New("no starters to show")
+	}
+	fmt.Fprintln(s.out, table)
+	return nil
+}
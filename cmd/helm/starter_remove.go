@@ -0,0 +1,75 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+)
+
+type starterRemoveCmd struct {
+	out  io.Writer
+	name string
+	home helmpath.Home
+}
+
+func newStarterRemoveCmd(out io.Writer) *cobra.Command {
+	remove := &starterRemoveCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:     "remove [flags] [NAME]",
+		Aliases: []string{"rm"},
+		Short:   "Remove a starter",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("need at least one argument, name of starter")
+			}
+
+			remove.home = settings.Home
+			for i := 0; i < len(args); i++ {
+				remove.name = args[i]
+				if err := remove.run(); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func (r *starterRemoveCmd) run() error {
+	if err := checkStarterName(r.name); err != nil {
+		return err
+	}
+	dir := filepath.Join(r.home.Starters(), r.name)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("no starter named %q found", r.name)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.out, "%q has been removed from your starters\n", r.name)
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/repo/repotest"
+)
+
+func TestStarterCmds(t *testing.T) {
+	hh, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := resetEnv()
+	defer func() {
+		os.RemoveAll(hh.String())
+		cleanup()
+	}()
+	settings.Home = hh
+
+	srv := repotest.NewServer(hh.String())
+	defer srv.Stop()
+	if _, err := srv.CopyCharts("testdata/testcharts/*.tgz*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.LinkIndices(); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, error) {
+		buf := bytes.NewBuffer(nil)
+		cmd := newStarterCmd(buf)
+		cmd.SetArgs(args)
+		cmd.SetOutput(buf)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	if out, err := run("install", "test/signtest"); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(out, `Installed starter "signtest"`) {
+		t.Errorf("unexpected output %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(hh.Starters(), "signtest", "Chart.yaml")); err != nil {
+		t.Errorf("expected the starter to be installed: %s", err)
+	}
+	if _, err := run("install", "test/signtest"); err == nil || !strings.Contains(err.Error(), "already installed") {
+		t.Errorf("expected an installed starter to be kept, got %v", err)
+	}
+	if _, err := run("install", "--force", "test/signtest"); err != nil {
+		t.Errorf("expected --force to replace the starter, got %s", err)
+	}
+
+	out, err := run("list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "signtest") || !strings.Contains(out, "0.1.0") {
+		t.Errorf("expected the starter to be listed, got %q", out)
+	}
+
+	// A remote starter that is installed is used without fetching it.
+	buf := bytes.NewBuffer(nil)
+	cc := &createCmd{home: hh, name: filepath.Join(hh.String(), "newchart"), starter: "test/signtest", out: buf}
+	if err := cc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Fetching starter") {
+		t.Errorf("expected the cached starter to be used, got %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(hh.String(), "newchart", "Chart.yaml")); err != nil {
+		t.Errorf("expected the chart to be created: %s", err)
+	}
+
+	if _, err := run("remove", "../repository"); err == nil {
+		t.Error("expected a path to be refused")
+	}
+	if _, err := run("remove", "signtest"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run("remove", "signtest"); err == nil {
+		t.Error("expected an error removing a missing starter")
+	}
+
+	// A remote starter is fetched when it is not installed.
+	buf.Reset()
+	cc.name = filepath.Join(hh.String(), "otherchart")
+	if err := cc.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Fetching starter test/signtest") {
+		t.Errorf("expected the starter to be fetched, got %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(hh.Starters(), "signtest")); err != nil {
+		t.Errorf("expected the fetched starter to be cached: %s", err)
+	}
+}
+
+func TestInstallStarterName(t *testing.T) {
+	hh, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := resetEnv()
+	defer func() {
+		os.RemoveAll(hh.String())
+		cleanup()
+	}()
+	settings.Home = hh
+
+	srv := repotest.NewServer(hh.String())
+	defer srv.Stop()
+
+	// A chart named ".." would be installed as the parent of the starters.
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "..", Version: "0.1.0"}}
+	saved, err := chartutil.Save(c, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CreateIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.LinkIndices(); err != nil {
+		t.Fatal(err)
+	}
+	keep := filepath.Join(hh.String(), "keep")
+	if err := os.Mkdir(keep, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := srv.URL() + "/" + filepath.Base(saved)
+	if _, _, err := installStarter(hh, ref, "", true, ioutil.Discard); err == nil || !strings.Contains(err.Error(), "invalid starter name") {
+		t.Errorf("expected the starter name to be refused, got %v", err)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("expected the Helm home to be kept: %s", err)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/timeconv"
 )
 
@@ -41,6 +42,14 @@ The status consists of:
 - k8s namespace in which the release lives
 - state of the release (can be: UNKNOWN, DEPLOYED, DELETED, SUPERSEDED, FAILED or DELETING)
 - list of resources that this release consists of, sorted by kind
+- with --show-resources, the current health of each resource as found in the
+  cluster, such as ready replicas, pod phases and load balancer addresses
+- with --detect-drift, the fields of each resource that were changed in the
+  cluster since it was deployed, and resources that were removed
+
+Fields that are not set in the release manifest, such as the ones defaulted by
+the cluster, are not compared. When drift is found, the command exits with a
+non-zero status.
 - details on last test suite run, if applicable
 - additional notes provided by the chart
 `
@@ -51,6 +60,8 @@ type statusCmd struct {
 	client  helm.Interface
 	version int32
 	outfmt  string
+	health  bool
+	drift   bool
 }
 
 func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -80,6 +91,8 @@ func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	settings.AddFlagsTLS(f)
 	f.Int32Var(&status.version, "revision", 0, "If set, display the status of the named release with revision")
 	f.StringVarP(&status.outfmt, "output", "o", "", "Output the status in the specified format (json or yaml)")
+	f.BoolVar(&status.health, "show-resources", false, "Query the cluster for the current health of each resource of the release")
+	f.BoolVar(&status.drift, "detect-drift", false, "Compare the resources of the release with their live state, and fail if they differ")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -88,32 +101,80 @@ func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (s *statusCmd) run() error {
-	res, err := s.client.ReleaseStatus(s.release, helm.StatusReleaseVersion(s.version))
+	res, err := s.client.ReleaseStatus(s.release, helm.StatusReleaseVersion(s.version), helm.StatusReleaseHealth(s.health))
 	if err != nil {
 		return prettyError(err)
 	}
 
+	var output interface{} = res
+	var drifts []releaseutil.Drift
+	if s.drift {
+		if drifts, err = s.detectDrift(); err != nil {
+			return err
+		}
+		output = &statusWithDrift{GetReleaseStatusResponse: res, Drift: drifts}
+	}
+
 	switch s.outfmt {
 	case "":
 		PrintStatus(s.out, res)
-		return nil
+		if s.drift {
+			printDrift(s.out, drifts)
+		}
 	case "json":
-		data, err := json.Marshal(res)
+		data, err := json.Marshal(output)
 		if err != nil {
 			return fmt.Errorf("Failed to Marshal JSON output: %s", err)
 		}
 		s.out.Write(data)
-		return nil
 	case "yaml":
-		data, err := yaml.Marshal(res)
+		data, err := yaml.Marshal(output)
 		if err != nil {
 			return fmt.Errorf("Failed to Marshal YAML output: %s", err)
 		}
 		s.out.Write(data)
-		return nil
+	default:
+		return fmt.Errorf("Unknown output format %q", s.outfmt)
 	}
 
-	return fmt.Errorf("Unknown output format %q", s.outfmt)
+	if len(drifts) > 0 {
+		return fmt.Errorf("release %s has drifted: %d of its resources differ from the release manifest", s.release, len(drifts))
+	}
+	return nil
+}
+
+// statusWithDrift is the status of a release as output with --detect-drift.
+type statusWithDrift struct {
+	*services.GetReleaseStatusResponse
+	Drift []releaseutil.Drift `json:"drift"`
+}
+
+// detectDrift compares the stored manifest of the release with the live state
+// of its objects.
+func (s *statusCmd) detectDrift() ([]releaseutil.Drift, error) {
+	res, err := s.client.ReleaseContent(s.release, helm.ContentReleaseVersion(s.version), helm.ContentLiveManifest(true))
+	if err != nil {
+		return nil, prettyError(err)
+	}
+	return releaseutil.DetectDrift(res.Release.Manifest, res.LiveManifest)
+}
+
+func printDrift(out io.Writer, drifts []releaseutil.Drift) {
+	if len(drifts) == 0 {
+		fmt.Fprintf(out, "DRIFT: none\n")
+		return
+	}
+	fmt.Fprintf(out, "DRIFT:\n")
+	for _, d := range drifts {
+		if d.Missing {
+			fmt.Fprintf(out, "%s/%s: not found in the cluster\n", d.Kind, d.Name)
+			continue
+		}
+		fmt.Fprintf(out, "%s/%s:\n", d.Kind, d.Name)
+		for _, f := range d.Fields {
+			fmt.Fprintf(out, "  %s: expected %s, found %s\n", f.Path, f.Expected, f.Actual)
+		}
+	}
 }
 
 // PrintStatus prints out the status of a release. Shared because also used by
@@ -124,6 +185,18 @@ func PrintStatus(out io.Writer, res *services.GetReleaseStatusResponse) {
 	}
 	fmt.Fprintf(out, "NAMESPACE: %s\n", res.Namespace)
 	fmt.Fprintf(out, "STATUS: %s\n", res.Info.Status.Code)
+	if res.Info.ServiceAccount != "" {
+		fmt.Fprintf(out, "SERVICE ACCOUNT: %s\n", res.Info.ServiceAccount)
+	}
+	if res.Info.User != "" {
+		fmt.Fprintf(out, "APPLIED BY: %s\n", res.Info.User)
+	}
+	if res.Info.FailurePhase != "" {
+		fmt.Fprintf(out, "FAILED PHASE: %s\n", res.Info.FailurePhase)
+	}
+	if res.Info.LastError != "" {
+		fmt.Fprintf(out, "LAST ERROR: %s\n", res.Info.LastError)
+	}
 	fmt.Fprintf(out, "\n")
 	if len(res.Info.Status.Resources) > 0 {
 		re := regexp.MustCompile("  +")
@@ -132,6 +205,18 @@ func PrintStatus(out io.Writer, res *services.GetReleaseStatusResponse) {
 		fmt.Fprintf(w, "RESOURCES:\n%s\n", re.ReplaceAllString(res.Info.Status.Resources, "\t"))
 		w.Flush()
 	}
+	if len(res.Info.Readiness) > 0 {
+		fmt.Fprintf(out, "READINESS:\n%s\n\n", formatReadiness(res.Info.Readiness))
+	}
+	if len(res.Health) > 0 {
+		ready := 0
+		for _, h := range res.Health {
+			if h.Ready {
+				ready++
+			}
+		}
+		fmt.Fprintf(out, "HEALTH: %d/%d resources ready\n%s\n\n", ready, len(res.Health), formatReadiness(res.Health))
+	}
 	if res.Info.Status.LastTestSuiteRun != nil {
 		lastRun := res.Info.Status.LastTestSuiteRun
 		fmt.Fprintf(out, "TEST SUITE:\n%s\n%s\n\n%s\n",
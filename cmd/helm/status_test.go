@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"testing"
@@ -26,6 +27,7 @@ import (
 
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/timeconv"
 )
 
@@ -57,6 +59,49 @@ func TestStatusCmd(t *testing.T) {
 				}),
 			},
 		},
+		{
+			name:     "get status of a release applied as a service account",
+			args:     []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\nSERVICE ACCOUNT: deployer\n\n"),
+			rels: []*release.Release{
+				func() *release.Release {
+					r := releaseMockWithStatus(&release.Status{
+						Code: release.Status_DEPLOYED,
+					})
+					r.Info.ServiceAccount = "deployer"
+					return r
+				}(),
+			},
+		},
+		{
+			name:     "get status of a release applied as the calling user",
+			args:     []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\nAPPLIED BY: alice\n\n"),
+			rels: []*release.Release{
+				func() *release.Release {
+					r := releaseMockWithStatus(&release.Status{
+						Code: release.Status_DEPLOYED,
+					})
+					r.Info.User = "alice"
+					return r
+				}(),
+			},
+		},
+		{
+			name:     "get status of a failed release",
+			args:     []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("FAILED\nFAILED PHASE: wait\nLAST ERROR: timed out waiting for the condition\n\n"),
+			rels: []*release.Release{
+				func() *release.Release {
+					r := releaseMockWithStatus(&release.Status{
+						Code: release.Status_FAILED,
+					})
+					r.Info.FailurePhase = "wait"
+					r.Info.LastError = "timed out waiting for the condition"
+					return r
+				}(),
+			},
+		},
 		{
 			name:     "get status of a deployed release with notes in json",
 			args:     []string{"flummoxed-chickadee"},
@@ -92,6 +137,42 @@ func TestStatusCmd(t *testing.T) {
 				}),
 			},
 		},
+		{
+			name:  "get status of a deployed release with resource health",
+			args:  []string{"flummoxed-chickadee"},
+			flags: []string{"--show-resources"},
+			expected: "HEALTH: 1/2 resources ready\n" +
+				"KIND (.*)\tNAME\tREADY\tMESSAGE(.*)\n" +
+				"Deployment\tweb (.*)\ttrue (.*)\t1/1 replicas ready\n" +
+				"Service (.*)\tweb (.*)\tfalse\tnot found(.*)\n\n",
+			rels: []*release.Release{
+				releaseMockWithHealth(),
+			},
+		},
+		{
+			name:     "get status of a deployed release with resource health in json",
+			args:     []string{"flummoxed-chickadee"},
+			flags:    []string{"--show-resources", "-o", "json"},
+			expected: `"health":\[{"kind":"Deployment","name":"web","ready":true,"message":"1/1 replicas ready"},{"kind":"Service","name":"web","message":"not found"}\]`,
+			rels: []*release.Release{
+				releaseMockWithHealth(),
+			},
+		},
+		{
+			name:     "get status of a deployed release without drift",
+			args:     []string{"flummoxed-chickadee"},
+			flags:    []string{"--detect-drift"},
+			expected: outputWithStatus("DEPLOYED\n\nDRIFT: none\n$"),
+			rels: []*release.Release{
+				func() *release.Release {
+					r := releaseMockWithStatus(&release.Status{
+						Code: release.Status_DEPLOYED,
+					})
+					r.Manifest = "kind: ConfigMap\nmetadata:\n  name: a\n"
+					return r
+				}(),
+			},
+		},
 		{
 			name: "get status of a deployed release with test suite",
 			args: []string{"flummoxed-chickadee"},
@@ -133,6 +214,20 @@ func TestStatusCmd(t *testing.T) {
 
 }
 
+func TestPrintDrift(t *testing.T) {
+	var buf bytes.Buffer
+	printDrift(&buf, []releaseutil.Drift{
+		{Kind: "Deployment", Name: "web", Fields: []releaseutil.FieldDrift{
+			{Path: "spec.replicas", Expected: "2", Actual: "3"},
+		}},
+		{Kind: "Service", Name: "web", Missing: true},
+	})
+	expect := "DRIFT:\nDeployment/web:\n  spec.replicas: expected 2, found 3\nService/web: not found in the cluster\n"
+	if buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
 func outputWithStatus(status string) string {
 	return fmt.Sprintf("LAST DEPLOYED: %s\nNAMESPACE: \nSTATUS: %s",
 		dateString,
@@ -149,3 +244,16 @@ func releaseMockWithStatus(status *release.Status) *release.Release {
 		},
 	}
 }
+
+// releaseMockWithHealth returns a release whose recorded readiness the fake
+// client reports as its health.
+func releaseMockWithHealth() *release.Release {
+	r := releaseMockWithStatus(&release.Status{
+		Code: release.Status_DEPLOYED,
+	})
+	r.Info.Readiness = []*release.ResourceReadiness{
+		{Kind: "Deployment", Name: "web", Ready: true, Message: "1/1 replicas ready"},
+		{Kind: "Service", Name: "web", Message: "not found"},
+	}
+	return r
+}
@@ -0,0 +1,43 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const storageHelp = `
+This command consists of subcommands working on the storage backend holding
+the release records of Tiller, without going through Tiller.
+
+Example usage:
+    $ helm storage migrate --from configmap --to secret
+`
+
+func newStorageCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage [FLAGS] migrate [ARGS]",
+		Short: "Manage the storage backend of Tiller",
+		Long:  storageHelp,
+	}
+
+	cmd.AddCommand(newStorageMigrateCmd(out))
+
+	return cmd
+}
@@ -0,0 +1,174 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+const storageMigrateHelp = `
+This command copies every release record of Tiller from a storage driver to
+another, so that Tiller can be switched to another storage backend without
+losing the history of its releases:
+
+    $ helm storage migrate --from configmap --to secret --dry-run
+    $ helm storage migrate --from configmap --to secret
+
+The records are read and written directly, in the namespace of Tiller for the
+configmap and secret drivers. The settings of other drivers are given as
+KEY=VALUE pairs, as with the --storage-opt flag of Tiller:
+
+    $ helm storage migrate --from configmap --to sql \
+        --to-opt connection-string=postgres://helm@db/helm
+
+Each record copied is read back and compared with its source. Records already
+in the target driver are skipped, so an interrupted migration can be run
+again. Records held with different contents by the target driver are reported
+and left as they are. The source records are never changed.
+
+Stop Tiller before migrating, and start it with the new driver afterwards: a
+release locked by an operation in progress makes the migration fail.
+`
+
+type storageMigrateCmd struct {
+	from       string
+	to         string
+	fromOpts   []string
+	toOpts     []string
+	dryRun     bool
+	quiet      bool
+	namespace  string
+	out        io.Writer
+	kubeClient kubernetes.Interface
+}
+
+func newStorageMigrateCmd(out io.Writer) *cobra.Command {
+	m := &storageMigrateCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "migrate --from DRIVER --to DRIVER",
+		Short: "Copy the release records of Tiller to another storage driver",
+		Long:  storageMigrateHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("this command does not accept arguments")
+			}
+			if m.from == "" || m.to == "" {
+				return errors.New("both --from and --to are required")
+			}
+			m.namespace = settings.TillerNamespace
+			return m.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&m.from, "from", "", fmt.Sprintf("Storage driver to copy the records from. One of %s", strings.Join(driver.Registered(), ", ")))
+	f.StringVar(&m.to, "to", "", "Storage driver to copy the records to")
+	f.StringArrayVar(&m.fromOpts, "from-opt", nil, "Setting of the source driver of the form key=value (can specify multiple)")
+	f.StringArrayVar(&m.toOpts, "to-opt", nil, "Setting of the target driver of the form key=value (can specify multiple)")
+	f.BoolVar(&m.dryRun, "dry-run", false, "List the records to copy without writing them")
+	f.BoolVar(&m.quiet, "quiet", false, "Print nothing on success")
+
+	return cmd
+}
+
+func (m *storageMigrateCmd) run() error {
+	m.from, m.to = storageDriverName(m.from), storageDriverName(m.to)
+	if m.from == m.to && m.from != "sql" {
+		return fmt.Errorf("--from and --to are both the %s driver", m.from)
+	}
+	from, err := m.driver(m.from, m.fromOpts)
+	if err != nil {
+		return err
+	}
+	to, err := m.driver(m.to, m.toOpts)
+	if err != nil {
+		return err
+	}
+
+	migration := &storage.Migration{From: from, To: to, DryRun: m.dryRun, Log: debug}
+	res, err := migration.Run()
+	if err != nil {
+		return err
+	}
+	if m.dryRun {
+		for _, key := range res.Copied {
+			fmt.Fprintf(m.out, "Would copy %s\n", key)
+		}
+	}
+	if len(res.Conflicts) > 0 {
+		return fmt.Errorf("%d records differ in the %s driver and were not copied: %s", len(res.Conflicts), m.to, strings.Join(res.Conflicts, ", "))
+	}
+	if !m.dryRun {
+		printMessage(m.out, m.quiet, msgStorageMigrated, len(res.Copied), m.from, m.to, len(res.Skipped))
+	}
+	return nil
+}
+
+// driver creates the storage driver registered under name, with the settings
+// given as key=value pairs.
+func (m *storageMigrateCmd) driver(name string, opts []string) (driver.Driver, error) {
+	if name == "memory" {
+		return nil, errors.New("the memory storage driver keeps no records to migrate")
+	}
+	config := map[string]string{}
+	for _, opt := range opts {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("storage option %q is not of the form key=value", opt)
+		}
+		config[kv[0]] = kv[1]
+	}
+	if _, ok := config["dialect"]; !ok && name == "sql" {
+		config["dialect"] = "postgres"
+	}
+
+	if m.kubeClient == nil && (name == "configmap" || name == "secret") {
+		_, c, err := getKubeClient(settings.KubeContext, settings.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+		m.kubeClient = c
+	}
+	return driver.New(name, driver.Options{
+		Namespace: m.namespace,
+		Clientset: m.kubeClient,
+		Config:    config,
+		Log:       debug,
+	})
+}
+
+// storageDriverName returns the name a storage driver is registered under,
+// also accepting the plural of the Kubernetes resources holding the records,
+// such as configmaps.
+func storageDriverName(name string) string {
+	for _, registered := range driver.Registered() {
+		if name == registered+"s" {
+			return registered
+		}
+	}
+	return name
+}
@@ -0,0 +1,97 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestStorageMigrateCmd(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	cfgmaps := driver.NewConfigMaps(client.CoreV1().ConfigMaps("kube-system"))
+	for _, rls := range []*release.Release{
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide", Version: 2}),
+	} {
+		if err := cfgmaps.Create(fmt.Sprintf("%s.v%d", rls.Name, rls.Version), rls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	secrets := driver.NewSecrets(client.CoreV1().Secrets("kube-system"))
+
+	var buf bytes.Buffer
+	m := &storageMigrateCmd{from: "configmaps", to: "secrets", dryRun: true, namespace: "kube-system", out: &buf, kubeClient: client}
+	if err := m.run(); err != nil {
+		t.Fatalf("Failed dry run: %s", err)
+	}
+	if want := "Would copy thomas-guide.v1\nWould copy thomas-guide.v2\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+	if _, err := secrets.Get("thomas-guide.v1"); err == nil {
+		t.Error("expected nothing to be copied in a dry run")
+	}
+
+	buf.Reset()
+	m = &storageMigrateCmd{from: "configmap", to: "secret", namespace: "kube-system", out: &buf, kubeClient: client}
+	if err := m.run(); err != nil {
+		t.Fatalf("Failed migration: %s", err)
+	}
+	if want := "2 release records have been copied from the configmap driver to the secret driver, 0 were there already.\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+	if rls, err := secrets.Get("thomas-guide.v2"); err != nil || rls.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("expected revision 2 to be copied, got %v (%v)", rls, err)
+	}
+
+	// running it again copies nothing
+	buf.Reset()
+	if err := m.run(); err != nil {
+		t.Fatalf("Failed migration: %s", err)
+	}
+	if !strings.HasPrefix(buf.String(), "0 release records have been copied") {
+		t.Errorf("expected nothing to be copied again, got %q", buf.String())
+	}
+}
+
+func TestStorageMigrateCmdErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		opts     []string
+		err      string
+	}{
+		{"same driver", "secret", "secrets", nil, "both the secret driver"},
+		{"memory", "memory", "secret", nil, "memory storage driver"},
+		{"unknown driver", "configmap", "etcd", nil, "unknown storage driver"},
+		{"invalid option", "configmap", "sql", []string{"dialect"}, "not of the form key=value"},
+	}
+	for _, tt := range tests {
+		m := &storageMigrateCmd{from: tt.from, to: tt.to, toOpts: tt.opts, namespace: "kube-system", out: &bytes.Buffer{}, kubeClient: fake.NewSimpleClientset()}
+		if err := m.run(); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
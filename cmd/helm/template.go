@@ -24,6 +24,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,9 +32,11 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kubeschema"
 	"k8s.io/helm/pkg/manifest"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/renderutil"
 	"k8s.io/helm/pkg/tiller"
 	"k8s.io/helm/pkg/timeconv"
@@ -59,6 +62,39 @@ is done.
 To render just one template in a chart, use '-x':
 
 	$ helm template mychart -x templates/deployment.yaml
+
+To only show some of the templates, use '--show-only' with paths or glob
+patterns relative to the chart directory. A '*' does not match a '/':
+
+	$ helm template mychart --show-only templates/deployment.yaml --show-only 'charts/*/templates/*.yaml'
+
+The templates of the subcharts of an umbrella chart are rendered with the
+values the chart gives them, and shown with paths such as
+'mychart/charts/mysql/templates/secret.yaml'. Use '--with-subcharts=false' to
+only show the templates of the chart itself.
+
+With '--output-dir', every rendered template is written to a file of the same
+path under the directory, such as 'out/mychart/templates/deployment.yaml'.
+
+To check the rendered manifests against the Kubernetes API schemas compiled
+into Helm and the API versions served by '--kube-version', use '--validate'.
+Unknown fields, values of the wrong type and apiVersions that are not served
+fail the command, deprecated apiVersions are reported as warnings:
+
+	$ helm template mychart --kube-version 1.16 --validate
+
+Functions such as 'now' and 'randAlphaNum' make the output change from one
+run to the next. To get reproducible output, for instance for golden files or
+GitOps diffs, pin the time with '--render-time' and seed the random functions
+with '--random-seed'. They default to $HELM_RENDER_TIME and $HELM_RANDOM_SEED:
+
+	$ helm template mychart --render-time 2019-01-01T00:00:00Z --random-seed 42
+
+The custom resource definitions of the 'crds/' directory of the chart are not
+templates, and are only printed, before the templates, with '--include-crds'.
+
+As there is no cluster to query, the 'lookup' function finds no objects. The
+'getHostByName' function only resolves names with '--enable-dns-lookups'.
 `
 
 type templateCmd struct {
@@ -73,9 +109,20 @@ type templateCmd struct {
 	showNotes        bool
 	releaseName      string
 	releaseIsUpgrade bool
+	revision         int
+	previousValues   string
 	renderFiles      []string
 	kubeVersion      string
 	outputDir        string
+	validate         bool
+	renderTime       string
+	randomSeed       string
+	enableDNS        bool
+	strict           bool
+	showOnly         []string
+	includeCRDs      bool
+	sortOrderFile    string
+	withSubcharts    bool
 }
 
 func newTemplateCmd(out io.Writer) *cobra.Command {
@@ -92,10 +139,13 @@ func newTemplateCmd(out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.BoolVar(&t.showNotes, "notes", false, "Show the computed NOTES.txt file as well")
+	f.BoolVar(&t.showNotes, "notes", false, "Show the computed NOTES.txt and outputs.yaml files as well")
 	f.StringVarP(&t.releaseName, "name", "n", "release-name", "Release name")
 	f.BoolVar(&t.releaseIsUpgrade, "is-upgrade", false, "Set .Release.IsUpgrade instead of .Release.IsInstall")
+	f.IntVar(&t.revision, "revision", 0, "Set .Release.Revision. Defaults to 1, or 2 with --is-upgrade")
+	f.StringVar(&t.previousValues, "previous-values", "", "YAML file of the values exposed as .Release.PreviousValues to the charts that opt in")
 	f.StringArrayVarP(&t.renderFiles, "execute", "x", []string{}, "Only execute the given templates")
+	f.StringArrayVarP(&t.showOnly, "show-only", "s", []string{}, "Only show the templates matching this path or glob pattern, relative to the chart (can specify multiple)")
 	f.VarP(&t.valueFiles, "values", "f", "Specify values in a YAML file (can specify multiple)")
 	f.StringVar(&t.namespace, "namespace", "", "Namespace to install the release into")
 	f.StringArrayVar(&t.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
@@ -104,6 +154,14 @@ func newTemplateCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&t.nameTemplate, "name-template", "", "Specify template used to name the release")
 	f.StringVar(&t.kubeVersion, "kube-version", defaultKubeVersion, "Kubernetes version used as Capabilities.KubeVersion.Major/Minor")
 	f.StringVar(&t.outputDir, "output-dir", "", "Writes the executed templates to files in output-dir instead of stdout")
+	f.BoolVar(&t.validate, "validate", false, "Validate the rendered manifests against the Kubernetes schemas of --kube-version")
+	f.StringVar(&t.renderTime, "render-time", os.Getenv("HELM_RENDER_TIME"), "RFC 3339 time returned by 'now' and used as the release time, for reproducible output")
+	f.BoolVar(&t.strict, "strict", false, "Fail rendering on values used by the templates but not set, instead of rendering them empty")
+	f.BoolVar(&t.enableDNS, "enable-dns-lookups", false, "Let the 'getHostByName' template function resolve names")
+	f.BoolVar(&t.includeCRDs, "include-crds", false, "Include the custom resource definitions of the crds directory of the chart in the output")
+	f.BoolVar(&t.withSubcharts, "with-subcharts", true, "Show the templates of the subcharts as well")
+	f.StringVar(&t.sortOrderFile, "sort-order-file", "", "YAML file listing the kinds of resources in the order to print them in")
+	f.StringVar(&t.randomSeed, "random-seed", os.Getenv("HELM_RANDOM_SEED"), "Integer seed for 'randAlphaNum' and the other random functions, for reproducible output")
 
 	return cmd
 }
@@ -156,16 +214,50 @@ func (t *templateCmd) run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return prettyError(err)
 	}
+	if err := chartutil.IsChartInstallable(c); err != nil {
+		return err
+	}
 
 	renderOpts := renderutil.Options{
 		ReleaseOptions: chartutil.ReleaseOptions{
 			Name:      t.releaseName,
 			IsInstall: !t.releaseIsUpgrade,
 			IsUpgrade: t.releaseIsUpgrade,
+			Revision:  t.revision,
 			Time:      timeconv.Now(),
 			Namespace: t.namespace,
 		},
-		KubeVersion: t.kubeVersion,
+		KubeVersion:      t.kubeVersion,
+		EnableDNSLookups: t.enableDNS,
+		Strict:           t.strict,
+	}
+	if t.revision == 0 {
+		renderOpts.ReleaseOptions.Revision = 1
+		if t.releaseIsUpgrade {
+			renderOpts.ReleaseOptions.Revision = 2
+		}
+	}
+	if t.previousValues != "" {
+		previous, err := chartutil.ReadValuesFile(t.previousValues)
+		if err != nil {
+			return fmt.Errorf("failed to read previous values: %s", err)
+		}
+		renderOpts.ReleaseOptions.PreviousValues = previous
+	}
+	if t.renderTime != "" {
+		renderTime, err := time.Parse(time.RFC3339, t.renderTime)
+		if err != nil {
+			return fmt.Errorf("invalid render time %q: %s", t.renderTime, err)
+		}
+		renderOpts.RenderTime = renderTime
+		renderOpts.ReleaseOptions.Time = timeconv.Timestamp(renderTime)
+	}
+	if t.randomSeed != "" {
+		seed, err := strconv.ParseInt(t.randomSeed, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid random seed %q: %s", t.randomSeed, err)
+		}
+		renderOpts.RandomSeed = &seed
 	}
 
 	renderedTemplates, err := renderutil.Render(c, config, renderOpts)
@@ -226,10 +318,47 @@ func (t *templateCmd) run(cmd *cobra.Command, args []string) error {
 		manifestsToRender = listManifests
 	}
 
-	for _, m := range tiller.SortByKind(manifestsToRender) {
+	if !t.withSubcharts {
+		manifestsToRender = withoutSubcharts(manifestsToRender)
+	}
+
+	if len(t.showOnly) > 0 {
+		if manifestsToRender, err = showOnly(manifestsToRender, t.showOnly); err != nil {
+			return err
+		}
+	}
+
+	if t.validate {
+		if err := validateManifests(os.Stderr, manifestsToRender, t.kubeVersion); err != nil {
+			return err
+		}
+	}
+
+	if t.includeCRDs {
+		for _, crd := range chartutil.CRDs(c) {
+			if t.outputDir != "" {
+				if err := writeToFile(t.outputDir, crd.Name, string(crd.Data)); err != nil {
+					return err
+				}
+				continue
+			}
+			fmt.Printf("---\n# Source: %s\n", crd.Name)
+			fmt.Println(string(crd.Data))
+		}
+	}
+
+	installOrder, uninstallOrder, err := readSortOrderFile(t.sortOrderFile)
+	if err != nil {
+		return err
+	}
+	order, _ := tiller.SortOrders(c, map[string]string{
+		tiller.InstallOrderAnno:   installOrder,
+		tiller.UninstallOrderAnno: uninstallOrder,
+	})
+	for _, m := range tiller.SortByOrder(manifestsToRender, order) {
 		data := m.Content
 		b := filepath.Base(m.Name)
-		if !t.showNotes && b == "NOTES.txt" {
+		if !t.showNotes && (b == "NOTES.txt" || isOutputsFile(m.Name)) {
 			continue
 		}
 		if strings.HasPrefix(b, "_") {
@@ -253,6 +382,92 @@ func (t *templateCmd) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// withoutSubcharts returns the manifests of the templates of the chart
+// itself, leaving out the ones of its subcharts.
+func withoutSubcharts(manifests []manifest.Manifest) []manifest.Manifest {
+	var own []manifest.Manifest
+	for _, m := range manifests {
+		// manifest.Name starts with the chart name and uses '/' on every OS
+		parts := strings.SplitN(m.Name, "/", 3)
+		if len(parts) == 3 && parts[1] == chartutil.ChartsDir {
+			continue
+		}
+		own = append(own, m)
+	}
+	return own
+}
+
+// showOnly returns the manifests whose path relative to the chart matches one
+// of the patterns. Every pattern must match at least one manifest.
+func showOnly(manifests []manifest.Manifest, patterns []string) ([]manifest.Manifest, error) {
+	var shown []manifest.Manifest
+	matched := make([]bool, len(patterns))
+	for _, m := range manifests {
+		// manifest.Name starts with the chart name and uses '/' on every OS
+		rel := m.Name
+		if i := strings.Index(rel, "/"); i >= 0 {
+			rel = rel[i+1:]
+		}
+		show := false
+		for i, pattern := range patterns {
+			ok, err := path.Match(filepath.ToSlash(path.Clean(pattern)), rel)
+			if err != nil {
+				return nil, fmt.Errorf("invalid template pattern %s: %s", pattern, err)
+			}
+			if ok {
+				matched[i] = true
+				show = true
+			}
+		}
+		if show {
+			shown = append(shown, m)
+		}
+	}
+	for i, pattern := range patterns {
+		if !matched[i] {
+			return nil, fmt.Errorf("could not find template %s in chart", pattern)
+		}
+	}
+	return shown, nil
+}
+
+// validateManifests validates the rendered manifests against the Kubernetes
+// schemas of kubeVersion. Deprecated apiVersions are written to out as
+// warnings, and every other finding fails the validation.
+func validateManifests(out io.Writer, manifests []manifest.Manifest, kubeVersion string) error {
+	validator, err := kubeschema.NewValidator(kubeVersion)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, m := range manifests {
+		b := filepath.Base(m.Name)
+		if b == "NOTES.txt" || isOutputsFile(m.Name) || strings.HasPrefix(b, "_") {
+			continue
+		}
+		docs := releaseutil.SplitManifests(m.Content)
+		for i := 0; i < len(docs); i++ {
+			findings, err := validator.Validate(docs[fmt.Sprintf("manifest-%d", i)])
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", m.Name, err))
+				continue
+			}
+			for _, f := range findings {
+				if f.Type == kubeschema.DeprecatedAPI {
+					fmt.Fprintf(out, "WARNING: %s: %s\n", m.Name, f)
+					continue
+				}
+				failures = append(failures, fmt.Sprintf("%s: %s", m.Name, f))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("manifests failed validation against Kubernetes %s:\n%s", kubeVersion, strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
 // write the <data> to <output-dir>/<name>
 func writeToFile(outputDir string, name string, data string) error {
 	outfileName := strings.Join([]string{outputDir, name}, string(filepath.Separator))
@@ -289,3 +504,9 @@ func ensureDirectoryForFile(file string) error {
 
 	return os.MkdirAll(baseDir, defaultDirectoryPermission)
 }
+
+// isOutputsFile returns true if the template renders the outputs of a chart,
+// rather than Kubernetes resources.
+func isOutputsFile(name string) bool {
+	return filepath.Base(name) == chartutil.OutputsName && filepath.Base(filepath.Dir(name)) == chartutil.TemplatesDir
+}
@@ -30,6 +30,8 @@ import (
 var (
 	subchart1ChartPath = "./../../pkg/chartutil/testdata/subpop/charts/subchart1"
 	frobnitzChartPath  = "./../../pkg/chartutil/testdata/frobnitz"
+	outdatedChartPath  = "./../../pkg/lint/rules/testdata/outdated"
+	crdsChartPath      = "testdata/testcharts/crds"
 )
 
 func TestTemplateCmd(t *testing.T) {
@@ -107,6 +109,20 @@ func TestTemplateCmd(t *testing.T) {
 			expectKey:   "subchart1/templates/service.yaml",
 			expectValue: "release-name: \"test\"",
 		},
+		{
+			name:        "check_render_time",
+			desc:        "verify an invalid render time is rejected",
+			args:        []string{subchart1ChartPath, "--render-time", "yesterday"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectError: "invalid render time",
+		},
+		{
+			name:        "check_random_seed",
+			desc:        "verify an invalid random seed is rejected",
+			args:        []string{subchart1ChartPath, "--random-seed", "abc"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectError: "invalid random seed",
+		},
 		{
 			name:        "check_invalid_name_uppercase",
 			desc:        "verify the release name using capitals is invalid",
@@ -142,6 +158,27 @@ func TestTemplateCmd(t *testing.T) {
 			expectKey:   "subchart1/templates/service.yaml",
 			expectValue: "release-is-upgrade: \"true\"",
 		},
+		{
+			name:        "check_release_revision",
+			desc:        "verify --is-upgrade renders the second revision",
+			args:        []string{subchart1ChartPath, "--is-upgrade", "true"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectValue: "release-revision: \"2\"",
+		},
+		{
+			name:        "check_revision",
+			desc:        "verify --revision sets .Release.Revision",
+			args:        []string{subchart1ChartPath, "--revision", "7"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectValue: "release-revision: \"7\"",
+		},
+		{
+			name:        "check_previous_values",
+			desc:        "verify a missing --previous-values file is rejected",
+			args:        []string{subchart1ChartPath, "--previous-values", "missing.yaml"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectError: "failed to read previous values",
+		},
 		{
 			name:        "check_notes",
 			desc:        "verify --notes shows notes",
@@ -176,6 +213,58 @@ func TestTemplateCmd(t *testing.T) {
 			expectKey:   "subchart1/templates/service.yaml",
 			expectValue: "kube-version/major: \"1\"\n    kube-version/minor: \"6\"\n    kube-version/gitversion: \"v1.6.0\"",
 		},
+		{
+			name:        "check_validate",
+			desc:        "verify --validate accepts valid manifests",
+			args:        []string{subchart1ChartPath, "--validate"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectValue: "protocol: TCP\n    name: nginx",
+		},
+		{
+			name:        "check_show_only",
+			desc:        "verify --show-only renders the given template",
+			args:        []string{subchart1ChartPath, "--show-only", "templates/service.yaml"},
+			expectKey:   "subchart1/templates/service.yaml",
+			expectValue: "protocol: TCP\n    name: nginx",
+		},
+		{
+			name:        "check_show_only_glob",
+			desc:        "verify --show-only renders the templates matching a glob",
+			args:        []string{subchart1ChartPath, "--show-only", "charts/*/templates/service.yaml"},
+			expectKey:   "subchart1/charts/subcharta/templates/service.yaml",
+			expectValue: "name: apache",
+		},
+		{
+			name:        "check_without_subcharts",
+			desc:        "verify --with-subcharts=false leaves out the templates of the subcharts",
+			args:        []string{subchart1ChartPath, "--with-subcharts=false", "--show-only", "charts/*/templates/service.yaml"},
+			expectError: "could not find template charts/*/templates/service.yaml in chart",
+		},
+		{
+			name:        "check_show_only_missing",
+			desc:        "verify --show-only fails on a pattern matching no template",
+			args:        []string{subchart1ChartPath, "--show-only", "templates/*.json"},
+			expectError: "could not find template templates/*.json in chart",
+		},
+		{
+			name:        "check_include_crds",
+			desc:        "verify --include-crds prints the CRDs of the chart",
+			args:        []string{crdsChartPath, "--include-crds"},
+			expectKey:   "crds/crds/crontab.yaml",
+			expectValue: "kind: CustomResourceDefinition",
+		},
+		{
+			name:        "check_validate_invalid",
+			desc:        "verify --validate rejects manifests that do not match the schemas",
+			args:        []string{outdatedChartPath, "--validate"},
+			expectError: "spec.template.spec.containers[0].port: unknown field",
+		},
+		{
+			name:        "check_validate_removed_api",
+			desc:        "verify --validate rejects apiVersions --kube-version does not serve",
+			args:        []string{outdatedChartPath, "--validate", "--kube-version", "1.16"},
+			expectError: "extensions/v1beta1 Deployment is not served by Kubernetes 1.16",
+		},
 	}
 
 	var buf bytes.Buffer
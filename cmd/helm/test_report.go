@@ -0,0 +1,125 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the results of a test suite run of a release as a JUnit
+// XML report.
+func writeJUnit(out io.Writer, name string, suite *release.TestSuite) error {
+	ts := junitTestSuite{
+		Name: name,
+		Time: testDuration(suite.StartedAt, suite.CompletedAt),
+	}
+	if suite.StartedAt != nil {
+		ts.Timestamp = timeconv.Time(suite.StartedAt).UTC().Format(time.RFC3339)
+	}
+	for _, r := range suite.Results {
+		tc := junitTestCase{
+			Name:      r.Name,
+			Classname: name,
+			Time:      testDuration(r.StartedAt, r.CompletedAt),
+			SystemOut: r.Logs,
+		}
+		switch r.Status {
+		case release.TestRun_SUCCESS:
+		case release.TestRun_FAILURE:
+			ts.Failures++
+			tc.Failure = &junitMessage{Message: fmt.Sprintf("test %s failed", r.Name), Text: r.Info}
+		default:
+			ts.Errors++
+			tc.Error = &junitMessage{Message: fmt.Sprintf("test %s ended with status %s", r.Name, r.Status), Text: r.Info}
+		}
+		ts.TestCases = append(ts.TestCases, tc)
+	}
+	ts.Tests = len(ts.TestCases)
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{ts}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to Marshal JUnit output: %s", err)
+	}
+	fmt.Fprintf(out, "%s%s\n", xml.Header, data)
+	return nil
+}
+
+// formatTestLogs renders the stored logs of each test of a test suite run.
+func formatTestLogs(results []*release.TestRun) string {
+	var b bytes.Buffer
+	for _, r := range results {
+		if r.Logs == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "LOGS: %s\n%s\n", r.Name, indentLogs(r.Logs))
+	}
+	return b.String()
+}
+
+// indentLogs indents every line of logs so they stand out from the test
+// results around them.
+func indentLogs(logs string) string {
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	return "    " + strings.Join(lines, "\n    ")
+}
+
+func testDuration(start, end *timestamp.Timestamp) string {
+	if start == nil || end == nil {
+		return "0"
+	}
+	return fmt.Sprintf("%.3f", timeconv.Time(end).Sub(timeconv.Time(start)).Seconds())
+}
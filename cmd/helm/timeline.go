@@ -0,0 +1,298 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+var timelineHelp = `
+This command prints a chronological view of what happened to a release: its
+revisions, the hooks run by each of them, the resources Tiller waited for and
+why a revision failed.
+
+With '--events', the Kubernetes Events of the resources of the release that
+were emitted while each revision was being deployed are added. A revision is
+being deployed from the time it was created until the time of the next one.
+
+    $ helm timeline angry-bird
+    TIME                    REVISION  SOURCE   OBJECT                 MESSAGE
+    2019-05-02T10:15:13Z    1         release                         DEPLOYED: Install complete
+    2019-05-02T10:15:20Z    1         hook     Job/angry-bird-db      pre-install
+    2019-05-02T10:15:52Z    1         wait     Deployment/angry-bird  ready
+`
+
+// timelineEntry is one event in the timeline of a release.
+type timelineEntry struct {
+	Time     string `json:"time"`
+	Revision int32  `json:"revision"`
+	Source   string `json:"source"`
+	Object   string `json:"object,omitempty"`
+	Message  string `json:"message"`
+
+	time time.Time
+}
+
+type timelineCmd struct {
+	release      string
+	max          int32
+	events       bool
+	outputFormat string
+	colWidth     uint
+	out          io.Writer
+	client       helm.Interface
+	kubeClient   kubernetes.Interface
+}
+
+func newTimelineCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	tl := &timelineCmd{out: out, client: client}
+
+	cmd := &cobra.Command{
+		Use:     "timeline [flags] RELEASE_NAME",
+		Short:   "Print a chronological view of the revisions, hooks and waits of a release",
+		Long:    timelineHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			tl.release = args[0]
+			tl.client = ensureHelmClient(tl.client)
+			return tl.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.Int32Var(&tl.max, "max", 256, "Maximum number of revisions to include in the timeline")
+	f.BoolVar(&tl.events, "events", false, "Include the Kubernetes Events of the resources of the release")
+	f.UintVar(&tl.colWidth, "col-width", 80, "Specifies the max column width of output")
+	f.StringVarP(&tl.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (t *timelineCmd) run() error {
+	res, err := t.client.ReleaseHistory(t.release, helm.WithMaxHistory(t.max))
+	if err != nil {
+		return prettyError(err)
+	}
+	rels := res.GetReleases()
+	releaseutil.SortByRevision(rels)
+
+	entries := releaseTimeline(rels)
+	if t.events {
+		if t.kubeClient == nil {
+			_, c, err := getKubeClient(settings.KubeContext, settings.KubeConfig)
+			if err != nil {
+				return fmt.Errorf("could not get kubernetes client: %s", err)
+			}
+			t.kubeClient = c
+		}
+		events, err := eventTimeline(t.kubeClient, rels, time.Now())
+		if err != nil {
+			return fmt.Errorf("could not list events: %s", err)
+		}
+		entries = append(entries, events...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time.Before(entries[j].time)
+	})
+
+	var out []byte
+	switch t.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(entries)
+	case "json":
+		out, err = json.Marshal(entries)
+	case "table":
+		out = formatTimeline(entries, t.colWidth)
+	default:
+		return fmt.Errorf("unknown output format %q", t.outputFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(t.out, string(out))
+	return nil
+}
+
+func newTimelineEntry(ts time.Time, revision int32, source, object, message string) timelineEntry {
+	return timelineEntry{
+		Time:     ts.UTC().Format(time.RFC3339),
+		Revision: revision,
+		Source:   source,
+		Object:   object,
+		Message:  message,
+		time:     ts,
+	}
+}
+
+// releaseTimeline returns the entries recorded in the revisions of a release.
+func releaseTimeline(rels []*release.Release) []timelineEntry {
+	var entries []timelineEntry
+	for _, r := range rels {
+		info := r.GetInfo()
+		if info.GetLastDeployed() != nil {
+			msg := info.GetStatus().GetCode().String()
+			if info.Description != "" {
+				msg += ": " + info.Description
+			}
+			entries = append(entries, newTimelineEntry(timeconv.Time(info.LastDeployed), r.Version, "release", "", msg))
+		}
+		for _, h := range r.Hooks {
+			if h.LastRun == nil {
+				continue
+			}
+			var events []string
+			for _, e := range h.Events {
+				events = append(events, strings.Replace(strings.ToLower(e.String()), "_", "-", -1))
+			}
+			entries = append(entries, newTimelineEntry(timeconv.Time(h.LastRun), r.Version, "hook", h.Kind+"/"+h.Name, strings.Join(events, ",")))
+		}
+		for _, rr := range info.GetReadiness() {
+			if rr.Time == nil {
+				continue
+			}
+			msg := "ready"
+			if !rr.Ready {
+				msg = "not ready"
+			}
+			if rr.Message != "" {
+				msg += ": " + rr.Message
+			}
+			entries = append(entries, newTimelineEntry(timeconv.Time(rr.Time), r.Version, "wait", rr.Kind+"/"+rr.Name, msg))
+		}
+		if info.GetFailurePhase() != "" {
+			// The release records the time the operation started, not the
+			// time of the failure, so the failure comes after its entries.
+			ts := timeconv.Time(info.LastDeployed)
+			for _, e := range entries {
+				if e.Revision == r.Version && e.time.After(ts) {
+					ts = e.time
+				}
+			}
+			entries = append(entries, newTimelineEntry(ts, r.Version, "release", "", fmt.Sprintf("failed in %s: %s", info.FailurePhase, info.LastError)))
+		}
+		if info.GetDeleted() != nil {
+			entries = append(entries, newTimelineEntry(timeconv.Time(info.Deleted), r.Version, "release", "", "deleted"))
+		}
+	}
+	return entries
+}
+
+// timelineObject is the part of a manifest identifying a resource.
+type timelineObject struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+}
+
+// eventTimeline returns the Kubernetes Events of the resources and hooks of
+// each revision that were last seen while the revision was being deployed.
+// rels must be sorted by revision.
+func eventTimeline(client kubernetes.Interface, rels []*release.Release, now time.Time) ([]timelineEntry, error) {
+	events := map[string][]timelineEntry{}
+	var entries []timelineEntry
+	for i, r := range rels {
+		start := timeconv.Time(r.GetInfo().GetLastDeployed())
+		end := now
+		if i+1 < len(rels) {
+			end = timeconv.Time(rels[i+1].GetInfo().GetLastDeployed())
+		}
+
+		objects := map[string]bool{}
+		seen := map[string]bool{}
+		var namespaces []string
+		add := func(kind, name, namespace string) {
+			if namespace == "" {
+				namespace = r.Namespace
+			}
+			if !seen[namespace] {
+				seen[namespace] = true
+				namespaces = append(namespaces, namespace)
+			}
+			objects[namespace+"/"+kind+"/"+name] = true
+		}
+		for _, doc := range releaseutil.SplitManifests(r.Manifest) {
+			var obj timelineObject
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj.Kind == "" {
+				continue
+			}
+			add(obj.Kind, obj.Metadata.Name, obj.Metadata.Namespace)
+		}
+		for _, h := range r.Hooks {
+			add(h.Kind, h.Name, "")
+		}
+
+		for _, ns := range namespaces {
+			if _, ok := events[ns]; !ok {
+				list, err := client.CoreV1().Events(ns).List(metav1.ListOptions{})
+				if err != nil {
+					return nil, err
+				}
+				events[ns] = []timelineEntry{}
+				for _, e := range list.Items {
+					ts := e.LastTimestamp.Time
+					if ts.IsZero() {
+						ts = e.EventTime.Time
+					}
+					obj := e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name
+					events[ns] = append(events[ns], newTimelineEntry(ts, 0, "event", obj, fmt.Sprintf("%s %s: %s", e.Type, e.Reason, e.Message)))
+				}
+			}
+			for _, e := range events[ns] {
+				if objects[ns+"/"+e.Object] && !e.time.Before(start) && e.time.Before(end) {
+					e.Revision = r.Version
+					entries = append(entries, e)
+				}
+			}
+		}
+	}
+	return entries, nil
+}
+
+func formatTimeline(entries []timelineEntry, colWidth uint) []byte {
+	tbl := uitable.New()
+	tbl.MaxColWidth = colWidth
+	tbl.AddRow("TIME", "REVISION", "SOURCE", "OBJECT", "MESSAGE")
+	for _, e := range entries {
+		tbl.AddRow(e.Time, e.Revision, e.Source, e.Object, e.Message)
+	}
+	return tbl.Bytes()
+}
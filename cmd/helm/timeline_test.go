@@ -0,0 +1,132 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func timelineRelease(version int32, seconds int64) *release.Release {
+	r := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "angry-bird", Version: version})
+	r.Info.LastDeployed = &timestamp.Timestamp{Seconds: seconds}
+	r.Hooks[0].LastRun = &timestamp.Timestamp{Seconds: seconds + 5}
+	return r
+}
+
+func TestTimelineCmd(t *testing.T) {
+	failed := timelineRelease(2, 1000)
+	failed.Info.Status.Code = release.Status_FAILED
+	failed.Info.Description = "Upgrade \"angry-bird\" failed: timed out waiting for the condition"
+	failed.Info.Readiness = []*release.ResourceReadiness{
+		{Kind: "Deployment", Name: "angry-bird", Message: "0 of 1 replicas available", Time: &timestamp.Timestamp{Seconds: 1010}},
+	}
+	failed.Info.FailurePhase = "wait"
+	failed.Info.LastError = "timed out waiting for the condition"
+
+	tests := []releaseCase{
+		{
+			name: "timeline of a release",
+			args: []string{"angry-bird"},
+			rels: []*release.Release{failed, timelineRelease(1, 0)},
+			expected: `TIME\s+REVISION\s+SOURCE\s+OBJECT\s+MESSAGE\s*
+1970-01-01T00:00:00Z\s+1\s+release\s+DEPLOYED: Release mock\s*
+1970-01-01T00:00:05Z\s+1\s+hook\s+Job/pre-install-hook\s+pre-install\s*
+1970-01-01T00:16:40Z\s+2\s+release\s+FAILED: Upgrade "angry-bird" failed: timed out waiting for the condition\s*
+1970-01-01T00:16:45Z\s+2\s+hook\s+Job/pre-install-hook\s+pre-install\s*
+1970-01-01T00:16:50Z\s+2\s+wait\s+Deployment/angry-bird\s+not ready: 0 of 1 replicas available\s*
+1970-01-01T00:16:50Z\s+2\s+release\s+failed in wait: timed out waiting for the condition`,
+		},
+		{
+			name:     "timeline as json",
+			args:     []string{"angry-bird"},
+			flags:    []string{"--output", "json"},
+			rels:     []*release.Release{timelineRelease(1, 0)},
+			expected: `\[{"time":"1970-01-01T00:00:00Z","revision":1,"source":"release","message":"DEPLOYED: Release mock"},{"time":"1970-01-01T00:00:05Z","revision":1,"source":"hook","object":"Job/pre-install-hook","message":"pre-install"}\]`,
+		},
+		{
+			name: "timeline requires a release name",
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newTimelineCmd(c, out)
+	})
+}
+
+func TestTimelineEvents(t *testing.T) {
+	event := func(name, kind, reason string, seconds int64) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: "fixture"},
+			Type:           v1.EventTypeNormal,
+			Reason:         reason,
+			Message:        name,
+			LastTimestamp:  metav1.NewTime(time.Unix(seconds, 0)),
+		}
+	}
+	kc := fake.NewSimpleClientset(
+		event("first", "Secret", "Created", 10),
+		event("second", "Secret", "Updated", 1010),
+		event("other", "ConfigMap", "Created", 20),
+	)
+
+	var buf bytes.Buffer
+	tl := &timelineCmd{
+		release:      "angry-bird",
+		max:          256,
+		events:       true,
+		outputFormat: "table",
+		colWidth:     80,
+		out:          &buf,
+		client:       &helm.FakeClient{Rels: []*release.Release{timelineRelease(2, 1000), timelineRelease(1, 0)}},
+		kubeClient:   kc,
+	}
+	if err := tl.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var events []string
+	for _, l := range lines {
+		if strings.Contains(l, "\tevent") {
+			events = append(events, l)
+		}
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d:\n%s", len(events), buf.String())
+	}
+	if !strings.Contains(events[0], "00:00:10Z\t1") || !strings.Contains(events[0], "Normal Created: first") {
+		t.Errorf("unexpected first event %q", events[0])
+	}
+	if !strings.Contains(events[1], "00:16:50Z\t2") || !strings.Contains(events[1], "Normal Updated: second") {
+		t.Errorf("unexpected second event %q", events[1])
+	}
+}
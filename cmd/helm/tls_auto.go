@@ -0,0 +1,103 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/tlsutil"
+)
+
+// Files of the certificates generated by 'helm init --tls-auto' that are only
+// used by Tiller. The CA certificate and the client certificate and key are
+// written to the default locations of --tls-ca-cert, --tls-cert and --tls-key.
+const (
+	tlsAutoCAKey      = "ca.key.pem"
+	tlsAutoServerCert = "tiller.cert.pem"
+	tlsAutoServerKey  = "tiller.key.pem"
+)
+
+// tlsAutoValidity is how long the generated certificates are valid for.
+const tlsAutoValidity = 5 * 365 * 24 * time.Hour
+
+// provisionTLS generates a CA and the certificates of Tiller and of the client
+// in home. Certificates generated by an earlier run are kept.
+func provisionTLS(home helmpath.Home, namespace string) error {
+	if _, err := os.Stat(home.TLS(tlsAutoServerCert)); err == nil {
+		return nil
+	}
+	for _, f := range []string{home.TLSCaCert(), home.TLSCert(), home.TLSKey()} {
+		if _, err := os.Stat(f); err == nil {
+			return fmt.Errorf("%s already exists; remove it, or use --tiller-tls-cert, --tiller-tls-key and --tls-ca-cert to install Tiller with existing certificates", f)
+		}
+	}
+
+	hosts := []string{
+		"127.0.0.1",
+		"localhost",
+		"tiller-deploy",
+		"tiller-deploy." + namespace,
+		"tiller-deploy." + namespace + ".svc",
+	}
+	b, err := tlsutil.GenerateBundle(hosts, tlsAutoValidity)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(home.TLS(), 0700); err != nil {
+		return err
+	}
+	files := []struct {
+		path string
+		data []byte
+		mode os.FileMode
+	}{
+		{home.TLSCaCert(), b.CACert, 0644},
+		{home.TLSCert(), b.ClientCert, 0644},
+		{home.TLSKey(), b.ClientKey, 0600},
+		{home.TLS(tlsAutoCAKey), b.CAKey, 0600},
+		{home.TLS(tlsAutoServerKey), b.ServerKey, 0600},
+		// written last, as it marks the certificates as complete
+		{home.TLS(tlsAutoServerCert), b.ServerCert, 0644},
+	}
+	for _, f := range files {
+		if err := ioutil.WriteFile(f.path, f.data, f.mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enableAutoTLS makes commands connecting to Tiller verify it with TLS once
+// 'helm init --tls-auto' has generated certificates, unless TLS is set with a
+// flag or environment variable.
+func enableAutoTLS(fs *pflag.FlagSet, home helmpath.Home) {
+	if fs.Lookup("tls") == nil || fs.Changed("tls") || fs.Changed("tls-verify") {
+		return
+	}
+	if _, err := os.Stat(home.TLS(tlsAutoServerCert)); err != nil {
+		return
+	}
+	settings.TLSEnable = true
+	settings.TLSVerify = true
+}
@@ -0,0 +1,364 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+const topHelp = `
+This command shows a live view of the releases in the cluster, refreshed
+every few seconds until it is interrupted with Ctrl-C.
+
+For every release it shows:
+- the status and revision of the latest release
+- the operation in progress, if the release is pending an install, upgrade,
+  rollback or delete, and for how long it has been pending
+- whether resources of the release are missing from the cluster (DRIFT)
+- the newest version of the chart in the local repository cache, if it is
+  newer than the deployed one (UPDATE). Run 'helm repo update' to refresh it
+
+The status of the releases is fetched in parallel, see '--parallel'. Deleted
+releases are not shown.
+
+To print the view once, for instance to use it in a script, use '--once':
+
+	$ helm top --once --namespace production
+`
+
+// topStatuses are the release statuses shown by 'helm top'.
+var topStatuses = []release.Status_Code{
+	release.Status_UNKNOWN,
+	release.Status_DEPLOYED,
+	release.Status_DELETING,
+	release.Status_FAILED,
+	release.Status_PENDING_INSTALL,
+	release.Status_PENDING_UPGRADE,
+	release.Status_PENDING_ROLLBACK,
+}
+
+type topCmd struct {
+	out       io.Writer
+	client    helm.Interface
+	home      helmpath.Home
+	namespace string
+	selector  string
+	limit     int
+	interval  int
+	parallel  int
+	once      bool
+	colWidth  uint
+}
+
+type topRow struct {
+	Name      string
+	Namespace string
+	Revision  int32
+	Status    string
+	Pending   string
+	Drift     string
+	Chart     string
+	Update    string
+	Updated   string
+}
+
+func newTopCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	top := &topCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "top [flags]",
+		Short:   "Display a live view of the releases",
+		Long:    topHelp,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if top.client == nil {
+				top.client = newClient()
+			}
+			top.home = settings.Home
+			return top.run()
+		},
+	}
+
+	f := cmd.Flags()
+	settings.AddFlagsTLS(f)
+	f.StringVar(&top.namespace, "namespace", "", "Show releases within a specific namespace")
+	f.StringVarP(&top.selector, "selector", "l", "", "Selector (label query) to filter releases on, supports '=', '==', '!=', 'in' and 'notin' (e.g. -l key1=value1,key2=value2)")
+	f.IntVarP(&top.limit, "max", "m", 256, "Maximum number of releases to show")
+	f.IntVar(&top.interval, "interval", 5, "Seconds between two refreshes of the view")
+	f.IntVar(&top.parallel, "parallel", 8, "Number of release statuses fetched at the same time")
+	f.BoolVar(&top.once, "once", false, "Print the view once and exit")
+	f.UintVar(&top.colWidth, "col-width", 60, "Specifies the max column width of output")
+
+	// set defaults from environment
+	settings.InitTLS(f)
+
+	return cmd
+}
+
+func (t *topCmd) run() error {
+	if t.interval < 1 {
+		return errors.New("interval must be at least one second")
+	}
+	if t.parallel < 1 {
+		t.parallel = 1
+	}
+
+	if t.once {
+		rows, err := t.snapshot()
+		if err != nil {
+			return prettyError(err)
+		}
+		fmt.Fprintln(t.out, formatTop(rows, t.colWidth))
+		return nil
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(time.Duration(t.interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		rows, err := t.snapshot()
+		// clear the screen and move the cursor to the top left corner
+		fmt.Fprint(t.out, "\033[H\033[2J")
+		fmt.Fprintf(t.out, "Every %ds: %s\t%s\n\n", t.interval, summarizeTop(rows), time.Now().Format(time.RFC1123))
+		if err != nil {
+			fmt.Fprintf(t.out, "Error: %s\n", prettyError(err))
+		} else {
+			fmt.Fprintln(t.out, formatTop(rows, t.colWidth))
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshot lists the releases and fetches their status.
+func (t *topCmd) snapshot() ([]topRow, error) {
+	res, err := t.client.ListReleases(
+		helm.ReleaseListLimit(t.limit),
+		helm.ReleaseListSort(int32(services.ListSort_NAME)),
+		helm.ReleaseListStatuses(topStatuses),
+		helm.ReleaseListNamespace(t.namespace),
+		helm.ReleaseListSelector(t.selector),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+
+	rels := filterList(res.GetReleases())
+	latest := t.latestChartVersions()
+
+	rows := make([]topRow, len(rels))
+	sem := make(chan struct{}, t.parallel)
+	var wg sync.WaitGroup
+	for i, r := range rels {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, r *release.Release) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			rows[i] = t.row(r, latest)
+		}(i, r)
+	}
+	wg.Wait()
+	return rows, nil
+}
+
+func (t *topCmd) row(r *release.Release, latest map[string]*repoChartVersion) topRow {
+	md := r.GetChart().GetMetadata()
+	row := topRow{
+		Name:      r.GetName(),
+		Namespace: r.GetNamespace(),
+		Revision:  r.GetVersion(),
+		Status:    r.GetInfo().GetStatus().GetCode().String(),
+		Pending:   pendingOperation(r.GetInfo(), time.Now()),
+		Drift:     "-",
+		Chart:     fmt.Sprintf("%s-%s", md.GetName(), md.GetVersion()),
+		Update:    availableUpdate(md, latest),
+		Updated:   "-",
+	}
+	if tspb := r.GetInfo().GetLastDeployed(); tspb != nil {
+		row.Updated = timeconv.String(tspb)
+	}
+
+	// only deployed releases are expected to have all of their resources
+	if r.GetInfo().GetStatus().GetCode() != release.Status_DEPLOYED {
+		return row
+	}
+	status, err := t.client.ReleaseStatus(r.GetName(), helm.StatusReleaseVersion(r.GetVersion()))
+	if err != nil {
+		row.Drift = "unknown"
+		return row
+	}
+	if missing := missingResources(status.GetInfo().GetStatus().GetResources()); missing > 0 {
+		row.Drift = fmt.Sprintf("%d missing", missing)
+	}
+	return row
+}
+
+// repoChartVersion is the newest version of a chart in a repository.
+type repoChartVersion struct {
+	repo    string
+	version *semver.Version
+}
+
+// latestChartVersions returns the newest version of every chart in the local
+// repository cache. Repositories that cannot be loaded are skipped, the view
+// is refreshed too often to warn about them.
+func (t *topCmd) latestChartVersions() map[string]*repoChartVersion {
+	latest := map[string]*repoChartVersion{}
+	rf, err := repo.LoadRepositoriesFile(t.home.RepositoryFile())
+	if err != nil {
+		return latest
+	}
+	for _, re := range rf.Repositories {
+		ind, err := repo.LoadIndexFile(t.home.CacheIndex(re.Name))
+		if err != nil {
+			continue
+		}
+		for name := range ind.Entries {
+			cv, err := ind.Get(name, "")
+			if err != nil {
+				continue
+			}
+			v, err := semver.NewVersion(cv.Version)
+			if err != nil {
+				continue
+			}
+			if l, ok := latest[name]; !ok || v.GreaterThan(l.version) {
+				latest[name] = &repoChartVersion{repo: re.Name, version: v}
+			}
+		}
+	}
+	return latest
+}
+
+// availableUpdate describes the newest version of the chart if it is newer
+// than the deployed one.
+func availableUpdate(md *chart.Metadata, latest map[string]*repoChartVersion) string {
+	l, ok := latest[md.GetName()]
+	if !ok {
+		return "-"
+	}
+	deployed, err := semver.NewVersion(md.GetVersion())
+	if err != nil || !l.version.GreaterThan(deployed) {
+		return "-"
+	}
+	return fmt.Sprintf("%s/%s %s", l.repo, md.GetName(), l.version)
+}
+
+// pendingOperation describes the operation in progress on a release.
+func pendingOperation(info *release.Info, now time.Time) string {
+	var op string
+	switch info.GetStatus().GetCode() {
+	case release.Status_PENDING_INSTALL:
+		op = "install"
+	case release.Status_PENDING_UPGRADE:
+		op = "upgrade"
+	case release.Status_PENDING_ROLLBACK:
+		op = "rollback"
+	case release.Status_DELETING:
+		op = "delete"
+	default:
+		return "-"
+	}
+	if info.GetLastDeployed() == nil {
+		return op
+	}
+	since := now.Sub(timeconv.Time(info.GetLastDeployed())).Round(time.Second)
+	return fmt.Sprintf("%s (%s)", op, since)
+}
+
+// missingResources counts the resources listed as missing in the resources
+// of a release status.
+func missingResources(resources string) int {
+	i := strings.Index(resources, kube.MissingGetHeader)
+	if i < 0 {
+		return 0
+	}
+	count := 0
+	for _, line := range strings.Split(resources[i+len(kube.MissingGetHeader):], "\n") {
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+func summarizeTop(rows []topRow) string {
+	var failed, pending, drifted, updates int
+	for _, r := range rows {
+		switch {
+		case r.Status == release.Status_FAILED.String():
+			failed++
+		case r.Pending != "-":
+			pending++
+		}
+		if r.Drift != "-" {
+			drifted++
+		}
+		if r.Update != "-" {
+			updates++
+		}
+	}
+	return fmt.Sprintf("%d releases, %d failed, %d pending, %d drifted, %d with updates", len(rows), failed, pending, drifted, updates)
+}
+
+func formatTop(rows []topRow, colWidth uint) string {
+	table := uitable.New()
+	table.MaxColWidth = colWidth
+	table.AddRow("NAME", "NAMESPACE", "REVISION", "STATUS", "PENDING", "DRIFT", "CHART", "UPDATE", "UPDATED")
+	for _, r := range rows {
+		table.AddRow(r.Name, r.Namespace, r.Revision, r.Status, r.Pending, r.Drift, r.Chart, r.Update, r.Updated)
+	}
+	return table.String()
+}
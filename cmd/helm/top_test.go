@@ -0,0 +1,100 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+func TestTopCmd(t *testing.T) {
+	cleanup := resetEnv()
+	defer cleanup()
+
+	settings.Home = "testdata/helmhome"
+
+	alpine := &chart.Chart{Metadata: &chart.Metadata{Name: "alpine", Version: "0.1.0"}}
+	missing := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "beacon"})
+	missing.Info.Status.Resources = kube.MissingGetHeader + "v1/ConfigMap\t\tbeacon-config\nv1/Service\t\tbeacon\n"
+
+	tests := []releaseCase{
+		{
+			name:     "empty",
+			flags:    []string{"--once"},
+			rels:     []*release.Release{},
+			expected: "NAME\tNAMESPACE\tREVISION\tSTATUS\tPENDING\tDRIFT\tCHART\tUPDATE\tUPDATED",
+		},
+		{
+			name:  "with an update",
+			flags: []string{"--once"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "atlas", Chart: alpine}),
+			},
+			expected: `atlas\s*default\s*1\s*DEPLOYED\s*-\s*-\s*alpine-0.1.0\s*testing/alpine 0.2.0`,
+		},
+		{
+			name:  "with missing resources",
+			flags: []string{"--once"},
+			rels: []*release.Release{
+				missing,
+			},
+			expected: `beacon\s*default\s*1\s*DEPLOYED\s*-\s*2 missing`,
+		},
+		{
+			name:  "with a pending upgrade",
+			flags: []string{"--once"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "comet", StatusCode: release.Status_PENDING_UPGRADE}),
+			},
+			expected: `comet\s*default\s*1\s*PENDING_UPGRADE\s*upgrade \(.*\)`,
+		},
+		{
+			name:  "invalid interval",
+			flags: []string{"--interval", "0"},
+			err:   true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newTopCmd(c, out)
+	})
+}
+
+func TestPendingOperation(t *testing.T) {
+	now := time.Now()
+	info := &release.Info{
+		Status:       &release.Status{Code: release.Status_DELETING},
+		LastDeployed: timeconv.Timestamp(now.Add(-90 * time.Second)),
+	}
+	if got := pendingOperation(info, now); got != "delete (1m30s)" {
+		t.Errorf("Expected \"delete (1m30s)\", got %q", got)
+	}
+
+	info.Status.Code = release.Status_DEPLOYED
+	if got := pendingOperation(info, now); got != "-" {
+		t.Errorf("Expected \"-\", got %q", got)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -25,6 +26,8 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/renderutil"
 	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
@@ -81,38 +84,58 @@ your shell convention for escaping it; for example in bash:
 	$ helm upgrade --set pwd='3jk$o2z=f\\30with'\''quote'
 
 which results in "pwd: 3jk$o2z=f\30with'quote".
+
+To apply part of the chart only, use '--only' with the path of a template,
+with 'kind=KIND' or with 'labels=SELECTOR'. The whole chart is still rendered, but only the matching
+resources are changed; every other resource is left as it is. The new revision
+is recorded as a partial upgrade:
+
+	$ helm upgrade --only templates/configmap.yaml my-release ./mychart
+	$ helm upgrade --only kind=ConfigMap --only kind=Secret my-release ./mychart
 `
 
 type upgradeCmd struct {
-	release       string
-	chart         string
-	out           io.Writer
-	client        helm.Interface
-	dryRun        bool
-	recreate      bool
-	force         bool
-	disableHooks  bool
-	valueFiles    valueFiles
-	values        []string
-	stringValues  []string
-	fileValues    []string
-	verify        bool
-	keyring       string
-	install       bool
-	namespace     string
-	version       string
-	timeout       int64
-	resetValues   bool
-	reuseValues   bool
-	wait          bool
-	atomic        bool
-	repoURL       string
-	username      string
-	password      string
-	devel         bool
-	subNotes      bool
-	description   string
-	cleanupOnFail bool
+	release        string
+	chart          string
+	out            io.Writer
+	client         helm.Interface
+	dryRun         bool
+	recreate       bool
+	force          bool
+	disableHooks   bool
+	valueFiles     valueFiles
+	values         []string
+	stringValues   []string
+	fileValues     []string
+	verify         bool
+	keyring        string
+	install        bool
+	namespace      string
+	version        string
+	timeout        int64
+	hookTimeout    int64
+	resetValues    bool
+	reuseValues    bool
+	wait           bool
+	atomic         bool
+	repoURL        string
+	username       string
+	password       string
+	devel          bool
+	subNotes       bool
+	strict         bool
+	description    string
+	overrideWindow string
+	serviceAccount string
+	cleanupOnFail  bool
+	waitTimeouts   waitTimeouts
+	waitForJobs    bool
+	waitConditions waitConditions
+	labels         labelsFlag
+	only           []string
+	sortOrderFile  string
+	output         string
+	quiet          bool
 
 	certFile string
 	keyFile  string
@@ -130,13 +153,13 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 		Use:     "upgrade [RELEASE] [CHART]",
 		Short:   "Upgrade a release",
 		Long:    upgradeDesc,
-		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnectionIn(upgrade.namespace) },
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := checkArgsLength(len(args), "release name", "chart path"); err != nil {
 				return err
 			}
 
-			if upgrade.version == "" && upgrade.devel {
+			if upgrade.version == "" && upgrade.devel && !pinnedChartReference(args[1]) {
 				debug("setting version to >0.0.0-0")
 				upgrade.version = ">0.0.0-0"
 			}
@@ -166,11 +189,18 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVarP(&upgrade.install, "install", "i", false, "If a release by this name doesn't already exist, run an install")
 	f.StringVar(&upgrade.namespace, "namespace", "", "Namespace to install the release into (only used if --install is set). Defaults to the current kube config namespace")
 	f.StringVar(&upgrade.version, "version", "", "Specify the exact chart version to use. If this is not specified, the latest version is used")
-	f.Int64Var(&upgrade.timeout, "timeout", 300, "Time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	helm_env.TimeoutVar(f, &upgrade.timeout, "timeout", 300, "Time to wait for any individual Kubernetes operation (like Jobs for hooks), as a duration such as 5m30s or in seconds")
+	helm_env.TimeoutVar(f, &upgrade.hookTimeout, "hook-timeout", 0, "Time hooks may run, as a duration such as 10m or in seconds. Defaults to --timeout")
 	f.BoolVar(&upgrade.resetValues, "reset-values", false, "When upgrading, reset the values to the ones built into the chart")
 	f.BoolVar(&upgrade.reuseValues, "reuse-values", false, "When upgrading, reuse the last release's values and merge in any overrides from the command line via --set and -f. If '--reset-values' is specified, this is ignored.")
 	f.BoolVar(&upgrade.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 	f.BoolVar(&upgrade.atomic, "atomic", false, "If set, upgrade process rolls back changes made in case of failed upgrade, also sets --wait flag")
+	f.Var(&upgrade.labels, "labels", "Labels to add to the release, also set on its resources as release-label.helm.sh/KEY (can specify multiple or separate values with commas: team=web,env=prod)")
+	f.Var(&upgrade.waitTimeouts, "wait-timeout", "Override --timeout for resources of a kind while waiting, as KIND=DURATION (can specify multiple or separate values with commas: Deployment=10m,Service=60s)")
+	f.BoolVar(&upgrade.waitForJobs, "wait-for-jobs", false, "If set with --wait, will also wait until all Jobs have completed. A failed Job fails the wait")
+	f.Var(&upgrade.waitConditions, "wait-condition", "Wait for resources of a kind until a field of theirs has a value, as KIND=PATH==VALUE, such as 'Certificate=status.conditions[?(@.type==\"Ready\")].status==True' (can specify multiple)")
+	f.StringVar(&upgrade.output, "output", "", "Output the readiness reports and status in the specified format (json)")
+	f.BoolVar(&upgrade.quiet, "quiet", false, "Print only the name of the release on success")
 	f.StringVar(&upgrade.repoURL, "repo", "", "Chart repository url where to locate the requested chart")
 	f.StringVar(&upgrade.username, "username", "", "Chart repository username where to locate the requested chart")
 	f.StringVar(&upgrade.password, "password", "", "Chart repository password where to locate the requested chart")
@@ -179,8 +209,13 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.StringVar(&upgrade.caFile, "ca-file", "", "Verify certificates of HTTPS-enabled servers using this CA bundle")
 	f.BoolVar(&upgrade.devel, "devel", false, "Use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored.")
 	f.BoolVar(&upgrade.subNotes, "render-subchart-notes", false, "Render subchart notes along with parent")
+	f.BoolVar(&upgrade.strict, "strict", false, "Fail rendering on values used by the templates but not set, instead of rendering them empty")
 	f.StringVar(&upgrade.description, "description", "", "Specify the description to use for the upgrade, rather than the default")
+	f.StringVar(&upgrade.overrideWindow, "override-window", "", "Upgrade outside the maintenance windows of the release, giving the reason recorded in the Tiller audit log")
 	f.BoolVar(&upgrade.cleanupOnFail, "cleanup-on-fail", false, "Allow deletion of new resources created in this upgrade when upgrade failed")
+	f.StringVar(&upgrade.serviceAccount, "service-account-for-apply", "", "Have Tiller apply the upgrade as this service account of the release namespace, instead of the one of the release")
+	f.StringArrayVar(&upgrade.only, "only", []string{}, "Only apply the resources rendered from this template path, of this kind with kind=KIND, or matching labels=SELECTOR (can specify multiple)")
+	f.StringVar(&upgrade.sortOrderFile, "sort-order-file", "", "YAML file listing the kinds of resources in the order to install and uninstall them in, kept for the later revisions")
 
 	f.MarkDeprecated("disable-hooks", "Use --no-hooks instead")
 
@@ -191,6 +226,13 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (u *upgradeCmd) run() error {
+	if u.output != "" && u.output != "json" {
+		return fmt.Errorf("unknown output format %q", u.output)
+	}
+	if len(u.only) > 0 && u.force {
+		return errors.New("--only cannot be used with --force")
+	}
+
 	chartPath, err := locateChartPath(u.repoURL, u.username, u.password, u.chart, u.version, u.verify, u.keyring, u.certFile, u.keyFile, u.caFile)
 	if err != nil {
 		return err
@@ -212,33 +254,45 @@ func (u *upgradeCmd) run() error {
 			}
 			previousReleaseNamespace := releaseHistory.Releases[0].Namespace
 			if previousReleaseNamespace != u.namespace {
-				fmt.Fprintf(u.out,
-					"WARNING: Namespace %q doesn't match with previous. Release will be deployed to %s\n",
-					u.namespace, previousReleaseNamespace,
-				)
+				printMessage(u.out, u.quiet, msgNamespaceMismatch, u.namespace, previousReleaseNamespace)
 			}
 		}
 
 		if err != nil && strings.Contains(err.Error(), storageerrors.ErrReleaseNotFound(u.release).Error()) {
-			fmt.Fprintf(u.out, "Release %q does not exist. Installing it now.\n", u.release)
+			if len(u.only) > 0 {
+				return fmt.Errorf("release %q does not exist and cannot be installed with --only", u.release)
+			}
+			printMessage(u.out, u.quiet, msgInstallingRelease, u.release)
 			ic := &installCmd{
-				chartPath:    chartPath,
-				client:       u.client,
-				out:          u.out,
-				name:         u.release,
-				valueFiles:   u.valueFiles,
-				dryRun:       u.dryRun,
-				verify:       u.verify,
-				disableHooks: u.disableHooks,
-				keyring:      u.keyring,
-				values:       u.values,
-				stringValues: u.stringValues,
-				fileValues:   u.fileValues,
-				namespace:    u.namespace,
-				timeout:      u.timeout,
-				wait:         u.wait,
-				description:  u.description,
-				atomic:       u.atomic,
+				chartPath:      chartPath,
+				client:         u.client,
+				out:            u.out,
+				name:           u.release,
+				valueFiles:     u.valueFiles,
+				dryRun:         u.dryRun,
+				verify:         u.verify,
+				disableHooks:   u.disableHooks,
+				keyring:        u.keyring,
+				values:         u.values,
+				stringValues:   u.stringValues,
+				fileValues:     u.fileValues,
+				namespace:      u.namespace,
+				timeout:        u.timeout,
+				hookTimeout:    u.hookTimeout,
+				wait:           u.wait,
+				description:    u.description,
+				overrideWindow: u.overrideWindow,
+				atomic:         u.atomic,
+				cleanupOnFail:  u.cleanupOnFail,
+				waitTimeouts:   u.waitTimeouts,
+				waitForJobs:    u.waitForJobs,
+				waitConditions: u.waitConditions,
+				labels:         u.labels,
+				output:         u.output,
+				strict:         u.strict,
+				quiet:          u.quiet,
+				serviceAccount: u.serviceAccount,
+				sortOrderFile:  u.sortOrderFile,
 			}
 			return ic.run()
 		}
@@ -248,6 +302,10 @@ func (u *upgradeCmd) run() error {
 	if err != nil {
 		return err
 	}
+	installOrder, uninstallOrder, err := readSortOrderFile(u.sortOrderFile)
+	if err != nil {
+		return err
+	}
 
 	// Check chart requirements to make sure all dependencies are present in /charts
 	ch, err := chartutil.Load(chartPath)
@@ -262,7 +320,14 @@ func (u *upgradeCmd) run() error {
 	} else {
 		return prettyError(err)
 	}
+	if err := chartutil.IsChartInstallable(ch); err != nil {
+		return err
+	}
 
+	var stream *readinessStream
+	if u.output == "json" && u.wait && !u.dryRun {
+		stream = streamReadiness(u.out, u.client, u.release)
+	}
 	resp, err := u.client.UpdateReleaseFromChart(
 		u.release,
 		ch,
@@ -272,31 +337,27 @@ func (u *upgradeCmd) run() error {
 		helm.UpgradeForce(u.force),
 		helm.UpgradeDisableHooks(u.disableHooks),
 		helm.UpgradeTimeout(u.timeout),
+		helm.UpgradeHookTimeout(u.hookTimeout),
 		helm.ResetValues(u.resetValues),
 		helm.ReuseValues(u.reuseValues),
 		helm.UpgradeSubNotes(u.subNotes),
+		helm.UpgradeStrict(u.strict),
+		helm.UpgradeServiceAccount(u.serviceAccount),
 		helm.UpgradeWait(u.wait),
+		helm.UpgradeWaitTimeouts(u.waitTimeouts),
+		helm.UpgradeWaitForJobs(u.waitForJobs),
+		helm.UpgradeWaitConditions(u.waitConditions),
+		helm.UpgradeLabels(u.labels),
 		helm.UpgradeDescription(u.description),
-		helm.UpgradeCleanupOnFail(u.cleanupOnFail))
+		helm.UpgradeOverrideWindow(u.overrideWindow),
+		helm.UpgradeCleanupOnFail(u.cleanupOnFail),
+		helm.UpgradeOnly(u.only),
+		helm.UpgradeSortOrder(installOrder, uninstallOrder))
+	printed := stream.close()
 	if err != nil {
-		fmt.Fprintf(u.out, "UPGRADE FAILED\nError: %v\n", prettyError(err))
-		if u.atomic {
-			fmt.Fprint(u.out, "ROLLING BACK")
-			rollback := &rollbackCmd{
-				out:           u.out,
-				client:        u.client,
-				name:          u.release,
-				dryRun:        u.dryRun,
-				recreate:      u.recreate,
-				force:         u.force,
-				timeout:       u.timeout,
-				wait:          u.wait,
-				description:   "",
-				revision:      releaseHistory.Releases[0].Version,
-				disableHooks:  u.disableHooks,
-				cleanupOnFail: u.cleanupOnFail,
-			}
-			if err := rollback.run(); err != nil {
+		printMessage(u.out, u.quiet, msgUpgradeFailed, prettyError(err))
+		if u.atomic && !u.dryRun {
+			if err := u.revert(); err != nil {
 				return err
 			}
 		}
@@ -307,7 +368,15 @@ func (u *upgradeCmd) run() error {
 		printRelease(u.out, resp.Release)
 	}
 
-	fmt.Fprintf(u.out, "Release %q has been upgraded.\n", u.release)
+	if u.output == "json" && !u.dryRun {
+		return printReadinessAndStatus(u.out, u.client, resp.Release, printed)
+	}
+
+	if u.quiet {
+		fmt.Fprintln(u.out, u.release)
+		return nil
+	}
+	printMessage(u.out, u.quiet, msgUpgraded, u.release)
 
 	// Print the status like status command does
 	status, err := u.client.ReleaseStatus(u.release)
@@ -318,3 +387,63 @@ func (u *upgradeCmd) run() error {
 
 	return nil
 }
+
+// revert undoes a failed atomic upgrade. The release is rolled back to its
+// last deployed revision or, if it was never deployed successfully, purged.
+func (u *upgradeCmd) revert() error {
+	history, err := u.client.ReleaseHistory(u.release, helm.WithMaxHistory(256))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	revision := lastDeployedRevision(history.Releases)
+	if revision == 0 {
+		printMessage(u.out, u.quiet, msgPurgingRelease)
+		purge := &deleteCmd{
+			name:           u.release,
+			disableHooks:   u.disableHooks,
+			purge:          true,
+			timeout:        u.timeout,
+			hookTimeout:    u.hookTimeout,
+			overrideWindow: u.overrideWindow,
+			out:            u.out,
+			client:         u.client,
+			quiet:          u.quiet,
+		}
+		return purge.run()
+	}
+
+	printMessage(u.out, u.quiet, msgRollingBack, revision)
+	rollback := &rollbackCmd{
+		out:            u.out,
+		client:         u.client,
+		name:           u.release,
+		recreate:       u.recreate,
+		force:          u.force,
+		timeout:        u.timeout,
+		hookTimeout:    u.hookTimeout,
+		wait:           u.wait,
+		description:    "",
+		overrideWindow: u.overrideWindow,
+		revision:       revision,
+		disableHooks:   u.disableHooks,
+		cleanupOnFail:  u.cleanupOnFail,
+		waitTimeouts:   u.waitTimeouts,
+		waitForJobs:    u.waitForJobs,
+		waitConditions: u.waitConditions,
+		quiet:          u.quiet,
+	}
+	return rollback.run()
+}
+
+// lastDeployedRevision returns the newest revision of a release history that
+// is deployed, or 0 if there is none.
+func lastDeployedRevision(history []*release.Release) int32 {
+	var revision int32
+	for _, r := range history {
+		if r.Info.Status.Code == release.Status_DEPLOYED && r.Version > revision {
+			revision = r.Version
+		}
+	}
+	return revision
+}
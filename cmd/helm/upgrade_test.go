@@ -99,6 +99,14 @@ func TestUpgradeCmd(t *testing.T) {
 			expected: "Release \"funny-bunny\" has been upgraded.\n",
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-bunny", Version: 2, Chart: ch})},
 		},
+		{
+			name:     "quiet upgrade",
+			args:     []string{"funny-bunny", chartPath},
+			flags:    []string{"--quiet"},
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-bunny", Version: 2, Chart: ch}),
+			expected: "^funny-bunny\n$",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-bunny", Version: 2, Chart: ch})},
+		},
 		{
 			name:     "upgrade a release with timeout",
 			args:     []string{"funny-bunny", chartPath},
@@ -171,6 +179,21 @@ func TestUpgradeCmd(t *testing.T) {
 			expected: "Release \"crazy-bunny\" has been upgraded.\n",
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2, Description: "foo"})},
 		},
+		{
+			name:     "upgrade part of a release",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--only", "kind=ConfigMap"},
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2}),
+			expected: "Release \"crazy-bunny\" has been upgraded.\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2})},
+		},
+		{
+			name:  "upgrade part of a release with force",
+			args:  []string{"crazy-bunny", chartPath},
+			flags: []string{"--only", "kind=ConfigMap", "--force"},
+			resp:  helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2}),
+			err:   true,
+		},
 		{
 			name: "upgrade a release with missing dependencies",
 			args: []string{"bonkers-bunny", missingDepsPath},
@@ -192,3 +215,32 @@ func TestUpgradeCmd(t *testing.T) {
 	runReleaseCases(t, tests, cmd)
 
 }
+
+func TestLastDeployedRevision(t *testing.T) {
+	mock := func(version int32, code release.Status_Code) *release.Release {
+		return helm.ReleaseMock(&helm.MockReleaseOptions{Name: "angry-bird", Version: version, StatusCode: code})
+	}
+
+	tests := []struct {
+		name     string
+		history  []*release.Release
+		expected int32
+	}{
+		{
+			name:     "failed upgrade of a deployed release",
+			history:  []*release.Release{mock(3, release.Status_FAILED), mock(2, release.Status_DEPLOYED), mock(1, release.Status_SUPERSEDED)},
+			expected: 2,
+		},
+		{
+			name:     "failed first install",
+			history:  []*release.Release{mock(2, release.Status_FAILED), mock(1, release.Status_FAILED)},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := lastDeployedRevision(tt.history); got != tt.expected {
+			t.Errorf("%s: expected revision %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+}
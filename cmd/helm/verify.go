@@ -18,10 +18,12 @@ package main
 import (
 	"errors"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/downloader"
+	"k8s.io/helm/pkg/provenance"
 )
 
 const verifyDesc = `
@@ -33,11 +35,25 @@ tampered with, and was packaged by a trusted provider.
 This command can be used to verify a local chart. Several other commands provide
 '--verify' flags that run the same validation. To generate a signed package, use
 the 'helm package --sign' command.
+
+A provenance file may hold the signatures of several signers. By default, the
+chart is valid if one of them is signed by a key of the keyring. With
+'--require-all', every signature must be verified.
+
+Signatures can also be verified by external programs, given with '--verifier'.
+Such a program is given a signature block on its standard input; it must exit
+with a zero status if the signature is valid, after printing the identity of
+the signer. This allows to verify charts signed with keyless signing systems,
+or recorded in transparency logs:
+
+    $ helm verify --verifier 'my-verifier --log https://log.example.com' mychart-0.1.0.tgz
 `
 
 type verifyCmd struct {
-	keyring   string
-	chartfile string
+	keyring    string
+	chartfile  string
+	requireAll bool
+	verifiers  []string
 
 	out io.Writer
 }
@@ -60,11 +76,21 @@ func newVerifyCmd(out io.Writer) *cobra.Command {
 
 	f := cmd.Flags()
 	f.StringVar(&vc.keyring, "keyring", defaultKeyring(), "Keyring containing public keys")
+	f.BoolVar(&vc.requireAll, "require-all", false, "Require every signature of the provenance file to be verified")
+	f.StringArrayVar(&vc.verifiers, "verifier", []string{}, "Command verifying the signatures not signed by a key of the keyring (can specify multiple)")
 
 	return cmd
 }
 
 func (v *verifyCmd) run() error {
-	_, err := downloader.VerifyChart(v.chartfile, v.keyring)
+	policy := provenance.RequireAny
+	if v.requireAll {
+		policy = provenance.RequireAll
+	}
+	var verifiers []provenance.Verifier
+	for _, command := range v.verifiers {
+		verifiers = append(verifiers, &provenance.ExecVerifier{Command: strings.Fields(command)})
+	}
+	_, err := downloader.VerifyChartWithPolicy(v.chartfile, v.keyring, policy, verifiers...)
 	return err
 }
@@ -0,0 +1,302 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kubeschema"
+	"k8s.io/helm/pkg/manifest"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/timeconv"
+	"k8s.io/helm/pkg/version"
+)
+
+const verifyCompatDesc = `
+This command checks a chart against several Kubernetes versions. For each
+version, the chart is rendered with .Capabilities.KubeVersion set to it, and the
+rendered manifests are validated against the Kubernetes API schemas compiled
+into Helm and the API versions served by that version, as with
+'helm template --validate':
+
+	$ helm verify-compat ./mychart --kube-versions 1.13,1.14,1.15,1.16
+
+A version fails if the chart cannot be rendered for it, or if a resource has
+unknown fields, values of the wrong type or an apiVersion it does not serve.
+Deprecated apiVersions are reported as warnings. Each problem names the
+template and the resource it was found in.
+
+The DECLARED column shows whether the kubeVersion range of the Chart.yaml file
+includes the version. A chart without a kubeVersion range declares every
+version. The command fails if a declared version fails, so it can be run before
+releasing a chart to keep its kubeVersion range honest.
+`
+
+type verifyCompatCmd struct {
+	chartPath    string
+	kubeVersions []string
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	fileValues   []string
+	outputFormat string
+	out          io.Writer
+}
+
+// compatResult is the outcome of checking a chart against a Kubernetes version.
+type compatResult struct {
+	KubeVersion string          `json:"kubeVersion"`
+	Declared    bool            `json:"declared"`
+	Passed      bool            `json:"passed"`
+	Problems    []compatProblem `json:"problems,omitempty"`
+}
+
+// compatProblem is a problem found for a Kubernetes version. Problems of the
+// rendering have no source.
+type compatProblem struct {
+	Source  string `json:"source,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+	Warning bool   `json:"warning,omitempty"`
+}
+
+func (p compatProblem) String() string {
+	var b strings.Builder
+	if p.Warning {
+		b.WriteString("WARNING: ")
+	}
+	if p.Kind != "" {
+		fmt.Fprintf(&b, "%s/%s ", p.Kind, p.Name)
+	}
+	if p.Source != "" {
+		fmt.Fprintf(&b, "(%s): ", p.Source)
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+func newVerifyCompatCmd(out io.Writer) *cobra.Command {
+	v := &verifyCompatCmd{out: out}
+
+	cmd := &cobra.Command{
+		Use:   "verify-compat [flags] CHART",
+		Short: "Check that a chart renders valid manifests for several Kubernetes versions",
+		Long:  verifyCompatDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart path"); err != nil {
+				return err
+			}
+			v.chartPath = args[0]
+			return v.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringSliceVar(&v.kubeVersions, "kube-versions", []string{}, "Kubernetes versions to check the chart against, such as 1.14,1.15 (can specify multiple)")
+	f.VarP(&v.valueFiles, "values", "f", "Specify values in a YAML file (can specify multiple)")
+	f.StringArrayVar(&v.values, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&v.stringValues, "set-string", []string{}, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&v.fileValues, "set-file", []string{}, "Set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+	f.StringVarP(&v.outputFormat, "output", "o", "table", "Prints the output in the specified format (json|table|yaml)")
+
+	return cmd
+}
+
+func (v *verifyCompatCmd) run() error {
+	if len(v.kubeVersions) == 0 {
+		return errors.New("at least one Kubernetes version is required, see --kube-versions")
+	}
+	rawVals, err := vals(v.valueFiles, v.values, v.stringValues, v.fileValues, "", "", "")
+	if err != nil {
+		return err
+	}
+	c, err := chartutil.Load(v.chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	if err := chartutil.IsChartInstallable(c); err != nil {
+		return err
+	}
+	declared := c.Metadata.KubeVersion
+	if declared != "" {
+		if _, err := semver.NewConstraint(declared); err != nil {
+			return fmt.Errorf("invalid kubeVersion %q in Chart.yaml: %s", declared, err)
+		}
+	}
+
+	var results []compatResult
+	var failed []string
+	for _, kv := range v.kubeVersions {
+		sv, err := semver.NewVersion(kv)
+		if err != nil {
+			return fmt.Errorf("invalid Kubernetes version %q: %s", kv, err)
+		}
+		// rendering disables the subcharts whose conditions are false, so
+		// each version gets a fresh copy of the chart
+		if c, err = chartutil.Load(v.chartPath); err != nil {
+			return prettyError(err)
+		}
+		res := checkCompat(c, rawVals, kv)
+		res.Declared = declared == "" || version.IsCompatibleRange(declared, sv.String())
+		if res.Declared && !res.Passed {
+			failed = append(failed, kv)
+		}
+		results = append(results, res)
+	}
+
+	var out []byte
+	switch v.outputFormat {
+	case "yaml":
+		out, err = yaml.Marshal(results)
+	case "json":
+		out, err = json.Marshal(results)
+	case "table":
+		out = formatCompat(c.Metadata, results)
+	default:
+		return fmt.Errorf("unknown output format %q", v.outputFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(v.out, strings.TrimSuffix(string(out), "\n"))
+
+	switch {
+	case len(failed) == 0:
+		return nil
+	case declared == "":
+		return fmt.Errorf("chart %q fails on Kubernetes %s", c.Metadata.Name, strings.Join(failed, ", "))
+	}
+	return fmt.Errorf("chart %q fails on Kubernetes %s, included in its kubeVersion %q", c.Metadata.Name, strings.Join(failed, ", "), declared)
+}
+
+// checkCompat renders a chart for a Kubernetes version and validates the
+// rendered manifests against the schemas of that version.
+func checkCompat(c *chart.Chart, rawVals []byte, kubeVersion string) compatResult {
+	res := compatResult{KubeVersion: kubeVersion}
+	fail := func(p compatProblem) {
+		res.Problems = append(res.Problems, p)
+	}
+
+	validator, err := kubeschema.NewValidator(kubeVersion)
+	if err != nil {
+		fail(compatProblem{Message: err.Error()})
+		return res
+	}
+	rendered, err := renderutil.Render(c, &chart.Config{Raw: string(rawVals)}, renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      "release-name",
+			IsInstall: true,
+			Revision:  1,
+			Time:      timeconv.Now(),
+			Namespace: defaultNamespace(),
+		},
+		KubeVersion: kubeVersion,
+	})
+	if err != nil {
+		fail(compatProblem{Message: fmt.Sprintf("could not render the chart: %s", err)})
+		return res
+	}
+
+	for _, m := range manifest.SplitManifests(rendered) {
+		b := filepath.Base(m.Name)
+		if b == "NOTES.txt" || isOutputsFile(m.Name) || strings.HasPrefix(b, "_") {
+			continue
+		}
+		docs := releaseutil.SplitManifests(m.Content)
+		for i := 0; i < len(docs); i++ {
+			doc := docs[fmt.Sprintf("manifest-%d", i)]
+			p := compatProblem{Source: m.Name}
+			var head releaseutil.SimpleHead
+			if err := yaml.Unmarshal([]byte(doc), &head); err == nil {
+				p.Kind = head.Kind
+				if head.Metadata != nil {
+					p.Name = head.Metadata.Name
+				}
+			}
+			findings, err := validator.Validate(doc)
+			if err != nil {
+				p.Message = err.Error()
+				fail(p)
+				continue
+			}
+			for _, f := range findings {
+				p.Message = f.Error()
+				p.Warning = f.Type == kubeschema.DeprecatedAPI
+				res.Problems = append(res.Problems, p)
+			}
+		}
+	}
+
+	res.Passed = true
+	for _, p := range res.Problems {
+		if !p.Warning {
+			res.Passed = false
+		}
+	}
+	return res
+}
+
+func formatCompat(md *chart.Metadata, results []compatResult) []byte {
+	var b strings.Builder
+	declared := md.KubeVersion
+	if declared == "" {
+		declared = "none"
+	}
+	fmt.Fprintf(&b, "CHART: %s-%s\nKUBE VERSION RANGE: %s\n", md.Name, md.Version, declared)
+
+	table := uitable.New()
+	table.AddRow("KUBE VERSION", "DECLARED", "RESULT", "PROBLEMS", "WARNINGS")
+	for _, r := range results {
+		result := "pass"
+		if !r.Passed {
+			result = "fail"
+		}
+		warnings := 0
+		for _, p := range r.Problems {
+			if p.Warning {
+				warnings++
+			}
+		}
+		table.AddRow(r.KubeVersion, yesNo(r.Declared), result, len(r.Problems)-warnings, warnings)
+	}
+	fmt.Fprintln(&b, table)
+
+	for _, r := range results {
+		if len(r.Problems) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\nKubernetes %s:\n", r.KubeVersion)
+		for _, p := range r.Problems {
+			fmt.Fprintf(&b, "  %s\n", p)
+		}
+	}
+	return []byte(b.String())
+}
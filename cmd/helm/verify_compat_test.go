@@ -0,0 +1,65 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestVerifyCompatCmd(t *testing.T) {
+	const chartPath = "testdata/testcharts/compat"
+	tests := []releaseCase{
+		{
+			name:     "declared versions pass",
+			args:     []string{chartPath},
+			flags:    []string{"--kube-versions", "1.13,1.16"},
+			expected: `(?s)CHART: compat-0\.1\.0\nKUBE VERSION RANGE: >=1\.13\.0-0, <1\.16\.0-0\n.*1\.13\s+yes\s+pass\s+0\s+1\s*\n1\.16\s+no\s+fail\s+1\s+0.*Kubernetes 1\.16:\n  Deployment/web \(compat/templates/deployment\.yaml\): apiVersion: extensions/v1beta1 Deployment is not served by Kubernetes 1\.16`,
+		},
+		{
+			name:     "declared version fails",
+			args:     []string{chartPath},
+			flags:    []string{"--kube-versions", "1.14", "--set-string", "replicas=two"},
+			expected: `Kubernetes 1\.14:\n  WARNING: Deployment/web \(compat/templates/deployment\.yaml\): apiVersion: .*\n  Deployment/web \(compat/templates/deployment\.yaml\): spec\.replicas: expected integer, got string`,
+			err:      true,
+		},
+		{
+			name:     "json output",
+			args:     []string{chartPath},
+			flags:    []string{"--kube-versions", "1.16", "--output", "json"},
+			expected: `^\[\{"kubeVersion":"1\.16","declared":false,"passed":false,"problems":\[\{"source":"compat/templates/deployment\.yaml","kind":"Deployment","name":"web","message":"apiVersion: extensions/v1beta1 Deployment is not served`,
+		},
+		{
+			name: "kube versions required",
+			args: []string{chartPath},
+			err:  true,
+		},
+		{
+			name:  "invalid kube version",
+			args:  []string{chartPath},
+			flags: []string{"--kube-versions", "one"},
+			err:   true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newVerifyCompatCmd(out)
+	})
+}
@@ -70,6 +70,20 @@ func TestVerifyCmd(t *testing.T) {
 			expect: "",
 			err:    false,
 		},
+		{
+			name:   "verify requires every signature with --require-all",
+			args:   []string{"testdata/testcharts/signtest-0.1.0.tgz"},
+			flags:  []string{"--keyring", "testdata/helm-test-key.pub", "--require-all"},
+			expect: "",
+			err:    false,
+		},
+		{
+			name:   "verify requires a verifier",
+			args:   []string{"testdata/testcharts/signtest-0.1.0.tgz"},
+			flags:  []string{"--keyring", ""},
+			expect: "no signature verifier given",
+			err:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,123 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+const windowDesc = `
+This command sets the maintenance window of a release. Outside of it, Tiller
+refuses to upgrade, roll back or delete the release, unless the operation is
+run with '--override-window REASON'. The reason is recorded in the audit log of
+Tiller.
+
+The window opens at the times matched by a cron schedule of five fields
+(minute, hour, day of month, month and day of week) and stays open for the
+given duration. The schedule is read in UTC unless '--timezone' is given:
+
+	$ helm window my-release --schedule "0 2 * * 6" --duration 4h --timezone Europe/Berlin
+
+The window of a release replaces the maintenance windows of the Tiller
+configuration. '--clear' removes it, so that only those apply again.
+`
+
+type windowCmd struct {
+	release  string
+	schedule string
+	duration string
+	timezone string
+	clear    bool
+	quiet    bool
+	out      io.Writer
+	client   helm.Interface
+}
+
+func newWindowCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	w := &windowCmd{
+		out:    out,
+		client: client,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "window [flags] RELEASE_NAME",
+		Short:   "Set the maintenance window during which a release may be changed",
+		Long:    windowDesc,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return setupConnection() },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "release name"); err != nil {
+				return err
+			}
+			w.release = args[0]
+			w.client = ensureHelmClient(w.client)
+			return w.run()
+		},
+	}
+
+	flags := cmd.Flags()
+	settings.AddFlagsTLS(flags)
+	flags.StringVar(&w.schedule, "schedule", "", "Cron schedule of the times the window opens, such as \"0 2 * * 6\"")
+	flags.StringVar(&w.duration, "duration", "", "How long the window stays open, such as 4h")
+	flags.StringVar(&w.timezone, "timezone", "", "Time zone the schedule is read in, such as Europe/Berlin. Defaults to UTC")
+	flags.BoolVar(&w.clear, "clear", false, "Remove the maintenance window of the release")
+	flags.BoolVar(&w.quiet, "quiet", false, "Print nothing on success")
+
+	// set defaults from environment
+	settings.InitTLS(flags)
+
+	return cmd
+}
+
+func (w *windowCmd) run() error {
+	if w.clear {
+		if w.schedule != "" || w.duration != "" || w.timezone != "" {
+			return errors.New("--clear cannot be used with --schedule, --duration or --timezone")
+		}
+		if _, err := w.client.UpdateReleaseMetadata(w.release,
+			helm.MetadataAnnotations(nil, relutil.WindowAnnotations),
+		); err != nil {
+			return prettyError(err)
+		}
+		printMessage(w.out, w.quiet, msgWindowCleared, w.release)
+		return nil
+	}
+
+	if w.schedule == "" || w.duration == "" {
+		return errors.New("--schedule and --duration are required, or --clear to remove the window")
+	}
+	window, err := relutil.ParseWindow(w.schedule, w.duration, w.timezone)
+	if err != nil {
+		return err
+	}
+
+	// clear the previous window so a new one in UTC does not inherit its
+	// time zone
+	_, err = w.client.UpdateReleaseMetadata(w.release,
+		helm.MetadataAnnotations(window.Annotations(), relutil.WindowAnnotations),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+	printMessage(w.out, w.quiet, msgWindowSet, w.release, window)
+	return nil
+}
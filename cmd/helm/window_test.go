@@ -0,0 +1,99 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+func TestWindowCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "set a window",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--schedule", "0 2 * * 6", "--duration", "4h", "--timezone", "Europe/Berlin"},
+			expected: "Release \"thomas-guide\" may now only be changed during 0 2 \\* \\* 6 for 4h0m0s \\(Europe/Berlin\\).\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name:  "invalid schedule",
+			args:  []string{"thomas-guide"},
+			flags: []string{"--schedule", "0 2 * *", "--duration", "4h"},
+			err:   true,
+			rels:  []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name:  "duration required",
+			args:  []string{"thomas-guide"},
+			flags: []string{"--schedule", "0 2 * * 6"},
+			err:   true,
+			rels:  []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name:  "clear with a schedule",
+			args:  []string{"thomas-guide"},
+			flags: []string{"--clear", "--schedule", "0 2 * * 6"},
+			err:   true,
+			rels:  []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})},
+		},
+		{
+			name: "release required",
+			args: []string{},
+			err:  true,
+		},
+	}
+
+	runReleaseCases(t, tests, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newWindowCmd(c, out)
+	})
+}
+
+func TestWindowCmdClear(t *testing.T) {
+	rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "thomas-guide"})
+	w, err := relutil.ParseWindow("0 2 * * 6", "4h", "Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel.Annotations = w.Annotations()
+	rel.Annotations["owner"] = "alice"
+
+	runReleaseCases(t, []releaseCase{
+		{
+			name:     "clear the window",
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--clear"},
+			expected: "Release \"thomas-guide\" no longer has its own maintenance window.\n",
+			rels:     []*release.Release{rel},
+		},
+	}, func(c *helm.FakeClient, out io.Writer) *cobra.Command {
+		return newWindowCmd(c, out)
+	})
+
+	if w, _ := relutil.GetWindow(rel); w != nil {
+		t.Errorf("expected the window to be removed, got annotations %v", rel.Annotations)
+	}
+	if rel.Annotations["owner"] != "alice" {
+		t.Errorf("expected other annotations to be kept, got %v", rel.Annotations)
+	}
+}
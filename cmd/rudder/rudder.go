@@ -109,7 +109,7 @@ func (r *ReleaseModuleServiceServer) DeleteRelease(ctx context.Context, in *rudd
 		return resp, fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)
 	}
 
-	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient)
+	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient, kube.CascadeBackground)
 	rel.Manifest = kept
 
 	allErrors := ""
@@ -137,6 +137,7 @@ func (r *ReleaseModuleServiceServer) RollbackRelease(ctx context.Context, in *ru
 		Timeout:       in.Timeout,
 		ShouldWait:    in.Wait,
 		CleanupOnFail: in.CleanupOnFail,
+		Rollback:      true,
 	})
 	return &rudderAPI.RollbackReleaseResponse{}, err
 }
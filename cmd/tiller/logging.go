@@ -0,0 +1,97 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main // import "k8s.io/helm/cmd/tiller"
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+
+	"k8s.io/klog"
+
+	"k8s.io/helm/pkg/logging"
+)
+
+var (
+	logFormat = flag.String("log-format", "text", "format of the logs: text, or json for log pipelines")
+	logLevel  = &levelFlag{level: logging.InfoLevel}
+
+	// rootLogger is the logger all the components of Tiller derive theirs from.
+	rootLogger = logging.New(os.Stderr, logging.TextFormat, logging.InfoLevel)
+)
+
+// levelFlag is the -v flag. It takes a level name, such as debug, or like
+// klog a number: the verbosity of the Kubernetes client, which also enables
+// the debug level from 4 on.
+type levelFlag struct {
+	level logging.Level
+	klog  flag.Value
+}
+
+func (f *levelFlag) String() string {
+	return f.level.String()
+}
+
+func (f *levelFlag) Set(s string) error {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n >= 4 {
+			f.level = logging.DebugLevel
+		}
+		return f.klog.Set(strconv.Itoa(n))
+	}
+	level, err := logging.ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	f.level = level
+	return nil
+}
+
+// initLogFlags registers the flags of the logs, taking over the -v flag of
+// klog.
+func initLogFlags() {
+	klogFlags := flag.NewFlagSet("klog", flag.ExitOnError)
+	klog.InitFlags(klogFlags)
+	klogFlags.VisitAll(func(f *flag.Flag) {
+		if f.Name == "v" {
+			logLevel.klog = f.Value
+			return
+		}
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+	flag.Var(logLevel, "v", "log level: debug, info, warn or error, or a number for the verbosity of the Kubernetes client")
+}
+
+// setupLogging applies the log flags, and sends the output of the standard
+// logger through rootLogger.
+func setupLogging() error {
+	format, err := logging.ParseFormat(*logFormat)
+	if err != nil {
+		return err
+	}
+	rootLogger.SetFormat(format)
+	rootLogger.SetLevel(logLevel.level)
+
+	log.SetOutput(rootLogger)
+	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
+	return nil
+}
+
+func newLogger(component string) *logging.Logger {
+	return rootLogger.Component(component)
+}
@@ -36,16 +36,18 @@ import (
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
-	"k8s.io/klog"
 
 	// Import to initialize client auth plugins.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
 	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/config"
 	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/tlsutil"
 	"k8s.io/helm/pkg/version"
@@ -62,10 +64,12 @@ const (
 	tlsCertsEnvVar = "TILLER_TLS_CERTS"
 	// historyMaxEnvVar is the name of the env var for setting max history.
 	historyMaxEnvVar = "TILLER_HISTORY_MAX"
+	// configEnvVar names the environment variable that points to Tiller's
+	// configuration file.
+	configEnvVar = "TILLER_CONFIG"
 
 	storageMemory    = "memory"
 	storageConfigMap = "configmap"
-	storageSecret    = "secret"
 	storageSQL       = "sql"
 
 	traceAddr = ":44136"
@@ -78,12 +82,18 @@ var (
 	grpcAddr      = flag.String("listen", fmt.Sprintf(":%v", environment.DefaultTillerPort), "address:port to listen on")
 	probeAddr     = flag.String("probe-listen", fmt.Sprintf(":%v", environment.DefaultTillerProbePort), "address:port to listen on for probes")
 	enableTracing = flag.Bool("trace", false, "enable rpc tracing")
-	store         = flag.String("storage", storageConfigMap, "storage driver to use. One of 'configmap', 'memory', 'sql' or 'secret'")
+	store         = flag.String("storage", storageConfigMap, fmt.Sprintf("storage driver to use. One of %s", strings.Join(driver.Registered(), ", ")))
+	storageOpts   = storageOptions{}
 
 	sqlDialect          = flag.String("sql-dialect", "postgres", "SQL dialect to use (only postgres is supported for now")
 	sqlConnectionString = flag.String("sql-connection-string", "", "SQL connection string to use")
 
 	remoteReleaseModules = flag.Bool("experimental-release", false, "enable experimental release modules")
+	enableDNSLookups     = flag.Bool("enable-dns-lookups", false, "let the getHostByName template function resolve names")
+	capabilitiesTTL      = flag.Duration("capabilities-ttl", tiller.DefaultCapabilitiesTTL, "how long the capabilities of the cluster are cached, with 0 meaning no cache")
+	templateCacheSize    = flag.Int("template-cache-size", 100, "number of charts whose parsed templates are cached, with 0 meaning no cache")
+	renderParallelism    = flag.Int("render-parallelism", 0, "number of templates of a chart rendered at the same time, with 0 meaning one per CPU")
+	impersonateUsers     = flag.Bool("user-impersonate", false, "apply the changes to the resources of releases as the users calling Tiller, authenticated by their Kubernetes token")
 
 	tlsEnable    = flag.Bool("tls", tlsEnableEnvVarDefault(), "enable TLS")
 	tlsVerify    = flag.Bool("tls-verify", tlsVerifyEnvVarDefault(), "enable TLS and verify remote certificate")
@@ -93,6 +103,9 @@ var (
 	maxHistory   = flag.Int("history-max", historyMaxFromEnv(), "maximum number of releases kept in release history, with 0 meaning no limit")
 	printVersion = flag.Bool("version", false, "print the version number")
 
+	configFile     = flag.String("config", os.Getenv(configEnvVar), "path to a configuration file, usually a mounted ConfigMap, that is reloaded on change")
+	configInterval = flag.Duration("config-reload-interval", config.DefaultWatchInterval, "how often the configuration file is checked for changes")
+
 	// rootServer is the root gRPC server.
 	//
 	// Each gRPC service registers itself to this server during start().
@@ -103,11 +116,12 @@ var (
 	// Any changes to env should be done before rootServer.Serve() is called.
 	env = environment.New()
 
-	logger *log.Logger
+	logger *logging.Logger
 )
 
 func main() {
-	klog.InitFlags(nil)
+	initLogFlags()
+	flag.Var(storageOpts, "storage-opt", "driver specific storage setting of the form key=value (can specify multiple)")
 	// TODO: use spf13/cobra for tiller instead of flags
 	flag.Parse()
 
@@ -120,6 +134,9 @@ func main() {
 		log.SetFlags(log.Lshortfile)
 	}
 	logger = newLogger("main")
+	if err := setupLogging(); err != nil {
+		logger.Fatalf("Invalid log settings: %s", err)
+	}
 
 	start()
 }
@@ -134,32 +151,43 @@ func start() {
 		logger.Fatalf("Cannot initialize Kubernetes connection: %s", err)
 	}
 
-	switch *store {
-	case storageMemory:
-		env.Releases = storage.Init(driver.NewMemory())
-	case storageConfigMap:
-		cfgmaps := driver.NewConfigMaps(clientset.CoreV1().ConfigMaps(namespace()))
-		cfgmaps.Log = newLogger("storage/driver").Printf
-
-		env.Releases = storage.Init(cfgmaps)
-		env.Releases.Log = newLogger("storage").Printf
-	case storageSecret:
-		secrets := driver.NewSecrets(clientset.CoreV1().Secrets(namespace()))
-		secrets.Log = newLogger("storage/driver").Printf
+	if *impersonateUsers {
+		if *remoteReleaseModules {
+			logger.Fatalf("--user-impersonate cannot be used with --experimental-release")
+		}
+		if !*tlsEnable && !*tlsVerify {
+			logger.Printf("WARNING: --user-impersonate without TLS, the tokens of the users are sent in plain text")
+		}
+	}
 
-		env.Releases = storage.Init(secrets)
-		env.Releases.Log = newLogger("storage").Printf
-	case storageSQL:
-		sqlDriver, err := driver.NewSQL(
-			*sqlDialect,
-			*sqlConnectionString,
-			newLogger("storage/driver").Printf,
-		)
+	var watcher *config.Watcher
+	if *configFile != "" {
+		cfg, err := config.Load(*configFile)
 		if err != nil {
-			logger.Fatalf("Cannot initialize SQL storage driver: %v", err)
+			logger.Fatalf("Cannot load configuration: %s", err)
 		}
+		applyStorageConfig(cfg.Storage)
+
+		watcher = config.NewWatcher(*configFile, applyConfig)
+		watcher.Interval = *configInterval
+		watcher.Log = newLogger("config").Printf
+	}
 
-		env.Releases = storage.Init(sqlDriver)
+	if *store == storageSQL {
+		storageOpts.setDefault("dialect", *sqlDialect)
+		storageOpts.setDefault("connection-string", *sqlConnectionString)
+	}
+	storageDriver, err := driver.New(*store, driver.Options{
+		Namespace: namespace(),
+		Clientset: clientset,
+		Config:    storageOpts,
+		Log:       newLogger("storage/driver").Printf,
+	})
+	if err != nil {
+		logger.Fatalf("Cannot initialize %s storage driver: %v", *store, err)
+	}
+	env.Releases = storage.Init(storageDriver)
+	if *store != storageMemory {
 		env.Releases.Log = newLogger("storage").Printf
 	}
 
@@ -167,9 +195,32 @@ func start() {
 		env.Releases.MaxHistory = *maxHistory
 	}
 
+	if watcher != nil {
+		// The first check always succeeds as the file was loaded above.
+		watcher.Check()
+		go watcher.Run(make(chan struct{}))
+		logger.Printf("Configuration loaded from %s", *configFile)
+	}
+
 	kubeClient := kube.New(nil)
 	kubeClient.Log = newLogger("kube").Printf
 	env.KubeClient = kubeClient
+	env.Impersonate = func(user string, groups []string) environment.KubeClient {
+		c := kube.NewImpersonating(user, groups...)
+		c.Log = newLogger("kube").Printf
+		return c
+	}
+
+	if e, ok := env.EngineYard.Get(environment.GoTplEngine); ok {
+		if gotpl, ok := e.(*engine.Engine); ok {
+			gotpl.Lookup = kubeClient.Lookup
+			gotpl.EnableDNSLookups = *enableDNSLookups
+			gotpl.Parallelism = *renderParallelism
+			if *templateCacheSize > 0 {
+				gotpl.Templates = engine.NewTemplateCache(*templateCacheSize)
+			}
+		}
+	}
 
 	if *tlsEnable || *tlsVerify {
 		opts := tlsutil.Options{CertFile: *certFile, KeyFile: *keyFile}
@@ -218,6 +269,9 @@ func start() {
 	go func() {
 		svc := tiller.NewReleaseServer(env, clientset, *remoteReleaseModules)
 		svc.Log = newLogger("tiller").Printf
+		svc.Logger = newLogger("tiller")
+		svc.CapabilitiesTTL = *capabilitiesTTL
+		svc.ImpersonateUsers = *impersonateUsers
 		services.RegisterReleaseServiceServer(rootServer, svc)
 		if err := rootServer.Serve(lstn); err != nil {
 			srvErrCh <- err
@@ -228,6 +282,7 @@ func start() {
 		mux := newProbesMux()
 
 		// Register gRPC server to prometheus to initialized matrix
+		goprom.EnableHandlingTimeHistogram()
 		goprom.Register(rootServer)
 		addPrometheusHandler(mux)
 
@@ -246,11 +301,68 @@ func start() {
 	}
 }
 
-func newLogger(prefix string) *log.Logger {
-	if len(prefix) > 0 {
-		prefix = fmt.Sprintf("[%s] ", prefix)
+// applyStorageConfig overrides the storage flags with the configured values.
+// It is only used at startup, since the storage driver cannot be swapped while
+// Tiller is running.
+func applyStorageConfig(c config.Storage) {
+	if c.Driver != "" {
+		*store = c.Driver
+	}
+	if c.SQLDialect != "" {
+		*sqlDialect = c.SQLDialect
+	}
+	if c.SQLConnectionString != "" {
+		*sqlConnectionString = c.SQLConnectionString
+	}
+	for k, v := range c.Options {
+		storageOpts[k] = v
+	}
+}
+
+// storageOptions holds the driver specific settings given with the repeatable
+// --storage-opt key=value flag.
+type storageOptions map[string]string
+
+func (o storageOptions) String() string {
+	opts := make([]string, 0, len(o))
+	for k, v := range o {
+		opts = append(opts, k+"="+v)
+	}
+	return strings.Join(opts, ",")
+}
+
+func (o storageOptions) Set(value string) error {
+	kv := strings.SplitN(value, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("storage option %q is not of the form key=value", value)
+	}
+	o[kv[0]] = kv[1]
+	return nil
+}
+
+func (o storageOptions) setDefault(key, value string) {
+	if _, ok := o[key]; !ok {
+		o[key] = value
+	}
+}
+
+// applyConfig makes a freshly loaded configuration the active one.
+//
+// The max history of the configuration overrides --history-max, which applies
+// again once the configuration no longer sets it.
+func applyConfig(c *config.Config) {
+	max := *maxHistory
+	if c.HistoryMax != nil {
+		max = *c.HistoryMax
+	}
+	if max != env.Releases.MaxHistory {
+		env.Releases.SetMaxHistory(max)
+		logger.Printf("Max history per release is %d", max)
+	}
+	if c.Storage.Driver != "" && c.Storage.Driver != *store {
+		logger.Printf("Storage driver changed to %s; restart Tiller for it to take effect", c.Storage.Driver)
 	}
-	return log.New(os.Stderr, prefix, log.Flags())
+	env.Config.Set(c)
 }
 
 // namespace returns the namespace of tiller
@@ -20,6 +20,9 @@ import (
 	"testing"
 
 	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/config"
 	"k8s.io/helm/pkg/tiller/environment"
 )
 
@@ -45,3 +48,44 @@ func TestInit(t *testing.T) {
 		t.Fatalf("Template engine GoTplEngine returned nil.")
 	}
 }
+
+func TestApplyConfigHistoryMax(t *testing.T) {
+	defer func(releases *storage.Storage, flag int) {
+		env.Releases, *maxHistory = releases, flag
+	}(env.Releases, *maxHistory)
+	env.Releases = storage.Init(driver.NewMemory())
+	logger = newLogger("main")
+	*maxHistory = 5
+
+	historyMax := 2
+	applyConfig(&config.Config{HistoryMax: &historyMax})
+	if env.Releases.MaxHistory != 2 {
+		t.Errorf("expected the max history of the configuration, got %d", env.Releases.MaxHistory)
+	}
+
+	applyConfig(&config.Config{})
+	if env.Releases.MaxHistory != 5 {
+		t.Errorf("expected the max history of --history-max, got %d", env.Releases.MaxHistory)
+	}
+}
+
+func TestStorageOptions(t *testing.T) {
+	opts := storageOptions{}
+	for _, v := range []string{"region=eu-west-1", "table=helm=releases"} {
+		if err := opts.Set(v); err != nil {
+			t.Fatalf("Failed to set %q: %s", v, err)
+		}
+	}
+	if opts["region"] != "eu-west-1" || opts["table"] != "helm=releases" {
+		t.Errorf("Unexpected storage options %v", opts)
+	}
+	if err := opts.Set("region"); err == nil {
+		t.Error("Expected an option without a value to be rejected")
+	}
+
+	opts.setDefault("region", "us-east-1")
+	opts.setDefault("endpoint", "localhost")
+	if opts["region"] != "eu-west-1" || opts["endpoint"] != "localhost" {
+		t.Errorf("Unexpected storage options %v", opts)
+	}
+}
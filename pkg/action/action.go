@@ -0,0 +1,81 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action // import "k8s.io/helm/pkg/action"
+
+import (
+	"io"
+	"io/ioutil"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/kube"
+)
+
+// Configuration is the environment the actions run in.
+type Configuration struct {
+	// Home is the Helm home holding the repositories and their caches.
+	Home helmpath.Home
+	// Getters fetch the indexes and charts of repositories.
+	Getters getter.Providers
+	// Out receives the messages of the actions, such as the progress of
+	// downloads. Nil discards them.
+	Out io.Writer
+	// Log receives debug messages.
+	Log func(string, ...interface{})
+	// Debug makes the errors of the actions more detailed.
+	Debug bool
+}
+
+// NewConfiguration returns the configuration of the given settings, as
+// resolved from the flags and environment of the helm command.
+func NewConfiguration(settings environment.EnvSettings) *Configuration {
+	return &Configuration{
+		Home:    settings.Home,
+		Getters: getter.All(settings),
+		Log:     nopLogger,
+		Debug:   settings.Debug,
+	}
+}
+
+var nopLogger = func(_ string, _ ...interface{}) {}
+
+func (c *Configuration) out() io.Writer {
+	if c.Out == nil {
+		return ioutil.Discard
+	}
+	return c.Out
+}
+
+func (c *Configuration) log(format string, v ...interface{}) {
+	if c.Log != nil {
+		c.Log(format, v...)
+	}
+}
+
+// DefaultNamespace returns the namespace releases go to when none is given:
+// the namespace of the settings, or else the one of the kube context, or else
+// "default".
+func DefaultNamespace(settings environment.EnvSettings) string {
+	if settings.Namespace != "" {
+		return settings.Namespace
+	}
+	if ns, _, err := kube.GetConfig(settings.KubeContext, settings.KubeConfig).Namespace(); err == nil {
+		return ns
+	}
+	return "default"
+}
@@ -0,0 +1,204 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action // import "k8s.io/helm/pkg/action"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/downloader"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/repo"
+)
+
+// ChartPathOptions tells where and how to get a chart.
+type ChartPathOptions struct {
+	// Version is the version or version range of the chart.
+	Version string
+	// RepoURL is the URL of the repository to find the chart in, instead of
+	// the repositories of the Helm home.
+	RepoURL  string
+	Username string
+	Password string
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// Verify is how the chart is verified against its provenance file.
+	Verify  downloader.VerificationStrategy
+	Keyring string
+}
+
+// DownloadChart downloads a chart, given as a chart reference or a URL, to the
+// directory dest. It returns the path of the archive and, if it was verified,
+// its verification.
+func (c *Configuration) DownloadChart(ref string, opts ChartPathOptions, dest string) (string, *provenance.Verification, error) {
+	dl := downloader.ChartDownloader{
+		HelmHome: c.Home,
+		Out:      c.out(),
+		Keyring:  opts.Keyring,
+		Verify:   opts.Verify,
+		Getters:  c.Getters,
+		Username: opts.Username,
+		Password: opts.Password,
+	}
+
+	ref, version, err := c.findChartInRepoURL(ref, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return dl.DownloadTo(ref, version, dest)
+}
+
+// findChartInRepoURL returns the URL of a chart reference, and the version to
+// download it at, when the chart is found in the repository of opts.RepoURL.
+// Without a repository URL, the reference and version are returned as given.
+func (c *Configuration) findChartInRepoURL(ref string, opts ChartPathOptions) (string, string, error) {
+	if opts.RepoURL == "" {
+		return ref, opts.Version, nil
+	}
+	chartName, chartVersion, digest, err := downloader.ParseChartReference(ref, opts.Version)
+	if err != nil {
+		return "", "", err
+	}
+	chartURL, err := repo.FindChartInAuthRepoURL(opts.RepoURL, opts.Username, opts.Password, chartName, chartVersion,
+		opts.CertFile, opts.KeyFile, opts.CAFile, c.Getters)
+	if err != nil {
+		return "", "", err
+	}
+	return pinChartURL(chartURL, digest), "", nil
+}
+
+// pinChartURL pins the URL of a chart found in a repository given by URL to a
+// digest, so that the downloader checks the archive against it.
+func pinChartURL(chartURL, digest string) string {
+	if digest == "" {
+		return chartURL
+	}
+	return chartURL + "@sha256:" + digest
+}
+
+// LocateChart returns the path of a chart, downloading it to the archive of
+// the Helm home if needed.
+//
+// Order of resolution:
+// - current working directory
+// - if path is absolute or begins with '.', error out here
+// - chart repos in $HELM_HOME
+// - URL
+//
+// Charts that are local files are verified if opts.Verify is VerifyAlways.
+func (c *Configuration) LocateChart(name string, opts ChartPathOptions) (string, error) {
+	name = strings.TrimSpace(name)
+	opts.Version = strings.TrimSpace(opts.Version)
+	if fi, err := os.Stat(name); err == nil {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return abs, err
+		}
+		if opts.Verify == downloader.VerifyAlways {
+			if fi.IsDir() {
+				return "", errors.New("cannot verify a directory")
+			}
+			if _, err := downloader.VerifyChart(abs, opts.Keyring); err != nil {
+				return "", err
+			}
+		}
+		return abs, nil
+	}
+	if filepath.IsAbs(name) || strings.HasPrefix(name, ".") {
+		return name, fmt.Errorf("path %q not found", name)
+	}
+
+	crepo := filepath.Join(c.Home.Repository(), name)
+	if _, err := os.Stat(crepo); err == nil {
+		return filepath.Abs(crepo)
+	}
+
+	// A chart missing from the repository is reported as it is, rather than
+	// as a failed download.
+	ref, version, err := c.findChartInRepoURL(name, opts)
+	if err != nil {
+		return "", err
+	}
+	opts.RepoURL, opts.Version = "", version
+
+	if _, err := os.Stat(c.Home.Archive()); os.IsNotExist(err) {
+		os.MkdirAll(c.Home.Archive(), 0744)
+	}
+
+	filename, _, err := c.DownloadChart(ref, opts, c.Home.Archive())
+	if err == nil {
+		lname, err := filepath.Abs(filename)
+		if err != nil {
+			return filename, err
+		}
+		c.log("Fetched %s to %s\n", name, filename)
+		return lname, nil
+	} else if c.Debug {
+		return filename, err
+	}
+
+	return filename, fmt.Errorf("failed to download %q (hint: running `helm repo update` may help)", name)
+}
+
+// LoadChart loads the chart at path and checks that the dependencies of its
+// requirements are in its charts/ directory. If they are not and
+// updateDependencies is set, they are updated from the requirements first, as
+// by 'helm dependency update'.
+func (c *Configuration) LoadChart(path string, updateDependencies bool, keyring string) (*chart.Chart, error) {
+	ch, err := chartutil.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := chartutil.LoadRequirements(ch)
+	if err == chartutil.ErrRequirementsNotFound {
+		return ch, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot load requirements: %v", err)
+	}
+	// If CheckDependencies returns an error, we have unfulfilled dependencies.
+	// As of Helm 2.4.0, this is treated as a stopping condition:
+	// https://github.com/kubernetes/helm/issues/2209
+	if err := renderutil.CheckDependencies(ch, req); err != nil {
+		if !updateDependencies {
+			return nil, err
+		}
+		man := &downloader.Manager{
+			Out:       c.out(),
+			ChartPath: path,
+			HelmHome:  c.Home,
+			Keyring:   keyring,
+			Getters:   c.Getters,
+			Debug:     c.Debug,
+		}
+		if err := man.Update(); err != nil {
+			return nil, err
+		}
+
+		// Update all dependencies which are present in /charts.
+		return chartutil.Load(path)
+	}
+	return ch, nil
+}
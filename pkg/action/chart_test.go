@@ -0,0 +1,76 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action // import "k8s.io/helm/pkg/action"
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/repo"
+)
+
+func TestLocateChart(t *testing.T) {
+	cfg, srv := newTestConfiguration(t)
+	defer cleanupTestConfiguration(cfg, srv)
+
+	local := "../repo/repotest/testdata/examplechart"
+	path, err := cfg.LocateChart(local, ChartPathOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abs, _ := filepath.Abs(local); path != abs {
+		t.Errorf("expected %s, got %s", abs, path)
+	}
+
+	if _, err := cfg.LocateChart("./missing", ChartPathOptions{}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	path, err = cfg.LocateChart("examplechart", ChartPathOptions{RepoURL: srv.URL(), Version: "0.1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := filepath.Join(cfg.Home.Archive(), "examplechart-0.1.0.tgz"); path != expect {
+		t.Errorf("expected %s, got %s", expect, path)
+	}
+
+	if err := cfg.AddRepository(&repo.Entry{Name: "test", URL: srv.URL()}, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.LocateChart("test/examplechart", ChartPathOptions{}); err != nil {
+		t.Errorf("expected the chart to be found in the test repository: %s", err)
+	}
+	if _, err := cfg.LocateChart("test/nosuchchart", ChartPathOptions{}); err == nil || !strings.Contains(err.Error(), "hint") {
+		t.Errorf("expected a download error, got %v", err)
+	}
+}
+
+func TestLoadChart(t *testing.T) {
+	cfg := &Configuration{}
+	ch, err := cfg.LoadChart("../repo/repotest/testdata/examplechart", false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Metadata.Name != "examplechart" {
+		t.Errorf("expected examplechart, got %s", ch.Metadata.Name)
+	}
+
+	if _, err := cfg.LoadChart("testdata/missing-deps", false, ""); err == nil {
+		t.Error("expected an error loading a chart with missing dependencies")
+	}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package action implements the client side operations of the helm command, such
+as managing chart repositories and locating charts, so that other programs can
+perform them without going through the command line.
+
+The operations take their environment from a Configuration instead of the
+global settings of the helm command:
+
+	cfg := action.NewConfiguration(settings)
+	err := cfg.AddRepository(&repo.Entry{Name: "stable", URL: url}, false)
+*/
+package action // import "k8s.io/helm/pkg/action"
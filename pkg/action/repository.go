@@ -0,0 +1,140 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action // import "k8s.io/helm/pkg/action"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"k8s.io/helm/pkg/repo"
+)
+
+var (
+	// ErrNoRepositories indicates that no repository has been added.
+	ErrNoRepositories = errors.New("no repositories found. You must add one before updating")
+	// ErrNoRepositoriesMatchingRepoName indicates that no repository has the
+	// requested name.
+	ErrNoRepositoriesMatchingRepoName = errors.New("no repositories found matching the provided name. Verify if the repo exists")
+)
+
+// AddRepository downloads the index of a repository and adds it to the
+// repositories file. If noUpdate is set, it fails when a repository of the
+// same name exists instead of replacing it.
+//
+// The cache of the entry defaults to the cache index of its name.
+func (c *Configuration) AddRepository(e *repo.Entry, noUpdate bool) error {
+	f, err := repo.LoadRepositoriesFile(c.Home.RepositoryFile())
+	if err != nil {
+		return err
+	}
+
+	if noUpdate && f.Has(e.Name) {
+		return fmt.Errorf("repository name (%s) already exists, please specify a different name", e.Name)
+	}
+
+	if e.Cache == "" {
+		e.Cache = c.Home.CacheIndex(e.Name)
+	}
+	r, err := repo.NewChartRepository(e, c.Getters)
+	if err != nil {
+		return err
+	}
+
+	if err := r.DownloadIndexFile(c.Home.Cache()); err != nil {
+		return fmt.Errorf("Looks like %q is not a valid chart repository or cannot be reached: %s", e.URL, err.Error())
+	}
+
+	// Lock the repository file for concurrent goroutines or processes synchronization
+	fileLock := flock.New(c.Home.RepositoryFile())
+	lockCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	locked, err := fileLock.TryLockContext(lockCtx, time.Second)
+	if err == nil && locked {
+		defer fileLock.Unlock()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Re-read the repositories file before updating it as its content may have been changed
+	// by a concurrent execution after the first read and before being locked
+	f, err = repo.LoadRepositoriesFile(c.Home.RepositoryFile())
+	if err != nil {
+		return err
+	}
+
+	f.Update(e)
+
+	return f.WriteFile(c.Home.RepositoryFile(), 0644)
+}
+
+// RemoveRepository removes a repository from the repositories file, along
+// with its cached index.
+func (c *Configuration) RemoveRepository(name string) error {
+	repoFile := c.Home.RepositoryFile()
+	r, err := repo.LoadRepositoriesFile(repoFile)
+	if err != nil {
+		return err
+	}
+
+	if !r.Remove(name) {
+		return fmt.Errorf("no repo named %q found", name)
+	}
+	if err := r.WriteFile(repoFile, 0644); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(c.Home.CacheIndex(name)); err == nil {
+		if err := os.Remove(c.Home.CacheIndex(name)); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(repo.ShardCacheDir(c.Home.CacheIndex(name)))
+}
+
+// ChartRepositories returns the repositories of the repositories file, or
+// only the one called name if name is not empty.
+func (c *Configuration) ChartRepositories(name string) ([]*repo.ChartRepository, error) {
+	f, err := repo.LoadRepositoriesFile(c.Home.RepositoryFile())
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Repositories) == 0 {
+		return nil, ErrNoRepositories
+	}
+
+	var repos []*repo.ChartRepository
+	for _, cfg := range f.Repositories {
+		if name != "" && cfg.Name != name {
+			continue
+		}
+		r, err := repo.NewChartRepository(cfg, c.Getters)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, r)
+	}
+	if len(repos) == 0 {
+		return nil, ErrNoRepositoriesMatchingRepoName
+	}
+	return repos, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action // import "k8s.io/helm/pkg/action"
+
+import (
+	"os"
+	"testing"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/repo/repotest"
+)
+
+// newTestConfiguration starts a repository server serving the example chart
+// and returns a configuration with an empty Helm home.
+func newTestConfiguration(t *testing.T) (*Configuration, *repotest.Server) {
+	srv, home, err := repotest.NewTempServer("../repo/repotest/testdata/examplechart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{home.Repository(), home.Cache(), home.Archive()} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.NewRepoFile().WriteFile(home.RepositoryFile(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Configuration{
+		Home:    home,
+		Getters: getter.All(environment.EnvSettings{Home: home}),
+	}
+	return cfg, srv
+}
+
+func cleanupTestConfiguration(cfg *Configuration, srv *repotest.Server) {
+	srv.Stop()
+	os.RemoveAll(cfg.Home.String())
+}
+
+func TestRepositories(t *testing.T) {
+	cfg, srv := newTestConfiguration(t)
+	defer cleanupTestConfiguration(cfg, srv)
+
+	if _, err := cfg.ChartRepositories(""); err != ErrNoRepositories {
+		t.Errorf("expected %v, got %v", ErrNoRepositories, err)
+	}
+
+	if err := cfg.AddRepository(&repo.Entry{Name: "test", URL: srv.URL()}, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cfg.Home.CacheIndex("test")); err != nil {
+		t.Errorf("expected the index of the repository to be cached: %s", err)
+	}
+	if err := cfg.AddRepository(&repo.Entry{Name: "test", URL: srv.URL()}, true); err == nil {
+		t.Error("expected an error adding an existing repository with noUpdate")
+	}
+	if err := cfg.AddRepository(&repo.Entry{Name: "test", URL: srv.URL()}, false); err != nil {
+		t.Errorf("expected the repository to be updated: %s", err)
+	}
+	if err := cfg.AddRepository(&repo.Entry{Name: "broken", URL: srv.URL() + "/missing"}, false); err == nil {
+		t.Error("expected an error adding a repository without an index")
+	}
+
+	repos, err := cfg.ChartRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Config.Name != "test" {
+		t.Errorf("expected the test repository, got %v", repos)
+	}
+	if _, err := cfg.ChartRepositories("other"); err != ErrNoRepositoriesMatchingRepoName {
+		t.Errorf("expected %v, got %v", ErrNoRepositoriesMatchingRepoName, err)
+	}
+
+	if err := cfg.RemoveRepository("test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cfg.Home.CacheIndex("test")); !os.IsNotExist(err) {
+		t.Errorf("expected the cached index to be removed, got %v", err)
+	}
+	if err := cfg.RemoveRepository("test"); err == nil {
+		t.Error("expected an error removing a missing repository")
+	}
+}
+
+func TestNewConfiguration(t *testing.T) {
+	cfg := NewConfiguration(environment.EnvSettings{Home: helmpath.Home("/helm"), Debug: true})
+	if cfg.Home != "/helm" || !cfg.Debug || len(cfg.Getters) == 0 {
+		t.Errorf("unexpected configuration %+v", cfg)
+	}
+	if ns := DefaultNamespace(environment.EnvSettings{Namespace: "team"}); ns != "team" {
+		t.Errorf("expected namespace team, got %s", ns)
+	}
+}
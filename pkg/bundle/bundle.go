@@ -0,0 +1,300 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle // import "k8s.io/helm/pkg/bundle"
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/repo"
+)
+
+const (
+	// APIVersionV1 is the version of the bundle format.
+	APIVersionV1 = "v1"
+	// MetadataFile is the name of the file describing a bundle.
+	MetadataFile = "bundle.yaml"
+	// IndexFile is the name of the repository index of the charts of a bundle.
+	IndexFile = "index.yaml"
+	// ChartsDir is the directory of the chart archives of a bundle.
+	ChartsDir = "charts"
+)
+
+// Metadata describes the contents of a bundle.
+type Metadata struct {
+	APIVersion string `json:"apiVersion"`
+	// Name and Version are those of the chart.
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Chart is the path of the archive of the chart in the bundle.
+	Chart        string       `json:"chart"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+	// Images are the container images the manifests of the chart refer to.
+	Images []string `json:"images,omitempty"`
+}
+
+// Dependency is a chart the chart of a bundle depends on.
+type Dependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Chart is the path of the archive of the dependency in the bundle.
+	Chart string `json:"chart"`
+}
+
+// Bundle is a bundle loaded in memory.
+type Bundle struct {
+	Metadata *Metadata
+	files    map[string][]byte
+}
+
+// Create writes a bundle of c, its dependencies and the given images to the
+// directory dest, and returns the path of the bundle.
+//
+// The dependencies of c must have been loaded into c, as they are for a chart
+// whose dependencies are in its charts/ directory. The archives of the charts
+// are reproducible, so the same chart always yields the same digests.
+func Create(c *chart.Chart, images []string, dest string) (string, error) {
+	if c.Metadata == nil {
+		return "", errors.New("no Chart.yaml data")
+	}
+	tmp, err := ioutil.TempDir("", "helm-bundle-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	md := &Metadata{
+		APIVersion: APIVersionV1,
+		Name:       c.Metadata.Name,
+		Version:    c.Metadata.Version,
+		Images:     images,
+	}
+	index := repo.NewIndexFile()
+	files := map[string][]byte{}
+	add := func(c *chart.Chart) (string, error) {
+		filename, err := chartutil.Save(c, tmp)
+		if err != nil {
+			return "", err
+		}
+		name := path.Join(ChartsDir, filepath.Base(filename))
+		if _, ok := files[name]; ok {
+			return name, nil
+		}
+		if files[name], err = ioutil.ReadFile(filename); err != nil {
+			return "", err
+		}
+		digest, err := provenance.Digest(bytes.NewReader(files[name]))
+		if err != nil {
+			return "", err
+		}
+		index.Add(c.Metadata, name, "", digest)
+		return name, nil
+	}
+
+	if md.Chart, err = add(c); err != nil {
+		return "", err
+	}
+	for _, dep := range c.Dependencies {
+		name, err := add(dep)
+		if err != nil {
+			return "", err
+		}
+		md.Dependencies = append(md.Dependencies, Dependency{
+			Name:    dep.Metadata.Name,
+			Version: dep.Metadata.Version,
+			Chart:   name,
+		})
+	}
+	index.SortEntries()
+
+	if files[MetadataFile], err = yaml.Marshal(md); err != nil {
+		return "", err
+	}
+	if files[IndexFile], err = yaml.Marshal(index); err != nil {
+		return "", err
+	}
+
+	filename := filepath.Join(dest, fmt.Sprintf("%s-%s.bundle.tgz", md.Name, md.Version))
+	if err := writeArchive(filename, files); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// writeArchive writes files to a gzipped tar archive, bundle.yaml first and
+// then sorted by name.
+func writeArchive(filename string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if name != MetadataFile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{MetadataFile}, names...)
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	zipper := gzip.NewWriter(f)
+	tw := tar.NewWriter(zipper)
+	for _, name := range names {
+		h := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			ModTime:  time.Unix(0, 0),
+			Typeflag: tar.TypeReg,
+		}
+		if err = tw.WriteHeader(h); err != nil {
+			break
+		}
+		if _, err = tw.Write(files[name]); err != nil {
+			break
+		}
+	}
+	for _, c := range []io.Closer{tw, zipper, f} {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		os.Remove(filename)
+	}
+	return err
+}
+
+// Load reads a bundle. The digests of its chart archives are checked against
+// its index.
+func Load(filename string) (*Bundle, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a bundle: %s", filename, err)
+	}
+	defer zr.Close()
+
+	b := &Bundle{files: map[string][]byte{}}
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a bundle: %s", filename, err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(h.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("bundle %s has a file outside of it: %s", filename, h.Name)
+		}
+		if b.files[name], err = ioutil.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+
+	data, ok := b.files[MetadataFile]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a bundle: no %s", filename, MetadataFile)
+	}
+	b.Metadata = &Metadata{}
+	if err := yaml.Unmarshal(data, b.Metadata); err != nil {
+		return nil, fmt.Errorf("cannot load %s of bundle %s: %s", MetadataFile, filename, err)
+	}
+	if b.Metadata.APIVersion != APIVersionV1 {
+		return nil, fmt.Errorf("bundle %s has unsupported apiVersion %q", filename, b.Metadata.APIVersion)
+	}
+	if _, ok := b.files[b.Metadata.Chart]; !ok {
+		return nil, fmt.Errorf("bundle %s has no chart %s", filename, b.Metadata.Chart)
+	}
+	return b, b.verify()
+}
+
+// verify checks the digests of the chart archives listed in the index of the
+// bundle.
+func (b *Bundle) verify() error {
+	data, ok := b.files[IndexFile]
+	if !ok {
+		return fmt.Errorf("bundle has no %s", IndexFile)
+	}
+	var index repo.IndexFile
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("cannot load %s of bundle: %s", IndexFile, err)
+	}
+	for _, versions := range index.Entries {
+		for _, v := range versions {
+			for _, u := range v.URLs {
+				archive, ok := b.files[path.Clean(u)]
+				if !ok {
+					return fmt.Errorf("bundle has no archive %s for chart %s %s", u, v.Name, v.Version)
+				}
+				digest, err := provenance.Digest(bytes.NewReader(archive))
+				if err != nil {
+					return err
+				}
+				if digest != v.Digest {
+					return fmt.Errorf("digest of %s does not match the index of the bundle", u)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Chart loads the chart of the bundle, with its dependencies.
+func (b *Bundle) Chart() (*chart.Chart, error) {
+	return chartutil.LoadArchive(bytes.NewReader(b.files[b.Metadata.Chart]))
+}
+
+// Extract writes the files of the bundle to dir, creating it if needed.
+func (b *Bundle) Extract(dir string) error {
+	for name, data := range b.files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle // import "k8s.io/helm/pkg/bundle"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+)
+
+func TestCreateAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-bundle-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := chartutil.Load("../chartutil/testdata/frobnitz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := []string{"alpine:3.3", "nginx:1.15"}
+	filename, err := Create(c, images, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := filepath.Join(dir, "frobnitz-1.2.3.bundle.tgz"); filename != expect {
+		t.Errorf("expected bundle %s, got %s", expect, filename)
+	}
+
+	b, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := b.Metadata
+	if md.Name != "frobnitz" || md.Version != "1.2.3" || md.Chart != "charts/frobnitz-1.2.3.tgz" {
+		t.Errorf("unexpected metadata %+v", md)
+	}
+	if !reflect.DeepEqual(md.Images, images) {
+		t.Errorf("expected images %v, got %v", images, md.Images)
+	}
+	if len(md.Dependencies) != len(c.Dependencies) {
+		t.Errorf("expected %d dependencies, got %+v", len(c.Dependencies), md.Dependencies)
+	}
+
+	loaded, err := b.Chart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Metadata.Name != "frobnitz" || len(loaded.Dependencies) != len(c.Dependencies) {
+		t.Errorf("unexpected chart %s with %d dependencies", loaded.Metadata.Name, len(loaded.Dependencies))
+	}
+
+	out := filepath.Join(dir, "extracted")
+	if err := b.Extract(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{MetadataFile, IndexFile, md.Chart} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("expected %s to be extracted: %s", name, err)
+		}
+	}
+}
+
+func TestLoadChecksDigests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-bundle-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := chartutil.Load("../chartutil/testdata/frobnitz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename, err := Create(c, nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.files[b.Metadata.Chart] = []byte("tampered")
+	if err := writeArchive(filename, b.files); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil || !strings.Contains(err.Error(), "digest of charts/frobnitz-1.2.3.tgz") {
+		t.Errorf("expected a digest error, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package bundle reads and writes bundles, archives holding everything needed to
+install a chart without network access.
+
+A bundle is a gzipped tar archive. Its bundle.yaml file names the chart and
+lists the container images its manifests refer to, so that they can be
+mirrored into a registry reachable from the cluster. The archives of the chart
+and of its dependencies are stored under charts/, and described by the
+index.yaml file of a chart repository, which is also used to check their
+digests when the bundle is loaded. Extracted, a bundle can thus be served as a
+chart repository.
+*/
+package bundle // import "k8s.io/helm/pkg/bundle"
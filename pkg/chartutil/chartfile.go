@@ -22,8 +22,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
@@ -33,6 +36,60 @@ import (
 // This is ApiVersionV1 instead of APIVersionV1 to match the protobuf-generated name.
 const ApiVersionV1 = "v1" // nolint
 
+const (
+	// ChartTypeApplication is the type of a chart that can be installed. It is
+	// the type of the charts not setting one.
+	ChartTypeApplication = "application"
+	// ChartTypeLibrary is the type of a chart providing named templates and
+	// default values to the charts depending on it.
+	ChartTypeLibrary = "library"
+)
+
+// ValidateChartType returns an error if the type of a chart is not known.
+func ValidateChartType(cf *chart.Metadata) error {
+	switch cf.GetType() {
+	case "", ChartTypeApplication, ChartTypeLibrary:
+		return nil
+	}
+	return fmt.Errorf("chart type %q is not valid. The value must be %q or %q", cf.GetType(), ChartTypeApplication, ChartTypeLibrary)
+}
+
+// ValidateAnnotations returns an error if an annotation of a chart is not
+// valid. Like the annotations of Kubernetes objects, their keys are names
+// optionally prefixed with a DNS subdomain, as in 'example.com/name'.
+func ValidateAnnotations(cf *chart.Metadata) error {
+	keys := make([]string, 0, len(cf.GetAnnotations()))
+	for k := range cf.GetAnnotations() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("annotation %q is not valid: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// IsLibraryChart returns true if the chart is a library chart.
+func IsLibraryChart(c *chart.Chart) bool {
+	return c.GetMetadata().GetType() == ChartTypeLibrary
+}
+
+// IsChartInstallable returns an error if the chart cannot be installed.
+//
+// Library charts only provide templates and values to the charts depending on
+// them, they are installed as dependencies of an application chart.
+func IsChartInstallable(c *chart.Chart) error {
+	if err := ValidateChartType(c.GetMetadata()); err != nil {
+		return err
+	}
+	if IsLibraryChart(c) {
+		return fmt.Errorf("chart %s is a library chart and cannot be installed, add it to the requirements of an application chart instead", c.GetMetadata().GetName())
+	}
+	return nil
+}
+
 // UnmarshalChartfile takes raw Chart.yaml data and unmarshals it.
 func UnmarshalChartfile(data []byte) (*chart.Metadata, error) {
 	y := &chart.Metadata{}
@@ -108,6 +108,47 @@ func verifyChartfile(t *testing.T, f *chart.Metadata, name string) {
 	}
 }
 
+func TestIsChartInstallable(t *testing.T) {
+	tests := []struct {
+		typ         string
+		valid       bool
+		installable bool
+	}{
+		{"", true, true},
+		{ChartTypeApplication, true, true},
+		{ChartTypeLibrary, true, false},
+		{"plugin", false, false},
+	}
+	for _, tt := range tests {
+		c := &chart.Chart{Metadata: &chart.Metadata{Name: "foo", Type: tt.typ}}
+		if err := ValidateChartType(c.Metadata); (err == nil) != tt.valid {
+			t.Errorf("type %q: expected valid to be %t, got %v", tt.typ, tt.valid, err)
+		}
+		if err := IsChartInstallable(c); (err == nil) != tt.installable {
+			t.Errorf("type %q: expected installable to be %t, got %v", tt.typ, tt.installable, err)
+		}
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		valid       bool
+	}{
+		{nil, true},
+		{map[string]string{"category": "Database", "example.com/images": "mariadb:10.1"}, true},
+		{map[string]string{"example.com/": "x"}, false},
+		{map[string]string{"has spaces": "x"}, false},
+		{map[string]string{"-dash": "x"}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateAnnotations(&chart.Metadata{Name: "foo", Annotations: tt.annotations})
+		if (err == nil) != tt.valid {
+			t.Errorf("annotations %v: expected valid to be %t, got %v", tt.annotations, tt.valid, err)
+		}
+	}
+}
+
 func TestIsChartDir(t *testing.T) {
 	validChartDir, err := IsChartDir("testdata/frobnitz")
 	if !validChartDir {
@@ -0,0 +1,424 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+)
+
+// errCapabilitiesUnknown is returned when a condition refers to the
+// capabilities of the cluster, but they are not known yet. This is the case
+// when the Helm client processes the requirements of a chart before sending it
+// to Tiller, which evaluates the condition again.
+var errCapabilitiesUnknown = errors.New("the capabilities of the cluster are not known")
+
+// isConditionExpression returns true if the condition of a requirement is an
+// expression rather than a list of value paths.
+func isConditionExpression(cond string) bool {
+	return strings.ContainsAny(cond, "=!<>()&|'\"")
+}
+
+// evalCondition evaluates the expression of a requirement condition.
+//
+// The expressions are made of:
+//   - value paths, such as 'subchart1.enabled' or '.Values.subchart1.enabled'
+//   - '.Capabilities.KubeVersion', and its 'Major', 'Minor' and 'GitVersion' fields
+//   - strings in single or double quotes, numbers, true, false and null
+//   - the comparisons ==, !=, <, <=, > and >=
+//   - the operators !, && and ||, and parentheses
+//   - semverCompare(constraint, version), true if the version satisfies the
+//     SemVer constraint
+//
+// A value path missing from the values is null. In a condition, false, null,
+// zero and the empty string are false, everything else is true.
+func evalCondition(cond string, cvals Values, caps *Capabilities) (bool, error) {
+	tokens, err := tokenizeCondition(cond)
+	if err != nil {
+		return false, err
+	}
+	p := &conditionParser{tokens: tokens, vals: cvals, caps: caps}
+	v, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if !p.done() {
+		return false, fmt.Errorf("unexpected %q in condition %q", p.peek().text, cond)
+	}
+	return truthy(v), nil
+}
+
+type conditionTokenKind int
+
+const (
+	tokenPath conditionTokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type conditionToken struct {
+	kind conditionTokenKind
+	text string
+}
+
+var conditionOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", ","}
+
+func tokenizeCondition(cond string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	rs := []rune(cond)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				j++
+			}
+			if j == len(rs) {
+				return nil, fmt.Errorf("unterminated string in condition %q", cond)
+			}
+			tokens = append(tokens, conditionToken{tokenString, string(rs[i+1 : j])})
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, conditionToken{tokenNumber, string(rs[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_' || r == '.':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || strings.ContainsRune("_.-", rs[j])) {
+				j++
+			}
+			tokens = append(tokens, conditionToken{tokenPath, string(rs[i:j])})
+			i = j
+		default:
+			op := ""
+			for _, o := range conditionOperators {
+				if strings.HasPrefix(string(rs[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q in condition %q", r, cond)
+			}
+			tokens = append(tokens, conditionToken{tokenOperator, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// conditionParser evaluates the tokens of a condition while parsing them.
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	vals   Values
+	caps   *Capabilities
+}
+
+func (p *conditionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *conditionParser) peek() conditionToken {
+	if p.done() {
+		return conditionToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is one of the given operators.
+func (p *conditionParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if p.done() || t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *conditionParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		if p.done() {
+			return fmt.Errorf("expected %q at the end of the condition", op)
+		}
+		return fmt.Errorf("expected %q, got %q", op, p.peek().text)
+	}
+	return nil
+}
+
+func (p *conditionParser) or() (interface{}, error) {
+	v, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return v, nil
+		}
+		w, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		v = truthy(v) || truthy(w)
+	}
+}
+
+func (p *conditionParser) and() (interface{}, error) {
+	v, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return v, nil
+		}
+		w, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		v = truthy(v) && truthy(w)
+	}
+}
+
+func (p *conditionParser) unary() (interface{}, error) {
+	if _, ok := p.accept("!"); ok {
+		v, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(v), nil
+	}
+	return p.comparison()
+}
+
+func (p *conditionParser) comparison() (interface{}, error) {
+	v, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return v, nil
+	}
+	w, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return compareValues(op, v, w)
+}
+
+func (p *conditionParser) operand() (interface{}, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of the condition")
+	}
+	if _, ok := p.accept("("); ok {
+		v, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return v, p.expect(")")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenString:
+		return t.text, nil
+	case tokenNumber:
+		return strconv.ParseFloat(t.text, 64)
+	case tokenPath:
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null", "nil":
+			return nil, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.call(t.text)
+		}
+		return p.lookup(t.text)
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// call evaluates a function call, after its opening parenthesis.
+func (p *conditionParser) call(name string) (interface{}, error) {
+	var args []interface{}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			v, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch name {
+	case "semverCompare":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("semverCompare takes a constraint and a version, got %d arguments", len(args))
+		}
+		constraint, err := semver.NewConstraint(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %s", args[0], err)
+		}
+		if args[1] == nil {
+			return false, nil
+		}
+		v, err := semver.NewVersion(fmt.Sprint(args[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %s", args[1], err)
+		}
+		return constraint.Check(v), nil
+	}
+	return nil, fmt.Errorf("unknown function %q", name)
+}
+
+// lookup returns the value at a path, null if it is missing.
+func (p *conditionParser) lookup(path string) (interface{}, error) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "Capabilities" || strings.HasPrefix(path, "Capabilities.") {
+		return p.capability(path)
+	}
+	path = strings.TrimPrefix(path, "Values.")
+	v, _ := lookupPath(p.vals, path)
+	return v, nil
+}
+
+func (p *conditionParser) capability(path string) (interface{}, error) {
+	if p.caps == nil || p.caps.KubeVersion == nil {
+		return nil, errCapabilitiesUnknown
+	}
+	kv := p.caps.KubeVersion
+	switch path {
+	case "Capabilities.KubeVersion", "Capabilities.KubeVersion.GitVersion":
+		return kv.GitVersion, nil
+	case "Capabilities.KubeVersion.Major":
+		return kv.Major, nil
+	case "Capabilities.KubeVersion.Minor":
+		return kv.Minor, nil
+	}
+	return nil, fmt.Errorf("unknown capability %q", path)
+}
+
+// truthy returns the truth value of a condition value.
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case map[string]interface{}:
+		return len(t) > 0
+	case []interface{}:
+		return len(t) > 0
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func compareValues(op string, a, b interface{}) (interface{}, error) {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch op {
+			case "==":
+				return fa == fb, nil
+			case "!=":
+				return fa != fb, nil
+			case "<":
+				return fa < fb, nil
+			case "<=":
+				return fa <= fb, nil
+			case ">":
+				return fa > fb, nil
+			case ">=":
+				return fa >= fb, nil
+			}
+		}
+	}
+	sa, aok := a.(string)
+	sb, bok := b.(string)
+	if aok && bok {
+		switch op {
+		case "==":
+			return sa == sb, nil
+		case "!=":
+			return sa != sb, nil
+		case "<":
+			return sa < sb, nil
+		case "<=":
+			return sa <= sb, nil
+		case ">":
+			return sa > sb, nil
+		case ">=":
+			return sa >= sb, nil
+		}
+	}
+	switch op {
+	case "==":
+		return fmt.Sprint(a) == fmt.Sprint(b) && (a == nil) == (b == nil), nil
+	case "!=":
+		return fmt.Sprint(a) != fmt.Sprint(b) || (a == nil) != (b == nil), nil
+	}
+	return nil, fmt.Errorf("cannot compare %v and %v with %s", a, b, op)
+}
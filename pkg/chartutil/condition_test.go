@@ -0,0 +1,143 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"k8s.io/apimachinery/pkg/version"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestEvalCondition(t *testing.T) {
+	vals, err := ReadValues([]byte(`
+frontend:
+  enabled: true
+backend:
+  enabled: false
+replicas: 3
+env: production
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps := &Capabilities{KubeVersion: &version.Info{Major: "1", Minor: "14", GitVersion: "v1.14.2"}}
+
+	tests := []struct {
+		cond   string
+		expect bool
+		err    bool
+	}{
+		{cond: "frontend.enabled && !backend.enabled", expect: true},
+		{cond: "frontend.enabled && backend.enabled", expect: false},
+		{cond: "backend.enabled || .Values.frontend.enabled", expect: true},
+		{cond: "replicas > 1 && env == 'production'", expect: true},
+		{cond: `replicas <= 2 || env != "production"`, expect: false},
+		{cond: "!(replicas == 3)", expect: false},
+		{cond: "missing.enabled == null", expect: true},
+		{cond: "!missing.enabled", expect: true},
+		{cond: "semverCompare('>=1.14.0', .Capabilities.KubeVersion)", expect: true},
+		{cond: "semverCompare('<1.14.0', .Capabilities.KubeVersion.GitVersion) || .Capabilities.KubeVersion.Minor == '14'", expect: true},
+		{cond: "semverCompare('>=1.16.0', .Capabilities.KubeVersion) && frontend.enabled", expect: false},
+		{cond: "frontend.enabled &&", err: true},
+		{cond: "(frontend.enabled", err: true},
+		{cond: "env == 'production", err: true},
+		{cond: "replicas > 'three'", err: true},
+		{cond: "unknown(replicas)", err: true},
+		{cond: "semverCompare('not a constraint', .Capabilities.KubeVersion)", err: true},
+	}
+	for _, tt := range tests {
+		got, err := evalCondition(tt.cond, vals, caps)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.cond)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tt.cond, err)
+			continue
+		}
+		if got != tt.expect {
+			t.Errorf("%q: expected %t, got %t", tt.cond, tt.expect, got)
+		}
+	}
+
+	if _, err := evalCondition("semverCompare('>=1.14.0', .Capabilities.KubeVersion)", vals, nil); err != errCapabilitiesUnknown {
+		t.Errorf("Expected %q without capabilities, got %v", errCapabilitiesUnknown, err)
+	}
+}
+
+func TestProcessRequirementsEnabledCaps(t *testing.T) {
+	reqs := `dependencies:
+- name: modern
+  version: 0.1.0
+  repository: http://localhost:10191
+  condition: semverCompare(">=1.14.0", .Capabilities.KubeVersion)
+- name: legacy
+  version: 0.1.0
+  repository: http://localhost:10191
+  condition: semverCompare("<1.14.0", .Capabilities.KubeVersion) && legacy.enabled
+`
+	newChart := func() *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: "parent"},
+			Values:   &chart.Config{Raw: "legacy:\n  enabled: true\n"},
+			Files:    []*any.Any{{TypeUrl: "requirements.yaml", Value: []byte(reqs)}},
+			Dependencies: []*chart.Chart{
+				{Metadata: &chart.Metadata{Name: "modern", Version: "0.1.0"}},
+				{Metadata: &chart.Metadata{Name: "legacy", Version: "0.1.0"}},
+			},
+		}
+	}
+
+	// without capabilities, the conditions are left to Tiller
+	c := newChart()
+	if err := ProcessRequirementsEnabled(c, &chart.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyNames(c); len(got) != 2 {
+		t.Errorf("Expected both dependencies to be kept without capabilities, got %v", got)
+	}
+
+	caps := &Capabilities{KubeVersion: &version.Info{Major: "1", Minor: "14", GitVersion: "v1.14.2"}}
+	if err := ProcessRequirementsEnabledCaps(c, &chart.Config{}, caps); err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyNames(c); len(got) != 1 || got[0] != "modern" {
+		t.Errorf("Expected only the modern dependency on Kubernetes 1.14, got %v", got)
+	}
+
+	caps.KubeVersion = &version.Info{Major: "1", Minor: "13", GitVersion: "v1.13.5"}
+	c = newChart()
+	if err := ProcessRequirementsEnabledCaps(c, &chart.Config{}, caps); err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyNames(c); len(got) != 1 || got[0] != "legacy" {
+		t.Errorf("Expected only the legacy dependency on Kubernetes 1.13, got %v", got)
+	}
+}
+
+func dependencyNames(c *chart.Chart) []string {
+	var names []string
+	for _, d := range c.Dependencies {
+		names = append(names, d.Metadata.Name)
+	}
+	return names
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// CRDsDir is the directory of a chart holding the custom resource definitions
+// that are installed before the templates are rendered. The files are not
+// templates, they are installed as they are.
+const CRDsDir = "crds"
+
+// CRD is a file of custom resource definitions of a chart.
+type CRD struct {
+	// Name is the path of the file, starting with the name of the chart,
+	// such as "mychart/crds/crontab.yaml" or "mychart/charts/sub/crds/crontab.yaml".
+	Name string
+	Data []byte
+}
+
+// CRDs returns the YAML and JSON files of the crds directory of the chart and
+// of its dependencies, the files of the chart first, in the order of their
+// names.
+func CRDs(c *chart.Chart) []CRD {
+	return crds(c, c.GetMetadata().GetName())
+}
+
+func crds(c *chart.Chart, prefix string) []CRD {
+	var found []CRD
+	for _, f := range c.Files {
+		if !strings.HasPrefix(f.TypeUrl, CRDsDir+"/") {
+			continue
+		}
+		switch path.Ext(f.TypeUrl) {
+		case ".yaml", ".yml", ".json":
+			found = append(found, CRD{Name: path.Join(prefix, f.TypeUrl), Data: f.Value})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+
+	for _, dep := range c.Dependencies {
+		found = append(found, crds(dep, path.Join(prefix, "charts", dep.GetMetadata().GetName()))...)
+	}
+	return found
+}
@@ -0,0 +1,55 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestCRDs(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "parent"},
+		Files: []*any.Any{
+			{TypeUrl: "crds/b.yaml", Value: []byte("b")},
+			{TypeUrl: "crds/a.json", Value: []byte("a")},
+			{TypeUrl: "crds/notes.txt", Value: []byte("not a CRD")},
+			{TypeUrl: "files/crds/c.yaml", Value: []byte("not in crds/")},
+		},
+		Dependencies: []*chart.Chart{{
+			Metadata: &chart.Metadata{Name: "child"},
+			Files:    []*any.Any{{TypeUrl: "crds/c.yml", Value: []byte("c")}},
+		}},
+	}
+
+	crds := CRDs(c)
+	expect := []string{"parent/crds/a.json", "parent/crds/b.yaml", "parent/charts/child/crds/c.yml"}
+	if len(crds) != len(expect) {
+		t.Fatalf("Expected %d CRD files, got %v", len(expect), crds)
+	}
+	for i, name := range expect {
+		if crds[i].Name != name {
+			t.Errorf("Expected CRD file %d to be %s, got %s", i, name, crds[i].Name)
+		}
+	}
+	if string(crds[2].Data) != "c" {
+		t.Errorf("Unexpected data %q", crds[2].Data)
+	}
+}
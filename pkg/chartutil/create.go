@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
@@ -46,6 +47,8 @@ const (
 	ServiceAccountName = "serviceaccount.yaml"
 	// NotesName is the name of the example NOTES.txt file.
 	NotesName = "NOTES.txt"
+	// OutputsName is the name of the template rendering the outputs of a release.
+	OutputsName = "outputs.yaml"
 	// HelpersName is the name of the example helpers file.
 	HelpersName = "_helpers.tpl"
 	// TemplatesTestsDir is the relative directory name for templates tests.
@@ -126,7 +129,8 @@ affinity: {}
 
 const defaultIgnore = `# Patterns to ignore when building packages.
 # This supports shell glob matching, relative path matching, and
-# negation (prefixed with !). Only one pattern per line.
+# negation (prefixed with !). Only one pattern per line, and the last
+# matching pattern wins.
 .DS_Store
 # Common VCS dirs
 .git/
@@ -146,6 +150,8 @@ const defaultIgnore = `# Patterns to ignore when building packages.
 .idea/
 *.tmproj
 .vscode/
+# Golden files of 'helm snapshot'
+snapshots/
 `
 
 const defaultIngress = `{{- if .Values.ingress.enabled -}}
@@ -373,24 +379,55 @@ spec:
 
 // CreateFrom creates a new chart, but scaffolds it from the src chart.
 func CreateFrom(chartfile *chart.Metadata, dest string, src string) error {
+	return CreateFromStarter(chartfile, dest, src, nil)
+}
+
+// CreateFromStarter creates a new chart from the src starter, replacing the
+// variables the starter declares in its starter.yaml with the given values or
+// their defaults. See Starterfile.
+func CreateFromStarter(chartfile *chart.Metadata, dest string, src string, vars map[string]string) error {
 	schart, err := Load(src)
 	if err != nil {
 		return fmt.Errorf("could not load %s: %s", src, err)
 	}
 
+	sf, err := LoadStarterfile(schart)
+	if err != nil {
+		return err
+	}
+	repl, err := sf.Replacements(vars)
+	if err != nil {
+		return err
+	}
+	repl["CHARTNAME"] = chartfile.Name
+	var pairs []string
+	for name, val := range repl {
+		pairs = append(pairs, "<"+name+">", val)
+	}
+	transform := strings.NewReplacer(pairs...).Replace
+
 	schart.Metadata = chartfile
 
 	var updatedTemplates []*chart.Template
 
 	for _, template := range schart.Templates {
-		newData := Transform(string(template.Data), "<CHARTNAME>", schart.Metadata.Name)
-		updatedTemplates = append(updatedTemplates, &chart.Template{Name: template.Name, Data: newData})
+		newData := transform(string(template.Data))
+		updatedTemplates = append(updatedTemplates, &chart.Template{Name: template.Name, Data: []byte(newData)})
 	}
 
 	schart.Templates = updatedTemplates
 	if schart.Values != nil {
-		schart.Values = &chart.Config{Raw: string(Transform(schart.Values.Raw, "<CHARTNAME>", schart.Metadata.Name))}
+		schart.Values = &chart.Config{Raw: transform(schart.Values.Raw)}
+	}
+
+	// The variables are for the starter, not for the new chart.
+	files := schart.Files[:0]
+	for _, f := range schart.Files {
+		if f.TypeUrl != StarterfileName {
+			files = append(files, f)
+		}
 	}
+	schart.Files = files
 	return SaveDir(schart, dest)
 }
 
@@ -0,0 +1,145 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// DecryptionProvider decrypts values files encrypted with a tool such as
+// SOPS, so that secret values can be kept encrypted in version control and
+// only be decrypted when the values are loaded.
+type DecryptionProvider interface {
+	// Name is the name of the provider, used in messages.
+	Name() string
+	// Encrypted tells whether the data of a values file is encrypted in the
+	// format of the provider.
+	Encrypted(data []byte) bool
+	// Decrypt returns the plain data of a values file. The name of the file
+	// helps to tell the format of the data, such as YAML or JSON.
+	Decrypt(name string, data []byte) ([]byte, error)
+}
+
+// DecryptionProviders is a list of decryption providers.
+type DecryptionProviders []DecryptionProvider
+
+// DefaultDecryptionProviders returns the decryption providers built into
+// Helm.
+func DefaultDecryptionProviders() DecryptionProviders {
+	return DecryptionProviders{&SOPSProvider{}}
+}
+
+// For returns the first provider the data is encrypted for, or nil if the
+// data is not encrypted.
+func (p DecryptionProviders) For(data []byte) DecryptionProvider {
+	for _, provider := range p {
+		if provider.Encrypted(data) {
+			return provider
+		}
+	}
+	return nil
+}
+
+// Decrypt decrypts the data of a values file with the provider it is
+// encrypted for. Data that is not encrypted is returned as is.
+func (p DecryptionProviders) Decrypt(name string, data []byte) ([]byte, error) {
+	provider := p.For(data)
+	if provider == nil {
+		return data, nil
+	}
+	plain, err := provider.Decrypt(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s with %s: %s", name, provider.Name(), err)
+	}
+	return plain, nil
+}
+
+// SOPSProvider decrypts the YAML and JSON files encrypted with SOPS, with
+// any of its keys: age, PGP or a cloud KMS. It runs the sops command, which
+// finds the keys the way it does on its own.
+type SOPSProvider struct {
+	// Command is the sops executable. It defaults to "sops", looked up in
+	// the PATH.
+	Command string
+}
+
+// Name returns "sops".
+func (p *SOPSProvider) Name() string {
+	return "sops"
+}
+
+// Encrypted tells whether the data is a document with the metadata SOPS
+// adds to the files it encrypts.
+func (p *SOPSProvider) Encrypted(data []byte) bool {
+	if !bytes.Contains(data, []byte("sops")) {
+		return false
+	}
+	var doc struct {
+		SOPS map[string]interface{} `json:"sops"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	_, ok := doc.SOPS["mac"]
+	return ok
+}
+
+// Decrypt runs sops to decrypt the data. The encrypted data is written to a
+// temporary file for sops to read, the decrypted data is only kept in memory.
+func (p *SOPSProvider) Decrypt(name string, data []byte) ([]byte, error) {
+	inputType := "yaml"
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		inputType = "json"
+	}
+
+	f, err := ioutil.TempFile("", "helm-sops-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	command := p.Command
+	if command == "" {
+		command = "sops"
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(command, "--decrypt", "--input-type", inputType, "--output-type", "yaml", f.Name())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
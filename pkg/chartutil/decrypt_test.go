@@ -0,0 +1,128 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const sopsEncrypted = `image:
+    tag: ENC[AES256_GCM,data:cWzH,iv:Ovq7,tag:n5Q4,type:str]
+sops:
+    age:
+    -   recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+    lastmodified: '2019-06-01T00:00:00Z'
+    mac: ENC[AES256_GCM,data:Z3Vl,iv:tZ3c,tag:1K7x,type:str]
+    version: 3.3.1
+`
+
+func TestSOPSProviderEncrypted(t *testing.T) {
+	p := &SOPSProvider{}
+	tests := map[string]bool{
+		sopsEncrypted: true,
+		`{"image": {"tag": "ENC[...]"}, "sops": {"mac": "ENC[...]"}}`: true,
+		"image:\n  tag: 1.0\n":     false,
+		"sops:\n  enabled: true\n": false,
+		"sops: true\n":             false,
+		"not: [valid":              false,
+	}
+	for data, expect := range tests {
+		if got := p.Encrypted([]byte(data)); got != expect {
+			t.Errorf("Expected %t for %q, got %t", expect, data, got)
+		}
+	}
+}
+
+type fakeDecryptionProvider struct{}
+
+func (fakeDecryptionProvider) Name() string { return "fake" }
+
+func (fakeDecryptionProvider) Encrypted(data []byte) bool {
+	return strings.HasPrefix(string(data), "fake:")
+}
+
+func (fakeDecryptionProvider) Decrypt(name string, data []byte) ([]byte, error) {
+	return []byte(strings.ToUpper(strings.TrimPrefix(string(data), "fake:"))), nil
+}
+
+func TestDecryptionProviders(t *testing.T) {
+	p := DecryptionProviders{fakeDecryptionProvider{}}
+
+	out, err := p.Decrypt("values.yaml", []byte("fake:secret: value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "SECRET: VALUE" {
+		t.Errorf("Expected the decrypted data, got %q", out)
+	}
+
+	out, err = p.Decrypt("values.yaml", []byte("plain: value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "plain: value" {
+		t.Errorf("Expected the plain data as is, got %q", out)
+	}
+}
+
+func TestSOPSProviderDecrypt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake sops command is a shell script")
+	}
+	tmp, err := ioutil.TempDir("", "helm-sops-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// The fake sops prints its options and the file it is given, and fails
+	// on JSON.
+	sops := filepath.Join(tmp, "sops")
+	script := `#!/bin/sh
+if [ "$3" = json ]; then
+  echo "Error: no key could decrypt the data" >&2
+  exit 128
+fi
+echo "args: $1 $2 $3 $4 $5"
+cat "$6"
+`
+	if err := ioutil.WriteFile(sops, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := DecryptionProviders{&SOPSProvider{Command: sops}}
+
+	out, err := p.Decrypt("secrets.enc.yaml", []byte(sopsEncrypted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "args: --decrypt --input-type yaml --output-type yaml\n" + sopsEncrypted; string(out) != expect {
+		t.Errorf("Expected %q, got %q", expect, out)
+	}
+
+	_, err = p.Decrypt("secrets.enc.json", []byte(sopsEncrypted))
+	if err == nil {
+		t.Fatal("Expected sops to fail")
+	}
+	if expect := "failed to decrypt secrets.enc.json with sops: exit status 128: Error: no key could decrypt the data"; err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+}
@@ -16,6 +16,7 @@ limitations under the License.
 package chartutil
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/any"
@@ -167,6 +168,26 @@ one:
 	}
 }
 
+func TestFromYamlArray(t *testing.T) {
+	doc := `
+- one
+- two: three
+`
+	list := FromYamlArray(doc)
+	if len(list) != 2 {
+		t.Fatalf("expected two elements, got %v", list)
+	}
+	if list[0].(string) != "one" {
+		t.Errorf("Expected \"one\", got %v", list[0])
+	}
+
+	// This should fail because it is a map, not a list:
+	list = FromYamlArray("hello: world")
+	if len(list) != 1 || !strings.Contains(list[0].(string), "cannot unmarshal") {
+		t.Fatalf("Expected parser error, got %v", list)
+	}
+}
+
 func TestToJson(t *testing.T) {
 	expect := `{"foo":"bar"}`
 	v := struct {
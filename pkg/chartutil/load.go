@@ -18,6 +18,8 @@ package chartutil
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"errors"
@@ -67,8 +69,31 @@ type BufferedFile struct {
 
 var drivePathPattern = regexp.MustCompile(`^[a-zA-Z]:/`)
 
-// loadArchiveFiles loads files out of an archive
+// zipMagic starts every zip archive that contains files.
+var zipMagic = []byte("PK\x03\x04")
+
+// IsArchive tests whether the given file name is the one of a chart archive,
+// that is a gzipped tar archive (.tgz) or a zip archive (.zip).
+func IsArchive(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tgz", ".zip":
+		return true
+	}
+	return false
+}
+
+// loadArchiveFiles loads files out of an archive. The format of the archive,
+// a gzipped tar archive or a zip archive, is detected from its contents.
 func loadArchiveFiles(in io.Reader) ([]*BufferedFile, error) {
+	br := bufio.NewReader(in)
+	if magic, _ := br.Peek(len(zipMagic)); bytes.Equal(magic, zipMagic) {
+		return loadZipFiles(br)
+	}
+	return loadTarFiles(br)
+}
+
+// loadTarFiles loads files out of a gzipped tar archive.
+func loadTarFiles(in io.Reader) ([]*BufferedFile, error) {
 	unzipped, err := gzip.NewReader(in)
 	if err != nil {
 		return nil, err
@@ -99,49 +124,60 @@ func loadArchiveFiles(in io.Reader) ([]*BufferedFile, error) {
 			continue
 		}
 
-		// Archive could contain \ if generated on Windows
-		delimiter := "/"
-		if strings.ContainsRune(hd.Name, '\\') {
-			delimiter = "\\"
+		n, err := archiveFileName(hd.Name)
+		if err != nil {
+			return nil, err
 		}
 
-		parts := strings.Split(hd.Name, delimiter)
-		n := strings.Join(parts[1:], delimiter)
+		if _, err := io.Copy(b, tr); err != nil {
+			return files, err
+		}
 
-		// Normalize the path to the / delimiter
-		n = strings.Replace(n, delimiter, "/", -1)
+		files = append(files, &BufferedFile{Name: n, Data: b.Bytes()})
+		b.Reset()
+	}
 
-		if path.IsAbs(n) {
-			return nil, errors.New("chart illegally contains absolute paths")
-		}
+	if len(files) == 0 {
+		return nil, errors.New("no files in chart archive")
+	}
+	return files, nil
+}
 
-		n = path.Clean(n)
-		if n == "." {
-			// In this case, the original path was relative when it should have been absolute.
-			return nil, errors.New("chart illegally contains empty path")
-		}
-		if strings.HasPrefix(n, "..") {
-			return nil, errors.New("chart illegally references parent directory")
-		}
+// loadZipFiles loads files out of a zip archive.
+func loadZipFiles(in io.Reader) ([]*BufferedFile, error) {
+	// The directory of a zip archive is at its end, so the whole archive is
+	// read first.
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
 
-		// In some particularly arcane acts of path creativity, it is possible to intermix
-		// UNIX and Windows style paths in such a way that you produce a result of the form
-		// c:/foo even after all the built-in absolute path checks. So we explicitly check
-		// for this condition.
-		if drivePathPattern.MatchString(n) {
-			return nil, errors.New("chart contains illegally named files")
+	files := []*BufferedFile{}
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
 		}
 
-		if parts[0] == "Chart.yaml" {
-			return nil, errors.New("chart yaml not in base directory")
+		n, err := archiveFileName(zf.Name)
+		if err != nil {
+			return nil, err
 		}
 
-		if _, err := io.Copy(b, tr); err != nil {
+		r, err := zf.Open()
+		if err != nil {
+			return files, err
+		}
+		body, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
 			return files, err
 		}
 
-		files = append(files, &BufferedFile{Name: n, Data: b.Bytes()})
-		b.Reset()
+		files = append(files, &BufferedFile{Name: n, Data: body})
 	}
 
 	if len(files) == 0 {
@@ -150,7 +186,51 @@ func loadArchiveFiles(in io.Reader) ([]*BufferedFile, error) {
 	return files, nil
 }
 
-// LoadArchive loads from a reader containing a compressed tar archive.
+// archiveFileName returns the name of a file of a chart archive relative to
+// the top directory of the archive, the one of the chart. It fails if the
+// file is not in that directory.
+func archiveFileName(name string) (string, error) {
+	// Archive could contain \ if generated on Windows
+	delimiter := "/"
+	if strings.ContainsRune(name, '\\') {
+		delimiter = "\\"
+	}
+
+	parts := strings.Split(name, delimiter)
+	n := strings.Join(parts[1:], delimiter)
+
+	// Normalize the path to the / delimiter
+	n = strings.Replace(n, delimiter, "/", -1)
+
+	if path.IsAbs(n) {
+		return "", errors.New("chart illegally contains absolute paths")
+	}
+
+	n = path.Clean(n)
+	if n == "." {
+		// In this case, the original path was relative when it should have been absolute.
+		return "", errors.New("chart illegally contains empty path")
+	}
+	if strings.HasPrefix(n, "..") {
+		return "", errors.New("chart illegally references parent directory")
+	}
+
+	// In some particularly arcane acts of path creativity, it is possible to intermix
+	// UNIX and Windows style paths in such a way that you produce a result of the form
+	// c:/foo even after all the built-in absolute path checks. So we explicitly check
+	// for this condition.
+	if drivePathPattern.MatchString(n) {
+		return "", errors.New("chart contains illegally named files")
+	}
+
+	if parts[0] == "Chart.yaml" {
+		return "", errors.New("chart yaml not in base directory")
+	}
+	return n, nil
+}
+
+// LoadArchive loads from a reader containing a compressed tar archive or a zip
+// archive.
 func LoadArchive(in io.Reader) (*chart.Chart, error) {
 	files, err := loadArchiveFiles(in)
 	if err != nil {
@@ -160,9 +240,15 @@ func LoadArchive(in io.Reader) (*chart.Chart, error) {
 }
 
 // LoadFiles loads from in-memory files.
+//
+// The dependencies declared in the 'dependencies' block of Chart.yaml are
+// loaded as a requirements.yaml file, so that they are handled like the ones of
+// the charts written for older versions of Helm. A chart cannot declare its
+// dependencies in both files.
 func LoadFiles(files []*BufferedFile) (*chart.Chart, error) {
 	c := &chart.Chart{}
 	subcharts := map[string][]*BufferedFile{}
+	var requirements []byte
 
 	for _, f := range files {
 		if f.Name == "Chart.yaml" {
@@ -175,6 +261,9 @@ func LoadFiles(files []*BufferedFile) (*chart.Chart, error) {
 			if apiVersion != "" && apiVersion != ApiVersionV1 {
 				return c, fmt.Errorf("apiVersion '%s' is not valid. The value must be \"v1\"", apiVersion)
 			}
+			if requirements, err = chartfileRequirements(f.Data); err != nil {
+				return c, fmt.Errorf("invalid dependencies in Chart.yaml: %s", err)
+			}
 		} else if f.Name == "values.toml" {
 			return c, errors.New("values.toml is illegal as of 2.0.0-alpha.2")
 		} else if f.Name == "values.yaml" {
@@ -206,18 +295,26 @@ func LoadFiles(files []*BufferedFile) (*chart.Chart, error) {
 	if c.Metadata.Name == "" {
 		return c, errors.New("invalid chart (Chart.yaml): name must not be empty")
 	}
+	if requirements != nil {
+		for _, f := range c.Files {
+			if f.TypeUrl == requirementsName {
+				return c, errors.New("dependencies are declared in both Chart.yaml and " + requirementsName)
+			}
+		}
+		c.Files = append(c.Files, &any.Any{TypeUrl: requirementsName, Value: requirements})
+	}
 
 	for n, files := range subcharts {
 		var sc *chart.Chart
 		var err error
 		if strings.IndexAny(n, "_.") == 0 {
 			continue
-		} else if filepath.Ext(n) == ".tgz" {
+		} else if IsArchive(n) {
 			file := files[0]
 			if file.Name != n {
 				return c, fmt.Errorf("error unpacking tar in %s: expected %s, got %s", c.Metadata.Name, n, file.Name)
 			}
-			// Untar the chart and add to c.Dependencies
+			// Unpack the chart and add to c.Dependencies
 			b := bytes.NewBuffer(file.Data)
 			sc, err = LoadArchive(b)
 		} else {
@@ -262,6 +359,22 @@ func LoadFile(name string) (*chart.Chart, error) {
 	return LoadArchive(raw)
 }
 
+// LoadIgnoreRules reads the ignore rules of a chart directory from its
+// .helmignore file, if it has one, and adds the default rules.
+func LoadIgnoreRules(dir string) (*ignore.Rules, error) {
+	rules := ignore.Empty()
+	ifile := filepath.Join(dir, ignore.HelmIgnore)
+	if _, err := os.Stat(ifile); err == nil {
+		r, err := ignore.ParseFile(ifile)
+		if err != nil {
+			return nil, err
+		}
+		rules = r
+	}
+	rules.AddDefaults()
+	return rules, nil
+}
+
 // LoadDir loads from a directory.
 //
 // This loads charts only from directories.
@@ -274,16 +387,10 @@ func LoadDir(dir string) (*chart.Chart, error) {
 	// Just used for errors.
 	c := &chart.Chart{}
 
-	rules := ignore.Empty()
-	ifile := filepath.Join(topdir, ignore.HelmIgnore)
-	if _, err := os.Stat(ifile); err == nil {
-		r, err := ignore.ParseFile(ifile)
-		if err != nil {
-			return c, err
-		}
-		rules = r
+	rules, err := LoadIgnoreRules(topdir)
+	if err != nil {
+		return c, err
 	}
-	rules.AddDefaults()
 
 	files := []*BufferedFile{}
 	topdir += string(filepath.Separator)
@@ -61,6 +61,25 @@ func TestLoadFile(t *testing.T) {
 	verifyRequirements(t, c)
 }
 
+func TestIsArchive(t *testing.T) {
+	tests := map[string]bool{
+		"foo.tgz":           true,
+		"foo/bar/baz.tgz":   true,
+		"foo-1.2.3.4.5.tgz": true,
+		"foo-1.2.3.zip":     true,
+		"FOO.ZIP":           true,
+		"foo.tar.gz":        false, // for our purposes
+		"foo.tgz.1":         false,
+		"footgz":            false,
+	}
+
+	for src, expect := range tests {
+		if IsArchive(src) != expect {
+			t.Errorf("%q should be %t", src, expect)
+		}
+	}
+}
+
 func TestLoadArchive_InvalidArchive(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "helm-test-")
 	if err != nil {
@@ -229,6 +248,41 @@ icon: https://example.com/64x64.png
 	}
 }
 
+func TestLoadFilesChartfileDependencies(t *testing.T) {
+	chartfile := &BufferedFile{
+		Name: ChartfileName,
+		Data: []byte(`apiVersion: v1
+name: frobnitz
+version: 1.2.3
+dependencies:
+  - name: alpine
+    version: ">=0.1.0"
+    repository: https://example.com/charts
+    condition: alpine.enabled
+`),
+	}
+
+	c, err := LoadFiles([]*BufferedFile{chartfile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs, err := LoadRequirements(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs.Dependencies) != 1 {
+		t.Fatalf("expected 1 dependency, got %d", len(reqs.Dependencies))
+	}
+	if d := reqs.Dependencies[0]; d.Name != "alpine" || d.Version != ">=0.1.0" || d.Condition != "alpine.enabled" {
+		t.Errorf("unexpected dependency %+v", d)
+	}
+
+	_, err = LoadFiles([]*BufferedFile{chartfile, {Name: "requirements.yaml", Data: []byte("dependencies: []\n")}})
+	if err == nil || !strings.Contains(err.Error(), "both Chart.yaml and requirements.yaml") {
+		t.Errorf("expected an error for dependencies declared twice, got %v", err)
+	}
+}
+
 // Packaging the chart on a Windows machine will produce an
 // archive that has \\ as delimiters. Test that we support these archives
 func TestLoadFileBackslash(t *testing.T) {
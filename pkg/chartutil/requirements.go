@@ -18,6 +18,8 @@ package chartutil
 import (
 	"errors"
 	"log"
+	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -52,6 +54,12 @@ type Dependency struct {
 	// A lock file will always produce a single version, while a dependency
 	// may contain a semantic version range.
 	Version string `json:"version,omitempty"`
+	// Digest is the SHA256 digest of the chart archive, in the form
+	// 'sha256:<hex>'.
+	//
+	// When it is set, the dependency only resolves to the version of the chart
+	// with this digest, and the downloaded archive is checked against it.
+	Digest string `json:"digest,omitempty"`
 	// The URL to the repository.
 	//
 	// Appending `index.yaml` to this string should result in a URL that can be
@@ -66,6 +74,10 @@ type Dependency struct {
 	// ImportValues holds the mapping of source values to parent key to be imported. Each item can be a
 	// string or pair of child/parent sublist items.
 	ImportValues []interface{} `json:"import-values,omitempty"`
+	// ExportValues holds the mapping of parent values to be copied into the values of this chart.
+	// Each item can be a string, the path copied to the same path in the chart, or a pair of
+	// parent/child items.
+	ExportValues []interface{} `json:"export-values,omitempty"`
 	// Alias usable alias to be used for the chart
 	Alias string `json:"alias,omitempty"`
 }
@@ -108,6 +120,26 @@ func LoadRequirements(c *chart.Chart) (*Requirements, error) {
 	return r, yaml.Unmarshal(data, r)
 }
 
+// ChartfileDependencies returns the dependencies declared in the
+// 'dependencies' block of a Chart.yaml file, nil if it has none.
+func ChartfileDependencies(data []byte) ([]*Dependency, error) {
+	r := &Requirements{}
+	if err := yaml.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	return r.Dependencies, nil
+}
+
+// chartfileRequirements returns the requirements file equivalent to the
+// 'dependencies' block of a Chart.yaml file, nil if it has none.
+func chartfileRequirements(data []byte) ([]byte, error) {
+	deps, err := ChartfileDependencies(data)
+	if err != nil || len(deps) == 0 {
+		return nil, err
+	}
+	return yaml.Marshal(&Requirements{Dependencies: deps})
+}
+
 // LoadRequirementsLock loads a requirements lock file.
 func LoadRequirementsLock(c *chart.Chart) (*RequirementsLock, error) {
 	var data []byte
@@ -125,6 +157,13 @@ func LoadRequirementsLock(c *chart.Chart) (*RequirementsLock, error) {
 
 // ProcessRequirementsConditions disables charts based on condition path value in values
 func ProcessRequirementsConditions(reqs *Requirements, cvals Values) {
+	processRequirementsConditions(reqs, cvals, nil)
+}
+
+// processRequirementsConditions disables charts based on their condition,
+// a list of value paths or an expression. Expressions depending on the
+// capabilities of the cluster are skipped if caps is nil.
+func processRequirementsConditions(reqs *Requirements, cvals Values, caps *Capabilities) {
 	var cond string
 	var conds []string
 	if reqs == nil || len(reqs.Dependencies) == 0 {
@@ -133,6 +172,15 @@ func ProcessRequirementsConditions(reqs *Requirements, cvals Values) {
 	for _, r := range reqs.Dependencies {
 		var hasTrue, hasFalse bool
 		cond = string(r.Condition)
+		if isConditionExpression(cond) {
+			enabled, err := evalCondition(cond, cvals, caps)
+			if err == nil {
+				r.Enabled = enabled
+			} else if err != errCapabilitiesUnknown {
+				log.Printf("Warning: Condition '%s' for chart %s cannot be evaluated: %s", cond, r.Name, err)
+			}
+			continue
+		}
 		// check for list
 		if len(cond) > 0 {
 			if strings.Contains(cond, ",") {
@@ -219,6 +267,12 @@ func ProcessRequirementsTags(reqs *Requirements, cvals Values) {
 
 }
 
+// isRequiredDependency returns true if the chart is the one required by the
+// requirement, under its name or, once processed, its alias.
+func isRequiredDependency(c *chart.Chart, req *Dependency) bool {
+	return c.Metadata.Name == req.Name || (req.Alias != "" && c.Metadata.Name == req.Alias)
+}
+
 func getAliasDependency(charts []*chart.Chart, aliasChart *Dependency) *chart.Chart {
 	var chartFound chart.Chart
 	for _, existingChart := range charts {
@@ -228,7 +282,7 @@ func getAliasDependency(charts []*chart.Chart, aliasChart *Dependency) *chart.Ch
 		if existingChart.Metadata == nil {
 			continue
 		}
-		if existingChart.Metadata.Name != aliasChart.Name {
+		if !isRequiredDependency(existingChart, aliasChart) {
 			continue
 		}
 		if !version.IsCompatibleRange(aliasChart.Version, existingChart.Metadata.Version) {
@@ -247,6 +301,15 @@ func getAliasDependency(charts []*chart.Chart, aliasChart *Dependency) *chart.Ch
 
 // ProcessRequirementsEnabled removes disabled charts from dependencies
 func ProcessRequirementsEnabled(c *chart.Chart, v *chart.Config) error {
+	return ProcessRequirementsEnabledCaps(c, v, nil)
+}
+
+// ProcessRequirementsEnabledCaps removes disabled charts from dependencies,
+// evaluating the conditions depending on the capabilities of the cluster
+// against caps. Those conditions are left undecided if caps is nil.
+//
+// It can process a chart again once the capabilities are known.
+func ProcessRequirementsEnabledCaps(c *chart.Chart, v *chart.Config, caps *Capabilities) error {
 	reqs, err := LoadRequirements(c)
 	if err != nil {
 		// if not just missing requirements file, return error
@@ -267,7 +330,7 @@ func ProcessRequirementsEnabled(c *chart.Chart, v *chart.Config) error {
 	for _, existingDependency := range c.Dependencies {
 		var dependencyFound bool
 		for _, req := range reqs.Dependencies {
-			if existingDependency.Metadata.Name == req.Name && version.IsCompatibleRange(req.Version, existingDependency.Metadata.Version) {
+			if isRequiredDependency(existingDependency, req) && version.IsCompatibleRange(req.Version, existingDependency.Metadata.Version) {
 				dependencyFound = true
 				break
 			}
@@ -303,7 +366,7 @@ func ProcessRequirementsEnabled(c *chart.Chart, v *chart.Config) error {
 	cc := chart.Config{Raw: yvals}
 	// flag dependencies as enabled/disabled
 	ProcessRequirementsTags(reqs, cvals)
-	ProcessRequirementsConditions(reqs, cvals)
+	processRequirementsConditions(reqs, cvals, caps)
 	// make a map of charts to remove
 	rm := map[string]bool{}
 	for _, r := range reqs.Dependencies {
@@ -323,7 +386,7 @@ func ProcessRequirementsEnabled(c *chart.Chart, v *chart.Config) error {
 	}
 	// recursively call self to process sub dependencies
 	for _, t := range cd {
-		err := ProcessRequirementsEnabled(t, &cc)
+		err := ProcessRequirementsEnabledCaps(t, &cc, caps)
 		// if its not just missing requirements file, return error
 		if nerr, ok := err.(ErrNoRequirementsFile); !ok && err != nil {
 			return nerr
@@ -335,9 +398,12 @@ func ProcessRequirementsEnabled(c *chart.Chart, v *chart.Config) error {
 }
 
 // pathToMap creates a nested map given a YAML path in dot notation.
-func pathToMap(path string, data map[string]interface{}) map[string]interface{} {
+func pathToMap(path string, data interface{}) map[string]interface{} {
 	if path == "." {
-		return data
+		if m, ok := data.(map[string]interface{}); ok {
+			return m
+		}
+		return nil
 	}
 	ap := strings.Split(path, ".")
 	if len(ap) == 0 {
@@ -365,6 +431,32 @@ func pathToMap(path string, data map[string]interface{}) map[string]interface{}
 	return n[0]
 }
 
+// setPath sets the value at a YAML path in dot notation, creating the
+// missing tables. It replaces the values that are not tables on the way.
+func setPath(dst map[string]interface{}, path string, data interface{}) {
+	ap := strings.Split(path, ".")
+	for _, k := range ap[:len(ap)-1] {
+		next, ok := dst[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			dst[k] = next
+		}
+		dst = next
+	}
+	dst[ap[len(ap)-1]] = data
+}
+
+// lookupPath returns the value, or the table, at a YAML path in dot notation.
+func lookupPath(v Values, path string) (interface{}, bool) {
+	if t, err := v.Table(path); err == nil {
+		return t.AsMap(), true
+	}
+	if val, err := v.PathValue(path); err == nil {
+		return val, true
+	}
+	return nil, false
+}
+
 // getParents returns a slice of parent charts in reverse order.
 func getParents(c *chart.Chart, out []*chart.Chart) []*chart.Chart {
 	if len(out) == 0 {
@@ -392,7 +484,85 @@ func processImportValues(c *chart.Chart) error {
 		return err
 	}
 	b := cvals.AsMap()
+
+	mappings := valuesMappings(c, reqs, cvals)
 	// import values from each dependency if specified in import-values
+	for _, m := range mappings {
+		if m.Export {
+			continue
+		}
+		v, ok := lookupPath(cvals, m.From)
+		if !ok {
+			log.Printf("Warning: ImportValues missing value: %s", m.From)
+			continue
+		}
+		vm := pathToMap(m.To, v)
+		if vm == nil {
+			log.Printf("Warning: ImportValues %s is not a table and cannot be imported at the top level", m.From)
+			continue
+		}
+		b = coalesceTables(b, vm, c.Metadata.Name)
+	}
+	// then export the values, imported ones included, to the dependencies
+	for _, m := range mappings {
+		if !m.Export {
+			continue
+		}
+		v, ok := lookupPath(b, m.From)
+		if !ok {
+			log.Printf("Warning: ExportValues missing value: %s", m.From)
+			continue
+		}
+		setPath(b, m.To, v)
+	}
+
+	y, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	// set the new values
+	c.Values = &chart.Config{Raw: string(y)}
+
+	return nil
+}
+
+// ValuesMapping is a value copied between a chart and one of its dependencies,
+// as declared by the import-values and export-values of its requirements.
+//
+// Both paths are YAML paths in the values of the chart, where the values of a
+// dependency are under its name or alias.
+type ValuesMapping struct {
+	// Dependency is the name, or alias, of the dependency.
+	Dependency string
+	// Export is true if the value is copied from the chart to the dependency,
+	// false if it is imported from the dependency.
+	Export bool
+	// From is the path of the copied value.
+	From string
+	// To is the path the value is copied to, "." for the top level.
+	To string
+}
+
+// RequirementsValuesMappings returns the values copied between a chart and its
+// dependencies, in the order they are applied. Wildcard imports are expanded
+// against the exports of the dependencies.
+func RequirementsValuesMappings(c *chart.Chart) ([]ValuesMapping, error) {
+	reqs, err := LoadRequirements(c)
+	if err != nil {
+		return nil, err
+	}
+	cvals, err := CoalesceValues(c, &chart.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return valuesMappings(c, reqs, cvals), nil
+}
+
+// valuesMappings lists the import-values then the export-values of the
+// requirements found in the dependencies of the chart.
+func valuesMappings(c *chart.Chart, reqs *Requirements, cvals Values) []ValuesMapping {
+	var imports, exports []ValuesMapping
 	for _, r := range reqs.Dependencies {
 		// only process raw requirement that is found in chart's dependencies (enabled)
 		found := false
@@ -409,54 +579,66 @@ func processImportValues(c *chart.Chart) error {
 		if !found {
 			continue
 		}
-		if len(r.ImportValues) > 0 {
-			var outiv []interface{}
-			for _, riv := range r.ImportValues {
-				switch iv := riv.(type) {
-				case map[string]interface{}:
-					nm := map[string]string{
-						"child":  iv["child"].(string),
-						"parent": iv["parent"].(string),
-					}
-					outiv = append(outiv, nm)
-					s := name + "." + nm["child"]
-					// get child table
-					vv, err := cvals.Table(s)
-					if err != nil {
-						log.Printf("Warning: ImportValues missing table: %v", err)
-						continue
-					}
-					// create value map from child to be merged into parent
-					vm := pathToMap(nm["parent"], vv.AsMap())
-					b = coalesceTables(b, vm, c.Metadata.Name)
-				case string:
-					nm := map[string]string{
-						"child":  "exports." + iv,
-						"parent": ".",
-					}
-					outiv = append(outiv, nm)
-					s := name + "." + nm["child"]
-					vm, err := cvals.Table(s)
-					if err != nil {
-						log.Printf("Warning: ImportValues missing table: %v", err)
-						continue
-					}
-					b = coalesceTables(b, vm.AsMap(), c.Metadata.Name)
+
+		for _, riv := range r.ImportValues {
+			switch iv := riv.(type) {
+			case map[string]interface{}:
+				child, parent, ok := valuesPair(iv)
+				if !ok {
+					log.Printf("Warning: ImportValues of %s need a child and a parent path: %v", name, iv)
+					continue
+				}
+				imports = append(imports, ValuesMapping{Dependency: name, From: name + "." + child, To: parent})
+			case string:
+				for _, export := range matchExports(cvals, name, iv) {
+					imports = append(imports, ValuesMapping{Dependency: name, From: name + ".exports." + export, To: "."})
 				}
 			}
-			// set our formatted import values
-			r.ImportValues = outiv
+		}
+
+		for _, rev := range r.ExportValues {
+			switch ev := rev.(type) {
+			case map[string]interface{}:
+				child, parent, ok := valuesPair(ev)
+				if !ok {
+					log.Printf("Warning: ExportValues of %s need a child and a parent path: %v", name, ev)
+					continue
+				}
+				exports = append(exports, ValuesMapping{Dependency: name, Export: true, From: parent, To: name + "." + child})
+			case string:
+				exports = append(exports, ValuesMapping{Dependency: name, Export: true, From: ev, To: name + "." + ev})
+			}
 		}
 	}
-	y, err := yaml.Marshal(b)
-	if err != nil {
-		return err
-	}
+	return append(imports, exports...)
+}
 
-	// set the new values
-	c.Values = &chart.Config{Raw: string(y)}
+// valuesPair returns the child and parent paths of an import or export.
+func valuesPair(m map[string]interface{}) (child, parent string, ok bool) {
+	child, cok := m["child"].(string)
+	parent, pok := m["parent"].(string)
+	return child, parent, cok && pok && child != "" && parent != ""
+}
 
-	return nil
+// matchExports returns the sorted names of the exports of a dependency matching
+// a pattern, as supported by path.Match. A name without wildcard is returned
+// as is, so that a missing export is reported when it is imported.
+func matchExports(cvals Values, name, pattern string) []string {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}
+	}
+	exports, err := cvals.Table(name + ".exports")
+	if err != nil {
+		return nil
+	}
+	var matches []string
+	for k := range exports {
+		if ok, _ := path.Match(pattern, k); ok {
+			matches = append(matches, k)
+		}
+	}
+	sort.Strings(matches)
+	return matches
 }
 
 // ProcessRequirementsImportValues imports specified chart values from child to parent.
@@ -0,0 +1,232 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/version"
+)
+
+// DependencyExplanation tells whether a dependency of a chart is enabled under
+// some values, and why.
+type DependencyExplanation struct {
+	// Name is the name, or alias, of the dependency prefixed by the names of
+	// the dependencies it is nested in, such as 'subchart1.subcharta'.
+	Name string `json:"name"`
+	// Chart is the name of the chart of the dependency.
+	Chart string `json:"chart"`
+	// Missing is true if the chart of the dependency is not in 'charts/'.
+	Missing bool `json:"missing,omitempty"`
+	// Enabled is true if the dependency is enabled.
+	Enabled bool `json:"enabled"`
+	// Reason tells what decided whether the dependency is enabled.
+	Reason string `json:"reason"`
+	// Condition is the condition of the dependency.
+	Condition string `json:"condition,omitempty"`
+	// Tags are the tags of the dependency.
+	Tags []string `json:"tags,omitempty"`
+	// Values are the values of the condition paths and tags that were looked
+	// up, in order.
+	Values []ExplainedValue `json:"values,omitempty"`
+	// Toggle is the value that would enable a disabled dependency or disable an
+	// enabled one, in the form of a '--set' argument. It is empty if no single
+	// value does, as for conditions that are expressions.
+	Toggle string `json:"toggle,omitempty"`
+}
+
+// ExplainedValue is a value looked up to decide whether a dependency is enabled.
+type ExplainedValue struct {
+	Path  string      `json:"path"`
+	Set   bool        `json:"set"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ExplainRequirements tells, for each dependency of the chart and of its enabled
+// dependencies, whether it is enabled under the values v and why. The tags and
+// conditions are processed as ProcessRequirementsEnabledCaps does, the
+// conditions depending on the capabilities of the cluster are undecided if caps
+// is nil.
+//
+// The dependencies of the chart are replaced by their aliased copies, as they
+// are when the requirements are processed.
+func ExplainRequirements(c *chart.Chart, v *chart.Config, caps *Capabilities) ([]*DependencyExplanation, error) {
+	return explainRequirements(c, v, caps, "")
+}
+
+func explainRequirements(c *chart.Chart, v *chart.Config, caps *Capabilities, prefix string) ([]*DependencyExplanation, error) {
+	reqs, err := LoadRequirements(c)
+	if err != nil {
+		if err == ErrRequirementsNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	charts := map[string]*chart.Chart{}
+	var chartDependencies []*chart.Chart
+	for _, existingDependency := range c.Dependencies {
+		var dependencyFound bool
+		for _, req := range reqs.Dependencies {
+			if isRequiredDependency(existingDependency, req) && version.IsCompatibleRange(req.Version, existingDependency.Metadata.Version) {
+				dependencyFound = true
+				break
+			}
+		}
+		if !dependencyFound {
+			chartDependencies = append(chartDependencies, existingDependency)
+		}
+	}
+	names := map[*Dependency]string{}
+	for _, req := range reqs.Dependencies {
+		names[req] = req.Name
+		if chartDependency := getAliasDependency(c.Dependencies, req); chartDependency != nil {
+			chartDependencies = append(chartDependencies, chartDependency)
+			charts[chartDependency.Metadata.Name] = chartDependency
+		}
+		if req.Alias != "" {
+			req.Name = req.Alias
+		}
+	}
+	c.Dependencies = chartDependencies
+
+	cvals, err := CoalesceValues(c, v)
+	if err != nil {
+		return nil, err
+	}
+	yvals, err := cvals.YAML()
+	if err != nil {
+		return nil, err
+	}
+	cc := &chart.Config{Raw: yvals}
+
+	var res []*DependencyExplanation
+	for _, req := range reqs.Dependencies {
+		e := explainRequirement(req, cvals, caps)
+		e.Name = prefix + req.Name
+		e.Chart = names[req]
+		sub, found := charts[req.Name]
+		e.Missing = !found
+		res = append(res, e)
+		if !e.Enabled || !found {
+			continue
+		}
+		subs, err := explainRequirements(sub, cc, caps, e.Name+".")
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, subs...)
+	}
+	return res, nil
+}
+
+// explainRequirement decides whether a requirement is enabled, the tags first
+// and then the condition, as ProcessRequirementsTags and
+// processRequirementsConditions do.
+func explainRequirement(r *Dependency, cvals Values, caps *Capabilities) *DependencyExplanation {
+	e := &DependencyExplanation{
+		Enabled:   true,
+		Reason:    "it has neither a condition nor tags",
+		Condition: r.Condition,
+		Tags:      r.Tags,
+	}
+
+	if len(r.Tags) > 0 {
+		vt, _ := cvals.Table("tags")
+		var hasTrue, hasFalse bool
+		var trueTag string
+		for _, k := range r.Tags {
+			b, ok := vt[k]
+			e.Values = append(e.Values, ExplainedValue{Path: "tags." + k, Set: ok, Value: b})
+			if bv, isBool := b.(bool); isBool {
+				if bv && !hasTrue {
+					hasTrue = true
+					trueTag = k
+				} else if !bv {
+					hasFalse = true
+				}
+			}
+		}
+		switch {
+		case hasTrue:
+			e.Reason = fmt.Sprintf("its tag %q is true", trueTag)
+		case hasFalse:
+			e.Enabled = false
+			e.Reason = "its tags are false"
+		default:
+			e.Reason = "none of its tags are set"
+		}
+		e.Toggle = tagsToggle(r.Tags, !e.Enabled)
+	}
+
+	cond := r.Condition
+	if isConditionExpression(cond) {
+		e.Toggle = ""
+		enabled, err := evalCondition(cond, cvals, caps)
+		switch err {
+		case nil:
+			e.Enabled = enabled
+			e.Reason = fmt.Sprintf("its condition is %t", enabled)
+		case errCapabilitiesUnknown:
+			e.Reason += ", its condition depends on the capabilities of the cluster"
+		default:
+			e.Reason += fmt.Sprintf(", its condition cannot be evaluated: %s", err)
+		}
+		return e
+	}
+	if len(cond) == 0 {
+		return e
+	}
+	conds := strings.Split(strings.TrimSpace(cond), ",")
+	if len(conds[0]) > 0 {
+		e.Toggle = fmt.Sprintf("%s=%t", conds[0], !e.Enabled)
+	}
+	for _, c := range conds {
+		if len(c) == 0 {
+			continue
+		}
+		vv, err := cvals.PathValue(c)
+		e.Values = append(e.Values, ExplainedValue{Path: c, Set: err == nil, Value: vv})
+		if bv, ok := vv.(bool); ok && err == nil {
+			e.Enabled = bv
+			e.Reason = fmt.Sprintf("its condition %s is %t", c, bv)
+			e.Toggle = fmt.Sprintf("%s=%t", conds[0], !bv)
+		} else if err == nil {
+			e.Reason += fmt.Sprintf(", its condition %s is not a boolean", c)
+		}
+		if vv != nil {
+			break
+		}
+	}
+	return e
+}
+
+// tagsToggle returns the tags set to value in the form of a '--set' argument.
+// A single true tag enables a dependency, all of them have to be false to
+// disable it.
+func tagsToggle(tags []string, value bool) string {
+	if value {
+		return fmt.Sprintf("tags.%s=true", tags[0])
+	}
+	var set []string
+	for _, t := range tags {
+		set = append(set, fmt.Sprintf("tags.%s=false", t))
+	}
+	return strings.Join(set, ",")
+}
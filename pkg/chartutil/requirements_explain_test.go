@@ -0,0 +1,92 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestExplainRequirements(t *testing.T) {
+	type expl struct {
+		name    string
+		enabled bool
+		reason  string
+		toggle  string
+	}
+	tests := []struct {
+		name     string
+		values   string
+		expected []expl
+	}{
+		{
+			name: "tags of the chart values",
+			expected: []expl{
+				{"subchart1", true, `its tag "front-end" is true`, "subchart1.enabled=false"},
+				{"subchart1.subcharta", true, `its tag "front-end" is true`, "subcharta.enabled=false"},
+				{"subchart1.subchartb", true, `its tag "front-end" is true`, "subchartb.enabled=false"},
+				{"subchart2", false, "its tags are false", "subchart2.enabled=true"},
+			},
+		},
+		{
+			name:   "conditions override tags",
+			values: "subchart1:\n  enabled: false\nsubchart2:\n  enabled: true\n",
+			expected: []expl{
+				{"subchart1", false, "its condition subchart1.enabled is false", "subchart1.enabled=true"},
+				{"subchart2", true, "its condition subchart2.enabled is true", "subchart2.enabled=false"},
+				{"subchart2.subchartb", false, "its tags are false", "subchartb.enabled=true"},
+				{"subchart2.subchartc", false, "its tags are false", "subchartc.enabled=true"},
+			},
+		},
+		{
+			name:   "second condition path",
+			values: "subchart1:\n  subcharta:\n    enabled: false\n",
+			expected: []expl{
+				{"subchart1", true, `its tag "front-end" is true`, "subchart1.enabled=false"},
+				{"subchart1.subcharta", false, "its condition subchart1.subcharta.enabled is false", "subcharta.enabled=true"},
+				{"subchart1.subchartb", true, `its tag "front-end" is true`, "subchartb.enabled=false"},
+				{"subchart2", false, "its tags are false", "subchart2.enabled=true"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load("testdata/subpop")
+			if err != nil {
+				t.Fatalf("Failed to load testdata: %s", err)
+			}
+			res, err := ExplainRequirements(c, &chart.Config{Raw: tt.values}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != len(tt.expected) {
+				for _, e := range res {
+					t.Logf("%+v", e)
+				}
+				t.Fatalf("expected %d dependencies, got %d", len(tt.expected), len(res))
+			}
+			for i, e := range tt.expected {
+				got := expl{res[i].Name, res[i].Enabled, res[i].Reason, res[i].Toggle}
+				if got != e {
+					t.Errorf("expected %+v, got %+v", e, got)
+				}
+			}
+		})
+	}
+}
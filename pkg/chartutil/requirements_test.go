@@ -18,11 +18,14 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"testing"
 
 	"strconv"
 
+	"github.com/golang/protobuf/ptypes/any"
+
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/version"
 )
@@ -284,6 +287,76 @@ func TestProcessRequirementsImportValues(t *testing.T) {
 
 	verifyRequirementsImportValues(t, c, v, e)
 }
+func TestProcessRequirementsExportValues(t *testing.T) {
+	reqs := `dependencies:
+- name: child
+  version: 0.1.0
+  repository: http://localhost:10191
+  import-values:
+  - "ports*"
+  - child: service.port
+    parent: serviceport
+  export-values:
+  - region
+  - child: database.host
+    parent: db.host
+  - child: database.port
+    parent: serviceport
+`
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "parent"},
+		Values:   &chart.Config{Raw: "region: eu\ndb:\n  host: db.example.com\n"},
+		Files:    []*any.Any{{TypeUrl: "requirements.yaml", Value: []byte(reqs)}},
+		Dependencies: []*chart.Chart{{
+			Metadata: &chart.Metadata{Name: "child"},
+			Values: &chart.Config{Raw: `service:
+  port: 8080
+exports:
+  portsA:
+    httpPort: 80
+  portsB:
+    grpcPort: 90
+  other:
+    debugPort: 70
+`},
+		}},
+	}
+
+	mappings, err := RequirementsValuesMappings(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectMappings := []ValuesMapping{
+		{Dependency: "child", From: "child.exports.portsA", To: "."},
+		{Dependency: "child", From: "child.exports.portsB", To: "."},
+		{Dependency: "child", From: "child.service.port", To: "serviceport"},
+		{Dependency: "child", Export: true, From: "region", To: "child.region"},
+		{Dependency: "child", Export: true, From: "db.host", To: "child.database.host"},
+		{Dependency: "child", Export: true, From: "serviceport", To: "child.database.port"},
+	}
+	if !reflect.DeepEqual(mappings, expectMappings) {
+		t.Errorf("Expected mappings %v, got %v", expectMappings, mappings)
+	}
+
+	e := map[string]string{
+		"httpPort":            "80",
+		"grpcPort":            "90",
+		"serviceport":         "8080",
+		"child.region":        "eu",
+		"child.database.host": "db.example.com",
+		"child.database.port": "8080",
+	}
+	verifyRequirementsImportValues(t, c, &chart.Config{}, e)
+
+	vals, err := ReadValues([]byte(c.Values.Raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vals.PathValue("debugPort"); err == nil {
+		t.Error("Expected the exports not matching the wildcard not to be imported")
+	}
+}
+
 func verifyRequirementsImportValues(t *testing.T, c *chart.Chart, v *chart.Config, e map[string]string) {
 
 	err := ProcessRequirementsImportValues(c)
@@ -18,21 +18,55 @@ package chartutil
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/ptypes/any"
 
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 var headerBytes = []byte("+aHR0cHM6Ly95b3V0dS5iZS96OVV6MWljandyTQo=")
 
+// ArchiveFormat is the format of a chart archive. It is also the extension
+// of the archive file.
+type ArchiveFormat string
+
+const (
+	// FormatTgz is a gzipped tar archive, the default format.
+	FormatTgz ArchiveFormat = "tgz"
+	// FormatZip is a zip archive, for the artifact stores that only take zip
+	// files. Charts load from both formats alike.
+	FormatZip ArchiveFormat = "zip"
+)
+
+// zipEpoch is the earliest modification time a zip archive can hold.
+var zipEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SaveOptions controls how a chart archive is written.
+//
+// Archives are reproducible: saving the same chart with the same options
+// always writes the same bytes, so the digest and the provenance of a chart
+// do not depend on when or where it was packaged.
+type SaveOptions struct {
+	// ModTime is the modification time of the files in the archive. The zero
+	// value stands for the Unix epoch. Zip archives hold no time earlier than
+	// 1980, which they use instead.
+	ModTime time.Time
+	// Format is the format of the archive. The zero value stands for
+	// FormatTgz.
+	Format ArchiveFormat
+}
+
 // SaveDir saves a chart as files in a directory.
 func SaveDir(c *chart.Chart, dest string) error {
 	// Create the chart directory
@@ -108,6 +142,21 @@ func SaveDir(c *chart.Chart, dest string) error {
 //
 // This returns the absolute path to the chart archive file.
 func Save(c *chart.Chart, outDir string) (string, error) {
+	return SaveWithOptions(c, outDir, SaveOptions{})
+}
+
+// SaveWithOptions creates an archived chart to the given directory, like Save.
+// The extension of the archive file is the one of its format, such as
+// /foo/bar-1.0.0.zip for a zip archive.
+func SaveWithOptions(c *chart.Chart, outDir string, opts SaveOptions) (string, error) {
+	format := opts.Format
+	if format == "" {
+		format = FormatTgz
+	}
+	if format != FormatTgz && format != FormatZip {
+		return "", fmt.Errorf("unknown archive format %q, must be %s or %s", format, FormatTgz, FormatZip)
+	}
+
 	// Create archive
 	if fi, err := os.Stat(outDir); err != nil {
 		return "", err
@@ -126,7 +175,7 @@ func Save(c *chart.Chart, outDir string) (string, error) {
 		return "", errors.New("no chart version specified (Chart.yaml)")
 	}
 
-	filename := fmt.Sprintf("%s-%s.tgz", cfile.Name, cfile.Version)
+	filename := fmt.Sprintf("%s-%s.%s", cfile.Name, cfile.Version, format)
 	filename = filepath.Join(outDir, filename)
 	if stat, err := os.Stat(filepath.Dir(filename)); os.IsNotExist(err) {
 		if err := os.MkdirAll(filepath.Dir(filename), 0755); !os.IsExist(err) {
@@ -141,30 +190,87 @@ func Save(c *chart.Chart, outDir string) (string, error) {
 		return "", err
 	}
 
+	modTime := opts.ModTime
+	if modTime.IsZero() {
+		modTime = time.Unix(0, 0)
+	}
+	if format == FormatZip {
+		err = writeZip(f, c, modTime)
+	} else {
+		err = writeTgz(f, c, modTime)
+	}
+	f.Close()
+	if err != nil {
+		os.Remove(filename)
+	}
+	return filename, err
+}
+
+// writeTgz writes a chart as a gzipped tar archive.
+func writeTgz(w io.Writer, c *chart.Chart, modTime time.Time) error {
 	// Wrap in gzip writer
-	zipper := gzip.NewWriter(f)
+	zipper := gzip.NewWriter(w)
 	zipper.Header.Extra = headerBytes
 	zipper.Header.Comment = "Helm"
 
 	// Wrap in tar writer
 	twriter := tar.NewWriter(zipper)
-	rollback := false
-	defer func() {
-		twriter.Close()
-		zipper.Close()
-		f.Close()
-		if rollback {
-			os.Remove(filename)
-		}
-	}()
+	if err := writeContents(tarArchive{twriter}, c, "", modTime); err != nil {
+		return err
+	}
+	if err := twriter.Close(); err != nil {
+		return err
+	}
+	return zipper.Close()
+}
 
-	if err := writeTarContents(twriter, c, ""); err != nil {
-		rollback = true
+// writeZip writes a chart as a zip archive.
+func writeZip(w io.Writer, c *chart.Chart, modTime time.Time) error {
+	if modTime.Before(zipEpoch) {
+		modTime = zipEpoch
 	}
-	return filename, err
+	zwriter := zip.NewWriter(w)
+	if err := zwriter.SetComment("Helm"); err != nil {
+		return err
+	}
+	if err := writeContents(zipArchive{zwriter}, c, "", modTime); err != nil {
+		return err
+	}
+	return zwriter.Close()
+}
+
+// archive adds files to a chart archive.
+type archive interface {
+	add(name string, body []byte, modTime time.Time) error
+}
+
+type tarArchive struct{ *tar.Writer }
+
+func (a tarArchive) add(name string, body []byte, modTime time.Time) error {
+	return writeToTar(a.Writer, name, body, modTime)
 }
 
-func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
+type zipArchive struct{ *zip.Writer }
+
+// add writes a single file to a zip archive, normalized like writeToTar.
+func (a zipArchive) add(name string, body []byte, modTime time.Time) error {
+	h := &zip.FileHeader{
+		Name:     filepath.ToSlash(name),
+		Method:   zip.Deflate,
+		Modified: modTime.UTC(),
+	}
+	h.SetMode(0644)
+	w, err := a.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// writeContents writes the files of a chart and of its dependencies, each
+// sorted by name.
+func writeContents(out archive, c *chart.Chart, prefix string, modTime time.Time) error {
 	base := filepath.Join(prefix, c.Metadata.Name)
 
 	// Save Chart.yaml
@@ -172,50 +278,65 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 	if err != nil {
 		return err
 	}
-	if err := writeToTar(out, base+"/Chart.yaml", cdata); err != nil {
+	if err := out.add(base+"/Chart.yaml", cdata, modTime); err != nil {
 		return err
 	}
 
 	// Save values.yaml
 	if c.Values != nil && len(c.Values.Raw) > 0 {
-		if err := writeToTar(out, base+"/values.yaml", []byte(c.Values.Raw)); err != nil {
+		if err := out.add(base+"/values.yaml", []byte(c.Values.Raw), modTime); err != nil {
 			return err
 		}
 	}
 
 	// Save templates
-	for _, f := range c.Templates {
+	templates := append([]*chart.Template(nil), c.Templates...)
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	for _, f := range templates {
 		n := filepath.Join(base, f.Name)
-		if err := writeToTar(out, n, f.Data); err != nil {
+		if err := out.add(n, f.Data, modTime); err != nil {
 			return err
 		}
 	}
 
 	// Save files
-	for _, f := range c.Files {
+	files := append([]*any.Any(nil), c.Files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].TypeUrl < files[j].TypeUrl })
+	for _, f := range files {
 		n := filepath.Join(base, f.TypeUrl)
-		if err := writeToTar(out, n, f.Value); err != nil {
+		if err := out.add(n, f.Value, modTime); err != nil {
 			return err
 		}
 	}
 
 	// Save dependencies
-	for _, dep := range c.Dependencies {
-		if err := writeTarContents(out, dep, base+"/charts"); err != nil {
+	deps := append([]*chart.Chart(nil), c.Dependencies...)
+	sort.Slice(deps, func(i, j int) bool {
+		mi, mj := deps[i].GetMetadata(), deps[j].GetMetadata()
+		if mi.GetName() != mj.GetName() {
+			return mi.GetName() < mj.GetName()
+		}
+		return mi.GetVersion() < mj.GetVersion()
+	})
+	for _, dep := range deps {
+		if err := writeContents(out, dep, base+"/charts", modTime); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// writeToTar writes a single file to a tar archive.
-func writeToTar(out *tar.Writer, name string, body []byte) error {
+// writeToTar writes a single file to a tar archive. Everything but the name
+// and the body of the file is normalized, so that the archive only depends on
+// the contents of the chart.
+func writeToTar(out *tar.Writer, name string, body []byte, modTime time.Time) error {
 	// TODO: Do we need to create dummy parent directory names if none exist?
 	h := &tar.Header{
-		Name:    filepath.ToSlash(name),
-		Mode:    0755,
-		Size:    int64(len(body)),
-		ModTime: time.Now(),
+		Name:     filepath.ToSlash(name),
+		Mode:     0644,
+		Size:     int64(len(body)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
 	}
 	if err := out.WriteHeader(h); err != nil {
 		return err
@@ -18,6 +18,8 @@ package chartutil
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bytes"
 	"compress/gzip"
 	"io"
 	"io/ioutil"
@@ -83,12 +85,7 @@ func TestSave(t *testing.T) {
 	}
 }
 
-func TestSavePreservesTimestamps(t *testing.T) {
-	// Test executes so quickly that if we don't subtract a second, the
-	// check will fail because `initialCreateTime` will be identical to the
-	// written timestamp for the files.
-	initialCreateTime := time.Now().Add(-1 * time.Second)
-
+func TestSaveTimestamps(t *testing.T) {
 	tmp, err := ioutil.TempDir("", "helm-")
 	if err != nil {
 		t.Fatal(err)
@@ -111,21 +108,141 @@ func TestSavePreservesTimestamps(t *testing.T) {
 		},
 	}
 
-	where, err := Save(c, tmp)
+	tests := []struct {
+		opts   SaveOptions
+		expect time.Time
+	}{
+		{SaveOptions{}, time.Unix(0, 0)},
+		{SaveOptions{ModTime: time.Unix(1556668800, 0)}, time.Unix(1556668800, 0)},
+	}
+	for _, tt := range tests {
+		where, err := SaveWithOptions(c, tmp, tt.opts)
+		if err != nil {
+			t.Fatalf("Failed to save: %s", err)
+		}
+
+		allHeaders, err := retrieveAllHeadersFromTar(where)
+		if err != nil {
+			t.Fatalf("Failed to parse tar: %v", err)
+		}
+
+		for _, header := range allHeaders {
+			if !header.ModTime.Equal(tt.expect) {
+				t.Errorf("Expected %s to be modified at %s, got %s", header.Name, tt.expect, header.ModTime)
+			}
+			if header.Mode != 0644 {
+				t.Errorf("Expected %s to have mode 0644, got %o", header.Name, header.Mode)
+			}
+		}
+	}
+}
+
+func TestSaveIsReproducible(t *testing.T) {
+	newChart := func(reverse bool) *chart.Chart {
+		c := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "ahab", Version: "1.2.3"},
+			Values:   &chart.Config{Raw: "ship: Pequod"},
+			Files: []*any.Any{
+				{TypeUrl: "README.md", Value: []byte("Call me Ishmael.")},
+				{TypeUrl: "LICENSE", Value: []byte("public domain")},
+			},
+			Templates: []*chart.Template{
+				{Name: "templates/whale.yaml", Data: []byte("kind: Whale")},
+				{Name: "templates/boat.yaml", Data: []byte("kind: Boat")},
+			},
+			Dependencies: []*chart.Chart{
+				{Metadata: &chart.Metadata{Name: "starbuck", Version: "0.1.0"}},
+				{Metadata: &chart.Metadata{Name: "queequeg", Version: "0.1.0"}},
+			},
+		}
+		if reverse {
+			c.Files[0], c.Files[1] = c.Files[1], c.Files[0]
+			c.Templates[0], c.Templates[1] = c.Templates[1], c.Templates[0]
+			c.Dependencies[0], c.Dependencies[1] = c.Dependencies[1], c.Dependencies[0]
+		}
+		return c
+	}
+
+	var archives [][]byte
+	for _, reverse := range []bool{false, true} {
+		tmp, err := ioutil.TempDir("", "helm-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(tmp)
+
+		where, err := Save(newChart(reverse), tmp)
+		if err != nil {
+			t.Fatalf("Failed to save: %s", err)
+		}
+		b, err := ioutil.ReadFile(where)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archives = append(archives, b)
+	}
+	if !bytes.Equal(archives[0], archives[1]) {
+		t.Error("Expected the archives of the same chart to be identical")
+	}
+}
+
+func TestSaveZip(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-")
 	if err != nil {
-		t.Fatalf("Failed to save: %s", err)
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	c, err := Load("testdata/frobnitz-1.2.3.tgz")
+	if err != nil {
+		t.Fatalf("Failed to load testdata: %s", err)
 	}
 
-	allHeaders, err := retrieveAllHeadersFromTar(where)
+	where, err := SaveWithOptions(c, tmp, SaveOptions{Format: FormatZip})
 	if err != nil {
-		t.Fatalf("Failed to parse tar: %v", err)
+		t.Fatalf("Failed to save: %s", err)
+	}
+	if !strings.HasSuffix(where, "frobnitz-1.2.3.zip") {
+		t.Fatalf("Expected %q to end with frobnitz-1.2.3.zip", where)
+	}
+	first, err := ioutil.ReadFile(where)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	for _, header := range allHeaders {
-		if header.ModTime.Before(initialCreateTime) {
-			t.Fatalf("File timestamp not preserved: %v", header.ModTime)
+	zr, err := zip.OpenReader(where)
+	if err != nil {
+		t.Fatalf("Failed to open zip: %s", err)
+	}
+	for _, f := range zr.File {
+		if !f.Modified.Equal(zipEpoch) {
+			t.Errorf("Expected %s to be modified at %s, got %s", f.Name, zipEpoch, f.Modified)
 		}
 	}
+	zr.Close()
+
+	c2, err := LoadFile(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyFrobnitz(t, c2)
+	verifyChart(t, c2)
+	verifyRequirements(t, c2)
+
+	if _, err := SaveWithOptions(c2, tmp, SaveOptions{Format: FormatZip}); err != nil {
+		t.Fatalf("Failed to save: %s", err)
+	}
+	second, err := ioutil.ReadFile(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("Expected the zip archives of the same chart to be identical")
+	}
+
+	if _, err := SaveWithOptions(c, tmp, SaveOptions{Format: "rar"}); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
 }
 
 // We could refactor `load.go` to use this `retrieveAllHeadersFromTar` function
@@ -0,0 +1,99 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// StarterfileName is the name of the file declaring the variables of a starter.
+const StarterfileName = "starter.yaml"
+
+var starterVariableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Starterfile declares the variables of a starter, the chart that 'helm create'
+// scaffolds new charts from.
+//
+// Like <CHARTNAME>, each variable is written as <NAME> in the templates and the
+// values of the starter, and replaced when a chart is created from it.
+type Starterfile struct {
+	Variables []*StarterVariable `json:"variables,omitempty"`
+}
+
+// StarterVariable is a variable of a starter.
+type StarterVariable struct {
+	// Name is the name of the variable, written as <Name> in the starter.
+	Name string `json:"name"`
+	// Description tells what the variable is for, when it is asked for.
+	Description string `json:"description,omitempty"`
+	// Default is the value of the variable when it is not given. A variable
+	// without a default must be given.
+	Default string `json:"default,omitempty"`
+}
+
+// LoadStarterfile loads the variables declared by a starter in starter.yaml.
+//
+// A starter without a starter.yaml has no variables.
+func LoadStarterfile(c *chart.Chart) (*Starterfile, error) {
+	s := &Starterfile{}
+	for _, f := range c.Files {
+		if f.TypeUrl == StarterfileName {
+			if err := yaml.Unmarshal(f.Value, s); err != nil {
+				return nil, fmt.Errorf("cannot load %s: %s", StarterfileName, err)
+			}
+		}
+	}
+	for _, v := range s.Variables {
+		if !starterVariableRe.MatchString(v.Name) {
+			return nil, fmt.Errorf("invalid starter variable name %q", v.Name)
+		}
+		if v.Name == "CHARTNAME" {
+			return nil, fmt.Errorf("starter variable %s is reserved for the name of the chart", v.Name)
+		}
+	}
+	return s, nil
+}
+
+// Replacements returns the values of the variables of the starter: the given
+// ones, or their defaults.
+//
+// It fails if a variable without a default is not given, or if a given
+// variable is not declared by the starter.
+func (s *Starterfile) Replacements(given map[string]string) (map[string]string, error) {
+	vals := make(map[string]string, len(s.Variables))
+	for _, v := range s.Variables {
+		val, ok := given[v.Name]
+		if !ok {
+			if v.Default == "" {
+				return nil, fmt.Errorf("starter variable %s is not set", v.Name)
+			}
+			val = v.Default
+		}
+		vals[v.Name] = val
+	}
+	for name := range given {
+		if _, ok := vals[name]; !ok {
+			return nil, fmt.Errorf("the starter has no variable %s", name)
+		}
+	}
+	return vals, nil
+}
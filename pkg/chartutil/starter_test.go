@@ -0,0 +1,96 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestCreateFromStarter(t *testing.T) {
+	tdir, err := ioutil.TempDir("", "helm-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tdir)
+
+	src, err := Create(&chart.Metadata{Name: "starter"}, tdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	starterfile := `variables:
+- name: PORT
+  description: Port of the service
+- name: IMAGE
+  default: nginx
+`
+	if err := ioutil.WriteFile(filepath.Join(src, StarterfileName), []byte(starterfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tpl := "name: <CHARTNAME>\nport: <PORT>\nimage: <IMAGE>\n"
+	if err := ioutil.WriteFile(filepath.Join(src, TemplatesDir, "vars.yaml"), []byte(tpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(tdir, "out")
+	os.Mkdir(dest, 0755)
+	cf := &chart.Metadata{Name: "foo"}
+
+	if err := CreateFromStarter(cf, dest, src, nil); err == nil || !strings.Contains(err.Error(), "PORT is not set") {
+		t.Errorf("expected an error for the missing variable, got %v", err)
+	}
+	if err := CreateFromStarter(cf, dest, src, map[string]string{"PORT": "80", "TAG": "1"}); err == nil || !strings.Contains(err.Error(), "no variable TAG") {
+		t.Errorf("expected an error for the unknown variable, got %v", err)
+	}
+
+	if err := CreateFromStarter(cf, dest, src, map[string]string{"PORT": "8080"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dest, "foo", TemplatesDir, "vars.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "name: foo\nport: 8080\nimage: nginx\n"; string(data) != expect {
+		t.Errorf("expected %q, got %q", expect, data)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "foo", StarterfileName)); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be left out of the new chart", StarterfileName)
+	}
+}
+
+func TestLoadStarterfile(t *testing.T) {
+	c := &chart.Chart{}
+	sf, err := LoadStarterfile(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.Variables) != 0 {
+		t.Errorf("expected no variables, got %d", len(sf.Variables))
+	}
+
+	c.Files = append(c.Files, &any.Any{TypeUrl: StarterfileName, Value: []byte("variables:\n- name: CHARTNAME\n")})
+	if _, err := LoadStarterfile(c); err == nil {
+		t.Error("expected CHARTNAME to be refused")
+	}
+}
@@ -321,6 +321,10 @@ func coalesceTables(dst, src map[string]interface{}, chartName string) map[strin
 	return rv
 }
 
+// PreviousValuesAnnotation is the Chart.yaml annotation with which a chart
+// opts in to .Release.PreviousValues, by setting it to "true".
+const PreviousValuesAnnotation = "helm.sh/previous-values"
+
 // ReleaseOptions represents the additional release options needed
 // for the composition of the final values struct
 type ReleaseOptions struct {
@@ -330,6 +334,15 @@ type ReleaseOptions struct {
 	IsUpgrade bool
 	IsInstall bool
 	Revision  int
+	// PreviousValues are the values of the release being upgraded. They are
+	// only exposed to the charts that opt in with PreviousValuesAnnotation.
+	PreviousValues Values
+}
+
+// WantsPreviousValues returns whether a chart opts in to
+// .Release.PreviousValues.
+func WantsPreviousValues(c *chart.Chart) bool {
+	return c.GetMetadata().GetAnnotations()[PreviousValuesAnnotation] == "true"
 }
 
 // ToRenderValues composes the struct from the data coming from the Releases, Charts and Values files
@@ -347,17 +360,27 @@ func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOp
 //
 // This takes both ReleaseOptions and Capabilities to merge into the render values.
 func ToRenderValuesCaps(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOptions, caps *Capabilities) (Values, error) {
+	release := map[string]interface{}{
+		"Name":      options.Name,
+		"Time":      options.Time,
+		"Namespace": options.Namespace,
+		"IsUpgrade": options.IsUpgrade,
+		"IsInstall": options.IsInstall,
+		"Revision":  options.Revision,
+		"Service":   "Tiller",
+	}
+	// The charts that opt in see an empty object rather than a missing one
+	// when there is no previous release.
+	if WantsPreviousValues(chrt) {
+		previous := Values{}
+		if options.PreviousValues != nil {
+			previous = options.PreviousValues
+		}
+		release["PreviousValues"] = previous
+	}
 
 	top := map[string]interface{}{
-		"Release": map[string]interface{}{
-			"Name":      options.Name,
-			"Time":      options.Time,
-			"Namespace": options.Namespace,
-			"IsUpgrade": options.IsUpgrade,
-			"IsInstall": options.IsInstall,
-			"Revision":  options.Revision,
-			"Service":   "Tiller",
-		},
+		"Release":      release,
 		"Chart":        chrt.Metadata,
 		"Files":        NewFiles(chrt.Files),
 		"Capabilities": caps,
@@ -0,0 +1,200 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ValueDoc documents a value of a chart, from the comments above it in
+// values.yaml.
+//
+// Besides the description, the comments may hold markers on lines of their
+// own:
+//
+//	# Name of the database to create.
+//	# @required
+//	# @pattern ^[a-z][a-z0-9_]*$
+//	database: ""
+//	# @enum Always IfNotPresent Never
+//	pullPolicy: IfNotPresent
+type ValueDoc struct {
+	// Path is the path of the value, such as image.tag.
+	Path string
+	// Description is the text of the comments above the value.
+	Description string
+	// Default is the value in values.yaml.
+	Default interface{}
+	// Required is whether the value must be set to something other than an
+	// empty string, marked with @required.
+	Required bool
+	// Enum lists the accepted values, marked with @enum.
+	Enum []string
+	// Pattern is a regular expression a string value must match, marked
+	// with @pattern.
+	Pattern string
+}
+
+// Validate checks an answer for the value against its markers.
+func (d *ValueDoc) Validate(s string) error {
+	if s == "" {
+		if d.Required {
+			return fmt.Errorf("%s is required", d.Path)
+		}
+		return nil
+	}
+	if len(d.Enum) > 0 {
+		found := false
+		for _, e := range d.Enum {
+			found = found || e == s
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %s", d.Path, strings.Join(d.Enum, ", "))
+		}
+	}
+	if d.Pattern != "" {
+		re, err := regexp.Compile(d.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for %s: %s", d.Path, err)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%s must match %s", d.Path, d.Pattern)
+		}
+	}
+	return nil
+}
+
+// DocumentValues returns the documentation of the scalar values of a
+// values.yaml, in the order they are written. Values in lists are left out.
+func DocumentValues(raw string) ([]*ValueDoc, error) {
+	vals, err := ReadValues([]byte(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var (
+		docs     []*ValueDoc
+		comments []string
+		parents  []level
+		// skip is the indentation under which lines belong to a list or a
+		// block scalar, or -1.
+		skip = -1
+	)
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if trimmed == "" {
+			comments = nil
+			continue
+		}
+		if skip >= 0 {
+			if indent > skip || (indent == skip && strings.HasPrefix(trimmed, "-")) {
+				continue
+			}
+			skip = -1
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+			continue
+		case strings.HasPrefix(trimmed, "-"):
+			skip, comments = indent, nil
+			continue
+		}
+
+		i := strings.Index(trimmed, ":")
+		if i < 0 {
+			comments = nil
+			continue
+		}
+		key := strings.Trim(trimmed[:i], `"'`)
+		rest := strings.TrimSpace(trimmed[i+1:])
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		path := key
+		if len(parents) > 0 {
+			path = parents[len(parents)-1].key + "." + key
+		}
+
+		if rest == "" || strings.HasPrefix(rest, "#") {
+			parents = append(parents, level{indent: indent, key: path})
+			comments = nil
+			continue
+		}
+		if strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">") {
+			skip = indent
+		}
+		if v, ok := scalarValue(vals, path); ok {
+			docs = append(docs, newValueDoc(path, v, comments))
+		}
+		comments = nil
+	}
+	return docs, scanner.Err()
+}
+
+func newValueDoc(path string, v interface{}, comments []string) *ValueDoc {
+	d := &ValueDoc{Path: path, Default: v}
+	var text []string
+	for _, c := range comments {
+		fields := strings.Fields(c)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "@required":
+			d.Required = true
+		case fields[0] == "@enum":
+			for _, e := range fields[1:] {
+				d.Enum = append(d.Enum, strings.Trim(e, ","))
+			}
+		case fields[0] == "@pattern" && len(fields) > 1:
+			d.Pattern = strings.TrimSpace(strings.TrimPrefix(c, "@pattern"))
+		default:
+			text = append(text, c)
+		}
+	}
+	d.Description = strings.Join(text, " ")
+	return d
+}
+
+// scalarValue returns the value at a dotted path, if it is not a table or
+// a list.
+func scalarValue(vals Values, path string) (interface{}, bool) {
+	var cur interface{} = map[string]interface{}(vals)
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	switch cur.(type) {
+	case map[string]interface{}, []interface{}:
+		return nil, false
+	}
+	return cur, true
+}
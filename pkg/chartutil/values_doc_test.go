@@ -0,0 +1,91 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocumentValues(t *testing.T) {
+	raw := `# Number of pods
+replicaCount: 1
+
+image:
+  ## The image to run
+  repository: nginx
+  tag: stable
+  # @enum Always IfNotPresent Never
+  pullPolicy: IfNotPresent
+
+ingress:
+  enabled: false
+  hosts:
+    - host: chart-example.local
+      paths: []
+  annotations: {}
+# Name of the database.
+# @required
+# @pattern ^[a-z]+$
+database: ""
+notes: |
+  first: line
+env:
+- name: DEBUG
+  value: "1"
+`
+	docs, err := DocumentValues(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, d := range docs {
+		paths = append(paths, d.Path)
+	}
+	expect := []string{"replicaCount", "image.repository", "image.tag", "image.pullPolicy", "ingress.enabled", "database", "notes"}
+	if !reflect.DeepEqual(paths, expect) {
+		t.Fatalf("expected %v, got %v", expect, paths)
+	}
+
+	if d := docs[1]; d.Description != "The image to run" || d.Default != "nginx" {
+		t.Errorf("unexpected documentation %+v", d)
+	}
+	if d := docs[3]; !reflect.DeepEqual(d.Enum, []string{"Always", "IfNotPresent", "Never"}) || d.Description != "" {
+		t.Errorf("unexpected documentation %+v", d)
+	}
+	db := docs[5]
+	if db.Description != "Name of the database." || !db.Required || db.Pattern != "^[a-z]+$" {
+		t.Errorf("unexpected documentation %+v", db)
+	}
+
+	for _, tt := range []struct {
+		doc   *ValueDoc
+		value string
+		valid bool
+	}{
+		{db, "", false},
+		{db, "Sales", false},
+		{db, "sales", true},
+		{docs[3], "Sometimes", false},
+		{docs[3], "Never", true},
+		{docs[2], "", true},
+	} {
+		if err := tt.doc.Validate(tt.value); (err == nil) != tt.valid {
+			t.Errorf("%s: expected %q to be valid=%t, got %v", tt.doc.Path, tt.value, tt.valid, err)
+		}
+	}
+}
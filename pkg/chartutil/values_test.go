@@ -174,6 +174,43 @@ where:
 	}
 }
 
+func TestToRenderValuesCapsPreviousValues(t *testing.T) {
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "test"}}
+	o := ReleaseOptions{
+		Name:           "Seven Voyages",
+		IsUpgrade:      true,
+		Revision:       2,
+		PreviousValues: Values{"name": "al Rashid"},
+	}
+	caps := &Capabilities{APIVersions: DefaultVersionSet}
+
+	res, err := ToRenderValuesCaps(c, &chart.Config{}, o, caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev, ok := res["Release"].(map[string]interface{})["PreviousValues"]; ok {
+		t.Errorf("Expected no previous values without opting in, got %v", prev)
+	}
+
+	c.Metadata.Annotations = map[string]string{PreviousValuesAnnotation: "true"}
+	res, err = ToRenderValuesCaps(c, &chart.Config{}, o, caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := res["Release"].(map[string]interface{})["PreviousValues"].(Values)["name"]; name != "al Rashid" {
+		t.Errorf("Expected previous name 'al Rashid', got %q", name)
+	}
+
+	o.PreviousValues = nil
+	res, err = ToRenderValuesCaps(c, &chart.Config{}, o, caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev := res["Release"].(map[string]interface{})["PreviousValues"].(Values); len(prev) != 0 {
+		t.Errorf("Expected empty previous values without a previous release, got %v", prev)
+	}
+}
+
 func TestReadValuesFile(t *testing.T) {
 	data, err := ReadValuesFile("./testdata/coleridge.yaml")
 	if err != nil {
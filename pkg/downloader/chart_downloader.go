@@ -16,20 +16,22 @@ limitations under the License.
 package downloader
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/helmpath"
 	"k8s.io/helm/pkg/provenance"
 	"k8s.io/helm/pkg/repo"
-	"k8s.io/helm/pkg/urlutil"
+	"k8s.io/helm/pkg/trace"
 )
 
 // VerificationStrategy describes a strategy for determining whether to verify a chart.
@@ -53,6 +55,50 @@ const (
 // ErrNoOwnerRepo indicates that a given chart URL can't be found in any repos.
 var ErrNoOwnerRepo = errors.New("could not find a repo containing the given URL")
 
+// digestPrefix is the prefix of a digest pinned in a chart reference.
+const digestPrefix = "sha256:"
+
+var digestRe = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// ParseChartReference splits a chart reference pinned to a version or to a
+// digest, such as 'stable/mariadb@5.1.0' or 'stable/mariadb@sha256:<hex>', into
+// the reference without the pin, the version and the SHA256 digest of the
+// chart archive.
+//
+// The version argument is the version requested separately from the
+// reference, for instance with --version. It is returned unchanged if the
+// reference is not pinned, and it is an error for it to disagree with the pin.
+// A digest cannot be combined with a version.
+func ParseChartReference(ref, version string) (string, string, string, error) {
+	i := strings.LastIndex(ref, "@")
+	if i < 0 || strings.Contains(ref[i:], "/") {
+		return ref, version, "", nil
+	}
+	name, pin := ref[:i], ref[i+1:]
+	if name == "" || pin == "" {
+		return ref, version, "", fmt.Errorf("invalid chart reference %q", ref)
+	}
+
+	if !strings.Contains(pin, ":") {
+		if version != "" && version != pin {
+			return ref, version, "", fmt.Errorf("chart reference %q is pinned to version %s, but version %s was requested", ref, pin, version)
+		}
+		return name, pin, "", nil
+	}
+
+	if !strings.HasPrefix(pin, digestPrefix) {
+		return ref, version, "", fmt.Errorf("unsupported digest in chart reference %q, only %s digests are supported", ref, strings.TrimSuffix(digestPrefix, ":"))
+	}
+	digest := strings.TrimPrefix(pin, digestPrefix)
+	if !digestRe.MatchString(digest) {
+		return ref, version, "", fmt.Errorf("invalid digest in chart reference %q", ref)
+	}
+	if version != "" {
+		return ref, version, "", fmt.Errorf("chart reference %q is pinned to a digest and cannot be combined with version %s", ref, version)
+	}
+	return name, "", digest, nil
+}
+
 // ChartDownloader handles downloading a chart.
 //
 // It is capable of performing verifications on charts as well.
@@ -82,6 +128,8 @@ type ChartDownloader struct {
 //
 // For VerifyNever and VerifyIfPossible, the Verification may be empty.
 //
+// If the reference is pinned to a digest, the downloaded archive must match it.
+//
 // Returns a string path to the location where the file was downloaded and a verification
 // (if provenance was verified), or an error if something bad happened.
 func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *provenance.Verification, error) {
@@ -95,9 +143,19 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 		return "", nil, err
 	}
 
+	if _, _, digest, _ := ParseChartReference(ref, version); digest != "" {
+		got, err := provenance.Digest(bytes.NewReader(data.Bytes()))
+		if err != nil {
+			return "", nil, err
+		}
+		if got != digest {
+			return "", nil, fmt.Errorf("digest mismatch for %s: expected %s%s, got %s%s", u, digestPrefix, digest, digestPrefix, got)
+		}
+	}
+
 	name := filepath.Base(u.Path)
 	destfile := filepath.Join(dest, name)
-	if err := ioutil.WriteFile(destfile, data.Bytes(), 0644); err != nil {
+	if err := trace.WriteFile(destfile, data.Bytes(), 0644); err != nil {
 		return destfile, nil, err
 	}
 
@@ -113,7 +171,7 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 			return destfile, ver, nil
 		}
 		provfile := destfile + ".prov"
-		if err := ioutil.WriteFile(provfile, body.Bytes(), 0644); err != nil {
+		if err := trace.WriteFile(provfile, body.Bytes(), 0644); err != nil {
 			return destfile, nil, err
 		}
 
@@ -135,6 +193,7 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 // the URL.
 //
 // A reference may be an HTTP URL, a 'reponame/chartname' reference, or a local path.
+// It may be pinned to a version or a digest, see ParseChartReference.
 //
 // A version is a SemVer string (1.2.3-beta.1+f334a6789).
 //
@@ -142,8 +201,14 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 //	- For a chart reference
 //		* If version is non-empty, this will return the URL for that version
 //		* If version is empty, this will return the URL for the latest version
+//		* If a digest is pinned, this will return the URL of the version with that digest
 //		* If no version can be found, an error is returned
 func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, getter.Getter, error) {
+	ref, pinned, digest, err := ParseChartReference(ref, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	u, err := url.Parse(ref)
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid chart URL format: %s", ref)
@@ -155,6 +220,10 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, ge
 	}
 
 	if u.IsAbs() && len(u.Host) > 0 && len(u.Path) > 0 {
+		if pinned != version {
+			return u, nil, fmt.Errorf("chart URL %s cannot be pinned to a version, pin it to a digest instead", ref)
+		}
+
 		// In this case, we have to find the parent repo that contains this chart
 		// URL. And this is an unfortunate problem, as it requires actually going
 		// through each repo cache file and finding a matching URL. But basically
@@ -171,7 +240,7 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, ge
 					return u, nil, err
 				}
 				g, err := getterConstructor(ref, "", "", "")
-				if t, ok := g.(*getter.HttpGetter); ok {
+				if t, ok := g.(getter.CredentialSetter); ok {
 					t.SetCredentials(c.Username, c.Password)
 				}
 				return u, g, err
@@ -216,9 +285,17 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, ge
 		return u, r.Client, fmt.Errorf("no cached repo found. (try 'helm repo update'). %s", err)
 	}
 
-	cv, err := i.Get(chartName, version)
-	if err != nil {
-		return u, r.Client, fmt.Errorf("chart %q matching version %q not found in %s index. (try 'helm repo update'). %s", chartName, version, r.Config.Name, err)
+	var cv *repo.ChartVersion
+	if digest != "" {
+		cv, err = chartVersionByDigest(i, chartName, digest)
+		if err != nil {
+			return u, r.Client, fmt.Errorf("%s in %s index. (try 'helm repo update')", err, r.Config.Name)
+		}
+	} else {
+		cv, err = i.Get(chartName, pinned)
+		if err != nil {
+			return u, r.Client, fmt.Errorf("chart %q matching version %q not found in %s index. (try 'helm repo update'). %s", chartName, pinned, r.Config.Name, err)
+		}
 	}
 
 	if len(cv.URLs) == 0 {
@@ -248,9 +325,19 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, ge
 	return u, r.Client, nil
 }
 
-// setCredentials if HttpGetter is used, this method sets the configured repository credentials on the HttpGetter.
+// chartVersionByDigest returns the version of a chart whose archive has the given digest.
+func chartVersionByDigest(i *repo.IndexFile, name, digest string) (*repo.ChartVersion, error) {
+	for _, cv := range i.Entries[name] {
+		if cv.Digest == digest {
+			return cv, nil
+		}
+	}
+	return nil, fmt.Errorf("chart %q with digest %s%s not found", name, digestPrefix, digest)
+}
+
+// setCredentials sets the configured repository credentials on getters that accept them.
 func (c *ChartDownloader) setCredentials(r *repo.ChartRepository) {
-	if t, ok := r.Client.(*getter.HttpGetter); ok {
+	if t, ok := r.Client.(getter.CredentialSetter); ok {
 		t.SetCredentials(c.getRepoCredentials(r))
 	}
 }
@@ -277,13 +364,22 @@ func (c *ChartDownloader) getRepoCredentials(r *repo.ChartRepository) (username,
 // It assumes that a chart archive file is accompanied by a provenance file whose
 // name is the archive file name plus the ".prov" extension.
 func VerifyChart(path string, keyring string) (*provenance.Verification, error) {
-	// For now, error out if it's not a tar file.
+	return VerifyChartWithPolicy(path, keyring, provenance.RequireAny)
+}
+
+// VerifyChartWithPolicy verifies a chart like VerifyChart, following the given
+// policy when the provenance file holds several signatures.
+//
+// The signatures are verified against the keyring, then by the given verifiers.
+// If the keyring is empty, only the verifiers are used.
+func VerifyChartWithPolicy(path, keyring string, policy provenance.Policy, verifiers ...provenance.Verifier) (*provenance.Verification, error) {
+	// For now, error out if it's not a chart archive.
 	if fi, err := os.Stat(path); err != nil {
 		return nil, err
 	} else if fi.IsDir() {
 		return nil, errors.New("unpacked charts cannot be verified")
-	} else if !isTar(path) {
-		return nil, errors.New("chart must be a tgz file")
+	} else if !chartutil.IsArchive(path) {
+		return nil, errors.New("chart must be a tgz or zip file")
 	}
 
 	provfile := path + ".prov"
@@ -291,19 +387,14 @@ func VerifyChart(path string, keyring string) (*provenance.Verification, error)
 		return nil, fmt.Errorf("could not load provenance file %s: %s", provfile, err)
 	}
 
-	sig, err := provenance.NewFromKeyring(keyring, "")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load keyring: %s", err)
+	if keyring != "" {
+		sig, err := provenance.NewFromKeyring(keyring, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load keyring: %s", err)
+		}
+		verifiers = append([]provenance.Verifier{sig}, verifiers...)
 	}
-	return sig.Verify(path, provfile)
-}
-
-// isTar tests whether the given file is a tar file.
-//
-// Currently, this simply checks extension, since a subsequent function will
-// untar the file and validate its binary format.
-func isTar(filename string) bool {
-	return strings.ToLower(filepath.Ext(filename)) == ".tgz"
+	return provenance.VerifyWithPolicy(path, provfile, policy, verifiers...)
 }
 
 func pickChartRepositoryConfigByName(name string, cfgs []*repo.Entry) (*repo.Entry, error) {
@@ -350,14 +441,8 @@ func (c *ChartDownloader) scanReposForURL(u string, rf *repo.RepoFile) (*repo.En
 			return nil, fmt.Errorf("no cached repo found. (try 'helm repo update'). %s", err)
 		}
 
-		for _, entry := range i.Entries {
-			for _, ver := range entry {
-				for _, dl := range ver.URLs {
-					if urlutil.Equal(u, dl) {
-						return rc, nil
-					}
-				}
-			}
+		if chartVersionByURL(i, u) != nil {
+			return rc, nil
 		}
 	}
 	// This means that there is no repo file for the given URL.
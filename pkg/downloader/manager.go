@@ -36,6 +36,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/repo"
 	"k8s.io/helm/pkg/resolver"
+	"k8s.io/helm/pkg/trace"
 	"k8s.io/helm/pkg/urlutil"
 )
 
@@ -249,7 +250,12 @@ func (m *Manager) downloadAll(deps []*chartutil.Dependency) error {
 			Password: password,
 		}
 
-		if _, _, err := dl.DownloadTo(churl, "", destPath); err != nil {
+		// A digest pinned in the reference is checked by the downloader.
+		ref := churl
+		if dep.Digest != "" {
+			ref = churl + "@" + dep.Digest
+		}
+		if _, _, err := dl.DownloadTo(ref, "", destPath); err != nil {
 			saveError = fmt.Errorf("could not download %s: %s", churl, err)
 			break
 		}
@@ -302,6 +308,11 @@ func (m *Manager) safeDeleteDep(name, dir string) error {
 		// Only for ErrBadPattern
 		return err
 	}
+	zips, err := filepath.Glob(filepath.Join(dir, name+"-*.zip"))
+	if err != nil {
+		return err
+	}
+	files = append(files, zips...)
 	for _, fname := range files {
 		ch, err := chartutil.LoadFile(fname)
 		if err != nil {
@@ -612,7 +623,7 @@ func writeLock(chartpath string, lock *chartutil.RequirementsLock) error {
 		return err
 	}
 	dest := filepath.Join(chartpath, "requirements.lock")
-	return ioutil.WriteFile(dest, data, 0644)
+	return trace.WriteFile(dest, data, 0644)
 }
 
 // tarFromLocalDir archive a dep chart from local directory and save it into charts/
@@ -0,0 +1,237 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downloader
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/urlutil"
+)
+
+// Resolution explains how a chart reference resolves to the URL of a chart
+// archive.
+type Resolution struct {
+	// Reference is the chart reference, without its pin.
+	Reference string `json:"reference"`
+	// Constraint is the version constraint the chart must satisfy.
+	Constraint string `json:"constraint,omitempty"`
+	// Digest is the SHA256 digest the reference is pinned to.
+	Digest string `json:"digest,omitempty"`
+	// Repositories are the repositories consulted, in order.
+	Repositories []ConsultedRepository `json:"repositories"`
+	// Candidates are the versions of the chart in the repository index,
+	// newest first.
+	Candidates []Candidate `json:"candidates,omitempty"`
+	// Version is the chosen version of the chart, when it is in an index.
+	Version string `json:"version,omitempty"`
+	// URL is the URL the chart archive is downloaded from.
+	URL string `json:"url,omitempty"`
+	// Repository is the repository whose credentials and TLS configuration
+	// are used for the download, if any.
+	Repository string `json:"repository,omitempty"`
+	// Username is the user the chart is downloaded as.
+	Username string `json:"username,omitempty"`
+	// CredentialsFrom is where the credentials come from: "flags" or
+	// "repository".
+	CredentialsFrom string `json:"credentialsFrom,omitempty"`
+	// Password tells whether a password is sent. The password itself is
+	// never part of the resolution.
+	Password bool   `json:"password"`
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	CAFile   string `json:"caFile,omitempty"`
+	// Error is why the reference does not resolve.
+	Error string `json:"error,omitempty"`
+}
+
+// ConsultedRepository describes a repository consulted to resolve a chart
+// reference, and the age of its cached index.
+type ConsultedRepository struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Index is the path of the cached index file.
+	Index string `json:"index"`
+	// Generated is when the repository generated the index.
+	Generated time.Time `json:"generated,omitempty"`
+	// Fetched is when the index was last downloaded, by 'helm repo update'
+	// or 'helm repo add'.
+	Fetched time.Time `json:"fetched,omitempty"`
+	// Error is why the index could not be loaded.
+	Error string `json:"error,omitempty"`
+}
+
+// Candidate is a version of a chart a reference may resolve to.
+type Candidate struct {
+	Version    string    `json:"version"`
+	AppVersion string    `json:"appVersion,omitempty"`
+	Created    time.Time `json:"created,omitempty"`
+	// Matches tells whether the version satisfies the constraint or the
+	// digest of the reference.
+	Matches bool `json:"matches"`
+	// Chosen tells whether this is the version the reference resolves to.
+	Chosen bool `json:"chosen"`
+}
+
+// Explain resolves a chart reference like ResolveChartVersion does, and
+// records the repositories it consults, the versions it chooses from and the
+// settings used to download the chart.
+//
+// The resolution is returned even when the reference does not resolve, along
+// with the error.
+func (c *ChartDownloader) Explain(ref, version string) (*Resolution, error) {
+	name, constraint, digest, err := ParseChartReference(ref, version)
+	res := &Resolution{Reference: name, Constraint: constraint, Digest: digest}
+	if err != nil {
+		return res.fail(err)
+	}
+
+	rf, err := repo.LoadRepositoriesFile(c.HelmHome.RepositoryFile())
+	if err != nil {
+		return res.fail(err)
+	}
+
+	var owner *repo.Entry
+	if u, err := url.Parse(name); err == nil && u.IsAbs() && len(u.Host) > 0 && len(u.Path) > 0 {
+		// The repository holding the URL provides the settings of the
+		// download, the repositories are searched in order.
+		for _, rc := range rf.Repositories {
+			i := res.consult(c, rc)
+			if i == nil {
+				break
+			}
+			if cv := chartVersionByURL(i, name); cv != nil {
+				owner = rc
+				res.Version = cv.Version
+				break
+			}
+		}
+	} else if p := strings.SplitN(name, "/", 2); len(p) == 2 {
+		if rc, err := pickChartRepositoryConfigByName(p[0], rf.Repositories); err == nil {
+			owner = rc
+			if i := res.consult(c, rc); i != nil {
+				res.candidates(i, p[1])
+			}
+		}
+	}
+
+	if owner != nil {
+		res.Repository = owner.Name
+		res.CertFile, res.KeyFile, res.CAFile = owner.CertFile, owner.KeyFile, owner.CAFile
+	}
+	switch {
+	case c.Username != "":
+		res.Username, res.CredentialsFrom = c.Username, "flags"
+	case owner != nil && owner.Username != "":
+		res.Username, res.CredentialsFrom = owner.Username, "repository"
+	}
+	res.Password = c.Password != "" || (owner != nil && owner.Password != "")
+
+	// The URL comes from the downloader itself, so that the explanation
+	// cannot disagree with it.
+	u, _, err := c.ResolveChartVersion(ref, version)
+	if err != nil {
+		return res.fail(err)
+	}
+	res.URL = u.String()
+	return res, nil
+}
+
+func (r *Resolution) fail(err error) (*Resolution, error) {
+	r.Error = err.Error()
+	return r, err
+}
+
+// consult records the repository and loads its cached index, which is nil if
+// it cannot be loaded.
+func (r *Resolution) consult(c *ChartDownloader, rc *repo.Entry) *repo.IndexFile {
+	cr := ConsultedRepository{
+		Name:  rc.Name,
+		URL:   rc.URL,
+		Index: c.HelmHome.CacheIndex(rc.Name),
+	}
+	defer func() { r.Repositories = append(r.Repositories, cr) }()
+
+	if fi, err := os.Stat(cr.Index); err == nil {
+		cr.Fetched = fi.ModTime()
+	}
+	i, err := repo.LoadIndexFile(cr.Index)
+	if err != nil {
+		cr.Error = err.Error()
+		return nil
+	}
+	cr.Generated = i.Generated
+	return i
+}
+
+// candidates records the versions of the chart in the index, marking the
+// ones matching the reference and the one chosen like IndexFile.Get does.
+func (r *Resolution) candidates(i *repo.IndexFile, name string) {
+	var chosen *repo.ChartVersion
+	if r.Digest != "" {
+		chosen, _ = chartVersionByDigest(i, name, r.Digest)
+	} else {
+		chosen, _ = i.Get(name, r.Constraint)
+	}
+
+	constraint, err := semver.NewConstraint("*")
+	if r.Constraint != "" {
+		constraint, err = semver.NewConstraint(r.Constraint)
+	}
+	for _, cv := range i.Entries[name] {
+		c := Candidate{
+			Version: cv.Version,
+			Created: cv.Created,
+			Chosen:  cv == chosen,
+		}
+		if cv.Metadata != nil {
+			c.AppVersion = cv.AppVersion
+		}
+		switch {
+		case r.Digest != "":
+			c.Matches = cv.Digest == r.Digest
+		case cv.Version == r.Constraint:
+			c.Matches = true
+		case err == nil:
+			v, verr := semver.NewVersion(cv.Version)
+			c.Matches = verr == nil && constraint.Check(v)
+		}
+		r.Candidates = append(r.Candidates, c)
+	}
+	if chosen != nil {
+		r.Version = chosen.Version
+	}
+}
+
+// chartVersionByURL returns the version of a chart downloaded from the URL.
+func chartVersionByURL(i *repo.IndexFile, u string) *repo.ChartVersion {
+	for _, entry := range i.Entries {
+		for _, cv := range entry {
+			for _, dl := range cv.URLs {
+				if urlutil.Equal(u, dl) {
+					return cv
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downloader
+
+import (
+	"os"
+	"testing"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/helm/helmpath"
+)
+
+func TestExplain(t *testing.T) {
+	c := ChartDownloader{
+		HelmHome: helmpath.Home("testdata/helmhome"),
+		Out:      os.Stderr,
+		Getters:  getter.All(environment.EnvSettings{}),
+		Username: "bob",
+	}
+
+	res, err := c.Explain("testing/alpine", "<1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Version != "0.2.0" || res.URL != "http://example.com/alpine-0.2.0.tgz" {
+		t.Errorf("Expected version 0.2.0 at http://example.com/alpine-0.2.0.tgz, got %s at %s", res.Version, res.URL)
+	}
+	if len(res.Repositories) != 1 || res.Repositories[0].Name != "testing" || res.Repositories[0].Fetched.IsZero() {
+		t.Errorf("Expected the fetched testing repository to be consulted, got %+v", res.Repositories)
+	}
+	if len(res.Candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %+v", res.Candidates)
+	}
+	if c := res.Candidates[0]; c.Version != "1.2.3" || c.Matches || c.Chosen {
+		t.Errorf("Expected 1.2.3 not to match, got %+v", c)
+	}
+	if c := res.Candidates[1]; c.Version != "0.2.0" || !c.Matches || !c.Chosen {
+		t.Errorf("Expected 0.2.0 to be chosen, got %+v", c)
+	}
+	if res.Repository != "testing" || res.Username != "bob" || res.CredentialsFrom != "flags" || res.Password {
+		t.Errorf("Unexpected download settings %+v", res)
+	}
+
+	res, err = c.Explain("https://kubernetes-charts.storage.googleapis.com/alpine-0.2.0.tgz", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Repository != "testing" || res.Version != "0.2.0" {
+		t.Errorf("Expected the URL to be found in the testing repository, got %+v", res)
+	}
+
+	res, err = c.Explain("testing/alpine", ">5.0.0")
+	if err == nil {
+		t.Fatal("Expected no version to match")
+	}
+	if res.Error == "" || len(res.Candidates) != 2 || res.Version != "" {
+		t.Errorf("Expected the candidates and the error, got %+v", res)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"sync"
+	"text/template"
+)
+
+// TemplateCache keeps the parsed templates of the charts rendered most
+// recently, so that rendering the same chart again, for instance to upgrade
+// many releases of one chart version, skips parsing its templates.
+//
+// Charts are identified by a digest of their templates, so a cached entry is
+// never used for a chart whose templates changed. It is safe for concurrent
+// use.
+type TemplateCache struct {
+	mu      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+
+	hits, misses int
+}
+
+type templateCacheEntry struct {
+	key string
+	t   *template.Template
+}
+
+// NewTemplateCache creates a cache holding the templates of up to size charts.
+func NewTemplateCache(size int) *TemplateCache {
+	return &TemplateCache{
+		size:    size,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// key returns the digest identifying the templates, or an empty string for a
+// nil cache.
+func (c *TemplateCache) key(tpls map[string]renderable, strict bool) string {
+	if c == nil {
+		return ""
+	}
+	h := sha256.New()
+	if strict {
+		io.WriteString(h, "strict\x00")
+	}
+	names := make([]string, 0, len(tpls))
+	for name := range tpls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		io.WriteString(h, name)
+		io.WriteString(h, "\x00")
+		io.WriteString(h, tpls[name].tpl)
+		io.WriteString(h, "\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get returns a copy of the templates cached for key, or nil. The functions
+// of the copy can be replaced without affecting the cached templates.
+func (c *TemplateCache) get(key string) (*template.Template, error) {
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		c.mu.Unlock()
+		return nil, nil
+	}
+	c.hits++
+	c.lru.MoveToFront(el)
+	t := el.Value.(*templateCacheEntry).t
+	c.mu.Unlock()
+	return t.Clone()
+}
+
+// add caches a copy of the templates t for key, evicting the least recently
+// used entry if the cache is full.
+func (c *TemplateCache) add(key string, t *template.Template) error {
+	cached, err := t.Clone()
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*templateCacheEntry).t = cached
+		c.lru.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.lru.PushFront(&templateCacheEntry{key: key, t: cached})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*templateCacheEntry).key)
+	}
+	return nil
+}
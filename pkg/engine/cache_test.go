@@ -0,0 +1,89 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+)
+
+func cachedTemplates(name string) map[string]renderable {
+	vals := chartutil.Values{"Values": map[string]interface{}{"name": name}}
+	return map[string]renderable{
+		"moby/templates/_helpers": {tpl: `{{ define "greeting" }}Hello {{ .Values.name }}{{ end }}`, vals: vals, basePath: "moby/templates"},
+		"moby/templates/main":     {tpl: `{{ include "greeting" . }} {{ tpl "{{ .Values.name | upper }}" . }} {{ required "name" .Values.name }}`, vals: vals, basePath: "moby/templates"},
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	e := New()
+	e.Templates = NewTemplateCache(1)
+
+	for _, name := range []string{"ishmael", "ahab"} {
+		out, err := e.render(cachedTemplates(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := fmt.Sprintf("Hello %s %s %s", name, map[string]string{"ishmael": "ISHMAEL", "ahab": "AHAB"}[name], name)
+		if out["moby/templates/main"] != expect {
+			t.Errorf("Expected %q, got %q", expect, out["moby/templates/main"])
+		}
+	}
+	if e.Templates.hits != 1 || e.Templates.misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d and %d", e.Templates.hits, e.Templates.misses)
+	}
+
+	// Other templates, or the same ones rendered strictly, are not the same
+	// entry, and evict the previous one.
+	e.Strict = true
+	if _, err := e.render(cachedTemplates("starbuck")); err != nil {
+		t.Fatal(err)
+	}
+	if e.Templates.misses != 2 || e.Templates.lru.Len() != 1 {
+		t.Errorf("Expected a miss evicting the previous entry, got %d misses and %d entries", e.Templates.misses, e.Templates.lru.Len())
+	}
+}
+
+func TestTemplateCacheParallel(t *testing.T) {
+	e := New()
+	e.Templates = NewTemplateCache(10)
+	if _, err := e.render(cachedTemplates("warmup")); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("sailor%d", i)
+			out, err := e.render(cachedTemplates(name))
+			if err != nil {
+				t.Errorf("Failed to render %s: %s", name, err)
+				return
+			}
+			if expect := fmt.Sprintf("Hello %s ", name); !strings.HasPrefix(out["moby/templates/main"], expect) {
+				t.Errorf("Expected %q, got %q", expect, out["moby/templates/main"])
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,100 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"text/template"
+	"time"
+)
+
+const (
+	alphaChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	numericChars = "0123456789"
+)
+
+// deterministicFuncs returns the functions replacing the time dependent and
+// random functions of the FuncMap when the engine pins them. It returns nil
+// if nothing is pinned.
+//
+// The random functions of a render share one source, so a render draws the
+// same sequence of values every time. Templates are executed in a stable
+// order, which makes the values each template gets stable too.
+func (e *Engine) deterministicFuncs() template.FuncMap {
+	if e.RenderTime.IsZero() && e.RandomSeed == nil {
+		return nil
+	}
+	f := template.FuncMap{}
+
+	if !e.RenderTime.IsZero() {
+		now := e.RenderTime
+		f["now"] = func() time.Time { return now }
+		f["ago"] = func(date interface{}) string {
+			var t time.Time
+			switch date := date.(type) {
+			case time.Time:
+				t = date
+			case int64:
+				t = time.Unix(date, 0)
+			case int:
+				t = time.Unix(int64(date), 0)
+			case int32:
+				t = time.Unix(int64(date), 0)
+			default:
+				t = now
+			}
+			return now.Sub(t).Round(time.Second).String()
+		}
+	}
+
+	if e.RandomSeed != nil {
+		r := rand.New(rand.NewSource(*e.RandomSeed))
+		f["randAlphaNum"] = func(n int) string { return randString(r, n, alphaChars+numericChars) }
+		f["randAlpha"] = func(n int) string { return randString(r, n, alphaChars) }
+		f["randNumeric"] = func(n int) string { return randString(r, n, numericChars) }
+		f["randAscii"] = func(n int) string {
+			b := make([]byte, n)
+			for i := range b {
+				// printable characters, from space to tilde
+				b[i] = byte(32 + r.Intn(95))
+			}
+			return string(b)
+		}
+		f["uuidv4"] = func() string {
+			var u [16]byte
+			r.Read(u[:])
+			u[6] = (u[6] & 0x0f) | 0x40 // version 4
+			u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+			return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+		}
+		f["shuffle"] = func(s string) string {
+			runes := []rune(s)
+			r.Shuffle(len(runes), func(i, j int) { runes[i], runes[j] = runes[j], runes[i] })
+			return string(runes)
+		}
+	}
+	return f
+}
+
+func randString(r *rand.Rand, n int, chars string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = chars[r.Intn(len(chars))]
+	}
+	return string(b)
+}
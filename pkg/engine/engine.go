@@ -21,9 +21,12 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig"
 
@@ -41,6 +44,27 @@ type Engine struct {
 	Strict bool
 	// In LintMode, some 'required' template values may be missing, so don't fail
 	LintMode bool
+	// RenderTime, if set, is the time returned by "now" and used by "ago",
+	// so that renders do not depend on when they happen.
+	RenderTime time.Time
+	// RandomSeed, if set, seeds the random functions such as "randAlphaNum"
+	// and "uuidv4", so that renders are reproducible. The keys and
+	// certificates of "genPrivateKey", "genCA" and the like stay random.
+	RandomSeed *int64
+	// Lookup, if set, gives the "lookup" function access to the cluster.
+	// Without it, "lookup" finds no objects.
+	Lookup LookupFunc
+	// EnableDNSLookups lets "getHostByName" resolve names. Without it,
+	// "getHostByName" returns an empty string.
+	EnableDNSLookups bool
+	// Templates, if set, caches the parsed templates of the charts rendered.
+	// The templates given to "tpl" are not cached.
+	Templates *TemplateCache
+	// Parallelism is the number of templates of a chart that are rendered
+	// at the same time. Zero means one per CPU, and one renders them one
+	// after another. Templates are always rendered one after another when
+	// RandomSeed is set, so that the random values do not depend on timing.
+	Parallelism int
 }
 
 // New creates a new Go template Engine instance.
@@ -71,6 +95,11 @@ func New() *Engine {
 //	   included in the FuncMap is a placeholder.
 //      - "tpl": This is late-bound in Engine.Render(). The version
 //	   included in the FuncMap is a placeholder.
+//      - "lookup": This is late-bound in Engine.Render() if the Engine has a
+//	   Lookup. The version included in the FuncMap finds no objects.
+//      - "getHostByName": This is late-bound in Engine.Render() if the Engine
+//	   has EnableDNSLookups. The version included in the FuncMap does not
+//	   resolve names.
 func FuncMap() template.FuncMap {
 	f := sprig.TxtFuncMap()
 	delete(f, "env")
@@ -78,11 +107,29 @@ func FuncMap() template.FuncMap {
 
 	// Add some extra functionality
 	extra := template.FuncMap{
-		"toToml":   chartutil.ToToml,
-		"toYaml":   chartutil.ToYaml,
-		"fromYaml": chartutil.FromYaml,
-		"toJson":   chartutil.ToJson,
-		"fromJson": chartutil.FromJson,
+		"toToml":        chartutil.ToToml,
+		"toYaml":        chartutil.ToYaml,
+		"fromYaml":      chartutil.FromYaml,
+		"fromYamlArray": chartutil.FromYamlArray,
+		"toJson":        chartutil.ToJson,
+		"fromJson":      chartutil.FromJson,
+		"fromJsonArray": chartutil.FromJsonArray,
+
+		"cidrHost":     cidrHost,
+		"cidrSubnet":   cidrSubnet,
+		"cidrNetmask":  cidrNetmask,
+		"cidrContains": cidrContains,
+
+		"regexSplit":                 regexSplit,
+		"mustRegexMatch":             mustRegexMatch,
+		"mustRegexFind":              mustRegexFind,
+		"mustRegexFindAll":           mustRegexFindAll,
+		"mustRegexReplaceAll":        mustRegexReplaceAll,
+		"mustRegexReplaceAllLiteral": mustRegexReplaceAllLiteral,
+		"mustRegexSplit":             mustRegexSplit,
+
+		"lookup":        emptyLookup,
+		"getHostByName": func(string) string { return "" },
 
 		// This is a placeholder for the "include" function, which is
 		// late-bound to a template. By declaring it here, we preserve the
@@ -132,17 +179,29 @@ type renderable struct {
 	vals chartutil.Values
 	// basePath namespace prefix to the templates of the current chart
 	basePath string
+	// library is true if the template belongs to a library chart. Library
+	// templates are only included from other templates.
+	library bool
 }
 
 // alterFuncMap takes the Engine's FuncMap and adds context-specific functions.
 //
 // The resulting FuncMap is only valid for the passed-in template.
-func (e *Engine) alterFuncMap(t *template.Template, referenceTpls map[string]renderable) template.FuncMap {
+func (e *Engine) alterFuncMap(t *template.Template, referenceTpls map[string]renderable, pinned template.FuncMap) template.FuncMap {
 	// Clone the func map because we are adding context-specific functions.
 	var funcMap template.FuncMap = map[string]interface{}{}
 	for k, v := range e.FuncMap {
 		funcMap[k] = v
 	}
+	for k, v := range pinned {
+		funcMap[k] = v
+	}
+	if e.Lookup != nil {
+		funcMap["lookup"] = e.Lookup
+	}
+	if e.EnableDNSLookups {
+		funcMap["getHostByName"] = getHostByName
+	}
 
 	// Add the 'include' function here so we can close over t.
 	funcMap["include"] = func(name string, data interface{}) (string, error) {
@@ -197,7 +256,7 @@ func (e *Engine) alterFuncMap(t *template.Template, referenceTpls map[string]ren
 
 		templates[templateName.(string)] = r
 
-		result, err := e.renderWithReferences(templates, referenceTpls)
+		result, err := e.renderWithReferences(templates, referenceTpls, pinned, "")
 		if err != nil {
 			return "", fmt.Errorf("Error during tpl function execution for %q: %s", tpl, err.Error())
 		}
@@ -209,12 +268,14 @@ func (e *Engine) alterFuncMap(t *template.Template, referenceTpls map[string]ren
 
 // render takes a map of templates/values and renders them.
 func (e *Engine) render(tpls map[string]renderable) (rendered map[string]string, err error) {
-	return e.renderWithReferences(tpls, tpls)
+	return e.renderWithReferences(tpls, tpls, e.deterministicFuncs(), e.Templates.key(tpls, e.Strict))
 }
 
 // renderWithReferences takes a map of templates/values to render, and a map of
-// templates which can be referenced within them.
-func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls map[string]renderable) (rendered map[string]string, err error) {
+// templates which can be referenced within them. The pinned functions replace
+// those of the FuncMap. A non-empty cacheKey identifies the templates in the
+// template cache.
+func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls map[string]renderable, pinned template.FuncMap, cacheKey string) (rendered map[string]string, err error) {
 	// Basically, what we do here is start with an empty parent template and then
 	// build up a list of templates -- one for each file. Once all of the templates
 	// have been parsed, we loop through again and execute every template.
@@ -227,6 +288,163 @@ func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls
 			err = fmt.Errorf("rendering template failed: %v", r)
 		}
 	}()
+	var t *template.Template
+	if cacheKey != "" {
+		if t, err = e.Templates.get(cacheKey); err != nil {
+			return map[string]string{}, err
+		}
+	}
+	if t != nil {
+		// The cached templates get the functions bound to this render.
+		t.Funcs(e.alterFuncMap(t, referenceTpls, pinned))
+	} else {
+		if t, err = e.parse(tpls, referenceTpls, pinned); err != nil {
+			return map[string]string{}, err
+		}
+		if cacheKey != "" {
+			if err := e.Templates.add(cacheKey, t); err != nil {
+				return map[string]string{}, err
+			}
+		}
+	}
+
+	// We want to render the templates in a predictable order. The order favors
+	// higher-level (in file system) templates over deeply nested templates.
+	files := sortTemplates(tpls)
+
+	// Don't render partials. We don't care out the direct output of partials.
+	// They are only included from other templates.
+	toRender := make([]string, 0, len(files))
+	for _, file := range files {
+		if strings.HasPrefix(path.Base(file), "_") || tpls[file].library {
+			continue
+		}
+		toRender = append(toRender, file)
+	}
+
+	outs := make([]string, len(toRender))
+	errs := make([]error, len(toRender))
+	workers := e.workers(len(toRender))
+	render := func(i int) {
+		outs[i], errs[i] = e.execute(t, toRender[i], tpls, referenceTpls, workers > 1)
+	}
+	if workers <= 1 {
+		for i := range toRender {
+			if render(i); errs[i] != nil {
+				break
+			}
+		}
+	} else {
+		next := make(chan int)
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					render(i)
+				}
+			}()
+		}
+		for i := range toRender {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	// Report the error of the first template in render order, whichever
+	// template failed first.
+	for _, err := range errs {
+		if err != nil {
+			return map[string]string{}, err
+		}
+	}
+	rendered = make(map[string]string, len(toRender))
+	for i, file := range toRender {
+		rendered[file] = outs[i]
+	}
+	return rendered, nil
+}
+
+// workers returns the number of templates to render at the same time.
+func (e *Engine) workers(templates int) int {
+	if e.RandomSeed != nil {
+		return 1
+	}
+	n := e.Parallelism
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	if n > templates {
+		n = templates
+	}
+	return n
+}
+
+// execute renders a single template file of the template set.
+//
+// When copyVals is set, the template is given its own copy of the values,
+// so that templates rendered at the same time cannot see or race on the
+// changes the others make to them (e.g. with "set").
+func (e *Engine) execute(t *template.Template, file string, tpls map[string]renderable, referenceTpls map[string]renderable, copyVals bool) (out string, err error) {
+	// Panics happening in another goroutine would not be recovered by
+	// renderWithReferences.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rendering template failed: %v", r)
+		}
+	}()
+	// At render time, add information about the template that is being rendered.
+	vals := tpls[file].vals
+	if copyVals {
+		vals = copyValues(vals)
+	}
+	vals["Template"] = map[string]interface{}{"Name": file, "BasePath": tpls[file].basePath}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, file, vals); err != nil {
+		return "", fmt.Errorf("render error in %q: %s", file, describeError(err, tpls, referenceTpls))
+	}
+
+	// Work around the issue where Go will emit "<no value>" even if Options(missing=zero)
+	// is set. Since missing=error will never get here, we do not need to handle
+	// the Strict case.
+	return strings.Replace(buf.String(), "<no value>", "", -1), nil
+}
+
+// copyValues returns a copy of the values, in which the maps and lists are
+// copied too. Other values, such as the chart metadata, are shared.
+func copyValues(vals chartutil.Values) chartutil.Values {
+	return copyValue(vals).(chartutil.Values)
+}
+
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case chartutil.Values:
+		c := make(chartutil.Values, len(v))
+		for k, val := range v {
+			c[k] = copyValue(val)
+		}
+		return c
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			c[k] = copyValue(val)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, val := range v {
+			c[i] = copyValue(val)
+		}
+		return c
+	}
+	return v
+}
+
+// parse parses the templates to render and the ones they can reference into a
+// template set.
+func (e *Engine) parse(tpls map[string]renderable, referenceTpls map[string]renderable, pinned template.FuncMap) (*template.Template, error) {
 	t := template.New("gotpl")
 	if e.Strict {
 		t.Option("missingkey=error")
@@ -236,21 +454,22 @@ func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls
 		t.Option("missingkey=zero")
 	}
 
-	funcMap := e.alterFuncMap(t, referenceTpls)
+	funcMap := e.alterFuncMap(t, referenceTpls, pinned)
+
+	// The builtin helpers come first, so that the charts can replace them.
+	t = t.New(HelpersName).Funcs(funcMap)
+	if _, err := t.Parse(helpersTemplate); err != nil {
+		return nil, fmt.Errorf("parse error in %q: %s", HelpersName, err)
+	}
 
 	// We want to parse the templates in a predictable order. The order favors
 	// higher-level (in file system) templates over deeply nested templates.
-	keys := sortTemplates(tpls)
-
-	files := []string{}
-
-	for _, fname := range keys {
+	for _, fname := range sortTemplates(tpls) {
 		r := tpls[fname]
 		t = t.New(fname).Funcs(funcMap)
 		if _, err := t.Parse(r.tpl); err != nil {
-			return map[string]string{}, fmt.Errorf("parse error in %q: %s", fname, err)
+			return nil, fmt.Errorf("parse error in %q: %s", fname, describeError(err, tpls))
 		}
-		files = append(files, fname)
 	}
 
 	// Adding the reference templates to the template context
@@ -259,34 +478,11 @@ func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls
 		if t.Lookup(fname) == nil {
 			t = t.New(fname).Funcs(funcMap)
 			if _, err := t.Parse(r.tpl); err != nil {
-				return map[string]string{}, fmt.Errorf("parse error in %q: %s", fname, err)
+				return nil, fmt.Errorf("parse error in %q: %s", fname, describeError(err, referenceTpls))
 			}
 		}
 	}
-
-	rendered = make(map[string]string, len(files))
-	var buf bytes.Buffer
-	for _, file := range files {
-		// Don't render partials. We don't care out the direct output of partials.
-		// They are only included from other templates.
-		if strings.HasPrefix(path.Base(file), "_") {
-			continue
-		}
-		// At render time, add information about the template that is being rendered.
-		vals := tpls[file].vals
-		vals["Template"] = map[string]interface{}{"Name": file, "BasePath": tpls[file].basePath}
-		if err := t.ExecuteTemplate(&buf, file, vals); err != nil {
-			return map[string]string{}, fmt.Errorf("render error in %q: %s", file, err)
-		}
-
-		// Work around the issue where Go will emit "<no value>" even if Options(missing=zero)
-		// is set. Since missing=error will never get here, we do not need to handle
-		// the Strict case.
-		rendered[file] = strings.Replace(buf.String(), "<no value>", "", -1)
-		buf.Reset()
-	}
-
-	return rendered, nil
+	return t, nil
 }
 
 func sortTemplates(tpls map[string]renderable) []string {
@@ -364,11 +560,13 @@ func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals char
 	for _, child := range c.Dependencies {
 		recAllTpls(child, templates, cvals, false, newParentID)
 	}
+	library := chartutil.IsLibraryChart(c)
 	for _, t := range c.Templates {
 		templates[path.Join(newParentID, t.Name)] = renderable{
 			tpl:      string(t.Data),
 			vals:     cvals,
 			basePath: path.Join(newParentID, "templates"),
+			library:  library,
 		}
 	}
 }
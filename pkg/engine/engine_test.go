@@ -18,8 +18,10 @@ package engine
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
@@ -80,7 +82,7 @@ func TestFuncMap(t *testing.T) {
 	}
 
 	// Test for Engine-specific template functions.
-	expect := []string{"include", "required", "tpl", "toYaml", "fromYaml", "toToml", "toJson", "fromJson"}
+	expect := []string{"include", "required", "tpl", "toYaml", "fromYaml", "fromYamlArray", "toToml", "toJson", "fromJson", "fromJsonArray", "lookup", "getHostByName"}
 	for _, f := range expect {
 		if _, ok := fns[f]; !ok {
 			t.Errorf("Expected add-on function %q", f)
@@ -176,6 +178,45 @@ func TestRenderInternals(t *testing.T) {
 	}
 }
 
+func TestRenderDeterministic(t *testing.T) {
+	seed := int64(42)
+	e := New()
+	e.RenderTime = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	e.RandomSeed = &seed
+
+	vals := chartutil.Values{}
+	tpls := map[string]renderable{
+		"time":   {tpl: `{{ now | date "2006-01-02" }} {{ ago now }}`, vals: vals},
+		"random": {tpl: `{{ randAlphaNum 16 }} {{ uuidv4 }} {{ tpl "{{ randNumeric 4 }}" . }}`, vals: vals},
+	}
+
+	first, err := e.render(tpls)
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if first["time"] != "2019-01-01 0s" {
+		t.Errorf("Expected the pinned time, got %q", first["time"])
+	}
+
+	second, err := e.render(tpls)
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if first["random"] != second["random"] {
+		t.Errorf("Expected renders with the same seed to match, got %q and %q", first["random"], second["random"])
+	}
+
+	other := int64(43)
+	e.RandomSeed = &other
+	third, err := e.render(tpls)
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if first["random"] == third["random"] {
+		t.Errorf("Expected renders with different seeds to differ, got %q", third["random"])
+	}
+}
+
 func TestParallelRenderInternals(t *testing.T) {
 	// Make sure that we can use one Engine to run parallel template renders.
 	e := New()
@@ -200,6 +241,56 @@ func TestParallelRenderInternals(t *testing.T) {
 	wg.Wait()
 }
 
+func TestRenderParallelism(t *testing.T) {
+	e := New()
+	e.Parallelism = 8
+
+	vals := chartutil.Values{"Values": map[string]interface{}{"count": 0}}
+	tpls := map[string]renderable{}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("mychart/templates/cm-%02d.yaml", i)
+		tpls[name] = renderable{tpl: `{{ $_ := set .Values "count" (add1 .Values.count) }}{{ .Template.Name }} {{ .Values.count }}`, vals: vals}
+	}
+	out, err := e.render(tpls)
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if len(out) != len(tpls) {
+		t.Fatalf("Expected %d templates, got %d", len(tpls), len(out))
+	}
+	for name := range tpls {
+		// Each template changes its own copy of the values.
+		if expect := name + " 1"; out[name] != expect {
+			t.Errorf("Expected %q, got %q", expect, out[name])
+		}
+	}
+	if count := vals["Values"].(map[string]interface{})["count"]; count != 0 {
+		t.Errorf("Expected the values to be left unchanged, got count %v", count)
+	}
+
+	// The error reported is the one of the first template in render order.
+	failing := []string{"mychart/templates/cm-10.yaml", "mychart/templates/cm-40.yaml"}
+	for _, name := range failing {
+		tpls[name] = renderable{tpl: fmt.Sprintf(`{{ required %q .Values.missing }}`, name+" failed"), vals: vals}
+	}
+	var first string
+	for _, name := range sortTemplates(tpls) {
+		if name == failing[0] || name == failing[1] {
+			first = name
+			break
+		}
+	}
+	for i := 0; i < 10; i++ {
+		_, err := e.render(tpls)
+		if err == nil {
+			t.Fatal("Expected rendering to fail")
+		}
+		if !strings.Contains(err.Error(), first+" failed") {
+			t.Fatalf("Expected the error of %s, got %q", first, err)
+		}
+	}
+}
+
 func TestAllTemplates(t *testing.T) {
 	ch1 := &chart.Chart{
 		Metadata: &chart.Metadata{Name: "ch1"},
@@ -267,6 +358,43 @@ func TestRenderDependency(t *testing.T) {
 
 }
 
+func TestRenderLibraryDependency(t *testing.T) {
+	e := New()
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "outerchart"},
+		Templates: []*chart.Template{
+			{Name: "templates/outer", Data: []byte(`Hello {{include "common.name" .}}`)},
+		},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata: &chart.Metadata{Name: "common", Type: chartutil.ChartTypeLibrary},
+				Templates: []*chart.Template{
+					{Name: "templates/_names.tpl", Data: []byte(`{{define "common.name"}}{{.Chart.Name}}{{end}}`)},
+					{Name: "templates/configmap.yaml", Data: []byte(`{{define "common.configmap"}}kind: ConfigMap{{end}}`)},
+				},
+			},
+		},
+	}
+
+	vals, err := chartutil.ToRenderValues(ch, &chart.Config{}, chartutil.ReleaseOptions{})
+	if err != nil {
+		t.Fatalf("failed to build the render values: %s", err)
+	}
+
+	out, err := e.Render(ch, vals)
+	if err != nil {
+		t.Fatalf("failed to render chart: %s", err)
+	}
+
+	if len(out) != 1 {
+		t.Errorf("Expected only the templates of the application chart to be rendered, got %v", out)
+	}
+	expect := "Hello outerchart"
+	if out["outerchart/templates/outer"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["outerchart/templates/outer"])
+	}
+}
+
 func TestRenderNestedValues(t *testing.T) {
 	e := New()
 
@@ -589,3 +717,38 @@ func TestAlterFuncMap(t *testing.T) {
 	}
 
 }
+
+func TestRenderErrorDescription(t *testing.T) {
+	e := New()
+	e.Strict = true
+
+	vals := chartutil.Values{"Values": map[string]interface{}{}}
+	tpls := map[string]renderable{
+		"mychart/templates/cm.yaml": {tpl: "kind: ConfigMap\ndata:\n  mode: {{ .Values.mode }}\n", vals: vals},
+	}
+	_, err := e.render(tpls)
+	if err == nil {
+		t.Fatal("Expected strict rendering to fail on a missing value")
+	}
+	for _, expect := range []string{
+		`render error in "mychart/templates/cm.yaml": mychart/templates/cm.yaml:3:`,
+		`at <.Values.mode>: map has no entry for key "mode"`,
+		"\n\t3 | mode: {{ .Values.mode }}",
+	} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("Expected %q to contain %q", err.Error(), expect)
+		}
+	}
+
+	tpls = map[string]renderable{
+		"mychart/templates/cm.yaml": {tpl: "kind: ConfigMap\ndata: {{ nope }}\n", vals: vals},
+	}
+	_, err = e.render(tpls)
+	if err == nil {
+		t.Fatal("Expected an undefined function to fail parsing")
+	}
+	expect := "parse error in \"mychart/templates/cm.yaml\": mychart/templates/cm.yaml:2: function \"nope\" not defined\n\t2 | data: {{ nope }}"
+	if err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err.Error())
+	}
+}
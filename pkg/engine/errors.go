@@ -0,0 +1,94 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxSnippetLength is the length from which the template line quoted in an
+// error is truncated.
+const maxSnippetLength = 120
+
+var (
+	// execErrorRe matches the errors of text/template executions, such as
+	//   template: mychart/templates/cm.yaml:5:20: executing "mychart/templates/cm.yaml" at <.Values.foo>: map has no entry for key "foo"
+	execErrorRe = regexp.MustCompile(`(?s)^template: (.+?):(\d+):(\d+): executing "[^"]*" at <(.*?)>: (.*)$`)
+	// parseErrorRe matches the errors of text/template parsing, such as
+	//   template: mychart/templates/cm.yaml:5: function "foo" not defined
+	parseErrorRe = regexp.MustCompile(`(?s)^template: (.+?):(\d+): (.*)$`)
+)
+
+// describeError rewrites a text/template error as the location of the error,
+// the offending expression and the cause, followed by the line of the
+// template the error is on. The line is searched for in tpls. Errors that are
+// not text/template errors are returned as they are.
+func describeError(err error, tpls ...map[string]renderable) string {
+	msg := err.Error()
+
+	var file, line, col, expr, cause string
+	if m := execErrorRe.FindStringSubmatch(msg); m != nil {
+		file, line, col, expr, cause = m[1], m[2], m[3], m[4], m[5]
+	} else if m := parseErrorRe.FindStringSubmatch(msg); m != nil {
+		file, line, cause = m[1], m[2], m[3]
+	} else {
+		return msg
+	}
+
+	location := file + ":" + line
+	if col != "" {
+		location += ":" + col
+	}
+	desc := location + ": "
+	if expr != "" {
+		desc += fmt.Sprintf("at <%s>: ", expr)
+	}
+	desc += cause
+
+	if snippet := sourceLine(file, line, tpls); snippet != "" {
+		desc += fmt.Sprintf("\n\t%s | %s", line, snippet)
+	}
+	return desc
+}
+
+// sourceLine returns the line of the named template, or an empty string if
+// the template is not in tpls.
+func sourceLine(file, line string, tpls []map[string]renderable) string {
+	n, err := strconv.Atoi(line)
+	if err != nil {
+		return ""
+	}
+	for _, m := range tpls {
+		r, ok := m[file]
+		if !ok {
+			continue
+		}
+		lines := strings.Split(r.tpl, "\n")
+		if n < 1 || n > len(lines) {
+			return ""
+		}
+		snippet := strings.TrimSpace(lines[n-1])
+		if len(snippet) > maxSnippetLength {
+			snippet = snippet[:maxSnippetLength] + "..."
+		}
+		return snippet
+	}
+	return ""
+}
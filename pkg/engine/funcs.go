@@ -0,0 +1,164 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"regexp"
+)
+
+// LookupFunc returns the live object of the given apiVersion, kind, namespace
+// and name, or the list of those objects if name is empty. It backs the
+// "lookup" template function.
+type LookupFunc func(apiVersion, kind, namespace, name string) (map[string]interface{}, error)
+
+// emptyLookup is the "lookup" function of an engine without cluster access:
+// no object exists.
+func emptyLookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+// getHostByName returns the first address name resolves to, or an empty
+// string if it does not resolve.
+func getHostByName(name string) string {
+	addrs, err := net.LookupHost(name)
+	if err != nil || len(addrs) == 0 {
+		return ""
+	}
+	return addrs[0]
+}
+
+// cidrHost returns the address of the hostnum-th host of the prefix, such as
+// "10.0.0.5" for "10.0.0.0/24" and 5.
+func cidrHost(prefix string, hostnum int) (string, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	ones, bits := network.Mask.Size()
+	if hostnum < 0 || big.NewInt(int64(hostnum)).BitLen() > bits-ones {
+		return "", fmt.Errorf("prefix %s has no host number %d", prefix, hostnum)
+	}
+	ip := new(big.Int).Add(new(big.Int).SetBytes(network.IP), big.NewInt(int64(hostnum)))
+	return intToIP(ip, len(network.IP)).String(), nil
+}
+
+// cidrSubnet returns the netnum-th subnet of the prefix extended by newbits,
+// such as "10.0.2.0/24" for "10.0.0.0/16", 8 and 2.
+func cidrSubnet(prefix string, newbits, netnum int) (string, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	ones, bits := network.Mask.Size()
+	if newbits < 0 || ones+newbits > bits {
+		return "", fmt.Errorf("prefix %s cannot be extended by %d bits", prefix, newbits)
+	}
+	if netnum < 0 || big.NewInt(int64(netnum)).BitLen() > newbits {
+		return "", fmt.Errorf("prefix %s extended by %d bits has no network number %d", prefix, newbits, netnum)
+	}
+	offset := new(big.Int).Lsh(big.NewInt(int64(netnum)), uint(bits-ones-newbits))
+	ip := new(big.Int).Add(new(big.Int).SetBytes(network.IP), offset)
+	subnet := &net.IPNet{IP: intToIP(ip, len(network.IP)), Mask: net.CIDRMask(ones+newbits, bits)}
+	return subnet.String(), nil
+}
+
+// cidrNetmask returns the netmask of an IPv4 prefix, such as "255.255.255.0"
+// for "10.0.0.0/24".
+func cidrNetmask(prefix string) (string, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	if len(network.Mask) != net.IPv4len {
+		return "", fmt.Errorf("prefix %s is not an IPv4 prefix", prefix)
+	}
+	return net.IP(network.Mask).String(), nil
+}
+
+// cidrContains returns whether the prefix contains the address.
+func cidrContains(prefix, address string) (bool, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return false, err
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false, fmt.Errorf("invalid IP address %q", address)
+	}
+	return network.Contains(ip), nil
+}
+
+func intToIP(n *big.Int, length int) net.IP {
+	b := n.Bytes()
+	ip := make(net.IP, length)
+	copy(ip[length-len(b):], b)
+	return ip
+}
+
+func regexSplit(regex, s string, n int) []string {
+	return regexp.MustCompile(regex).Split(s, n)
+}
+
+// The "must" variants of the regular expression functions return an error
+// for an invalid expression instead of panicking.
+
+func mustRegexMatch(regex, s string) (bool, error) {
+	return regexp.MatchString(regex, s)
+}
+
+func mustRegexFind(regex, s string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.FindString(s), nil
+}
+
+func mustRegexFindAll(regex, s string, n int) ([]string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return r.FindAllString(s, n), nil
+}
+
+func mustRegexReplaceAll(regex, s, repl string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplaceAllString(s, repl), nil
+}
+
+func mustRegexReplaceAllLiteral(regex, s, repl string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplaceAllLiteralString(s, repl), nil
+}
+
+func mustRegexSplit(regex, s string, n int) ([]string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return r.Split(s, n), nil
+}
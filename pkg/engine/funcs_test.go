@@ -0,0 +1,115 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+)
+
+func TestCIDRFuncs(t *testing.T) {
+	tests := []struct {
+		tpl    string
+		expect string
+	}{
+		{`{{ cidrHost "10.0.0.0/24" 5 }}`, "10.0.0.5"},
+		{`{{ cidrHost "fd00::/64" 10 }}`, "fd00::a"},
+		{`{{ cidrSubnet "10.0.0.0/16" 8 2 }}`, "10.0.2.0/24"},
+		{`{{ cidrSubnet "10.0.0.0/8" 0 0 }}`, "10.0.0.0/8"},
+		{`{{ cidrNetmask "10.0.0.0/20" }}`, "255.255.240.0"},
+		{`{{ cidrContains "10.0.0.0/8" "10.1.2.3" }}`, "true"},
+		{`{{ cidrContains "10.0.0.0/8" "192.168.0.1" }}`, "false"},
+	}
+	for _, tt := range tests {
+		out, err := New().render(map[string]renderable{"t": {tpl: tt.tpl, vals: chartutil.Values{}}})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.tpl, err)
+			continue
+		}
+		if out["t"] != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.tpl, tt.expect, out["t"])
+		}
+	}
+
+	for _, tpl := range []string{
+		`{{ cidrHost "10.0.0.0/30" 4 }}`,
+		`{{ cidrSubnet "10.0.0.0/24" 4 16 }}`,
+		`{{ cidrSubnet "10.0.0.0/24" 9 0 }}`,
+		`{{ cidrNetmask "fd00::/64" }}`,
+		`{{ cidrContains "10.0.0.0/8" "nope" }}`,
+	} {
+		if _, err := New().render(map[string]renderable{"t": {tpl: tpl, vals: chartutil.Values{}}}); err == nil {
+			t.Errorf("%s: expected an error", tpl)
+		}
+	}
+}
+
+func TestRegexFuncs(t *testing.T) {
+	tpl := `{{ regexSplit "[,;]" "a,b;c" -1 | join " " }} {{ mustRegexReplaceAll "o+" "foo" "0" }}`
+	out, err := New().render(map[string]renderable{"t": {tpl: tpl, vals: chartutil.Values{}}})
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if out["t"] != "a b c f0" {
+		t.Errorf("Expected \"a b c f0\", got %q", out["t"])
+	}
+
+	_, err = New().render(map[string]renderable{"t": {tpl: `{{ mustRegexSplit "(" "a" -1 }}`, vals: chartutil.Values{}}})
+	if err == nil || !strings.Contains(err.Error(), "missing closing )") {
+		t.Errorf("Expected an invalid expression error, got %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tpl := `{{ $cm := lookup "v1" "ConfigMap" "default" "settings" }}{{ if $cm }}{{ $cm.data.mode }}{{ else }}none{{ end }}`
+	vals := chartutil.Values{}
+
+	out, err := New().render(map[string]renderable{"t": {tpl: tpl, vals: vals}})
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if out["t"] != "none" {
+		t.Errorf("Expected no object without a cluster, got %q", out["t"])
+	}
+
+	e := New()
+	e.Lookup = func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		if apiVersion != "v1" || kind != "ConfigMap" || namespace != "default" || name != "settings" {
+			t.Errorf("Unexpected lookup of %s %s %s/%s", apiVersion, kind, namespace, name)
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"mode": "fast"}}, nil
+	}
+	out, err = e.render(map[string]renderable{"t": {tpl: tpl, vals: vals}})
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if out["t"] != "fast" {
+		t.Errorf("Expected the looked up object, got %q", out["t"])
+	}
+}
+
+func TestGetHostByNameDisabled(t *testing.T) {
+	out, err := New().render(map[string]renderable{"t": {tpl: `{{ getHostByName "localhost" }}`, vals: chartutil.Values{}}})
+	if err != nil {
+		t.Fatalf("Failed template rendering: %s", err)
+	}
+	if out["t"] != "" {
+		t.Errorf("Expected no address without DNS lookups, got %q", out["t"])
+	}
+}
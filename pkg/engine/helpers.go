@@ -0,0 +1,119 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+// HelpersName is the name of the template holding the builtin helpers.
+const HelpersName = "_helm_helpers.tpl"
+
+// HelpersVersion is the version of the builtin helpers, returned by the
+// "helm.helpersVersion" template. It is increased whenever a helper is added
+// or its output changes.
+const HelpersVersion = "1"
+
+// helpersTemplate defines the named templates every chart can include, such
+// as "helm.fullname" and "helm.labels". It is parsed before the templates of
+// the charts, so a chart defining a template of the same name replaces it.
+//
+// Optional values are read with "index", so that the helpers also work with
+// strict rendering.
+const helpersTemplate = `
+{{- define "helm.helpersVersion" -}}
+` + HelpersVersion + `
+{{- end -}}
+
+{{- /*
+The name of the chart, or .Values.nameOverride, truncated to the 63
+characters of a Kubernetes name.
+*/ -}}
+{{- define "helm.name" -}}
+{{- default .Chart.Name (index .Values "nameOverride") | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{- /*
+A name for the resources of the release: .Values.fullnameOverride, or the
+release name followed by the chart name, unless the release name contains
+it already. It is truncated to the 63 characters of a Kubernetes name.
+*/ -}}
+{{- define "helm.fullname" -}}
+{{- if index .Values "fullnameOverride" -}}
+{{- index .Values "fullnameOverride" | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- $name := default .Chart.Name (index .Values "nameOverride") -}}
+{{- if contains $name .Release.Name -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- printf "%s-%s" .Release.Name $name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+{{- /*
+The name and version of the chart, as used by the helm.sh/chart label.
+*/ -}}
+{{- define "helm.chart" -}}
+{{- printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{- /*
+The labels selecting the resources of the release.
+*/ -}}
+{{- define "helm.selectorLabels" -}}
+app.kubernetes.io/name: {{ include "helm.name" . }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end -}}
+
+{{- /*
+The recommended labels of the resources of the release.
+*/ -}}
+{{- define "helm.labels" -}}
+helm.sh/chart: {{ include "helm.chart" . }}
+{{ include "helm.selectorLabels" . }}
+{{- if .Chart.AppVersion }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+{{- end }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end -}}
+
+{{- /*
+An image reference built from a map of the registry, repository, tag and
+digest of an image. The digest takes precedence over the tag, which defaults
+to the app version of the chart:
+
+  image: {{ include "helm.image" (dict "image" .Values.image "context" $) }}
+*/ -}}
+{{- define "helm.image" -}}
+{{- $image := .image -}}
+{{- with index $image "registry" }}{{ . }}/{{ end -}}
+{{- index $image "repository" -}}
+{{- with index $image "digest" -}}
+@{{ . }}
+{{- else -}}
+:{{ default .context.Chart.AppVersion (index $image "tag") | toString }}
+{{- end -}}
+{{- end -}}
+
+{{- /*
+The SHA-256 checksum of another template of the chart, given by its path in
+the templates directory, to annotate pods so that they are replaced when it
+changes:
+
+  checksum/config: {{ include "helm.checksum" (dict "template" "configmap.yaml" "context" $) }}
+*/ -}}
+{{- define "helm.checksum" -}}
+{{- include (print .context.Template.BasePath "/" .template) .context | sha256sum -}}
+{{- end -}}
+`
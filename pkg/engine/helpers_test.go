@@ -0,0 +1,113 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestRenderHelpers(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "web", Version: "1.2.3+build", AppVersion: "4.5"},
+		Templates: []*chart.Template{
+			{Name: "templates/name", Data: []byte(`{{ include "helm.name" . }}`)},
+			{Name: "templates/fullname", Data: []byte(`{{ include "helm.fullname" . }}`)},
+			{Name: "templates/chart", Data: []byte(`{{ include "helm.chart" . }}`)},
+			{Name: "templates/labels", Data: []byte(`{{ include "helm.labels" . }}`)},
+			{Name: "templates/image", Data: []byte(`{{ include "helm.image" (dict "image" .Values.image "context" $) }}`)},
+			{Name: "templates/digest", Data: []byte(`{{ include "helm.image" (dict "image" .Values.pinned "context" $) }}`)},
+			{Name: "templates/config", Data: []byte(`key: value`)},
+			{Name: "templates/checksum", Data: []byte(`{{ include "helm.checksum" (dict "template" "config" "context" $) }}`)},
+			{Name: "templates/version", Data: []byte(`{{ include "helm.helpersVersion" . }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+	vals := chartutil.Values{
+		"Values": map[string]interface{}{
+			"image":  map[string]interface{}{"registry": "example.com", "repository": "web"},
+			"pinned": map[string]interface{}{"repository": "web", "tag": "ignored", "digest": "sha256:abc"},
+		},
+		"Chart": c.Metadata,
+		"Release": chartutil.Values{
+			"Name":    "prod",
+			"Service": "Tiller",
+		},
+	}
+
+	for _, strict := range []bool{false, true} {
+		e := New()
+		e.Strict = strict
+		out, err := e.Render(c, vals)
+		if err != nil {
+			t.Fatalf("failed to render templates (strict %t): %s", strict, err)
+		}
+
+		expects := map[string]string{
+			"web/templates/name":     "web",
+			"web/templates/fullname": "prod-web",
+			"web/templates/chart":    "web-1.2.3_build",
+			"web/templates/labels": `helm.sh/chart: web-1.2.3_build
+app.kubernetes.io/name: web
+app.kubernetes.io/instance: prod
+app.kubernetes.io/version: "4.5"
+app.kubernetes.io/managed-by: Tiller`,
+			"web/templates/image":    "example.com/web:4.5",
+			"web/templates/digest":   "web@sha256:abc",
+			"web/templates/checksum": "b701870861d6ff0565b7078ee799ae7362323298a814d7af4d2dce6cb8d8b674",
+			"web/templates/version":  HelpersVersion,
+		}
+		for file, expect := range expects {
+			if out[file] != expect {
+				t.Errorf("Expected %q for %s (strict %t), got %q", expect, file, strict, out[file])
+			}
+		}
+	}
+}
+
+func TestRenderHelpersOverrides(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "web"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{ define "helm.name" }}custom{{ end }}`)},
+			{Name: "templates/name", Data: []byte(`{{ include "helm.name" . }}`)},
+			{Name: "templates/fullname", Data: []byte(`{{ include "helm.fullname" . }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+	vals := chartutil.Values{
+		"Values": map[string]interface{}{
+			"nameOverride":     "ignored",
+			"fullnameOverride": "a-very-long-name-that-does-not-fit-in-sixty-three-characters-abc-",
+		},
+		"Chart":   c.Metadata,
+		"Release": chartutil.Values{"Name": "prod"},
+	}
+
+	out, err := New().Render(c, vals)
+	if err != nil {
+		t.Fatalf("failed to render templates: %s", err)
+	}
+	if expect := "custom"; out["web/templates/name"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["web/templates/name"])
+	}
+	if expect := "a-very-long-name-that-does-not-fit-in-sixty-three-characters-ab"; out["web/templates/fullname"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["web/templates/fullname"])
+	}
+}
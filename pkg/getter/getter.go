@@ -19,6 +19,8 @@ package getter
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"time"
 
 	"k8s.io/helm/pkg/helm/environment"
 )
@@ -29,6 +31,63 @@ type Getter interface {
 	Get(url string) (*bytes.Buffer, error)
 }
 
+// Progress is called as content is received, with the number of bytes
+// received so far and the total number of bytes expected, or -1 if the total
+// is not known.
+type Progress func(received, total int64)
+
+// RangeGetter is implemented by getters that can stream part of a resource
+// and report progress while doing so.
+type RangeGetter interface {
+	Getter
+	// GetRange writes the content at url to w, starting at offset. A length
+	// greater than zero limits the number of bytes fetched. progress may be
+	// nil.
+	GetRange(url string, offset, length int64, w io.Writer, progress Progress) error
+}
+
+// CredentialSetter is implemented by getters that accept basic
+// authentication credentials.
+type CredentialSetter interface {
+	SetCredentials(username, password string)
+}
+
+// HeaderSetter is implemented by getters that can send additional headers
+// with their requests.
+type HeaderSetter interface {
+	SetHeader(name, value string)
+}
+
+// HTTPOptions configures the connections of HTTP getters.
+type HTTPOptions struct {
+	// Proxy is the URL of the proxy to send the requests through, instead of
+	// the one of $HTTP_PROXY, $HTTPS_PROXY and $NO_PROXY.
+	Proxy string
+	// ConnectTimeout limits the time to establish a connection, including the
+	// TLS handshake.
+	ConnectTimeout time.Duration
+	// ReadTimeout limits the time to wait for the response once the request
+	// is sent.
+	ReadTimeout time.Duration
+	// Timeout limits the time of each attempt of a request, reading the body
+	// included.
+	Timeout time.Duration
+	// Retries is the number of times a request failing with a server error or
+	// a connection failure is retried. A negative number disables retries.
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// following one.
+	RetryBackoff time.Duration
+}
+
+// HTTPConfigurer is implemented by getters whose connections can be
+// configured.
+type HTTPConfigurer interface {
+	// ConfigureHTTP sets the options of the getter. Zero options are left
+	// unchanged.
+	ConfigureHTTP(o HTTPOptions) error
+}
+
 // Constructor is the function for every getter which creates a specific instance
 // according to the configuration
 type Constructor func(URL, CertFile, KeyFile, CAFile string) (Getter, error)
@@ -74,7 +133,7 @@ func All(settings environment.EnvSettings) Providers {
 	result := Providers{
 		{
 			Schemes: []string{"http", "https"},
-			New:     newHTTPGetter,
+			New:     httpGetterConstructor(settings),
 		},
 	}
 	pluginDownloaders, _ := collectPlugins(settings)
@@ -82,6 +141,20 @@ func All(settings environment.EnvSettings) Providers {
 	return result
 }
 
+// httpGetterConstructor returns a constructor of HTTP getters applying the
+// global settings, such as --repo-timeout.
+func httpGetterConstructor(settings environment.EnvSettings) Constructor {
+	return func(URL, CertFile, KeyFile, CAFile string) (Getter, error) {
+		g, err := NewHTTPGetter(URL, CertFile, KeyFile, CAFile)
+		if err != nil {
+			return g, err
+		}
+		return g, g.ConfigureHTTP(HTTPOptions{
+			Timeout: time.Duration(settings.RepoTimeout) * time.Second,
+		})
+	}
+}
+
 // ByScheme returns a getter for the given scheme.
 //
 // If the scheme is not supported, this will return an error.
@@ -19,19 +19,73 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
+	"syscall"
+	"time"
 
 	"k8s.io/helm/pkg/tlsutil"
+	"k8s.io/helm/pkg/trace"
 	"k8s.io/helm/pkg/version"
 )
 
 //HttpGetter is the default HTTP(/S) backend handler
 // TODO: change the name to HTTPGetter in Helm 3
 type HttpGetter struct { //nolint
-	client   *http.Client
-	username string
-	password string
+	client    *http.Client
+	transport *http.Transport
+	username  string
+	password  string
+	headers   http.Header
+	retries   int
+	backoff   time.Duration
+}
+
+const (
+	// DefaultConnectTimeout is the default time to establish a connection.
+	DefaultConnectTimeout = 30 * time.Second
+	// DefaultRetries is the default number of times a failed request is retried.
+	DefaultRetries = 3
+	// DefaultRetryBackoff is the default delay before the first retry, doubled
+	// for each following one.
+	DefaultRetryBackoff = time.Second
+)
+
+// ConfigureHTTP sets the connection options of the getter. Zero options are
+// left unchanged.
+func (g *HttpGetter) ConfigureHTTP(o HTTPOptions) error {
+	if o.Proxy != "" {
+		u, err := url.Parse(o.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL %q: %s", o.Proxy, err)
+		}
+		g.transport.Proxy = http.ProxyURL(u)
+	}
+	if o.ConnectTimeout > 0 {
+		g.transport.DialContext = (&net.Dialer{
+			Timeout:   o.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+		g.transport.TLSHandshakeTimeout = o.ConnectTimeout
+	}
+	if o.ReadTimeout > 0 {
+		g.transport.ResponseHeaderTimeout = o.ReadTimeout
+	}
+	if o.Timeout > 0 {
+		g.client.Timeout = o.Timeout
+	}
+	if o.Retries > 0 {
+		g.retries = o.Retries
+	} else if o.Retries < 0 {
+		g.retries = 0
+	}
+	if o.RetryBackoff > 0 {
+		g.backoff = o.RetryBackoff
+	}
+	return nil
 }
 
 //SetCredentials sets the credentials for the getter
@@ -40,6 +94,14 @@ func (g *HttpGetter) SetCredentials(username, password string) {
 	g.password = password
 }
 
+// SetHeader sets a header sent with every request of the getter.
+func (g *HttpGetter) SetHeader(name, value string) {
+	if g.headers == nil {
+		g.headers = http.Header{}
+	}
+	g.headers.Set(name, value)
+}
+
 //Get performs a Get from repo.Getter and returns the body.
 func (g *HttpGetter) Get(href string) (*bytes.Buffer, error) {
 	return g.get(href)
@@ -55,22 +117,124 @@ func (g *HttpGetter) get(href string) (*bytes.Buffer, error) {
 		return buf, err
 	}
 	req.Header.Set("User-Agent", "Helm/"+strings.TrimPrefix(version.GetVersion(), "v"))
+	for name := range g.headers {
+		req.Header.Set(name, g.headers.Get(name))
+	}
 
 	if g.username != "" && g.password != "" {
 		req.SetBasicAuth(g.username, g.password)
 	}
 
-	resp, err := g.client.Do(req)
+	resp, err := g.do(req)
 	if err != nil {
 		return buf, err
 	}
 	if resp.StatusCode != 200 {
+		resp.Body.Close()
 		return buf, fmt.Errorf("Failed to fetch %s : %s", href, resp.Status)
 	}
 
-	_, err = io.Copy(buf, resp.Body)
+	return buf, g.read(buf, req, resp)
+}
+
+// read copies the body of a response to buf. A download interrupted by a
+// failure of the connection is retried with an exponential backoff, and
+// resumed where it stopped if the server supports range requests.
+func (g *HttpGetter) read(buf *bytes.Buffer, req *http.Request, resp *http.Response) error {
+	ranges := resp.Header.Get("Accept-Ranges") == "bytes"
+	validator := resp.Header.Get("ETag")
+	if validator == "" {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	backoff := g.backoff
+	for attempt := 0; ; attempt++ {
+		_, err := io.Copy(buf, resp.Body)
+		resp.Body.Close()
+		if err == nil || attempt >= g.retries || !retryable(err) {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if resp, err = g.resume(req, buf, ranges, validator); err != nil {
+			return err
+		}
+	}
+}
+
+// resume requests the rest of a download interrupted after the bytes of buf.
+// The download starts over if the server does not support range requests, or
+// if the resource changed in the meantime.
+func (g *HttpGetter) resume(req *http.Request, buf *bytes.Buffer, ranges bool, validator string) (*http.Response, error) {
+	r := new(http.Request)
+	*r = *req
+	r.Header = http.Header{}
+	for name, values := range req.Header {
+		r.Header[name] = values
+	}
+	offset := buf.Len()
+	if ranges && offset > 0 {
+		r.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			r.Header.Set("If-Range", validator)
+		}
+	}
+
+	resp, err := g.client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		buf.Reset()
+		return resp, nil
+	case resp.StatusCode == http.StatusPartialContent && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)):
+		return resp, nil
+	}
 	resp.Body.Close()
-	return buf, err
+	return nil, fmt.Errorf("Failed to resume %s : %s", req.URL, resp.Status)
+}
+
+// do sends a request, retrying it with an exponential backoff on server
+// errors and on failures of the connection.
+func (g *HttpGetter) do(req *http.Request) (*http.Response, error) {
+	backoff := g.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := g.client.Do(req)
+		if attempt >= g.retries {
+			return resp, err
+		}
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if err != nil && !retryable(err) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// retryable returns true for the errors of requests that may succeed when
+// sent again: timeouts, and connections reset or closed early.
+func retryable(err error) bool {
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return true
+	}
+	if oe, ok := err.(*net.OpError); ok {
+		if se, ok := oe.Err.(*os.SyscallError); ok {
+			return se.Err == syscall.ECONNRESET
+		}
+	}
+	return false
 }
 
 // newHTTPGetter constructs a valid http/https client as Getter
@@ -80,10 +244,18 @@ func newHTTPGetter(URL, CertFile, KeyFile, CAFile string) (Getter, error) {
 
 // NewHTTPGetter constructs a valid http/https client as HttpGetter
 func NewHTTPGetter(URL, CertFile, KeyFile, CAFile string) (*HttpGetter, error) {
-	var client HttpGetter
+	client := HttpGetter{
+		retries: DefaultRetries,
+		backoff: DefaultRetryBackoff,
+	}
 	tr := &http.Transport{
 		DisableCompression: true,
 		Proxy:              http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   DefaultConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
 	}
 	if (CertFile != "" && KeyFile != "") || CAFile != "" {
 		tlsConf, err := tlsutil.NewTLSConfig(URL, CertFile, KeyFile, CAFile)
@@ -92,6 +264,7 @@ func NewHTTPGetter(URL, CertFile, KeyFile, CAFile string) (*HttpGetter, error) {
 		}
 		tr.TLSClientConfig = tlsConf
 	}
-	client.client = &http.Client{Transport: tr}
+	client.transport = tr
+	client.client = &http.Client{Transport: trace.Transport(tr)}
 	return &client, nil
 }
@@ -16,13 +16,16 @@ limitations under the License.
 package getter
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 type TestFileHandler struct{}
@@ -105,3 +108,129 @@ func TestHTTPGetterTarDownload(t *testing.T) {
 		t.Fatalf("Expected response with MIME type %s, but got %s", expectedMimeType, mimeType)
 	}
 }
+
+func TestHTTPGetterRetries(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	g, err := NewHTTPGetter(server.URL, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ConfigureHTTP(HTTPOptions{RetryBackoff: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := g.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.String() != "ok" || requests != 3 {
+		t.Errorf("expected ok after 3 requests, got %q after %d", data, requests)
+	}
+
+	requests = 0
+	if err := g.ConfigureHTTP(HTTPOptions{Retries: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Get(server.URL); err == nil {
+		t.Error("expected an error without retries")
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request without retries, got %d", requests)
+	}
+}
+
+func TestHTTPGetterResume(t *testing.T) {
+	content := strings.Repeat("0123456789", 100)
+	for _, ranges := range []bool{true, false} {
+		var requested []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.Header.Get("Range"))
+			if ranges {
+				w.Header().Set("Accept-Ranges", "bytes")
+				w.Header().Set("ETag", `"v1"`)
+			}
+			if rg := r.Header.Get("Range"); rg != "" {
+				var start int
+				fmt.Sscanf(rg, "bytes=%d-", &start)
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write([]byte(content[start:]))
+				return
+			}
+			if len(requested) > 1 {
+				w.Write([]byte(content))
+				return
+			}
+			// Drop the connection in the middle of the first download.
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.Write([]byte(content[:400]))
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		}))
+
+		g, err := NewHTTPGetter(server.URL, "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.ConfigureHTTP(HTTPOptions{RetryBackoff: time.Millisecond}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := g.Get(server.URL)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.String() != content {
+			t.Errorf("ranges=%t: expected the complete content, got %d bytes", ranges, data.Len())
+		}
+		expect := []string{"", ""}
+		if ranges {
+			expect[1] = "bytes=400-"
+		}
+		if strings.Join(requested, ",") != strings.Join(expect, ",") {
+			t.Errorf("ranges=%t: expected requests %q, got %q", ranges, expect, requested)
+		}
+	}
+}
+
+func TestHTTPGetterProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+
+	g, err := NewHTTPGetter("http://charts.example.com", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ConfigureHTTP(HTTPOptions{Proxy: proxy.URL}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := g.Get("http://charts.example.com/index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.String() != "proxied" || proxied != "http://charts.example.com/index.yaml" {
+		t.Errorf("expected the request to go through the proxy, got %q for %q", data, proxied)
+	}
+
+	if err := g.ConfigureHTTP(HTTPOptions{Proxy: "://bad"}); err == nil {
+		t.Error("expected an error for an invalid proxy URL")
+	}
+}
@@ -37,14 +37,19 @@ func collectPlugins(settings environment.EnvSettings) (Providers, error) {
 	var result Providers
 	for _, plugin := range plugins {
 		for _, downloader := range plugin.Metadata.Downloaders {
+			var constructor Constructor
+			switch downloader.ProtocolVersion {
+			case 0, 1:
+				constructor = newPluginGetter(downloader.Command, settings, plugin.Metadata.Name, plugin.Dir)
+			case 2:
+				constructor = newStreamPluginGetter(downloader.Command, settings, plugin.Metadata.Name, plugin.Dir)
+			default:
+				// written for a newer Helm
+				continue
+			}
 			result = append(result, Provider{
 				Schemes: downloader.Protocols,
-				New: newPluginGetter(
-					downloader.Command,
-					settings,
-					plugin.Metadata.Name,
-					plugin.Dir,
-				),
+				New:     constructor,
 			})
 		}
 	}
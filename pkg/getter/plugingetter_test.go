@@ -16,6 +16,8 @@ limitations under the License.
 package getter
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -121,3 +123,100 @@ func TestPluginSubCommands(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expect, got)
 	}
 }
+
+// TestHelperDownloader is not a real test. It is run as a downloader plugin
+// speaking version 2 of the protocol by TestStreamPluginGetter.
+func TestHelperDownloader(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	dec := json.NewDecoder(os.Stdin)
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		var req pluginRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		if strings.Contains(req.URL, "missing") {
+			enc.Encode(pluginMessage{ID: req.ID, Type: "error", Error: "not found"})
+			continue
+		}
+		content := req.URL
+		if req.Credentials != nil {
+			content += " as " + req.Credentials.Username
+		}
+		total := int64(len(content))
+		content = content[req.Offset:]
+		if req.Length > 0 && req.Length < int64(len(content)) {
+			content = content[:req.Length]
+		}
+		for len(content) > 0 {
+			n := 4
+			if n > len(content) {
+				n = len(content)
+			}
+			enc.Encode(pluginMessage{ID: req.ID, Type: "data", Data: []byte(content[:n]), Total: total})
+			content = content[n:]
+		}
+		enc.Encode(pluginMessage{ID: req.ID, Type: "done"})
+	}
+}
+
+func TestStreamPluginGetter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("TODO: refactor this test to work on windows")
+	}
+
+	oldhh := os.Getenv("HELM_HOME")
+	defer os.Setenv("HELM_HOME", oldhh)
+	os.Setenv("HELM_HOME", "")
+	os.Setenv("GO_WANT_HELPER_PROCESS", "1")
+	defer os.Unsetenv("GO_WANT_HELPER_PROCESS")
+
+	env := hh(false)
+	pg := newStreamPluginGetter(os.Args[0]+" -test.run=^TestHelperDownloader$", env, "test", "")
+	g, err := pg("test://foo/bar", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sg := g.(*streamPluginGetter)
+	defer sg.Close()
+
+	data, err := g.Get("test://foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "test://foo/bar"; data.String() != expect {
+		t.Errorf("Expected %q, got %q", expect, data.String())
+	}
+
+	// the same plugin process serves ranged requests with progress
+	var buf bytes.Buffer
+	var received, total int64
+	progress := func(r, t int64) { received, total = r, t }
+	if err := sg.GetRange("test://foo/bar", 7, 3, &buf, progress); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "foo"; buf.String() != expect {
+		t.Errorf("Expected %q, got %q", expect, buf.String())
+	}
+	if received != 3 || total != 14 {
+		t.Errorf("Expected progress of 3/14 bytes, got %d/%d", received, total)
+	}
+
+	if _, err := g.Get("test://missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected the plugin error, got %v", err)
+	}
+
+	// credentials are passed in the request
+	sg.SetCredentials("user", "pass")
+	data, err = g.Get("test://foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "test://foo/bar as user"; data.String() != expect {
+		t.Errorf("Expected %q, got %q", expect, data.String())
+	}
+}
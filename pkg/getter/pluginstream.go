@@ -0,0 +1,277 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package getter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/plugin"
+)
+
+// The version 2 downloader protocol keeps the plugin command running and
+// exchanges newline-delimited JSON messages with it. Helm writes one
+// pluginRequest at a time to the plugin's standard input, and the plugin
+// answers with any number of "data" messages followed by a single "done" or
+// "error" message carrying the same id.
+//
+// Credentials are sent inside requests rather than through arguments or the
+// environment, so they are not visible to other processes on the machine.
+
+// pluginRequest asks a plugin for the content at URL.
+type pluginRequest struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+	// Offset is the byte the content starts at.
+	Offset int64 `json:"offset,omitempty"`
+	// Length limits the number of bytes to send. Zero sends everything after
+	// Offset.
+	Length      int64              `json:"length,omitempty"`
+	Credentials *pluginCredentials `json:"credentials,omitempty"`
+}
+
+type pluginCredentials struct {
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	CAFile   string `json:"caFile,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// pluginMessage is a message sent by a plugin in answer to a request.
+type pluginMessage struct {
+	ID int `json:"id"`
+	// Type is one of "data", "done" or "error".
+	Type string `json:"type"`
+	// Data is a chunk of the content, base64 encoded.
+	Data []byte `json:"data,omitempty"`
+	// Total is the size of the requested content, if the plugin knows it.
+	Total int64 `json:"total,omitempty"`
+	// Error describes why the request failed.
+	Error string `json:"error,omitempty"`
+}
+
+// streamPluginGetter invokes downloader plugins speaking version 2 of the
+// downloader protocol.
+type streamPluginGetter struct {
+	command  string
+	creds    pluginCredentials
+	settings environment.EnvSettings
+	name     string
+	base     string
+	mu       sync.Mutex
+	proc     *pluginProcess
+	lastID   int
+}
+
+// pluginProcess is a running downloader plugin.
+type pluginProcess struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	dec   *json.Decoder
+}
+
+// SetCredentials sets the basic authentication credentials sent to the plugin.
+func (p *streamPluginGetter) SetCredentials(username, password string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.creds.Username = username
+	p.creds.Password = password
+}
+
+// Get fetches the content at href through the plugin.
+func (p *streamPluginGetter) Get(href string) (*bytes.Buffer, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := p.GetRange(href, 0, 0, buf, nil); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// GetRange streams the content at href through the plugin. The plugin is
+// started on first use and serves every later request of this getter.
+func (p *streamPluginGetter) GetRange(href string, offset, length int64, w io.Writer, progress Progress) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.proc == nil {
+		proc, err := p.start()
+		if err != nil {
+			return err
+		}
+		p.proc = proc
+	}
+
+	p.lastID++
+	req := pluginRequest{
+		ID:     p.lastID,
+		URL:    href,
+		Offset: offset,
+		Length: length,
+	}
+	if p.creds != (pluginCredentials{}) {
+		creds := p.creds
+		req.Credentials = &creds
+	}
+
+	err := p.exchange(req, w, progress)
+	if _, ok := err.(*pluginError); !ok && err != nil {
+		// the plugin is no longer following the protocol, so start
+		// afresh on the next request
+		p.kill()
+	}
+	return err
+}
+
+// Close stops the plugin.
+func (p *streamPluginGetter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stop()
+}
+
+// pluginError is an error reported by the plugin itself. The plugin can keep
+// serving requests after one.
+type pluginError struct {
+	command string
+	msg     string
+}
+
+func (e *pluginError) Error() string {
+	return fmt.Sprintf("plugin %q: %s", e.command, e.msg)
+}
+
+func (p *streamPluginGetter) exchange(req pluginRequest, w io.Writer, progress Progress) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if _, err := p.proc.stdin.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("plugin %q is not accepting requests: %s", p.command, err)
+	}
+
+	var received int64
+	total := int64(-1)
+	for {
+		var msg pluginMessage
+		if err := p.proc.dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("plugin %q exited before completing the request", p.command)
+			}
+			return fmt.Errorf("plugin %q sent an invalid message: %s", p.command, err)
+		}
+		if msg.ID != req.ID {
+			return fmt.Errorf("plugin %q answered request %d while serving request %d", p.command, msg.ID, req.ID)
+		}
+
+		switch msg.Type {
+		case "data":
+			if msg.Total > 0 {
+				total = msg.Total
+			}
+			if _, err := w.Write(msg.Data); err != nil {
+				return err
+			}
+			received += int64(len(msg.Data))
+			if progress != nil {
+				progress(received, total)
+			}
+		case "done":
+			return nil
+		case "error":
+			return &pluginError{command: p.command, msg: msg.Error}
+		default:
+			return fmt.Errorf("plugin %q sent a message of unknown type %q", p.command, msg.Type)
+		}
+	}
+}
+
+func (p *streamPluginGetter) start() (*pluginProcess, error) {
+	commands := strings.Split(p.command, " ")
+	prog := exec.Command(filepath.Join(p.base, commands[0]), commands[1:]...)
+	plugin.SetupPluginEnv(p.settings, p.name, p.base)
+	prog.Env = os.Environ()
+	prog.Stderr = os.Stderr
+
+	stdin, err := prog.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := prog.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := prog.Start(); err != nil {
+		return nil, fmt.Errorf("could not start plugin %q: %s", p.command, err)
+	}
+	return &pluginProcess{
+		cmd:   prog,
+		stdin: stdin,
+		dec:   json.NewDecoder(stdout),
+	}, nil
+}
+
+// stop closes the standard input of the plugin, which is its signal to exit,
+// and waits for it to do so.
+func (p *streamPluginGetter) stop() error {
+	if p.proc == nil {
+		return nil
+	}
+	proc := p.proc
+	p.proc = nil
+	proc.stdin.Close()
+	return proc.cmd.Wait()
+}
+
+// kill stops a plugin that no longer follows the protocol.
+func (p *streamPluginGetter) kill() {
+	if p.proc == nil {
+		return
+	}
+	proc := p.proc
+	p.proc = nil
+	proc.stdin.Close()
+	proc.cmd.Process.Kill()
+	proc.cmd.Wait()
+}
+
+// newStreamPluginGetter constructs a getter for plugins speaking version 2 of
+// the downloader protocol.
+func newStreamPluginGetter(command string, settings environment.EnvSettings, name, base string) Constructor {
+	return func(URL, CertFile, KeyFile, CAFile string) (Getter, error) {
+		result := &streamPluginGetter{
+			command: command,
+			creds: pluginCredentials{
+				CertFile: CertFile,
+				KeyFile:  KeyFile,
+				CAFile:   CAFile,
+			},
+			settings: settings,
+			name:     name,
+			base:     base,
+		}
+		return result, nil
+	}
+}
@@ -29,6 +29,7 @@ import (
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
 )
 
@@ -302,6 +303,143 @@ func (h *Client) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-ch
 	return h.test(ctx, req)
 }
 
+// WatchReadiness streams the readiness reports of the install, upgrade or
+// rollback in progress on a release, until the operation is over or stop is
+// closed.
+func (h *Client) WatchReadiness(rlsName string, stop <-chan struct{}) (<-chan *release.ResourceReadiness, <-chan error) {
+	reqOpts := h.opts
+	req := &rls.WatchReadinessRequest{Name: rlsName}
+	ctx, cancel := context.WithCancel(NewContext())
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			cancel()
+			errc := make(chan error, 1)
+			errc <- err
+			return nil, errc
+		}
+	}
+	go func() {
+		<-stop
+		cancel()
+	}()
+	return h.watchReadiness(ctx, req)
+}
+
+// UpdateReleaseMetadata changes the labels and annotations of a release.
+func (h *Client) UpdateReleaseMetadata(rlsName string, opts ...MetadataOption) (*rls.UpdateReleaseMetadataResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.metadataReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.metadata(ctx, req)
+}
+
+// MapReleaseAPIs replaces the apiVersions Kubernetes no longer serves in the
+// manifest of a release.
+func (h *Client) MapReleaseAPIs(rlsName string, opts ...MapAPIsOption) (*rls.MapReleaseAPIsResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.mapAPIsReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.mapAPIs(ctx, req)
+}
+
+// GetCapabilities returns the capabilities of the cluster Tiller renders charts for.
+func (h *Client) GetCapabilities(opts ...CapabilitiesOption) (*rls.GetCapabilitiesResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.capsReq
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.capabilities(ctx, req)
+}
+
+// GetAuditLog returns the recorded operations on a release.
+func (h *Client) GetAuditLog(rlsName string, opts ...AuditOption) (*rls.GetAuditLogResponse, error) {
+	reqOpts := h.opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.auditReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.auditLog(ctx, req)
+}
+
+// CancelRelease cancels the install, upgrade or rollback in progress on a release.
+func (h *Client) CancelRelease(rlsName string) (*rls.CancelReleaseResponse, error) {
+	reqOpts := h.opts
+	req := &rls.CancelReleaseRequest{Name: rlsName}
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.cancel(ctx, req)
+}
+
+// UnlockRelease removes the lock on a release left by an operation that did not finish.
+func (h *Client) UnlockRelease(rlsName string) (*rls.UnlockReleaseResponse, error) {
+	reqOpts := h.opts
+	req := &rls.UnlockReleaseRequest{Name: rlsName}
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.unlock(ctx, req)
+}
+
+// ImportRelease restores the revisions of a release exported from another Tiller.
+func (h *Client) ImportRelease(rlsName string, rels []*release.Release) (*rls.ImportReleaseResponse, error) {
+	reqOpts := h.opts
+	req := &rls.ImportReleaseRequest{Name: rlsName, Releases: rels}
+	ctx := NewContext()
+
+	if reqOpts.before != nil {
+		if err := reqOpts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.importRelease(ctx, req)
+}
+
 // PingTiller pings the Tiller pod and ensures that it is up and running
 func (h *Client) PingTiller() error {
 	ctx := NewContext()
@@ -326,6 +464,9 @@ func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error)
 	default:
 		opts = append(opts, grpc.WithInsecure())
 	}
+	if h.opts.userToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(userToken(h.opts.userToken)))
+	}
 	ctx, cancel := context.WithTimeout(ctx, h.opts.connectTimeout)
 	defer cancel()
 	if conn, err = grpc.DialContext(ctx, h.opts.host, opts...); err != nil {
@@ -334,6 +475,19 @@ func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error)
 	return conn, nil
 }
 
+// userToken passes the Kubernetes token of the user to Tiller.
+type userToken string
+
+func (t userToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"x-helm-user-token": string(t)}, nil
+}
+
+// RequireTransportSecurity does not require TLS, as Tiller may be reached
+// through a port forward. Tiller warns when it impersonates users without TLS.
+func (t userToken) RequireTransportSecurity() bool {
+	return false
+}
+
 // list executes tiller.ListReleases RPC.
 func (h *Client) list(ctx context.Context, req *rls.ListReleasesRequest) (*rls.ListReleasesResponse, error) {
 	c, err := h.connect(ctx)
@@ -461,6 +615,90 @@ func (h *Client) history(ctx context.Context, req *rls.GetHistoryRequest) (*rls.
 	return rlc.GetHistory(ctx, req)
 }
 
+// metadata executes tiller.UpdateReleaseMetadata RPC.
+func (h *Client) metadata(ctx context.Context, req *rls.UpdateReleaseMetadataRequest) (*rls.UpdateReleaseMetadataResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.UpdateReleaseMetadata(ctx, req)
+}
+
+// mapAPIs executes tiller.MapReleaseAPIs RPC.
+func (h *Client) mapAPIs(ctx context.Context, req *rls.MapReleaseAPIsRequest) (*rls.MapReleaseAPIsResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.MapReleaseAPIs(ctx, req)
+}
+
+// capabilities executes tiller.GetCapabilities RPC.
+func (h *Client) capabilities(ctx context.Context, req *rls.GetCapabilitiesRequest) (*rls.GetCapabilitiesResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetCapabilities(ctx, req)
+}
+
+// auditLog executes tiller.GetAuditLog RPC.
+func (h *Client) auditLog(ctx context.Context, req *rls.GetAuditLogRequest) (*rls.GetAuditLogResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetAuditLog(ctx, req)
+}
+
+// cancel executes tiller.CancelRelease RPC.
+func (h *Client) cancel(ctx context.Context, req *rls.CancelReleaseRequest) (*rls.CancelReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.CancelRelease(ctx, req)
+}
+
+// unlock executes tiller.UnlockRelease RPC.
+func (h *Client) unlock(ctx context.Context, req *rls.UnlockReleaseRequest) (*rls.UnlockReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.UnlockRelease(ctx, req)
+}
+
+// importRelease executes tiller.ImportRelease RPC.
+func (h *Client) importRelease(ctx context.Context, req *rls.ImportReleaseRequest) (*rls.ImportReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.ImportRelease(ctx, req)
+}
+
 // test executes tiller.TestRelease RPC.
 func (h *Client) test(ctx context.Context, req *rls.TestReleaseRequest) (<-chan *rls.TestReleaseResponse, <-chan error) {
 	errc := make(chan error, 1)
@@ -499,6 +737,44 @@ func (h *Client) test(ctx context.Context, req *rls.TestReleaseRequest) (<-chan
 	return ch, errc
 }
 
+// watchReadiness executes tiller.WatchReadiness RPC.
+func (h *Client) watchReadiness(ctx context.Context, req *rls.WatchReadinessRequest) (<-chan *release.ResourceReadiness, <-chan error) {
+	errc := make(chan error, 1)
+	c, err := h.connect(ctx)
+	if err != nil {
+		errc <- err
+		return nil, errc
+	}
+
+	ch := make(chan *release.ResourceReadiness, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+		defer c.Close()
+
+		rlc := rls.NewReleaseServiceClient(c)
+		s, err := rlc.WatchReadiness(ctx, req)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		for {
+			msg, err := s.Recv()
+			if err == io.EOF || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+			ch <- msg.Readiness
+		}
+	}()
+
+	return ch, errc
+}
+
 // ping executes tiller.Ping RPC.
 func (h *Client) ping(ctx context.Context) error {
 	c, err := h.connect(ctx)
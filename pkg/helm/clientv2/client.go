@@ -0,0 +1,355 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientv2
+
+import (
+	"crypto/tls"
+	"io"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/version"
+)
+
+// maxMsgSize is the largest response accepted from Tiller, as for the helm command.
+const maxMsgSize = 1024 * 1024 * 20
+
+// Client calls the release service of a Tiller.
+type Client struct {
+	conn *grpc.ClientConn
+	rlc  services.ReleaseServiceClient
+	opts options
+}
+
+type options struct {
+	retry     RetryPolicy
+	tlsConfig *tls.Config
+	dialOpts  []grpc.DialOption
+	interval  time.Duration
+}
+
+// Option configures a Client.
+type Option func(*options)
+
+// WithRetry replaces the DefaultRetryPolicy of the client.
+func WithRetry(policy RetryPolicy) Option {
+	return func(opts *options) {
+		opts.retry = policy
+	}
+}
+
+// WithTLS connects to Tiller over TLS with the given configuration.
+func WithTLS(config *tls.Config) Option {
+	return func(opts *options) {
+		opts.tlsConfig = config
+	}
+}
+
+// WithDialOptions adds gRPC dial options used by Dial.
+func WithDialOptions(dialOpts ...grpc.DialOption) Option {
+	return func(opts *options) {
+		opts.dialOpts = append(opts.dialOpts, dialOpts...)
+	}
+}
+
+// WithProgressInterval sets how often InstallReleaseProgress polls the state
+// of the resources. It defaults to 2 seconds.
+func WithProgressInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.interval = interval
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{retry: DefaultRetryPolicy, interval: 2 * time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Dial connects to the Tiller at address, such as "localhost:44134". The
+// context bounds the time taken to connect.
+func Dial(ctx context.Context, address string, opts ...Option) (*Client, error) {
+	o := newOptions(opts)
+	dialOpts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			// Send keepalive every 30 seconds to prevent the connection from
+			// getting closed by upstreams
+			Time: 30 * time.Second,
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)),
+	}
+	if o.tlsConfig != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(o.tlsConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+	conn, err := grpc.DialContext(ctx, address, append(dialOpts, o.dialOpts...)...)
+	if err != nil {
+		return nil, wrapError("dial", err)
+	}
+	return &Client{conn: conn, rlc: services.NewReleaseServiceClient(conn), opts: o}, nil
+}
+
+// New returns a client using an existing connection to Tiller, which is left
+// open by Close.
+func New(conn *grpc.ClientConn, opts ...Option) *Client {
+	return &Client{rlc: services.NewReleaseServiceClient(conn), opts: newOptions(opts)}
+}
+
+// Close closes the connection opened by Dial.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// outgoing adds the version of the client, checked by Tiller, to the context.
+func outgoing(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-helm-api-client", version.GetVersion())
+}
+
+// ListReleases lists the releases matching the request. The releases Tiller
+// streams back in several messages are gathered in a single response.
+func (c *Client) ListReleases(ctx context.Context, req *services.ListReleasesRequest) (*services.ListReleasesResponse, error) {
+	var resp *services.ListReleasesResponse
+	err := c.retry(ctx, func(ctx context.Context) error {
+		resp = nil
+		s, err := c.rlc.ListReleases(ctx, req)
+		if err != nil {
+			return err
+		}
+		for {
+			r, err := s.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if resp == nil {
+				resp = r
+				continue
+			}
+			resp.Releases = append(resp.Releases, r.GetReleases()...)
+		}
+		if resp == nil {
+			resp = &services.ListReleasesResponse{}
+		}
+		return nil
+	})
+	return resp, wrapError("list", err)
+}
+
+// GetReleaseStatus returns the status of a release.
+func (c *Client) GetReleaseStatus(ctx context.Context, req *services.GetReleaseStatusRequest) (*services.GetReleaseStatusResponse, error) {
+	var resp *services.GetReleaseStatusResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.rlc.GetReleaseStatus(ctx, req)
+		return err
+	})
+	return resp, wrapError("status", err)
+}
+
+// GetReleaseContent returns a release, with its chart, values and manifest.
+func (c *Client) GetReleaseContent(ctx context.Context, req *services.GetReleaseContentRequest) (*services.GetReleaseContentResponse, error) {
+	var resp *services.GetReleaseContentResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.rlc.GetReleaseContent(ctx, req)
+		return err
+	})
+	return resp, wrapError("content", err)
+}
+
+// GetHistory returns the revisions of a release.
+func (c *Client) GetHistory(ctx context.Context, req *services.GetHistoryRequest) (*services.GetHistoryResponse, error) {
+	var resp *services.GetHistoryResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.rlc.GetHistory(ctx, req)
+		return err
+	})
+	return resp, wrapError("history", err)
+}
+
+// GetVersion returns the version of Tiller.
+func (c *Client) GetVersion(ctx context.Context) (*services.GetVersionResponse, error) {
+	var resp *services.GetVersionResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.rlc.GetVersion(ctx, &services.GetVersionRequest{})
+		return err
+	})
+	return resp, wrapError("version", err)
+}
+
+// GetCapabilities returns the capabilities of the cluster Tiller renders charts for.
+func (c *Client) GetCapabilities(ctx context.Context, req *services.GetCapabilitiesRequest) (*services.GetCapabilitiesResponse, error) {
+	var resp *services.GetCapabilitiesResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.rlc.GetCapabilities(ctx, req)
+		return err
+	})
+	return resp, wrapError("capabilities", err)
+}
+
+// GetAuditLog returns the recorded operations on a release.
+func (c *Client) GetAuditLog(ctx context.Context, req *services.GetAuditLogRequest) (*services.GetAuditLogResponse, error) {
+	var resp *services.GetAuditLogResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.rlc.GetAuditLog(ctx, req)
+		return err
+	})
+	return resp, wrapError("audit", err)
+}
+
+// InstallRelease installs a chart. Requirements are processed by Tiller as
+// given: use chartutil to process them beforehand, as the helm command does.
+func (c *Client) InstallRelease(ctx context.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+	resp, err := c.rlc.InstallRelease(outgoing(ctx), req)
+	return resp, wrapError("install", err)
+}
+
+// UpdateRelease upgrades a release to a new chart or new values.
+func (c *Client) UpdateRelease(ctx context.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+	resp, err := c.rlc.UpdateRelease(outgoing(ctx), req)
+	return resp, wrapError("upgrade", err)
+}
+
+// RollbackRelease rolls a release back to a previous revision.
+func (c *Client) RollbackRelease(ctx context.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+	resp, err := c.rlc.RollbackRelease(outgoing(ctx), req)
+	return resp, wrapError("rollback", err)
+}
+
+// UninstallRelease deletes a release.
+func (c *Client) UninstallRelease(ctx context.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
+	resp, err := c.rlc.UninstallRelease(outgoing(ctx), req)
+	return resp, wrapError("delete", err)
+}
+
+// UpdateReleaseMetadata changes the labels and annotations of a release.
+func (c *Client) UpdateReleaseMetadata(ctx context.Context, req *services.UpdateReleaseMetadataRequest) (*services.UpdateReleaseMetadataResponse, error) {
+	resp, err := c.rlc.UpdateReleaseMetadata(outgoing(ctx), req)
+	return resp, wrapError("metadata", err)
+}
+
+// CancelRelease cancels the install, upgrade or rollback in progress on a
+// release. It is never retried.
+func (c *Client) CancelRelease(ctx context.Context, req *services.CancelReleaseRequest) (*services.CancelReleaseResponse, error) {
+	resp, err := c.rlc.CancelRelease(outgoing(ctx), req)
+	return resp, wrapError("cancel", err)
+}
+
+// UnlockRelease removes the lock on a release left by an operation that did
+// not finish. It is never retried.
+func (c *Client) UnlockRelease(ctx context.Context, req *services.UnlockReleaseRequest) (*services.UnlockReleaseResponse, error) {
+	resp, err := c.rlc.UnlockRelease(outgoing(ctx), req)
+	return resp, wrapError("unlock", err)
+}
+
+// ImportRelease restores the revisions of a release exported from another
+// Tiller. It is never retried.
+func (c *Client) ImportRelease(ctx context.Context, req *services.ImportReleaseRequest) (*services.ImportReleaseResponse, error) {
+	resp, err := c.rlc.ImportRelease(outgoing(ctx), req)
+	return resp, wrapError("import", err)
+}
+
+// MapReleaseAPIs replaces the apiVersions Kubernetes no longer serves in the
+// manifest of a release.
+func (c *Client) MapReleaseAPIs(ctx context.Context, req *services.MapReleaseAPIsRequest) (*services.MapReleaseAPIsResponse, error) {
+	resp, err := c.rlc.MapReleaseAPIs(outgoing(ctx), req)
+	return resp, wrapError("mapapis", err)
+}
+
+// RunReleaseTest runs the tests of a release. The results are sent on the
+// first channel as Tiller reports them; it is closed when the tests are done.
+// The second channel receives the error the tests failed to run with, if any.
+func (c *Client) RunReleaseTest(ctx context.Context, req *services.TestReleaseRequest) (<-chan *services.TestReleaseResponse, <-chan error) {
+	ch := make(chan *services.TestReleaseResponse, 1)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+
+		s, err := c.rlc.RunReleaseTest(outgoing(ctx), req)
+		if err != nil {
+			errc <- wrapError("test", err)
+			return
+		}
+		for {
+			msg, err := s.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errc <- wrapError("test", err)
+				return
+			}
+			select {
+			case ch <- msg:
+			case <-ctx.Done():
+				errc <- wrapError("test", ctx.Err())
+				return
+			}
+		}
+	}()
+	return ch, errc
+}
+
+// WatchReadiness streams the readiness reports of the install, upgrade or
+// rollback in progress on a release. The reports are sent on the first
+// channel; it is closed when the operation is over or ctx is done. The second
+// channel receives the error the stream failed with, if any.
+func (c *Client) WatchReadiness(ctx context.Context, req *services.WatchReadinessRequest) (<-chan *release.ResourceReadiness, <-chan error) {
+	ch := make(chan *release.ResourceReadiness, 1)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+
+		s, err := c.rlc.WatchReadiness(outgoing(ctx), req)
+		if err != nil {
+			errc <- wrapError("watch", err)
+			return
+		}
+		for {
+			msg, err := s.Recv()
+			if err == io.EOF || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				errc <- wrapError("watch", err)
+				return
+			}
+			select {
+			case ch <- msg.Readiness:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, errc
+}
@@ -0,0 +1,216 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientv2
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// fakeReleaseService fails the first calls with the given errors.
+type fakeReleaseService struct {
+	services.ReleaseServiceClient
+	errs    []error
+	calls   int
+	install chan struct{}
+}
+
+func (f *fakeReleaseService) fail(ctx context.Context) error {
+	f.calls++
+	if md, _ := metadata.FromOutgoingContext(ctx); len(md["x-helm-api-client"]) == 0 {
+		return errors.New("missing client version")
+	}
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func (f *fakeReleaseService) GetReleaseContent(ctx context.Context, in *services.GetReleaseContentRequest, opts ...grpc.CallOption) (*services.GetReleaseContentResponse, error) {
+	if err := f.fail(ctx); err != nil {
+		return nil, err
+	}
+	return &services.GetReleaseContentResponse{Release: &release.Release{Name: in.Name}}, nil
+}
+
+func (f *fakeReleaseService) GetReleaseStatus(ctx context.Context, in *services.GetReleaseStatusRequest, opts ...grpc.CallOption) (*services.GetReleaseStatusResponse, error) {
+	return &services.GetReleaseStatusResponse{
+		Name:   in.Name,
+		Info:   &release.Info{Status: &release.Status{Code: release.Status_PENDING_INSTALL}},
+		Health: []*release.ResourceReadiness{{Kind: "Deployment", Name: in.Name}},
+	}, nil
+}
+
+func (f *fakeReleaseService) InstallRelease(ctx context.Context, in *services.InstallReleaseRequest, opts ...grpc.CallOption) (*services.InstallReleaseResponse, error) {
+	if f.install != nil {
+		<-f.install
+	}
+	if err := f.fail(ctx); err != nil {
+		return nil, err
+	}
+	return &services.InstallReleaseResponse{Release: &release.Release{Name: in.Name}}, nil
+}
+
+func newFakeClient(f *fakeReleaseService, opts ...Option) *Client {
+	return &Client{rlc: f, opts: newOptions(opts)}
+}
+
+var unavailable = status.Error(codes.Unavailable, "transport is closing")
+
+func TestRetryReads(t *testing.T) {
+	f := &fakeReleaseService{errs: []error{unavailable, unavailable}}
+	c := newFakeClient(f, WithRetry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+
+	resp, err := c.GetReleaseContent(context.Background(), &services.GetReleaseContentRequest{Name: "angry-panda"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Release.Name != "angry-panda" {
+		t.Errorf("expected release angry-panda, got %q", resp.Release.Name)
+	}
+	if f.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", f.calls)
+	}
+
+	f = &fakeReleaseService{errs: []error{unavailable, unavailable, unavailable}}
+	c = newFakeClient(f, WithRetry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+	if _, err := c.GetReleaseContent(context.Background(), &services.GetReleaseContentRequest{Name: "angry-panda"}); !IsUnavailable(err) {
+		t.Errorf("expected an unavailable error, got %v", err)
+	}
+	if f.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", f.calls)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	// Mutations are not retried, nor errors other than unavailable.
+	f := &fakeReleaseService{errs: []error{unavailable}}
+	c := newFakeClient(f, WithRetry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+	if _, err := c.InstallRelease(context.Background(), &services.InstallReleaseRequest{Name: "angry-panda"}); !IsUnavailable(err) {
+		t.Errorf("expected an unavailable error, got %v", err)
+	}
+	if f.calls != 1 {
+		t.Errorf("expected 1 call, got %d", f.calls)
+	}
+
+	f = &fakeReleaseService{errs: []error{status.Error(codes.Unknown, `release: "angry-panda" not found`)}}
+	c = newFakeClient(f, WithRetry(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+	if _, err := c.GetReleaseContent(context.Background(), &services.GetReleaseContentRequest{Name: "angry-panda"}); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if f.calls != 1 {
+		t.Errorf("expected 1 call, got %d", f.calls)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	f := &fakeReleaseService{errs: []error{unavailable}}
+	c := newFakeClient(f, WithRetry(RetryPolicy{Attempts: 3, Backoff: time.Hour}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetReleaseContent(ctx, &services.GetReleaseContentRequest{Name: "angry-panda"}); !IsCanceled(err) {
+		t.Errorf("expected a canceled error, got %v", err)
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code Code
+	}{
+		{status.Error(codes.Unknown, `release: "angry-panda" not found`), NotFound},
+		{status.Error(codes.Unknown, "a release named angry-panda already exists.\nRun: helm ls --all angry-panda; to check the status of the release\nOr run: helm del --purge angry-panda; to delete it"), AlreadyExists},
+		{status.Error(codes.Unknown, "a release named angry-panda is in use, cannot re-use a name that is still in use"), AlreadyExists},
+		{status.Error(codes.Unavailable, "all SubConns are in TransientFailure"), Unavailable},
+		{status.Error(codes.DeadlineExceeded, "context deadline exceeded"), Timeout},
+		{context.DeadlineExceeded, Timeout},
+		{status.Error(codes.Unknown, "render error in \"mychart/templates/service.yaml\""), Unknown},
+		{fmt.Errorf("connection refused"), Unknown},
+	}
+	for _, tt := range tests {
+		err := wrapError("install", tt.err)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("expected *Error, got %T", err)
+		}
+		if e.Code != tt.code {
+			t.Errorf("%q: expected code %s, got %s", tt.err, tt.code, e.Code)
+		}
+		if e.Op != "install" {
+			t.Errorf("expected op install, got %q", e.Op)
+		}
+	}
+	if wrapError("install", nil) != nil {
+		t.Error("expected nil error")
+	}
+}
+
+func TestInstallReleaseProgress(t *testing.T) {
+	f := &fakeReleaseService{install: make(chan struct{})}
+	c := newFakeClient(f, WithProgressInterval(time.Millisecond))
+
+	var progress int
+	var rel *release.Release
+	for ev := range c.InstallReleaseProgress(context.Background(), &services.InstallReleaseRequest{Name: "angry-panda"}) {
+		switch {
+		case ev.Progress != nil:
+			progress++
+			if len(ev.Progress.Resources) != 1 || ev.Progress.Status != release.Status_PENDING_INSTALL {
+				t.Errorf("unexpected progress %v", ev.Progress)
+			}
+			if progress == 2 {
+				close(f.install)
+			}
+		case ev.Err != nil:
+			t.Fatal(ev.Err)
+		default:
+			rel = ev.Release
+		}
+	}
+	if progress < 2 {
+		t.Errorf("expected at least 2 progress events, got %d", progress)
+	}
+	if rel == nil || rel.Name != "angry-panda" {
+		t.Errorf("expected release angry-panda, got %v", rel)
+	}
+}
+
+func TestInstallReleaseProgressError(t *testing.T) {
+	f := &fakeReleaseService{errs: []error{status.Error(codes.Unknown, "a release named angry-panda already exists.")}}
+	c := newFakeClient(f, WithProgressInterval(time.Hour))
+
+	var events []InstallEvent
+	for ev := range c.InstallReleaseProgress(context.Background(), &services.InstallReleaseRequest{Name: "angry-panda"}) {
+		events = append(events, ev)
+	}
+	if len(events) != 1 || !IsAlreadyExists(events[0].Err) {
+		t.Errorf("expected a single already exists error, got %v", events)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clientv2 is a Go client for Tiller, for programs that manage releases
+without going through the helm command.
+
+The requests and responses are the messages of the hapi.services.tiller gRPC
+API, which only ever gains fields. Every method takes a context, which bounds
+the call and carries its cancellation. The package keeps its exported API
+compatible within Helm 2: new features are added as new methods and options.
+
+Calls that only read, such as ListReleases or GetReleaseStatus, are retried
+when Tiller cannot be reached, following the RetryPolicy of the client. Calls
+that change releases are never retried, as Tiller may have acted on them.
+
+Errors returned by the client are of type *Error, whose Code tells apart the
+failures a caller can act on:
+
+	rel, err := c.GetReleaseContent(ctx, &services.GetReleaseContentRequest{Name: "web"})
+	if clientv2.IsNotFound(err) {
+		// install it
+	}
+
+InstallReleaseProgress installs a release and reports the state of its
+resources while Tiller waits for them.
+*/
+package clientv2 // import "k8s.io/helm/pkg/helm/clientv2"
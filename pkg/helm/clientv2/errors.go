@@ -0,0 +1,141 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientv2
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code classifies the errors returned by a Client.
+type Code int
+
+const (
+	// Unknown is an error not classified below, such as a chart failing to render.
+	Unknown Code = iota
+	// NotFound means that the release or revision does not exist.
+	NotFound
+	// AlreadyExists means that the name of the release is already in use.
+	AlreadyExists
+	// Unavailable means that Tiller could not be reached.
+	Unavailable
+	// Timeout means that the deadline of the context expired.
+	Timeout
+	// Canceled means that the context was canceled.
+	Canceled
+)
+
+var codeNames = map[Code]string{
+	Unknown:       "unknown",
+	NotFound:      "not found",
+	AlreadyExists: "already exists",
+	Unavailable:   "unavailable",
+	Timeout:       "timeout",
+	Canceled:      "canceled",
+}
+
+func (c Code) String() string {
+	if s, ok := codeNames[c]; ok {
+		return s
+	}
+	return fmt.Sprintf("code(%d)", int(c))
+}
+
+// Error is an error returned by a Client.
+type Error struct {
+	// Code classifies the error.
+	Code Code
+	// Op is the call that failed, such as "install".
+	Op string
+	// Message is the description of the error given by Tiller.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Op, e.Message)
+}
+
+// wrapError converts an error of a gRPC call into an *Error.
+func wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	msg := err.Error()
+	code := Unknown
+	switch err {
+	case context.DeadlineExceeded:
+		code = Timeout
+	case context.Canceled:
+		code = Canceled
+	}
+	if s, ok := status.FromError(err); ok {
+		msg = s.Message()
+		code = classify(s.Code(), msg)
+	}
+	return &Error{Code: code, Op: op, Message: msg}
+}
+
+// classify returns the Code of an error from its gRPC code. Tiller reports
+// most errors as Unknown, so these are told apart by their message.
+func classify(c codes.Code, msg string) Code {
+	switch c {
+	case codes.NotFound:
+		return NotFound
+	case codes.AlreadyExists:
+		return AlreadyExists
+	case codes.Unavailable:
+		return Unavailable
+	case codes.DeadlineExceeded:
+		return Timeout
+	case codes.Canceled:
+		return Canceled
+	}
+	switch {
+	case strings.Contains(msg, "not found"):
+		return NotFound
+	case strings.Contains(msg, "already exists"), strings.Contains(msg, "is in use"):
+		return AlreadyExists
+	}
+	return Unknown
+}
+
+func hasCode(err error, c Code) bool {
+	e, ok := err.(*Error)
+	return ok && e.Code == c
+}
+
+// IsNotFound returns true if err reports that a release does not exist.
+func IsNotFound(err error) bool { return hasCode(err, NotFound) }
+
+// IsAlreadyExists returns true if err reports that a release name is in use.
+func IsAlreadyExists(err error) bool { return hasCode(err, AlreadyExists) }
+
+// IsUnavailable returns true if err reports that Tiller could not be reached.
+func IsUnavailable(err error) bool { return hasCode(err, Unavailable) }
+
+// IsTimeout returns true if err reports that the deadline of the call expired.
+func IsTimeout(err error) bool { return hasCode(err, Timeout) }
+
+// IsCanceled returns true if err reports that the call was canceled.
+func IsCanceled(err error) bool { return hasCode(err, Canceled) }
@@ -0,0 +1,101 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientv2
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// InstallEvent is sent by InstallReleaseProgress. Exactly one of its fields is set.
+type InstallEvent struct {
+	// Progress is the state of the release while it is being installed.
+	Progress *Progress
+	// Release is the installed release, sent last on success.
+	Release *release.Release
+	// Err is the error the install failed with, sent last on failure.
+	Err error
+}
+
+// Progress is the state of a release being installed.
+type Progress struct {
+	// Status is the status of the release, once Tiller has recorded it.
+	Status release.Status_Code
+	// Resources is the readiness of each resource of the release.
+	Resources []*release.ResourceReadiness
+}
+
+// InstallReleaseProgress installs a release like InstallRelease. While Tiller
+// installs it, the state of its resources is polled and sent on the returned
+// channel, which is closed after the final event. Progress can only be
+// reported for requests that name the release; Tiller waits for the resources
+// when req.Wait is set.
+func (c *Client) InstallReleaseProgress(ctx context.Context, req *services.InstallReleaseRequest) <-chan InstallEvent {
+	ch := make(chan InstallEvent, 1)
+	done := make(chan InstallEvent, 1)
+	go func() {
+		resp, err := c.InstallRelease(ctx, req)
+		if err != nil {
+			done <- InstallEvent{Err: err}
+			return
+		}
+		done <- InstallEvent{Release: resp.GetRelease()}
+	}()
+	go func() {
+		defer close(ch)
+		var tick <-chan time.Time
+		if req.Name != "" {
+			ticker := time.NewTicker(c.opts.interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case ev := <-done:
+				ch <- ev
+				return
+			case <-tick:
+				if p := c.progress(ctx, req.Name); p != nil {
+					select {
+					case ch <- InstallEvent{Progress: p}:
+					case ev := <-done:
+						ch <- ev
+						return
+					}
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// progress returns the state of the named release, or nil if it is not known
+// yet. Tiller records the release only once its resources are created.
+func (c *Client) progress(ctx context.Context, name string) *Progress {
+	s, err := c.rlc.GetReleaseStatus(outgoing(ctx), &services.GetReleaseStatusRequest{Name: name, Health: true})
+	if err != nil {
+		return nil
+	}
+	return &Progress{
+		Status:    s.GetInfo().GetStatus().GetCode(),
+		Resources: s.GetHealth(),
+	}
+}
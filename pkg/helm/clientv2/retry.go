@@ -0,0 +1,60 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientv2
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy controls how calls that only read are retried while Tiller
+// cannot be reached.
+type RetryPolicy struct {
+	// Attempts is the number of times a call is made, including the first.
+	Attempts int
+	// Backoff is the time waited before the first retry. It doubles with
+	// each further retry.
+	Backoff time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy of a client created without WithRetry.
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, Backoff: 500 * time.Millisecond}
+
+// NoRetry makes each call once.
+var NoRetry = RetryPolicy{Attempts: 1}
+
+// retry calls fn until it succeeds, fails with an error other than
+// codes.Unavailable, the attempts run out or ctx is done.
+func (c *Client) retry(ctx context.Context, fn func(context.Context) error) error {
+	ctx = outgoing(ctx)
+	backoff := c.opts.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= c.opts.retry.Attempts || status.Code(err) != codes.Unavailable {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
@@ -48,6 +48,10 @@ const (
 // DefaultHelmHome is the default HELM_HOME.
 var DefaultHelmHome = filepath.Join(homedir.HomeDir(), ".helm")
 
+// DefaultProfilesFile is the default location of the profiles file. It is kept
+// in the default HELM_HOME as profiles may select other homes.
+var DefaultProfilesFile = filepath.Join(DefaultHelmHome, "profiles.yaml")
+
 // EnvSettings describes all of the environment settings.
 type EnvSettings struct {
 	// TillerHost is the host and port of Tiller.
@@ -60,6 +64,12 @@ type EnvSettings struct {
 	Home helmpath.Home
 	// Debug indicates whether or not Helm is running in Debug mode.
 	Debug bool
+	// LogFormat is the format of the logs: text or json.
+	LogFormat string
+	// LogLevel is the lowest level of the entries logged: debug, info, warn or error.
+	LogLevel string
+	// Explain tells helm to log the files it reads and writes under Home and the network requests it makes.
+	Explain bool
 	// KubeContext is the name of the kubeconfig context.
 	KubeContext string
 	// KubeConfig is the path to an explicit kubeconfig file. This overwrites the value in $KUBECONFIG
@@ -76,6 +86,18 @@ type EnvSettings struct {
 	TLSCertFile string
 	// TLSKeyFile is the path to a TLS key file
 	TLSKeyFile string
+	// ClientOnly tells helm to manage releases itself instead of through Tiller
+	ClientOnly bool
+	// UserImpersonate tells helm to send the Kubernetes token of the user to Tiller, which applies the releases as that user
+	UserImpersonate bool
+	// Profile is the name of the profile to use instead of the current one of the profiles file.
+	Profile string
+	// Namespace is the namespace of releases when none is given, instead of the one of the kubeconfig context.
+	Namespace string
+	// RepoTimeout is the duration (in seconds) each request to a chart repository may take, 0 for no limit.
+	RepoTimeout int64
+	// EnableDecryption tells helm to decrypt the values files encrypted with a tool such as SOPS.
+	EnableDecryption bool
 }
 
 // AddFlags binds flags to the given flagset.
@@ -85,8 +107,16 @@ func (s *EnvSettings) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.KubeContext, "kube-context", "", "Name of the kubeconfig context to use")
 	fs.StringVar(&s.KubeConfig, "kubeconfig", "", "Absolute path of the kubeconfig file to be used")
 	fs.BoolVar(&s.Debug, "debug", false, "Enable verbose output")
+	fs.StringVar(&s.LogFormat, "log-format", "text", "Format of the logs: text, or json for log pipelines")
+	fs.StringVar(&s.LogLevel, "v", "info", "Log level: debug, info, warn or error. --debug sets it to debug")
+	fs.BoolVar(&s.Explain, "explain", false, "Log every file read or written under the Helm home and every network request of the command")
 	fs.StringVar(&s.TillerNamespace, "tiller-namespace", "kube-system", "Namespace of Tiller")
-	fs.Int64Var(&s.TillerConnectionTimeout, "tiller-connection-timeout", int64(300), "The duration (in seconds) Helm will wait to establish a connection to Tiller")
+	TimeoutVar(fs, &s.TillerConnectionTimeout, "tiller-connection-timeout", 300, "The duration Helm will wait to establish a connection to Tiller, such as 5m or 300 (seconds)")
+	fs.BoolVar(&s.ClientOnly, "client-only", false, "Manage releases from the client, storing them as Secrets in the release namespace, without Tiller")
+	fs.BoolVar(&s.UserImpersonate, "user-impersonate", false, "Send the Kubernetes token of the kubeconfig user to Tiller, for a Tiller applying releases as its callers")
+	TimeoutVar(fs, &s.RepoTimeout, "repo-timeout", 0, "The duration each request to a chart repository may take before it is retried or fails, such as 1m or 60 (seconds). 0 for no limit")
+	fs.StringVar(&s.Profile, "profile", "", "Name of the profile to use instead of the current one. Overrides $HELM_PROFILE")
+	fs.BoolVar(&s.EnableDecryption, "enable-decryption", false, "Decrypt the values files encrypted with SOPS, by running the sops command")
 }
 
 // AddFlagsTLS adds the flags for supporting client side TLS to the given flagset.
@@ -115,10 +145,18 @@ func (s *EnvSettings) InitTLS(fs *pflag.FlagSet) {
 
 // envMap maps flag names to envvars
 var envMap = map[string]string{
-	"debug":            "HELM_DEBUG",
-	"home":             "HELM_HOME",
-	"host":             "HELM_HOST",
-	"tiller-namespace": "TILLER_NAMESPACE",
+	"debug":             "HELM_DEBUG",
+	"log-format":        "HELM_LOG_FORMAT",
+	"v":                 "HELM_LOG_LEVEL",
+	"explain":           "HELM_EXPLAIN",
+	"home":              "HELM_HOME",
+	"host":              "HELM_HOST",
+	"tiller-namespace":  "TILLER_NAMESPACE",
+	"client-only":       "HELM_CLIENT_ONLY",
+	"user-impersonate":  "HELM_USER_IMPERSONATE",
+	"profile":           "HELM_PROFILE",
+	"repo-timeout":      "HELM_REPO_TIMEOUT",
+	"enable-decryption": "HELM_ENABLE_DECRYPTION",
 }
 
 var tlsEnvMap = map[string]string{
@@ -138,6 +176,14 @@ func (s EnvSettings) PluginDirs() string {
 	return s.Home.Plugins()
 }
 
+// ProfilesFile is the path to the profiles file.
+func (s EnvSettings) ProfilesFile() string {
+	if f, ok := os.LookupEnv("HELM_PROFILES"); ok {
+		return f
+	}
+	return DefaultProfilesFile
+}
+
 // HelmKeyPassphrase is the passphrase used to sign a helm chart.
 func (s EnvSettings) HelmKeyPassphrase() string {
 	if d, ok := os.LookupEnv("HELM_KEY_PASSPHRASE"); ok {
@@ -36,7 +36,7 @@ func TestEnvSettings(t *testing.T) {
 
 		// expected values
 		home, host, ns, kcontext, kconfig, plugins string
-		debug, tlsverify                           bool
+		debug, tlsverify, clientOnly               bool
 	}{
 		{
 			name:      "defaults",
@@ -79,6 +79,15 @@ func TestEnvSettings(t *testing.T) {
 			debug:     true,
 			tlsverify: true,
 		},
+		{
+			name:       "with client-only envvar set",
+			args:       []string{},
+			envars:     map[string]string{"HELM_CLIENT_ONLY": "true"},
+			home:       DefaultHelmHome,
+			plugins:    helmpath.Home(DefaultHelmHome).Plugins(),
+			ns:         "kube-system",
+			clientOnly: true,
+		},
 		{
 			name:      "with flags and envvars set",
 			args:      []string{"--home", "/foo", "--host=here", "--debug", "--tiller-namespace=myns"},
@@ -97,6 +106,7 @@ func TestEnvSettings(t *testing.T) {
 		"HELM_HOME":         "",
 		"HELM_HOST":         "",
 		"TILLER_NAMESPACE":  "",
+		"HELM_CLIENT_ONLY":  "",
 		"HELM_PLUGIN":       "",
 		"HELM_TLS_HOSTNAME": "",
 		"HELM_TLS_CA_CERT":  "",
@@ -149,6 +159,9 @@ func TestEnvSettings(t *testing.T) {
 			if settings.TLSVerify != tt.tlsverify {
 				t.Errorf("expected tls-verify %t, got %t", tt.tlsverify, settings.TLSVerify)
 			}
+			if settings.ClientOnly != tt.clientOnly {
+				t.Errorf("expected client-only %t, got %t", tt.clientOnly, settings.ClientOnly)
+			}
 
 			resetEnv(tt.envars)
 		})
@@ -0,0 +1,268 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/helm/helmpath"
+	"k8s.io/helm/pkg/trace"
+)
+
+// Profile is a named set of settings, such as the cluster, namespaces, TLS
+// files and Helm home to use together.
+type Profile struct {
+	// Name is the name of the profile.
+	Name string `json:"name"`
+	// Home is the Helm home directory.
+	Home string `json:"home,omitempty"`
+	// KubeConfig is the path to the kubeconfig file.
+	KubeConfig string `json:"kubeconfig,omitempty"`
+	// KubeContext is the name of the kubeconfig context.
+	KubeContext string `json:"kubeContext,omitempty"`
+	// Namespace is the namespace of releases when none is given.
+	Namespace string `json:"namespace,omitempty"`
+	// TillerNamespace is the namespace in which Tiller runs.
+	TillerNamespace string `json:"tillerNamespace,omitempty"`
+	// TillerHost is the host and port of Tiller.
+	TillerHost string `json:"host,omitempty"`
+	// TLSEnable tells helm to communicate with Tiller via TLS.
+	TLSEnable bool `json:"tls,omitempty"`
+	// TLSVerify tells helm to verify the certificate of Tiller.
+	TLSVerify bool `json:"tlsVerify,omitempty"`
+	// TLSServerName is the hostname the certificate of Tiller is verified against.
+	TLSServerName string `json:"tlsHostname,omitempty"`
+	// TLSCaCertFile is the path to the TLS CA certificate file.
+	TLSCaCertFile string `json:"tlsCaCert,omitempty"`
+	// TLSCertFile is the path to the TLS certificate file.
+	TLSCertFile string `json:"tlsCert,omitempty"`
+	// TLSKeyFile is the path to the TLS key file.
+	TLSKeyFile string `json:"tlsKey,omitempty"`
+}
+
+// Flags returns the settings of the profile that are set, by the name of their flag.
+func (p *Profile) Flags() map[string]string {
+	flags := map[string]string{}
+	add := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	add("home", p.Home)
+	add("kubeconfig", p.KubeConfig)
+	add("kube-context", p.KubeContext)
+	add("namespace", p.Namespace)
+	add("tiller-namespace", p.TillerNamespace)
+	add("host", p.TillerHost)
+	if p.TLSEnable {
+		add("tls", "true")
+	}
+	if p.TLSVerify {
+		add("tls-verify", "true")
+	}
+	add("tls-hostname", p.TLSServerName)
+	add("tls-ca-cert", p.TLSCaCertFile)
+	add("tls-cert", p.TLSCertFile)
+	add("tls-key", p.TLSKeyFile)
+	return flags
+}
+
+// SetFlag sets the setting of the profile with the given flag name, as in
+// Flags. Setting a boolean to anything but "true" unsets it.
+func (p *Profile) SetFlag(name, value string) error {
+	switch name {
+	case "home":
+		p.Home = value
+	case "kubeconfig":
+		p.KubeConfig = value
+	case "kube-context":
+		p.KubeContext = value
+	case "namespace":
+		p.Namespace = value
+	case "tiller-namespace":
+		p.TillerNamespace = value
+	case "host":
+		p.TillerHost = value
+	case "tls":
+		p.TLSEnable = value == "true"
+	case "tls-verify":
+		p.TLSVerify = value == "true"
+	case "tls-hostname":
+		p.TLSServerName = value
+	case "tls-ca-cert":
+		p.TLSCaCertFile = value
+	case "tls-cert":
+		p.TLSCertFile = value
+	case "tls-key":
+		p.TLSKeyFile = value
+	default:
+		return fmt.Errorf("profiles have no setting %q", name)
+	}
+	return nil
+}
+
+// ProfileFlags are the names of the flags of the settings profiles hold.
+var ProfileFlags = []string{
+	"home",
+	"kubeconfig",
+	"kube-context",
+	"namespace",
+	"tiller-namespace",
+	"host",
+	"tls",
+	"tls-verify",
+	"tls-hostname",
+	"tls-ca-cert",
+	"tls-cert",
+	"tls-key",
+}
+
+// ProfilesFile is the file of the profiles.
+type ProfilesFile struct {
+	// Current is the name of the profile used when none is given.
+	Current string `json:"current,omitempty"`
+	// Profiles are the profiles.
+	Profiles []*Profile `json:"profiles"`
+}
+
+// LoadProfilesFile loads a profiles file. A missing file has no profiles.
+func LoadProfilesFile(path string) (*ProfilesFile, error) {
+	f := &ProfilesFile{}
+	b, err := trace.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("cannot load profiles file %s: %s", path, err)
+	}
+	return f, nil
+}
+
+// Get returns the profile with the given name, or nil.
+func (f *ProfilesFile) Get(name string) *Profile {
+	for _, p := range f.Profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Set adds a profile, or replaces the one with the same name.
+func (f *ProfilesFile) Set(p *Profile) {
+	for i, existing := range f.Profiles {
+		if existing.Name == p.Name {
+			f.Profiles[i] = p
+			return
+		}
+	}
+	f.Profiles = append(f.Profiles, p)
+}
+
+// Remove removes the profile with the given name, and returns false if there
+// is none. Removing the current profile leaves no profile current.
+func (f *ProfilesFile) Remove(name string) bool {
+	for i, p := range f.Profiles {
+		if p.Name == name {
+			f.Profiles = append(f.Profiles[:i], f.Profiles[i+1:]...)
+			if f.Current == name {
+				f.Current = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// WriteFile writes the profiles file, creating its directory if needed.
+func (f *ProfilesFile) WriteFile(path string, perm os.FileMode) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return trace.WriteFile(path, data, perm)
+}
+
+// ApplyProfile sets the settings of a profile that are not set by the flags in
+// fs, or by the environment variables of these flags.
+func (s *EnvSettings) ApplyProfile(p *Profile, fs *pflag.FlagSet) {
+	for name, value := range p.Flags() {
+		if fs.Changed(name) {
+			continue
+		}
+		switch name {
+		case "home":
+			s.Home = helmpath.Home(os.ExpandEnv(value))
+		case "kubeconfig":
+			s.KubeConfig = os.ExpandEnv(value)
+		case "kube-context":
+			s.KubeContext = value
+		case "namespace":
+			s.Namespace = value
+		case "tiller-namespace":
+			s.TillerNamespace = value
+		case "host":
+			s.TillerHost = value
+		case "tls":
+			s.TLSEnable = true
+		case "tls-verify":
+			s.TLSVerify = true
+		case "tls-hostname":
+			s.TLSServerName = value
+		case "tls-ca-cert":
+			s.TLSCaCertFile = value
+		case "tls-cert":
+			s.TLSCertFile = value
+		case "tls-key":
+			s.TLSKeyFile = value
+		}
+	}
+}
+
+// Source tells where the setting of a flag in fs comes from: "flag", the
+// environment variable of the flag, "profile" if p sets it, or "default".
+func Source(name string, fs *pflag.FlagSet, p *Profile) string {
+	if fs.Changed(name) {
+		envar, ok := envMap[name]
+		if !ok {
+			envar, ok = tlsEnvMap[name]
+		}
+		if f := fs.Lookup(name); ok && f != nil {
+			if v, set := os.LookupEnv(envar); set && v == f.Value.String() {
+				return "$" + envar
+			}
+		}
+		return "flag"
+	}
+	if p != nil {
+		if _, ok := p.Flags()[name]; ok {
+			return "profile"
+		}
+	}
+	return "default"
+}
@@ -0,0 +1,121 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package environment
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestApplyProfile(t *testing.T) {
+	s := EnvSettings{}
+	fs := pflag.NewFlagSet("testing", pflag.ContinueOnError)
+	s.AddFlags(fs)
+	s.AddFlagsTLS(fs)
+	if err := fs.Parse([]string{"--tiller-namespace", "mine"}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Profile{
+		Name:            "prod",
+		Home:            "/prod",
+		KubeContext:     "prod-cluster",
+		Namespace:       "apps",
+		TillerNamespace: "theirs",
+		TLSVerify:       true,
+	}
+	s.ApplyProfile(p, fs)
+
+	if s.Home != "/prod" {
+		t.Errorf("expected home /prod, got %q", s.Home)
+	}
+	if s.KubeContext != "prod-cluster" {
+		t.Errorf("expected kube context prod-cluster, got %q", s.KubeContext)
+	}
+	if s.Namespace != "apps" {
+		t.Errorf("expected namespace apps, got %q", s.Namespace)
+	}
+	if s.TillerNamespace != "mine" {
+		t.Errorf("expected the flag to override the profile, got tiller namespace %q", s.TillerNamespace)
+	}
+	if !s.TLSVerify {
+		t.Error("expected TLS verification to be enabled")
+	}
+
+	for name, expected := range map[string]string{
+		"tiller-namespace": "flag",
+		"kube-context":     "profile",
+		"host":             "default",
+	} {
+		if got := Source(name, fs, p); got != expected {
+			t.Errorf("expected the source of %s to be %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestProfilesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-profiles-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sub", "profiles.yaml")
+
+	f, err := LoadProfilesFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Profiles) != 0 {
+		t.Fatalf("expected no profiles in a missing file, got %d", len(f.Profiles))
+	}
+
+	f.Set(&Profile{Name: "dev", KubeContext: "minikube"})
+	f.Set(&Profile{Name: "prod", KubeContext: "gke"})
+	f.Set(&Profile{Name: "dev", KubeContext: "kind"})
+	f.Current = "dev"
+	if err := f.WriteFile(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = LoadProfilesFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(f.Profiles))
+	}
+	if p := f.Get("dev"); p == nil || p.KubeContext != "kind" {
+		t.Errorf("expected the dev profile to be replaced, got %+v", p)
+	}
+	p := f.Get("prod")
+	if err := p.SetFlag("tls", "true"); err != nil || !p.TLSEnable {
+		t.Errorf("expected TLS to be enabled, got %t (%v)", p.TLSEnable, err)
+	}
+	if err := p.SetFlag("debug", "true"); err == nil {
+		t.Error("expected an error setting a flag profiles do not hold")
+	}
+	if !f.Remove("dev") || f.Remove("dev") {
+		t.Error("expected the dev profile to be removed once")
+	}
+	if f.Current != "" {
+		t.Errorf("expected no current profile after removing it, got %q", f.Current)
+	}
+}
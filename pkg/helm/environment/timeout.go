@@ -0,0 +1,51 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package environment
+
+import (
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// timeoutValue is a flag holding a timeout in seconds. It accepts a Go
+// duration, such as "5m30s", as well as a bare number of seconds.
+type timeoutValue int64
+
+func (t *timeoutValue) String() string {
+	return timeconv.FormatSeconds(int64(*t))
+}
+
+func (t *timeoutValue) Type() string {
+	return "duration"
+}
+
+func (t *timeoutValue) Set(s string) error {
+	secs, err := timeconv.ParseSeconds(s)
+	if err != nil {
+		return err
+	}
+	*t = timeoutValue(secs)
+	return nil
+}
+
+// TimeoutVar defines a timeout flag with the given name, default value in
+// seconds and usage. The number of seconds is stored in p.
+func TimeoutVar(fs *pflag.FlagSet, p *int64, name string, value int64, usage string) {
+	*p = value
+	fs.Var((*timeoutValue)(p), name, usage)
+}
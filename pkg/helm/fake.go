@@ -19,12 +19,15 @@ package helm // import "k8s.io/helm/pkg/helm"
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/rand"
+	"path"
 	"strings"
 	"sync"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kubeschema"
 	"k8s.io/helm/pkg/manifest"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
@@ -38,8 +41,12 @@ import (
 type FakeClient struct {
 	Rels            []*release.Release
 	Responses       map[string]release.TestRun_Status
+	Readiness       []*release.ResourceReadiness
 	Opts            options
 	RenderManifests bool
+
+	// mu guards Rels against concurrent deletions
+	mu sync.Mutex
 }
 
 // Option returns the fake release client
@@ -61,14 +68,27 @@ func (c *FakeClient) ListReleases(opts ...ReleaseListOption) (*rls.ListReleasesR
 	}
 	req := &reqOpts.listReq
 	rels := c.Rels
-	count := int64(len(c.Rels))
+	if req.ChartName != "" || req.AppVersion != "" {
+		rels = nil
+		for _, r := range c.Rels {
+			md := r.GetChart().GetMetadata()
+			if ok, _ := path.Match(req.ChartName, md.GetName()); !ok && req.ChartName != "" {
+				continue
+			}
+			if ok, _ := path.Match(req.AppVersion, md.GetAppVersion()); !ok && req.AppVersion != "" {
+				continue
+			}
+			rels = append(rels, r)
+		}
+	}
+	count := int64(len(rels))
 	var next string
 	limit := req.GetLimit()
 	// TODO: Handle all other options.
 	if limit != 0 && limit < count {
+		next = rels[limit].GetName()
 		rels = rels[:limit]
 		count = limit
-		next = c.Rels[limit].GetName()
 	}
 
 	resp := &rls.ListReleasesResponse{
@@ -97,7 +117,7 @@ func (c *FakeClient) InstallReleaseFromChart(chart *chart.Chart, ns string, opts
 	releaseDescription := c.Opts.instReq.Description
 
 	// Check to see if the release already exists.
-	rel, err := c.ReleaseStatus(releaseName, nil)
+	rel, err := c.ReleaseStatus(releaseName)
 	if err == nil && rel != nil {
 		return nil, errors.New("cannot re-use a name that is still in use")
 	}
@@ -111,6 +131,8 @@ func (c *FakeClient) InstallReleaseFromChart(chart *chart.Chart, ns string, opts
 	}
 
 	release := ReleaseMock(mockOpts)
+	release.Info.ServiceAccount = c.Opts.instReq.ServiceAccount
+	release.Labels = c.Opts.instReq.Labels
 
 	if c.RenderManifests {
 		if err := RenderReleaseMock(release, false); err != nil {
@@ -129,6 +151,8 @@ func (c *FakeClient) InstallReleaseFromChart(chart *chart.Chart, ns string, opts
 
 // DeleteRelease deletes a release from the FakeClient
 func (c *FakeClient) DeleteRelease(rlsName string, opts ...DeleteOption) (*rls.UninstallReleaseResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for i, rel := range c.Rels {
 		if rel.Name == rlsName {
 			c.Rels = append(c.Rels[:i], c.Rels[i+1:]...)
@@ -161,7 +185,7 @@ func (c *FakeClient) UpdateReleaseFromChart(rlsName string, newChart *chart.Char
 		opt(&c.Opts)
 	}
 	// Check to see if the release already exists.
-	rel, err := c.ReleaseContent(rlsName, nil)
+	rel, err := c.ReleaseContent(rlsName)
 	if err != nil {
 		return nil, err
 	}
@@ -176,6 +200,15 @@ func (c *FakeClient) UpdateReleaseFromChart(rlsName string, newChart *chart.Char
 	}
 
 	newRelease := ReleaseMock(mockOpts)
+	if len(rel.Release.Labels)+len(c.Opts.updateReq.Labels) > 0 {
+		newRelease.Labels = map[string]string{}
+		for k, v := range rel.Release.Labels {
+			newRelease.Labels[k] = v
+		}
+		for k, v := range c.Opts.updateReq.Labels {
+			newRelease.Labels[k] = v
+		}
+	}
 
 	if c.Opts.updateReq.ResetValues {
 		newRelease.Config = &chart.Config{Raw: "{}"}
@@ -203,13 +236,22 @@ func (c *FakeClient) RollbackRelease(rlsName string, opts ...RollbackOption) (*r
 
 // ReleaseStatus returns a release status response with info from the matching release name.
 func (c *FakeClient) ReleaseStatus(rlsName string, opts ...StatusOption) (*rls.GetReleaseStatusResponse, error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
 	for _, rel := range c.Rels {
 		if rel.Name == rlsName {
-			return &rls.GetReleaseStatusResponse{
+			resp := &rls.GetReleaseStatusResponse{
 				Name:      rel.Name,
 				Info:      rel.Info,
 				Namespace: rel.Namespace,
-			}, nil
+			}
+			// there is no cluster, so the health is as it was last recorded
+			if reqOpts.statusReq.Health {
+				resp.Health = rel.Info.Readiness
+			}
+			return resp, nil
 		}
 	}
 	return nil, storageerrors.ErrReleaseNotFound(rlsName)
@@ -217,12 +259,25 @@ func (c *FakeClient) ReleaseStatus(rlsName string, opts ...StatusOption) (*rls.G
 
 // ReleaseContent returns the configuration for the matching release name in the fake release client.
 func (c *FakeClient) ReleaseContent(rlsName string, opts ...ContentOption) (resp *rls.GetReleaseContentResponse, err error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
 	for _, rel := range c.Rels {
-		if rel.Name == rlsName {
-			return &rls.GetReleaseContentResponse{
-				Release: rel,
-			}, nil
+		if rel.Name != rlsName {
+			continue
 		}
+		if v := reqOpts.contentReq.Version; v != 0 && rel.Version != v {
+			continue
+		}
+		resp := &rls.GetReleaseContentResponse{
+			Release: rel,
+		}
+		// there is no cluster, so the objects are as they were stored
+		if reqOpts.contentReq.LiveManifest {
+			resp.LiveManifest = rel.Manifest
+		}
+		return resp, nil
 	}
 	return resp, storageerrors.ErrReleaseNotFound(rlsName)
 }
@@ -257,6 +312,219 @@ func (c *FakeClient) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (
 	return results, errc
 }
 
+// WatchReadiness streams the fake client's readiness reports, whatever the
+// release
+func (c *FakeClient) WatchReadiness(rlsName string, stop <-chan struct{}) (<-chan *release.ResourceReadiness, <-chan error) {
+	ch := make(chan *release.ResourceReadiness)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+		for _, r := range c.Readiness {
+			select {
+			case ch <- r:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return ch, errc
+}
+
+// UpdateReleaseMetadata changes the labels and annotations of a release in the fake client
+func (c *FakeClient) UpdateReleaseMetadata(rlsName string, opts ...MetadataOption) (*rls.UpdateReleaseMetadataResponse, error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.metadataReq
+	for _, rel := range c.Rels {
+		if rel.Name != rlsName {
+			continue
+		}
+		if rel.Labels == nil {
+			rel.Labels = map[string]string{}
+		}
+		if rel.Annotations == nil {
+			rel.Annotations = map[string]string{}
+		}
+		for _, k := range req.RemoveLabels {
+			delete(rel.Labels, k)
+		}
+		for _, k := range req.RemoveAnnotations {
+			delete(rel.Annotations, k)
+		}
+		for k, v := range req.Labels {
+			rel.Labels[k] = v
+		}
+		for k, v := range req.Annotations {
+			rel.Annotations[k] = v
+		}
+		return &rls.UpdateReleaseMetadataResponse{Release: rel}, nil
+	}
+	return nil, storageerrors.ErrReleaseNotFound(rlsName)
+}
+
+// MapReleaseAPIs replaces the apiVersions Kubernetes no longer serves in the
+// manifest of a release in the fake client
+func (c *FakeClient) MapReleaseAPIs(rlsName string, opts ...MapAPIsOption) (*rls.MapReleaseAPIsResponse, error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.mapAPIsReq
+	kubeVersion := req.KubeVersion
+	if kubeVersion == "" {
+		kubeVersion = "1.14"
+	}
+	var rel *release.Release
+	for _, r := range c.Rels {
+		if r.Name == rlsName && (rel == nil || r.Version > rel.Version) {
+			rel = r
+		}
+	}
+	if rel == nil {
+		return nil, storageerrors.ErrReleaseNotFound(rlsName)
+	}
+
+	manifest, mappings, err := kubeschema.MapAPIVersions(rel.Manifest, kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	res := &rls.MapReleaseAPIsResponse{Release: rel}
+	for _, m := range mappings {
+		res.Mappings = append(res.Mappings, m.String())
+	}
+	if !req.DryRun {
+		rel.Manifest = manifest
+	}
+	return res, nil
+}
+
+// GetCapabilities returns fake capabilities
+func (c *FakeClient) GetCapabilities(opts ...CapabilitiesOption) (*rls.GetCapabilitiesResponse, error) {
+	return &rls.GetCapabilitiesResponse{
+		KubeVersion: "v1.14.0",
+		ApiVersions: []string{"apps/v1", "v1"},
+	}, nil
+}
+
+// GetAuditLog returns an entry for every revision of the named release in the
+// fake client's collection
+func (c *FakeClient) GetAuditLog(rlsName string, opts ...AuditOption) (*rls.GetAuditLogResponse, error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	res := &rls.GetAuditLogResponse{}
+	for _, r := range c.Rels {
+		if r.Name != rlsName {
+			continue
+		}
+		e := &rls.AuditEntry{
+			Time:      "2016-01-16T00:00:00Z",
+			Operation: "upgrade",
+			Release:   r.Name,
+			Namespace: r.Namespace,
+			Revision:  r.Version,
+			Outcome:   "success",
+		}
+		if r.Version == 1 {
+			e.Operation = "install"
+		}
+		if r.Info.GetStatus().GetCode() == release.Status_FAILED {
+			e.Outcome = "failure"
+			e.Error = r.Info.Description
+		}
+		if md := r.GetChart().GetMetadata(); md != nil {
+			e.Chart = md.Name + "-" + md.Version
+		}
+		res.Entries = append(res.Entries, e)
+	}
+	if len(res.Entries) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(rlsName)
+	}
+	if max := int(reqOpts.auditReq.Max); max > 0 && len(res.Entries) > max {
+		res.Entries = res.Entries[len(res.Entries)-max:]
+	}
+	return res, nil
+}
+
+// CancelRelease cancels the operation of the named release in the fake client's
+// collection if it is pending, and marks the release as failed
+func (c *FakeClient) CancelRelease(rlsName string) (*rls.CancelReleaseResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.Rels) - 1; i >= 0; i-- {
+		r := c.Rels[i]
+		if r.Name != rlsName {
+			continue
+		}
+		op := pendingOperation(r)
+		if op == "" {
+			return nil, fmt.Errorf("release %s has no operation in progress", rlsName)
+		}
+		r.Info.Status.Code = release.Status_FAILED
+		return &rls.CancelReleaseResponse{Operation: op, Started: "2016-01-16T00:00:00Z"}, nil
+	}
+	return nil, storageerrors.ErrReleaseNotFound(rlsName)
+}
+
+// UnlockRelease removes the lock on the named release in the fake client's
+// collection, which is held while the release is pending
+func (c *FakeClient) UnlockRelease(rlsName string) (*rls.UnlockReleaseResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.Rels) - 1; i >= 0; i-- {
+		r := c.Rels[i]
+		if r.Name != rlsName {
+			continue
+		}
+		op := pendingOperation(r)
+		if op == "" {
+			return nil, fmt.Errorf("release %s is not locked", rlsName)
+		}
+		return &rls.UnlockReleaseResponse{
+			Operation: op,
+			Holder:    "tiller-deploy-0a1b2c3d",
+			Acquired:  "2016-01-16T00:00:00Z",
+			Expires:   "2016-01-16T00:10:00Z",
+		}, nil
+	}
+	return nil, storageerrors.ErrReleaseNotFound(rlsName)
+}
+
+// ImportRelease adds the revisions of a release to the fake client's
+// collection, unless it already holds the release
+func (c *FakeClient) ImportRelease(rlsName string, rels []*release.Release) (*rls.ImportReleaseResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(rels) == 0 {
+		return nil, fmt.Errorf("no revisions of release %s to import", rlsName)
+	}
+	for _, r := range c.Rels {
+		if r.Name == rlsName {
+			return nil, fmt.Errorf("release %s already exists", rlsName)
+		}
+	}
+	c.Rels = append(c.Rels, rels...)
+	return &rls.ImportReleaseResponse{Release: rels[len(rels)-1]}, nil
+}
+
+// pendingOperation returns the operation a pending release is waiting on, or
+// an empty string if it is not pending.
+func pendingOperation(r *release.Release) string {
+	switch r.Info.GetStatus().GetCode() {
+	case release.Status_PENDING_INSTALL:
+		return "install"
+	case release.Status_PENDING_UPGRADE:
+		return "upgrade"
+	case release.Status_PENDING_ROLLBACK:
+		return "rollback"
+	}
+	return ""
+}
+
 // PingTiller pings the Tiller pod and ensures that it is up and running
 func (c *FakeClient) PingTiller() error {
 	return nil
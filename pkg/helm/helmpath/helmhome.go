@@ -82,6 +82,22 @@ func (h Home) Plugins() string {
 	return h.Path("plugins")
 }
 
+// PluginsLockFile returns the path to the file tracking installed plugins.
+func (h Home) PluginsLockFile() string {
+	return h.Path("plugins", "plugins.lock")
+}
+
+// Messages returns the path to the translations of the messages of the CLI.
+func (h Home) Messages() string {
+	return h.Path("messages")
+}
+
+// AuditLog returns the path to the audit log of the releases managed in
+// client-only mode.
+func (h Home) AuditLog() string {
+	return h.Path("audit.log")
+}
+
 // Archive returns the path to download chart archives.
 func (h Home) Archive() string {
 	return h.Path("cache", "archive")
@@ -101,3 +117,10 @@ func (h Home) TLSCert() string {
 func (h Home) TLSKey() string {
 	return h.Path("key.pem")
 }
+
+// TLS returns the path to the certificates generated by 'helm init --tls-auto'.
+func (h Home) TLS(elem ...string) string {
+	p := []string{"tls"}
+	p = append(p, elem...)
+	return h.Path(p...)
+}
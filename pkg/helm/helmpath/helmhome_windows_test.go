@@ -35,7 +35,10 @@ func TestHelmHome(t *testing.T) {
 	isEq(t, hh.CacheIndex("t"), "r:\\repository\\cache\\t-index.yaml")
 	isEq(t, hh.Starters(), "r:\\starters")
 	isEq(t, hh.Archive(), "r:\\cache\\archive")
+	isEq(t, hh.Messages(), "r:\\messages")
+	isEq(t, hh.AuditLog(), "r:\\audit.log")
 	isEq(t, hh.TLSCaCert(), "r:\\ca.pem")
 	isEq(t, hh.TLSCert(), "r:\\cert.pem")
 	isEq(t, hh.TLSKey(), "r:\\key.pem")
+	isEq(t, hh.TLS("ca.key.pem"), "r:\\tls\\ca.key.pem")
 }
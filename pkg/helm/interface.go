@@ -18,6 +18,7 @@ package helm
 
 import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
 )
 
@@ -35,5 +36,13 @@ type Interface interface {
 	ReleaseHistory(rlsName string, opts ...HistoryOption) (*rls.GetHistoryResponse, error)
 	GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, error)
 	RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-chan *rls.TestReleaseResponse, <-chan error)
+	WatchReadiness(rlsName string, stop <-chan struct{}) (<-chan *release.ResourceReadiness, <-chan error)
+	UpdateReleaseMetadata(rlsName string, opts ...MetadataOption) (*rls.UpdateReleaseMetadataResponse, error)
+	MapReleaseAPIs(rlsName string, opts ...MapAPIsOption) (*rls.MapReleaseAPIsResponse, error)
+	GetCapabilities(opts ...CapabilitiesOption) (*rls.GetCapabilitiesResponse, error)
+	GetAuditLog(rlsName string, opts ...AuditOption) (*rls.GetAuditLogResponse, error)
+	CancelRelease(rlsName string) (*rls.CancelReleaseResponse, error)
+	UnlockRelease(rlsName string) (*rls.UnlockReleaseResponse, error)
+	ImportRelease(rlsName string, rels []*release.Release) (*rls.ImportReleaseResponse, error)
 	PingTiller() error
 }
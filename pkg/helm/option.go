@@ -83,6 +83,16 @@ type options struct {
 	testReq rls.TestReleaseRequest
 	// connectTimeout specifies the time duration Helm will wait to establish a connection to tiller
 	connectTimeout time.Duration
+	// release metadata options are applied directly to the update release metadata request
+	metadataReq rls.UpdateReleaseMetadataRequest
+	// map release apis options are applied directly to the map release apis request
+	mapAPIsReq rls.MapReleaseAPIsRequest
+	// capabilities options are applied directly to the get capabilities request
+	capsReq rls.GetCapabilitiesRequest
+	// audit options are applied directly to the get audit log request
+	auditReq rls.GetAuditLogRequest
+	// Kubernetes token of the user, sent to a Tiller impersonating its callers
+	userToken string
 }
 
 // Host specifies the host address of the Tiller release server, (default = ":44134").
@@ -100,6 +110,14 @@ func WithTLS(cfg *tls.Config) Option {
 	}
 }
 
+// UserToken sends the Kubernetes token of the user with every call, so a Tiller
+// started with --user-impersonate applies the releases as that user.
+func UserToken(token string) Option {
+	return func(opts *options) {
+		opts.userToken = token
+	}
+}
+
 // BeforeCall returns an option that allows intercepting a helm client rpc
 // before being sent OTA to tiller. The intercepting function should return
 // an error to indicate that the call should not proceed or nil otherwise.
@@ -166,6 +184,27 @@ func ReleaseListNamespace(namespace string) ReleaseListOption {
 	}
 }
 
+// ReleaseListSelector specifies a label selector to match release labels against.
+func ReleaseListSelector(selector string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.Selector = selector
+	}
+}
+
+// ReleaseListChartName specifies a glob to match the chart name of releases against.
+func ReleaseListChartName(chartName string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.ChartName = chartName
+	}
+}
+
+// ReleaseListAppVersion specifies a glob to match the app version of releases against.
+func ReleaseListAppVersion(appVersion string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.AppVersion = appVersion
+	}
+}
+
 // InstallOption allows specifying various settings
 // configurable by the helm client user for overriding
 // the defaults used when running the `helm install` command.
@@ -234,6 +273,20 @@ func ReleaseTestParallel(parallel bool) ReleaseTestOption {
 	}
 }
 
+// ReleaseTestParallelism limits the number of test pods run at once when running in parallel
+func ReleaseTestParallelism(parallelism uint32) ReleaseTestOption {
+	return func(opts *options) {
+		opts.testReq.Parallelism = parallelism
+	}
+}
+
+// ReleaseTestLogs is a boolean value representing whether to stream the logs of test pods
+func ReleaseTestLogs(logs bool) ReleaseTestOption {
+	return func(opts *options) {
+		opts.testReq.Logs = logs
+	}
+}
+
 // RollbackTimeout specifies the number of seconds before kubernetes calls timeout
 func RollbackTimeout(timeout int64) RollbackOption {
 	return func(opts *options) {
@@ -262,6 +315,111 @@ func RollbackWait(wait bool) RollbackOption {
 	}
 }
 
+// InstallHookTimeout specifies the number of seconds hooks may run, overriding InstallTimeout for hooks
+func InstallHookTimeout(timeout int64) InstallOption {
+	return func(opts *options) {
+		opts.instReq.HookTimeout = timeout
+	}
+}
+
+// UpgradeHookTimeout specifies the number of seconds hooks may run, overriding UpgradeTimeout for hooks
+func UpgradeHookTimeout(timeout int64) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.HookTimeout = timeout
+	}
+}
+
+// DeleteHookTimeout specifies the number of seconds hooks may run, overriding DeleteTimeout for hooks
+func DeleteHookTimeout(timeout int64) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.HookTimeout = timeout
+	}
+}
+
+// RollbackHookTimeout specifies the number of seconds hooks may run, overriding RollbackTimeout for hooks
+func RollbackHookTimeout(timeout int64) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.HookTimeout = timeout
+	}
+}
+
+// InstallLabels specifies the labels of the release, also set on its resources
+func InstallLabels(labels map[string]string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Labels = labels
+	}
+}
+
+// UpgradeLabels specifies labels to add to the release, also set on its resources
+func UpgradeLabels(labels map[string]string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Labels = labels
+	}
+}
+
+// InstallWaitTimeouts overrides the wait timeout, in seconds, for resources of the given kinds
+func InstallWaitTimeouts(timeouts map[string]int64) InstallOption {
+	return func(opts *options) {
+		opts.instReq.WaitTimeouts = timeouts
+	}
+}
+
+// UpgradeWaitTimeouts overrides the wait timeout, in seconds, for resources of the given kinds
+func UpgradeWaitTimeouts(timeouts map[string]int64) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.WaitTimeouts = timeouts
+	}
+}
+
+// RollbackWaitTimeouts overrides the wait timeout, in seconds, for resources of the given kinds
+func RollbackWaitTimeouts(timeouts map[string]int64) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.WaitTimeouts = timeouts
+	}
+}
+
+// InstallWaitForJobs makes the wait also wait for Jobs to complete
+func InstallWaitForJobs(wait bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.WaitForJobs = wait
+	}
+}
+
+// UpgradeWaitForJobs makes the wait also wait for Jobs to complete
+func UpgradeWaitForJobs(wait bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.WaitForJobs = wait
+	}
+}
+
+// RollbackWaitForJobs makes the wait also wait for Jobs to complete
+func RollbackWaitForJobs(wait bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.WaitForJobs = wait
+	}
+}
+
+// InstallWaitConditions sets, by kind, the condition resources must meet to be ready while waiting
+func InstallWaitConditions(conditions map[string]string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.WaitConditions = conditions
+	}
+}
+
+// UpgradeWaitConditions sets, by kind, the condition resources must meet to be ready while waiting
+func UpgradeWaitConditions(conditions map[string]string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.WaitConditions = conditions
+	}
+}
+
+// RollbackWaitConditions sets, by kind, the condition resources must meet to be ready while waiting
+func RollbackWaitConditions(conditions map[string]string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.WaitConditions = conditions
+	}
+}
+
 // UpdateValueOverrides specifies a list of values to include when upgrading
 func UpdateValueOverrides(raw []byte) UpdateOption {
 	return func(opts *options) {
@@ -283,6 +441,14 @@ func UpgradeDescription(description string) UpdateOption {
 	}
 }
 
+// UpgradeOnly restricts the update to the resources matching one of the
+// selectors, either a template path, "kind=KIND" or "labels=SELECTOR".
+func UpgradeOnly(only []string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Only = only
+	}
+}
+
 // RollbackDescription specifies the description for the release
 func RollbackDescription(description string) RollbackOption {
 	return func(opts *options) {
@@ -297,6 +463,45 @@ func DeleteDescription(description string) DeleteOption {
 	}
 }
 
+// InstallOverrideWindow lets the install proceed outside the maintenance
+// windows, for the given reason
+func InstallOverrideWindow(reason string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.OverrideWindow = reason
+	}
+}
+
+// UpgradeOverrideWindow lets the upgrade proceed outside the maintenance
+// windows of the release, for the given reason
+func UpgradeOverrideWindow(reason string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.OverrideWindow = reason
+	}
+}
+
+// RollbackOverrideWindow lets the rollback proceed outside the maintenance
+// windows of the release, for the given reason
+func RollbackOverrideWindow(reason string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.OverrideWindow = reason
+	}
+}
+
+// DeleteOverrideWindow lets the deletion proceed outside the maintenance
+// windows of the release, for the given reason
+func DeleteOverrideWindow(reason string) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.OverrideWindow = reason
+	}
+}
+
+// InstallCleanupOnFail allows deletion of new resources created in this install when install failed
+func InstallCleanupOnFail(cleanupOnFail bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.CleanupOnFail = cleanupOnFail
+	}
+}
+
 // UpgradeCleanupOnFail allows deletion of new resources created in this upgrade when upgrade failed
 func UpgradeCleanupOnFail(cleanupOnFail bool) UpdateOption {
 	return func(opts *options) {
@@ -311,6 +516,22 @@ func RollbackCleanupOnFail(cleanupOnFail bool) RollbackOption {
 	}
 }
 
+// RollbackOnly restricts the rollback to the resources matching one of the
+// selectors, as for UpgradeOnly.
+func RollbackOnly(only []string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.Only = only
+	}
+}
+
+// RollbackValuesOnly rolls back the values only, rendering the current chart
+// again with the values of the target revision.
+func RollbackValuesOnly(valuesOnly bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.ValuesOnly = valuesOnly
+	}
+}
+
 // DeleteDisableHooks will disable hooks for a deletion operation.
 func DeleteDisableHooks(disable bool) DeleteOption {
 	return func(opts *options) {
@@ -332,6 +553,22 @@ func DeletePurge(purge bool) DeleteOption {
 	}
 }
 
+// DeleteCascade sets how the dependents of the deleted resources are handled:
+// "background", "foreground" or "orphan".
+func DeleteCascade(cascade string) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.Cascade = cascade
+	}
+}
+
+// DeleteForce will (if true) delete a release even when other releases
+// require a capability it provides.
+func DeleteForce(force bool) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.Force = force
+	}
+}
+
 // InstallDryRun will (if true) execute an installation as a dry run.
 func InstallDryRun(dry bool) InstallOption {
 	return func(opts *options) {
@@ -374,6 +611,80 @@ func UpgradeSubNotes(enable bool) UpdateOption {
 	}
 }
 
+// InstallStrict will (if true) instruct Tiller to fail rendering on missing values
+func InstallStrict(strict bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Strict = strict
+	}
+}
+
+// UpgradeStrict will (if true) instruct Tiller to fail rendering on missing values
+func UpgradeStrict(strict bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Strict = strict
+	}
+}
+
+// InstallServiceAccount instructs Tiller to apply the release as a service
+// account of the release namespace.
+func InstallServiceAccount(name string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.ServiceAccount = name
+	}
+}
+
+// InstallCreateNamespace will (if true) instruct Tiller to create the namespace
+// of the release if it does not exist
+func InstallCreateNamespace(create bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.CreateNamespace = create
+	}
+}
+
+// InstallTakeOwnership will (if true) instruct Tiller to adopt the resources
+// of the release that already exist instead of failing
+func InstallTakeOwnership(take bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.TakeOwnership = take
+	}
+}
+
+// InstallSkipCRDs will (if true) prevent Tiller from installing the custom
+// resource definitions of the crds directory of the chart
+func InstallSkipCRDs(skip bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.SkipCrds = skip
+	}
+}
+
+// InstallSortOrder overrides the order Tiller applies the resources of the
+// release in, as comma-separated lists of kinds. An empty uninstall order is
+// the reverse of the install order.
+func InstallSortOrder(install, uninstall string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.InstallOrder = install
+		opts.instReq.UninstallOrder = uninstall
+	}
+}
+
+// UpgradeServiceAccount instructs Tiller to apply the upgrade as a service
+// account of the release namespace, instead of the one of the current release.
+func UpgradeServiceAccount(name string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.ServiceAccount = name
+	}
+}
+
+// UpgradeSortOrder overrides the order Tiller applies the resources of the
+// release in, as comma-separated lists of kinds. The orders are kept for the
+// later revisions of the release.
+func UpgradeSortOrder(install, uninstall string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.InstallOrder = install
+		opts.updateReq.UninstallOrder = uninstall
+	}
+}
+
 // RollbackDisableHooks will disable hooks for a rollback operation
 func RollbackDisableHooks(disable bool) RollbackOption {
 	return func(opts *options) {
@@ -464,6 +775,14 @@ func ContentReleaseVersion(version int32) ContentOption {
 	}
 }
 
+// ContentLiveManifest will instruct Tiller to also read the current state of
+// the objects of the release from the cluster.
+func ContentLiveManifest(live bool) ContentOption {
+	return func(opts *options) {
+		opts.contentReq.LiveManifest = live
+	}
+}
+
 // StatusOption allows setting optional attributes when
 // performing a GetReleaseStatus tiller rpc.
 type StatusOption func(*options)
@@ -476,6 +795,13 @@ func StatusReleaseVersion(version int32) StatusOption {
 	}
 }
 
+// StatusReleaseHealth will request the current state of each resource of the release.
+func StatusReleaseHealth(health bool) StatusOption {
+	return func(opts *options) {
+		opts.statusReq.Health = health
+	}
+}
+
 // DeleteOption allows setting optional attributes when
 // performing a UninstallRelease tiller rpc.
 type DeleteOption func(*options)
@@ -505,6 +831,92 @@ func WithMaxHistory(max int32) HistoryOption {
 	}
 }
 
+// MetadataOption allows configuring optional request data for
+// issuing an UpdateReleaseMetadata rpc.
+type MetadataOption func(*options)
+
+// MetadataReleaseVersion will instruct Tiller to change the metadata
+// of a particular version of a release.
+func MetadataReleaseVersion(version int32) MetadataOption {
+	return func(opts *options) {
+		opts.metadataReq.Version = version
+	}
+}
+
+// MetadataLabels sets labels on a release and removes the labels with the given keys.
+func MetadataLabels(set map[string]string, remove []string) MetadataOption {
+	return func(opts *options) {
+		opts.metadataReq.Labels = set
+		opts.metadataReq.RemoveLabels = remove
+	}
+}
+
+// MetadataAnnotations sets annotations on a release and removes the annotations with the given keys.
+func MetadataAnnotations(set map[string]string, remove []string) MetadataOption {
+	return func(opts *options) {
+		opts.metadataReq.Annotations = set
+		opts.metadataReq.RemoveAnnotations = remove
+	}
+}
+
+// MetadataPropagate will instruct Tiller to apply the change to the
+// resources of the release as well.
+func MetadataPropagate(propagate bool) MetadataOption {
+	return func(opts *options) {
+		opts.metadataReq.Propagate = propagate
+	}
+}
+
+// MapAPIsOption allows configuring optional request data for
+// issuing a MapReleaseAPIs rpc.
+type MapAPIsOption func(*options)
+
+// MapAPIsKubeVersion sets the Kubernetes version to map the apiVersions of a
+// release for, instead of the version of the cluster.
+func MapAPIsKubeVersion(kubeVersion string) MapAPIsOption {
+	return func(opts *options) {
+		opts.mapAPIsReq.KubeVersion = kubeVersion
+	}
+}
+
+// MapAPIsDryRun will instruct Tiller to report the apiVersions to map
+// without changing the release.
+func MapAPIsDryRun(dry bool) MapAPIsOption {
+	return func(opts *options) {
+		opts.mapAPIsReq.DryRun = dry
+	}
+}
+
+// CapabilitiesOption allows configuring optional request data for
+// issuing a GetCapabilities rpc.
+type CapabilitiesOption func(*options)
+
+// CapabilitiesRefresh will instruct Tiller to discover the capabilities of
+// the cluster again instead of returning the cached ones.
+func CapabilitiesRefresh(refresh bool) CapabilitiesOption {
+	return func(opts *options) {
+		opts.capsReq.Refresh = refresh
+	}
+}
+
+// CapabilitiesChartName will make Tiller return the limits of the named chart.
+func CapabilitiesChartName(name string) CapabilitiesOption {
+	return func(opts *options) {
+		opts.capsReq.ChartName = name
+	}
+}
+
+// AuditOption allows configuring optional request data for
+// issuing a GetAuditLog rpc.
+type AuditOption func(*options)
+
+// AuditMax limits the audit log to the most recent entries.
+func AuditMax(max int32) AuditOption {
+	return func(opts *options) {
+		opts.auditReq.Max = max
+	}
+}
+
 // NewContext creates a versioned context.
 func NewContext() context.Context {
 	md := metadata.Pairs("x-helm-api-client", version.GetVersion())
@@ -27,8 +27,16 @@ const (
 	HookWeightAnno = "helm.sh/hook-weight"
 	// HookDeleteAnno is the label name for the delete policy for a hook
 	HookDeleteAnno = "helm.sh/hook-delete-policy"
-	// HookDeleteTimeoutAnno is the label name for the timeout value for delete policies
+	// HookDeleteTimeoutAnno is the label name for the timeout value for delete policies, as a duration or in seconds
 	HookDeleteTimeoutAnno = "helm.sh/hook-delete-timeout"
+	// HookTimeoutAnno is the label name for the time a hook may run, as a duration or in seconds
+	HookTimeoutAnno = "helm.sh/hook-timeout"
+	// HookRetriesAnno is the label name for the number of times a failed hook is retried
+	HookRetriesAnno = "helm.sh/hook-retries"
+	// HookRetryBackoffAnno is the label name for the time to wait before retrying a hook, as a duration or in seconds
+	HookRetryBackoffAnno = "helm.sh/hook-retry-backoff"
+	// HookFailurePolicyAnno is the label name for what to do when a hook fails
+	HookFailurePolicyAnno = "helm.sh/hook-failure-policy"
 )
 
 // Types of hooks
@@ -44,6 +52,8 @@ const (
 	ReleaseTestSuccess = "test-success"
 	ReleaseTestFailure = "test-failure"
 	CRDInstall         = "crd-install"
+	TestSetup          = "test-setup"
+	TestTeardown       = "test-teardown"
 )
 
 // Type of policy for deleting the hook
@@ -53,6 +63,17 @@ const (
 	BeforeHookCreation = "before-hook-creation"
 )
 
+// Type of policy for a failed hook
+const (
+	// HookAbort fails the operation running the hook. It is the default.
+	HookAbort = "abort"
+	// HookIgnore runs the next hooks as if the hook had succeeded.
+	HookIgnore = "ignore"
+	// HookRollback fails the operation, and undoes it: an upgrade is rolled
+	// back to the previous revision, and an install is deleted.
+	HookRollback = "rollback"
+)
+
 // FilterTestHooks filters the list of hooks are returns only testing hooks.
 func FilterTestHooks(hooks []*release.Hook) []*release.Hook {
 	testHooks := []*release.Hook{}
@@ -14,54 +14,64 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-/*Package ignore provides tools for writing ignore files (a la .gitignore).
-
-This provides both an ignore parser and a file-aware processor.
-
-The format of ignore files closely follows, but does not exactly match, the
-format for .gitignore files (https://git-scm.com/docs/gitignore).
-
-The formatting rules are as follows:
-
-	- Parsing is line-by-line
-	- Empty lines are ignored
-	- Lines the begin with # (comments) will be ignored
-	- Leading and trailing spaces are always ignored
-	- Inline comments are NOT supported ('foo* # Any foo' does not contain a comment)
-	- There is no support for multi-line patterns
-	- Shell glob patterns are supported. See Go's "path/filepath".Match
-	- If a pattern begins with a leading !, the match will be negated.
-	- If a pattern begins with a leading /, only paths relatively rooted will match.
-	- If the pattern ends with a trailing /, only directories will match
-	- If a pattern contains no slashes, file basenames are tested (not paths)
-	- The pattern sequence "**", while legal in a glob, will cause an error here
-	  (to indicate incompatibility with .gitignore).
-
-Example:
-
-	# Match any file named foo.txt
-	foo.txt
-
-	# Match any text file
-	*.txt
-
-	# Match only directories named mydir
-	mydir/
-
-	# Match only text files in the top-level directory
-	/*.txt
-
-	# Match only the file foo.txt in the top-level directory
-	/foo.txt
-
-	# Match any file named ab.txt, ac.txt, or ad.txt
-	a[b-d].txt
-
-Notable differences from .gitignore:
-	- The '**' syntax is not supported.
-	- The globbing library is Go's 'filepath.Match', not fnmatch(3)
-	- Trailing spaces are always ignored (there is no supported escape sequence)
-	- The evaluation of escape sequences has not been tested for compatibility
-	- There is no support for '\!' as a special leading sequence.
-*/
+// Package ignore provides tools for writing ignore files (a la .gitignore).
+//
+// This provides both an ignore parser and a file-aware processor.
+//
+// The format of ignore files closely follows the format for .gitignore files
+// (https://git-scm.com/docs/gitignore).
+//
+// The formatting rules are as follows:
+//
+//   - Parsing is line-by-line
+//   - Empty lines are ignored
+//   - Lines the begin with # (comments) will be ignored
+//   - Leading and trailing spaces are always ignored
+//   - Inline comments are NOT supported ('foo* # Any foo' does not contain a comment)
+//   - There is no support for multi-line patterns
+//   - Shell glob patterns are supported. See Go's "path/filepath".Match
+//   - All patterns are evaluated, and the last pattern that matches decides
+//     whether a path is ignored
+//   - If a pattern begins with a leading !, a matching path is not ignored,
+//     even if an earlier pattern ignored it. A file cannot be re-included if
+//     its directory is ignored.
+//   - If a pattern begins with, or contains, a /, only paths relatively rooted will match.
+//   - If the pattern ends with a trailing /, only directories will match
+//   - If a pattern contains no slashes, file basenames are tested (not paths)
+//   - A leading "**/" matches in all directories, a trailing "/**" matches
+//     everything inside a directory, and "/**/" matches zero or more
+//     directories. Any other use of "**" is an error.
+//
+// Example:
+//
+//	# Match any file named foo.txt
+//	foo.txt
+//
+//	# Match any text file
+//	*.txt
+//
+//	# Match only directories named mydir
+//	mydir/
+//
+//	# Match only text files in the top-level directory
+//	/*.txt
+//
+//	# Match only the file foo.txt in the top-level directory
+//	/foo.txt
+//
+//	# Match any file named ab.txt, ac.txt, or ad.txt
+//	a[b-d].txt
+//
+//	# Match any text file, except for NOTES.txt
+//	*.txt
+//	!NOTES.txt
+//
+//	# Match any file named foo.txt under a directory named docs
+//	docs/**/foo.txt
+//
+// Notable differences from .gitignore:
+//   - The globbing syntax is Go's 'filepath.Match', not fnmatch(3)
+//   - Trailing spaces are always ignored (there is no supported escape sequence)
+//   - The evaluation of escape sequences has not been tested for compatibility
+//   - There is no support for '\!' as a special leading sequence.
 package ignore // import "k8s.io/helm/pkg/ignore"
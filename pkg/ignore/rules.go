@@ -18,11 +18,14 @@ package ignore
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -45,8 +48,14 @@ func Empty() *Rules {
 // AddDefaults adds default ignore patterns.
 //
 // Ignore all dotfiles in "templates/"
+//
+// The defaults are evaluated before the parsed rules, so they can be
+// overridden by a negated rule.
 func (r *Rules) AddDefaults() {
+	parsed := r.patterns
+	r.patterns = []*pattern{}
 	r.parseRule(`templates/.?*`)
+	r.patterns = append(r.patterns, parsed...)
 }
 
 // ParseFile parses a helmignore file and returns the *Rules.
@@ -79,36 +88,36 @@ func (r *Rules) Len() int {
 
 // Ignore evaluates the file at the given path, and returns true if it should be ignored.
 //
-// Ignore evaluates path against the rules in order. Evaluation stops when a match
-// is found. Matching a negative rule will stop evaluation.
+// Ignore evaluates path against all of the rules, and the last rule that
+// matches decides the outcome. A negative rule that matches means the path
+// is not ignored.
 func (r *Rules) Ignore(path string, fi os.FileInfo) bool {
+	ignored, _ := r.Explain(path, fi)
+	return ignored
+}
+
+// Explain evaluates the file at the given path like Ignore, and also returns
+// the rule that decided the outcome, as written in the ignore file. The rule
+// is empty when no rule matches.
+func (r *Rules) Explain(path string, fi os.FileInfo) (bool, string) {
 	// Don't match on empty dirs.
 	if path == "" {
-		return false
+		return false, ""
 	}
 
 	// Disallow ignoring the current working directory.
 	// See issue:
 	// 1776 (New York City) Hamilton: "Pardon me, are you Aaron Burr, sir?"
 	if path == "." || path == "./" {
-		return false
+		return false, ""
 	}
+	path = strings.TrimSuffix(path, "/")
+
+	var last *pattern
 	for _, p := range r.patterns {
 		if p.match == nil {
 			log.Printf("ignore: no matcher supplied for %q", p.raw)
-			return false
-		}
-
-		// For negative rules, we need to capture and return non-matches,
-		// and continue for matches.
-		if p.negate {
-			if p.mustDir && !fi.IsDir() {
-				return true
-			}
-			if !p.match(path, fi) {
-				return true
-			}
-			continue
+			return false, ""
 		}
 
 		// If the rule is looking for directories, and this is not a directory,
@@ -117,10 +126,13 @@ func (r *Rules) Ignore(path string, fi os.FileInfo) bool {
 			continue
 		}
 		if p.match(path, fi) {
-			return true
+			last = p
 		}
 	}
-	return false
+	if last == nil {
+		return false, ""
+	}
+	return !last.negate, last.raw
 }
 
 // parseRule parses a rule string and creates a pattern, which is then stored in the Rules object.
@@ -136,11 +148,6 @@ func (r *Rules) parseRule(rule string) error {
 		return nil
 	}
 
-	// Fail any rules that contain **
-	if strings.Contains(rule, "**") {
-		return errors.New("double-star (**) syntax is not supported")
-	}
-
 	// Fail any patterns that can't compile. A non-empty string must be
 	// given to Match() to avoid optimization that skips rule evaluation.
 	if _, err := filepath.Match(rule, "abc"); err != nil {
@@ -164,43 +171,79 @@ func (r *Rules) parseRule(rule string) error {
 		rule = strings.TrimSuffix(rule, "/")
 	}
 
-	if strings.HasPrefix(rule, "/") {
-		// Require path matches the root path.
-		p.match = func(n string, fi os.FileInfo) bool {
-			rule = strings.TrimPrefix(rule, "/")
-			ok, err := filepath.Match(rule, n)
-			if err != nil {
-				log.Printf("Failed to compile %q: %s", rule, err)
-				return false
+	// A rule with a slash at the beginning or in the middle matches paths
+	// relative to the root. Any other rule matches at any depth.
+	anchored := strings.Contains(rule, "/")
+	rule = strings.TrimPrefix(rule, "/")
+
+	expr, err := globToRegexp(rule)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %s", p.raw, err)
+	}
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %s", p.raw, err)
+	}
+	p.match = func(n string, fi os.FileInfo) bool {
+		return re.MatchString(n)
+	}
+
+	r.patterns = append(r.patterns, p)
+	return nil
+}
+
+// globToRegexp translates a glob into a regular expression. A "*" matches
+// anything but a slash, while "**/" matches any number of directories and a
+// trailing "/**" matches everything inside a directory.
+func globToRegexp(glob string) (string, error) {
+	var b bytes.Buffer
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if !strings.HasPrefix(glob[i:], "**") {
+				b.WriteString("[^/]*")
+				continue
 			}
-			return ok
-		}
-	} else if strings.Contains(rule, "/") {
-		// require structural match.
-		p.match = func(n string, fi os.FileInfo) bool {
-			ok, err := filepath.Match(rule, n)
-			if err != nil {
-				log.Printf("Failed to compile %q: %s", rule, err)
-				return false
+			if i > 0 && glob[i-1] != '/' {
+				return "", errors.New("double-star (**) must be a whole path segment")
 			}
-			return ok
-		}
-	} else {
-		p.match = func(n string, fi os.FileInfo) bool {
-			// When there is no slash in the pattern, we evaluate ONLY the
-			// filename.
-			n = filepath.Base(n)
-			ok, err := filepath.Match(rule, n)
-			if err != nil {
-				log.Printf("Failed to compile %q: %s", rule, err)
-				return false
+			i++
+			switch {
+			case i+1 == len(glob):
+				b.WriteString(".*")
+			case glob[i+1] == '/':
+				b.WriteString("(?:.*/)?")
+				i++
+			default:
+				return "", errors.New("double-star (**) must be a whole path segment")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return "", errors.New("unterminated character class")
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
 			}
-			return ok
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
 		}
 	}
-
-	r.patterns = append(r.patterns, p)
-	return nil
+	return b.String(), nil
 }
 
 // matcher is a function capable of computing a match.
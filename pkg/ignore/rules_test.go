@@ -56,7 +56,7 @@ one/more
 }
 
 func TestParseFail(t *testing.T) {
-	shouldFail := []string{"foo/**/bar", "[z-"}
+	shouldFail := []string{"foo**", "**bar", "foo/**bar", "[z-"}
 	for _, fail := range shouldFail {
 		_, err := parseString(fail)
 		if err == nil {
@@ -114,14 +114,25 @@ func TestIgnore(t *testing.T) {
 
 		// Negation tests
 		{`!helm.txt`, "helm.txt", false},
-		{`!helm.txt`, "tiller.txt", true},
-		{`!*.txt`, "cargo", true},
-		{`!cargo/`, "mast/", true},
+		{`!helm.txt`, "tiller.txt", false},
+		{`!*.txt`, "cargo", false},
+		{`!cargo/`, "mast/", false},
 
 		// Absolute path tests
 		{`/a.txt`, "a.txt", true},
 		{`/a.txt`, "cargo/a.txt", false},
 		{`/cargo/a.txt`, "cargo/a.txt", true},
+		{`/*.txt`, "a.txt", true},
+		{`/*.txt`, "cargo/a.txt", false},
+
+		// Double-star tests
+		{`**/a.txt`, "a.txt", true},
+		{`**/a.txt`, "cargo/a.txt", true},
+		{`**/a.txt`, "cargo/b.txt", false},
+		{`cargo/**`, "cargo/a.txt", true},
+		{`cargo/**`, "cargo", false},
+		{`cargo/**/b.txt`, "cargo/b.txt", true},
+		{`**/cargo/`, "cargo", true},
 	}
 
 	for _, test := range tests {
@@ -149,6 +160,57 @@ func TestAddDefaults(t *testing.T) {
 	}
 }
 
+func TestIgnoreLastMatchWins(t *testing.T) {
+	r, err := parseString("*.txt\n!cargo/*.txt\ncargo/c.txt\n")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		expect bool
+		rule   string
+	}{
+		{"tiller.txt", true, "*.txt"},
+		{"mast/a.txt", true, "*.txt"},
+		{"cargo/a.txt", false, "!cargo/*.txt"},
+		{"cargo/c.txt", true, "cargo/c.txt"},
+		{"cargo", false, ""},
+	}
+	for _, test := range tests {
+		fi, err := os.Stat(filepath.Join(testdata, test.name))
+		if err != nil {
+			t.Fatalf("Fixture missing: %s", err)
+		}
+		ignored, rule := r.Explain(test.name, fi)
+		if ignored != test.expect || rule != test.rule {
+			t.Errorf("Expected %q to be %v by rule %q, got %v by rule %q", test.name, test.expect, test.rule, ignored, rule)
+		}
+	}
+}
+
+func TestAddDefaultsOverride(t *testing.T) {
+	fi, err := os.Stat(filepath.Join(testdata, "templates/.dotfile"))
+	if err != nil {
+		t.Fatalf("Fixture missing: %s", err)
+	}
+
+	r := Empty()
+	r.AddDefaults()
+	if !r.Ignore("templates/.dotfile", fi) {
+		t.Error("Expected dotfiles in templates/ to be ignored by default")
+	}
+
+	r, err = parseString("!templates/.dotfile")
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	r.AddDefaults()
+	if r.Ignore("templates/.dotfile", fi) {
+		t.Error("Expected a negated rule to override the defaults")
+	}
+}
+
 func parseString(str string) (*Rules, error) {
 	b := bytes.NewBuffer([]byte(str))
 	return Parse(b)
@@ -0,0 +1,102 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	// ReleaseNameAnno is the annotation naming the release that took
+	// ownership of an existing resource.
+	ReleaseNameAnno = "helm.sh/release-name"
+
+	// ReleaseNamespaceAnno is the annotation holding the namespace of the
+	// release that took ownership of an existing resource.
+	ReleaseNamespaceAnno = "helm.sh/release-namespace"
+)
+
+// adoptResource takes over a resource that already exists. The manifest of
+// info is merged into the live resource, along with the labels and
+// annotations of owner.
+//
+// A resource whose annotations already give another value to one of the
+// annotations of owner belongs to someone else, and is not adopted.
+func adoptResource(info *resource.Info, owner MetadataChange) error {
+	kind := info.Mapping.GroupVersionKind.Kind
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	live, err := helper.Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return err
+	}
+	annotations, err := metadataAccessor.Annotations(live)
+	if err != nil {
+		return err
+	}
+	for k, v := range owner.Annotations {
+		if current, ok := annotations[k]; ok && current != v {
+			return fmt.Errorf("cannot take ownership of %s %q: its %s annotation is %q", kind, info.Name, k, current)
+		}
+	}
+
+	if err := mergeMetadata(info.Object, owner); err != nil {
+		return err
+	}
+	patch, err := json.Marshal(info.Object)
+	if err != nil {
+		return fmt.Errorf("serializing %s %q: %s", kind, info.Name, err)
+	}
+	obj, err := helper.Patch(info.Namespace, info.Name, types.MergePatchType, patch, nil)
+	if err != nil {
+		return fmt.Errorf("cannot take ownership of %s %q: %s", kind, info.Name, err)
+	}
+	return info.Refresh(obj, true)
+}
+
+// mergeMetadata adds the labels and annotations of change to obj.
+func mergeMetadata(obj runtime.Object, change MetadataChange) error {
+	labels, err := metadataAccessor.Labels(obj)
+	if err != nil {
+		return err
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range change.Labels {
+		labels[k] = v
+	}
+	if err := metadataAccessor.SetLabels(obj, labels); err != nil {
+		return err
+	}
+
+	annotations, err := metadataAccessor.Annotations(obj)
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range change.Annotations {
+		annotations[k] = v
+	}
+	return metadataAccessor.SetAnnotations(obj, annotations)
+}
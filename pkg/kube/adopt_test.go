@@ -0,0 +1,85 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestAdoptResource(t *testing.T) {
+	pods := newPodList("starfish", "otter")
+	owned := newPod("otter")
+	owned.Annotations = map[string]string{ReleaseNameAnno: "other"}
+
+	var patch string
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &pods.Items[0])
+			case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not read request: %s", err)
+				}
+				patch = string(data)
+				return newResponse(200, &pods.Items[0])
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &owned)
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+	c := &Client{Factory: tf, Log: nopLogger}
+
+	infos, err := c.BuildUnstructured(v1.NamespaceDefault, objBody(&pods))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := MetadataChange{
+		Labels:      map[string]string{"app.kubernetes.io/managed-by": "Tiller"},
+		Annotations: map[string]string{ReleaseNameAnno: "mine"},
+	}
+
+	if err := adoptResource(infos[0], owner); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{`"helm.sh/release-name":"mine"`, `"app.kubernetes.io/managed-by":"Tiller"`, `"containers":`} {
+		if !strings.Contains(patch, expect) {
+			t.Errorf("expected %s in the patch, got %s", expect, patch)
+		}
+	}
+
+	if err := adoptResource(infos[1], owner); err == nil {
+		t.Error("expected a resource owned by another release not to be adopted")
+	} else if !strings.Contains(err.Error(), `is "other"`) {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
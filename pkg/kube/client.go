@@ -47,10 +47,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	cachetools "k8s.io/client-go/tools/cache"
 	watchtools "k8s.io/client-go/tools/watch"
@@ -73,6 +73,9 @@ var metadataAccessor = meta.NewAccessor()
 type Client struct {
 	cmdutil.Factory
 	Log func(string, ...interface{})
+
+	// ctx stops the waits and watches of the client when it is done.
+	ctx context.Context
 }
 
 // New creates a new Client.
@@ -101,6 +104,43 @@ type ResourceActorFunc func(*resource.Info) error
 //
 // Namespace will set the namespace.
 func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+	return c.CreateWithOptions(namespace, reader, CreateOptions{
+		Timeout:    timeout,
+		ShouldWait: shouldWait,
+	})
+}
+
+// CreateOptions provides options to control create behavior
+type CreateOptions struct {
+	Timeout    int64
+	ShouldWait bool
+	// WaitTimeouts overrides Timeout when waiting on resources of the given kind.
+	WaitTimeouts map[string]time.Duration
+	// OnReadiness is called whenever the readiness of a resource changes while waiting.
+	OnReadiness func(ReadinessEvent)
+	// WaitForJobs makes the wait also wait for Jobs to complete.
+	WaitForJobs bool
+	// WaitConditions gives, by kind, the condition resources of the kind must
+	// meet to be ready while waiting, as PATH==VALUE.
+	WaitConditions map[string]string
+	// CleanupOnFail deletes the resources created so far if the creation or
+	// the wait fails.
+	CleanupOnFail bool
+	// Adopt, when set, takes over the resources that already exist instead
+	// of failing: the manifest is merged into them, along with the labels and
+	// annotations of the change. Adopted resources are not deleted by
+	// CleanupOnFail.
+	Adopt *MetadataChange
+	// SkipExisting leaves the resources that already exist as they are
+	// instead of failing.
+	SkipExisting bool
+}
+
+// CreateWithOptions creates Kubernetes resources from an io.reader.
+//
+// Namespace will set the namespace. CreateOptions provides additional parameters
+// to control create behavior.
+func (c *Client) CreateWithOptions(namespace string, reader io.Reader, opts CreateOptions) error {
 	client, err := c.KubernetesClientSet()
 	if err != nil {
 		return err
@@ -114,13 +154,37 @@ func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shoul
 		return buildErr
 	}
 	c.Log("creating %d resource(s)", len(infos))
-	if err := perform(infos, createResource); err != nil {
-		return err
+	var created []*resource.Info
+	err = perform(infos, func(info *resource.Info) error {
+		err := createResource(info)
+		if err != nil && opts.Adopt != nil && errors.IsAlreadyExists(err) {
+			c.Log("adopting existing %s %q", info.Mapping.GroupVersionKind.Kind, info.Name)
+			return adoptResource(info, *opts.Adopt)
+		}
+		if err != nil && opts.SkipExisting && errors.IsAlreadyExists(err) {
+			c.Log("skipping existing %s %q", info.Mapping.GroupVersionKind.Kind, info.Name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		created = append(created, info)
+		return nil
+	})
+	if err == nil && opts.ShouldWait {
+		err = c.waitForResourcesWithOptions(infos, WaitOptions{
+			Timeout:      time.Duration(opts.Timeout) * time.Second,
+			KindTimeouts: opts.WaitTimeouts,
+			OnEvent:      opts.OnReadiness,
+			Jobs:         opts.WaitForJobs,
+			Conditions:   opts.WaitConditions,
+		})
 	}
-	if shouldWait {
-		return c.waitForResources(time.Duration(timeout)*time.Second, infos)
+	if err != nil && opts.CleanupOnFail {
+		c.Log("Cleanup on fail enabled: cleaning up newly created resources due to install failure")
+		return withCleanupErrors(err, c.cleanup(created))
 	}
-	return nil
+	return err
 }
 
 func (c *Client) newBuilder(namespace string, reader io.Reader) *resource.Result {
@@ -341,6 +405,18 @@ type UpdateOptions struct {
 	ShouldWait bool
 	// Allow deletion of new resources created in this update when update failed
 	CleanupOnFail bool
+	// WaitTimeouts overrides Timeout when waiting on resources of the given kind.
+	WaitTimeouts map[string]time.Duration
+	// OnReadiness is called whenever the readiness of a resource changes while waiting.
+	OnReadiness func(ReadinessEvent)
+	// WaitForJobs makes the wait also wait for Jobs to complete.
+	WaitForJobs bool
+	// WaitConditions gives, by kind, the condition resources of the kind must
+	// meet to be ready while waiting, as PATH==VALUE.
+	WaitConditions map[string]string
+	// Rollback marks the update as a rollback, which orphans the removed resources
+	// whose resource policy asks for it instead of deleting them.
+	Rollback bool
 }
 
 // UpdateWithOptions reads the current configuration and a target configuration from io.reader
@@ -403,7 +479,7 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 			)
 		}
 
-		if err := updateResource(c, info, originalInfo.Object, opts.Force, opts.Recreate); err != nil {
+		if err := updateResource(c, info, originalInfo.Object, opts.Force, opts.Recreate, opts.Timeout); err != nil {
 			c.Log("error updating the resource %q:\n\t %v", info.Name, err)
 			updateErrors = append(updateErrors, err.Error())
 		}
@@ -435,7 +511,7 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 		if err != nil {
 			c.Log("Unable to get annotations on %q, err: %s", info.Name, err)
 		}
-		if ResourcePolicyIsKeep(annotations) {
+		if ResourcePolicyIsKeep(annotations) || opts.Rollback && ResourcePolicyIsOrphanOnRollback(annotations) {
 			policy := annotations[ResourcePolicyAnno]
 			c.Log("Skipping delete of %q due to annotation [%s=%s]", info.Name, ResourcePolicyAnno, policy)
 			continue
@@ -446,12 +522,17 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 		}
 	}
 	if opts.ShouldWait {
-		err := c.waitForResources(time.Duration(opts.Timeout)*time.Second, target)
+		err := c.waitForResourcesWithOptions(target, WaitOptions{
+			Timeout:      time.Duration(opts.Timeout) * time.Second,
+			KindTimeouts: opts.WaitTimeouts,
+			OnEvent:      opts.OnReadiness,
+			Jobs:         opts.WaitForJobs,
+			Conditions:   opts.WaitConditions,
+		})
 
 		if opts.CleanupOnFail && err != nil {
 			c.Log("Cleanup on fail enabled: cleaning up newly created resources due to wait failure during update")
-			cleanupErrors = c.cleanup(newlyCreatedResources)
-			return fmt.Errorf(strings.Join(append([]string{err.Error()}, cleanupErrors...), " && "))
+			return withCleanupErrors(err, c.cleanup(newlyCreatedResources))
 		}
 
 		return err
@@ -471,6 +552,16 @@ func (c *Client) cleanup(newlyCreatedResources []*resource.Info) (cleanupErrors
 	return
 }
 
+// withCleanupErrors appends the errors of a cleanup to err, keeping a wait
+// failure recognizable with IsWaitError.
+func withCleanupErrors(err error, cleanupErrors []string) error {
+	joined := fmt.Errorf(strings.Join(append([]string{err.Error()}, cleanupErrors...), " && "))
+	if IsWaitError(err) {
+		return &WaitError{Err: joined}
+	}
+	return joined
+}
+
 // Delete deletes Kubernetes resources from an io.reader.
 //
 // Namespace will set the namespace.
@@ -484,22 +575,34 @@ func (c *Client) Delete(namespace string, reader io.Reader) error {
 //
 // Namespace will set the namespace.
 func (c *Client) DeleteWithTimeout(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+	return c.DeleteWithOptions(namespace, reader, DeleteOptions{Timeout: timeout, ShouldWait: shouldWait})
+}
+
+// DeleteWithOptions deletes Kubernetes resources from an io.reader. DeleteOptions
+// provides additional parameters to control the deletion.
+//
+// Namespace will set the namespace.
+func (c *Client) DeleteWithOptions(namespace string, reader io.Reader, opts DeleteOptions) error {
+	policy, err := PropagationPolicy(opts.Cascade)
+	if err != nil {
+		return err
+	}
 	infos, err := c.BuildUnstructured(namespace, reader)
 	if err != nil {
 		return err
 	}
 	err = perform(infos, func(info *resource.Info) error {
 		c.Log("Starting delete for %q %s", info.Name, info.Mapping.GroupVersionKind.Kind)
-		err := deleteResource(info)
+		err := deleteResourceWithPolicy(info, policy)
 		return c.skipIfNotFound(err)
 	})
 	if err != nil {
 		return err
 	}
 
-	if shouldWait {
-		c.Log("Waiting for %d seconds for delete to be completed", timeout)
-		return waitUntilAllResourceDeleted(infos, time.Duration(timeout)*time.Second)
+	if opts.ShouldWait {
+		c.Log("Waiting for %d seconds for delete to be completed", opts.Timeout)
+		return c.waitUntilAllResourceDeleted(infos, time.Duration(opts.Timeout)*time.Second)
 	}
 
 	return nil
@@ -513,8 +616,8 @@ func (c *Client) skipIfNotFound(err error) error {
 	return err
 }
 
-func waitUntilAllResourceDeleted(infos Result, timeout time.Duration) error {
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
+func (c *Client) waitUntilAllResourceDeleted(infos Result, timeout time.Duration) error {
+	return c.poll(2*time.Second, timeout, func() (bool, error) {
 		allDeleted := true
 		err := perform(infos, func(info *resource.Info) error {
 			innerErr := info.Get()
@@ -582,7 +685,7 @@ func (c *Client) pollCRDEstablished(t time.Duration) ResourceActorFunc {
 }
 
 func (c *Client) pollCRDUntilEstablished(timeout time.Duration, info *resource.Info) error {
-	return wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+	return c.pollImmediate(time.Second, timeout, func() (bool, error) {
 		err := info.Get()
 		if err != nil {
 			return false, fmt.Errorf("unable to get CRD: %v", err)
@@ -633,7 +736,10 @@ func createResource(info *resource.Info) error {
 }
 
 func deleteResource(info *resource.Info) error {
-	policy := metav1.DeletePropagationBackground
+	return deleteResourceWithPolicy(info, metav1.DeletePropagationBackground)
+}
+
+func deleteResourceWithPolicy(info *resource.Info, policy metav1.DeletionPropagation) error {
 	opts := &metav1.DeleteOptions{PropagationPolicy: &policy}
 	_, err := resource.NewHelper(info.Client, info.Mapping).DeleteWithOptions(info.Namespace, info.Name, opts)
 	return err
@@ -688,18 +794,26 @@ func createPatch(target *resource.Info, current runtime.Object) ([]byte, types.P
 	}
 }
 
-func updateResource(c *Client, target *resource.Info, currentObj runtime.Object, force bool, recreate bool) error {
+func updateResource(c *Client, target *resource.Info, currentObj runtime.Object, force bool, recreate bool, timeout int64) error {
 	patch, patchType, err := createPatch(target, currentObj)
 	if err != nil {
 		return fmt.Errorf("failed to create patch: %s", err)
 	}
-	if patch == nil {
+	if annotations, err := metadataAccessor.Annotations(target.Object); err == nil && ResourcePolicyIsDeleteOnUpgrade(annotations) {
+		if err := c.recreateResource(target, timeout); err != nil {
+			return err
+		}
+	} else if patch == nil {
 		c.Log("Looks like there are no changes for %s %q", target.Mapping.GroupVersionKind.Kind, target.Name)
 		// This needs to happen to make sure that tiller has the latest info from the API
 		// Otherwise there will be no labels and other functions that use labels will panic
 		if err := target.Get(); err != nil {
 			return fmt.Errorf("error trying to refresh resource information: %v", err)
 		}
+	} else if annotations, err := metadataAccessor.Annotations(target.Object); err == nil && UpgradeStrategyIsRecreate(annotations) {
+		if err := c.recreateResource(target, timeout); err != nil {
+			return err
+		}
 	} else {
 		// send patch to server
 		helper := resource.NewHelper(target.Client, target.Mapping)
@@ -823,7 +937,7 @@ func (c *Client) watchUntilReady(timeout time.Duration, info *resource.Info) err
 	// In the future, we might want to add some special logic for types
 	// like Ingress, Volume, etc.
 
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := watchtools.ContextWithOptionalTimeout(c.Context(), timeout)
 	defer cancel()
 	_, err := watchtools.ListWatchUntil(ctx, lw, func(e watch.Event) (bool, error) {
 		switch e.Type {
@@ -912,11 +1026,71 @@ func (c *Client) WaitAndGetCompletedPodPhase(namespace string, reader io.Reader,
 	return status, nil
 }
 
+// GetPodLogs returns the logs of the pod in reader, or of the latest pod of
+// the job in reader, keeping at most limitBytes from the end of the output.
+// A limitBytes of 0 returns all of it.
+func (c *Client) GetPodLogs(namespace string, reader io.Reader, limitBytes int64) (string, error) {
+	infos, err := c.Build(namespace, reader)
+	if err != nil {
+		return "", err
+	}
+	info := infos[0]
+
+	client, err := c.KubernetesClientSet()
+	if err != nil {
+		return "", err
+	}
+	pod := info.Name
+	switch kind := info.Mapping.GroupVersionKind.Kind; kind {
+	case "Pod":
+	case "Job":
+		if pod, err = latestJobPod(client, info.Namespace, info.Name); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%s is not a Pod", info.Name)
+	}
+	logs, err := client.CoreV1().Pods(info.Namespace).GetLogs(pod, &v1.PodLogOptions{}).Do().Raw()
+	if err != nil {
+		return "", err
+	}
+	if limitBytes > 0 && int64(len(logs)) > limitBytes {
+		logs = logs[int64(len(logs))-limitBytes:]
+	}
+	return string(logs), nil
+}
+
+// latestJobPod returns the name of the pod of a job created last.
+func latestJobPod(client kubernetes.Interface, namespace, name string) (string, error) {
+	job, err := client.BatchV1().Jobs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+	if len(pods.Items) == 0 {
+		return "", fmt.Errorf("job %s has no pods", name)
+	}
+	latest := pods.Items[0]
+	for _, p := range pods.Items[1:] {
+		if latest.CreationTimestamp.Before(&p.CreationTimestamp) {
+			latest = p
+		}
+	}
+	return latest.Name, nil
+}
+
 func (c *Client) watchPodUntilComplete(timeout time.Duration, info *resource.Info) error {
 	lw := cachetools.NewListWatchFromClient(info.Client, info.Mapping.Resource.Resource, info.Namespace, fields.Everything())
 
 	c.Log("Watching pod %s for completion with timeout of %v", info.Name, timeout)
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := watchtools.ContextWithOptionalTimeout(c.Context(), timeout)
 	defer cancel()
 	_, err := watchtools.ListWatchUntil(ctx, lw, func(e watch.Event) (bool, error) {
 		return isPodComplete(e)
@@ -26,12 +26,14 @@ import (
 	"testing"
 	"time"
 
+	batch "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/resource"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
@@ -244,6 +246,92 @@ func TestUpdate(t *testing.T) {
 			t.Errorf("should not have deleted squid - it has helm.sh/resource-policy=keep")
 		}
 	}
+
+	// Test orphan-on-rollback is only respected by rollbacks
+	listA.Items[2].ObjectMeta.Annotations = map[string]string{ResourcePolicyAnno: OrphanOnRollbackPolicy}
+	for _, rollback := range []bool{false, true} {
+		actions = nil
+		if err := c.UpdateWithOptions(v1.NamespaceDefault, objBody(&listA), objBody(&listB), UpdateOptions{Rollback: rollback}); err != nil {
+			t.Fatal(err)
+		}
+		deleted := false
+		for _, v := range actions {
+			if v == "/namespaces/default/pods/squid:DELETE" {
+				deleted = true
+			}
+		}
+		if deleted == rollback {
+			t.Errorf("expected squid to be deleted by upgrades and orphaned by rollbacks, got deleted=%t for rollback=%t", deleted, rollback)
+		}
+	}
+}
+
+func TestUpdateRecreateStrategy(t *testing.T) {
+	current := newPodList("starfish")
+	target := newPodList("starfish")
+	target.Items[0].ObjectMeta.Annotations = map[string]string{UpgradeStrategyAnno: UpgradeStrategyRecreate}
+	target.Items[0].Spec.Containers[0].Ports = []v1.ContainerPort{{Name: "https", ContainerPort: 443}}
+
+	var actions []string
+	deleted := false
+
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			actions = append(actions, p+":"+m)
+			t.Logf("got request %s %s", p, m)
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				if deleted {
+					return newResponse(404, notFoundBody())
+				}
+				return newResponse(200, &current.Items[0])
+			case p == "/namespaces/default/pods/starfish" && m == "DELETE":
+				deleted = true
+				return newResponse(200, &current.Items[0])
+			case p == "/namespaces/default/pods" && m == "POST":
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not dump request: %s", err)
+				}
+				req.Body.Close()
+				if !strings.Contains(string(data), `"containerPort":443`) {
+					t.Errorf("expected the target pod to be created, got\n%s", string(data))
+				}
+				return newResponse(201, &target.Items[0])
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := &Client{
+		Factory: tf,
+		Log:     nopLogger,
+	}
+
+	if err := c.Update(v1.NamespaceDefault, objBody(&current), objBody(&target), false, false, 30, false); err != nil {
+		t.Fatal(err)
+	}
+	expectedActions := []string{
+		"/namespaces/default/pods/starfish:GET",
+		"/namespaces/default/pods/starfish:DELETE",
+		"/namespaces/default/pods/starfish:GET",
+		"/namespaces/default/pods:POST",
+	}
+	if len(expectedActions) != len(actions) {
+		t.Fatalf("unexpected requests, expected %v, got %v", expectedActions, actions)
+	}
+	for k, v := range expectedActions {
+		if actions[k] != v {
+			t.Errorf("expected %s request got %s", v, actions[k])
+		}
+	}
 }
 
 func TestUpdateNonManagedResourceError(t *testing.T) {
@@ -331,6 +419,46 @@ func TestDeleteWithTimeout(t *testing.T) {
 	}
 }
 
+func TestDeleteWithOptionsCascade(t *testing.T) {
+	for cascade, expected := range map[string]string{
+		"":                `"propagationPolicy":"Background"`,
+		CascadeForeground: `"propagationPolicy":"Foreground"`,
+		CascadeOrphan:     `"propagationPolicy":"Orphan"`,
+	} {
+		c := newTestClient()
+		service := newService("my-service")
+		var body string
+		c.TestFactory.UnstructuredClient = &fake.RESTClient{
+			GroupVersion:         schema.GroupVersion{Version: "v1"},
+			NegotiatedSerializer: unstructuredSerializer,
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				if req.Method == "DELETE" {
+					data, err := ioutil.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("could not read request: %s", err)
+					}
+					body = string(data)
+				}
+				return newResponse(200, &service)
+			}),
+		}
+
+		if err := c.DeleteWithOptions(metav1.NamespaceDefault, strings.NewReader(testServiceManifest), DeleteOptions{Cascade: cascade}); err != nil {
+			t.Errorf("cascade %q: %s", cascade, err)
+		}
+		if !strings.Contains(body, expected) {
+			t.Errorf("cascade %q: expected %s in the delete options, got %s", cascade, expected, body)
+		}
+		c.Cleanup()
+	}
+
+	c := newTestClient()
+	defer c.Cleanup()
+	if err := c.DeleteWithOptions(metav1.NamespaceDefault, strings.NewReader(testServiceManifest), DeleteOptions{Cascade: "sideways"}); err == nil {
+		t.Error("expected an invalid cascade to fail")
+	}
+}
+
 func TestBuild(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -891,3 +1019,40 @@ status:
   conditions: []
   storedVersions: []
 `
+
+func TestLatestJobPod(t *testing.T) {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+		Spec: batch.JobSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "migrate"}},
+		},
+	}
+	pod := func(name string, created time.Time, labels map[string]string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+	}
+	now := time.Now()
+	jobLabels := map[string]string{"job-name": "migrate"}
+	client := kubefake.NewSimpleClientset(
+		job,
+		pod("migrate-first", now.Add(-time.Minute), jobLabels),
+		pod("migrate-retry", now, jobLabels),
+		pod("unrelated", now.Add(time.Minute), map[string]string{"job-name": "other"}),
+	)
+
+	name, err := latestJobPod(client, "default", "migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "migrate-retry" {
+		t.Errorf("expected the latest pod of the job, got %s", name)
+	}
+
+	if _, err := latestJobPod(client, "default", "missing"); err == nil {
+		t.Error("expected an error for a missing job")
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// WithContext returns a copy of the client whose waits and watches stop when
+// ctx is done, so that an abandoned operation does not keep waiting on the
+// cluster.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// Context returns the context of the client, which is the background context
+// unless one was given with WithContext.
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// poll is wait.Poll, stopped when the context of the client is done. The
+// error of the context is returned then, rather than wait.ErrWaitTimeout.
+func (c *Client) poll(interval, timeout time.Duration, condition wait.ConditionFunc) error {
+	return c.pollWith(wait.PollUntil, interval, timeout, condition)
+}
+
+// pollImmediate is wait.PollImmediate, stopped when the context of the client
+// is done.
+func (c *Client) pollImmediate(interval, timeout time.Duration, condition wait.ConditionFunc) error {
+	return c.pollWith(wait.PollImmediateUntil, interval, timeout, condition)
+}
+
+func (c *Client) pollWith(until func(time.Duration, wait.ConditionFunc, <-chan struct{}) error, interval, timeout time.Duration, condition wait.ConditionFunc) error {
+	ctx, cancel := context.WithTimeout(c.Context(), timeout)
+	defer cancel()
+	err := until(interval, condition, ctx.Done())
+	if err == wait.ErrWaitTimeout && c.Context().Err() != nil {
+		return c.Context().Err()
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Health reports the current state of every resource in reader, in the order
+// of reader. Kinds with a notion of readiness are checked as they are when
+// waiting; other kinds are reported ready as long as they exist. A resource
+// that is not found is reported as not ready.
+func (c *Client) Health(namespace string, reader io.Reader) ([]ReadinessEvent, error) {
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return nil, err
+	}
+	kcs, err := c.KubernetesClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ReadinessEvent, 0, len(infos))
+	for _, info := range infos {
+		kind := info.Mapping.GroupVersionKind.Kind
+		c.Log("Checking health of %s: %q", kind, info.Name)
+		r, err := resourceHealth(kcs, info)
+		if err != nil {
+			return nil, fmt.Errorf("could not get %s %q: %s", kind, info.Name, err)
+		}
+		events = append(events, ReadinessEvent{
+			Kind:      kind,
+			Namespace: info.Namespace,
+			Name:      info.Name,
+			Ready:     r.ready,
+			Message:   r.message,
+			Time:      time.Now(),
+		})
+	}
+	return events, nil
+}
+
+func resourceHealth(kcs kubernetes.Interface, info *resource.Info) (*readiness, error) {
+	if _, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false); err != nil {
+		if errors.IsNotFound(err) {
+			return &readiness{message: "not found"}, nil
+		}
+		return nil, err
+	}
+	r, err := resourceReadiness(kcs, info)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return &readiness{ready: true, message: "exists"}, nil
+	}
+	return r, nil
+}
@@ -0,0 +1,37 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ServiceAccountUser returns the user name Kubernetes authenticates a service
+// account as.
+func ServiceAccountUser(namespace, name string) string {
+	return "system:serviceaccount:" + namespace + ":" + name
+}
+
+// NewImpersonating creates a new Client that acts as the given user, member of
+// the given groups. The identity of the process must be allowed to
+// impersonate them.
+func NewImpersonating(user string, groups ...string) *Client {
+	flags := genericclioptions.NewConfigFlags(true)
+	flags.Impersonate = &user
+	flags.ImpersonateGroup = &groups
+	return New(flags)
+}
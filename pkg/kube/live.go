@@ -0,0 +1,99 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// lastAppliedAnno is the annotation kubectl apply records the applied
+// configuration in.
+const lastAppliedAnno = "kubectl.kubernetes.io/last-applied-configuration"
+
+// serverMetadataFields are the metadata fields populated by the cluster.
+var serverMetadataFields = []string{
+	"creationTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// GetLive returns the resources in reader as they are currently found in the
+// cluster, as a YAML stream in the order of reader. Status and the metadata
+// populated by the cluster are removed, so the output can be compared with
+// the manifest the resources were created from. A resource that is not found
+// is replaced by a comment saying so.
+func (c *Client) GetLive(namespace string, reader io.Reader) (string, error) {
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return "", err
+	}
+
+	docs := make([]string, 0, len(infos))
+	for _, info := range infos {
+		kind := info.Mapping.GroupVersionKind.Kind
+		c.Log("Doing live get for %s: %q", kind, info.Name)
+		obj, err := liveObject(info)
+		if errors.IsNotFound(err) {
+			docs = append(docs, fmt.Sprintf("# %s %q was not found in namespace %q", kind, info.Name, info.Namespace))
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not get %s %q: %s", kind, info.Name, err)
+		}
+		cleanLiveObject(obj)
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, strings.TrimSpace(string(b)))
+	}
+	return strings.Join(docs, "\n---\n"), nil
+}
+
+func liveObject(info *resource.Info) (map[string]interface{}, error) {
+	obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// cleanLiveObject removes the fields of obj populated by the cluster.
+func cleanLiveObject(obj map[string]interface{}) {
+	delete(obj, "status")
+	for _, f := range serverMetadataFields {
+		unstructured.RemoveNestedField(obj, "metadata", f)
+	}
+	unstructured.RemoveNestedField(obj, "metadata", "annotations", lastAppliedAnno)
+	if annotations, ok, _ := unstructured.NestedMap(obj, "metadata", "annotations"); ok && len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	}
+}
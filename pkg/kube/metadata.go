@@ -0,0 +1,83 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// MetadataChange describes labels and annotations to set on, or remove
+// from, resources.
+type MetadataChange struct {
+	Labels            map[string]string
+	Annotations       map[string]string
+	RemoveLabels      []string
+	RemoveAnnotations []string
+}
+
+// UpdateMetadata applies a metadata change to the resources in reader.
+//
+// Resources that no longer exist are skipped.
+func (c *Client) UpdateMetadata(namespace string, reader io.Reader, change MetadataChange) error {
+	patch, err := change.mergePatch()
+	if err != nil {
+		return err
+	}
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return err
+	}
+	return perform(infos, func(info *resource.Info) error {
+		c.Log("Updating metadata of %s %q", info.Mapping.GroupVersionKind.Kind, info.Name)
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		_, err := helper.Patch(info.Namespace, info.Name, types.MergePatchType, patch, nil)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to update metadata of %s %q: %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+		return nil
+	})
+}
+
+// mergePatch returns the JSON merge patch of the change. Removed keys are
+// set to null.
+func (m MetadataChange) mergePatch() ([]byte, error) {
+	meta := map[string]interface{}{}
+	if l := patchMap(m.Labels, m.RemoveLabels); len(l) > 0 {
+		meta["labels"] = l
+	}
+	if a := patchMap(m.Annotations, m.RemoveAnnotations); len(a) > 0 {
+		meta["annotations"] = a
+	}
+	return json.Marshal(map[string]interface{}{"metadata": meta})
+}
+
+func patchMap(set map[string]string, remove []string) map[string]interface{} {
+	m := make(map[string]interface{}, len(set)+len(remove))
+	for _, k := range remove {
+		m[k] = nil
+	}
+	for k, v := range set {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,55 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import "testing"
+
+func TestMetadataChangeMergePatch(t *testing.T) {
+	tests := []struct {
+		change MetadataChange
+		expect string
+	}{
+		{
+			change: MetadataChange{},
+			expect: `{"metadata":{}}`,
+		},
+		{
+			change: MetadataChange{
+				Labels:       map[string]string{"frozen": "true"},
+				RemoveLabels: []string{"owner"},
+			},
+			expect: `{"metadata":{"labels":{"frozen":"true","owner":null}}}`,
+		},
+		{
+			change: MetadataChange{
+				Annotations:       map[string]string{"incident": "INC-1234"},
+				RemoveAnnotations: []string{"incident"},
+			},
+			expect: `{"metadata":{"annotations":{"incident":"INC-1234"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		patch, err := tt.change.mergePatch()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(patch) != tt.expect {
+			t.Errorf("expected %s, got %s", tt.expect, patch)
+		}
+	}
+}
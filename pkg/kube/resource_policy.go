@@ -16,28 +16,129 @@ limitations under the License.
 
 package kube
 
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 const (
-	// ResourcePolicyAnno is the annotation name for a resource policy
+	// ResourcePolicyAnno is the annotation name for a resource policy. Its
+	// value is a comma separated list of policies.
 	ResourcePolicyAnno = "helm.sh/resource-policy"
 
 	// deletePolicy is the resource policy type for delete
 	//
 	// This resource policy type allows explicitly opting in to the default
-	//   resource deletion behavior, for example when overriding a chart's
-	//   default annotations. Any other value allows resources to skip being
-	//   deleted during an uninstallRelease action.
+	// resource deletion behavior, for example when overriding a chart's
+	// default annotations. Any value that is not a known policy allows
+	// resources to skip being deleted during an uninstallRelease action.
 	deletePolicy = "delete"
+
+	// KeepPolicy keeps the resource when the release is deleted, or when an
+	// upgrade or a rollback removes it from the release.
+	KeepPolicy = "keep"
+
+	// DeleteOnUpgradePolicy deletes the resource and creates it again on
+	// every upgrade and rollback, even when it did not change.
+	DeleteOnUpgradePolicy = "delete-on-upgrade"
+
+	// OrphanOnRollbackPolicy leaves the resource in the cluster, no longer
+	// managed by the release, when a rollback removes it from the release.
+	OrphanOnRollbackPolicy = "orphan-on-rollback"
 )
 
+// resourcePolicies returns the policies listed in the resource policy
+// annotation, and whether the annotation is set.
+func resourcePolicies(annotations map[string]string) ([]string, bool) {
+	value, ok := annotations[ResourcePolicyAnno]
+	if !ok {
+		return nil, false
+	}
+	policies := strings.Split(value, ",")
+	for i, p := range policies {
+		policies[i] = strings.TrimSpace(p)
+	}
+	return policies, true
+}
+
+func hasResourcePolicy(annotations map[string]string, policy string) bool {
+	policies, _ := resourcePolicies(annotations)
+	for _, p := range policies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
 // ResourcePolicyIsKeep accepts a map of Kubernetes resource annotations and
-//   returns true if the resource should be kept, otherwise false if it is safe
-//   for Helm to delete.
+// returns true if the resource should be kept, otherwise false if it is safe
+// for Helm to delete.
 func ResourcePolicyIsKeep(annotations map[string]string) bool {
-	if annotations != nil {
-		resourcePolicyType, ok := annotations[ResourcePolicyAnno]
-		if ok && resourcePolicyType != deletePolicy {
+	policies, ok := resourcePolicies(annotations)
+	if !ok {
+		return false
+	}
+	for _, p := range policies {
+		switch p {
+		case deletePolicy, DeleteOnUpgradePolicy, OrphanOnRollbackPolicy:
+		default:
 			return true
 		}
 	}
 	return false
 }
+
+// ResourcePolicyIsDeleteOnUpgrade accepts a map of Kubernetes resource
+// annotations and returns true if the resource must be recreated on every
+// upgrade.
+func ResourcePolicyIsDeleteOnUpgrade(annotations map[string]string) bool {
+	return hasResourcePolicy(annotations, DeleteOnUpgradePolicy)
+}
+
+// ResourcePolicyIsOrphanOnRollback accepts a map of Kubernetes resource
+// annotations and returns true if the resource must be left in the cluster
+// when a rollback removes it.
+func ResourcePolicyIsOrphanOnRollback(annotations map[string]string) bool {
+	return hasResourcePolicy(annotations, OrphanOnRollbackPolicy)
+}
+
+// Cascading deletion modes of the resources of a deleted release.
+const (
+	// CascadeBackground deletes the resources right away, and lets Kubernetes
+	// delete their dependents in the background. It is the default.
+	CascadeBackground = "background"
+
+	// CascadeForeground deletes the dependents of the resources before the
+	// resources themselves.
+	CascadeForeground = "foreground"
+
+	// CascadeOrphan deletes the resources but leaves their dependents, such as
+	// the pods of a deployment, in the cluster.
+	CascadeOrphan = "orphan"
+)
+
+// DeleteOptions control how resources are deleted.
+type DeleteOptions struct {
+	Timeout    int64
+	ShouldWait bool
+	// Cascade is one of CascadeBackground, CascadeForeground or CascadeOrphan.
+	// An empty value is CascadeBackground.
+	Cascade string
+}
+
+// PropagationPolicy returns the Kubernetes propagation policy of a cascading
+// deletion mode.
+func PropagationPolicy(cascade string) (metav1.DeletionPropagation, error) {
+	switch cascade {
+	case "", CascadeBackground:
+		return metav1.DeletePropagationBackground, nil
+	case CascadeForeground:
+		return metav1.DeletePropagationForeground, nil
+	case CascadeOrphan:
+		return metav1.DeletePropagationOrphan, nil
+	}
+	return "", fmt.Errorf("invalid cascade %q, must be one of %s, %s or %s", cascade, CascadeBackground, CascadeForeground, CascadeOrphan)
+}
@@ -62,6 +62,18 @@ func TestResourcePolicyIsKeep(t *testing.T) {
 			},
 			true,
 		},
+		{
+			annotations{
+				ResourcePolicyAnno: "delete-on-upgrade, orphan-on-rollback",
+			},
+			false,
+		},
+		{
+			annotations{
+				ResourcePolicyAnno: "orphan-on-rollback,keep",
+			},
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -70,3 +82,16 @@ func TestResourcePolicyIsKeep(t *testing.T) {
 		}
 	}
 }
+
+func TestResourcePolicyLists(t *testing.T) {
+	annotations := map[string]string{ResourcePolicyAnno: "keep, delete-on-upgrade"}
+	if !ResourcePolicyIsDeleteOnUpgrade(annotations) {
+		t.Errorf("Expected %v to be recreated on upgrades", annotations)
+	}
+	if ResourcePolicyIsOrphanOnRollback(annotations) {
+		t.Errorf("Expected %v not to be orphaned on rollbacks", annotations)
+	}
+	if ResourcePolicyIsDeleteOnUpgrade(nil) || ResourcePolicyIsOrphanOnRollback(nil) {
+		t.Error("Expected no policy without annotations")
+	}
+}
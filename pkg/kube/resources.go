@@ -0,0 +1,291 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The ways a resource belongs to a release, as reported in ReleaseResource.
+const (
+	// SourceManifest is a resource of the manifest of the release.
+	SourceManifest = "manifest"
+	// SourceHook is a resource created by a hook of the release.
+	SourceHook = "hook"
+	// SourceOwned is a resource owned by another resource of the release.
+	SourceOwned = "owned"
+	// SourceLabel is a resource matching the label selector of the release.
+	SourceLabel = "label"
+)
+
+// ReleaseResource is a live resource of a release.
+type ReleaseResource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Source tells how the resource belongs to the release.
+	Source string `json:"source"`
+	// Owner is the resource of the release owning this one, as Kind/Name.
+	Owner   string    `json:"owner,omitempty"`
+	Created time.Time `json:"created,omitempty"`
+	Ready   bool      `json:"ready"`
+	Message string    `json:"message"`
+}
+
+// ResourcesOptions controls the resources returned by ReleaseResources.
+type ResourcesOptions struct {
+	// Hooks holds the manifests of the hooks of the release. The hooks that
+	// no longer exist, as most are once they succeeded, are not returned.
+	Hooks io.Reader
+	// Owned returns the resources owned, directly or not, by the resources
+	// of the release, following their owner references. This finds the pods
+	// of a deployment, or the resources an operator creates for a custom
+	// resource.
+	Owned bool
+	// Selector returns the resources matching this label selector in the
+	// namespaces of the release, and the resources they own if Owned is set.
+	Selector string
+}
+
+// ReleaseResources returns the live resources of a release, in the order of
+// manifest followed by the ones of the hooks. A resource of the manifest that
+// is not found is returned as not ready.
+//
+// Finding the owned and labelled resources lists every kind of namespaced
+// resource in the namespaces of the release, the resources they find are
+// returned last, sorted by kind, namespace and name.
+func (c *Client) ReleaseResources(namespace string, manifest io.Reader, opts ResourcesOptions) ([]ReleaseResource, error) {
+	kcs, err := c.KubernetesClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []ReleaseResource
+	// owners maps the UIDs of the resources found so far to their Kind/Name.
+	owners := map[types.UID]string{}
+	namespaces := map[string]bool{namespace: true}
+	add := func(reader io.Reader, source string) error {
+		infos, err := c.BuildUnstructured(namespace, reader)
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			kind := info.Mapping.GroupVersionKind.Kind
+			c.Log("Getting %s %q", kind, info.Name)
+			obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+			if errors.IsNotFound(err) {
+				if source == SourceManifest {
+					resources = append(resources, ReleaseResource{Kind: kind, Namespace: info.Namespace, Name: info.Name, Source: source, Message: "not found"})
+				}
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not get %s %q: %s", kind, info.Name, err)
+			}
+			info.Object = obj
+			r, err := releaseResource(kcs, info, source)
+			if err != nil {
+				return err
+			}
+			if accessor, err := meta.Accessor(obj); err == nil {
+				owners[accessor.GetUID()] = kind + "/" + info.Name
+			}
+			if info.Namespace != "" {
+				namespaces[info.Namespace] = true
+			}
+			resources = append(resources, r)
+		}
+		return nil
+	}
+	if err := add(manifest, SourceManifest); err != nil {
+		return nil, err
+	}
+	if opts.Hooks != nil {
+		if err := add(opts.Hooks, SourceHook); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.Owned && opts.Selector == "" {
+		return resources, nil
+	}
+
+	var selector labels.Selector
+	if opts.Selector != "" {
+		if selector, err = labels.Parse(opts.Selector); err != nil {
+			return nil, err
+		}
+	}
+	candidates, err := c.namespacedResources(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	// Owners may be found after the resources they own, so the candidates are
+	// checked again until no resource is added.
+	var related []ReleaseResource
+	for added := true; added; {
+		added = false
+		for _, info := range candidates {
+			u := info.Object.(*unstructured.Unstructured)
+			if _, ok := owners[u.GetUID()]; ok {
+				continue
+			}
+			source, owner := "", ""
+			if opts.Owned {
+				for _, ref := range u.GetOwnerReferences() {
+					if o, ok := owners[ref.UID]; ok {
+						source, owner = SourceOwned, o
+						break
+					}
+				}
+			}
+			if source == "" && selector != nil && selector.Matches(labels.Set(u.GetLabels())) {
+				source = SourceLabel
+			}
+			if source == "" {
+				continue
+			}
+			owners[u.GetUID()] = info.Mapping.GroupVersionKind.Kind + "/" + info.Name
+			added = true
+
+			r, err := releaseResource(kcs, info, source)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			r.Owner = owner
+			related = append(related, r)
+		}
+	}
+	sort.Slice(related, func(i, j int) bool {
+		a, b := related[i], related[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return append(resources, related...), nil
+}
+
+// namespacedResources lists the resources of every namespaced kind that can
+// be listed in the given namespaces. The groups that fail to be discovered
+// are skipped.
+func (c *Client) namespacedResources(namespaces map[string]bool) ([]*resource.Info, error) {
+	dc, err := c.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	lists, err := dc.ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+	mapper, err := c.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := c.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []*resource.Info
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if !hasVerb(r.Verbs, "list") {
+				continue
+			}
+			mapping, err := mapper.RESTMapping(gv.WithKind(r.Kind).GroupKind(), gv.Version)
+			if err != nil {
+				continue
+			}
+			for ns := range namespaces {
+				c.Log("Listing %s in namespace %q", r.Name, ns)
+				objs, err := dynamicClient.Resource(mapping.Resource).Namespace(ns).List(metav1.ListOptions{})
+				if errors.IsForbidden(err) || errors.IsNotFound(err) || errors.IsMethodNotSupported(err) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("could not list %s: %s", r.Name, err)
+				}
+				for i := range objs.Items {
+					u := &objs.Items[i]
+					infos = append(infos, &resource.Info{
+						Mapping:   mapping,
+						Namespace: u.GetNamespace(),
+						Name:      u.GetName(),
+						Object:    u,
+					})
+				}
+			}
+		}
+	}
+	return infos, nil
+}
+
+// releaseResource reports the state of a live resource of a release.
+func releaseResource(kcs kubernetes.Interface, info *resource.Info, source string) (ReleaseResource, error) {
+	r := ReleaseResource{
+		Kind:      info.Mapping.GroupVersionKind.Kind,
+		Namespace: info.Namespace,
+		Name:      info.Name,
+		Source:    source,
+	}
+	if accessor, err := meta.Accessor(info.Object); err == nil {
+		r.Created = accessor.GetCreationTimestamp().Time
+	}
+	rd, err := resourceReadiness(kcs, info)
+	if err != nil {
+		return r, err
+	}
+	if rd == nil {
+		rd = &readiness{ready: true, message: "exists"}
+	}
+	r.Ready, r.Message = rd.ready, rd.message
+	return r, nil
+}
+
+func hasVerb(verbs metav1.Verbs, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	// UpgradeStrategyAnno is the annotation name for the upgrade strategy of a resource
+	UpgradeStrategyAnno = "helm.sh/upgrade-strategy"
+
+	// UpgradeStrategyRolling patches the resource in place. It is the default.
+	UpgradeStrategyRolling = "rolling"
+
+	// UpgradeStrategyRecreate deletes the resource, and waits for it and its
+	// dependents to be gone, before creating its replacement. It is meant for
+	// resources that cannot be patched, such as Jobs whose pod template
+	// changed, or that must not exist next to their replacement.
+	UpgradeStrategyRecreate = "recreate"
+)
+
+// UpgradeStrategyIsRecreate accepts a map of Kubernetes resource annotations
+// and returns true if the resource must be deleted before its replacement is
+// created during an upgrade.
+func UpgradeStrategyIsRecreate(annotations map[string]string) bool {
+	if annotations == nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(annotations[UpgradeStrategyAnno]), UpgradeStrategyRecreate)
+}
+
+// recreateResource deletes the resource target replaces and creates target once
+// the resource and its dependents are gone.
+func (c *Client) recreateResource(target *resource.Info, timeout int64) error {
+	kind := target.Mapping.GroupVersionKind.Kind
+
+	policy := metav1.DeletePropagationForeground
+	opts := &metav1.DeleteOptions{PropagationPolicy: &policy}
+	_, err := resource.NewHelper(target.Client, target.Mapping).DeleteWithOptions(target.Namespace, target.Name, opts)
+	if err := c.skipIfNotFound(err); err != nil {
+		return fmt.Errorf("failed to delete %s %q for recreation: %s", kind, target.Name, err)
+	}
+	c.Log("Deleted %s %q, waiting for it to be removed before recreating it", kind, target.Name)
+
+	// polling refreshes the object of the info it is given, so poll a copy
+	// to keep the target intact
+	existing := *target
+	if err := c.waitUntilAllResourceDeleted(Result{&existing}, time.Duration(timeout)*time.Second); err != nil {
+		return fmt.Errorf("timed out waiting for %s %q to be deleted: %s", kind, target.Name, err)
+	}
+
+	if err := createResource(target); err != nil {
+		return fmt.Errorf("failed to recreate %s %q: %s", kind, target.Name, err)
+	}
+	c.Log("Recreated %s %q", kind, target.Name)
+	return nil
+}
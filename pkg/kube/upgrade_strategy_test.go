@@ -0,0 +1,39 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import "testing"
+
+func TestUpgradeStrategyIsRecreate(t *testing.T) {
+	cases := []struct {
+		annotations map[string]string
+		recreate    bool
+	}{
+		{nil, false},
+		{map[string]string{"foo": "bar"}, false},
+		{map[string]string{UpgradeStrategyAnno: "rolling"}, false},
+		{map[string]string{UpgradeStrategyAnno: ""}, false},
+		{map[string]string{UpgradeStrategyAnno: "recreate"}, true},
+		{map[string]string{UpgradeStrategyAnno: " Recreate "}, true},
+	}
+
+	for _, tc := range cases {
+		if tc.recreate != UpgradeStrategyIsRecreate(tc.annotations) {
+			t.Errorf("Expected function to return %t for annotations %v", tc.recreate, tc.annotations)
+		}
+	}
+}
@@ -17,237 +17,380 @@ limitations under the License.
 package kube // import "k8s.io/helm/pkg/kube"
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	batch "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes"
 	deploymentutil "k8s.io/kubernetes/pkg/controller/deployment/util"
 )
 
-// deployment holds associated replicaSets for a deployment
-type deployment struct {
-	replicaSets *appsv1.ReplicaSet
-	deployment  *appsv1.Deployment
+// ReadinessEvent reports a change in the readiness of a single resource while
+// waiting for a release.
+type ReadinessEvent struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Ready     bool      `json:"ready"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
 }
 
-// waitForResources polls to get the current status of all pods, PVCs, and Services
-// until all are ready or a timeout is reached
-func (c *Client) waitForResources(timeout time.Duration, created Result) error {
-	c.Log("beginning wait for %d resources with timeout of %v", len(created), timeout)
+// WaitOptions controls how resources are waited on.
+type WaitOptions struct {
+	// Timeout is the time to wait for resources without a kind specific timeout.
+	Timeout time.Duration
+	// KindTimeouts overrides Timeout for resources of the given kind.
+	KindTimeouts map[string]time.Duration
+	// OnEvent, if set, is called whenever the readiness of a resource changes.
+	OnEvent func(ReadinessEvent)
+	// Jobs makes Jobs block the wait until they complete. A failed Job fails
+	// the wait.
+	Jobs bool
+	// Conditions gives, by kind, the condition that resources of the kind
+	// must meet to be ready, as PATH==VALUE. See ParseWaitCondition.
+	Conditions map[string]string
+}
+
+func (o WaitOptions) timeoutFor(kind string) time.Duration {
+	if t, ok := o.KindTimeouts[kind]; ok {
+		return t
+	}
+	return o.Timeout
+}
+
+// maxTimeout returns the longest time any resource may be waited on.
+func (o WaitOptions) maxTimeout() time.Duration {
+	max := o.Timeout
+	for _, t := range o.KindTimeouts {
+		if t > max {
+			max = t
+		}
+	}
+	return max
+}
+
+// WaitError is returned when the resources of a release did not become ready
+// while waiting for them.
+type WaitError struct {
+	Err error
+}
+
+func (e *WaitError) Error() string {
+	return e.Err.Error()
+}
+
+// IsWaitError returns true if err was returned while waiting for resources.
+func IsWaitError(err error) bool {
+	_, ok := err.(*WaitError)
+	return ok
+}
+
+// readiness is the observed state of a single resource.
+type readiness struct {
+	ready   bool
+	message string
+	// informational resources are reported but never block the wait.
+	informational bool
+	// failed resources will never become ready.
+	failed bool
+}
+
+// waitForResourcesWithOptions polls the status of each resource until all of
+// them are ready, reporting every change through opts.OnEvent. A resource that
+// is not ready once the timeout for its kind elapses fails the wait.
+func (c *Client) waitForResourcesWithOptions(created Result, opts WaitOptions) error {
+	c.Log("beginning wait for %d resources with timeout of %v", len(created), opts.Timeout)
 
 	kcs, err := c.KubernetesClientSet()
 	if err != nil {
 		return err
 	}
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
-		pods := []v1.Pod{}
-		services := []v1.Service{}
-		pvc := []v1.PersistentVolumeClaim{}
-		deployments := []deployment{}
-		for _, v := range created {
-			switch value := asVersionedOrUnstructured(v).(type) {
-			case *v1.ReplicationController:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *v1.Pod:
-				pod, err := kcs.CoreV1().Pods(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, *pod)
-			case *appsv1.Deployment:
-				currentDeployment, err := kcs.AppsV1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.AppsV1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *appsv1beta1.Deployment:
-				currentDeployment, err := kcs.AppsV1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.AppsV1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *appsv1beta2.Deployment:
-				currentDeployment, err := kcs.AppsV1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.AppsV1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *extensions.Deployment:
-				currentDeployment, err := kcs.AppsV1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.AppsV1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *extensions.DaemonSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1.DaemonSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta2.DaemonSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1.StatefulSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta1.StatefulSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta2.StatefulSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *extensions.ReplicaSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta2.ReplicaSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1.ReplicaSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *v1.PersistentVolumeClaim:
-				claim, err := kcs.CoreV1().PersistentVolumeClaims(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				pvc = append(pvc, *claim)
-			case *v1.Service:
-				svc, err := kcs.CoreV1().Services(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				services = append(services, *svc)
+	conditions, err := parseWaitConditions(opts.Conditions)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	last := make(map[string]readiness)
+	err = c.poll(2*time.Second, opts.maxTimeout(), func() (bool, error) {
+		allReady := true
+		for _, info := range created {
+			r, err := waitReadiness(kcs, info, opts, conditions)
+			if err != nil {
+				return false, err
+			}
+			if r == nil {
+				continue
+			}
+
+			kind := info.Mapping.GroupVersionKind.Kind
+			key := fmt.Sprintf("%s/%s/%s", kind, info.Namespace, info.Name)
+			if prev, ok := last[key]; !ok || prev.ready != r.ready || prev.message != r.message {
+				last[key] = *r
+				c.report(opts, ReadinessEvent{
+					Kind:      kind,
+					Namespace: info.Namespace,
+					Name:      info.Name,
+					Ready:     r.ready,
+					Message:   r.message,
+					Time:      time.Now(),
+				})
+			}
+
+			if r.ready || r.informational {
+				continue
+			}
+			allReady = false
+			if r.failed {
+				return false, fmt.Errorf("%s %s/%s %s", kind, info.Namespace, info.Name, r.message)
+			}
+			if t := opts.timeoutFor(kind); time.Since(start) > t {
+				return false, fmt.Errorf("timed out after %v waiting for %s %s/%s: %s", t, kind, info.Namespace, info.Name, r.message)
 			}
 		}
-		isReady := c.podsReady(pods) && c.servicesReady(services) && c.volumesReady(pvc) && c.deploymentsReady(deployments)
-		return isReady, nil
+		return allReady, nil
 	})
+	if err == wait.ErrWaitTimeout {
+		var pending []string
+		for key, r := range last {
+			if !r.ready && !r.informational {
+				pending = append(pending, fmt.Sprintf("%s (%s)", key, r.message))
+			}
+		}
+		if len(pending) > 0 {
+			sort.Strings(pending)
+			err = fmt.Errorf("%s: not ready: %s", err, strings.Join(pending, ", "))
+		}
+	}
+	if err != nil {
+		return &WaitError{Err: err}
+	}
+	return nil
 }
 
-func (c *Client) podsReady(pods []v1.Pod) bool {
-	for _, pod := range pods {
-		if !isPodReady(&pod) {
-			c.Log("Pod is not ready: %s/%s", pod.GetNamespace(), pod.GetName())
-			return false
-		}
+func (c *Client) report(opts WaitOptions, e ReadinessEvent) {
+	state := "not ready"
+	if e.Ready {
+		state = "ready"
+	}
+	c.Log("%s is %s: %s/%s (%s)", e.Kind, state, e.Namespace, e.Name, e.Message)
+	if opts.OnEvent != nil {
+		opts.OnEvent(e)
+	}
+}
+
+// waitReadiness is resourceReadiness with the options of a wait applied:
+// resources with a wait condition are checked against it, and Jobs block the
+// wait when opts.Jobs is set.
+func waitReadiness(kcs kubernetes.Interface, info *resource.Info, opts WaitOptions, conditions waitConditions) (*readiness, error) {
+	w, err := conditions.conditionFor(info)
+	if err != nil {
+		return nil, err
+	}
+	if w != nil {
+		return conditionReadiness(info, w)
 	}
-	return true
+	r, err := resourceReadiness(kcs, info)
+	if r != nil && opts.Jobs && info.Mapping.GroupVersionKind.Kind == "Job" {
+		r.informational = false
+	}
+	return r, err
 }
 
-func (c *Client) servicesReady(svc []v1.Service) bool {
-	for _, s := range svc {
-		// ExternalName Services are external to cluster so helm shouldn't be checking to see if they're 'ready' (i.e. have an IP Set)
-		if s.Spec.Type == v1.ServiceTypeExternalName {
-			continue
+// resourceReadiness fetches the current state of a resource from the cluster.
+// It returns nil for kinds that have no notion of readiness.
+func resourceReadiness(kcs kubernetes.Interface, info *resource.Info) (*readiness, error) {
+	switch value := asVersionedOrUnstructured(info).(type) {
+	case *v1.ReplicationController:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector)
+	case *v1.Pod:
+		pod, err := kcs.CoreV1().Pods(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if isPodReady(pod) {
+			return &readiness{ready: true, message: string(pod.Status.Phase)}, nil
+		}
+		return &readiness{message: string(pod.Status.Phase)}, nil
+	case *appsv1.Deployment:
+		return deploymentReadiness(kcs, value.Namespace, value.Name)
+	case *appsv1beta1.Deployment:
+		return deploymentReadiness(kcs, value.Namespace, value.Name)
+	case *appsv1beta2.Deployment:
+		return deploymentReadiness(kcs, value.Namespace, value.Name)
+	case *extensions.Deployment:
+		return deploymentReadiness(kcs, value.Namespace, value.Name)
+	case *extensions.DaemonSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1.DaemonSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1beta2.DaemonSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1.StatefulSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1beta1.StatefulSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1beta2.StatefulSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *extensions.ReplicaSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1beta2.ReplicaSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *appsv1.ReplicaSet:
+		return podsReadiness(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+	case *v1.PersistentVolumeClaim:
+		claim, err := kcs.CoreV1().PersistentVolumeClaims(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return volumeReadiness(claim), nil
+	case *v1.Service:
+		svc, err := kcs.CoreV1().Services(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return serviceReadiness(svc), nil
+	case *batch.Job:
+		job, err := kcs.BatchV1().Jobs(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		r := jobReadiness(job)
+		r.informational = true
+		return r, nil
+	case *extensions.Ingress:
+		ing, err := kcs.ExtensionsV1beta1().Ingresses(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return ingressReadiness(ing.Status.LoadBalancer), nil
+	case *networking.Ingress:
+		ing, err := kcs.NetworkingV1beta1().Ingresses(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
 		}
+		return ingressReadiness(ing.Status.LoadBalancer), nil
+	}
+	return nil, nil
+}
 
-		// Make sure the service is not explicitly set to "None" before checking the IP
-		if s.Spec.ClusterIP != v1.ClusterIPNone && s.Spec.ClusterIP == "" {
-			c.Log("Service is not ready: %s/%s", s.GetNamespace(), s.GetName())
-			return false
+func podsReadiness(kcs kubernetes.Interface, namespace string, selector map[string]string) (*readiness, error) {
+	pods, err := getPods(kcs, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	ready := 0
+	for i := range pods {
+		if isPodReady(&pods[i]) {
+			ready++
 		}
-		// This checks if the service has a LoadBalancer and that balancer has an Ingress defined
-		if s.Spec.Type == v1.ServiceTypeLoadBalancer && s.Status.LoadBalancer.Ingress == nil {
-			c.Log("Service is not ready: %s/%s", s.GetNamespace(), s.GetName())
-			return false
+	}
+	return &readiness{
+		ready:   ready == len(pods),
+		message: fmt.Sprintf("%d/%d pods ready", ready, len(pods)),
+	}, nil
+}
+
+func deploymentReadiness(kcs kubernetes.Interface, namespace, name string) (*readiness, error) {
+	current, err := kcs.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// Find RS associated with deployment
+	newReplicaSet, err := deploymentutil.GetNewReplicaSet(current, kcs.AppsV1())
+	if err != nil {
+		return nil, err
+	}
+	if newReplicaSet == nil {
+		return &readiness{message: "waiting for new replica set"}, nil
+	}
+	return replicaSetReadiness(current, newReplicaSet), nil
+}
+
+func replicaSetReadiness(d *appsv1.Deployment, rs *appsv1.ReplicaSet) *readiness {
+	expected := *d.Spec.Replicas - deploymentutil.MaxUnavailable(*d)
+	return &readiness{
+		ready:   rs.Status.ReadyReplicas >= expected,
+		message: fmt.Sprintf("%d/%d replicas ready, %d required", rs.Status.ReadyReplicas, *d.Spec.Replicas, expected),
+	}
+}
+
+func volumeReadiness(claim *v1.PersistentVolumeClaim) *readiness {
+	return &readiness{
+		ready:   claim.Status.Phase == v1.ClaimBound,
+		message: string(claim.Status.Phase),
+	}
+}
+
+func serviceReadiness(s *v1.Service) *readiness {
+	// ExternalName Services are external to cluster so helm shouldn't be checking to see if they're 'ready' (i.e. have an IP Set)
+	if s.Spec.Type == v1.ServiceTypeExternalName {
+		return &readiness{ready: true, message: "external name " + s.Spec.ExternalName}
+	}
+
+	// Make sure the service is not explicitly set to "None" before checking the IP
+	if s.Spec.ClusterIP != v1.ClusterIPNone && s.Spec.ClusterIP == "" {
+		return &readiness{message: "waiting for cluster IP"}
+	}
+	// This checks if the service has a LoadBalancer and that balancer has an Ingress defined
+	if s.Spec.Type == v1.ServiceTypeLoadBalancer {
+		if s.Status.LoadBalancer.Ingress == nil {
+			return &readiness{message: "waiting for load balancer"}
 		}
+		return &readiness{ready: true, message: "load balancer " + loadBalancerAddresses(s.Status.LoadBalancer)}
 	}
-	return true
+	return &readiness{ready: true, message: "cluster IP " + s.Spec.ClusterIP}
 }
 
-func (c *Client) volumesReady(vols []v1.PersistentVolumeClaim) bool {
-	for _, v := range vols {
-		if v.Status.Phase != v1.ClaimBound {
-			c.Log("PersistentVolumeClaim is not ready: %s/%s", v.GetNamespace(), v.GetName())
-			return false
+func jobReadiness(job *batch.Job) *readiness {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Type == batch.JobFailed && c.Status == v1.ConditionTrue {
+			return &readiness{failed: true, message: "failed: " + c.Reason}
 		}
 	}
-	return true
+	return &readiness{
+		ready:   job.Status.Succeeded >= completions,
+		message: fmt.Sprintf("%d/%d completions, %d active", job.Status.Succeeded, completions, job.Status.Active),
+	}
+}
+
+// ingressReadiness reports the load balancer address of an ingress. Many
+// ingress controllers never publish an address, so ingresses never block.
+func ingressReadiness(lb v1.LoadBalancerStatus) *readiness {
+	if len(lb.Ingress) == 0 {
+		return &readiness{informational: true, message: "no load balancer address"}
+	}
+	return &readiness{ready: true, informational: true, message: "load balancer " + loadBalancerAddresses(lb)}
 }
 
-func (c *Client) deploymentsReady(deployments []deployment) bool {
-	for _, v := range deployments {
-		if !(v.replicaSets.Status.ReadyReplicas >= *v.deployment.Spec.Replicas-deploymentutil.MaxUnavailable(*v.deployment)) {
-			c.Log("Deployment is not ready: %s/%s", v.deployment.GetNamespace(), v.deployment.GetName())
-			return false
+func loadBalancerAddresses(lb v1.LoadBalancerStatus) string {
+	addrs := make([]string, 0, len(lb.Ingress))
+	for _, i := range lb.Ingress {
+		if i.IP != "" {
+			addrs = append(addrs, i.IP)
+		} else {
+			addrs = append(addrs, i.Hostname)
 		}
 	}
-	return true
+	return strings.Join(addrs, ",")
 }
 
 func getPods(client kubernetes.Interface, namespace string, selector map[string]string) ([]v1.Pod, error) {
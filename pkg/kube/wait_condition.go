@@ -0,0 +1,141 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// WaitConditionAnno is the annotation giving the condition a resource must
+// meet to be ready while waiting, as PATH==VALUE. It overrides the wait
+// condition given for the kind of the resource.
+const WaitConditionAnno = "helm.sh/wait-condition"
+
+// WaitCondition is a check on a field of a resource, used to wait on kinds
+// that Helm knows nothing about, such as custom resources.
+type WaitCondition struct {
+	path  *jsonpath.JSONPath
+	expr  string
+	value string
+}
+
+// ParseWaitCondition parses a condition given as PATH==VALUE, such as
+// status.conditions[?(@.type=="Ready")].status==True. PATH is a JSONPath
+// expression, whose braces and leading dot may be left out. The condition is
+// met when any of the fields matched by PATH is VALUE.
+func ParseWaitCondition(s string) (*WaitCondition, error) {
+	i := strings.LastIndex(s, "==")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid wait condition %q, expected PATH==VALUE", s)
+	}
+	expr, value := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+2:])
+	if expr == "" || value == "" {
+		return nil, fmt.Errorf("invalid wait condition %q, expected PATH==VALUE", s)
+	}
+	tmpl := expr
+	if !strings.HasPrefix(tmpl, "{") {
+		if !strings.HasPrefix(tmpl, ".") {
+			tmpl = "." + tmpl
+		}
+		tmpl = "{" + tmpl + "}"
+	}
+	path := jsonpath.New("wait-condition")
+	path.AllowMissingKeys(true)
+	if err := path.Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("invalid wait condition %q: %s", s, err)
+	}
+	return &WaitCondition{path: path, expr: expr, value: value}, nil
+}
+
+func (w *WaitCondition) String() string {
+	return w.expr + "==" + w.value
+}
+
+// check reports whether an object, in its unstructured form, meets the
+// condition.
+func (w *WaitCondition) check(obj map[string]interface{}) (*readiness, error) {
+	results, err := w.path.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	for _, result := range results {
+		for _, r := range result {
+			v := fmt.Sprint(r.Interface())
+			if v == w.value {
+				return &readiness{ready: true, message: w.String()}, nil
+			}
+			found = append(found, v)
+		}
+	}
+	if len(found) == 0 {
+		return &readiness{message: fmt.Sprintf("waiting for %s, %s is not set", w, w.expr)}, nil
+	}
+	return &readiness{message: fmt.Sprintf("waiting for %s, %s is %s", w, w.expr, strings.Join(found, ","))}, nil
+}
+
+// waitConditions holds the wait conditions given by kind.
+type waitConditions map[string]*WaitCondition
+
+func parseWaitConditions(conditions map[string]string) (waitConditions, error) {
+	parsed := make(waitConditions, len(conditions))
+	for kind, s := range conditions {
+		w, err := ParseWaitCondition(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", kind, err)
+		}
+		parsed[kind] = w
+	}
+	return parsed, nil
+}
+
+// conditionFor returns the wait condition of a resource, from its annotation
+// or else from its kind, or nil if it has none.
+func (c waitConditions) conditionFor(info *resource.Info) (*WaitCondition, error) {
+	annotations, err := metadataAccessor.Annotations(info.Object)
+	if err == nil {
+		if s, ok := annotations[WaitConditionAnno]; ok {
+			w, err := ParseWaitCondition(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s %q: %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+			}
+			return w, nil
+		}
+	}
+	return c[info.Mapping.GroupVersionKind.Kind], nil
+}
+
+// conditionReadiness fetches a resource from the cluster and checks it
+// against a wait condition.
+func conditionReadiness(info *resource.Info, w *WaitCondition) (*readiness, error) {
+	obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]interface{}
+	if u, ok := obj.(runtime.Unstructured); ok {
+		content = u.UnstructuredContent()
+	} else if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+		return nil, err
+	}
+	return w.check(content)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	for _, s := range []string{
+		`status.conditions[?(@.type=="Ready")].status==True`,
+		`.status.phase == Running`,
+		`{.status.phase}==Running`,
+	} {
+		if _, err := ParseWaitCondition(s); err != nil {
+			t.Errorf("expected %q to parse, got %s", s, err)
+		}
+	}
+	for _, s := range []string{"status.phase", "==Running", "status.phase==", "{.status.phase==Running"} {
+		if _, err := ParseWaitCondition(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestWaitConditionCheck(t *testing.T) {
+	w, err := ParseWaitCondition(`status.conditions[?(@.type=="Ready")].status==True`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		obj    map[string]interface{}
+		expect bool
+	}{
+		{"no status", map[string]interface{}{}, false},
+		{"not ready", conditions("Synced", "True", "Ready", "False"), false},
+		{"ready", conditions("Synced", "False", "Ready", "True"), true},
+	}
+	for _, tt := range tests {
+		r, err := w.check(tt.obj)
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if r.ready != tt.expect {
+			t.Errorf("%s: expected ready to be %t, got %t (%s)", tt.name, tt.expect, r.ready, r.message)
+		}
+	}
+}
+
+// conditions builds an unstructured object with the given condition types
+// and statuses.
+func conditions(typesAndStatuses ...string) map[string]interface{} {
+	var list []interface{}
+	for i := 0; i+1 < len(typesAndStatuses); i += 2 {
+		list = append(list, map[string]interface{}{
+			"type":   typesAndStatuses[i],
+			"status": typesAndStatuses[i+1],
+		})
+	}
+	return map[string]interface{}{
+		"status": map[string]interface{}{"conditions": list},
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// APIVersionLifecycle records when an API version stopped being recommended
+// and when it stopped being served.
+type APIVersionLifecycle struct {
+	// APIVersion is the group and version, such as "extensions/v1beta1".
+	APIVersion string
+	// Kind limits the entry to one kind. If empty, the entry covers every
+	// kind of the API version.
+	Kind string
+	// Deprecated is the Kubernetes version that deprecated the API version.
+	Deprecated string
+	// Removed is the Kubernetes version that stopped serving the API version.
+	Removed string
+	// Replacement is the API version to use instead.
+	Replacement string
+}
+
+// APIVersionLifecycles lists the lifecycle of the API versions deprecated by
+// Kubernetes. Entries for a single kind take precedence over entries for a
+// whole API version.
+var APIVersionLifecycles = []APIVersionLifecycle{
+	{APIVersion: "extensions/v1beta1", Kind: "Deployment", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", Deprecated: "1.9", Removed: "1.16", Replacement: "networking.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", Deprecated: "1.11", Removed: "1.16", Replacement: "policy/v1beta1"},
+	{APIVersion: "extensions/v1beta1", Kind: "Ingress", Deprecated: "1.14", Removed: "1.22", Replacement: "networking.k8s.io/v1beta1"},
+	{APIVersion: "apps/v1beta1", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Deprecated: "1.9", Removed: "1.16", Replacement: "apps/v1"},
+	{APIVersion: "scheduling.k8s.io/v1alpha1", Deprecated: "1.14", Removed: "1.17", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Deprecated: "1.14", Removed: "1.22", Replacement: "scheduling.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1alpha1", Deprecated: "1.17", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Deprecated: "1.17", Removed: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Deprecated: "1.16", Removed: "1.22", Replacement: "apiextensions.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Deprecated: "1.16", Removed: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+}
+
+// LookupLifecycle returns the lifecycle of the given API version and kind, if
+// the API version is deprecated.
+func LookupLifecycle(apiVersion, kind string) (APIVersionLifecycle, bool) {
+	var group *APIVersionLifecycle
+	for i, l := range APIVersionLifecycles {
+		if l.APIVersion != apiVersion {
+			continue
+		}
+		if l.Kind == kind {
+			return l, true
+		}
+		if l.Kind == "" {
+			group = &APIVersionLifecycles[i]
+		}
+	}
+	if group != nil {
+		return *group, true
+	}
+	return APIVersionLifecycle{}, false
+}
+
+// kubeVersion is a Kubernetes major and minor version.
+type kubeVersion struct {
+	major, minor int
+}
+
+// parseKubeVersion parses versions such as "1.14", "v1.14" and "v1.14.3".
+func parseKubeVersion(s string) (kubeVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) < 2 {
+		return kubeVersion{}, fmt.Errorf("invalid Kubernetes version %q, expected MAJOR.MINOR", s)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return kubeVersion{}, fmt.Errorf("invalid Kubernetes version %q, expected MAJOR.MINOR", s)
+	}
+	// minor versions of managed clusters may carry a suffix, as in "14+"
+	minor, err := strconv.Atoi(strings.TrimSuffix(parts[1], "+"))
+	if err != nil {
+		return kubeVersion{}, fmt.Errorf("invalid Kubernetes version %q, expected MAJOR.MINOR", s)
+	}
+	return kubeVersion{major: major, minor: minor}, nil
+}
+
+// atLeast reports whether v is the version s or a later one.
+func (v kubeVersion) atLeast(s string) bool {
+	o, err := parseKubeVersion(s)
+	if err != nil {
+		return false
+	}
+	if v.major != o.major {
+		return v.major > o.major
+	}
+	return v.minor >= o.minor
+}
+
+func (v kubeVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
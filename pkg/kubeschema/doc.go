@@ -0,0 +1,30 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package kubeschema validates Kubernetes manifests offline.
+
+Manifests are checked against the schemas of the Kubernetes API types compiled
+into Helm, which catches unknown fields and values of the wrong type, and
+against a bundled table of the API versions each Kubernetes release serves,
+which catches apiVersions that are deprecated or no longer served by the
+targeted Kubernetes version. Kinds Helm knows nothing about, such as custom
+resources, are not validated.
+
+The same table is used to move release manifests off apiVersions a
+Kubernetes version no longer serves, see MapAPIVersions.
+*/
+package kubeschema // import "k8s.io/helm/pkg/kubeschema"
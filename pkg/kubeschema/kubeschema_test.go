@@ -0,0 +1,251 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+const validDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        ports:
+        - containerPort: 80
+        resources:
+          limits:
+            cpu: 100m
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		kubeVersion string
+		manifest    string
+		expect      []Finding
+	}{
+		{
+			name:        "valid",
+			kubeVersion: "1.14",
+			manifest:    validDeployment,
+		},
+		{
+			name:        "unknown kinds are skipped",
+			kubeVersion: "1.14",
+			manifest:    "apiVersion: example.com/v1\nkind: Widget\nspec:\n  anything: true\n",
+		},
+		{
+			name:        "unknown field and wrong types",
+			kubeVersion: "1.14",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  restartPolicy: Always
+  containres: []
+  containers:
+  - name: web
+    image: nginx
+    ports:
+    - containerPort: "80"
+    resources:
+      limits:
+        cpu: lots
+`,
+			expect: []Finding{
+				{Type: InvalidField, Path: "spec.containers[0].ports[0].containerPort", Message: "expected integer, got string"},
+				{Type: InvalidField, Path: "spec.containers[0].resources.limits.cpu", Message: "invalid value: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'"},
+				{Type: InvalidField, Path: "spec.containres", Message: "unknown field"},
+			},
+		},
+		{
+			name:        "deprecated apiVersion",
+			kubeVersion: "1.14",
+			manifest:    "apiVersion: extensions/v1beta1\nkind: Ingress\nmetadata:\n  name: web\n",
+			expect: []Finding{
+				{Type: DeprecatedAPI, Path: "apiVersion", Message: "extensions/v1beta1 Ingress is deprecated since Kubernetes 1.14 and removed in 1.22, use networking.k8s.io/v1beta1"},
+			},
+		},
+		{
+			name:        "removed apiVersion",
+			kubeVersion: "v1.16.2",
+			manifest:    "apiVersion: extensions/v1beta1\nkind: Deployment\nmetadata:\n  name: web\n",
+			expect: []Finding{
+				{Type: RemovedAPI, Path: "apiVersion", Message: "extensions/v1beta1 Deployment is not served by Kubernetes 1.16, use apps/v1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.kubeVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := v.Validate(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(findings, tt.expect) {
+				t.Errorf("expected findings %v, got %v", tt.expect, findings)
+			}
+		})
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	v, err := NewValidator("1.14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Validate("kind: Pod\n"); err == nil {
+		t.Error("expected an error for a document without apiVersion")
+	}
+	if _, err := v.Validate("a: [\n"); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+	if findings, err := v.Validate("# just a comment\n"); err != nil || findings != nil {
+		t.Errorf("expected no findings for an empty document, got %v, %v", findings, err)
+	}
+}
+
+func TestNewValidator(t *testing.T) {
+	for _, s := range []string{"1.14", "v1.14", "v1.14.3", "1.14+"} {
+		if _, err := NewValidator(s); err != nil {
+			t.Errorf("expected %q to parse, got %s", s, err)
+		}
+	}
+	for _, s := range []string{"", "1", "one.two"} {
+		if _, err := NewValidator(s); err == nil {
+			t.Errorf("expected %q not to parse", s)
+		}
+	}
+}
+
+func TestLookupLifecycle(t *testing.T) {
+	l, ok := LookupLifecycle("extensions/v1beta1", "NetworkPolicy")
+	if !ok || l.Replacement != "networking.k8s.io/v1" {
+		t.Errorf("expected the NetworkPolicy entry, got %v", l)
+	}
+	l, ok = LookupLifecycle("apps/v1beta2", "StatefulSet")
+	if !ok || l.Replacement != "apps/v1" {
+		t.Errorf("expected the apps/v1beta2 entry, got %v", l)
+	}
+	if _, ok := LookupLifecycle("apps/v1", "Deployment"); ok {
+		t.Error("apps/v1 is not deprecated")
+	}
+}
+
+func TestMapAPIVersions(t *testing.T) {
+	manifest := `---
+# Source: web/templates/deployment.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: web/templates/ingress.yaml
+apiVersion: "extensions/v1beta1"
+kind: Ingress
+metadata:
+  name: web
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+	expect := `---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: web/templates/ingress.yaml
+apiVersion: "extensions/v1beta1"
+kind: Ingress
+metadata:
+  name: web
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+	mapped, mappings, err := MapAPIVersions(manifest, "1.16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapped != expect {
+		t.Errorf("expected manifest\n%s\ngot\n%s", expect, mapped)
+	}
+	expectMappings := []Mapping{{Kind: "Deployment", Name: "web", From: "extensions/v1beta1", To: "apps/v1"}}
+	if !reflect.DeepEqual(mappings, expectMappings) {
+		t.Errorf("expected mappings %v, got %v", expectMappings, mappings)
+	}
+
+	if _, mappings, _ := MapAPIVersions(manifest, "1.22"); len(mappings) != 2 {
+		t.Errorf("expected the Deployment and Ingress to be mapped for 1.22, got %v", mappings)
+	}
+	if _, _, err := MapAPIVersions(manifest, "next"); err == nil {
+		t.Error("expected an error for an invalid Kubernetes version")
+	}
+}
+
+func TestValidateReportUpcoming(t *testing.T) {
+	manifest := "apiVersion: rbac.authorization.k8s.io/v1beta1\nkind: Role\nmetadata:\n  name: web\n"
+	v, err := NewValidator("1.14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings, _ := v.Validate(manifest); len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+
+	v.ReportUpcoming = true
+	findings, err := v.Validate(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Type != UpcomingRemoval {
+		t.Fatalf("expected an upcoming removal, got %v", findings)
+	}
+	expect := "apiVersion: rbac.authorization.k8s.io/v1beta1 Role is deprecated in Kubernetes 1.17 and removed in 1.22, use rbac.authorization.k8s.io/v1"
+	if findings[0].Error() != expect {
+		t.Errorf("expected %q, got %q", expect, findings[0].Error())
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Mapping is an apiVersion replaced by MapAPIVersions.
+type Mapping struct {
+	Kind string
+	Name string
+	From string
+	To   string
+}
+
+func (m Mapping) String() string {
+	return fmt.Sprintf("%s %q: %s -> %s", m.Kind, m.Name, m.From, m.To)
+}
+
+var apiVersionLine = regexp.MustCompile(`^apiVersion:\s*["']?([^"'\s#]+)["']?\s*(#.*)?$`)
+
+// MapAPIVersions replaces the apiVersions of the resources in manifest that
+// kubeVersion no longer serves with their replacements. Only the apiVersion
+// lines change, the rest of the manifest is left as it is.
+func MapAPIVersions(manifest, kubeVersion string) (string, []Mapping, error) {
+	v, err := parseKubeVersion(kubeVersion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	lines := strings.Split(manifest, "\n")
+	var mappings []Mapping
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !strings.HasPrefix(lines[i], "---") {
+			continue
+		}
+		if m, ok := mapDocument(lines[start:i], v); ok {
+			mappings = append(mappings, m)
+		}
+		start = i + 1
+	}
+	return strings.Join(lines, "\n"), mappings, nil
+}
+
+// mapDocument replaces the apiVersion line of a single document in place.
+func mapDocument(lines []string, v kubeVersion) (Mapping, bool) {
+	var head struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	// documents that do not parse are left alone
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &head); err != nil {
+		return Mapping{}, false
+	}
+	for i, line := range lines {
+		m := apiVersionLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		l, ok := LookupLifecycle(m[1], head.Kind)
+		if !ok || !v.atLeast(l.Removed) {
+			return Mapping{}, false
+		}
+		lines[i] = "apiVersion: " + l.Replacement
+		return Mapping{Kind: head.Kind, Name: head.Metadata.Name, From: m[1], To: l.Replacement}, true
+	}
+	return Mapping{}, false
+}
@@ -0,0 +1,300 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// FindingType classifies the problems found in a manifest.
+type FindingType int
+
+const (
+	// InvalidField is a field unknown to the schema, or a value of the wrong type.
+	InvalidField FindingType = iota
+	// RemovedAPI is an apiVersion the targeted Kubernetes version does not serve.
+	RemovedAPI
+	// DeprecatedAPI is an apiVersion the targeted Kubernetes version deprecates.
+	DeprecatedAPI
+	// UpcomingRemoval is an apiVersion a later Kubernetes version deprecates.
+	// It is only reported if the validator is asked to.
+	UpcomingRemoval
+)
+
+// Finding is a problem found in a manifest.
+type Finding struct {
+	Type FindingType
+	// Path is the path of the offending field, such as "spec.replicas".
+	Path    string
+	Message string
+}
+
+func (f Finding) Error() string {
+	if f.Path == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%s: %s", f.Path, f.Message)
+}
+
+// Validator validates manifests for a Kubernetes version.
+type Validator struct {
+	// ReportUpcoming also reports apiVersions that Kubernetes versions later
+	// than the targeted one deprecate.
+	ReportUpcoming bool
+	version        kubeVersion
+}
+
+// NewValidator returns a validator for the given Kubernetes version, such as
+// "1.14".
+func NewValidator(kubeVersion string) (*Validator, error) {
+	v, err := parseKubeVersion(kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{version: v}, nil
+}
+
+// Validate validates a single YAML document. Empty documents and documents of
+// unknown kinds have no findings. An error is returned if the document is not
+// a valid Kubernetes object.
+func (v *Validator) Validate(manifest string) ([]Finding, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, nil
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	if apiVersion == "" || kind == "" {
+		return nil, fmt.Errorf("object has no apiVersion or kind")
+	}
+
+	var findings []Finding
+	if l, ok := LookupLifecycle(apiVersion, kind); ok {
+		switch {
+		case v.version.atLeast(l.Removed):
+			return append(findings, Finding{
+				Type:    RemovedAPI,
+				Path:    "apiVersion",
+				Message: fmt.Sprintf("%s %s is not served by Kubernetes %s, use %s", apiVersion, kind, v.version, l.Replacement),
+			}), nil
+		case v.version.atLeast(l.Deprecated):
+			findings = append(findings, Finding{
+				Type:    DeprecatedAPI,
+				Path:    "apiVersion",
+				Message: fmt.Sprintf("%s %s is deprecated since Kubernetes %s and removed in %s, use %s", apiVersion, kind, l.Deprecated, l.Removed, l.Replacement),
+			})
+		case v.ReportUpcoming:
+			findings = append(findings, Finding{
+				Type:    UpcomingRemoval,
+				Path:    "apiVersion",
+				Message: fmt.Sprintf("%s %s is deprecated in Kubernetes %s and removed in %s, use %s", apiVersion, kind, l.Deprecated, l.Removed, l.Replacement),
+			})
+		}
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	typed, err := scheme.Scheme.New(gv.WithKind(kind))
+	if runtime.IsNotRegisteredError(err) {
+		return findings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, validateValue("", obj, reflect.TypeOf(typed))...), nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// validateValue validates the decoded JSON value v against the Go type t the
+// value is unmarshalled into by Kubernetes.
+func validateValue(path string, v interface{}, t reflect.Type) []Finding {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if v == nil {
+		return nil
+	}
+
+	// types decoding themselves, such as quantities and times, are checked
+	// by decoding the value
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		b, err := json.Marshal(v)
+		if err == nil {
+			err = json.Unmarshal(b, reflect.New(t).Interface())
+		}
+		if err != nil {
+			return []Finding{invalid(path, "invalid value: %s", err)}
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return []Finding{wrongType(path, "object", v)}
+		}
+		fields := jsonFields(t)
+		var findings []Finding
+		for _, k := range sortedKeys(m) {
+			f, ok := fields[k]
+			if !ok {
+				findings = append(findings, invalid(join(path, k), "unknown field"))
+				continue
+			}
+			findings = append(findings, validateValue(join(path, k), m[k], f)...)
+		}
+		return findings
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return []Finding{wrongType(path, "object", v)}
+		}
+		var findings []Finding
+		for _, k := range sortedKeys(m) {
+			findings = append(findings, validateValue(join(path, k), m[k], t.Elem())...)
+		}
+		return findings
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := v.(string); !ok {
+				return []Finding{wrongType(path, "base64 encoded string", v)}
+			}
+			return nil
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			return []Finding{wrongType(path, "array", v)}
+		}
+		var findings []Finding
+		for i, item := range items {
+			findings = append(findings, validateValue(fmt.Sprintf("%s[%d]", path, i), item, t.Elem())...)
+		}
+		return findings
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			return []Finding{wrongType(path, "string", v)}
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			return []Finding{wrongType(path, "boolean", v)}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			return []Finding{wrongType(path, "integer", v)}
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := v.(float64); !ok {
+			return []Finding{wrongType(path, "number", v)}
+		}
+	}
+	return nil
+}
+
+// jsonFields returns the types of the fields of a struct by JSON name,
+// including the fields of inlined structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" && (f.Anonymous || strings.Contains(tag, ",inline")) {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func invalid(path, format string, a ...interface{}) Finding {
+	return Finding{Type: InvalidField, Path: path, Message: fmt.Sprintf(format, a...)}
+}
+
+func wrongType(path, expected string, v interface{}) Finding {
+	return invalid(path, "expected %s, got %s", expected, jsonType(v))
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
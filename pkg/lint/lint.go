@@ -23,14 +23,61 @@ import (
 	"k8s.io/helm/pkg/lint/support"
 )
 
+// Options configure the linters run by AllWithOptions.
+type Options struct {
+	// KubeVersion is the Kubernetes version the rendered manifests are
+	// validated against. Manifests are not validated if it is empty.
+	KubeVersion string
+	// Deprecations also reports apiVersions that Kubernetes versions later
+	// than KubeVersion deprecate.
+	Deprecations bool
+	// External are rules run after the built-in ones.
+	External []rules.ExternalRule
+	// WithSubcharts also lints the subcharts of the charts/ directory that
+	// the values enable, each with the values the chart gives it. Their
+	// messages have paths relative to the chart, such as
+	// charts/mysql/values.yaml.
+	WithSubcharts bool
+}
+
 // All runs all of the available linters on the given base directory.
 func All(basedir string, values []byte, namespace string, strict bool) support.Linter {
+	return AllWithOptions(basedir, values, namespace, strict, Options{})
+}
+
+// AllWithOptions runs all of the available linters, followed by the external
+// rules of opts, on the given base directory.
+//
+// The rules are configured by the .helmlint.yaml file of the chart, if it has
+// one.
+func AllWithOptions(basedir string, values []byte, namespace string, strict bool, opts Options) support.Linter {
 	// Using abs path to get directory context
 	chartDir, _ := filepath.Abs(basedir)
 
-	linter := support.Linter{ChartDir: chartDir}
+	linter := support.Linter{
+		ChartDir:     chartDir,
+		KubeVersion:  opts.KubeVersion,
+		Deprecations: opts.Deprecations,
+	}
+	config, err := support.LoadConfig(chartDir)
+	if linter.RunLinterRule(support.ErrorSev, support.ConfigFileName, err) {
+		linter.Config = config
+	}
+
 	rules.Chartfile(&linter)
 	rules.Values(&linter)
 	rules.Templates(&linter, values, namespace, strict)
+	rules.Secrets(&linter)
+	for _, r := range opts.External {
+		rules.External(&linter, r)
+	}
+	if opts.WithSubcharts {
+		subcharts, cleanup := rules.Subcharts(&linter, values)
+		defer cleanup()
+		for _, s := range subcharts {
+			sub := AllWithOptions(s.Dir, s.Values, namespace, strict, opts)
+			linter.Merge(s.Path, sub, !s.Archive)
+		}
+	}
 	return linter
 }
@@ -33,6 +33,9 @@ const (
 	badValuesFileDir = "rules/testdata/badvaluesfile"
 	badYamlFileDir   = "rules/testdata/albatross"
 	goodChartDir     = "rules/testdata/goodone"
+	configuredDir    = "rules/testdata/configured"
+	outdatedDir      = "rules/testdata/outdated"
+	umbrellaDir      = "rules/testdata/umbrella"
 )
 
 func TestBadChart(t *testing.T) {
@@ -101,3 +104,70 @@ func TestGoodChart(t *testing.T) {
 		t.Errorf("All failed but shouldn't have: %#v", m)
 	}
 }
+
+func TestConfiguredChart(t *testing.T) {
+	m := All(configuredDir, values, namespace, strict).Messages
+	if len(m) != 1 {
+		t.Fatalf("Expected only the reconfigured rule to report, got %#v", m)
+	}
+	if m[0].Rule != "chart-icon" || m[0].Severity != support.ErrorSev {
+		t.Errorf("Expected chart-icon to report an error, got %#v", m[0])
+	}
+}
+
+func TestSchemaValidation(t *testing.T) {
+	if m := All(outdatedDir, values, namespace, strict).Messages; len(m) != 0 {
+		t.Errorf("Expected no schema validation without a Kubernetes version, got %#v", m)
+	}
+
+	m := AllWithOptions(outdatedDir, values, namespace, strict, Options{KubeVersion: "1.14"}).Messages
+	var rules []string
+	for _, msg := range m {
+		rules = append(rules, msg.Rule)
+	}
+	expected := []string{"template-api-deprecated", "template-schema", "template-schema", "template-api-deprecated"}
+	if strings.Join(rules, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected rules %v to report, got %#v", expected, m)
+	}
+	if !strings.Contains(m[1].Err.Error(), "spec.replicas: expected integer, got string") {
+		t.Errorf("Unexpected message: %s", m[1].Err)
+	}
+	if !strings.Contains(m[2].Err.Error(), "spec.template.spec.containers[0].port: unknown field") {
+		t.Errorf("Unexpected message: %s", m[2].Err)
+	}
+
+	m = AllWithOptions(outdatedDir, values, namespace, strict, Options{KubeVersion: "1.16"}).Messages
+	if len(m) != 2 || m[0].Rule != "template-api-removed" || m[0].Severity != support.ErrorSev {
+		t.Errorf("Expected the Deployment apiVersion to be reported as removed, got %#v", m)
+	}
+
+	m = AllWithOptions(outdatedDir, values, namespace, strict, Options{KubeVersion: "1.9", Deprecations: true}).Messages
+	if last := m[len(m)-1]; last.Rule != "template-api-upcoming" || !strings.Contains(last.Err.Error(), "Ingress is deprecated in Kubernetes 1.14") {
+		t.Errorf("Expected the Ingress apiVersion to be reported as an upcoming removal, got %#v", m)
+	}
+
+	m = AllWithOptions(outdatedDir, values, namespace, strict, Options{KubeVersion: "latest"}).Messages
+	if len(m) != 1 || m[0].Rule != "kube-version" {
+		t.Errorf("Expected an invalid Kubernetes version to be reported, got %#v", m)
+	}
+}
+
+func TestSubcharts(t *testing.T) {
+	if m := All(umbrellaDir, values, namespace, strict).Messages; len(m) != 0 {
+		t.Errorf("Expected the subcharts not to be linted, got %#v", m)
+	}
+	// Without the values of the umbrella chart, the backend renders invalid YAML.
+	if m := All(umbrellaDir+"/charts/backend", values, namespace, strict).Messages; len(m) != 1 || m[0].Rule != "template-yaml" {
+		t.Errorf("Expected the backend to render invalid YAML, got %#v", m)
+	}
+
+	// The disabled subchart is not linted, and the archived one is linted
+	// once extracted.
+	m := AllWithOptions(umbrellaDir, values, namespace, strict, Options{WithSubcharts: true}).Messages
+	if len(m) != 1 {
+		t.Fatalf("Expected a single message, got %#v", m)
+	}
+	if m[0].Rule != "chart-icon" || m[0].Path != "charts/frontend-0.1.0.tgz/Chart.yaml" {
+		t.Errorf("Expected chart-icon to report the frontend subchart, got %#v", m[0])
+	}
+}
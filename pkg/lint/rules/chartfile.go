@@ -19,6 +19,7 @@ package rules // import "k8s.io/helm/pkg/lint/rules"
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -36,28 +37,35 @@ func Chartfile(linter *support.Linter) {
 	chartFileName := "Chart.yaml"
 	chartPath := filepath.Join(linter.ChartDir, chartFileName)
 
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartYamlNotDirectory(chartPath))
+	linter.RunRule(chartfileIsFile, chartFileName, validateChartYamlNotDirectory(chartPath))
 
 	chartFile, err := chartutil.LoadChartfile(chartPath)
-	validChartFile := linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartYamlFormat(err))
+	validChartFile := linter.RunRule(chartfileFormat, chartFileName, validateChartYamlFormat(err))
 
 	// Guard clause. Following linter rules require a parseable ChartFile
 	if !validChartFile {
 		return
 	}
 
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartNamePresence(chartFile))
-	linter.RunLinterRule(support.WarningSev, chartFileName, validateChartNameFormat(chartFile))
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartNameDirMatch(linter.ChartDir, chartFile))
+	linter.RunRule(chartName, chartFileName, validateChartNamePresence(chartFile))
+	linter.RunRule(chartNameFormat, chartFileName, validateChartNameFormat(chartFile))
+	linter.RunRule(chartNameDir, chartFileName, validateChartNameDirMatch(linter.ChartDir, chartFile))
 
 	// Chart metadata
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartAPIVersion(chartFile))
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartVersion(chartFile))
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartEngine(chartFile))
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartMaintainer(chartFile))
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartSources(chartFile))
-	linter.RunLinterRule(support.InfoSev, chartFileName, validateChartIconPresence(chartFile))
-	linter.RunLinterRule(support.ErrorSev, chartFileName, validateChartIconURL(chartFile))
+	linter.RunRule(chartAPIVersion, chartFileName, validateChartAPIVersion(chartFile))
+	linter.RunRule(chartVersion, chartFileName, validateChartVersion(chartFile))
+	linter.RunRule(chartEngine, chartFileName, validateChartEngine(chartFile))
+	linter.RunRule(chartType, chartFileName, chartutil.ValidateChartType(chartFile))
+	linter.RunRule(chartMaintainer, chartFileName, validateChartMaintainer(chartFile))
+	linter.RunRule(chartSources, chartFileName, validateChartSources(chartFile))
+	linter.RunRule(chartIcon, chartFileName, validateChartIconPresence(chartFile))
+	linter.RunRule(chartIconURL, chartFileName, validateChartIconURL(chartFile))
+	linter.RunRule(chartAnnotations, chartFileName, chartutil.ValidateAnnotations(chartFile))
+
+	if data, err := ioutil.ReadFile(chartPath); err == nil {
+		linter.RunRule(chartDependencies, chartFileName, validateChartDependencies(linter.ChartDir, data))
+		runFixableRule(linter, chartfileQuoting, chartFileName, data, quoteChartfileFields)
+	}
 }
 
 func validateChartYamlNotDirectory(chartPath string) error {
@@ -191,3 +199,28 @@ func validateChartIconURL(cf *chart.Metadata) error {
 	}
 	return nil
 }
+
+func validateChartDependencies(chartDir string, data []byte) error {
+	deps, err := chartutil.ChartfileDependencies(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse dependencies\n\t%s", err)
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(chartDir, "requirements.yaml")); err == nil {
+		return errors.New("dependencies are declared in both Chart.yaml and requirements.yaml")
+	}
+	for i, dep := range deps {
+		if dep.Name == "" {
+			return fmt.Errorf("dependency %d has no name", i+1)
+		}
+		if dep.Version == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(dep.Version); err != nil {
+			return fmt.Errorf("dependency %s has an invalid version range %q: %s", dep.Name, dep.Version, err)
+		}
+	}
+	return nil
+}
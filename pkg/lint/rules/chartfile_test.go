@@ -231,6 +231,33 @@ func TestValidateChartIconURL(t *testing.T) {
 	}
 }
 
+func TestValidateChartDependencies(t *testing.T) {
+	tests := []struct {
+		chartfile string
+		err       string
+	}{
+		{"name: foo\n", ""},
+		{"name: foo\ndependencies:\n- name: bar\n  version: '>=1.0.0'\n", ""},
+		{"name: foo\ndependencies:\n- version: 1.0.0\n", "dependency 1 has no name"},
+		{"name: foo\ndependencies:\n- name: bar\n  version: not-a-version\n", "invalid version range"},
+		{"name: foo\ndependencies: bar\n", "unable to parse dependencies"},
+	}
+	for _, tt := range tests {
+		err := validateChartDependencies(goodChartDir, []byte(tt.chartfile))
+		if tt.err == "" && err != nil {
+			t.Errorf("%q: expected no error, got %s", tt.chartfile, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%q: expected error %q, got %v", tt.chartfile, tt.err, err)
+		}
+	}
+
+	err := validateChartDependencies("testdata/umbrella", []byte("name: umbrella\ndependencies:\n- name: backend\n"))
+	if err == nil || !strings.Contains(err.Error(), "both Chart.yaml and requirements.yaml") {
+		t.Errorf("expected an error for dependencies declared twice, got %v", err)
+	}
+}
+
 func TestChartfile(t *testing.T) {
 	linter := support.Linter{ChartDir: badChartDir}
 	Chartfile(&linter)
@@ -0,0 +1,84 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"k8s.io/helm/pkg/lint/support"
+)
+
+// ExternalRule is a lint rule implemented by a command, such as one shipped
+// by a plugin.
+type ExternalRule struct {
+	support.Rule
+	// Command is run with Args and the chart directory as arguments.
+	Command string
+	Args    []string
+	// Env is the environment of the command. A nil Env runs it in the
+	// environment of Helm.
+	Env []string
+}
+
+// externalFinding is a problem reported by an external rule.
+type externalFinding struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// External runs an external lint rule. The command reports each problem it
+// finds as a JSON object on its standard output, such as:
+//
+//	{"path": "templates/deployment.yaml", "message": "the team label is missing"}
+//
+// A command exiting with an error fails the rule as a whole.
+func External(linter *support.Linter, rule ExternalRule) {
+	cmd := exec.Command(rule.Command, append(rule.Args, linter.ChartDir)...)
+	cmd.Env = rule.Env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		linter.RunRule(rule.Rule, ".", fmt.Errorf("rule %s failed: %s", rule.ID, msg))
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var f externalFinding
+		if err := dec.Decode(&f); err == io.EOF {
+			return
+		} else if err != nil {
+			linter.RunRule(rule.Rule, ".", fmt.Errorf("rule %s sent invalid output: %s", rule.ID, err))
+			return
+		}
+		if f.Path == "" {
+			f.Path = "."
+		}
+		linter.RunRule(rule.Rule, f.Path, errors.New(f.Message))
+	}
+}
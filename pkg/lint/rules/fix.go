@@ -0,0 +1,149 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/lint/support"
+)
+
+// runFixableRule reports what fixer would change in the file at path as a
+// message of rule, and records the fix.
+func runFixableRule(linter *support.Linter, rule support.Rule, path string, data []byte, fixer support.Fixer) {
+	if _, findings := fixer(data); len(findings) > 0 {
+		linter.RunRule(rule, path, errors.New(strings.Join(findings, ", ")))
+	}
+	linter.RunFixer(rule, path, data, fixer)
+}
+
+// chartfileFieldRegex matches the string fields of Chart.yaml that are
+// commonly written unquoted.
+var chartfileFieldRegex = regexp.MustCompile(`^(version|appVersion|kubeVersion|icon):[ \t]*(.*?)[ \t]*$`)
+
+// quoteChartfileFields quotes the values of the string fields of Chart.yaml
+// that YAML would not read as they are written, such as an appVersion of
+// 1.10 read as the number 1.1.
+func quoteChartfileFields(data []byte) ([]byte, []string) {
+	lines := strings.Split(string(data), "\n")
+	var findings []string
+	for i, line := range lines {
+		m := chartfileFieldRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value, comment := m[2], ""
+		if j := strings.Index(value, " #"); j >= 0 {
+			value, comment = strings.TrimSpace(value[:j]), value[j:]
+		}
+		if !needsQuoting(value) {
+			continue
+		}
+		lines[i] = fmt.Sprintf("%s: %s%s", m[1], strconv.Quote(value), comment)
+		findings = append(findings, fmt.Sprintf("line %d: %s %s should be quoted", i+1, m[1], value))
+	}
+	return []byte(strings.Join(lines, "\n")), findings
+}
+
+// needsQuoting returns true if YAML does not read the unquoted value as the
+// string it is written as.
+func needsQuoting(value string) bool {
+	if value == "" || strings.ContainsAny(value[:1], `"'|>&*!`) {
+		return false
+	}
+	var m map[string]interface{}
+	if err := yaml.Unmarshal([]byte("v: "+value), &m); err != nil {
+		return true
+	}
+	s, ok := m["v"].(string)
+	return !ok || s != value
+}
+
+// fixWhitespace removes the trailing whitespace of every line, and replaces
+// each tab of the indentation of a line by two spaces.
+func fixWhitespace(data []byte) ([]byte, []string) {
+	lines := strings.Split(string(data), "\n")
+	var findings []string
+	for i, line := range lines {
+		fixed := strings.TrimRight(line, " \t")
+		if fixed != line {
+			findings = append(findings, fmt.Sprintf("line %d: trailing whitespace", i+1))
+		}
+		content := strings.TrimLeft(fixed, " \t")
+		if indent := fixed[:len(fixed)-len(content)]; strings.Contains(indent, "\t") {
+			fixed = strings.Replace(indent, "\t", "  ", -1) + content
+			findings = append(findings, fmt.Sprintf("line %d: tab indentation", i+1))
+		}
+		lines[i] = fixed
+	}
+	return []byte(strings.Join(lines, "\n")), findings
+}
+
+// legacyLabelRegex matches the labels of the charts written before the
+// Kubernetes recommended labels, which are not used by selectors.
+var legacyLabelRegex = regexp.MustCompile(`^(\s*)(heritage|chart):(\s*)(.*)$`)
+
+// recommendedLabels maps the legacy labels to the recommended ones.
+var recommendedLabels = map[string]string{
+	"heritage": "app.kubernetes.io/managed-by",
+	"chart":    "helm.sh/chart",
+}
+
+// fixLabels renames the legacy heritage and chart labels to their
+// recommended names. Only labels set to the usual values are renamed.
+func fixLabels(data []byte) ([]byte, []string) {
+	lines := strings.Split(string(data), "\n")
+	var findings []string
+	for i, line := range lines {
+		m := legacyLabelRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := m[4]
+		switch m[2] {
+		case "heritage":
+			if !strings.Contains(value, ".Release.Service") {
+				continue
+			}
+		case "chart":
+			if !strings.Contains(value, ".Chart.Version") && !strings.Contains(value, `.chart"`) {
+				continue
+			}
+		}
+		label := recommendedLabels[m[2]]
+		lines[i] = m[1] + label + ":" + m[3] + value
+		findings = append(findings, fmt.Sprintf("line %d: label %s should be %s", i+1, m[2], label))
+	}
+	return []byte(strings.Join(lines, "\n")), findings
+}
+
+// fixableTemplate returns true if the whitespace and labels of the template
+// named fileName can be fixed.
+func fixableTemplate(fileName string) bool {
+	switch filepath.Ext(fileName) {
+	case ".yaml", ".yml", ".tpl":
+		return true
+	}
+	return false
+}
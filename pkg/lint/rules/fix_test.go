@@ -0,0 +1,89 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/lint/support"
+)
+
+func TestFixers(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixer    support.Fixer
+		in       string
+		out      string
+		findings []string
+	}{
+		{
+			name:  "quoting",
+			fixer: quoteChartfileFields,
+			in:    "name: app\nversion: 1.0.0\nappVersion: 1.10 # the app\nkubeVersion: \">=1.10\"\nicon: true\n",
+			out:   "name: app\nversion: 1.0.0\nappVersion: \"1.10\" # the app\nkubeVersion: \">=1.10\"\nicon: \"true\"\n",
+			findings: []string{
+				"line 3: appVersion 1.10 should be quoted",
+				"line 5: icon true should be quoted",
+			},
+		},
+		{
+			name:  "whitespace",
+			fixer: fixWhitespace,
+			in:    "kind: Pod  \nmetadata:\n\tname: a\t\n",
+			out:   "kind: Pod\nmetadata:\n  name: a\n",
+			findings: []string{
+				"line 1: trailing whitespace",
+				"line 3: trailing whitespace",
+				"line 3: tab indentation",
+			},
+		},
+		{
+			name:  "labels",
+			fixer: fixLabels,
+			in:    "  labels:\n    chart: {{ .Chart.Name }}-{{ .Chart.Version }}\n    heritage: {{ .Release.Service }}\n    release: {{ .Release.Name }}\n  chart: other\n",
+			out:   "  labels:\n    helm.sh/chart: {{ .Chart.Name }}-{{ .Chart.Version }}\n    app.kubernetes.io/managed-by: {{ .Release.Service }}\n    release: {{ .Release.Name }}\n  chart: other\n",
+			findings: []string{
+				"line 2: label chart should be helm.sh/chart",
+				"line 3: label heritage should be app.kubernetes.io/managed-by",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		out, findings := tt.fixer([]byte(tt.in))
+		if string(out) != tt.out {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.out, out)
+		}
+		if strings.Join(findings, "\n") != strings.Join(tt.findings, "\n") {
+			t.Errorf("%s: expected findings %q, got %q", tt.name, tt.findings, findings)
+		}
+	}
+}
+
+func TestRunFixableRule(t *testing.T) {
+	linter := support.Linter{}
+	runFixableRule(&linter, templateSpacing, "templates/a.yaml", []byte("a: b \n"), fixWhitespace)
+	runFixableRule(&linter, templateSpacing, "templates/b.yaml", []byte("a: b\n"), fixWhitespace)
+
+	if len(linter.Messages) != 1 || linter.Messages[0].Rule != "template-whitespace" || linter.Messages[0].Severity != support.InfoSev {
+		t.Fatalf("Expected one template-whitespace message, got %#v", linter.Messages)
+	}
+	if len(linter.Fixes) != 1 || string(linter.Fixes[0].Fixed) != "a: b\n" {
+		t.Errorf("Expected templates/a.yaml to be fixed, got %#v", linter.Fixes)
+	}
+}
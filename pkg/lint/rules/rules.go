@@ -0,0 +1,93 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import "k8s.io/helm/pkg/lint/support"
+
+// The built-in lint rules.
+var (
+	chartfileIsFile   = support.Rule{ID: "chartfile-is-file", Severity: support.ErrorSev, Description: "Chart.yaml is a file"}
+	chartfileFormat   = support.Rule{ID: "chartfile-format", Severity: support.ErrorSev, Description: "Chart.yaml can be parsed"}
+	chartName         = support.Rule{ID: "chart-name", Severity: support.ErrorSev, Description: "The chart has a name"}
+	chartNameFormat   = support.Rule{ID: "chart-name-format", Severity: support.WarningSev, Description: "The chart name contains no dots"}
+	chartNameDir      = support.Rule{ID: "chart-name-dir", Severity: support.ErrorSev, Description: "The chart name matches its directory"}
+	chartAPIVersion   = support.Rule{ID: "chart-api-version", Severity: support.ErrorSev, Description: "The chart has a supported apiVersion"}
+	chartVersion      = support.Rule{ID: "chart-version", Severity: support.ErrorSev, Description: "The chart version is a positive SemVer 2 version"}
+	chartEngine       = support.Rule{ID: "chart-engine", Severity: support.ErrorSev, Description: "The chart uses a supported template engine"}
+	chartType         = support.Rule{ID: "chart-type", Severity: support.ErrorSev, Description: "The chart type is application or library"}
+	chartMaintainer   = support.Rule{ID: "chart-maintainer", Severity: support.ErrorSev, Description: "Every maintainer has a name and valid email and URL"}
+	chartSources      = support.Rule{ID: "chart-sources", Severity: support.ErrorSev, Description: "Every source is a valid URL"}
+	chartIcon         = support.Rule{ID: "chart-icon", Severity: support.InfoSev, Description: "The chart has an icon"}
+	chartIconURL      = support.Rule{ID: "chart-icon-url", Severity: support.ErrorSev, Description: "The chart icon is a valid URL"}
+	chartAnnotations  = support.Rule{ID: "chart-annotations", Severity: support.ErrorSev, Description: "The chart annotations have valid keys"}
+	chartDependencies = support.Rule{ID: "chart-dependencies", Severity: support.ErrorSev, Description: "The dependencies of Chart.yaml have a name and a valid version range"}
+	chartfileQuoting  = support.Rule{ID: "chartfile-quoting", Severity: support.WarningSev, Description: "Chart.yaml quotes the strings YAML would read as something else"}
+	valuesFile        = support.Rule{ID: "values-file", Severity: support.InfoSev, Description: "The chart has a values.yaml file"}
+	valuesFormat      = support.Rule{ID: "values-format", Severity: support.ErrorSev, Description: "values.yaml can be parsed"}
+	templatesDir      = support.Rule{ID: "templates-dir", Severity: support.WarningSev, Description: "The chart has a templates directory"}
+	chartLoad         = support.Rule{ID: "chart-load", Severity: support.ErrorSev, Description: "The chart can be loaded"}
+	templateRender    = support.Rule{ID: "template-render", Severity: support.ErrorSev, Description: "The templates render"}
+	templateExtension = support.Rule{ID: "template-extension", Severity: support.WarningSev, Description: "Templates have a known file extension"}
+	templateYAML      = support.Rule{ID: "template-yaml", Severity: support.ErrorSev, Description: "YAML templates render to valid YAML"}
+	templateSpacing   = support.Rule{ID: "template-whitespace", Severity: support.InfoSev, Description: "Templates have no trailing whitespace or tab indentation"}
+	templateLabels    = support.Rule{ID: "template-labels", Severity: support.InfoSev, Description: "Templates use the recommended Kubernetes labels"}
+	chartSecrets      = support.Rule{ID: "chart-secrets", Severity: support.ErrorSev, Description: "The files of the chart contain no likely credentials"}
+	subchartLoad      = support.Rule{ID: "subchart-load", Severity: support.ErrorSev, Description: "The subcharts can be loaded with the values of the chart"}
+
+	// Rules validating the rendered manifests against Kubernetes schemas
+	kubeVersion           = support.Rule{ID: "kube-version", Severity: support.ErrorSev, Description: "The targeted Kubernetes version is valid"}
+	templateSchema        = support.Rule{ID: "template-schema", Severity: support.ErrorSev, Description: "Rendered manifests match the Kubernetes schemas"}
+	templateAPIRemoved    = support.Rule{ID: "template-api-removed", Severity: support.ErrorSev, Description: "Rendered manifests use apiVersions served by the targeted Kubernetes version"}
+	templateAPIDeprecated = support.Rule{ID: "template-api-deprecated", Severity: support.WarningSev, Description: "Rendered manifests use apiVersions that are not deprecated"}
+	templateAPIUpcoming   = support.Rule{ID: "template-api-upcoming", Severity: support.WarningSev, Description: "Rendered manifests use apiVersions that later Kubernetes versions do not deprecate"}
+)
+
+// Builtin lists the built-in lint rules.
+var Builtin = []support.Rule{
+	chartfileIsFile,
+	chartfileFormat,
+	chartName,
+	chartNameFormat,
+	chartNameDir,
+	chartAPIVersion,
+	chartVersion,
+	chartEngine,
+	chartType,
+	chartMaintainer,
+	chartSources,
+	chartIcon,
+	chartIconURL,
+	chartAnnotations,
+	chartDependencies,
+	chartfileQuoting,
+	valuesFile,
+	valuesFormat,
+	templatesDir,
+	chartLoad,
+	templateRender,
+	templateExtension,
+	templateYAML,
+	templateSpacing,
+	templateLabels,
+	chartSecrets,
+	subchartLoad,
+	kubeVersion,
+	templateSchema,
+	templateAPIRemoved,
+	templateAPIDeprecated,
+	templateAPIUpcoming,
+}
@@ -0,0 +1,41 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/secretscan"
+)
+
+// Secrets reports the likely credentials found in the files of the chart,
+// unless the configuration of the linter allows them.
+func Secrets(linter *support.Linter) {
+	chart, err := chartutil.Load(linter.ChartDir)
+	if err != nil {
+		// charts that cannot be loaded are reported by the template rules
+		return
+	}
+
+	var allow []secretscan.Allow
+	if linter.Config != nil {
+		allow = linter.Config.Secrets.Allow
+	}
+	for _, f := range secretscan.ScanChart(chart, allow) {
+		linter.RunRule(chartSecrets, f.Path, f)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/lint/support"
+	cpb "k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Subchart is a subchart of the charts/ directory of a chart, to be linted
+// with the values the chart gives it.
+type Subchart struct {
+	// Name is the name of the subchart in the chart, which is its alias if
+	// it has one. The values of the chart for the subchart are under it.
+	Name string
+	// Path is the path of the subchart relative to the chart, such as
+	// charts/mysql or charts/mysql-1.0.0.tgz.
+	Path string
+	// Dir is the directory of the subchart. A subchart archive is extracted
+	// to a temporary directory.
+	Dir string
+	// Archive tells whether the subchart is an archive, whose files cannot
+	// be fixed.
+	Archive bool
+	// Values are the values the chart gives the subchart, as YAML.
+	Values []byte
+}
+
+// Subcharts returns the subcharts of the chart in the Linter that the values
+// enable, sorted by name. A subchart used under several aliases is returned
+// once for each.
+//
+// The returned function removes the temporary directories of the subchart
+// archives once they are linted.
+func Subcharts(linter *support.Linter, values []byte) ([]Subchart, func()) {
+	var tmpDirs []string
+	cleanup := func() {
+		for _, d := range tmpDirs {
+			os.RemoveAll(d)
+		}
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(linter.ChartDir, chartutil.ChartsDir))
+	if err != nil {
+		// The chart has no subcharts.
+		return nil, cleanup
+	}
+
+	c, err := chartutil.LoadDir(linter.ChartDir)
+	if !linter.RunRule(subchartLoad, chartutil.ChartsDir, err) {
+		return nil, cleanup
+	}
+	config := &cpb.Config{Raw: string(values)}
+	err = chartutil.ProcessRequirementsEnabled(c, config)
+	if err == nil {
+		err = chartutil.ProcessRequirementsImportValues(c)
+	}
+	if !linter.RunRule(subchartLoad, chartutil.ChartsDir, err) {
+		return nil, cleanup
+	}
+	cvals, err := chartutil.CoalesceValues(c, config)
+	if !linter.RunRule(subchartLoad, chartutil.ChartsDir, err) {
+		return nil, cleanup
+	}
+
+	// The enabled subcharts, by the chart they come from.
+	enabled := map[string][]string{}
+	aliases := map[string]string{}
+	if reqs, err := chartutil.LoadRequirements(c); err == nil {
+		for _, r := range reqs.Dependencies {
+			if r.Alias != "" {
+				aliases[r.Alias] = r.Name
+			}
+		}
+	}
+	for _, dep := range c.Dependencies {
+		name := dep.Metadata.Name
+		from := name
+		if n, ok := aliases[name]; ok {
+			from = n
+		}
+		enabled[from] = append(enabled[from], name)
+	}
+
+	var subcharts []Subchart
+	for _, fi := range entries {
+		if strings.IndexAny(fi.Name(), "._") == 0 {
+			continue
+		}
+		rel := path.Join(chartutil.ChartsDir, fi.Name())
+		dir := filepath.Join(linter.ChartDir, chartutil.ChartsDir, fi.Name())
+		archive := !fi.IsDir()
+
+		var md *cpb.Metadata
+		if archive {
+			if !chartutil.IsArchive(fi.Name()) {
+				continue
+			}
+			sc, err := chartutil.LoadFile(dir)
+			if !linter.RunRule(subchartLoad, rel, err) {
+				continue
+			}
+			tmp, err := ioutil.TempDir("", "helm-lint-")
+			if !linter.RunRule(subchartLoad, rel, err) {
+				continue
+			}
+			tmpDirs = append(tmpDirs, tmp)
+			if !linter.RunRule(subchartLoad, rel, chartutil.ExpandFile(tmp, dir)) {
+				continue
+			}
+			md = sc.Metadata
+			dir = filepath.Join(tmp, md.Name)
+		} else {
+			if md, err = chartutil.LoadChartfile(filepath.Join(dir, chartutil.ChartfileName)); !linter.RunRule(subchartLoad, rel, err) {
+				continue
+			}
+		}
+
+		for _, name := range enabled[md.Name] {
+			vals, err := cvals.Table(name)
+			if err != nil {
+				vals = chartutil.Values{}
+			}
+			y, err := vals.YAML()
+			if !linter.RunRule(subchartLoad, rel, err) {
+				continue
+			}
+			subcharts = append(subcharts, Subchart{Name: name, Path: rel, Dir: dir, Archive: archive, Values: []byte(y)})
+		}
+	}
+	sort.SliceStable(subcharts, func(i, j int) bool { return subcharts[i].Name < subcharts[j].Name })
+	return subcharts, cleanup
+}
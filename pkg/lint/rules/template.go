@@ -25,8 +25,10 @@ import (
 	"github.com/ghodss/yaml"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/kubeschema"
 	"k8s.io/helm/pkg/lint/support"
 	cpb "k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/timeconv"
 	tversion "k8s.io/helm/pkg/version"
 )
@@ -36,7 +38,7 @@ func Templates(linter *support.Linter, values []byte, namespace string, strict b
 	path := "templates/"
 	templatesPath := filepath.Join(linter.ChartDir, path)
 
-	templatesDirExist := linter.RunLinterRule(support.WarningSev, path, validateTemplatesDir(templatesPath))
+	templatesDirExist := linter.RunRule(templatesDir, path, validateTemplatesDir(templatesPath))
 
 	// Templates directory is optional for now
 	if !templatesDirExist {
@@ -46,13 +48,26 @@ func Templates(linter *support.Linter, values []byte, namespace string, strict b
 	// Load chart and parse templates, based on tiller/release_server
 	chart, err := chartutil.Load(linter.ChartDir)
 
-	chartLoaded := linter.RunLinterRule(support.ErrorSev, path, err)
+	chartLoaded := linter.RunRule(chartLoad, path, err)
 
 	if !chartLoaded {
 		return
 	}
 
-	options := chartutil.ReleaseOptions{Name: "testRelease", Time: timeconv.Now(), Namespace: namespace}
+	// templates can suppress rules for themselves with comments such as
+	// {{/* helmlint:disable template-yaml */}}
+	for _, t := range chart.Templates {
+		linter.Suppress(t.Name, support.ParseSuppressions(t.Data)...)
+	}
+
+	for _, t := range chart.Templates {
+		if fixableTemplate(t.Name) {
+			runFixableRule(linter, templateSpacing, t.Name, t.Data, fixWhitespace)
+			runFixableRule(linter, templateLabels, t.Name, t.Data, fixLabels)
+		}
+	}
+
+	options := chartutil.ReleaseOptions{Name: "testRelease", Time: timeconv.Now(), Namespace: namespace, IsInstall: true, Revision: 1}
 	caps := &chartutil.Capabilities{
 		APIVersions:   chartutil.DefaultVersionSet,
 		KubeVersion:   chartutil.DefaultKubeVersion,
@@ -82,12 +97,22 @@ func Templates(linter *support.Linter, values []byte, namespace string, strict b
 	}
 	renderedContentMap, err := e.Render(chart, valuesToRender)
 
-	renderOk := linter.RunLinterRule(support.ErrorSev, path, err)
+	renderOk := linter.RunRule(templateRender, path, err)
 
 	if !renderOk {
 		return
 	}
 
+	var validator *kubeschema.Validator
+	if linter.KubeVersion != "" {
+		validator, err = kubeschema.NewValidator(linter.KubeVersion)
+		if !linter.RunRule(kubeVersion, path, err) {
+			validator = nil
+		} else {
+			validator.ReportUpcoming = linter.Deprecations
+		}
+	}
+
 	/* Iterate over all the templates to check:
 	- It is a .yaml file
 	- All the values in the template file is defined
@@ -99,7 +124,7 @@ func Templates(linter *support.Linter, values []byte, namespace string, strict b
 		fileName, _ := template.Name, template.Data
 		path = fileName
 
-		linter.RunLinterRule(support.WarningSev, path, validateAllowedExtension(fileName))
+		linter.RunRule(templateExtension, path, validateAllowedExtension(fileName))
 
 		// We only apply the following lint rules to yaml files
 		if filepath.Ext(fileName) != ".yaml" || filepath.Ext(fileName) == ".yml" {
@@ -112,11 +137,41 @@ func Templates(linter *support.Linter, values []byte, namespace string, strict b
 		// key will be raised as well
 		err := yaml.Unmarshal([]byte(renderedContent), &yamlStruct)
 
-		validYaml := linter.RunLinterRule(support.ErrorSev, path, validateYamlContent(err))
+		validYaml := linter.RunRule(templateYAML, path, validateYamlContent(err))
 
 		if !validYaml {
 			continue
 		}
+
+		// The outputs of a chart are not Kubernetes resources
+		if validator != nil && fileName != filepath.Join(chartutil.TemplatesDir, chartutil.OutputsName) {
+			validateSchemas(linter, validator, path, renderedContent)
+		}
+	}
+}
+
+// validateSchemas validates each document of a rendered template against the
+// Kubernetes schemas.
+func validateSchemas(linter *support.Linter, validator *kubeschema.Validator, path, content string) {
+	docs := releaseutil.SplitManifests(content)
+	for i := 0; i < len(docs); i++ {
+		findings, err := validator.Validate(docs[fmt.Sprintf("manifest-%d", i)])
+		if err != nil {
+			linter.RunRule(templateSchema, path, err)
+			continue
+		}
+		for _, f := range findings {
+			switch f.Type {
+			case kubeschema.RemovedAPI:
+				linter.RunRule(templateAPIRemoved, path, f)
+			case kubeschema.DeprecatedAPI:
+				linter.RunRule(templateAPIDeprecated, path, f)
+			case kubeschema.UpcomingRemoval:
+				linter.RunRule(templateAPIUpcoming, path, f)
+			default:
+				linter.RunRule(templateSchema, path, f)
+			}
+		}
 	}
 }
 
@@ -0,0 +1,63 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support
+
+// Fix holds the content of a file of the chart once the findings of the
+// rules able to fix them are resolved.
+type Fix struct {
+	// Path is the path of the file, relative to the chart directory.
+	Path     string
+	Original []byte
+	Fixed    []byte
+	// Changes describes each change made to the file.
+	Changes []string
+}
+
+// Fixer rewrites the content of a file to resolve the findings of a rule. It
+// returns the new content and a description of each change, if any.
+type Fixer func(data []byte) ([]byte, []string)
+
+// RunFixer applies fixer, on behalf of rule, to the file at path, unless
+// the rule is disabled or suppressed for path. data is the content of the
+// file as read by the rule; fixers applied earlier to the same file are
+// taken into account.
+func (l *Linter) RunFixer(rule Rule, path string, data []byte, fixer Fixer) {
+	if _, enabled := l.Config.severity(rule); !enabled || l.suppressed[path][rule.ID] {
+		return
+	}
+
+	var fix *Fix
+	for i := range l.Fixes {
+		if l.Fixes[i].Path == path {
+			fix = &l.Fixes[i]
+			data = fix.Fixed
+		}
+	}
+
+	fixed, changes := fixer(data)
+	if len(changes) == 0 {
+		return
+	}
+	if fix == nil {
+		l.Fixes = append(l.Fixes, Fix{Path: path, Original: data})
+		fix = &l.Fixes[len(l.Fixes)-1]
+	}
+	fix.Fixed = fixed
+	for _, c := range changes {
+		fix.Changes = append(fix.Changes, rule.ID+": "+c)
+	}
+}
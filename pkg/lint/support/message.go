@@ -16,7 +16,10 @@ limitations under the License.
 
 package support
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+)
 
 // Severity indicates the severity of a Message.
 const (
@@ -39,6 +42,20 @@ type Linter struct {
 	// The highest severity of all the failing lint rules
 	HighestSeverity int
 	ChartDir        string
+	// Config configures the rules run through RunRule. A nil Config runs
+	// every rule with its own severity.
+	Config *Config
+	// KubeVersion is the Kubernetes version rendered manifests are validated
+	// against. Manifests are not validated if it is empty.
+	KubeVersion string
+	// Deprecations also reports apiVersions that Kubernetes versions later
+	// than KubeVersion deprecate.
+	Deprecations bool
+	// Fixes holds the files rewritten by the rules able to fix their own
+	// findings. They are not written to the chart.
+	Fixes []Fix
+	// suppressed holds the IDs of the rules suppressed for each path.
+	suppressed map[string]map[string]bool
 }
 
 // Message describes an error encountered while linting.
@@ -47,6 +64,8 @@ type Message struct {
 	Severity int
 	Path     string
 	Err      error
+	// Rule is the ID of the rule reporting the message, if any.
+	Rule string
 }
 
 func (m Message) Error() string {
@@ -74,3 +93,71 @@ func (l *Linter) RunLinterRule(severity int, path string, err error) bool {
 	}
 	return err == nil
 }
+
+// RunRule records err as a message of rule, unless the rule is disabled by
+// the configuration of the linter or suppressed for path. It returns true if
+// the validation passed, whether or not the message was recorded.
+func (l *Linter) RunRule(rule Rule, path string, err error) bool {
+	if err == nil {
+		return true
+	}
+	severity, enabled := l.Config.severity(rule)
+	if !enabled || l.suppressed[path][rule.ID] {
+		return false
+	}
+	msg := NewMessage(severity, path, err)
+	msg.Rule = rule.ID
+	l.Messages = append(l.Messages, msg)
+	if severity > l.HighestSeverity {
+		l.HighestSeverity = severity
+	}
+	return false
+}
+
+// Suppress stops the given rules from reporting messages for path.
+func (l *Linter) Suppress(path string, ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+	if l.suppressed == nil {
+		l.suppressed = map[string]map[string]bool{}
+	}
+	if l.suppressed[path] == nil {
+		l.suppressed[path] = map[string]bool{}
+	}
+	for _, id := range ids {
+		l.suppressed[path][id] = true
+	}
+}
+
+// Merge adds the messages of the linter of a subchart to l, with their paths
+// prefixed by the path of the subchart relative to the chart of l, such as
+// charts/mysql. The fixes of the subchart are added too if withFixes is set.
+func (l *Linter) Merge(prefix string, sub Linter, withFixes bool) {
+	for _, m := range sub.Messages {
+		m.Path = path.Join(prefix, m.Path)
+		l.Messages = append(l.Messages, m)
+	}
+	if sub.HighestSeverity > l.HighestSeverity {
+		l.HighestSeverity = sub.HighestSeverity
+	}
+	if !withFixes {
+		return
+	}
+	for _, f := range sub.Fixes {
+		f.Path = path.Join(prefix, f.Path)
+		// A subchart used under several aliases is linted for each.
+		if !l.hasFix(f.Path) {
+			l.Fixes = append(l.Fixes, f)
+		}
+	}
+}
+
+func (l *Linter) hasFix(name string) bool {
+	for _, f := range l.Fixes {
+		if f.Path == name {
+			return true
+		}
+	}
+	return false
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package support
 
 import (
+	"bytes"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -62,18 +64,93 @@ func TestRunLinterRule(t *testing.T) {
 }
 
 func TestMessage(t *testing.T) {
-	m := Message{ErrorSev, "Chart.yaml", errors.New("Foo")}
+	m := Message{Severity: ErrorSev, Path: "Chart.yaml", Err: errors.New("Foo")}
 	if m.Error() != "[ERROR] Chart.yaml: Foo" {
 		t.Errorf("Unexpected output: %s", m.Error())
 	}
 
-	m = Message{WarningSev, "templates/", errors.New("Bar")}
+	m = Message{Severity: WarningSev, Path: "templates/", Err: errors.New("Bar")}
 	if m.Error() != "[WARNING] templates/: Bar" {
 		t.Errorf("Unexpected output: %s", m.Error())
 	}
 
-	m = Message{InfoSev, "templates/rc.yaml", errors.New("FooBar")}
+	m = Message{Severity: InfoSev, Path: "templates/rc.yaml", Err: errors.New("FooBar")}
 	if m.Error() != "[INFO] templates/rc.yaml: FooBar" {
 		t.Errorf("Unexpected output: %s", m.Error())
 	}
 }
+
+func TestRunRule(t *testing.T) {
+	rule := Rule{ID: "test-rule", Severity: WarningSev}
+
+	l := Linter{}
+	if l.RunRule(rule, "Chart.yaml", nil) != true {
+		t.Error("RunRule should return true when the rule passes")
+	}
+	if l.RunRule(rule, "Chart.yaml", errLint) != false || len(l.Messages) != 1 {
+		t.Fatalf("RunRule should record a failing rule, got %#v", l.Messages)
+	}
+	if l.Messages[0].Rule != "test-rule" || l.HighestSeverity != WarningSev {
+		t.Errorf("Unexpected message: %#v", l.Messages[0])
+	}
+
+	l = Linter{Config: &Config{Rules: map[string]RuleConfig{"test-rule": {Severity: "error"}}}}
+	l.RunRule(rule, "Chart.yaml", errLint)
+	if len(l.Messages) != 1 || l.HighestSeverity != ErrorSev {
+		t.Errorf("Expected the configured severity, got %#v", l.Messages)
+	}
+
+	l = Linter{Config: &Config{Rules: map[string]RuleConfig{"test-rule": {Disabled: true}}}}
+	if l.RunRule(rule, "Chart.yaml", errLint) != false || len(l.Messages) != 0 {
+		t.Errorf("Expected a disabled rule to fail without reporting, got %#v", l.Messages)
+	}
+
+	l = Linter{}
+	l.Suppress("templates/a.yaml", ParseSuppressions([]byte("{{/* helmlint:disable other,test-rule */}}"))...)
+	l.RunRule(rule, "templates/a.yaml", errLint)
+	l.RunRule(rule, "templates/b.yaml", errLint)
+	if len(l.Messages) != 1 || l.Messages[0].Path != "templates/b.yaml" {
+		t.Errorf("Expected the rule to be suppressed for templates/a.yaml only, got %#v", l.Messages)
+	}
+}
+
+func TestRunFixer(t *testing.T) {
+	upper := func(data []byte) ([]byte, []string) {
+		fixed := bytes.ToUpper(data)
+		if bytes.Equal(fixed, data) {
+			return data, nil
+		}
+		return fixed, []string{"upper case"}
+	}
+	suffix := func(data []byte) ([]byte, []string) {
+		return append(append([]byte{}, data...), '!'), []string{"suffixed"}
+	}
+	rule := Rule{ID: "test-rule", Severity: WarningSev}
+	other := Rule{ID: "other-rule", Severity: WarningSev}
+
+	l := Linter{}
+	l.RunFixer(rule, "a.yaml", []byte("A"), upper)
+	if len(l.Fixes) != 0 {
+		t.Fatalf("Expected no fix when nothing changes, got %#v", l.Fixes)
+	}
+	l.RunFixer(rule, "a.yaml", []byte("a"), upper)
+	l.RunFixer(other, "a.yaml", []byte("a"), suffix)
+	if len(l.Fixes) != 1 {
+		t.Fatalf("Expected the fixes of a file to be combined, got %#v", l.Fixes)
+	}
+	f := l.Fixes[0]
+	if string(f.Original) != "a" || string(f.Fixed) != "A!" {
+		t.Errorf("Unexpected fix content %q -> %q", f.Original, f.Fixed)
+	}
+	if strings.Join(f.Changes, "; ") != "test-rule: upper case; other-rule: suffixed" {
+		t.Errorf("Unexpected changes %v", f.Changes)
+	}
+
+	l = Linter{Config: &Config{Rules: map[string]RuleConfig{"test-rule": {Disabled: true}}}}
+	l.Suppress("b.yaml", "other-rule")
+	l.RunFixer(rule, "a.yaml", []byte("a"), upper)
+	l.RunFixer(other, "b.yaml", []byte("b"), suffix)
+	if len(l.Fixes) != 0 {
+		t.Errorf("Expected disabled and suppressed rules not to fix, got %#v", l.Fixes)
+	}
+}
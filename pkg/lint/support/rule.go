@@ -0,0 +1,151 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/secretscan"
+)
+
+// ConfigFileName is the name of the file, at the root of a chart, that
+// configures the lint rules run on the chart.
+const ConfigFileName = ".helmlint.yaml"
+
+// Rule describes a lint rule.
+type Rule struct {
+	// ID identifies the rule in configuration and suppression comments.
+	ID string
+	// Severity is the severity of the messages of the rule, unless
+	// configured otherwise.
+	Severity    int
+	Description string
+}
+
+// Config configures the lint rules run on a chart.
+type Config struct {
+	Rules   map[string]RuleConfig `json:"rules"`
+	Secrets SecretsConfig         `json:"secrets"`
+}
+
+// SecretsConfig configures the scan of the chart for credentials.
+type SecretsConfig struct {
+	// Allow lists the findings that are not credentials, such as example
+	// keys.
+	Allow []secretscan.Allow `json:"allow"`
+}
+
+// RuleConfig configures a single lint rule.
+type RuleConfig struct {
+	// Disabled stops the rule from reporting anything.
+	Disabled bool `json:"disabled,omitempty"`
+	// Severity replaces the severity of the rule. It is one of "info",
+	// "warning" or "error".
+	Severity string `json:"severity,omitempty"`
+}
+
+// LoadConfig reads the lint configuration of the chart in chartDir. A chart
+// without a configuration file gets an empty configuration.
+func LoadConfig(chartDir string) (*Config, error) {
+	c := &Config{}
+	b, err := ioutil.ReadFile(filepath.Join(chartDir, ConfigFileName))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", ConfigFileName, err)
+	}
+	for id, rc := range c.Rules {
+		if rc.Severity == "" {
+			continue
+		}
+		if _, err := ParseSeverity(rc.Severity); err != nil {
+			return nil, fmt.Errorf("%s: rule %s: %s", ConfigFileName, id, err)
+		}
+	}
+	for _, a := range c.Secrets.Allow {
+		if err := a.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: secrets: %s", ConfigFileName, err)
+		}
+	}
+	return c, nil
+}
+
+// ParseSeverity returns the severity named s.
+func ParseSeverity(s string) (int, error) {
+	for i, name := range sev {
+		if i != UnknownSev && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	return UnknownSev, fmt.Errorf("unknown severity %q, expected info, warning or error", s)
+}
+
+// SeverityName returns the name of a severity, such as "WARNING".
+func SeverityName(severity int) string {
+	if severity < 0 || severity >= len(sev) {
+		return sev[UnknownSev]
+	}
+	return sev[severity]
+}
+
+// severity returns the severity of rule, taking the configuration into
+// account. A disabled rule has no severity.
+func (c *Config) severity(rule Rule) (int, bool) {
+	if c == nil {
+		return rule.Severity, true
+	}
+	rc, ok := c.Rules[rule.ID]
+	if !ok {
+		return rule.Severity, true
+	}
+	if rc.Disabled {
+		return UnknownSev, false
+	}
+	if s, err := ParseSeverity(rc.Severity); err == nil {
+		return s, true
+	}
+	return rule.Severity, true
+}
+
+// suppressRegex matches suppression comments such as
+// "helmlint:disable chart-icon,template-extension".
+var suppressRegex = regexp.MustCompile(`helmlint:disable\s+([A-Za-z0-9_,-]+)`)
+
+// ParseSuppressions returns the IDs of the rules disabled by suppression
+// comments in a file.
+func ParseSuppressions(data []byte) []string {
+	var ids []string
+	for _, m := range suppressRegex.FindAllSubmatch(data, -1) {
+		for _, id := range strings.Split(string(m[1]), ",") {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,32 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package logging provides the leveled, structured logger of Helm and Tiller.
+
+Entries have a level, a message and fields such as the release and the
+namespace an operation is about. They are written as text for people, or as
+lines of JSON for log pipelines:
+
+	2019/05/02 10:15:13 [tiller] INFO upgrade succeeded release=db namespace=prod revision=4
+	{"level":"info","msg":"upgrade succeeded","namespace":"prod","release":"db","revision":4,...}
+
+Much of Helm still logs through functions of the form
+func(format string, args ...interface{}). Logger.Printf fits them, and
+classifies the messages starting with "warning: " or "error: " at these
+levels.
+*/
+package logging // import "k8s.io/helm/pkg/logging"
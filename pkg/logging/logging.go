@@ -0,0 +1,287 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging // import "k8s.io/helm/pkg/logging"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+const (
+	// DebugLevel is for details only useful when investigating a problem.
+	DebugLevel Level = iota
+	// InfoLevel is for the normal operation.
+	InfoLevel
+	// WarnLevel is for problems that do not fail an operation.
+	WarnLevel
+	// ErrorLevel is for failures.
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warning",
+	ErrorLevel: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// ParseLevel parses the name of a level: debug, info, warn, warning or error.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLevel, nil
+	case "info", "":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q, use one of debug, info, warn or error", s)
+}
+
+// Format is how the entries are written.
+type Format string
+
+const (
+	// TextFormat writes entries as lines of text.
+	TextFormat Format = "text"
+	// JSONFormat writes entries as lines of JSON.
+	JSONFormat Format = "json"
+)
+
+// ParseFormat parses the name of a format: text or json.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case TextFormat, JSONFormat:
+		return f, nil
+	case "":
+		return TextFormat, nil
+	}
+	return TextFormat, fmt.Errorf("unknown log format %q, use text or json", s)
+}
+
+// Fields are the key-value pairs attached to log entries.
+type Fields map[string]interface{}
+
+// output is shared by a logger and the loggers derived from it.
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	format Format
+}
+
+// Logger writes leveled, structured log entries. It is safe for concurrent
+// use, and the loggers derived from it with Component and With share its
+// output and level.
+type Logger struct {
+	out       *output
+	component string
+	fields    Fields
+
+	// now returns the time of the entries, it is replaced in tests.
+	now func() time.Time
+}
+
+// New creates a logger writing the entries of the given level and above to w.
+func New(w io.Writer, format Format, level Level) *Logger {
+	return &Logger{
+		out: &output{w: w, level: level, format: format},
+		now: time.Now,
+	}
+}
+
+// SetLevel changes the level of the logger and of the loggers sharing its
+// output.
+func (l *Logger) SetLevel(level Level) {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.level = level
+}
+
+// SetFormat changes the format of the logger and of the loggers sharing its
+// output.
+func (l *Logger) SetFormat(format Format) {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.format = format
+}
+
+// Enabled returns whether the entries of the level are written.
+func (l *Logger) Enabled(level Level) bool {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	return level >= l.out.level
+}
+
+// Component returns a logger for a component of the program, such as
+// "storage", named in each of its entries.
+func (l *Logger) Component(name string) *Logger {
+	c := *l
+	c.component = name
+	return &c
+}
+
+// With returns a logger adding the fields to its entries.
+func (l *Logger) With(fields Fields) *Logger {
+	c := *l
+	c.fields = make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		c.fields[k] = v
+	}
+	for k, v := range fields {
+		c.fields[k] = v
+	}
+	return &c
+}
+
+// Debugf logs a message at the debug level.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(DebugLevel, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a message at the info level.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a message at the warning level.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a message at the error level.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// Fatalf logs a message at the error level and exits.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.Errorf(format, args...)
+	os.Exit(1)
+}
+
+// Printf logs a message at the level named by its prefix, such as
+// "warning: ", or else at the info level.
+func (l *Logger) Printf(format string, args ...interface{}) {
+	level, msg := classify(fmt.Sprintf(format, args...))
+	l.log(level, msg)
+}
+
+// Write logs each line of p like Printf, so that the logger can be the
+// output of a log.Logger.
+func (l *Logger) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		l.Printf("%s", line)
+	}
+	return len(p), nil
+}
+
+var prefixes = []struct {
+	prefix string
+	level  Level
+}{
+	{"debug:", DebugLevel},
+	{"[debug]", DebugLevel},
+	{"info:", InfoLevel},
+	{"warning:", WarnLevel},
+	{"warn:", WarnLevel},
+	{"error:", ErrorLevel},
+}
+
+// classify finds the level a message starts with.
+func classify(msg string) (Level, string) {
+	lower := strings.ToLower(msg)
+	for _, p := range prefixes {
+		if strings.HasPrefix(lower, p.prefix) {
+			return p.level, strings.TrimSpace(msg[len(p.prefix):])
+		}
+	}
+	return InfoLevel, msg
+}
+
+func (l *Logger) log(level Level, msg string) {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	if level < l.out.level {
+		return
+	}
+
+	msg = strings.TrimRight(msg, "\n")
+	t := l.now()
+	var b bytes.Buffer
+	if l.out.format == JSONFormat {
+		entry := make(map[string]interface{}, len(l.fields)+4)
+		for k, v := range l.fields {
+			entry[k] = v
+		}
+		entry["time"] = t.UTC().Format(time.RFC3339Nano)
+		entry["level"] = level.String()
+		entry["msg"] = msg
+		if l.component != "" {
+			entry["component"] = l.component
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			data, _ = json.Marshal(map[string]string{"level": level.String(), "msg": msg, "error": err.Error()})
+		}
+		b.Write(data)
+	} else {
+		b.WriteString(t.Format("2006/01/02 15:04:05 "))
+		if l.component != "" {
+			fmt.Fprintf(&b, "[%s] ", l.component)
+		}
+		fmt.Fprintf(&b, "%s %s", strings.ToUpper(level.String()), msg)
+		keys := make([]string, 0, len(l.fields))
+		for k := range l.fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%s", k, formatValue(l.fields[k]))
+		}
+	}
+	b.WriteByte('\n')
+	l.out.w.Write(b.Bytes())
+}
+
+// formatValue quotes the values of text entries that would be ambiguous.
+func formatValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging // import "k8s.io/helm/pkg/logging"
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"testing"
+	"time"
+)
+
+func testLogger(format Format, level Level) (*Logger, *bytes.Buffer) {
+	var b bytes.Buffer
+	l := New(&b, format, level)
+	l.now = func() time.Time { return time.Date(2019, 5, 2, 10, 15, 13, 0, time.UTC) }
+	return l, &b
+}
+
+func TestText(t *testing.T) {
+	l, b := testLogger(TextFormat, InfoLevel)
+	l.Component("tiller").With(Fields{"release": "db", "namespace": "prod", "chart": "my db"}).Infof("upgrade of %s succeeded", "db")
+	l.Debugf("not written")
+
+	expect := "2019/05/02 10:15:13 [tiller] INFO upgrade of db succeeded chart=\"my db\" namespace=prod release=db\n"
+	if b.String() != expect {
+		t.Errorf("Expected %q, got %q", expect, b.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	l, b := testLogger(JSONFormat, DebugLevel)
+	l.Component("tiller").With(Fields{"release": "db", "revision": 4}).Debugf("rendering")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"time":      "2019-05-02T10:15:13Z",
+		"level":     "debug",
+		"msg":       "rendering",
+		"component": "tiller",
+		"release":   "db",
+		"revision":  float64(4),
+	}
+	for k, v := range expect {
+		if entry[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestPrintf(t *testing.T) {
+	l, b := testLogger(TextFormat, WarnLevel)
+	l.Printf("info: skipping unknown hook")
+	l.Printf("warning: Release %s could not be given an owner", "db")
+
+	// the standard logger can write through a Logger
+	std := log.New(l, "", 0)
+	std.Printf("error: failed deletion of %q", "db")
+
+	expect := "2019/05/02 10:15:13 WARNING Release db could not be given an owner\n" +
+		"2019/05/02 10:15:13 ERROR failed deletion of \"db\"\n"
+	if b.String() != expect {
+		t.Errorf("Expected %q, got %q", expect, b.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, expect := range map[string]Level{"debug": DebugLevel, "": InfoLevel, "WARN": WarnLevel, "warning": WarnLevel, "error": ErrorLevel} {
+		if l, err := ParseLevel(s); err != nil || l != expect {
+			t.Errorf("Expected %q to be %s, got %s (%v)", s, expect, l, err)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an unknown level to be rejected")
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
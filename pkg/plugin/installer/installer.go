@@ -29,6 +29,10 @@ import (
 // ErrMissingMetadata indicates that plugin.yaml is missing.
 var ErrMissingMetadata = errors.New("plugin metadata (plugin.yaml) missing")
 
+// ErrUnknownSource indicates that the source of an installed plugin cannot be
+// found, so the plugin cannot be updated.
+var ErrUnknownSource = errors.New("cannot get information about plugin source")
+
 // Debug enables verbose output.
 var Debug bool
 
@@ -79,7 +83,7 @@ func NewForSource(source, version string, home helmpath.Home) (Installer, error)
 func FindSource(location string, home helmpath.Home) (Installer, error) {
 	installer, err := existingVCSRepo(location, home)
 	if err != nil && err.Error() == "Cannot detect VCS" {
-		return installer, errors.New("cannot get information about plugin source")
+		return installer, ErrUnknownSource
 	}
 	return installer, err
 }
@@ -0,0 +1,152 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin // import "k8s.io/helm/pkg/plugin"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/trace"
+)
+
+// LockFile records where installed plugins came from and what was installed.
+type LockFile struct {
+	Plugins []*LockEntry `json:"plugins"`
+}
+
+// LockEntry describes an installed plugin.
+type LockEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Source is the path or URL the plugin was installed from.
+	Source string `json:"source"`
+	// Digest is the digest of the plugin directory, as returned by Digest.
+	Digest string `json:"digest"`
+}
+
+// LoadLockFile reads a lock file. A missing file is returned as an empty lock
+// file.
+func LoadLockFile(path string) (*LockFile, error) {
+	l := &LockFile{}
+	b, err := trace.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, l); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", path, err)
+	}
+	return l, nil
+}
+
+// Get returns the entry of the named plugin, or nil if there is none.
+func (l *LockFile) Get(name string) *LockEntry {
+	for _, e := range l.Plugins {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// Set adds an entry, replacing any entry of the same plugin.
+func (l *LockFile) Set(entry *LockEntry) {
+	l.Remove(entry.Name)
+	l.Plugins = append(l.Plugins, entry)
+	sort.Slice(l.Plugins, func(i, j int) bool { return l.Plugins[i].Name < l.Plugins[j].Name })
+}
+
+// Remove removes the entry of the named plugin.
+func (l *LockFile) Remove(name string) {
+	for i, e := range l.Plugins {
+		if e.Name == name {
+			l.Plugins = append(l.Plugins[:i], l.Plugins[i+1:]...)
+			return
+		}
+	}
+}
+
+// WriteFile writes the lock file to path.
+func (l *LockFile) WriteFile(path string, perm os.FileMode) error {
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return trace.WriteFile(path, b, perm)
+}
+
+// Digest returns a digest of the files of a plugin directory, in the form
+// "sha256:HEX". VCS metadata is left out, so a plugin gets the same digest
+// however it was installed.
+func Digest(dir string) (string, error) {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	err = filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if fi.Name() == ".git" || fi.Name() == ".hg" || fi.Name() == ".svn" || fi.Name() == ".bzr" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+
+		if fi.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "link:%s\x00", target)
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fh := sha256.New()
+		if _, err := io.Copy(fh, f); err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%x\x00", fh.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin // import "k8s.io/helm/pkg/plugin"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-plugin-lock-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "plugins.lock")
+
+	lock, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatalf("expected a missing lock file to load, got %s", err)
+	}
+	if len(lock.Plugins) != 0 {
+		t.Fatalf("expected an empty lock file, got %v", lock.Plugins)
+	}
+
+	lock.Set(&LockEntry{Name: "hello", Version: "0.1.0", Source: "https://example.com/hello", Digest: "sha256:1"})
+	lock.Set(&LockEntry{Name: "echo", Version: "1.0.0", Source: "/tmp/echo", Digest: "sha256:2"})
+	lock.Set(&LockEntry{Name: "hello", Version: "0.2.0", Source: "https://example.com/hello", Digest: "sha256:3"})
+	if err := lock.WriteFile(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lock, loaded) {
+		t.Errorf("expected %v, got %v", lock, loaded)
+	}
+	if len(loaded.Plugins) != 2 || loaded.Plugins[0].Name != "echo" {
+		t.Errorf("expected entries sorted by name without duplicates, got %v", loaded.Plugins)
+	}
+	if e := loaded.Get("hello"); e == nil || e.Version != "0.2.0" {
+		t.Errorf("expected hello 0.2.0, got %v", e)
+	}
+
+	loaded.Remove("hello")
+	if e := loaded.Get("hello"); e != nil {
+		t.Errorf("expected hello to be removed, got %v", e)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-plugin-digest-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("plugin.yaml", "name: hello\n")
+	write("bin/hello.sh", "echo hello\n")
+
+	first, err := Digest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first, "sha256:") {
+		t.Errorf("expected a sha256 digest, got %s", first)
+	}
+
+	write(".git/HEAD", "ref: refs/heads/master\n")
+	if d, _ := Digest(dir); d != first {
+		t.Errorf("expected VCS metadata to be ignored, got %s and %s", first, d)
+	}
+
+	write("bin/hello.sh", "echo goodbye\n")
+	if d, _ := Digest(dir); d == first {
+		t.Error("expected the digest to change with the plugin files")
+	}
+}
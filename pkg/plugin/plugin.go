@@ -16,13 +16,16 @@ limitations under the License.
 package plugin // import "k8s.io/helm/pkg/plugin"
 
 import (
-	"io/ioutil"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/trace"
+	"k8s.io/helm/pkg/version"
 
+	"github.com/Masterminds/semver"
 	"github.com/ghodss/yaml"
 )
 
@@ -36,6 +39,32 @@ type Downloaders struct {
 	// Command is the executable path with which the plugin performs
 	// the actual download for the corresponding Protocols
 	Command string `json:"command"`
+	// ProtocolVersion selects how Helm talks to Command. Version 1, the
+	// default, runs Command once per download and reads the content from its
+	// standard output. Version 2 keeps Command running and exchanges JSON
+	// messages with it over standard input and output.
+	ProtocolVersion int `json:"protocolVersion,omitempty"`
+}
+
+// LintRule is a lint rule shipped by a plugin.
+type LintRule struct {
+	// ID identifies the rule within the plugin. 'helm lint' reports it as
+	// PLUGIN/ID.
+	ID string `json:"id"`
+	// Severity is one of "info", "warning" or "error". It defaults to
+	// "warning".
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	// Command is run with the chart directory as its last argument. It goes
+	// through environment expansion like the plugin command.
+	Command string `json:"command"`
+}
+
+// Platform is an operating system and architecture a plugin supports, using
+// the GOOS and GOARCH names. An empty field matches any value.
+type Platform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
 }
 
 // Metadata describes a plugin.
@@ -82,6 +111,42 @@ type Metadata struct {
 	// Downloaders field is used if the plugin supply downloader mechanism
 	// for special protocols.
 	Downloaders []Downloaders `json:"downloaders"`
+
+	// LintRules are additional rules run by 'helm lint'.
+	LintRules []LintRule `json:"lintRules,omitempty"`
+
+	// HelmVersion is a SemVer constraint on the versions of Helm the plugin
+	// works with, such as ">=2.14.0".
+	HelmVersion string `json:"helmVersion,omitempty"`
+
+	// Platforms lists the platforms the plugin supports. An empty list
+	// supports every platform.
+	Platforms []Platform `json:"platforms,omitempty"`
+}
+
+// CheckCompatibility returns an error if the plugin does not support the given
+// Helm version or platform.
+//
+// Unreleased builds of Helm satisfy any version constraint.
+func (m *Metadata) CheckCompatibility(helmVersion, goos, goarch string) error {
+	if m.HelmVersion != "" && !strings.HasSuffix(helmVersion, "unreleased") {
+		if _, err := semver.NewConstraint(m.HelmVersion); err != nil {
+			return fmt.Errorf("plugin %q has an invalid helmVersion %q: %s", m.Name, m.HelmVersion, err)
+		}
+		if !version.IsCompatibleRange(m.HelmVersion, helmVersion) {
+			return fmt.Errorf("plugin %q requires Helm %s, but this is Helm %s", m.Name, m.HelmVersion, helmVersion)
+		}
+	}
+
+	if len(m.Platforms) == 0 {
+		return nil
+	}
+	for _, p := range m.Platforms {
+		if (p.OS == "" || p.OS == goos) && (p.Arch == "" || p.Arch == goarch) {
+			return nil
+		}
+	}
+	return fmt.Errorf("plugin %q does not support %s/%s", m.Name, goos, goarch)
 }
 
 // Plugin represents a plugin.
@@ -113,7 +178,7 @@ func (p *Plugin) PrepareCommand(extraArgs []string) (string, []string) {
 
 // LoadDir loads a plugin from the given directory.
 func LoadDir(dirname string) (*Plugin, error) {
-	data, err := ioutil.ReadFile(filepath.Join(dirname, pluginFileName))
+	data, err := trace.ReadFile(filepath.Join(dirname, pluginFileName))
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +255,9 @@ func SetupPluginEnv(settings helm_env.EnvSettings,
 
 		"TILLER_HOST":      settings.TillerHost,
 		"TILLER_NAMESPACE": settings.TillerNamespace,
+
+		"HELM_LOG_FORMAT": settings.LogFormat,
+		"HELM_LOG_LEVEL":  settings.LogLevel,
 	} {
 		os.Setenv(key, val)
 	}
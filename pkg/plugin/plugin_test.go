@@ -151,3 +151,28 @@ func TestLoadAll(t *testing.T) {
 		t.Errorf("Expected second plugin to be hello, got %q", plugs[1].Metadata.Name)
 	}
 }
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		md      Metadata
+		version string
+		err     bool
+	}{
+		{"no constraints", Metadata{}, "v2.14.0", false},
+		{"version matches", Metadata{HelmVersion: ">=2.14.0"}, "v2.14.1", false},
+		{"version too old", Metadata{HelmVersion: ">=2.15.0"}, "v2.14.1", true},
+		{"unreleased build", Metadata{HelmVersion: ">=2.15.0"}, "v2.14+unreleased", false},
+		{"invalid constraint", Metadata{HelmVersion: "two"}, "v2.14.1", true},
+		{"platform matches", Metadata{Platforms: []Platform{{OS: "windows"}, {OS: "linux", Arch: "amd64"}}}, "v2.14.1", false},
+		{"any architecture", Metadata{Platforms: []Platform{{OS: "linux"}}}, "v2.14.1", false},
+		{"platform missing", Metadata{Platforms: []Platform{{OS: "linux", Arch: "arm64"}}}, "v2.14.1", true},
+	}
+	for _, tt := range tests {
+		tt.md.Name = "test"
+		err := tt.md.CheckCompatibility(tt.version, "linux", "amd64")
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error to be %t, got %v", tt.name, tt.err, err)
+		}
+	}
+}
@@ -34,6 +34,8 @@ const (
 	Hook_RELEASE_TEST_SUCCESS Hook_Event = 9
 	Hook_RELEASE_TEST_FAILURE Hook_Event = 10
 	Hook_CRD_INSTALL          Hook_Event = 11
+	Hook_TEST_SETUP           Hook_Event = 12
+	Hook_TEST_TEARDOWN        Hook_Event = 13
 )
 
 var Hook_Event_name = map[int32]string{
@@ -49,6 +51,8 @@ var Hook_Event_name = map[int32]string{
 	9:  "RELEASE_TEST_SUCCESS",
 	10: "RELEASE_TEST_FAILURE",
 	11: "CRD_INSTALL",
+	12: "TEST_SETUP",
+	13: "TEST_TEARDOWN",
 }
 var Hook_Event_value = map[string]int32{
 	"UNKNOWN":              0,
@@ -63,13 +67,15 @@ var Hook_Event_value = map[string]int32{
 	"RELEASE_TEST_SUCCESS": 9,
 	"RELEASE_TEST_FAILURE": 10,
 	"CRD_INSTALL":          11,
+	"TEST_SETUP":           12,
+	"TEST_TEARDOWN":        13,
 }
 
 func (x Hook_Event) String() string {
 	return proto.EnumName(Hook_Event_name, int32(x))
 }
 func (Hook_Event) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_hook_e64400ca8195038e, []int{0, 0}
+	return fileDescriptor_hook_bec6a97bba689566, []int{0, 0}
 }
 
 type Hook_DeletePolicy int32
@@ -95,7 +101,33 @@ func (x Hook_DeletePolicy) String() string {
 	return proto.EnumName(Hook_DeletePolicy_name, int32(x))
 }
 func (Hook_DeletePolicy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_hook_e64400ca8195038e, []int{0, 1}
+	return fileDescriptor_hook_bec6a97bba689566, []int{0, 1}
+}
+
+type Hook_FailurePolicy int32
+
+const (
+	Hook_ABORT    Hook_FailurePolicy = 0
+	Hook_IGNORE   Hook_FailurePolicy = 1
+	Hook_ROLLBACK Hook_FailurePolicy = 2
+)
+
+var Hook_FailurePolicy_name = map[int32]string{
+	0: "ABORT",
+	1: "IGNORE",
+	2: "ROLLBACK",
+}
+var Hook_FailurePolicy_value = map[string]int32{
+	"ABORT":    0,
+	"IGNORE":   1,
+	"ROLLBACK": 2,
+}
+
+func (x Hook_FailurePolicy) String() string {
+	return proto.EnumName(Hook_FailurePolicy_name, int32(x))
+}
+func (Hook_FailurePolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_hook_bec6a97bba689566, []int{0, 2}
 }
 
 // Hook defines a hook object.
@@ -116,7 +148,17 @@ type Hook struct {
 	// DeletePolicies are the policies that indicate when to delete the hook
 	DeletePolicies []Hook_DeletePolicy `protobuf:"varint,8,rep,packed,name=delete_policies,json=deletePolicies,proto3,enum=hapi.release.Hook_DeletePolicy" json:"delete_policies,omitempty"`
 	// DeleteTimeout indicates how long to wait for a resource to be deleted before timing out
-	DeleteTimeout        int64    `protobuf:"varint,9,opt,name=delete_timeout,json=deleteTimeout,proto3" json:"delete_timeout,omitempty"`
+	DeleteTimeout int64 `protobuf:"varint,9,opt,name=delete_timeout,json=deleteTimeout,proto3" json:"delete_timeout,omitempty"`
+	// Timeout is how long the hook may run, in seconds, instead of the timeout of the operation
+	Timeout int64 `protobuf:"varint,10,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Retries is how many times the hook is run again after failing
+	Retries int32 `protobuf:"varint,11,opt,name=retries,proto3" json:"retries,omitempty"`
+	// RetryBackoff is how long to wait, in seconds, before the first retry; it doubles after each one
+	RetryBackoff int64 `protobuf:"varint,12,opt,name=retry_backoff,json=retryBackoff,proto3" json:"retry_backoff,omitempty"`
+	// FailurePolicy is what to do when the hook fails after its retries
+	FailurePolicy Hook_FailurePolicy `protobuf:"varint,13,opt,name=failure_policy,json=failurePolicy,proto3,enum=hapi.release.Hook_FailurePolicy" json:"failure_policy,omitempty"`
+	// Logs are the logs of the pod of the last run of the hook, for Pod and Job hooks
+	Logs                 string   `protobuf:"bytes,14,opt,name=logs,proto3" json:"logs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -126,7 +168,7 @@ func (m *Hook) Reset()         { *m = Hook{} }
 func (m *Hook) String() string { return proto.CompactTextString(m) }
 func (*Hook) ProtoMessage()    {}
 func (*Hook) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hook_e64400ca8195038e, []int{0}
+	return fileDescriptor_hook_bec6a97bba689566, []int{0}
 }
 func (m *Hook) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Hook.Unmarshal(m, b)
@@ -209,44 +251,88 @@ func (m *Hook) GetDeleteTimeout() int64 {
 	return 0
 }
 
+func (m *Hook) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *Hook) GetRetries() int32 {
+	if m != nil {
+		return m.Retries
+	}
+	return 0
+}
+
+func (m *Hook) GetRetryBackoff() int64 {
+	if m != nil {
+		return m.RetryBackoff
+	}
+	return 0
+}
+
+func (m *Hook) GetFailurePolicy() Hook_FailurePolicy {
+	if m != nil {
+		return m.FailurePolicy
+	}
+	return Hook_ABORT
+}
+
+func (m *Hook) GetLogs() string {
+	if m != nil {
+		return m.Logs
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Hook)(nil), "hapi.release.Hook")
 	proto.RegisterEnum("hapi.release.Hook_Event", Hook_Event_name, Hook_Event_value)
 	proto.RegisterEnum("hapi.release.Hook_DeletePolicy", Hook_DeletePolicy_name, Hook_DeletePolicy_value)
+	proto.RegisterEnum("hapi.release.Hook_FailurePolicy", Hook_FailurePolicy_name, Hook_FailurePolicy_value)
 }
 
-func init() { proto.RegisterFile("hapi/release/hook.proto", fileDescriptor_hook_e64400ca8195038e) }
-
-var fileDescriptor_hook_e64400ca8195038e = []byte{
-	// 473 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xdb, 0x8e, 0xda, 0x3c,
-	0x10, 0x80, 0x37, 0x1c, 0x02, 0x0c, 0x87, 0xf5, 0x6f, 0xfd, 0x6a, 0x2d, 0x6e, 0x16, 0x21, 0x55,
-	0xe2, 0x2a, 0x54, 0x5b, 0xf5, 0x01, 0x42, 0xe2, 0x2d, 0x88, 0x88, 0x20, 0x27, 0xa8, 0x52, 0x6f,
-	0xa2, 0x6c, 0xf1, 0x42, 0x44, 0x88, 0x23, 0x62, 0x5a, 0xf5, 0x81, 0xfb, 0x18, 0x95, 0x2a, 0x3b,
-	0x21, 0x5d, 0xa9, 0xbd, 0x9b, 0xf9, 0xe6, 0xf3, 0x78, 0xc6, 0x86, 0xb7, 0xc7, 0x38, 0x4f, 0xe6,
-	0x17, 0x9e, 0xf2, 0xb8, 0xe0, 0xf3, 0xa3, 0x10, 0x27, 0x2b, 0xbf, 0x08, 0x29, 0xf0, 0x40, 0x15,
-	0xac, 0xaa, 0x30, 0x7e, 0x38, 0x08, 0x71, 0x48, 0xf9, 0x5c, 0xd7, 0x9e, 0xaf, 0x2f, 0x73, 0x99,
-	0x9c, 0x79, 0x21, 0xe3, 0x73, 0x5e, 0xea, 0xd3, 0x5f, 0x2d, 0x68, 0x2d, 0x85, 0x38, 0x61, 0x0c,
-	0xad, 0x2c, 0x3e, 0x73, 0x62, 0x4c, 0x8c, 0x59, 0x8f, 0xe9, 0x58, 0xb1, 0x53, 0x92, 0xed, 0x49,
-	0xa3, 0x64, 0x2a, 0x56, 0x2c, 0x8f, 0xe5, 0x91, 0x34, 0x4b, 0xa6, 0x62, 0x3c, 0x86, 0xee, 0x39,
-	0xce, 0x92, 0x17, 0x5e, 0x48, 0xd2, 0xd2, 0xbc, 0xce, 0xf1, 0x7b, 0x30, 0xf9, 0x37, 0x9e, 0xc9,
-	0x82, 0xb4, 0x27, 0xcd, 0xd9, 0xe8, 0x91, 0x58, 0xaf, 0x07, 0xb4, 0xd4, 0xdd, 0x16, 0x55, 0x02,
-	0xab, 0x3c, 0xfc, 0x11, 0xba, 0x69, 0x5c, 0xc8, 0xe8, 0x72, 0xcd, 0x88, 0x39, 0x31, 0x66, 0xfd,
-	0xc7, 0xb1, 0x55, 0xae, 0x61, 0xdd, 0xd6, 0xb0, 0xc2, 0xdb, 0x1a, 0xac, 0xa3, 0x5c, 0x76, 0xcd,
-	0xf0, 0x1b, 0x30, 0xbf, 0xf3, 0xe4, 0x70, 0x94, 0xa4, 0x33, 0x31, 0x66, 0x6d, 0x56, 0x65, 0x78,
-	0x09, 0xf7, 0x7b, 0x9e, 0x72, 0xc9, 0xa3, 0x5c, 0xa4, 0xc9, 0xd7, 0x84, 0x17, 0xa4, 0xab, 0x27,
-	0x79, 0xf8, 0xc7, 0x24, 0xae, 0x36, 0xb7, 0x4a, 0xfc, 0xc1, 0x46, 0xfb, 0x3f, 0x59, 0xc2, 0x0b,
-	0xfc, 0x0e, 0x2a, 0x12, 0xa9, 0x57, 0x14, 0x57, 0x49, 0x7a, 0x13, 0x63, 0xd6, 0x64, 0xc3, 0x92,
-	0x86, 0x25, 0x9c, 0xfe, 0x34, 0xa0, 0xad, 0x37, 0xc2, 0x7d, 0xe8, 0xec, 0x36, 0xeb, 0x8d, 0xff,
-	0x79, 0x83, 0xee, 0xf0, 0x3d, 0xf4, 0xb7, 0x8c, 0x46, 0xab, 0x4d, 0x10, 0xda, 0x9e, 0x87, 0x0c,
-	0x8c, 0x60, 0xb0, 0xf5, 0x83, 0xb0, 0x26, 0x0d, 0x3c, 0x02, 0x50, 0x8a, 0x4b, 0x3d, 0x1a, 0x52,
-	0xd4, 0xd4, 0x47, 0x94, 0x51, 0x81, 0xd6, 0xad, 0xc7, 0x6e, 0xfb, 0x89, 0xd9, 0x2e, 0x45, 0xed,
-	0xba, 0xc7, 0x8d, 0x98, 0x9a, 0x30, 0x1a, 0x31, 0xdf, 0xf3, 0x16, 0xb6, 0xb3, 0x46, 0x1d, 0xfc,
-	0x1f, 0x0c, 0xb5, 0x53, 0xa3, 0x2e, 0x26, 0xf0, 0x3f, 0xa3, 0x1e, 0xb5, 0x03, 0x1a, 0x85, 0x34,
-	0x08, 0xa3, 0x60, 0xe7, 0x38, 0x34, 0x08, 0x50, 0xef, 0xaf, 0xca, 0x93, 0xbd, 0xf2, 0x76, 0x8c,
-	0x22, 0x50, 0x77, 0x3b, 0xcc, 0xad, 0xa7, 0xed, 0x4f, 0x1d, 0x18, 0xbc, 0x7e, 0x2e, 0x3c, 0x84,
-	0x9e, 0xee, 0x43, 0x5d, 0xea, 0xa2, 0x3b, 0x0c, 0x60, 0xaa, 0xc3, 0xd4, 0x45, 0x86, 0xea, 0xba,
-	0xa0, 0x4f, 0x3e, 0xa3, 0xd1, 0xd2, 0xf7, 0xd7, 0x91, 0xc3, 0xa8, 0x1d, 0xae, 0xfc, 0x0d, 0x6a,
-	0x2c, 0x7a, 0x5f, 0x3a, 0xd5, 0x07, 0x3c, 0x9b, 0xfa, 0x77, 0x3f, 0xfc, 0x0e, 0x00, 0x00, 0xff,
-	0xff, 0xce, 0x84, 0xd9, 0x98, 0xdb, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/release/hook.proto", fileDescriptor_hook_bec6a97bba689566) }
+
+var fileDescriptor_hook_bec6a97bba689566 = []byte{
+	// 594 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0x6f, 0xda, 0x4c,
+	0x10, 0xc6, 0x63, 0xfe, 0x19, 0x06, 0x9b, 0xec, 0xbb, 0x7a, 0xd5, 0xae, 0x72, 0x89, 0x45, 0x55,
+	0x89, 0x93, 0xa9, 0xd2, 0xf6, 0x03, 0x18, 0xbc, 0x49, 0x50, 0x2c, 0x8c, 0xd6, 0x46, 0x95, 0x7a,
+	0xb1, 0x9c, 0x64, 0x01, 0x0b, 0xc3, 0x22, 0xbc, 0xb4, 0xca, 0x37, 0xe8, 0xa1, 0x1f, 0xba, 0xda,
+	0xb5, 0x21, 0x54, 0xcd, 0x6d, 0xe6, 0x37, 0xcf, 0xce, 0xcc, 0xe3, 0x31, 0xbc, 0x5f, 0xa5, 0xbb,
+	0x6c, 0xb8, 0xe7, 0x39, 0x4f, 0x0b, 0x3e, 0x5c, 0x09, 0xb1, 0x76, 0x77, 0x7b, 0x21, 0x05, 0xb6,
+	0x54, 0xc1, 0xad, 0x0a, 0x57, 0xd7, 0x4b, 0x21, 0x96, 0x39, 0x1f, 0xea, 0xda, 0xe3, 0x61, 0x31,
+	0x94, 0xd9, 0x86, 0x17, 0x32, 0xdd, 0xec, 0x4a, 0x79, 0xff, 0xb7, 0x09, 0x8d, 0x7b, 0x21, 0xd6,
+	0x18, 0x43, 0x63, 0x9b, 0x6e, 0x38, 0x31, 0x1c, 0x63, 0xd0, 0x61, 0x3a, 0x56, 0x6c, 0x9d, 0x6d,
+	0x9f, 0x49, 0xad, 0x64, 0x2a, 0x56, 0x6c, 0x97, 0xca, 0x15, 0xa9, 0x97, 0x4c, 0xc5, 0xf8, 0x0a,
+	0xda, 0x9b, 0x74, 0x9b, 0x2d, 0x78, 0x21, 0x49, 0x43, 0xf3, 0x53, 0x8e, 0x3f, 0x41, 0x8b, 0xff,
+	0xe0, 0x5b, 0x59, 0x90, 0xa6, 0x53, 0x1f, 0xf4, 0x6e, 0x88, 0x7b, 0xbe, 0xa0, 0xab, 0x66, 0xbb,
+	0x54, 0x09, 0x58, 0xa5, 0xc3, 0x5f, 0xa1, 0x9d, 0xa7, 0x85, 0x4c, 0xf6, 0x87, 0x2d, 0x69, 0x39,
+	0xc6, 0xa0, 0x7b, 0x73, 0xe5, 0x96, 0x36, 0xdc, 0xa3, 0x0d, 0x37, 0x3e, 0xda, 0x60, 0xa6, 0xd2,
+	0xb2, 0xc3, 0x16, 0xbf, 0x83, 0xd6, 0x4f, 0x9e, 0x2d, 0x57, 0x92, 0x98, 0x8e, 0x31, 0x68, 0xb2,
+	0x2a, 0xc3, 0xf7, 0x70, 0xf9, 0xcc, 0x73, 0x2e, 0x79, 0xb2, 0x13, 0x79, 0xf6, 0x94, 0xf1, 0x82,
+	0xb4, 0xf5, 0x26, 0xd7, 0x6f, 0x6c, 0xe2, 0x6b, 0xe5, 0x4c, 0x09, 0x5f, 0x58, 0xef, 0xf9, 0x35,
+	0xcb, 0x78, 0x81, 0x3f, 0x42, 0x45, 0x12, 0xf5, 0x15, 0xc5, 0x41, 0x92, 0x8e, 0x63, 0x0c, 0xea,
+	0xcc, 0x2e, 0x69, 0x5c, 0x42, 0x4c, 0xc0, 0x3c, 0xd6, 0x41, 0xd7, 0x4d, 0xf9, 0x5a, 0xd9, 0x73,
+	0xb9, 0x57, 0x2b, 0x74, 0xf5, 0x8e, 0xc7, 0x14, 0x7f, 0x00, 0x5b, 0x85, 0x2f, 0xc9, 0x63, 0xfa,
+	0xb4, 0x16, 0x8b, 0x05, 0xb1, 0xf4, 0x4b, 0x4b, 0xc3, 0x51, 0xc9, 0xf0, 0x1d, 0xf4, 0x16, 0x69,
+	0x96, 0x1f, 0xf6, 0x95, 0x95, 0x17, 0x62, 0x3b, 0xc6, 0xa0, 0x77, 0xe3, 0xbc, 0x61, 0xe4, 0xb6,
+	0x14, 0x56, 0x4e, 0xec, 0xc5, 0x79, 0xaa, 0x6e, 0x98, 0x8b, 0x65, 0x41, 0x7a, 0xe5, 0x0d, 0x55,
+	0xdc, 0xff, 0x55, 0x83, 0xa6, 0xbe, 0x03, 0xee, 0x82, 0x39, 0x9f, 0x3e, 0x4c, 0xc3, 0x6f, 0x53,
+	0x74, 0x81, 0x2f, 0xa1, 0x3b, 0x63, 0x34, 0x99, 0x4c, 0xa3, 0xd8, 0x0b, 0x02, 0x64, 0x60, 0x04,
+	0xd6, 0x2c, 0x8c, 0xe2, 0x13, 0xa9, 0xe1, 0x1e, 0x80, 0x92, 0xf8, 0x34, 0xa0, 0x31, 0x45, 0x75,
+	0xfd, 0x44, 0x29, 0x2a, 0xd0, 0x38, 0xf6, 0x98, 0xcf, 0xee, 0x98, 0xe7, 0x53, 0xd4, 0x3c, 0xf5,
+	0x38, 0x92, 0x96, 0x26, 0x8c, 0x26, 0x2c, 0x0c, 0x82, 0x91, 0x37, 0x7e, 0x40, 0x26, 0xfe, 0x0f,
+	0x6c, 0xad, 0x39, 0xa1, 0x36, 0x26, 0xf0, 0x3f, 0xa3, 0x01, 0xf5, 0x22, 0x9a, 0xc4, 0x34, 0x8a,
+	0x93, 0x68, 0x3e, 0x1e, 0xd3, 0x28, 0x42, 0x9d, 0x7f, 0x2a, 0xb7, 0xde, 0x24, 0x98, 0x33, 0x8a,
+	0x40, 0xcd, 0x1e, 0x33, 0xff, 0xb4, 0x6d, 0x57, 0x6d, 0x5b, 0x3e, 0xa6, 0xf1, 0x7c, 0x86, 0x2c,
+	0x35, 0x47, 0xe7, 0x31, 0xf5, 0x98, 0xaf, 0x3c, 0xdb, 0xfd, 0x31, 0x58, 0xe7, 0xff, 0x01, 0xb6,
+	0xa1, 0xa3, 0x47, 0x51, 0x9f, 0xfa, 0xe8, 0x02, 0x03, 0xb4, 0x54, 0x7f, 0xea, 0x23, 0x43, 0x0d,
+	0x1e, 0xd1, 0xdb, 0x90, 0xd1, 0xe4, 0x3e, 0x0c, 0x1f, 0x92, 0x31, 0xa3, 0x5e, 0x3c, 0x09, 0xa7,
+	0xa8, 0xd6, 0xff, 0x02, 0xf6, 0x5f, 0x37, 0xc0, 0x1d, 0x68, 0x7a, 0xa3, 0x90, 0xc5, 0x65, 0x87,
+	0xc9, 0xdd, 0x34, 0x64, 0x14, 0x19, 0xd8, 0x82, 0xf6, 0xc9, 0x62, 0x6d, 0xd4, 0xf9, 0x6e, 0x56,
+	0x67, 0x7c, 0x6c, 0xe9, 0x9f, 0xfd, 0xf3, 0x9f, 0x01, 0x00, 0x91, 0x90, 0x6c, 0xda, 0xea, 0x03,
+	0x00, 0x00,
 }
@@ -27,7 +27,25 @@ type Info struct {
 	// Deleted tracks when this object was deleted.
 	Deleted *timestamp.Timestamp `protobuf:"bytes,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	// Description is human-friendly "log entry" about this release.
-	Description          string   `protobuf:"bytes,5,opt,name=Description,proto3" json:"Description,omitempty"`
+	Description string `protobuf:"bytes,5,opt,name=Description,proto3" json:"Description,omitempty"`
+	// Readiness records the readiness of each resource observed while waiting.
+	Readiness []*ResourceReadiness `protobuf:"bytes,6,rep,name=readiness,proto3" json:"readiness,omitempty"`
+	// Partial lists the selectors of a partial upgrade. Only the resources
+	// matching them were applied in this revision.
+	Partial []string `protobuf:"bytes,7,rep,name=partial,proto3" json:"partial,omitempty"`
+	// FailurePhase is the phase of the operation that made a FAILED release
+	// fail: hooks, apply or wait.
+	FailurePhase string `protobuf:"bytes,8,opt,name=failure_phase,json=failurePhase,proto3" json:"failure_phase,omitempty"`
+	// LastError is the error that made a FAILED release fail.
+	LastError string `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// ServiceAccount is the service account, in the namespace of the release,
+	// that Tiller impersonates to apply the release.
+	ServiceAccount string `protobuf:"bytes,10,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// User is the Kubernetes user whose request Tiller applied this release
+	// as, when Tiller impersonates its users.
+	User string `protobuf:"bytes,11,opt,name=user,proto3" json:"user,omitempty"`
+	// UserGroups are the groups of User.
+	UserGroups           []string `protobuf:"bytes,12,rep,name=user_groups,json=userGroups,proto3" json:"user_groups,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -37,7 +55,7 @@ func (m *Info) Reset()         { *m = Info{} }
 func (m *Info) String() string { return proto.CompactTextString(m) }
 func (*Info) ProtoMessage()    {}
 func (*Info) Descriptor() ([]byte, []int) {
-	return fileDescriptor_info_1c62b71ed76c67c1, []int{0}
+	return fileDescriptor_info_d2e6299cd1059a94, []int{0}
 }
 func (m *Info) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Info.Unmarshal(m, b)
@@ -92,27 +110,170 @@ func (m *Info) GetDescription() string {
 	return ""
 }
 
+func (m *Info) GetReadiness() []*ResourceReadiness {
+	if m != nil {
+		return m.Readiness
+	}
+	return nil
+}
+
+func (m *Info) GetPartial() []string {
+	if m != nil {
+		return m.Partial
+	}
+	return nil
+}
+
+func (m *Info) GetFailurePhase() string {
+	if m != nil {
+		return m.FailurePhase
+	}
+	return ""
+}
+
+func (m *Info) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *Info) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+func (m *Info) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Info) GetUserGroups() []string {
+	if m != nil {
+		return m.UserGroups
+	}
+	return nil
+}
+
+// ResourceReadiness reports the readiness of a single resource while waiting.
+type ResourceReadiness struct {
+	Kind      string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name      string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Ready     bool   `protobuf:"varint,4,opt,name=ready,proto3" json:"ready,omitempty"`
+	// Message is a human-friendly description of the resource state.
+	Message              string               `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ResourceReadiness) Reset()         { *m = ResourceReadiness{} }
+func (m *ResourceReadiness) String() string { return proto.CompactTextString(m) }
+func (*ResourceReadiness) ProtoMessage()    {}
+func (*ResourceReadiness) Descriptor() ([]byte, []int) {
+	return fileDescriptor_info_d2e6299cd1059a94, []int{1}
+}
+func (m *ResourceReadiness) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceReadiness.Unmarshal(m, b)
+}
+func (m *ResourceReadiness) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceReadiness.Marshal(b, m, deterministic)
+}
+func (dst *ResourceReadiness) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceReadiness.Merge(dst, src)
+}
+func (m *ResourceReadiness) XXX_Size() int {
+	return xxx_messageInfo_ResourceReadiness.Size(m)
+}
+func (m *ResourceReadiness) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceReadiness.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceReadiness proto.InternalMessageInfo
+
+func (m *ResourceReadiness) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ResourceReadiness) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ResourceReadiness) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResourceReadiness) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *ResourceReadiness) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ResourceReadiness) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Info)(nil), "hapi.release.Info")
+	proto.RegisterType((*ResourceReadiness)(nil), "hapi.release.ResourceReadiness")
 }
 
-func init() { proto.RegisterFile("hapi/release/info.proto", fileDescriptor_info_1c62b71ed76c67c1) }
-
-var fileDescriptor_info_1c62b71ed76c67c1 = []byte{
-	// 235 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x8f, 0x31, 0x4f, 0xc3, 0x30,
-	0x10, 0x85, 0x95, 0x52, 0x5a, 0xd5, 0x6d, 0x19, 0x2c, 0x24, 0x42, 0x16, 0x22, 0xa6, 0x0e, 0xc8,
-	0x91, 0x80, 0x1d, 0x81, 0xba, 0xb0, 0x06, 0x26, 0x16, 0xe4, 0xe2, 0x73, 0xb1, 0xe4, 0xe6, 0x2c,
-	0xfb, 0x3a, 0xf0, 0x2f, 0xf8, 0xc9, 0xa8, 0xb6, 0x83, 0xd2, 0xa9, 0xab, 0xbf, 0xf7, 0x3e, 0xbf,
-	0x63, 0x57, 0xdf, 0xd2, 0x99, 0xc6, 0x83, 0x05, 0x19, 0xa0, 0x31, 0x9d, 0x46, 0xe1, 0x3c, 0x12,
-	0xf2, 0xc5, 0x01, 0x88, 0x0c, 0xaa, 0x9b, 0x2d, 0xe2, 0xd6, 0x42, 0x13, 0xd9, 0x66, 0xaf, 0x1b,
-	0x32, 0x3b, 0x08, 0x24, 0x77, 0x2e, 0xc5, 0xab, 0xeb, 0x23, 0x4f, 0x20, 0x49, 0xfb, 0x90, 0xd0,
-	0xed, 0xef, 0x88, 0x8d, 0x5f, 0x3b, 0x8d, 0xfc, 0x8e, 0x4d, 0x12, 0x28, 0x8b, 0xba, 0x58, 0xcd,
-	0xef, 0x2f, 0xc5, 0xf0, 0x0f, 0xf1, 0x16, 0x59, 0x9b, 0x33, 0xfc, 0x99, 0x5d, 0x68, 0xe3, 0x03,
-	0x7d, 0x2a, 0x70, 0x16, 0x7f, 0x40, 0x95, 0xa3, 0xd8, 0xaa, 0x44, 0xda, 0x22, 0xfa, 0x2d, 0xe2,
-	0xbd, 0xdf, 0xd2, 0x2e, 0x63, 0x63, 0x9d, 0x0b, 0xfc, 0x89, 0x2d, 0xad, 0x1c, 0x1a, 0xce, 0x4e,
-	0x1a, 0x16, 0x87, 0xc2, 0xbf, 0xe0, 0x91, 0x4d, 0x15, 0x58, 0x20, 0x50, 0xe5, 0xf8, 0x64, 0xb5,
-	0x8f, 0xf2, 0x9a, 0xcd, 0xd7, 0x10, 0xbe, 0xbc, 0x71, 0x64, 0xb0, 0x2b, 0xcf, 0xeb, 0x62, 0x35,
-	0x6b, 0x87, 0x4f, 0x2f, 0xb3, 0x8f, 0x69, 0xbe, 0x7a, 0x33, 0x89, 0xa6, 0x87, 0xbf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x1a, 0x52, 0x8f, 0x9c, 0x89, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/release/info.proto", fileDescriptor_info_d2e6299cd1059a94) }
+
+var fileDescriptor_info_d2e6299cd1059a94 = []byte{
+	// 440 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0xcd, 0x8e, 0xd3, 0x30,
+	0x10, 0x56, 0x69, 0xb6, 0x5d, 0x4f, 0xda, 0x45, 0x58, 0x2b, 0x61, 0x2a, 0x50, 0xa3, 0xe5, 0x40,
+	0x0f, 0x28, 0x95, 0x16, 0xae, 0x08, 0x2d, 0x5a, 0x84, 0xb8, 0x21, 0xc3, 0x89, 0x4b, 0xe5, 0x4d,
+	0x26, 0x5d, 0x8b, 0x34, 0xb6, 0x6c, 0x07, 0x69, 0x9f, 0x81, 0x27, 0xe2, 0xed, 0x90, 0x27, 0x89,
+	0x36, 0x15, 0x87, 0x9e, 0xe2, 0xf9, 0x7e, 0xc6, 0x93, 0xf9, 0x0c, 0xcf, 0xef, 0x95, 0xd5, 0x5b,
+	0x87, 0x35, 0x2a, 0x8f, 0x5b, 0xdd, 0x54, 0x26, 0xb7, 0xce, 0x04, 0xc3, 0x17, 0x91, 0xc8, 0x7b,
+	0x62, 0xb5, 0xde, 0x1b, 0xb3, 0xaf, 0x71, 0x4b, 0xdc, 0x5d, 0x5b, 0x6d, 0x83, 0x3e, 0xa0, 0x0f,
+	0xea, 0x60, 0x3b, 0xf9, 0xea, 0xc5, 0x51, 0x1f, 0x1f, 0x54, 0x68, 0x7d, 0x47, 0x5d, 0xfd, 0x49,
+	0x20, 0xf9, 0xda, 0x54, 0x86, 0xbf, 0x85, 0x59, 0x47, 0x88, 0x49, 0x36, 0xd9, 0xa4, 0xd7, 0x97,
+	0xf9, 0xf8, 0x8e, 0xfc, 0x3b, 0x71, 0xb2, 0xd7, 0xf0, 0x1b, 0xb8, 0xa8, 0xb4, 0xf3, 0x61, 0x57,
+	0xa2, 0xad, 0xcd, 0x03, 0x96, 0xe2, 0x09, 0xb9, 0x56, 0x79, 0x37, 0x4b, 0x3e, 0xcc, 0x92, 0xff,
+	0x18, 0x66, 0x91, 0x4b, 0x72, 0xdc, 0xf6, 0x06, 0xfe, 0x11, 0x96, 0xb5, 0x1a, 0x77, 0x98, 0x9e,
+	0xec, 0xb0, 0xa8, 0xd5, 0xa8, 0xc1, 0x7b, 0x98, 0x97, 0x58, 0x63, 0xc0, 0x52, 0x24, 0x27, 0xad,
+	0x83, 0x94, 0x67, 0x90, 0xde, 0xa2, 0x2f, 0x9c, 0xb6, 0x41, 0x9b, 0x46, 0x9c, 0x65, 0x93, 0x0d,
+	0x93, 0x63, 0x88, 0x7f, 0x00, 0xe6, 0x50, 0x95, 0xba, 0x41, 0xef, 0xc5, 0x2c, 0x9b, 0x6e, 0xd2,
+	0xeb, 0xf5, 0xf1, 0x32, 0x24, 0x7a, 0xd3, 0xba, 0x02, 0xe5, 0x20, 0x93, 0x8f, 0x0e, 0x2e, 0x60,
+	0x6e, 0x95, 0x0b, 0x5a, 0xd5, 0x62, 0x9e, 0x4d, 0x37, 0x4c, 0x0e, 0x25, 0x7f, 0x0d, 0xcb, 0x4a,
+	0xe9, 0xba, 0x75, 0xb8, 0xb3, 0xf7, 0xca, 0xa3, 0x38, 0xa7, 0xcb, 0x17, 0x3d, 0xf8, 0x2d, 0x62,
+	0xfc, 0x15, 0x00, 0xad, 0x05, 0x9d, 0x33, 0x4e, 0x30, 0x52, 0xb0, 0x88, 0x7c, 0x8e, 0x00, 0x7f,
+	0x03, 0x4f, 0x3d, 0xba, 0xdf, 0xba, 0xc0, 0x9d, 0x2a, 0x0a, 0xd3, 0x36, 0x41, 0x00, 0x69, 0x2e,
+	0x7a, 0xf8, 0xa6, 0x43, 0x39, 0x87, 0xa4, 0xf5, 0xe8, 0x44, 0x4a, 0x2c, 0x9d, 0xf9, 0x1a, 0xd2,
+	0xf8, 0xdd, 0xed, 0x9d, 0x69, 0xad, 0x17, 0x0b, 0x1a, 0x0f, 0x22, 0xf4, 0x85, 0x90, 0xab, 0xbf,
+	0x13, 0x78, 0xf6, 0xdf, 0xcf, 0xc5, 0x56, 0xbf, 0x74, 0x53, 0xd2, 0xc3, 0x60, 0x92, 0xce, 0xfc,
+	0x25, 0xb0, 0x46, 0x1d, 0xd0, 0x5b, 0x55, 0x20, 0x65, 0xcf, 0xe4, 0x23, 0x10, 0x1d, 0xb1, 0xa0,
+	0x48, 0x99, 0xa4, 0x33, 0xbf, 0x84, 0xb3, 0xb8, 0xa4, 0x07, 0x0a, 0xeb, 0x5c, 0x76, 0x45, 0xdc,
+	0xd6, 0x01, 0xbd, 0x57, 0x7b, 0xec, 0xa3, 0x18, 0x4a, 0x9e, 0x43, 0x12, 0xdf, 0xb1, 0x98, 0x9d,
+	0xcc, 0x96, 0x74, 0x9f, 0xd8, 0xcf, 0x79, 0x9f, 0xcf, 0xdd, 0x8c, 0x44, 0xef, 0xfe, 0x0d, 0x00,
+	0x88, 0x88, 0xd1, 0x5c, 0x40, 0x03, 0x00, 0x00,
 }
@@ -69,7 +69,7 @@ func (x Status_Code) String() string {
 	return proto.EnumName(Status_Code_name, int32(x))
 }
 func (Status_Code) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_status_933517e5a50981ed, []int{0, 0}
+	return fileDescriptor_status_6062ada3388f25d3, []int{0, 0}
 }
 
 // Status defines the status of a release.
@@ -80,17 +80,19 @@ type Status struct {
 	// Contains the rendered templates/NOTES.txt if available
 	Notes string `protobuf:"bytes,4,opt,name=notes,proto3" json:"notes,omitempty"`
 	// LastTestSuiteRun provides results on the last test run on a release
-	LastTestSuiteRun     *TestSuite `protobuf:"bytes,5,opt,name=last_test_suite_run,json=lastTestSuiteRun,proto3" json:"last_test_suite_run,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	LastTestSuiteRun *TestSuite `protobuf:"bytes,5,opt,name=last_test_suite_run,json=lastTestSuiteRun,proto3" json:"last_test_suite_run,omitempty"`
+	// Contains the rendered templates/outputs.yaml if available
+	Outputs              string   `protobuf:"bytes,6,opt,name=outputs,proto3" json:"outputs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Status) Reset()         { *m = Status{} }
 func (m *Status) String() string { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()    {}
 func (*Status) Descriptor() ([]byte, []int) {
-	return fileDescriptor_status_933517e5a50981ed, []int{0}
+	return fileDescriptor_status_6062ada3388f25d3, []int{0}
 }
 func (m *Status) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Status.Unmarshal(m, b)
@@ -138,34 +140,42 @@ func (m *Status) GetLastTestSuiteRun() *TestSuite {
 	return nil
 }
 
+func (m *Status) GetOutputs() string {
+	if m != nil {
+		return m.Outputs
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Status)(nil), "hapi.release.Status")
 	proto.RegisterEnum("hapi.release.Status_Code", Status_Code_name, Status_Code_value)
 }
 
-func init() { proto.RegisterFile("hapi/release/status.proto", fileDescriptor_status_933517e5a50981ed) }
-
-var fileDescriptor_status_933517e5a50981ed = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xd1, 0x6e, 0xa2, 0x40,
-	0x14, 0x86, 0x17, 0x45, 0xd4, 0xa3, 0x71, 0x27, 0xa3, 0xc9, 0xa2, 0xd9, 0x4d, 0x8c, 0x57, 0xde,
-	0x2c, 0x24, 0xf6, 0x09, 0xd0, 0x19, 0x0d, 0x71, 0x82, 0x04, 0x30, 0x4d, 0x7b, 0x43, 0x50, 0xa7,
-	0xd6, 0xc4, 0x30, 0x86, 0x19, 0x2e, 0xfa, 0x26, 0x7d, 0xaa, 0x3e, 0x53, 0x03, 0xd8, 0xa8, 0x97,
-	0xff, 0xff, 0x7d, 0x87, 0x73, 0x18, 0x18, 0xbe, 0x27, 0x97, 0x93, 0x9d, 0xf1, 0x33, 0x4f, 0x24,
-	0xb7, 0xa5, 0x4a, 0x54, 0x2e, 0xad, 0x4b, 0x26, 0x94, 0xc0, 0xdd, 0x02, 0x59, 0x57, 0x34, 0xfa,
-	0xf7, 0x20, 0x2a, 0x2e, 0x55, 0x2c, 0xf3, 0x93, 0xe2, 0x95, 0x3c, 0x1a, 0x1e, 0x85, 0x38, 0x9e,
-	0xb9, 0x5d, 0xa6, 0x5d, 0xfe, 0x66, 0x27, 0xe9, 0x47, 0x85, 0x26, 0x5f, 0x35, 0x30, 0xc2, 0xf2,
-	0xc3, 0xf8, 0x3f, 0xe8, 0x7b, 0x71, 0xe0, 0xa6, 0x36, 0xd6, 0xa6, 0xbd, 0xd9, 0xd0, 0xba, 0xdf,
-	0x60, 0x55, 0x8e, 0xb5, 0x10, 0x07, 0x1e, 0x94, 0x1a, 0xfe, 0x0b, 0xed, 0x8c, 0x4b, 0x91, 0x67,
-	0x7b, 0x2e, 0xcd, 0xfa, 0x58, 0x9b, 0xb6, 0x83, 0x5b, 0x81, 0x07, 0xd0, 0x48, 0x85, 0xe2, 0xd2,
-	0xd4, 0x4b, 0x52, 0x05, 0xbc, 0x84, 0xfe, 0x39, 0x91, 0x2a, 0xbe, 0x5d, 0x18, 0x67, 0x79, 0x6a,
-	0x36, 0xc6, 0xda, 0xb4, 0x33, 0xfb, 0xf3, 0xb8, 0x31, 0xe2, 0x52, 0x85, 0x85, 0x12, 0xa0, 0x62,
-	0xe6, 0x16, 0xf3, 0x74, 0xf2, 0xa9, 0x81, 0x5e, 0x9c, 0x82, 0x3b, 0xd0, 0xdc, 0x7a, 0x6b, 0x6f,
-	0xf3, 0xec, 0xa1, 0x5f, 0xb8, 0x0b, 0x2d, 0x42, 0x7d, 0xb6, 0x79, 0xa1, 0x04, 0x69, 0x05, 0x22,
-	0x94, 0xd1, 0x88, 0x12, 0x54, 0xc3, 0x3d, 0x80, 0x70, 0xeb, 0xd3, 0x20, 0xa4, 0x84, 0x12, 0x54,
-	0xc7, 0x00, 0xc6, 0xd2, 0x71, 0x19, 0x25, 0x48, 0xaf, 0xc6, 0x18, 0x8d, 0x5c, 0x6f, 0x85, 0x1a,
-	0xb8, 0x0f, 0xbf, 0x7d, 0xea, 0x11, 0xd7, 0x5b, 0xc5, 0xae, 0x17, 0x46, 0x0e, 0x63, 0xc8, 0xb8,
-	0x2f, 0xb7, 0xfe, 0x2a, 0x70, 0x08, 0x45, 0x4d, 0x3c, 0x00, 0xf4, 0x53, 0x06, 0x1b, 0xc6, 0xe6,
-	0xce, 0x62, 0x8d, 0x5a, 0xf3, 0xf6, 0x6b, 0xf3, 0xfa, 0x07, 0x3b, 0xa3, 0x7c, 0xe2, 0xa7, 0xef,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x09, 0x48, 0x18, 0xba, 0xc7, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/release/status.proto", fileDescriptor_status_6062ada3388f25d3) }
+
+var fileDescriptor_status_6062ada3388f25d3 = []byte{
+	// 341 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xdf, 0xca, 0xda, 0x30,
+	0x18, 0x87, 0xd7, 0xef, 0xab, 0xad, 0xbe, 0x8a, 0x0b, 0x51, 0x58, 0x95, 0x0d, 0xc4, 0x23, 0x4f,
+	0xd6, 0x82, 0xbb, 0x82, 0x6a, 0xa2, 0x14, 0x43, 0x2d, 0x6d, 0x65, 0x6c, 0x27, 0xa5, 0x6a, 0xe6,
+	0x04, 0x69, 0xa4, 0x49, 0x0e, 0x76, 0x27, 0xbb, 0xc4, 0x5d, 0xc6, 0x68, 0xab, 0xa8, 0x87, 0xbf,
+	0xf7, 0x79, 0xde, 0x3f, 0x09, 0x8c, 0x7e, 0xe7, 0xd7, 0xb3, 0x57, 0xf2, 0x0b, 0xcf, 0x25, 0xf7,
+	0xa4, 0xca, 0x95, 0x96, 0xee, 0xb5, 0x14, 0x4a, 0xe0, 0x5e, 0x85, 0xdc, 0x1b, 0x1a, 0x7f, 0x79,
+	0x11, 0x15, 0x97, 0x2a, 0x93, 0xfa, 0xac, 0x78, 0x23, 0x8f, 0x47, 0x27, 0x21, 0x4e, 0x17, 0xee,
+	0xd5, 0x69, 0xaf, 0x7f, 0x79, 0x79, 0xf1, 0xa7, 0x41, 0xd3, 0x7f, 0x6f, 0x60, 0x25, 0xf5, 0x60,
+	0xfc, 0x15, 0xcc, 0x83, 0x38, 0x72, 0xc7, 0x98, 0x18, 0xb3, 0xfe, 0x7c, 0xe4, 0x3e, 0x6f, 0x70,
+	0x1b, 0xc7, 0x5d, 0x8a, 0x23, 0x8f, 0x6b, 0x0d, 0x7f, 0x86, 0x4e, 0xc9, 0xa5, 0xd0, 0xe5, 0x81,
+	0x4b, 0xe7, 0x7d, 0x62, 0xcc, 0x3a, 0xf1, 0xa3, 0x80, 0x87, 0xd0, 0x2a, 0x84, 0xe2, 0xd2, 0x31,
+	0x6b, 0xd2, 0x04, 0xbc, 0x82, 0xc1, 0x25, 0x97, 0x2a, 0x7b, 0x5c, 0x98, 0x95, 0xba, 0x70, 0x5a,
+	0x13, 0x63, 0xd6, 0x9d, 0x7f, 0x7a, 0xdd, 0x98, 0x72, 0xa9, 0x92, 0x4a, 0x89, 0x51, 0xd5, 0xf3,
+	0x88, 0xba, 0xc0, 0x0e, 0xd8, 0x42, 0xab, 0xab, 0x56, 0xd2, 0xb1, 0xea, 0xf9, 0xf7, 0x38, 0xfd,
+	0x6b, 0x80, 0x59, 0x1d, 0x89, 0xbb, 0x60, 0xef, 0xc2, 0x4d, 0xb8, 0xfd, 0x1e, 0xa2, 0x0f, 0xb8,
+	0x07, 0x6d, 0x42, 0x23, 0xb6, 0xfd, 0x41, 0x09, 0x32, 0x2a, 0x44, 0x28, 0xa3, 0x29, 0x25, 0xe8,
+	0x0d, 0xf7, 0x01, 0x92, 0x5d, 0x44, 0xe3, 0x84, 0x12, 0x4a, 0xd0, 0x3b, 0x06, 0xb0, 0x56, 0x7e,
+	0xc0, 0x28, 0x41, 0x66, 0xd3, 0xc6, 0x68, 0x1a, 0x84, 0x6b, 0xd4, 0xc2, 0x03, 0xf8, 0x18, 0xd1,
+	0x90, 0x04, 0xe1, 0x3a, 0x0b, 0xc2, 0x24, 0xf5, 0x19, 0x43, 0xd6, 0x73, 0x71, 0x17, 0xad, 0x63,
+	0x9f, 0x50, 0x64, 0xe3, 0x21, 0xa0, 0x7b, 0x31, 0xde, 0x32, 0xb6, 0xf0, 0x97, 0x1b, 0xd4, 0x5e,
+	0x74, 0x7e, 0xda, 0xb7, 0xb7, 0xed, 0xad, 0xfa, 0xf3, 0xbf, 0xfd, 0x1f, 0x00, 0xea, 0xf1, 0xfb,
+	0x6b, 0xe1, 0x01, 0x00, 0x00,
 }
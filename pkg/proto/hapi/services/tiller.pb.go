@@ -53,7 +53,7 @@ func (x ListSort_SortBy) String() string {
 	return proto.EnumName(ListSort_SortBy_name, int32(x))
 }
 func (ListSort_SortBy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{1, 0}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{1, 0}
 }
 
 // SortOrder defines sort orders to augment sorting operations.
@@ -77,7 +77,7 @@ func (x ListSort_SortOrder) String() string {
 	return proto.EnumName(ListSort_SortOrder_name, int32(x))
 }
 func (ListSort_SortOrder) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{1, 1}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{1, 1}
 }
 
 // ListReleasesRequest requests a list of releases.
@@ -104,7 +104,15 @@ type ListReleasesRequest struct {
 	SortOrder   ListSort_SortOrder    `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,proto3,enum=hapi.services.tiller.ListSort_SortOrder" json:"sort_order,omitempty"`
 	StatusCodes []release.Status_Code `protobuf:"varint,6,rep,packed,name=status_codes,json=statusCodes,proto3,enum=hapi.release.Status_Code" json:"status_codes,omitempty"`
 	// Namespace is the filter to select releases only from a specific namespace.
-	Namespace            string   `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// It may be a glob, such as "team-*".
+	Namespace string `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Selector is a label selector, in the Kubernetes syntax, matched against
+	// the labels of the releases.
+	Selector string `protobuf:"bytes,8,opt,name=selector,proto3" json:"selector,omitempty"`
+	// ChartName is a glob matched against the name of the chart of the releases.
+	ChartName string `protobuf:"bytes,9,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	// AppVersion is a glob matched against the app version of the chart of the releases.
+	AppVersion           string   `protobuf:"bytes,10,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -114,7 +122,7 @@ func (m *ListReleasesRequest) Reset()         { *m = ListReleasesRequest{} }
 func (m *ListReleasesRequest) String() string { return proto.CompactTextString(m) }
 func (*ListReleasesRequest) ProtoMessage()    {}
 func (*ListReleasesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{0}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{0}
 }
 func (m *ListReleasesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReleasesRequest.Unmarshal(m, b)
@@ -183,6 +191,27 @@ func (m *ListReleasesRequest) GetNamespace() string {
 	return ""
 }
 
+func (m *ListReleasesRequest) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *ListReleasesRequest) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *ListReleasesRequest) GetAppVersion() string {
+	if m != nil {
+		return m.AppVersion
+	}
+	return ""
+}
+
 // ListSort defines sorting fields on a release list.
 type ListSort struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -194,7 +223,7 @@ func (m *ListSort) Reset()         { *m = ListSort{} }
 func (m *ListSort) String() string { return proto.CompactTextString(m) }
 func (*ListSort) ProtoMessage()    {}
 func (*ListSort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{1}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{1}
 }
 func (m *ListSort) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListSort.Unmarshal(m, b)
@@ -234,7 +263,7 @@ func (m *ListReleasesResponse) Reset()         { *m = ListReleasesResponse{} }
 func (m *ListReleasesResponse) String() string { return proto.CompactTextString(m) }
 func (*ListReleasesResponse) ProtoMessage()    {}
 func (*ListReleasesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{2}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{2}
 }
 func (m *ListReleasesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReleasesResponse.Unmarshal(m, b)
@@ -287,7 +316,9 @@ type GetReleaseStatusRequest struct {
 	// Name is the name of the release
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Version is the version of the release
-	Version              int32    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Version int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Health requests the current state of each resource of the release
+	Health               bool     `protobuf:"varint,3,opt,name=health,proto3" json:"health,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -297,7 +328,7 @@ func (m *GetReleaseStatusRequest) Reset()         { *m = GetReleaseStatusRequest
 func (m *GetReleaseStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseStatusRequest) ProtoMessage()    {}
 func (*GetReleaseStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{3}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{3}
 }
 func (m *GetReleaseStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseStatusRequest.Unmarshal(m, b)
@@ -331,6 +362,13 @@ func (m *GetReleaseStatusRequest) GetVersion() int32 {
 	return 0
 }
 
+func (m *GetReleaseStatusRequest) GetHealth() bool {
+	if m != nil {
+		return m.Health
+	}
+	return false
+}
+
 // GetReleaseStatusResponse is the response indicating the status of the named release.
 type GetReleaseStatusResponse struct {
 	// Name is the name of the release.
@@ -338,17 +376,19 @@ type GetReleaseStatusResponse struct {
 	// Info contains information about the release.
 	Info *release.Info `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
 	// Namespace the release was released into
-	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Health is the current state of each resource of the release, if requested.
+	Health               []*release.ResourceReadiness `protobuf:"bytes,4,rep,name=health,proto3" json:"health,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
 }
 
 func (m *GetReleaseStatusResponse) Reset()         { *m = GetReleaseStatusResponse{} }
 func (m *GetReleaseStatusResponse) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseStatusResponse) ProtoMessage()    {}
 func (*GetReleaseStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{4}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{4}
 }
 func (m *GetReleaseStatusResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseStatusResponse.Unmarshal(m, b)
@@ -389,12 +429,22 @@ func (m *GetReleaseStatusResponse) GetNamespace() string {
 	return ""
 }
 
+func (m *GetReleaseStatusResponse) GetHealth() []*release.ResourceReadiness {
+	if m != nil {
+		return m.Health
+	}
+	return nil
+}
+
 // GetReleaseContentRequest is a request to get the contents of a release.
 type GetReleaseContentRequest struct {
 	// The name of the release
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Version is the version of the release
-	Version              int32    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Version int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// LiveManifest asks for the current state of the objects of the release,
+	// read from the cluster, in addition to the stored release
+	LiveManifest         bool     `protobuf:"varint,3,opt,name=live_manifest,json=liveManifest,proto3" json:"live_manifest,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -404,7 +454,7 @@ func (m *GetReleaseContentRequest) Reset()         { *m = GetReleaseContentReque
 func (m *GetReleaseContentRequest) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseContentRequest) ProtoMessage()    {}
 func (*GetReleaseContentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{5}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{5}
 }
 func (m *GetReleaseContentRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseContentRequest.Unmarshal(m, b)
@@ -438,20 +488,30 @@ func (m *GetReleaseContentRequest) GetVersion() int32 {
 	return 0
 }
 
+func (m *GetReleaseContentRequest) GetLiveManifest() bool {
+	if m != nil {
+		return m.LiveManifest
+	}
+	return false
+}
+
 // GetReleaseContentResponse is a response containing the contents of a release.
 type GetReleaseContentResponse struct {
 	// The release content
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// LiveManifest holds the objects of the release as they are currently
+	// found in the cluster, in the order of the stored manifest
+	LiveManifest         string   `protobuf:"bytes,2,opt,name=live_manifest,json=liveManifest,proto3" json:"live_manifest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetReleaseContentResponse) Reset()         { *m = GetReleaseContentResponse{} }
 func (m *GetReleaseContentResponse) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseContentResponse) ProtoMessage()    {}
 func (*GetReleaseContentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{6}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{6}
 }
 func (m *GetReleaseContentResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseContentResponse.Unmarshal(m, b)
@@ -478,6 +538,13 @@ func (m *GetReleaseContentResponse) GetRelease() *release.Release {
 	return nil
 }
 
+func (m *GetReleaseContentResponse) GetLiveManifest() string {
+	if m != nil {
+		return m.LiveManifest
+	}
+	return ""
+}
+
 // UpdateReleaseRequest updates a release.
 type UpdateReleaseRequest struct {
 	// The name of the release
@@ -509,17 +576,49 @@ type UpdateReleaseRequest struct {
 	// Render subchart notes if enabled
 	SubNotes bool `protobuf:"varint,13,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
 	// Allow deletion of new resources created in this update when update failed
-	CleanupOnFail        bool     `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	CleanupOnFail bool `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	// WaitTimeouts overrides timeout, in seconds, when waiting on resources of the given kind.
+	WaitTimeouts map[string]int64 `protobuf:"bytes,15,rep,name=wait_timeouts,json=waitTimeouts,proto3" json:"wait_timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Only restricts the update to the resources matching one of the selectors,
+	// either a template path or "kind=KIND".
+	Only []string `protobuf:"bytes,16,rep,name=only,proto3" json:"only,omitempty"`
+	// Strict makes rendering fail on values missing from the templates.
+	Strict bool `protobuf:"varint,17,opt,name=strict,proto3" json:"strict,omitempty"`
+	// ServiceAccount is the service account, in the namespace of the release,
+	// that Tiller impersonates to apply the release. Empty keeps the service
+	// account of the current release.
+	ServiceAccount string `protobuf:"bytes,18,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// InstallOrder replaces the order in which the kinds of resources are
+	// applied, as a comma separated list of kinds. It is kept for the later
+	// revisions of the release.
+	InstallOrder string `protobuf:"bytes,19,opt,name=install_order,json=installOrder,proto3" json:"install_order,omitempty"`
+	// UninstallOrder replaces the order in which the kinds of resources are
+	// deleted, as a comma separated list of kinds. It is kept for the later
+	// revisions of the release.
+	UninstallOrder string `protobuf:"bytes,20,opt,name=uninstall_order,json=uninstallOrder,proto3" json:"uninstall_order,omitempty"`
+	// HookTimeout, in seconds, replaces timeout when running hooks. Zero means timeout is used.
+	HookTimeout int64 `protobuf:"varint,21,opt,name=hook_timeout,json=hookTimeout,proto3" json:"hook_timeout,omitempty"`
+	// Labels are added to the labels of the release, replacing existing values, and
+	// set on its resources as release-label.helm.sh/KEY.
+	Labels map[string]string `protobuf:"bytes,22,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// OverrideWindow, if set, is the reason given to proceed outside the
+	// maintenance windows of the release. It is recorded in the audit log.
+	OverrideWindow string `protobuf:"bytes,23,opt,name=override_window,json=overrideWindow,proto3" json:"override_window,omitempty"`
+	// WaitForJobs, if true, makes wait also wait for Jobs to complete.
+	WaitForJobs bool `protobuf:"varint,24,opt,name=wait_for_jobs,json=waitForJobs,proto3" json:"wait_for_jobs,omitempty"`
+	// WaitConditions gives, by kind, the status condition that resources of
+	// the kind must meet to be ready while waiting, as PATH==VALUE.
+	WaitConditions       map[string]string `protobuf:"bytes,25,rep,name=wait_conditions,json=waitConditions,proto3" json:"wait_conditions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *UpdateReleaseRequest) Reset()         { *m = UpdateReleaseRequest{} }
 func (m *UpdateReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateReleaseRequest) ProtoMessage()    {}
 func (*UpdateReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{7}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{7}
 }
 func (m *UpdateReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateReleaseRequest.Unmarshal(m, b)
@@ -637,6 +736,83 @@ func (m *UpdateReleaseRequest) GetCleanupOnFail() bool {
 	return false
 }
 
+func (m *UpdateReleaseRequest) GetWaitTimeouts() map[string]int64 {
+	if m != nil {
+		return m.WaitTimeouts
+	}
+	return nil
+}
+
+func (m *UpdateReleaseRequest) GetOnly() []string {
+	if m != nil {
+		return m.Only
+	}
+	return nil
+}
+
+func (m *UpdateReleaseRequest) GetStrict() bool {
+	if m != nil {
+		return m.Strict
+	}
+	return false
+}
+
+func (m *UpdateReleaseRequest) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+func (m *UpdateReleaseRequest) GetInstallOrder() string {
+	if m != nil {
+		return m.InstallOrder
+	}
+	return ""
+}
+
+func (m *UpdateReleaseRequest) GetUninstallOrder() string {
+	if m != nil {
+		return m.UninstallOrder
+	}
+	return ""
+}
+
+func (m *UpdateReleaseRequest) GetHookTimeout() int64 {
+	if m != nil {
+		return m.HookTimeout
+	}
+	return 0
+}
+
+func (m *UpdateReleaseRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *UpdateReleaseRequest) GetOverrideWindow() string {
+	if m != nil {
+		return m.OverrideWindow
+	}
+	return ""
+}
+
+func (m *UpdateReleaseRequest) GetWaitForJobs() bool {
+	if m != nil {
+		return m.WaitForJobs
+	}
+	return false
+}
+
+func (m *UpdateReleaseRequest) GetWaitConditions() map[string]string {
+	if m != nil {
+		return m.WaitConditions
+	}
+	return nil
+}
+
 // UpdateReleaseResponse is the response to an update request.
 type UpdateReleaseResponse struct {
 	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
@@ -649,7 +825,7 @@ func (m *UpdateReleaseResponse) Reset()         { *m = UpdateReleaseResponse{} }
 func (m *UpdateReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateReleaseResponse) ProtoMessage()    {}
 func (*UpdateReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{8}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{8}
 }
 func (m *UpdateReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateReleaseResponse.Unmarshal(m, b)
@@ -697,17 +873,35 @@ type RollbackReleaseRequest struct {
 	// Description, if set, will set the description for the rollback
 	Description string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
 	// Allow deletion of new resources created in this rollback when rollback failed
-	CleanupOnFail        bool     `protobuf:"varint,10,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	CleanupOnFail bool `protobuf:"varint,10,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	// WaitTimeouts overrides timeout, in seconds, when waiting on resources of the given kind.
+	WaitTimeouts map[string]int64 `protobuf:"bytes,11,rep,name=wait_timeouts,json=waitTimeouts,proto3" json:"wait_timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Only restricts the rollback to the resources matching these selectors,
+	// as for a partial upgrade. Every other resource is left as it is.
+	Only []string `protobuf:"bytes,12,rep,name=only,proto3" json:"only,omitempty"`
+	// ValuesOnly rolls back the values only: the current chart is rendered
+	// again with the values of the target revision.
+	ValuesOnly bool `protobuf:"varint,13,opt,name=values_only,json=valuesOnly,proto3" json:"values_only,omitempty"`
+	// HookTimeout, in seconds, replaces timeout when running hooks. Zero means timeout is used.
+	HookTimeout int64 `protobuf:"varint,14,opt,name=hook_timeout,json=hookTimeout,proto3" json:"hook_timeout,omitempty"`
+	// OverrideWindow, if set, is the reason given to proceed outside the
+	// maintenance windows of the release. It is recorded in the audit log.
+	OverrideWindow string `protobuf:"bytes,15,opt,name=override_window,json=overrideWindow,proto3" json:"override_window,omitempty"`
+	// WaitForJobs, if true, makes wait also wait for Jobs to complete.
+	WaitForJobs bool `protobuf:"varint,16,opt,name=wait_for_jobs,json=waitForJobs,proto3" json:"wait_for_jobs,omitempty"`
+	// WaitConditions gives, by kind, the status condition that resources of
+	// the kind must meet to be ready while waiting, as PATH==VALUE.
+	WaitConditions       map[string]string `protobuf:"bytes,17,rep,name=wait_conditions,json=waitConditions,proto3" json:"wait_conditions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *RollbackReleaseRequest) Reset()         { *m = RollbackReleaseRequest{} }
 func (m *RollbackReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseRequest) ProtoMessage()    {}
 func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{9}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{9}
 }
 func (m *RollbackReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseRequest.Unmarshal(m, b)
@@ -797,6 +991,55 @@ func (m *RollbackReleaseRequest) GetCleanupOnFail() bool {
 	return false
 }
 
+func (m *RollbackReleaseRequest) GetWaitTimeouts() map[string]int64 {
+	if m != nil {
+		return m.WaitTimeouts
+	}
+	return nil
+}
+
+func (m *RollbackReleaseRequest) GetOnly() []string {
+	if m != nil {
+		return m.Only
+	}
+	return nil
+}
+
+func (m *RollbackReleaseRequest) GetValuesOnly() bool {
+	if m != nil {
+		return m.ValuesOnly
+	}
+	return false
+}
+
+func (m *RollbackReleaseRequest) GetHookTimeout() int64 {
+	if m != nil {
+		return m.HookTimeout
+	}
+	return 0
+}
+
+func (m *RollbackReleaseRequest) GetOverrideWindow() string {
+	if m != nil {
+		return m.OverrideWindow
+	}
+	return ""
+}
+
+func (m *RollbackReleaseRequest) GetWaitForJobs() bool {
+	if m != nil {
+		return m.WaitForJobs
+	}
+	return false
+}
+
+func (m *RollbackReleaseRequest) GetWaitConditions() map[string]string {
+	if m != nil {
+		return m.WaitConditions
+	}
+	return nil
+}
+
 // RollbackReleaseResponse is the response to an update request.
 type RollbackReleaseResponse struct {
 	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
@@ -809,7 +1052,7 @@ func (m *RollbackReleaseResponse) Reset()         { *m = RollbackReleaseResponse
 func (m *RollbackReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseResponse) ProtoMessage()    {}
 func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{10}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{10}
 }
 func (m *RollbackReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseResponse.Unmarshal(m, b)
@@ -863,18 +1106,56 @@ type InstallReleaseRequest struct {
 	Wait           bool `protobuf:"varint,9,opt,name=wait,proto3" json:"wait,omitempty"`
 	DisableCrdHook bool `protobuf:"varint,10,opt,name=disable_crd_hook,json=disableCrdHook,proto3" json:"disable_crd_hook,omitempty"`
 	// Description, if set, will set the description for the installed release
-	Description          string   `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
-	SubNotes             bool     `protobuf:"varint,12,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Description string `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	SubNotes    bool   `protobuf:"varint,12,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
+	// WaitTimeouts overrides timeout, in seconds, when waiting on resources of the given kind.
+	WaitTimeouts map[string]int64 `protobuf:"bytes,13,rep,name=wait_timeouts,json=waitTimeouts,proto3" json:"wait_timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// CleanupOnFail, if true, deletes the resources created by a failed install.
+	CleanupOnFail bool `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	// Strict makes rendering fail on values missing from the templates.
+	Strict bool `protobuf:"varint,15,opt,name=strict,proto3" json:"strict,omitempty"`
+	// ServiceAccount is the service account, in the namespace of the release,
+	// that Tiller impersonates to apply the release.
+	ServiceAccount string `protobuf:"bytes,16,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// CreateNamespace asks Tiller to create the namespace of the release if
+	// it does not exist.
+	CreateNamespace bool `protobuf:"varint,17,opt,name=create_namespace,json=createNamespace,proto3" json:"create_namespace,omitempty"`
+	// TakeOwnership makes resources of the manifest that already exist be
+	// adopted by the release instead of failing the install.
+	TakeOwnership bool `protobuf:"varint,18,opt,name=take_ownership,json=takeOwnership,proto3" json:"take_ownership,omitempty"`
+	// SkipCrds prevents the custom resource definitions of the crds directory
+	// of the chart from being installed.
+	SkipCrds bool `protobuf:"varint,19,opt,name=skip_crds,json=skipCrds,proto3" json:"skip_crds,omitempty"`
+	// InstallOrder replaces the order in which the kinds of resources are
+	// applied, as a comma separated list of kinds. It is kept for the later
+	// revisions of the release.
+	InstallOrder string `protobuf:"bytes,20,opt,name=install_order,json=installOrder,proto3" json:"install_order,omitempty"`
+	// UninstallOrder replaces the order in which the kinds of resources are
+	// deleted, as a comma separated list of kinds. It is kept for the later
+	// revisions of the release.
+	UninstallOrder string `protobuf:"bytes,21,opt,name=uninstall_order,json=uninstallOrder,proto3" json:"uninstall_order,omitempty"`
+	// HookTimeout, in seconds, replaces timeout when running hooks. Zero means timeout is used.
+	HookTimeout int64 `protobuf:"varint,22,opt,name=hook_timeout,json=hookTimeout,proto3" json:"hook_timeout,omitempty"`
+	// Labels are set on the release, and on its resources as release-label.helm.sh/KEY.
+	Labels map[string]string `protobuf:"bytes,23,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// OverrideWindow, if set, is the reason given to proceed outside the
+	// maintenance windows of the release. It is recorded in the audit log.
+	OverrideWindow string `protobuf:"bytes,24,opt,name=override_window,json=overrideWindow,proto3" json:"override_window,omitempty"`
+	// WaitForJobs, if true, makes wait also wait for Jobs to complete.
+	WaitForJobs bool `protobuf:"varint,25,opt,name=wait_for_jobs,json=waitForJobs,proto3" json:"wait_for_jobs,omitempty"`
+	// WaitConditions gives, by kind, the status condition that resources of
+	// the kind must meet to be ready while waiting, as PATH==VALUE.
+	WaitConditions       map[string]string `protobuf:"bytes,26,rep,name=wait_conditions,json=waitConditions,proto3" json:"wait_conditions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *InstallReleaseRequest) Reset()         { *m = InstallReleaseRequest{} }
 func (m *InstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseRequest) ProtoMessage()    {}
 func (*InstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{11}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{11}
 }
 func (m *InstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseRequest.Unmarshal(m, b)
@@ -978,6 +1259,104 @@ func (m *InstallReleaseRequest) GetSubNotes() bool {
 	return false
 }
 
+func (m *InstallReleaseRequest) GetWaitTimeouts() map[string]int64 {
+	if m != nil {
+		return m.WaitTimeouts
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetCleanupOnFail() bool {
+	if m != nil {
+		return m.CleanupOnFail
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetStrict() bool {
+	if m != nil {
+		return m.Strict
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetCreateNamespace() bool {
+	if m != nil {
+		return m.CreateNamespace
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetTakeOwnership() bool {
+	if m != nil {
+		return m.TakeOwnership
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetSkipCrds() bool {
+	if m != nil {
+		return m.SkipCrds
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetInstallOrder() string {
+	if m != nil {
+		return m.InstallOrder
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetUninstallOrder() string {
+	if m != nil {
+		return m.UninstallOrder
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetHookTimeout() int64 {
+	if m != nil {
+		return m.HookTimeout
+	}
+	return 0
+}
+
+func (m *InstallReleaseRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetOverrideWindow() string {
+	if m != nil {
+		return m.OverrideWindow
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetWaitForJobs() bool {
+	if m != nil {
+		return m.WaitForJobs
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetWaitConditions() map[string]string {
+	if m != nil {
+		return m.WaitConditions
+	}
+	return nil
+}
+
 // InstallReleaseResponse is the response from a release installation.
 type InstallReleaseResponse struct {
 	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
@@ -990,7 +1369,7 @@ func (m *InstallReleaseResponse) Reset()         { *m = InstallReleaseResponse{}
 func (m *InstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseResponse) ProtoMessage()    {}
 func (*InstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{12}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{12}
 }
 func (m *InstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseResponse.Unmarshal(m, b)
@@ -1028,7 +1407,17 @@ type UninstallReleaseRequest struct {
 	// timeout specifies the max amount of time any kubernetes client command can run.
 	Timeout int64 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	// Description, if set, will set the description for the uninstalled release
-	Description          string   `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Description string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// Cascade is how the dependents of the deleted resources are handled: "background"
+	// (the default), "foreground" or "orphan".
+	Cascade string `protobuf:"bytes,6,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	// Force deletes the release even when other releases require a capability it provides.
+	Force bool `protobuf:"varint,7,opt,name=force,proto3" json:"force,omitempty"`
+	// HookTimeout, in seconds, replaces timeout when running hooks. Zero means timeout is used.
+	HookTimeout int64 `protobuf:"varint,8,opt,name=hook_timeout,json=hookTimeout,proto3" json:"hook_timeout,omitempty"`
+	// OverrideWindow, if set, is the reason given to proceed outside the
+	// maintenance windows of the release. It is recorded in the audit log.
+	OverrideWindow       string   `protobuf:"bytes,9,opt,name=override_window,json=overrideWindow,proto3" json:"override_window,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1038,7 +1427,7 @@ func (m *UninstallReleaseRequest) Reset()         { *m = UninstallReleaseRequest
 func (m *UninstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UninstallReleaseRequest) ProtoMessage()    {}
 func (*UninstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{13}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{13}
 }
 func (m *UninstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallReleaseRequest.Unmarshal(m, b)
@@ -1093,6 +1482,34 @@ func (m *UninstallReleaseRequest) GetDescription() string {
 	return ""
 }
 
+func (m *UninstallReleaseRequest) GetCascade() string {
+	if m != nil {
+		return m.Cascade
+	}
+	return ""
+}
+
+func (m *UninstallReleaseRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *UninstallReleaseRequest) GetHookTimeout() int64 {
+	if m != nil {
+		return m.HookTimeout
+	}
+	return 0
+}
+
+func (m *UninstallReleaseRequest) GetOverrideWindow() string {
+	if m != nil {
+		return m.OverrideWindow
+	}
+	return ""
+}
+
 // UninstallReleaseResponse represents a successful response to an uninstall request.
 type UninstallReleaseResponse struct {
 	// Release is the release that was marked deleted.
@@ -1108,7 +1525,7 @@ func (m *UninstallReleaseResponse) Reset()         { *m = UninstallReleaseRespon
 func (m *UninstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UninstallReleaseResponse) ProtoMessage()    {}
 func (*UninstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{14}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{14}
 }
 func (m *UninstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallReleaseResponse.Unmarshal(m, b)
@@ -1153,7 +1570,7 @@ func (m *GetVersionRequest) Reset()         { *m = GetVersionRequest{} }
 func (m *GetVersionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetVersionRequest) ProtoMessage()    {}
 func (*GetVersionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{15}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{15}
 }
 func (m *GetVersionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetVersionRequest.Unmarshal(m, b)
@@ -1184,7 +1601,7 @@ func (m *GetVersionResponse) Reset()         { *m = GetVersionResponse{} }
 func (m *GetVersionResponse) String() string { return proto.CompactTextString(m) }
 func (*GetVersionResponse) ProtoMessage()    {}
 func (*GetVersionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{16}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{16}
 }
 func (m *GetVersionResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetVersionResponse.Unmarshal(m, b)
@@ -1226,7 +1643,7 @@ func (m *GetHistoryRequest) Reset()         { *m = GetHistoryRequest{} }
 func (m *GetHistoryRequest) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryRequest) ProtoMessage()    {}
 func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{17}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{17}
 }
 func (m *GetHistoryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryRequest.Unmarshal(m, b)
@@ -1272,7 +1689,7 @@ func (m *GetHistoryResponse) Reset()         { *m = GetHistoryResponse{} }
 func (m *GetHistoryResponse) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryResponse) ProtoMessage()    {}
 func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{18}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{18}
 }
 func (m *GetHistoryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryResponse.Unmarshal(m, b)
@@ -1308,7 +1725,11 @@ type TestReleaseRequest struct {
 	// cleanup specifies whether or not to attempt pod deletion after test completes
 	Cleanup bool `protobuf:"varint,3,opt,name=cleanup,proto3" json:"cleanup,omitempty"`
 	// parallel specifies whether or not to run test pods in parallel
-	Parallel             bool     `protobuf:"varint,4,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	Parallel bool `protobuf:"varint,4,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	// parallelism is the maximum number of test pods run at once when parallel is set, 0 meaning the server maximum
+	Parallelism uint32 `protobuf:"varint,5,opt,name=parallelism,proto3" json:"parallelism,omitempty"`
+	// logs specifies whether the logs of each test pod are streamed back with its result
+	Logs                 bool     `protobuf:"varint,6,opt,name=logs,proto3" json:"logs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1318,7 +1739,7 @@ func (m *TestReleaseRequest) Reset()         { *m = TestReleaseRequest{} }
 func (m *TestReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*TestReleaseRequest) ProtoMessage()    {}
 func (*TestReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{19}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{19}
 }
 func (m *TestReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestReleaseRequest.Unmarshal(m, b)
@@ -1366,20 +1787,36 @@ func (m *TestReleaseRequest) GetParallel() bool {
 	return false
 }
 
+func (m *TestReleaseRequest) GetParallelism() uint32 {
+	if m != nil {
+		return m.Parallelism
+	}
+	return 0
+}
+
+func (m *TestReleaseRequest) GetLogs() bool {
+	if m != nil {
+		return m.Logs
+	}
+	return false
+}
+
 // TestReleaseResponse represents a message from executing a test
 type TestReleaseResponse struct {
-	Msg                  string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
-	Status               release.TestRun_Status `protobuf:"varint,2,opt,name=status,proto3,enum=hapi.release.TestRun_Status" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Msg    string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	Status release.TestRun_Status `protobuf:"varint,2,opt,name=status,proto3,enum=hapi.release.TestRun_Status" json:"status,omitempty"`
+	// logs holds the logs of the test pod the message reports on, if requested
+	Logs                 string   `protobuf:"bytes,3,opt,name=logs,proto3" json:"logs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TestReleaseResponse) Reset()         { *m = TestReleaseResponse{} }
 func (m *TestReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*TestReleaseResponse) ProtoMessage()    {}
 func (*TestReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_bb72ee4a42494734, []int{20}
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{20}
 }
 func (m *TestReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestReleaseResponse.Unmarshal(m, b)
@@ -1413,19 +1850,1005 @@ func (m *TestReleaseResponse) GetStatus() release.TestRun_Status {
 	return release.TestRun_UNKNOWN
 }
 
-func init() {
-	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
-	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
-	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
-	proto.RegisterType((*GetReleaseStatusRequest)(nil), "hapi.services.tiller.GetReleaseStatusRequest")
-	proto.RegisterType((*GetReleaseStatusResponse)(nil), "hapi.services.tiller.GetReleaseStatusResponse")
+func (m *TestReleaseResponse) GetLogs() string {
+	if m != nil {
+		return m.Logs
+	}
+	return ""
+}
+
+// WatchReadinessRequest requests the readiness reports of the install,
+// upgrade or rollback in progress on a release. The reports already made are
+// sent first, and the stream ends with the operation.
+type WatchReadinessRequest struct {
+	// Name is the name of the release.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchReadinessRequest) Reset()         { *m = WatchReadinessRequest{} }
+func (m *WatchReadinessRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchReadinessRequest) ProtoMessage()    {}
+func (*WatchReadinessRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{21}
+}
+func (m *WatchReadinessRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchReadinessRequest.Unmarshal(m, b)
+}
+func (m *WatchReadinessRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchReadinessRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchReadinessRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchReadinessRequest.Merge(dst, src)
+}
+func (m *WatchReadinessRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchReadinessRequest.Size(m)
+}
+func (m *WatchReadinessRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchReadinessRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchReadinessRequest proto.InternalMessageInfo
+
+func (m *WatchReadinessRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// WatchReadinessResponse carries a readiness report.
+type WatchReadinessResponse struct {
+	Readiness            *release.ResourceReadiness `protobuf:"bytes,1,opt,name=readiness,proto3" json:"readiness,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *WatchReadinessResponse) Reset()         { *m = WatchReadinessResponse{} }
+func (m *WatchReadinessResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchReadinessResponse) ProtoMessage()    {}
+func (*WatchReadinessResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{22}
+}
+func (m *WatchReadinessResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchReadinessResponse.Unmarshal(m, b)
+}
+func (m *WatchReadinessResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchReadinessResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchReadinessResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchReadinessResponse.Merge(dst, src)
+}
+func (m *WatchReadinessResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchReadinessResponse.Size(m)
+}
+func (m *WatchReadinessResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchReadinessResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchReadinessResponse proto.InternalMessageInfo
+
+func (m *WatchReadinessResponse) GetReadiness() *release.ResourceReadiness {
+	if m != nil {
+		return m.Readiness
+	}
+	return nil
+}
+
+// UpdateReleaseMetadataRequest changes the labels and annotations of a release.
+type UpdateReleaseMetadataRequest struct {
+	// Name is the name of the release
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Version is the revision of the release to change, 0 meaning the latest.
+	Version int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Labels are added to the release, replacing existing values.
+	Labels map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Annotations are added to the release, replacing existing values.
+	Annotations map[string]string `protobuf:"bytes,4,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// RemoveLabels are the keys of the labels to remove.
+	RemoveLabels []string `protobuf:"bytes,5,rep,name=remove_labels,json=removeLabels,proto3" json:"remove_labels,omitempty"`
+	// RemoveAnnotations are the keys of the annotations to remove.
+	RemoveAnnotations []string `protobuf:"bytes,6,rep,name=remove_annotations,json=removeAnnotations,proto3" json:"remove_annotations,omitempty"`
+	// Propagate also applies the changes to the resources of the release.
+	Propagate            bool     `protobuf:"varint,7,opt,name=propagate,proto3" json:"propagate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateReleaseMetadataRequest) Reset()         { *m = UpdateReleaseMetadataRequest{} }
+func (m *UpdateReleaseMetadataRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateReleaseMetadataRequest) ProtoMessage()    {}
+func (*UpdateReleaseMetadataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{23}
+}
+func (m *UpdateReleaseMetadataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateReleaseMetadataRequest.Unmarshal(m, b)
+}
+func (m *UpdateReleaseMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateReleaseMetadataRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateReleaseMetadataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateReleaseMetadataRequest.Merge(dst, src)
+}
+func (m *UpdateReleaseMetadataRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateReleaseMetadataRequest.Size(m)
+}
+func (m *UpdateReleaseMetadataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateReleaseMetadataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateReleaseMetadataRequest proto.InternalMessageInfo
+
+func (m *UpdateReleaseMetadataRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateReleaseMetadataRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *UpdateReleaseMetadataRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *UpdateReleaseMetadataRequest) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *UpdateReleaseMetadataRequest) GetRemoveLabels() []string {
+	if m != nil {
+		return m.RemoveLabels
+	}
+	return nil
+}
+
+func (m *UpdateReleaseMetadataRequest) GetRemoveAnnotations() []string {
+	if m != nil {
+		return m.RemoveAnnotations
+	}
+	return nil
+}
+
+func (m *UpdateReleaseMetadataRequest) GetPropagate() bool {
+	if m != nil {
+		return m.Propagate
+	}
+	return false
+}
+
+// UpdateReleaseMetadataResponse is the response to an UpdateReleaseMetadata request.
+type UpdateReleaseMetadataResponse struct {
+	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *UpdateReleaseMetadataResponse) Reset()         { *m = UpdateReleaseMetadataResponse{} }
+func (m *UpdateReleaseMetadataResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateReleaseMetadataResponse) ProtoMessage()    {}
+func (*UpdateReleaseMetadataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{24}
+}
+func (m *UpdateReleaseMetadataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateReleaseMetadataResponse.Unmarshal(m, b)
+}
+func (m *UpdateReleaseMetadataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateReleaseMetadataResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateReleaseMetadataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateReleaseMetadataResponse.Merge(dst, src)
+}
+func (m *UpdateReleaseMetadataResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateReleaseMetadataResponse.Size(m)
+}
+func (m *UpdateReleaseMetadataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateReleaseMetadataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateReleaseMetadataResponse proto.InternalMessageInfo
+
+func (m *UpdateReleaseMetadataResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// MapReleaseAPIsRequest replaces the apiVersions Kubernetes no longer serves
+// in the manifest of the latest revision of a release.
+type MapReleaseAPIsRequest struct {
+	// Name is the name of the release
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// KubeVersion is the Kubernetes version to map the apiVersions for. The
+	// version of the cluster is used if it is empty.
+	KubeVersion string `protobuf:"bytes,2,opt,name=kube_version,json=kubeVersion,proto3" json:"kube_version,omitempty"`
+	// DryRun reports the apiVersions to map without changing the release.
+	DryRun               bool     `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MapReleaseAPIsRequest) Reset()         { *m = MapReleaseAPIsRequest{} }
+func (m *MapReleaseAPIsRequest) String() string { return proto.CompactTextString(m) }
+func (*MapReleaseAPIsRequest) ProtoMessage()    {}
+func (*MapReleaseAPIsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{25}
+}
+func (m *MapReleaseAPIsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapReleaseAPIsRequest.Unmarshal(m, b)
+}
+func (m *MapReleaseAPIsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapReleaseAPIsRequest.Marshal(b, m, deterministic)
+}
+func (dst *MapReleaseAPIsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapReleaseAPIsRequest.Merge(dst, src)
+}
+func (m *MapReleaseAPIsRequest) XXX_Size() int {
+	return xxx_messageInfo_MapReleaseAPIsRequest.Size(m)
+}
+func (m *MapReleaseAPIsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapReleaseAPIsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapReleaseAPIsRequest proto.InternalMessageInfo
+
+func (m *MapReleaseAPIsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *MapReleaseAPIsRequest) GetKubeVersion() string {
+	if m != nil {
+		return m.KubeVersion
+	}
+	return ""
+}
+
+func (m *MapReleaseAPIsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+// MapReleaseAPIsResponse is the response to a MapReleaseAPIs request.
+type MapReleaseAPIsResponse struct {
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Mappings describe the apiVersions that were replaced.
+	Mappings             []string `protobuf:"bytes,2,rep,name=mappings,proto3" json:"mappings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MapReleaseAPIsResponse) Reset()         { *m = MapReleaseAPIsResponse{} }
+func (m *MapReleaseAPIsResponse) String() string { return proto.CompactTextString(m) }
+func (*MapReleaseAPIsResponse) ProtoMessage()    {}
+func (*MapReleaseAPIsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{26}
+}
+func (m *MapReleaseAPIsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapReleaseAPIsResponse.Unmarshal(m, b)
+}
+func (m *MapReleaseAPIsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapReleaseAPIsResponse.Marshal(b, m, deterministic)
+}
+func (dst *MapReleaseAPIsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapReleaseAPIsResponse.Merge(dst, src)
+}
+func (m *MapReleaseAPIsResponse) XXX_Size() int {
+	return xxx_messageInfo_MapReleaseAPIsResponse.Size(m)
+}
+func (m *MapReleaseAPIsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapReleaseAPIsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapReleaseAPIsResponse proto.InternalMessageInfo
+
+func (m *MapReleaseAPIsResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *MapReleaseAPIsResponse) GetMappings() []string {
+	if m != nil {
+		return m.Mappings
+	}
+	return nil
+}
+
+// GetCapabilitiesRequest requests the capabilities of the cluster.
+type GetCapabilitiesRequest struct {
+	// Refresh discovers the capabilities again instead of using the cached ones.
+	Refresh bool `protobuf:"varint,1,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	// ChartName selects the chart whose limits are returned.
+	ChartName            string   `protobuf:"bytes,2,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCapabilitiesRequest) Reset()         { *m = GetCapabilitiesRequest{} }
+func (m *GetCapabilitiesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCapabilitiesRequest) ProtoMessage()    {}
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{27}
+}
+func (m *GetCapabilitiesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCapabilitiesRequest.Unmarshal(m, b)
+}
+func (m *GetCapabilitiesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCapabilitiesRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetCapabilitiesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCapabilitiesRequest.Merge(dst, src)
+}
+func (m *GetCapabilitiesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCapabilitiesRequest.Size(m)
+}
+func (m *GetCapabilitiesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCapabilitiesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCapabilitiesRequest proto.InternalMessageInfo
+
+func (m *GetCapabilitiesRequest) GetRefresh() bool {
+	if m != nil {
+		return m.Refresh
+	}
+	return false
+}
+
+func (m *GetCapabilitiesRequest) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+// GetCapabilitiesResponse describes the capabilities of the cluster.
+type GetCapabilitiesResponse struct {
+	// KubeVersion is the Kubernetes version of the cluster.
+	KubeVersion string `protobuf:"bytes,1,opt,name=kube_version,json=kubeVersion,proto3" json:"kube_version,omitempty"`
+	// APIVersions are the API versions and kinds served by the cluster.
+	ApiVersions []string `protobuf:"bytes,2,rep,name=api_versions,json=apiVersions,proto3" json:"api_versions,omitempty"`
+	// Age is the number of seconds since the capabilities were discovered.
+	Age int64 `protobuf:"varint,3,opt,name=age,proto3" json:"age,omitempty"`
+	// MaxManifestBytes is the maximum size of the manifest and hooks rendered by the chart, with 0 meaning no limit.
+	MaxManifestBytes int64 `protobuf:"varint,4,opt,name=max_manifest_bytes,json=maxManifestBytes,proto3" json:"max_manifest_bytes,omitempty"`
+	// MaxObjects is the maximum number of objects rendered by the chart, with 0 meaning no limit.
+	MaxObjects int64 `protobuf:"varint,5,opt,name=max_objects,json=maxObjects,proto3" json:"max_objects,omitempty"`
+	// MaxHooks is the maximum number of hooks rendered by the chart, with 0 meaning no limit.
+	MaxHooks             int64    `protobuf:"varint,6,opt,name=max_hooks,json=maxHooks,proto3" json:"max_hooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCapabilitiesResponse) Reset()         { *m = GetCapabilitiesResponse{} }
+func (m *GetCapabilitiesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetCapabilitiesResponse) ProtoMessage()    {}
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{28}
+}
+func (m *GetCapabilitiesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCapabilitiesResponse.Unmarshal(m, b)
+}
+func (m *GetCapabilitiesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCapabilitiesResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetCapabilitiesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCapabilitiesResponse.Merge(dst, src)
+}
+func (m *GetCapabilitiesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetCapabilitiesResponse.Size(m)
+}
+func (m *GetCapabilitiesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCapabilitiesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCapabilitiesResponse proto.InternalMessageInfo
+
+func (m *GetCapabilitiesResponse) GetKubeVersion() string {
+	if m != nil {
+		return m.KubeVersion
+	}
+	return ""
+}
+
+func (m *GetCapabilitiesResponse) GetApiVersions() []string {
+	if m != nil {
+		return m.ApiVersions
+	}
+	return nil
+}
+
+func (m *GetCapabilitiesResponse) GetAge() int64 {
+	if m != nil {
+		return m.Age
+	}
+	return 0
+}
+
+func (m *GetCapabilitiesResponse) GetMaxManifestBytes() int64 {
+	if m != nil {
+		return m.MaxManifestBytes
+	}
+	return 0
+}
+
+func (m *GetCapabilitiesResponse) GetMaxObjects() int64 {
+	if m != nil {
+		return m.MaxObjects
+	}
+	return 0
+}
+
+func (m *GetCapabilitiesResponse) GetMaxHooks() int64 {
+	if m != nil {
+		return m.MaxHooks
+	}
+	return 0
+}
+
+// GetAuditLogRequest requests the audit entries of a release.
+type GetAuditLogRequest struct {
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Max is the maximum number of entries returned, the most recent ones, with 0 meaning no limit.
+	Max                  int32    `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAuditLogRequest) Reset()         { *m = GetAuditLogRequest{} }
+func (m *GetAuditLogRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAuditLogRequest) ProtoMessage()    {}
+func (*GetAuditLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{29}
+}
+func (m *GetAuditLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAuditLogRequest.Unmarshal(m, b)
+}
+func (m *GetAuditLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAuditLogRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetAuditLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAuditLogRequest.Merge(dst, src)
+}
+func (m *GetAuditLogRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAuditLogRequest.Size(m)
+}
+func (m *GetAuditLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAuditLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAuditLogRequest proto.InternalMessageInfo
+
+func (m *GetAuditLogRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetAuditLogRequest) GetMax() int32 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+// GetAuditLogResponse is the response to a GetAuditLog request.
+type GetAuditLogResponse struct {
+	// Entries are the audit entries of the release, oldest first.
+	Entries              []*AuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetAuditLogResponse) Reset()         { *m = GetAuditLogResponse{} }
+func (m *GetAuditLogResponse) String() string { return proto.CompactTextString(m) }
+func (*GetAuditLogResponse) ProtoMessage()    {}
+func (*GetAuditLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{30}
+}
+func (m *GetAuditLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAuditLogResponse.Unmarshal(m, b)
+}
+func (m *GetAuditLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAuditLogResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetAuditLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAuditLogResponse.Merge(dst, src)
+}
+func (m *GetAuditLogResponse) XXX_Size() int {
+	return xxx_messageInfo_GetAuditLogResponse.Size(m)
+}
+func (m *GetAuditLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAuditLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAuditLogResponse proto.InternalMessageInfo
+
+func (m *GetAuditLogResponse) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// AuditEntry records a release operation.
+type AuditEntry struct {
+	// Time is when the operation completed, in RFC 3339 format.
+	Time string `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Operation is one of install, upgrade, rollback or delete.
+	Operation string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Release is the name of the release.
+	Release string `protobuf:"bytes,3,opt,name=release,proto3" json:"release,omitempty"`
+	// Namespace is the namespace of the release.
+	Namespace string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Revision is the revision the operation made.
+	Revision int32 `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	// User is who ran the operation, when known.
+	User string `protobuf:"bytes,6,opt,name=user,proto3" json:"user,omitempty"`
+	// Client is the address the operation was requested from.
+	Client string `protobuf:"bytes,7,opt,name=client,proto3" json:"client,omitempty"`
+	// Chart is the name and version of the chart of the release.
+	Chart string `protobuf:"bytes,8,opt,name=chart,proto3" json:"chart,omitempty"`
+	// ValuesDigest is the SHA-256 digest of the values supplied for the release.
+	ValuesDigest string `protobuf:"bytes,9,opt,name=values_digest,json=valuesDigest,proto3" json:"values_digest,omitempty"`
+	// Outcome is either success or failure.
+	Outcome string `protobuf:"bytes,10,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// Error is the reason of a failure.
+	Error string `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	// WindowOverride is the reason given to proceed outside the maintenance
+	// windows of the release, if any.
+	WindowOverride       string   `protobuf:"bytes,12,opt,name=window_override,json=windowOverride,proto3" json:"window_override,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{31}
+}
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (dst *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(dst, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRelease() string {
+	if m != nil {
+		return m.Release
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRevision() int32 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetClient() string {
+	if m != nil {
+		return m.Client
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetChart() string {
+	if m != nil {
+		return m.Chart
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetValuesDigest() string {
+	if m != nil {
+		return m.ValuesDigest
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetOutcome() string {
+	if m != nil {
+		return m.Outcome
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetWindowOverride() string {
+	if m != nil {
+		return m.WindowOverride
+	}
+	return ""
+}
+
+// CancelReleaseRequest requests the cancellation of the operation in progress on a release.
+type CancelReleaseRequest struct {
+	// Name is the name of the release.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelReleaseRequest) Reset()         { *m = CancelReleaseRequest{} }
+func (m *CancelReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelReleaseRequest) ProtoMessage()    {}
+func (*CancelReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{32}
+}
+func (m *CancelReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelReleaseRequest.Unmarshal(m, b)
+}
+func (m *CancelReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelReleaseRequest.Merge(dst, src)
+}
+func (m *CancelReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelReleaseRequest.Size(m)
+}
+func (m *CancelReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelReleaseRequest proto.InternalMessageInfo
+
+func (m *CancelReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// CancelReleaseResponse is the response to a CancelRelease request.
+type CancelReleaseResponse struct {
+	// Operation is the operation that was cancelled: install, upgrade or rollback.
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Started is when the operation started, in RFC 3339 format.
+	Started              string   `protobuf:"bytes,2,opt,name=started,proto3" json:"started,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelReleaseResponse) Reset()         { *m = CancelReleaseResponse{} }
+func (m *CancelReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelReleaseResponse) ProtoMessage()    {}
+func (*CancelReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{33}
+}
+func (m *CancelReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelReleaseResponse.Unmarshal(m, b)
+}
+func (m *CancelReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *CancelReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelReleaseResponse.Merge(dst, src)
+}
+func (m *CancelReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelReleaseResponse.Size(m)
+}
+func (m *CancelReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelReleaseResponse proto.InternalMessageInfo
+
+func (m *CancelReleaseResponse) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *CancelReleaseResponse) GetStarted() string {
+	if m != nil {
+		return m.Started
+	}
+	return ""
+}
+
+// UnlockReleaseRequest requests the removal of the lock on a release.
+type UnlockReleaseRequest struct {
+	// Name is the name of the release.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnlockReleaseRequest) Reset()         { *m = UnlockReleaseRequest{} }
+func (m *UnlockReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*UnlockReleaseRequest) ProtoMessage()    {}
+func (*UnlockReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{34}
+}
+func (m *UnlockReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlockReleaseRequest.Unmarshal(m, b)
+}
+func (m *UnlockReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlockReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *UnlockReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlockReleaseRequest.Merge(dst, src)
+}
+func (m *UnlockReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_UnlockReleaseRequest.Size(m)
+}
+func (m *UnlockReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlockReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnlockReleaseRequest proto.InternalMessageInfo
+
+func (m *UnlockReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// UnlockReleaseResponse describes the lock removed by an UnlockRelease request.
+type UnlockReleaseResponse struct {
+	// Operation is the operation that held the lock.
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Holder identifies the Tiller operation that held the lock.
+	Holder string `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	// Acquired is when the lock was taken, in RFC 3339 format.
+	Acquired string `protobuf:"bytes,3,opt,name=acquired,proto3" json:"acquired,omitempty"`
+	// Expires is when the lock would have expired, in RFC 3339 format.
+	Expires              string   `protobuf:"bytes,4,opt,name=expires,proto3" json:"expires,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnlockReleaseResponse) Reset()         { *m = UnlockReleaseResponse{} }
+func (m *UnlockReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*UnlockReleaseResponse) ProtoMessage()    {}
+func (*UnlockReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{35}
+}
+func (m *UnlockReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlockReleaseResponse.Unmarshal(m, b)
+}
+func (m *UnlockReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlockReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *UnlockReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlockReleaseResponse.Merge(dst, src)
+}
+func (m *UnlockReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_UnlockReleaseResponse.Size(m)
+}
+func (m *UnlockReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlockReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnlockReleaseResponse proto.InternalMessageInfo
+
+func (m *UnlockReleaseResponse) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *UnlockReleaseResponse) GetHolder() string {
+	if m != nil {
+		return m.Holder
+	}
+	return ""
+}
+
+func (m *UnlockReleaseResponse) GetAcquired() string {
+	if m != nil {
+		return m.Acquired
+	}
+	return ""
+}
+
+func (m *UnlockReleaseResponse) GetExpires() string {
+	if m != nil {
+		return m.Expires
+	}
+	return ""
+}
+
+// ImportReleaseRequest requests the import of the revisions of a release
+// exported from another Tiller.
+type ImportReleaseRequest struct {
+	// Name is the name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Releases are the revisions of the release.
+	Releases             []*release.Release `protobuf:"bytes,2,rep,name=releases,proto3" json:"releases,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ImportReleaseRequest) Reset()         { *m = ImportReleaseRequest{} }
+func (m *ImportReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportReleaseRequest) ProtoMessage()    {}
+func (*ImportReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{36}
+}
+func (m *ImportReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportReleaseRequest.Unmarshal(m, b)
+}
+func (m *ImportReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *ImportReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportReleaseRequest.Merge(dst, src)
+}
+func (m *ImportReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportReleaseRequest.Size(m)
+}
+func (m *ImportReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportReleaseRequest proto.InternalMessageInfo
+
+func (m *ImportReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ImportReleaseRequest) GetReleases() []*release.Release {
+	if m != nil {
+		return m.Releases
+	}
+	return nil
+}
+
+// ImportReleaseResponse is the response to an ImportRelease request.
+type ImportReleaseResponse struct {
+	// Release is the last imported revision of the release.
+	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ImportReleaseResponse) Reset()         { *m = ImportReleaseResponse{} }
+func (m *ImportReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportReleaseResponse) ProtoMessage()    {}
+func (*ImportReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_594c0031e0fe9806, []int{37}
+}
+func (m *ImportReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportReleaseResponse.Unmarshal(m, b)
+}
+func (m *ImportReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *ImportReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportReleaseResponse.Merge(dst, src)
+}
+func (m *ImportReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportReleaseResponse.Size(m)
+}
+func (m *ImportReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportReleaseResponse proto.InternalMessageInfo
+
+func (m *ImportReleaseResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
+	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
+	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
+	proto.RegisterType((*GetReleaseStatusRequest)(nil), "hapi.services.tiller.GetReleaseStatusRequest")
+	proto.RegisterType((*GetReleaseStatusResponse)(nil), "hapi.services.tiller.GetReleaseStatusResponse")
 	proto.RegisterType((*GetReleaseContentRequest)(nil), "hapi.services.tiller.GetReleaseContentRequest")
 	proto.RegisterType((*GetReleaseContentResponse)(nil), "hapi.services.tiller.GetReleaseContentResponse")
 	proto.RegisterType((*UpdateReleaseRequest)(nil), "hapi.services.tiller.UpdateReleaseRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.UpdateReleaseRequest.LabelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.UpdateReleaseRequest.WaitConditionsEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "hapi.services.tiller.UpdateReleaseRequest.WaitTimeoutsEntry")
 	proto.RegisterType((*UpdateReleaseResponse)(nil), "hapi.services.tiller.UpdateReleaseResponse")
 	proto.RegisterType((*RollbackReleaseRequest)(nil), "hapi.services.tiller.RollbackReleaseRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.RollbackReleaseRequest.WaitConditionsEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "hapi.services.tiller.RollbackReleaseRequest.WaitTimeoutsEntry")
 	proto.RegisterType((*RollbackReleaseResponse)(nil), "hapi.services.tiller.RollbackReleaseResponse")
 	proto.RegisterType((*InstallReleaseRequest)(nil), "hapi.services.tiller.InstallReleaseRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.InstallReleaseRequest.LabelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.InstallReleaseRequest.WaitConditionsEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "hapi.services.tiller.InstallReleaseRequest.WaitTimeoutsEntry")
 	proto.RegisterType((*InstallReleaseResponse)(nil), "hapi.services.tiller.InstallReleaseResponse")
 	proto.RegisterType((*UninstallReleaseRequest)(nil), "hapi.services.tiller.UninstallReleaseRequest")
 	proto.RegisterType((*UninstallReleaseResponse)(nil), "hapi.services.tiller.UninstallReleaseResponse")
@@ -1435,6 +2858,25 @@ func init() {
 	proto.RegisterType((*GetHistoryResponse)(nil), "hapi.services.tiller.GetHistoryResponse")
 	proto.RegisterType((*TestReleaseRequest)(nil), "hapi.services.tiller.TestReleaseRequest")
 	proto.RegisterType((*TestReleaseResponse)(nil), "hapi.services.tiller.TestReleaseResponse")
+	proto.RegisterType((*WatchReadinessRequest)(nil), "hapi.services.tiller.WatchReadinessRequest")
+	proto.RegisterType((*WatchReadinessResponse)(nil), "hapi.services.tiller.WatchReadinessResponse")
+	proto.RegisterType((*UpdateReleaseMetadataRequest)(nil), "hapi.services.tiller.UpdateReleaseMetadataRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.UpdateReleaseMetadataRequest.AnnotationsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.UpdateReleaseMetadataRequest.LabelsEntry")
+	proto.RegisterType((*UpdateReleaseMetadataResponse)(nil), "hapi.services.tiller.UpdateReleaseMetadataResponse")
+	proto.RegisterType((*MapReleaseAPIsRequest)(nil), "hapi.services.tiller.MapReleaseAPIsRequest")
+	proto.RegisterType((*MapReleaseAPIsResponse)(nil), "hapi.services.tiller.MapReleaseAPIsResponse")
+	proto.RegisterType((*GetCapabilitiesRequest)(nil), "hapi.services.tiller.GetCapabilitiesRequest")
+	proto.RegisterType((*GetCapabilitiesResponse)(nil), "hapi.services.tiller.GetCapabilitiesResponse")
+	proto.RegisterType((*GetAuditLogRequest)(nil), "hapi.services.tiller.GetAuditLogRequest")
+	proto.RegisterType((*GetAuditLogResponse)(nil), "hapi.services.tiller.GetAuditLogResponse")
+	proto.RegisterType((*AuditEntry)(nil), "hapi.services.tiller.AuditEntry")
+	proto.RegisterType((*CancelReleaseRequest)(nil), "hapi.services.tiller.CancelReleaseRequest")
+	proto.RegisterType((*CancelReleaseResponse)(nil), "hapi.services.tiller.CancelReleaseResponse")
+	proto.RegisterType((*UnlockReleaseRequest)(nil), "hapi.services.tiller.UnlockReleaseRequest")
+	proto.RegisterType((*UnlockReleaseResponse)(nil), "hapi.services.tiller.UnlockReleaseResponse")
+	proto.RegisterType((*ImportReleaseRequest)(nil), "hapi.services.tiller.ImportReleaseRequest")
+	proto.RegisterType((*ImportReleaseResponse)(nil), "hapi.services.tiller.ImportReleaseResponse")
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortBy", ListSort_SortBy_name, ListSort_SortBy_value)
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortOrder", ListSort_SortOrder_name, ListSort_SortOrder_value)
 }
@@ -1474,6 +2916,22 @@ type ReleaseServiceClient interface {
 	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+	// WatchReadiness streams the readiness reports of the install, upgrade or rollback in progress on a release.
+	WatchReadiness(ctx context.Context, in *WatchReadinessRequest, opts ...grpc.CallOption) (ReleaseService_WatchReadinessClient, error)
+	// UpdateReleaseMetadata changes the labels and annotations of a release.
+	UpdateReleaseMetadata(ctx context.Context, in *UpdateReleaseMetadataRequest, opts ...grpc.CallOption) (*UpdateReleaseMetadataResponse, error)
+	// MapReleaseAPIs replaces the apiVersions Kubernetes no longer serves in the manifest of a release.
+	MapReleaseAPIs(ctx context.Context, in *MapReleaseAPIsRequest, opts ...grpc.CallOption) (*MapReleaseAPIsResponse, error)
+	// GetCapabilities returns the capabilities of the cluster charts are rendered for.
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+	// GetAuditLog returns the recorded operations on a release.
+	GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error)
+	// CancelRelease cancels the install, upgrade or rollback in progress on a release.
+	CancelRelease(ctx context.Context, in *CancelReleaseRequest, opts ...grpc.CallOption) (*CancelReleaseResponse, error)
+	// UnlockRelease removes the lock on a release left by an operation that did not finish.
+	UnlockRelease(ctx context.Context, in *UnlockReleaseRequest, opts ...grpc.CallOption) (*UnlockReleaseResponse, error)
+	// ImportRelease restores the revisions of a release exported from another Tiller.
+	ImportRelease(ctx context.Context, in *ImportReleaseRequest, opts ...grpc.CallOption) (*ImportReleaseResponse, error)
 }
 
 type releaseServiceClient struct {
@@ -1620,6 +3078,101 @@ func (x *releaseServiceRunReleaseTestClient) Recv() (*TestReleaseResponse, error
 	return m, nil
 }
 
+func (c *releaseServiceClient) WatchReadiness(ctx context.Context, in *WatchReadinessRequest, opts ...grpc.CallOption) (ReleaseService_WatchReadinessClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ReleaseService_serviceDesc.Streams[2], "/hapi.services.tiller.ReleaseService/WatchReadiness", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseServiceWatchReadinessClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReleaseService_WatchReadinessClient interface {
+	Recv() (*WatchReadinessResponse, error)
+	grpc.ClientStream
+}
+
+type releaseServiceWatchReadinessClient struct {
+	grpc.ClientStream
+}
+
+func (x *releaseServiceWatchReadinessClient) Recv() (*WatchReadinessResponse, error) {
+	m := new(WatchReadinessResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *releaseServiceClient) UpdateReleaseMetadata(ctx context.Context, in *UpdateReleaseMetadataRequest, opts ...grpc.CallOption) (*UpdateReleaseMetadataResponse, error) {
+	out := new(UpdateReleaseMetadataResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UpdateReleaseMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) MapReleaseAPIs(ctx context.Context, in *MapReleaseAPIsRequest, opts ...grpc.CallOption) (*MapReleaseAPIsResponse, error) {
+	out := new(MapReleaseAPIsResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/MapReleaseAPIs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error) {
+	out := new(GetAuditLogResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) CancelRelease(ctx context.Context, in *CancelReleaseRequest, opts ...grpc.CallOption) (*CancelReleaseResponse, error) {
+	out := new(CancelReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/CancelRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) UnlockRelease(ctx context.Context, in *UnlockReleaseRequest, opts ...grpc.CallOption) (*UnlockReleaseResponse, error) {
+	out := new(UnlockReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UnlockRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ImportRelease(ctx context.Context, in *ImportReleaseRequest, opts ...grpc.CallOption) (*ImportReleaseResponse, error) {
+	out := new(ImportReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ImportRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReleaseServiceServer is the server API for ReleaseService service.
 type ReleaseServiceServer interface {
 	// ListReleases retrieves release history.
@@ -1645,6 +3198,22 @@ type ReleaseServiceServer interface {
 	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(*TestReleaseRequest, ReleaseService_RunReleaseTestServer) error
+	// WatchReadiness streams the readiness reports of the install, upgrade or rollback in progress on a release.
+	WatchReadiness(*WatchReadinessRequest, ReleaseService_WatchReadinessServer) error
+	// UpdateReleaseMetadata changes the labels and annotations of a release.
+	UpdateReleaseMetadata(context.Context, *UpdateReleaseMetadataRequest) (*UpdateReleaseMetadataResponse, error)
+	// MapReleaseAPIs replaces the apiVersions Kubernetes no longer serves in the manifest of a release.
+	MapReleaseAPIs(context.Context, *MapReleaseAPIsRequest) (*MapReleaseAPIsResponse, error)
+	// GetCapabilities returns the capabilities of the cluster charts are rendered for.
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	// GetAuditLog returns the recorded operations on a release.
+	GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error)
+	// CancelRelease cancels the install, upgrade or rollback in progress on a release.
+	CancelRelease(context.Context, *CancelReleaseRequest) (*CancelReleaseResponse, error)
+	// UnlockRelease removes the lock on a release left by an operation that did not finish.
+	UnlockRelease(context.Context, *UnlockReleaseRequest) (*UnlockReleaseResponse, error)
+	// ImportRelease restores the revisions of a release exported from another Tiller.
+	ImportRelease(context.Context, *ImportReleaseRequest) (*ImportReleaseResponse, error)
 }
 
 func RegisterReleaseServiceServer(s *grpc.Server, srv ReleaseServiceServer) {
@@ -1837,6 +3406,153 @@ func (x *releaseServiceRunReleaseTestServer) Send(m *TestReleaseResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ReleaseService_WatchReadiness_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchReadinessRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReleaseServiceServer).WatchReadiness(m, &releaseServiceWatchReadinessServer{stream})
+}
+
+type ReleaseService_WatchReadinessServer interface {
+	Send(*WatchReadinessResponse) error
+	grpc.ServerStream
+}
+
+type releaseServiceWatchReadinessServer struct {
+	grpc.ServerStream
+}
+
+func (x *releaseServiceWatchReadinessServer) Send(m *WatchReadinessResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ReleaseService_UpdateReleaseMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReleaseMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).UpdateReleaseMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/UpdateReleaseMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).UpdateReleaseMetadata(ctx, req.(*UpdateReleaseMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_MapReleaseAPIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapReleaseAPIsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).MapReleaseAPIs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/MapReleaseAPIs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).MapReleaseAPIs(ctx, req.(*MapReleaseAPIsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetAuditLog(ctx, req.(*GetAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_CancelRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).CancelRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/CancelRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).CancelRelease(ctx, req.(*CancelReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_UnlockRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).UnlockRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/UnlockRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).UnlockRelease(ctx, req.(*UnlockReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_ImportRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ImportRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/ImportRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ImportRelease(ctx, req.(*ImportReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hapi.services.tiller.ReleaseService",
 	HandlerType: (*ReleaseServiceServer)(nil),
@@ -1873,6 +3589,34 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHistory",
 			Handler:    _ReleaseService_GetHistory_Handler,
 		},
+		{
+			MethodName: "UpdateReleaseMetadata",
+			Handler:    _ReleaseService_UpdateReleaseMetadata_Handler,
+		},
+		{
+			MethodName: "MapReleaseAPIs",
+			Handler:    _ReleaseService_MapReleaseAPIs_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _ReleaseService_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _ReleaseService_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "CancelRelease",
+			Handler:    _ReleaseService_CancelRelease_Handler,
+		},
+		{
+			MethodName: "UnlockRelease",
+			Handler:    _ReleaseService_UnlockRelease_Handler,
+		},
+		{
+			MethodName: "ImportRelease",
+			Handler:    _ReleaseService_ImportRelease_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1885,96 +3629,186 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _ReleaseService_RunReleaseTest_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchReadiness",
+			Handler:       _ReleaseService_WatchReadiness_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "hapi/services/tiller.proto",
 }
 
-func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor_tiller_bb72ee4a42494734) }
-
-var fileDescriptor_tiller_bb72ee4a42494734 = []byte{
-	// 1337 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0x8e, 0x2d, 0xff, 0x1e, 0x27, 0xae, 0xbb, 0x4d, 0x13, 0xd5, 0x14, 0x26, 0x88, 0xa1, 0x75,
-	0x0b, 0x75, 0x20, 0x70, 0xc3, 0x0c, 0xc3, 0x4c, 0xea, 0x86, 0xa4, 0x10, 0xd2, 0x19, 0xb9, 0x2d,
-	0x33, 0xcc, 0x30, 0x1e, 0xc5, 0x5e, 0xb7, 0xa2, 0xb2, 0xd6, 0x68, 0x57, 0xa1, 0x79, 0x04, 0x2e,
-	0x79, 0x07, 0xae, 0x79, 0x06, 0x6e, 0x79, 0x06, 0x5e, 0x86, 0xd9, 0x3f, 0x45, 0x2b, 0x4b, 0xae,
-	0xc8, 0x4d, 0xac, 0xdd, 0x73, 0xf6, 0xfc, 0x7c, 0xdf, 0x9e, 0xb3, 0x67, 0x02, 0xfd, 0xd7, 0xde,
-	0xd2, 0xdf, 0xa7, 0x38, 0xba, 0xf0, 0xa7, 0x98, 0xee, 0x33, 0x3f, 0x08, 0x70, 0x34, 0x5c, 0x46,
-	0x84, 0x11, 0xb4, 0xcd, 0x65, 0x43, 0x2d, 0x1b, 0x4a, 0x59, 0x7f, 0x47, 0x9c, 0x98, 0xbe, 0xf6,
-	0x22, 0x26, 0xff, 0x4a, 0xed, 0xfe, 0x6e, 0x7a, 0x9f, 0x84, 0x73, 0xff, 0x95, 0x12, 0x48, 0x17,
-	0x11, 0x0e, 0xb0, 0x47, 0xb1, 0xfe, 0x35, 0x0e, 0x69, 0x99, 0x1f, 0xce, 0x89, 0x12, 0xbc, 0x67,
-	0x08, 0x18, 0xa6, 0x6c, 0x12, 0xc5, 0xa1, 0x12, 0xde, 0x31, 0x84, 0x94, 0x79, 0x2c, 0xa6, 0x86,
-	0xb3, 0x0b, 0x1c, 0x51, 0x9f, 0x84, 0xfa, 0x57, 0xca, 0x9c, 0xbf, 0xab, 0x70, 0xeb, 0xd4, 0xa7,
-	0xcc, 0x95, 0x07, 0xa9, 0x8b, 0x7f, 0x8d, 0x31, 0x65, 0x68, 0x1b, 0xea, 0x81, 0xbf, 0xf0, 0x99,
-	0x5d, 0xd9, 0xab, 0x0c, 0x2c, 0x57, 0x2e, 0xd0, 0x0e, 0x34, 0xc8, 0x7c, 0x4e, 0x31, 0xb3, 0xab,
-	0x7b, 0x95, 0x41, 0xdb, 0x55, 0x2b, 0xf4, 0x0d, 0x34, 0x29, 0x89, 0xd8, 0xe4, 0xfc, 0xd2, 0xb6,
-	0xf6, 0x2a, 0x83, 0xee, 0xc1, 0xc7, 0xc3, 0x3c, 0x9c, 0x86, 0xdc, 0xd3, 0x98, 0x44, 0x6c, 0xc8,
-	0xff, 0x3c, 0xbe, 0x74, 0x1b, 0x54, 0xfc, 0x72, 0xbb, 0x73, 0x3f, 0x60, 0x38, 0xb2, 0x6b, 0xd2,
-	0xae, 0x5c, 0xa1, 0x63, 0x00, 0x61, 0x97, 0x44, 0x33, 0x1c, 0xd9, 0x75, 0x61, 0x7a, 0x50, 0xc2,
-	0xf4, 0x33, 0xae, 0xef, 0xb6, 0xa9, 0xfe, 0x44, 0x5f, 0xc3, 0xa6, 0x84, 0x64, 0x32, 0x25, 0x33,
-	0x4c, 0xed, 0xc6, 0x9e, 0x35, 0xe8, 0x1e, 0xdc, 0x91, 0xa6, 0x34, 0xfc, 0x63, 0x09, 0xda, 0x88,
-	0xcc, 0xb0, 0xdb, 0x91, 0xea, 0xfc, 0x9b, 0xa2, 0xbb, 0xd0, 0x0e, 0xbd, 0x05, 0xa6, 0x4b, 0x6f,
-	0x8a, 0xed, 0xa6, 0x88, 0xf0, 0x6a, 0xc3, 0x09, 0xa1, 0xa5, 0x9d, 0x3b, 0x8f, 0xa1, 0x21, 0x53,
-	0x43, 0x1d, 0x68, 0xbe, 0x38, 0xfb, 0xfe, 0xec, 0xd9, 0x8f, 0x67, 0xbd, 0x0d, 0xd4, 0x82, 0xda,
-	0xd9, 0xe1, 0x0f, 0x47, 0xbd, 0x0a, 0xba, 0x09, 0x5b, 0xa7, 0x87, 0xe3, 0xe7, 0x13, 0xf7, 0xe8,
-	0xf4, 0xe8, 0x70, 0x7c, 0xf4, 0xa4, 0x57, 0x45, 0x5d, 0x80, 0xd1, 0xc9, 0xa1, 0xfb, 0x7c, 0x22,
-	0x54, 0x2c, 0xe7, 0x03, 0x68, 0x27, 0x39, 0xa0, 0x26, 0x58, 0x87, 0xe3, 0x91, 0x34, 0xf1, 0xe4,
-	0x68, 0x3c, 0xea, 0x55, 0x9c, 0xdf, 0x2b, 0xb0, 0x6d, 0x52, 0x46, 0x97, 0x24, 0xa4, 0x98, 0x73,
-	0x36, 0x25, 0x71, 0x98, 0x70, 0x26, 0x16, 0x08, 0x41, 0x2d, 0xc4, 0x6f, 0x35, 0x63, 0xe2, 0x9b,
-	0x6b, 0x32, 0xc2, 0xbc, 0x40, 0xb0, 0x65, 0xb9, 0x72, 0x81, 0x3e, 0x87, 0x96, 0x82, 0x82, 0xda,
-	0xb5, 0x3d, 0x6b, 0xd0, 0x39, 0xb8, 0x6d, 0x02, 0xa4, 0x3c, 0xba, 0x89, 0x9a, 0x73, 0x0c, 0xbb,
-	0xc7, 0x58, 0x47, 0x22, 0xf1, 0xd3, 0x37, 0x88, 0xfb, 0xf5, 0x16, 0x58, 0x04, 0xc3, 0xfd, 0x7a,
-	0x0b, 0x8c, 0x6c, 0x68, 0xaa, 0xeb, 0x27, 0xc2, 0xa9, 0xbb, 0x7a, 0xe9, 0x30, 0xb0, 0x57, 0x0d,
-	0xa9, 0xbc, 0xf2, 0x2c, 0xdd, 0x83, 0x1a, 0xaf, 0x0c, 0x61, 0xa6, 0x73, 0x80, 0xcc, 0x38, 0x9f,
-	0x86, 0x73, 0xe2, 0x0a, 0xb9, 0x49, 0x9d, 0x95, 0xa5, 0xee, 0x24, 0xed, 0x75, 0x44, 0x42, 0x86,
-	0x43, 0x76, 0xbd, 0xf8, 0x4f, 0xe1, 0x4e, 0x8e, 0x25, 0x95, 0xc0, 0x3e, 0x34, 0x55, 0x68, 0xc2,
-	0x5a, 0x21, 0xae, 0x5a, 0xcb, 0xf9, 0xc7, 0x82, 0xed, 0x17, 0xcb, 0x99, 0xc7, 0xb0, 0x16, 0xad,
-	0x09, 0xea, 0x3e, 0xd4, 0x45, 0x87, 0x51, 0x58, 0xdc, 0x94, 0xb6, 0x65, 0x1b, 0x1a, 0xf1, 0xbf,
-	0xae, 0x94, 0xa3, 0x87, 0xd0, 0xb8, 0xf0, 0x82, 0x18, 0x53, 0x01, 0x44, 0x82, 0x9a, 0xd2, 0x14,
-	0xed, 0xc9, 0x55, 0x1a, 0x68, 0x17, 0x9a, 0xb3, 0xe8, 0x92, 0xf7, 0x17, 0x51, 0x92, 0x2d, 0xb7,
-	0x31, 0x8b, 0x2e, 0xdd, 0x38, 0x44, 0x1f, 0xc1, 0xd6, 0xcc, 0xa7, 0xde, 0x79, 0x80, 0x27, 0xaf,
-	0x09, 0x79, 0x43, 0x45, 0x55, 0xb6, 0xdc, 0x4d, 0xb5, 0x79, 0xc2, 0xf7, 0x50, 0x9f, 0xdf, 0xa4,
-	0x69, 0x84, 0x3d, 0x86, 0xed, 0x86, 0x90, 0x27, 0x6b, 0x8e, 0x21, 0xf3, 0x17, 0x98, 0xc4, 0x4c,
-	0x94, 0x92, 0xe5, 0xea, 0x25, 0xfa, 0x10, 0x36, 0x23, 0x4c, 0x31, 0x9b, 0xa8, 0x28, 0x5b, 0xe2,
-	0x64, 0x47, 0xec, 0xbd, 0x94, 0x61, 0x21, 0xa8, 0xfd, 0xe6, 0xf9, 0xcc, 0x6e, 0x0b, 0x91, 0xf8,
-	0x96, 0xc7, 0x62, 0x8a, 0xf5, 0x31, 0xd0, 0xc7, 0x62, 0x8a, 0xd5, 0xb1, 0x6d, 0xa8, 0xcf, 0x49,
-	0x34, 0xc5, 0x76, 0x47, 0xc8, 0xe4, 0x02, 0xed, 0x41, 0x67, 0x86, 0xe9, 0x34, 0xf2, 0x97, 0x8c,
-	0x33, 0xba, 0x29, 0x30, 0x4d, 0x6f, 0xf1, 0x3c, 0x68, 0x7c, 0x7e, 0x46, 0x18, 0xa6, 0xf6, 0x96,
-	0xcc, 0x43, 0xaf, 0xd1, 0x3d, 0xb8, 0x31, 0x0d, 0xb0, 0x17, 0xc6, 0xcb, 0x09, 0x09, 0x27, 0x73,
-	0xcf, 0x0f, 0xec, 0xae, 0x50, 0xd9, 0x52, 0xdb, 0xcf, 0xc2, 0x6f, 0x3d, 0x3f, 0x70, 0x4e, 0xe0,
-	0x76, 0x86, 0xca, 0xeb, 0xde, 0x8a, 0xbf, 0xaa, 0xb0, 0xe3, 0x92, 0x20, 0x38, 0xf7, 0xa6, 0x6f,
-	0x4a, 0xdc, 0x8b, 0x14, 0x85, 0xd5, 0xf5, 0x14, 0x5a, 0x39, 0x14, 0xa6, 0xae, 0x7a, 0xcd, 0xb8,
-	0xea, 0x06, 0xb9, 0xf5, 0x62, 0x72, 0x1b, 0x26, 0xb9, 0x9a, 0xb9, 0x66, 0x8a, 0xb9, 0x84, 0x96,
-	0xd6, 0x1a, 0x5a, 0xda, 0xab, 0xb4, 0xe4, 0x40, 0x0f, 0x79, 0xd0, 0x7f, 0x07, 0xbb, 0x2b, 0x78,
-	0x5d, 0x17, 0xfc, 0x3f, 0x2c, 0xb8, 0xfd, 0x34, 0xa4, 0xcc, 0x0b, 0x82, 0x0c, 0xf6, 0x49, 0xfd,
-	0x55, 0x4a, 0xd7, 0x5f, 0xf5, 0xff, 0xd4, 0x9f, 0x65, 0x90, 0xa7, 0x99, 0xae, 0xa5, 0x98, 0x2e,
-	0x55, 0x93, 0x46, 0x27, 0x6c, 0x64, 0x3a, 0x21, 0x7a, 0x1f, 0x40, 0x16, 0x91, 0x30, 0x2e, 0x49,
-	0x6a, 0x8b, 0x9d, 0x33, 0xd5, 0xf8, 0x34, 0xaf, 0xad, 0x7c, 0x5e, 0xd3, 0x15, 0x39, 0x80, 0x9e,
-	0x8e, 0x67, 0x1a, 0xcd, 0x44, 0x4c, 0x8a, 0xa0, 0xae, 0xda, 0x1f, 0x45, 0x33, 0x1e, 0x55, 0x96,
-	0xeb, 0xce, 0xfa, 0x12, 0xdc, 0x34, 0x4b, 0xd0, 0x79, 0x0a, 0x3b, 0x59, 0x4a, 0xae, 0x4b, 0xef,
-	0x9f, 0x15, 0xd8, 0x7d, 0x11, 0xfa, 0xb9, 0x04, 0xe7, 0x15, 0xd7, 0x0a, 0xe4, 0xd5, 0x1c, 0xc8,
-	0xb7, 0xa1, 0xbe, 0x8c, 0xa3, 0x57, 0x58, 0x51, 0x28, 0x17, 0x69, 0x2c, 0x6b, 0x26, 0x96, 0x19,
-	0x34, 0xea, 0x2b, 0x68, 0x38, 0x13, 0xb0, 0x57, 0xa3, 0xbc, 0x66, 0xce, 0x3c, 0xaf, 0xe4, 0x0d,
-	0x6d, 0xcb, 0xf7, 0xd2, 0xb9, 0x05, 0x37, 0x8f, 0x31, 0x7b, 0x29, 0x4b, 0x5d, 0x01, 0xe0, 0x1c,
-	0x01, 0x4a, 0x6f, 0x5e, 0xf9, 0x53, 0x5b, 0xa6, 0x3f, 0x3d, 0x60, 0x6a, 0x7d, 0xad, 0xe5, 0x7c,
-	0x25, 0x6c, 0x9f, 0xf8, 0x94, 0x91, 0xe8, 0x72, 0x1d, 0xb8, 0x3d, 0xb0, 0x16, 0xde, 0x5b, 0xf5,
-	0xc4, 0xf2, 0x4f, 0xe7, 0x58, 0x44, 0x90, 0x1c, 0x55, 0x11, 0xa4, 0x07, 0x96, 0x4a, 0xb9, 0x81,
-	0xe5, 0x2d, 0xa0, 0xe7, 0x38, 0x99, 0x9d, 0xde, 0xf1, 0xd6, 0x6b, 0x9a, 0xaa, 0x26, 0x4d, 0x36,
-	0x34, 0x55, 0x9f, 0x51, 0xc4, 0xea, 0x25, 0xbf, 0xac, 0x4b, 0x2f, 0xf2, 0x82, 0x00, 0x07, 0xea,
-	0xd9, 0x4c, 0xd6, 0xce, 0xcf, 0x70, 0xcb, 0xf0, 0xac, 0x72, 0xe0, 0xb9, 0xd2, 0x57, 0xca, 0x33,
-	0xff, 0x44, 0x5f, 0x42, 0x43, 0x0e, 0x9f, 0xc2, 0x6f, 0xf7, 0xe0, 0xae, 0x99, 0x93, 0x30, 0x12,
-	0x87, 0x6a, 0x5a, 0x75, 0x95, 0xee, 0xc1, 0xbf, 0x2d, 0xe8, 0xea, 0xf1, 0x49, 0x8e, 0xc6, 0xc8,
-	0x87, 0xcd, 0xf4, 0x9c, 0x88, 0x1e, 0x14, 0x4f, 0xce, 0x99, 0xf1, 0xbf, 0xff, 0xb0, 0x8c, 0xaa,
-	0xcc, 0xc0, 0xd9, 0xf8, 0xac, 0x82, 0x28, 0xf4, 0xb2, 0xe3, 0x1b, 0x7a, 0x94, 0x6f, 0xa3, 0x60,
-	0x5e, 0xec, 0x0f, 0xcb, 0xaa, 0x6b, 0xb7, 0xe8, 0x42, 0xdc, 0x27, 0x73, 0xe6, 0x42, 0xef, 0x34,
-	0x63, 0x8e, 0x79, 0xfd, 0xfd, 0xd2, 0xfa, 0x89, 0xdf, 0x5f, 0x60, 0xcb, 0x78, 0xd1, 0x51, 0x01,
-	0x5a, 0x79, 0x13, 0x5c, 0xff, 0x93, 0x52, 0xba, 0x89, 0xaf, 0x05, 0x74, 0xcd, 0x16, 0x87, 0x0a,
-	0x0c, 0xe4, 0xbe, 0x4d, 0xfd, 0x4f, 0xcb, 0x29, 0x27, 0xee, 0x28, 0xf4, 0xb2, 0xfd, 0xa5, 0x88,
-	0xc7, 0x82, 0x6e, 0x59, 0xc4, 0x63, 0x51, 0xdb, 0x72, 0x36, 0x90, 0x07, 0x70, 0xd5, 0x5e, 0xd0,
-	0xfd, 0x42, 0x42, 0xcc, 0xae, 0xd4, 0x1f, 0xbc, 0x5b, 0x31, 0x71, 0xb1, 0x84, 0x1b, 0x99, 0x49,
-	0x00, 0x15, 0x40, 0x93, 0x3f, 0x60, 0xf5, 0x1f, 0x95, 0xd4, 0xce, 0x24, 0xa5, 0x3a, 0xd6, 0x9a,
-	0xa4, 0xcc, 0x76, 0xb8, 0x26, 0xa9, 0x4c, 0xf3, 0x73, 0x36, 0x90, 0x0f, 0x5d, 0x37, 0x0e, 0x95,
-	0x6b, 0xde, 0x16, 0x50, 0xc1, 0xe9, 0xd5, 0x8e, 0xd7, 0x7f, 0x50, 0x42, 0xf3, 0xaa, 0xbe, 0x1f,
-	0xc3, 0x4f, 0x2d, 0xad, 0x7a, 0xde, 0x10, 0xff, 0x39, 0xf8, 0xe2, 0xbf, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x20, 0xcd, 0x9e, 0x3a, 0x27, 0x11, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor_tiller_594c0031e0fe9806) }
+
+var fileDescriptor_tiller_594c0031e0fe9806 = []byte{
+	// 2697 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x1a, 0xcb, 0x72, 0xdc, 0xc6,
+	0xd1, 0xcb, 0xe5, 0x63, 0xb7, 0x77, 0xb9, 0x5c, 0x0e, 0x5f, 0x10, 0x6c, 0xc7, 0x14, 0x52, 0xb6,
+	0xa8, 0x17, 0x95, 0xd0, 0xa9, 0x38, 0x76, 0xf9, 0x11, 0x8a, 0xa6, 0x25, 0x39, 0x12, 0x69, 0x43,
+	0x92, 0x55, 0x95, 0xaa, 0x14, 0x6a, 0x16, 0x3b, 0x24, 0x21, 0x62, 0x01, 0x68, 0x66, 0x96, 0x22,
+	0xaf, 0x49, 0xe5, 0x90, 0x7f, 0xc8, 0x21, 0xb7, 0x9c, 0x92, 0x6b, 0xfe, 0x23, 0xe7, 0xe4, 0x3f,
+	0x72, 0x4c, 0xcd, 0x0b, 0x0b, 0x60, 0xb1, 0x24, 0x96, 0x49, 0x2e, 0xb9, 0x70, 0xd1, 0x3d, 0x3d,
+	0xd3, 0x3d, 0xfd, 0x9a, 0x9e, 0x1e, 0x82, 0x7d, 0x82, 0x93, 0xe0, 0x01, 0x23, 0xf4, 0x2c, 0xf0,
+	0x09, 0x7b, 0xc0, 0x83, 0x30, 0x24, 0x74, 0x3b, 0xa1, 0x31, 0x8f, 0xd1, 0xaa, 0x18, 0xdb, 0x36,
+	0x63, 0xdb, 0x6a, 0xcc, 0x5e, 0x97, 0x33, 0xfc, 0x13, 0x4c, 0xb9, 0xfa, 0xab, 0xa8, 0xed, 0x8d,
+	0x2c, 0x3e, 0x8e, 0x8e, 0x82, 0x63, 0x3d, 0xa0, 0x58, 0x50, 0x12, 0x12, 0xcc, 0x88, 0xf9, 0xcd,
+	0x4d, 0x32, 0x63, 0x41, 0x74, 0x14, 0xeb, 0x81, 0x77, 0x73, 0x03, 0x9c, 0x30, 0xee, 0xd1, 0x61,
+	0xa4, 0x07, 0x6f, 0xe4, 0x06, 0x19, 0xc7, 0x7c, 0xc8, 0x72, 0xcc, 0xce, 0x08, 0x65, 0x41, 0x1c,
+	0x99, 0x5f, 0x35, 0xe6, 0xfc, 0xb1, 0x0e, 0x2b, 0x4f, 0x03, 0xc6, 0x5d, 0x35, 0x91, 0xb9, 0xe4,
+	0xcd, 0x90, 0x30, 0x8e, 0x56, 0x61, 0x2e, 0x0c, 0x06, 0x01, 0xb7, 0x6a, 0x9b, 0xb5, 0xad, 0xba,
+	0xab, 0x00, 0xb4, 0x0e, 0xf3, 0xf1, 0xd1, 0x11, 0x23, 0xdc, 0x9a, 0xd9, 0xac, 0x6d, 0x35, 0x5d,
+	0x0d, 0xa1, 0x2f, 0x61, 0x81, 0xc5, 0x94, 0x7b, 0xbd, 0x0b, 0xab, 0xbe, 0x59, 0xdb, 0xea, 0xec,
+	0x7c, 0xb8, 0x5d, 0xa6, 0xa7, 0x6d, 0xc1, 0xe9, 0x79, 0x4c, 0xf9, 0xb6, 0xf8, 0xf3, 0xf0, 0xc2,
+	0x9d, 0x67, 0xf2, 0x57, 0xac, 0x7b, 0x14, 0x84, 0x9c, 0x50, 0x6b, 0x56, 0xad, 0xab, 0x20, 0xf4,
+	0x08, 0x40, 0xae, 0x1b, 0xd3, 0x3e, 0xa1, 0xd6, 0x9c, 0x5c, 0x7a, 0xab, 0xc2, 0xd2, 0x87, 0x82,
+	0xde, 0x6d, 0x32, 0xf3, 0x89, 0x3e, 0x87, 0xb6, 0x52, 0x89, 0xe7, 0xc7, 0x7d, 0xc2, 0xac, 0xf9,
+	0xcd, 0xfa, 0x56, 0x67, 0xe7, 0x86, 0x5a, 0xca, 0xa8, 0xff, 0xb9, 0x52, 0xda, 0x5e, 0xdc, 0x27,
+	0x6e, 0x4b, 0x91, 0x8b, 0x6f, 0x86, 0xde, 0x83, 0x66, 0x84, 0x07, 0x84, 0x25, 0xd8, 0x27, 0xd6,
+	0x82, 0x94, 0x70, 0x84, 0x40, 0x36, 0x34, 0x18, 0x09, 0x89, 0xcf, 0x63, 0x6a, 0x35, 0xe4, 0x60,
+	0x0a, 0xa3, 0xf7, 0x01, 0xa4, 0xf5, 0x3d, 0x41, 0x6e, 0x35, 0xd5, 0x54, 0x89, 0x39, 0xc0, 0x03,
+	0x82, 0x3e, 0x80, 0x16, 0x4e, 0x12, 0x4f, 0x9b, 0xc4, 0x02, 0x39, 0x0e, 0x38, 0x49, 0x7e, 0x50,
+	0x18, 0x27, 0x82, 0x86, 0xd9, 0x98, 0xf3, 0x10, 0xe6, 0x95, 0xda, 0x50, 0x0b, 0x16, 0x5e, 0x1e,
+	0xfc, 0xea, 0xe0, 0xf0, 0xd5, 0x41, 0xf7, 0x1d, 0xd4, 0x80, 0xd9, 0x83, 0xdd, 0x67, 0xfb, 0xdd,
+	0x1a, 0x5a, 0x86, 0xc5, 0xa7, 0xbb, 0xcf, 0x5f, 0x78, 0xee, 0xfe, 0xd3, 0xfd, 0xdd, 0xe7, 0xfb,
+	0x5f, 0x77, 0x67, 0x50, 0x07, 0x60, 0xef, 0xf1, 0xae, 0xfb, 0xc2, 0x93, 0x24, 0x75, 0xe7, 0x47,
+	0xd0, 0x4c, 0xf5, 0x83, 0x16, 0xa0, 0xbe, 0xfb, 0x7c, 0x4f, 0x2d, 0xf1, 0xf5, 0xfe, 0xf3, 0xbd,
+	0x6e, 0xcd, 0xf9, 0x43, 0x0d, 0x56, 0xf3, 0xee, 0xc0, 0x92, 0x38, 0x62, 0x44, 0xf8, 0x83, 0x1f,
+	0x0f, 0xa3, 0xd4, 0x1f, 0x24, 0x80, 0x10, 0xcc, 0x46, 0xe4, 0xdc, 0x78, 0x83, 0xfc, 0x16, 0x94,
+	0x3c, 0xe6, 0x38, 0x94, 0x9e, 0x50, 0x77, 0x15, 0x80, 0x7e, 0x0a, 0x0d, 0xad, 0x66, 0x66, 0xcd,
+	0x6e, 0xd6, 0xb7, 0x5a, 0x3b, 0x6b, 0x79, 0xe5, 0x6b, 0x8e, 0x6e, 0x4a, 0xe6, 0x78, 0xb0, 0xf1,
+	0x88, 0x18, 0x49, 0x94, 0x6d, 0x8c, 0x77, 0x0a, 0xbe, 0x42, 0xa1, 0x35, 0xcd, 0x57, 0xe8, 0xd2,
+	0x82, 0x05, 0xa3, 0x47, 0x21, 0xce, 0x9c, 0x6b, 0x40, 0xe1, 0x5d, 0x27, 0x04, 0x87, 0xfc, 0x44,
+	0x8a, 0xd4, 0x70, 0x35, 0xe4, 0xfc, 0xa5, 0x06, 0xd6, 0x38, 0x07, 0xbd, 0xe1, 0x32, 0x16, 0x1f,
+	0xc1, 0xac, 0x08, 0x47, 0xb9, 0x7e, 0x6b, 0x07, 0xe5, 0x37, 0xf0, 0x24, 0x3a, 0x8a, 0x5d, 0x39,
+	0x9e, 0xf7, 0x97, 0x7a, 0xd1, 0x5f, 0x3e, 0x49, 0xc5, 0x51, 0x8a, 0xf8, 0xa0, 0xa8, 0x08, 0x16,
+	0x0f, 0xa9, 0x4f, 0x5c, 0x82, 0xfb, 0x41, 0x44, 0x18, 0x4b, 0xe5, 0x1d, 0x64, 0xc5, 0xdd, 0x8b,
+	0x23, 0x4e, 0x22, 0x7e, 0x3d, 0x8d, 0xfc, 0x18, 0x16, 0xc3, 0xe0, 0x8c, 0x78, 0x03, 0x1c, 0x05,
+	0x47, 0x84, 0x71, 0xad, 0x98, 0xb6, 0x40, 0x3e, 0xd3, 0x38, 0xe7, 0x0d, 0xdc, 0x28, 0x61, 0xa7,
+	0xd5, 0xf3, 0x00, 0x16, 0xb4, 0xc0, 0x92, 0xe5, 0x44, 0x73, 0x1a, 0xaa, 0x71, 0x96, 0xca, 0x67,
+	0xf2, 0x2c, 0xff, 0xd6, 0x84, 0xd5, 0x97, 0x49, 0x1f, 0x73, 0x62, 0xe6, 0x5f, 0xb2, 0xbd, 0x5b,
+	0x30, 0x27, 0x23, 0x49, 0x9b, 0x63, 0x59, 0x09, 0x20, 0x51, 0xdb, 0x7b, 0xe2, 0xaf, 0xab, 0xc6,
+	0xd1, 0x1d, 0x98, 0x3f, 0xc3, 0xe1, 0x90, 0x30, 0xab, 0x9e, 0x35, 0x9c, 0xa6, 0x94, 0x69, 0xd9,
+	0xd5, 0x14, 0x68, 0x03, 0x16, 0xfa, 0xf4, 0x42, 0xe4, 0x55, 0x99, 0x8a, 0x1a, 0xee, 0x7c, 0x9f,
+	0x5e, 0xb8, 0x43, 0xa9, 0xb2, 0x7e, 0xc0, 0x70, 0x2f, 0x24, 0xde, 0x49, 0x1c, 0x9f, 0x32, 0x99,
+	0x8d, 0x1a, 0x6e, 0x5b, 0x23, 0x1f, 0x0b, 0x9c, 0x48, 0x05, 0x94, 0xf8, 0x94, 0x60, 0x4e, 0xac,
+	0x79, 0x39, 0x9e, 0xc2, 0xc2, 0x1a, 0x3c, 0x18, 0x90, 0x78, 0xc8, 0x65, 0x0a, 0xa9, 0xbb, 0x06,
+	0x44, 0x37, 0xa1, 0x4d, 0x09, 0x23, 0xdc, 0xd3, 0x52, 0x36, 0xe4, 0xcc, 0x96, 0xc4, 0xfd, 0xa0,
+	0xc4, 0x42, 0x30, 0xfb, 0x16, 0x07, 0x5c, 0x66, 0x90, 0x86, 0x2b, 0xbf, 0xd5, 0xb4, 0x21, 0x23,
+	0x66, 0x1a, 0x98, 0x69, 0x43, 0x46, 0xf4, 0xb4, 0x55, 0x98, 0x3b, 0x8a, 0xa9, 0x4f, 0xac, 0x96,
+	0x1c, 0x53, 0x00, 0xda, 0x84, 0x56, 0x9f, 0x30, 0x9f, 0x06, 0x09, 0x17, 0xbe, 0xd1, 0x96, 0x3a,
+	0xcd, 0xa2, 0x64, 0x4a, 0x1b, 0xf6, 0x0e, 0x62, 0x4e, 0x98, 0xb5, 0xa8, 0xf6, 0x61, 0x60, 0xf4,
+	0x11, 0x2c, 0xf9, 0x21, 0xc1, 0xd1, 0x30, 0xf1, 0xe2, 0xc8, 0x3b, 0xc2, 0x41, 0x68, 0x75, 0x24,
+	0xc9, 0xa2, 0x46, 0x1f, 0x46, 0xdf, 0xe0, 0x20, 0x44, 0x18, 0x16, 0x85, 0x98, 0x9e, 0xde, 0x25,
+	0xb3, 0x96, 0xa4, 0xb7, 0x7f, 0x5e, 0x9e, 0xbe, 0xcb, 0xac, 0xbe, 0xfd, 0x0a, 0x07, 0xfc, 0x85,
+	0x9e, 0xbe, 0x1f, 0x71, 0x7a, 0xe1, 0xb6, 0xdf, 0x66, 0x50, 0x42, 0x2b, 0x71, 0x14, 0x5e, 0x58,
+	0xdd, 0xcd, 0xba, 0xf0, 0x0a, 0xf1, 0x2d, 0x82, 0x9d, 0x71, 0x1a, 0xf8, 0xdc, 0x5a, 0x56, 0xf6,
+	0x53, 0x10, 0xba, 0x05, 0x4b, 0x9a, 0xa7, 0x87, 0x7d, 0x95, 0xca, 0x90, 0xdc, 0x78, 0x47, 0xa3,
+	0x77, 0x15, 0x56, 0x18, 0x3a, 0x88, 0x18, 0xc7, 0x61, 0xa8, 0x8f, 0x9d, 0x15, 0xe5, 0xa8, 0x1a,
+	0xa9, 0x52, 0xe7, 0x2d, 0x58, 0x1a, 0x46, 0x79, 0xb2, 0x55, 0xb5, 0xda, 0x30, 0xca, 0x11, 0xde,
+	0x84, 0xb6, 0x70, 0x17, 0xa3, 0x05, 0x6b, 0x4d, 0x9a, 0xbe, 0x25, 0x70, 0x7a, 0x1b, 0xe8, 0x00,
+	0xe6, 0x43, 0xdc, 0x23, 0x21, 0xb3, 0xd6, 0xa5, 0x86, 0x7e, 0x3e, 0x85, 0x86, 0x9e, 0xca, 0x89,
+	0x4a, 0x37, 0x7a, 0x15, 0x21, 0x5b, 0x7c, 0x46, 0x28, 0x0d, 0xfa, 0xc4, 0x7b, 0x1b, 0x44, 0xfd,
+	0xf8, 0xad, 0xb5, 0xa1, 0x64, 0x33, 0xe8, 0x57, 0x12, 0x8b, 0x1c, 0x6d, 0xa1, 0xa3, 0x98, 0x7a,
+	0xaf, 0xe3, 0x1e, 0xb3, 0x2c, 0xe5, 0x41, 0x02, 0xf9, 0x4d, 0x4c, 0xbf, 0x8d, 0x7b, 0x0c, 0x1d,
+	0xc3, 0x92, 0xa4, 0xf1, 0xe3, 0xa8, 0x1f, 0x08, 0xdf, 0x60, 0xd6, 0x0d, 0x29, 0xe5, 0x97, 0x53,
+	0xda, 0x71, 0x2f, 0x5d, 0x40, 0x49, 0xdb, 0x79, 0x9b, 0x43, 0xda, 0x5f, 0xc1, 0xf2, 0x98, 0xb9,
+	0x51, 0x17, 0xea, 0xa7, 0xe4, 0x42, 0x47, 0xbd, 0xf8, 0x14, 0x1e, 0x2d, 0xdd, 0x5d, 0x06, 0x7d,
+	0xdd, 0x55, 0xc0, 0x67, 0x33, 0xbf, 0xa8, 0xd9, 0x9f, 0x42, 0x2b, 0xa3, 0x8d, 0xab, 0xa6, 0x36,
+	0xb3, 0x53, 0x77, 0x61, 0xa5, 0x44, 0xc4, 0x69, 0x96, 0x70, 0x1e, 0xc3, 0x5a, 0x61, 0xeb, 0xd7,
+	0x4c, 0x94, 0xce, 0x9f, 0xe7, 0x61, 0xdd, 0x8d, 0xc3, 0xb0, 0x87, 0xfd, 0xd3, 0x0a, 0x59, 0x30,
+	0x93, 0xb0, 0x66, 0x2e, 0x4f, 0x58, 0xf5, 0x92, 0x84, 0x95, 0x39, 0x22, 0x66, 0xf3, 0x47, 0x44,
+	0x36, 0x95, 0xcd, 0x4d, 0x4e, 0x65, 0xf3, 0xf9, 0x54, 0x66, 0xf2, 0xd4, 0x42, 0x26, 0x4f, 0xa5,
+	0x49, 0xa8, 0x71, 0x49, 0x12, 0x6a, 0x8e, 0x27, 0xa1, 0x92, 0x44, 0x03, 0x65, 0x89, 0xc6, 0x2f,
+	0x26, 0x9a, 0xd6, 0x65, 0x0e, 0x5a, 0xae, 0xda, 0xca, 0xa9, 0xa6, 0x9d, 0x49, 0x35, 0x1f, 0x40,
+	0x4b, 0xa5, 0x5e, 0x4f, 0x0e, 0xa9, 0x44, 0x09, 0x0a, 0x75, 0x28, 0x08, 0x8a, 0xc1, 0xdf, 0x19,
+	0x0f, 0xfe, 0x92, 0x60, 0x5d, 0xaa, 0x16, 0xac, 0xdd, 0xf1, 0x60, 0x0d, 0xc6, 0x83, 0x75, 0x59,
+	0xea, 0xe2, 0x97, 0x53, 0xeb, 0xe2, 0x7f, 0x1e, 0xae, 0xff, 0x85, 0x98, 0xfb, 0x16, 0x36, 0xc6,
+	0x76, 0x70, 0xdd, 0xa8, 0xfb, 0x3d, 0xc0, 0xda, 0x13, 0x95, 0xb8, 0x0b, 0x41, 0x97, 0x96, 0x19,
+	0xb5, 0xca, 0x65, 0xc6, 0xcc, 0x34, 0x65, 0x46, 0x3d, 0x17, 0xb5, 0x26, 0xc4, 0x67, 0x33, 0x21,
+	0x5e, 0xa9, 0xf4, 0xc8, 0xd5, 0x9c, 0xf3, 0xc5, 0x9a, 0xf3, 0x7d, 0x00, 0x55, 0x2b, 0xc8, 0xc5,
+	0x55, 0x74, 0x36, 0x25, 0xe6, 0x40, 0x57, 0x8a, 0xc6, 0x47, 0x1b, 0xe5, 0x01, 0x9d, 0x2d, 0x3c,
+	0xb6, 0xa0, 0x6b, 0xe4, 0xf1, 0x69, 0x5f, 0xca, 0xa4, 0x23, 0xb3, 0xa3, 0xf1, 0x7b, 0xb4, 0x2f,
+	0xa4, 0x2a, 0x06, 0x79, 0xeb, 0xf2, 0x4a, 0xa3, 0x5d, 0xa8, 0x34, 0x7a, 0xc5, 0xc0, 0x5e, 0x94,
+	0xce, 0xfc, 0x45, 0xb9, 0x33, 0x97, 0x5a, 0xef, 0xca, 0xb8, 0xae, 0x5a, 0xcd, 0x8c, 0xca, 0x8a,
+	0xa5, 0xab, 0xca, 0x8a, 0x6e, 0x69, 0x59, 0x71, 0x1b, 0xba, 0x2a, 0x7b, 0x7a, 0x23, 0x33, 0xa9,
+	0x0a, 0x65, 0x49, 0xe1, 0x0f, 0x52, 0x63, 0x7d, 0x08, 0x1d, 0x8e, 0x4f, 0x89, 0x17, 0xbf, 0x8d,
+	0x08, 0x65, 0x27, 0x41, 0x22, 0x2b, 0x95, 0x86, 0xbb, 0x28, 0xb0, 0x87, 0x06, 0x89, 0xde, 0x85,
+	0x26, 0x3b, 0x0d, 0x12, 0x61, 0x03, 0x66, 0xad, 0x68, 0xdd, 0x9d, 0x06, 0xc9, 0x1e, 0xed, 0xb3,
+	0xf1, 0x2a, 0x66, 0xb5, 0x5a, 0x15, 0xb3, 0x56, 0xa9, 0x8a, 0x59, 0x1f, 0x4f, 0x64, 0x87, 0x69,
+	0x15, 0xb3, 0x21, 0xad, 0xf4, 0xc9, 0x34, 0x56, 0xaa, 0x58, 0xc6, 0x58, 0xd5, 0x32, 0xe3, 0x8d,
+	0xf1, 0xcc, 0x78, 0x32, 0x9e, 0x19, 0x6d, 0x29, 0xe6, 0x57, 0xd3, 0x3a, 0xd3, 0xff, 0x79, 0x1d,
+	0xf3, 0x04, 0xd6, 0x8b, 0x7b, 0xbf, 0x6e, 0x4a, 0xfd, 0xd3, 0x0c, 0x6c, 0xbc, 0x34, 0x7e, 0x54,
+	0xa1, 0x92, 0x19, 0x4b, 0x73, 0x33, 0x25, 0x69, 0x6e, 0x15, 0xe6, 0x92, 0x21, 0x3d, 0x26, 0x3a,
+	0x6d, 0x2a, 0x20, 0x9b, 0xbf, 0x66, 0xf3, 0xf9, 0xab, 0x90, 0x81, 0xe6, 0xc6, 0x33, 0x90, 0x05,
+	0x0b, 0x3e, 0x66, 0x3e, 0xee, 0x9b, 0xb4, 0x69, 0xc0, 0x51, 0xe1, 0xb2, 0x90, 0x2d, 0x5c, 0x8a,
+	0xb1, 0xd0, 0xa8, 0x74, 0xa8, 0x37, 0xcb, 0x5c, 0xd7, 0xf1, 0xc0, 0x1a, 0xd7, 0xd0, 0x75, 0x6f,
+	0xd8, 0x28, 0xd3, 0x9d, 0x68, 0xaa, 0x4e, 0x84, 0xb3, 0x02, 0xcb, 0x8f, 0x08, 0xd7, 0xdd, 0x24,
+	0xad, 0x7c, 0x67, 0x1f, 0x50, 0x16, 0x39, 0xe2, 0xa7, 0x51, 0x79, 0x7e, 0xa6, 0x5f, 0x68, 0xe8,
+	0x0d, 0x95, 0xf3, 0xa9, 0x5c, 0xfb, 0x71, 0xc0, 0x78, 0x4c, 0x2f, 0x2e, 0x33, 0x6c, 0x17, 0xea,
+	0x03, 0x7c, 0xae, 0x7b, 0x10, 0xe2, 0xd3, 0x79, 0x04, 0x28, 0x3b, 0x55, 0x4b, 0x90, 0xed, 0x11,
+	0xd5, 0xaa, 0xf5, 0x88, 0xfe, 0x5a, 0x03, 0xf4, 0x82, 0xa4, 0xfd, 0xaa, 0x2b, 0xba, 0x21, 0xc6,
+	0x64, 0x33, 0x79, 0x1f, 0x11, 0x1e, 0xa0, 0x92, 0xbd, 0xf6, 0x2a, 0x03, 0x8a, 0xd3, 0x29, 0xc1,
+	0x14, 0x87, 0x21, 0x09, 0x75, 0x3b, 0x20, 0x85, 0x85, 0x67, 0x99, 0xef, 0x80, 0x0d, 0xa4, 0x67,
+	0x2d, 0xba, 0x59, 0x94, 0x90, 0x22, 0x8c, 0x8f, 0x99, 0xee, 0x04, 0xc8, 0x6f, 0xe7, 0x0d, 0xac,
+	0xe4, 0xe4, 0xd5, 0x5b, 0x17, 0x2a, 0x62, 0xc7, 0x26, 0x44, 0x07, 0xec, 0x18, 0xfd, 0x4c, 0x1c,
+	0x38, 0x98, 0x0f, 0x55, 0x18, 0x74, 0x76, 0xde, 0xcb, 0xab, 0x42, 0x2e, 0x32, 0x8c, 0x74, 0xcf,
+	0xd2, 0xd5, 0xb4, 0x29, 0x4b, 0xd5, 0x74, 0x52, 0x2c, 0xef, 0xc2, 0xda, 0x2b, 0xcc, 0xfd, 0x93,
+	0x51, 0x43, 0x69, 0xb2, 0x96, 0x9c, 0x57, 0xb0, 0x5e, 0x24, 0xd6, 0x22, 0x7e, 0x01, 0x4d, 0x6a,
+	0x90, 0xda, 0x43, 0xae, 0xec, 0x5c, 0x8d, 0x66, 0x38, 0xff, 0xaa, 0xc3, 0x7b, 0xb9, 0x1b, 0xd2,
+	0x33, 0xc2, 0x71, 0x1f, 0x73, 0x7c, 0xbd, 0x0e, 0xd6, 0x0f, 0xe9, 0x71, 0x53, 0xaf, 0x7c, 0x1d,
+	0x2d, 0x70, 0x2c, 0x3d, 0x75, 0x08, 0xb4, 0x70, 0x14, 0xc5, 0x1c, 0xab, 0x43, 0x42, 0x75, 0xe8,
+	0xf6, 0xae, 0xb1, 0xf8, 0xee, 0x68, 0x15, 0xc5, 0x21, 0xbb, 0xae, 0xc8, 0x75, 0x94, 0x0c, 0xe2,
+	0x33, 0xe2, 0xe9, 0x5d, 0xcc, 0xc9, 0x7b, 0x45, 0x5b, 0x21, 0x95, 0x60, 0xe8, 0x3e, 0x20, 0x4d,
+	0x94, 0x15, 0x69, 0x5e, 0x52, 0x2e, 0xab, 0x91, 0x0c, 0x17, 0x51, 0x01, 0x26, 0x34, 0x4e, 0xf0,
+	0x31, 0xe6, 0x69, 0x89, 0x97, 0x22, 0xfe, 0x93, 0x63, 0xe5, 0x4b, 0xe8, 0x16, 0x77, 0x33, 0xd5,
+	0x99, 0xf2, 0x1d, 0xbc, 0x3f, 0x41, 0x55, 0xd7, 0x3d, 0x5a, 0x8e, 0x61, 0xed, 0x19, 0x4e, 0x34,
+	0x7a, 0xf7, 0xbb, 0x27, 0x97, 0x36, 0x86, 0x6f, 0x42, 0xfb, 0x74, 0xd8, 0x23, 0x5e, 0xd6, 0x93,
+	0x9a, 0x6e, 0x4b, 0xe0, 0x74, 0x2a, 0x9b, 0x58, 0x8e, 0x3b, 0x04, 0xd6, 0x8b, 0x8c, 0xae, 0x9b,
+	0x9e, 0x6d, 0x68, 0x0c, 0x70, 0x92, 0x04, 0xd1, 0xb1, 0x08, 0x69, 0x61, 0xc3, 0x14, 0x76, 0xbe,
+	0x87, 0xf5, 0x47, 0x84, 0xef, 0xe1, 0x04, 0xf7, 0x82, 0x30, 0xe0, 0xc1, 0xe8, 0x1d, 0xc6, 0x12,
+	0x6c, 0x8e, 0x28, 0x61, 0x27, 0x92, 0x4d, 0xc3, 0x35, 0x60, 0xe1, 0x69, 0x61, 0xa6, 0xf0, 0xb4,
+	0xe0, 0xfc, 0xb3, 0x06, 0x1b, 0x63, 0x6b, 0x6a, 0xd9, 0x8b, 0x1a, 0xa9, 0x8d, 0x6b, 0xe4, 0x26,
+	0xb4, 0x71, 0x12, 0x18, 0x0a, 0x23, 0x71, 0x0b, 0x27, 0x81, 0xa6, 0x60, 0xc2, 0x05, 0xb0, 0x3e,
+	0x88, 0xeb, 0xae, 0xf8, 0x44, 0xf7, 0x00, 0x0d, 0xf0, 0x79, 0xda, 0xe2, 0xf5, 0x7a, 0x17, 0x5c,
+	0xb6, 0xfb, 0x05, 0x41, 0x77, 0x80, 0xcf, 0x4d, 0x9f, 0xf7, 0xa1, 0xc0, 0x8b, 0xeb, 0xb3, 0xa0,
+	0x8e, 0x7b, 0xaf, 0x89, 0xcf, 0xd5, 0xa5, 0xa6, 0xee, 0xc2, 0x00, 0x9f, 0x1f, 0x2a, 0x8c, 0x28,
+	0x70, 0x05, 0x81, 0x2a, 0x06, 0x54, 0xa3, 0xa1, 0x31, 0xc0, 0xe7, 0xb2, 0x10, 0x70, 0x3e, 0x93,
+	0x47, 0xc8, 0xee, 0xb0, 0x1f, 0xf0, 0xa7, 0xf1, 0xf1, 0x74, 0xc7, 0xcf, 0xf7, 0xb0, 0x92, 0x9b,
+	0xab, 0xd5, 0xf2, 0x19, 0x2c, 0x90, 0x88, 0xd3, 0x20, 0x3d, 0x7e, 0x36, 0xcb, 0xe3, 0x5e, 0x4e,
+	0x54, 0x41, 0x6d, 0x26, 0x38, 0x7f, 0x9f, 0x01, 0x18, 0xe1, 0x85, 0x1c, 0x3c, 0x18, 0xc9, 0x21,
+	0xbe, 0x45, 0x7c, 0xc6, 0x09, 0xa1, 0x32, 0x8a, 0x8c, 0xbd, 0x52, 0x84, 0x32, 0xb4, 0xf2, 0x27,
+	0x95, 0xbc, 0x0d, 0x98, 0xbf, 0xd9, 0xcd, 0x96, 0xbc, 0x3e, 0x51, 0x72, 0x16, 0x30, 0x53, 0xdd,
+	0xcc, 0xb9, 0x29, 0x2c, 0xa4, 0x18, 0x32, 0x42, 0x75, 0x5d, 0x23, 0xbf, 0xc5, 0x45, 0xc6, 0x0f,
+	0x03, 0x12, 0x71, 0xfd, 0x90, 0xa5, 0x21, 0xf9, 0xc0, 0x23, 0xaf, 0xb9, 0xea, 0x09, 0x4b, 0x01,
+	0x22, 0x4f, 0xe9, 0x16, 0x47, 0x3f, 0x38, 0x26, 0x8c, 0xeb, 0x3a, 0xa6, 0xad, 0x90, 0x5f, 0x4b,
+	0x9c, 0x10, 0x3d, 0x1e, 0x72, 0x3f, 0x1e, 0x10, 0xfd, 0x82, 0x65, 0x40, 0xb1, 0x28, 0xa1, 0x34,
+	0xa6, 0xfa, 0xe6, 0xa7, 0x00, 0x51, 0x1e, 0xa9, 0xaa, 0xc8, 0x33, 0xe5, 0x90, 0xee, 0x41, 0x77,
+	0x14, 0xfa, 0x50, 0x63, 0x9d, 0x3b, 0xb0, 0xba, 0x87, 0x23, 0x9f, 0x54, 0xa8, 0x1e, 0x9d, 0x43,
+	0x58, 0x2b, 0xd0, 0x6a, 0xab, 0xe6, 0xd4, 0x5e, 0x2b, 0x51, 0x3b, 0xe3, 0x98, 0x72, 0xd2, 0xd7,
+	0x26, 0x31, 0xa0, 0x60, 0xfe, 0x32, 0x0a, 0xe3, 0x2a, 0x4d, 0x38, 0xe7, 0x77, 0x35, 0x58, 0x2b,
+	0x10, 0x57, 0xe2, 0x2e, 0x5e, 0xa6, 0xe2, 0x50, 0xdc, 0xbb, 0xf4, 0x7b, 0xaa, 0x82, 0x84, 0x51,
+	0xb1, 0xff, 0x66, 0x18, 0x50, 0xd2, 0xd7, 0xde, 0x90, 0xc2, 0x42, 0x62, 0x72, 0x9e, 0x04, 0x54,
+	0x47, 0x56, 0xd3, 0x35, 0xa0, 0xf3, 0x1b, 0x58, 0x7d, 0x32, 0x48, 0x62, 0x5a, 0xa5, 0x1a, 0xca,
+	0xd6, 0x5a, 0x33, 0xd5, 0x6a, 0xad, 0xc7, 0xb0, 0x56, 0x58, 0xfe, 0x9a, 0xa9, 0x70, 0xe7, 0x1f,
+	0x4b, 0xd0, 0x31, 0xaf, 0x6e, 0x2a, 0xb6, 0x50, 0x00, 0xed, 0xec, 0xbb, 0x23, 0xba, 0x3d, 0xf9,
+	0x95, 0xb7, 0xf0, 0x54, 0x6d, 0xdf, 0xa9, 0x42, 0xaa, 0x44, 0x75, 0xde, 0xf9, 0x49, 0x0d, 0x31,
+	0xe8, 0x16, 0x5f, 0xfd, 0xd0, 0xfd, 0xf2, 0x35, 0x26, 0xbc, 0x3f, 0xda, 0xdb, 0x55, 0xc9, 0x0d,
+	0x5b, 0x74, 0x06, 0xcb, 0xa3, 0x51, 0xfd, 0x98, 0x86, 0xae, 0x5c, 0x26, 0xff, 0xc8, 0x67, 0x3f,
+	0xa8, 0x4c, 0x9f, 0xf2, 0x7d, 0x0d, 0x8b, 0xb9, 0xb3, 0x17, 0xdd, 0xa9, 0xde, 0xb7, 0xb7, 0xef,
+	0x56, 0xa2, 0x4d, 0x79, 0x0d, 0xa0, 0x93, 0xbf, 0x3b, 0xa2, 0xbb, 0x53, 0xdc, 0xae, 0xed, 0x7b,
+	0xd5, 0x88, 0x53, 0x76, 0x0c, 0xba, 0xc5, 0xcb, 0xd3, 0x24, 0x3b, 0x4e, 0xb8, 0x86, 0xda, 0xdb,
+	0x55, 0xc9, 0x53, 0xa6, 0x18, 0x60, 0x74, 0x77, 0x42, 0xb7, 0x26, 0x1a, 0x24, 0x7f, 0xe5, 0xb2,
+	0xb7, 0xae, 0x26, 0x4c, 0x59, 0x24, 0xb0, 0x54, 0x68, 0x6b, 0xa2, 0x7b, 0xd3, 0xf4, 0x6f, 0xed,
+	0xfb, 0x15, 0xa9, 0x0b, 0x9b, 0xd2, 0xd7, 0xb1, 0x4b, 0x36, 0x95, 0xbf, 0xeb, 0xd9, 0x5b, 0x57,
+	0x13, 0xa6, 0x2c, 0x02, 0xe8, 0xb8, 0xc3, 0x48, 0xb3, 0x16, 0x97, 0x17, 0x34, 0x61, 0xf6, 0xf8,
+	0x6d, 0xce, 0xbe, 0x5d, 0x81, 0x32, 0x13, 0xdf, 0x31, 0x74, 0xf2, 0x57, 0x98, 0x49, 0x6e, 0x58,
+	0x7a, 0x2b, 0xb2, 0xef, 0x55, 0x23, 0xce, 0x30, 0xfc, 0x6d, 0x0d, 0xd6, 0x4a, 0x0b, 0x5c, 0xb4,
+	0x33, 0xfd, 0xc5, 0xc1, 0xfe, 0x78, 0xaa, 0x39, 0xd9, 0xe0, 0xcb, 0x57, 0xaa, 0x93, 0x76, 0x5d,
+	0x5a, 0x38, 0xdb, 0xf7, 0xaa, 0x11, 0x67, 0x9d, 0xb4, 0x50, 0x5d, 0x4e, 0x72, 0xd2, 0xf2, 0xc2,
+	0xd6, 0xbe, 0x5f, 0x91, 0x3a, 0xe5, 0xd8, 0x87, 0x56, 0xa6, 0x68, 0x43, 0x93, 0x9d, 0xaf, 0x50,
+	0x13, 0xda, 0xb7, 0x2b, 0x50, 0x66, 0xf3, 0x65, 0xae, 0x8c, 0x98, 0x94, 0x2f, 0xcb, 0xea, 0x12,
+	0xfb, 0x6e, 0x25, 0xda, 0x5c, 0x6e, 0xce, 0x16, 0x0d, 0x13, 0x73, 0x73, 0x49, 0x19, 0x62, 0xdf,
+	0xad, 0x44, 0x9b, 0xe5, 0x95, 0x3b, 0xbc, 0x27, 0xf1, 0x2a, 0x2b, 0x20, 0xec, 0xbb, 0x95, 0x68,
+	0x0d, 0xaf, 0x87, 0xf0, 0xeb, 0x86, 0x21, 0xed, 0xcd, 0xcb, 0x7f, 0x33, 0xfb, 0xf8, 0xdf, 0x03,
+	0x00, 0x97, 0xdb, 0x42, 0x30, 0x54, 0x27, 0x00, 0x00,
 }
@@ -0,0 +1,99 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// The results of a verification, as recorded in a Report.
+const (
+	ResultVerified = "verified"
+	ResultFailed   = "failed"
+)
+
+// Report is a machine-readable record of the verification of a chart.
+//
+// It is meant to be archived alongside the chart, as evidence that the chart
+// was verified before being used.
+type Report struct {
+	// Chart is the file name of the chart archive.
+	Chart string `json:"chart"`
+	// Digest is the digest of the chart archive, prepended with the scheme.
+	Digest string `json:"digest,omitempty"`
+	// Signer is the identity of the signer, as given by Verification.SignedBy.
+	Signer string `json:"signer,omitempty"`
+	// Fingerprint is the fingerprint of the primary key of the signer.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Signers lists every signer whose signature was verified.
+	Signers []ReportSigner `json:"signers,omitempty"`
+	// Timestamp is the time of the verification.
+	Timestamp time.Time `json:"timestamp"`
+	// Result is either ResultVerified or ResultFailed.
+	Result string `json:"result"`
+	// Error is the reason of the failure, if the verification failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ReportSigner is a signer recorded in a Report.
+type ReportSigner struct {
+	Name string `json:"name"`
+	// Fingerprint is empty for the signers not verified against a keyring.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// NewReport records the outcome of the verification of a chart archive.
+//
+// The verification and the error are the ones returned by the verification. If
+// the verification failed before the archive was hashed, the digest of the
+// archive is computed, so that the report identifies the rejected chart.
+func NewReport(chartpath string, v *Verification, err error) *Report {
+	r := &Report{
+		Chart:     filepath.Base(chartpath),
+		Timestamp: time.Now().UTC(),
+		Result:    ResultVerified,
+	}
+	if err != nil {
+		r.Result = ResultFailed
+		r.Error = err.Error()
+	}
+	if v != nil {
+		r.Digest = v.FileHash
+		for _, s := range v.Signers {
+			r.Signers = append(r.Signers, ReportSigner{Name: s.Name, Fingerprint: fingerprint(s)})
+		}
+		if len(v.Signers) > 0 && v.Signers[0].Entity == v.SignedBy {
+			r.Signer = v.Signers[0].Name
+			r.Fingerprint = fingerprint(v.Signers[0])
+		}
+	}
+	if r.Digest == "" {
+		if sum, err := DigestFile(chartpath); err == nil {
+			r.Digest = "sha256:" + sum
+		}
+	}
+	return r
+}
+
+func fingerprint(s *Signer) string {
+	if s.Entity == nil || s.Entity.PrimaryKey == nil {
+		return ""
+	}
+	return fmt.Sprintf("%X", s.Entity.PrimaryKey.Fingerprint)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewReport(t *testing.T) {
+	signer, err := NewFromFiles(testKeyfile, testPubfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := readSumFile(testSumfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ver, err := signer.Verify(testChartfile, testSigBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewReport(testChartfile, ver, nil)
+	if r.Result != ResultVerified || r.Error != "" {
+		t.Errorf("expected a verified report, got %q (%s)", r.Result, r.Error)
+	}
+	if r.Chart != "hashtest-1.2.3.tgz" {
+		t.Errorf("unexpected chart %q", r.Chart)
+	}
+	if r.Digest != "sha256:"+sum {
+		t.Errorf("expected digest sha256:%s, got %s", sum, r.Digest)
+	}
+	if r.Signer != testKeyName {
+		t.Errorf("expected signer %q, got %q", testKeyName, r.Signer)
+	}
+	fp := fmt.Sprintf("%X", signer.Entity.PrimaryKey.Fingerprint)
+	if r.Fingerprint != fp {
+		t.Errorf("expected fingerprint %s, got %s", fp, r.Fingerprint)
+	}
+	if len(r.Signers) != 1 || r.Signers[0].Fingerprint != fp {
+		t.Errorf("unexpected signers %v", r.Signers)
+	}
+	if r.Timestamp.IsZero() {
+		t.Error("expected a timestamp")
+	}
+
+	ver, err = signer.Verify(testChartfile, testTamperedSigBlock)
+	if err == nil {
+		t.Fatal("expected the tampered signature to fail")
+	}
+	r = NewReport(testChartfile, ver, err)
+	if r.Result != ResultFailed || r.Error != err.Error() {
+		t.Errorf("expected a failed report, got %q (%s)", r.Result, r.Error)
+	}
+	if r.Digest != "sha256:"+sum {
+		t.Errorf("expected the digest of the rejected chart, got %q", r.Digest)
+	}
+	if r.Signer != "" || r.Fingerprint != "" || len(r.Signers) != 0 {
+		t.Errorf("expected no signer, got %+v", r)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+// Signer identifies the signer of a provenance signature.
+type Signer struct {
+	// Name is the identity of the signer, as reported by the verifier.
+	Name string
+	// Entity is the PGP entity that signed, if the signature was verified
+	// against a keyring.
+	Entity *openpgp.Entity
+}
+
+// Verifier verifies the signatures of provenance files.
+//
+// A provenance file holds one or more clear-signed blocks. VerifyBlock is given
+// one of them, armored as it is found in the file, and returns the signer if
+// it vouches for it. Signatory verifies blocks against a PGP keyring; other
+// implementations can integrate keyless signing or transparency logs.
+type Verifier interface {
+	VerifyBlock(block []byte) (*Signer, error)
+}
+
+// Policy tells how many of the signatures of a provenance file must be verified.
+type Policy int
+
+const (
+	// RequireAny requires at least one signature to be verified. The signatures
+	// that no verifier vouches for are ignored.
+	RequireAny Policy = iota
+	// RequireAll requires every signature to be verified.
+	RequireAll
+)
+
+// VerifyBlock verifies a clear-signed block against the keyring of the Signatory.
+func (s *Signatory) VerifyBlock(data []byte) (*Signer, error) {
+	block, _ := clearsign.Decode(data)
+	if block == nil {
+		return nil, errors.New("signature block not found")
+	}
+	by, err := s.verifySignature(block)
+	if err != nil {
+		return nil, err
+	}
+	signer := &Signer{Entity: by}
+	for name := range by.Identities {
+		signer.Name = name
+		break
+	}
+	return signer, nil
+}
+
+// ExecVerifier verifies signatures by running an external program.
+//
+// The program is given the signature block on its standard input. It must exit
+// with a zero status if the signature is valid, after printing the identity of
+// the signer on its standard output.
+type ExecVerifier struct {
+	// Command is the program to run, followed by its arguments.
+	Command []string
+}
+
+// VerifyBlock runs the program of the verifier on a clear-signed block.
+func (e *ExecVerifier) VerifyBlock(block []byte) (*Signer, error) {
+	if len(e.Command) == 0 {
+		return nil, errors.New("no verifier command given")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(e.Command[0], e.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(block)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", e.Command[0], msg)
+		}
+		return nil, fmt.Errorf("%s: %s", e.Command[0], err)
+	}
+	return &Signer{Name: strings.TrimSpace(stdout.String())}, nil
+}
+
+// VerifyWithPolicy checks the signatures of a provenance file with the given
+// verifiers, and verifies that the provenance file is legit for the chart.
+//
+// Each signature is verified by the first verifier that vouches for it. All the
+// signatures of the file must sign the same message.
+func VerifyWithPolicy(chartpath, sigpath string, policy Policy, verifiers ...Verifier) (*Verification, error) {
+	ver := &Verification{}
+	for _, fname := range []string{chartpath, sigpath} {
+		if fi, err := os.Stat(fname); err != nil {
+			return ver, err
+		} else if fi.IsDir() {
+			return ver, fmt.Errorf("%s cannot be a directory", fname)
+		}
+	}
+	if len(verifiers) == 0 {
+		return ver, errors.New("no signature verifier given")
+	}
+
+	data, err := ioutil.ReadFile(sigpath)
+	if err != nil {
+		return ver, err
+	}
+	blocks, plaintext, err := splitSignatures(data)
+	if err != nil {
+		return ver, fmt.Errorf("failed to decode signature: %s", err)
+	}
+
+	// First verify the signatures
+	var firstErr error
+	for _, block := range blocks {
+		signer, err := verifyBlock(block, verifiers)
+		if err != nil {
+			if policy == RequireAll {
+				return ver, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		ver.Signers = append(ver.Signers, signer)
+	}
+	if len(ver.Signers) == 0 {
+		return ver, firstErr
+	}
+	ver.SignedBy = ver.Signers[0].Entity
+
+	// Second, verify the hash of the tarball.
+	sum, err := DigestFile(chartpath)
+	if err != nil {
+		return ver, err
+	}
+	_, sums, err := parseMessageBlock(plaintext)
+	if err != nil {
+		return ver, err
+	}
+
+	sum = "sha256:" + sum
+	basename := filepath.Base(chartpath)
+	if sha, ok := sums.Files[basename]; !ok {
+		return ver, fmt.Errorf("provenance does not contain a SHA for a file named %q", basename)
+	} else if sha != sum {
+		return ver, fmt.Errorf("sha256 sum does not match for %s: %q != %q", basename, sha, sum)
+	}
+	ver.FileHash = sum
+	ver.FileName = basename
+
+	return ver, nil
+}
+
+// AppendSignature adds a signature to a provenance file, so that a chart can be
+// signed by several signers. The signature must sign the same message as the
+// ones already in the file.
+func AppendSignature(prov []byte, sig string) (string, error) {
+	if _, _, err := splitSignatures(prov); err != nil {
+		return "", fmt.Errorf("cannot add signature: %s", err)
+	}
+	if _, _, err := splitSignatures(append(append([]byte{}, prov...), sig...)); err != nil {
+		return "", fmt.Errorf("cannot add signature: %s", err)
+	}
+	out := string(prov)
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return out + sig, nil
+}
+
+// verifyBlock returns the signer of a block, as given by the first verifier
+// vouching for it.
+func verifyBlock(block []byte, verifiers []Verifier) (*Signer, error) {
+	var firstErr error
+	for _, v := range verifiers {
+		signer, err := v.VerifyBlock(block)
+		if err == nil {
+			return signer, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// splitSignatures splits the clear-signed blocks of a provenance file, and
+// returns them along with the message they sign.
+func splitSignatures(data []byte) ([][]byte, []byte, error) {
+	var blocks [][]byte
+	var plaintext []byte
+	for {
+		block, rest := clearsign.Decode(data)
+		if block == nil {
+			break
+		}
+		if plaintext == nil {
+			plaintext = block.Plaintext
+		} else if !bytes.Equal(plaintext, block.Plaintext) {
+			return nil, nil, errors.New("signature blocks sign different messages")
+		}
+		blocks = append(blocks, data[:len(data)-len(rest)])
+		data = rest
+	}
+	if len(blocks) == 0 {
+		// There was no sig in the file.
+		return nil, nil, errors.New("signature block not found")
+	}
+	return blocks, plaintext, nil
+}
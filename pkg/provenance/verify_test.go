@@ -0,0 +1,139 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeVerifier vouches for the blocks containing a marker.
+type fakeVerifier struct {
+	marker string
+}
+
+func (f *fakeVerifier) VerifyBlock(block []byte) (*Signer, error) {
+	if !strings.Contains(string(block), f.marker) {
+		return nil, errors.New("unknown signer")
+	}
+	return &Signer{Name: "fake"}, nil
+}
+
+func TestVerifyWithPolicy(t *testing.T) {
+	signer, err := NewFromFiles(testKeyfile, testPubfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewFromKeyring(testPasswordKeyfile, testPasswordKeyName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.DecryptKey(func(s string) ([]byte, error) {
+		return []byte("secret"), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := signer.ClearSign(testChartfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSig, err := other.ClearSign(testChartfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prov, err := AppendSignature([]byte(sig), otherSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "helm-test-sig-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(prov)
+	f.Close()
+
+	// The second signature is not signed by a key of the keyring.
+	ver, err := VerifyWithPolicy(testChartfile, f.Name(), RequireAny, signer)
+	if err != nil {
+		t.Fatalf("Failed to pass verify. Err: %s", err)
+	}
+	if len(ver.Signers) != 1 || ver.Signers[0].Name != testKeyName {
+		t.Errorf("Expected %q to be the only signer, got %v", testKeyName, ver.Signers)
+	}
+	if ver.SignedBy == nil || ver.FileHash == "" {
+		t.Error("Verification is missing the signer or the hash")
+	}
+	if _, err := VerifyWithPolicy(testChartfile, f.Name(), RequireAll, signer); err == nil {
+		t.Error("Expected every signature to be required")
+	}
+
+	// A second verifier vouches for it.
+	ver, err = VerifyWithPolicy(testChartfile, f.Name(), RequireAll, signer, &fakeVerifier{marker: otherSig})
+	if err != nil {
+		t.Fatalf("Failed to pass verify. Err: %s", err)
+	}
+	if len(ver.Signers) != 2 || ver.Signers[1].Name != "fake" {
+		t.Errorf("Expected two signers, got %v", ver.Signers)
+	}
+
+	if _, err := VerifyWithPolicy(testChartfile, f.Name(), RequireAny); err == nil {
+		t.Error("Expected an error without verifiers")
+	}
+}
+
+func TestAppendSignature(t *testing.T) {
+	signer, err := NewFromFiles(testKeyfile, testPubfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := signer.ClearSign(testChartfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The message names the archive, so a copy has a different one.
+	data, err := ioutil.ReadFile(testChartfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "helm-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	copyfile := filepath.Join(dir, "hashtest-copy-1.2.3.tgz")
+	if err := ioutil.WriteFile(copyfile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	copySig, err := signer.ClearSign(copyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AppendSignature([]byte(sig), copySig); err == nil {
+		t.Error("Expected signatures of different messages to be rejected")
+	}
+	if _, err := AppendSignature([]byte("not a signature"), sig); err == nil {
+		t.Error("Expected a provenance file without signature to be rejected")
+	}
+}
@@ -30,6 +30,10 @@ import (
 	"k8s.io/helm/pkg/tiller/environment"
 )
 
+// maxLogBytes is the amount of output kept from the end of the logs of each
+// test pod. The logs are stored with the release, so they need to stay small.
+const maxLogBytes = 16 * 1024
+
 // Environment encapsulates information about where test suite executes and returns results
 type Environment struct {
 	Namespace   string
@@ -38,6 +42,7 @@ type Environment struct {
 	Timeout     int64
 	Parallel    bool
 	Parallelism uint32
+	Logs        bool
 	streamLock  sync.Mutex
 }
 
@@ -66,19 +71,33 @@ func (env *Environment) getTestPodStatus(test *test) (v1.PodPhase, error) {
 	return status, err
 }
 
+func (env *Environment) getTestPodLogs(test *test) {
+	b := bytes.NewBufferString(test.manifest)
+	logs, err := env.KubeClient.GetPodLogs(env.Namespace, b, maxLogBytes)
+	if err != nil {
+		log.Printf("Error getting logs for pod %s: %s", test.result.Name, err)
+		return
+	}
+	test.result.Logs = logs
+}
+
 func (env *Environment) streamResult(r *release.TestRun) error {
+	var logs string
+	if env.Logs {
+		logs = r.Logs
+	}
 	switch r.Status {
 	case release.TestRun_SUCCESS:
-		if err := env.streamSuccess(r.Name); err != nil {
+		if err := env.streamSuccess(r.Name, logs); err != nil {
 			return err
 		}
 	case release.TestRun_FAILURE:
-		if err := env.streamFailed(r.Name); err != nil {
+		if err := env.streamFailed(r.Name, logs); err != nil {
 			return err
 		}
 
 	default:
-		if err := env.streamUnknown(r.Name, r.Info); err != nil {
+		if err := env.streamUnknown(r.Name, r.Info, logs); err != nil {
 			return err
 		}
 	}
@@ -95,23 +114,26 @@ func (env *Environment) streamError(info string) error {
 	return env.streamMessage(msg, release.TestRun_FAILURE)
 }
 
-func (env *Environment) streamFailed(name string) error {
+func (env *Environment) streamFailed(name, logs string) error {
 	msg := fmt.Sprintf("FAILED: %s, run `kubectl logs %s --namespace %s` for more info", name, name, env.Namespace)
-	return env.streamMessage(msg, release.TestRun_FAILURE)
+	return env.streamResponse(&services.TestReleaseResponse{Msg: msg, Status: release.TestRun_FAILURE, Logs: logs})
 }
 
-func (env *Environment) streamSuccess(name string) error {
+func (env *Environment) streamSuccess(name, logs string) error {
 	msg := fmt.Sprintf("PASSED: %s", name)
-	return env.streamMessage(msg, release.TestRun_SUCCESS)
+	return env.streamResponse(&services.TestReleaseResponse{Msg: msg, Status: release.TestRun_SUCCESS, Logs: logs})
 }
 
-func (env *Environment) streamUnknown(name, info string) error {
+func (env *Environment) streamUnknown(name, info, logs string) error {
 	msg := fmt.Sprintf("UNKNOWN: %s: %s", name, info)
-	return env.streamMessage(msg, release.TestRun_UNKNOWN)
+	return env.streamResponse(&services.TestReleaseResponse{Msg: msg, Status: release.TestRun_UNKNOWN, Logs: logs})
 }
 
 func (env *Environment) streamMessage(msg string, status release.TestRun_Status) error {
-	resp := &services.TestReleaseResponse{Msg: msg, Status: status}
+	return env.streamResponse(&services.TestReleaseResponse{Msg: msg, Status: status})
+}
+
+func (env *Environment) streamResponse(resp *services.TestReleaseResponse) error {
 	env.streamLock.Lock()
 	defer env.streamLock.Unlock()
 	return env.Stream.Send(resp)
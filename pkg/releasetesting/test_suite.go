@@ -146,6 +146,7 @@ func (t *test) run(env *Environment) error {
 	}
 
 	if resourceCreated && resourceCleanExit {
+		env.getTestPodLogs(t)
 		if err := t.assignTestResult(status); err != nil {
 			return err
 		}
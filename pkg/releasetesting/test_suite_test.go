@@ -210,6 +210,30 @@ func TestRunSuccessWithTestFailureHook(t *testing.T) {
 	}
 }
 
+func TestRunCapturesLogs(t *testing.T) {
+	ts := testSuiteFixture([]string{manifestWithTestSuccessHook})
+	env := testEnvFixture()
+	env.Logs = true
+	env.KubeClient = newPodLogsKubeClient("just keep swimming")
+	if err := ts.Run(env); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if ts.Results[0].Logs != "just keep swimming" {
+		t.Errorf("Expected the test logs to be stored, got: %q", ts.Results[0].Logs)
+	}
+
+	var streamed bool
+	for _, m := range env.Stream.(*mockStream).messages {
+		if m.Status == release.TestRun_SUCCESS && m.Logs == "just keep swimming" {
+			streamed = true
+		}
+	}
+	if !streamed {
+		t.Error("Expected the test logs to be streamed with the result")
+	}
+}
+
 func TestExtractTestManifestsFromHooks(t *testing.T) {
 	rel := releaseStub()
 	testManifests, err := extractTestManifestsFromHooks(rel.Hooks)
@@ -464,3 +488,23 @@ func newPodFailedKubeClient() *podFailedKubeClient {
 func (p *podFailedKubeClient) WaitAndGetCompletedPodPhase(ns string, r io.Reader, timeout time.Duration) (v1.PodPhase, error) {
 	return v1.PodFailed, nil
 }
+
+type podLogsKubeClient struct {
+	tillerEnv.PrintingKubeClient
+	logs string
+}
+
+func newPodLogsKubeClient(logs string) *podLogsKubeClient {
+	return &podLogsKubeClient{
+		PrintingKubeClient: tillerEnv.PrintingKubeClient{Out: ioutil.Discard},
+		logs:               logs,
+	}
+}
+
+func (p *podLogsKubeClient) WaitAndGetCompletedPodPhase(ns string, r io.Reader, timeout time.Duration) (v1.PodPhase, error) {
+	return v1.PodSucceeded, nil
+}
+
+func (p *podLogsKubeClient) GetPodLogs(ns string, r io.Reader, limitBytes int64) (string, error) {
+	return p.logs, nil
+}
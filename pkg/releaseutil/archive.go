@@ -0,0 +1,196 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+const (
+	// ArchiveAPIVersionV1 is the version of the release archive format.
+	ArchiveAPIVersionV1 = "v1"
+	// ArchiveMetadataFile is the name of the file describing a release archive.
+	ArchiveMetadataFile = "release.yaml"
+	// ArchiveRevisionsDir is the directory of the revisions of a release archive.
+	ArchiveRevisionsDir = "revisions"
+)
+
+// ArchiveMetadata describes the contents of a release archive.
+type ArchiveMetadata struct {
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	// Revisions are the revision numbers of the release in the archive.
+	Revisions []int32 `json:"revisions"`
+}
+
+// revisionFile is the path of a revision in a release archive, named like the
+// record of the revision in the storage of Tiller.
+func revisionFile(rls *rspb.Release) string {
+	return path.Join(ArchiveRevisionsDir, fmt.Sprintf("%s.v%d", rls.Name, rls.Version))
+}
+
+// WriteArchive writes the revisions of a release to w as a gzipped tar archive,
+// holding a release.yaml file describing it and a protobuf encoded record per
+// revision. The revisions are written in ascending order.
+func WriteArchive(w io.Writer, rels []*rspb.Release) error {
+	if err := checkRevisions(rels); err != nil {
+		return err
+	}
+	rels = append([]*rspb.Release(nil), rels...)
+	SortByRevision(rels)
+
+	md := &ArchiveMetadata{
+		APIVersion: ArchiveAPIVersionV1,
+		Name:       rels[0].Name,
+		Namespace:  rels[0].Namespace,
+	}
+	names := []string{ArchiveMetadataFile}
+	files := map[string][]byte{}
+	for _, rls := range rels {
+		data, err := proto.Marshal(rls)
+		if err != nil {
+			return err
+		}
+		name := revisionFile(rls)
+		names = append(names, name)
+		files[name] = data
+		md.Revisions = append(md.Revisions, rls.Version)
+	}
+	data, err := yaml.Marshal(md)
+	if err != nil {
+		return err
+	}
+	files[ArchiveMetadataFile] = data
+
+	zipper := gzip.NewWriter(w)
+	tw := tar.NewWriter(zipper)
+	for _, name := range names {
+		h := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			ModTime:  time.Unix(0, 0),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return zipper.Close()
+}
+
+// ReadArchive reads the revisions of a release from an archive written by
+// WriteArchive. They are returned in ascending order.
+func ReadArchive(r io.Reader) ([]*rspb.Release, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a release archive: %s", err)
+	}
+	defer zr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not a release archive: %s", err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if files[path.Clean(h.Name)], err = ioutil.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+
+	data, ok := files[ArchiveMetadataFile]
+	if !ok {
+		return nil, fmt.Errorf("not a release archive: no %s", ArchiveMetadataFile)
+	}
+	var md ArchiveMetadata
+	if err := yaml.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("cannot load %s of release archive: %s", ArchiveMetadataFile, err)
+	}
+	if md.APIVersion != ArchiveAPIVersionV1 {
+		return nil, fmt.Errorf("release archive has unsupported apiVersion %q", md.APIVersion)
+	}
+
+	var rels []*rspb.Release
+	for _, v := range md.Revisions {
+		want := &rspb.Release{Name: md.Name, Version: v}
+		data, ok := files[revisionFile(want)]
+		if !ok {
+			return nil, fmt.Errorf("release archive has no revision %d of %s", v, md.Name)
+		}
+		rls := &rspb.Release{}
+		if err := proto.Unmarshal(data, rls); err != nil {
+			return nil, fmt.Errorf("cannot load revision %d of %s: %s", v, md.Name, err)
+		}
+		if rls.Name != md.Name || rls.Version != v {
+			return nil, fmt.Errorf("release archive has %s revision %d in place of %s revision %d", rls.Name, rls.Version, md.Name, v)
+		}
+		rels = append(rels, rls)
+	}
+	if err := checkRevisions(rels); err != nil {
+		return nil, err
+	}
+	SortByRevision(rels)
+	return rels, nil
+}
+
+// checkRevisions checks that rels are distinct revisions of the same release.
+func checkRevisions(rels []*rspb.Release) error {
+	if len(rels) == 0 {
+		return errors.New("no revisions of a release")
+	}
+	seen := map[int32]bool{}
+	for _, rls := range rels {
+		if rls.Name != rels[0].Name || rls.Namespace != rels[0].Namespace {
+			return fmt.Errorf("revisions of different releases: %s in %s and %s in %s", rels[0].Name, rels[0].Namespace, rls.Name, rls.Namespace)
+		}
+		if rls.Version <= 0 {
+			return fmt.Errorf("invalid revision %d of %s", rls.Version, rls.Name)
+		}
+		if seen[rls.Version] {
+			return fmt.Errorf("duplicate revision %d of %s", rls.Version, rls.Name)
+		}
+		seen[rls.Version] = true
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func archiveRelease(name string, vers int32, code rspb.Status_Code) *rspb.Release {
+	rls := tsRelease(name, vers, 0, code)
+	rls.Namespace = "default"
+	rls.Manifest = "kind: ConfigMap\n"
+	return rls
+}
+
+func TestArchive(t *testing.T) {
+	rels := []*rspb.Release{
+		archiveRelease("angry-bird", 3, rspb.Status_DEPLOYED),
+		archiveRelease("angry-bird", 1, rspb.Status_SUPERSEDED),
+		archiveRelease("angry-bird", 2, rspb.Status_FAILED),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, rels); err != nil {
+		t.Fatal(err)
+	}
+	if rels[0].Version != 3 {
+		t.Error("expected the revisions passed to WriteArchive to be left in place")
+	}
+
+	got, err := ReadArchive(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(got))
+	}
+	for i, rls := range got {
+		if rls.Version != int32(i+1) {
+			t.Errorf("expected revision %d at position %d, got %d", i+1, i, rls.Version)
+		}
+		if rls.Name != "angry-bird" || rls.Namespace != "default" || rls.Manifest != "kind: ConfigMap\n" {
+			t.Errorf("unexpected revision %d: %v", rls.Version, rls)
+		}
+	}
+	if got[2].Info.Status.Code != rspb.Status_DEPLOYED {
+		t.Errorf("expected revision 3 to be deployed, got %s", got[2].Info.Status.Code)
+	}
+}
+
+func TestWriteArchiveErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rels []*rspb.Release
+		err  string
+	}{
+		{"empty", nil, "no revisions"},
+		{
+			"different releases",
+			[]*rspb.Release{archiveRelease("angry-bird", 1, rspb.Status_SUPERSEDED), archiveRelease("quiet-bear", 2, rspb.Status_DEPLOYED)},
+			"different releases",
+		},
+		{
+			"duplicate revisions",
+			[]*rspb.Release{archiveRelease("angry-bird", 1, rspb.Status_SUPERSEDED), archiveRelease("angry-bird", 1, rspb.Status_DEPLOYED)},
+			"duplicate revision 1",
+		},
+	}
+	for _, tt := range tests {
+		err := WriteArchive(&bytes.Buffer{}, tt.rels)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestReadArchiveNotAnArchive(t *testing.T) {
+	if _, err := ReadArchive(strings.NewReader("kind: ConfigMap\n")); err == nil {
+		t.Error("expected an error reading a file that is not a release archive")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"sort"
+	"strings"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// Capabilities shared between releases are declared in the annotations of
+// the releases, or of their charts, as comma separated lists of names.
+const (
+	// ProvidesAnno lists the capabilities a release provides to others, such
+	// as a shared database.
+	ProvidesAnno = "helm.sh/provides"
+	// RequiresAnno lists the capabilities a release needs from others.
+	RequiresAnno = "helm.sh/requires"
+)
+
+// Dependent is a release requiring capabilities of another release.
+type Dependent struct {
+	Release  *rspb.Release
+	Requires []string
+}
+
+// Provides returns the capabilities provided by a release. The annotation of
+// the release takes precedence over the one of its chart.
+func Provides(rel *rspb.Release) []string {
+	return capabilities(rel, ProvidesAnno)
+}
+
+// Requires returns the capabilities required by a release. The annotation of
+// the release takes precedence over the one of its chart.
+func Requires(rel *rspb.Release) []string {
+	return capabilities(rel, RequiresAnno)
+}
+
+func capabilities(rel *rspb.Release, anno string) []string {
+	list, ok := rel.Annotations[anno]
+	if !ok {
+		list = rel.GetChart().GetMetadata().GetAnnotations()[anno]
+	}
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Dependents returns the releases of others that require a capability of rel
+// that none of the other releases provides, sorted by name. They would be
+// left without the capability if rel was deleted.
+func Dependents(rel *rspb.Release, others []*rspb.Release) []Dependent {
+	provided := map[string]bool{}
+	for _, name := range Provides(rel) {
+		provided[name] = true
+	}
+	if len(provided) == 0 {
+		return nil
+	}
+	for _, other := range others {
+		if other.Name == rel.Name {
+			continue
+		}
+		for _, name := range Provides(other) {
+			delete(provided, name)
+		}
+	}
+
+	var dependents []Dependent
+	for _, other := range others {
+		if other.Name == rel.Name {
+			continue
+		}
+		var requires []string
+		for _, name := range Requires(other) {
+			if provided[name] {
+				requires = append(requires, name)
+			}
+		}
+		if len(requires) > 0 {
+			dependents = append(dependents, Dependent{Release: other, Requires: requires})
+		}
+	}
+	sort.Slice(dependents, func(i, j int) bool {
+		return dependents[i].Release.Name < dependents[j].Release.Name
+	})
+	return dependents
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func capabilityRelease(name, provides, requires string) *rspb.Release {
+	rel := &rspb.Release{Name: name, Annotations: map[string]string{}}
+	if provides != "" {
+		rel.Annotations[ProvidesAnno] = provides
+	}
+	if requires != "" {
+		rel.Annotations[RequiresAnno] = requires
+	}
+	return rel
+}
+
+func TestCapabilities(t *testing.T) {
+	rel := &rspb.Release{
+		Chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: map[string]string{
+			ProvidesAnno: "postgres",
+			RequiresAnno: "storage",
+		}}},
+		Annotations: map[string]string{ProvidesAnno: "postgres, postgres-replica,"},
+	}
+	if got := Provides(rel); !reflect.DeepEqual(got, []string{"postgres", "postgres-replica"}) {
+		t.Errorf("expected the annotation of the release to take precedence, got %v", got)
+	}
+	if got := Requires(rel); !reflect.DeepEqual(got, []string{"storage"}) {
+		t.Errorf("expected the annotation of the chart, got %v", got)
+	}
+	if got := Provides(&rspb.Release{}); len(got) != 0 {
+		t.Errorf("expected no capabilities, got %v", got)
+	}
+}
+
+func TestDependents(t *testing.T) {
+	db := capabilityRelease("db", "postgres,redis", "")
+	others := []*rspb.Release{
+		db,
+		capabilityRelease("web", "", "postgres,storage"),
+		capabilityRelease("cache", "redis", ""),
+		capabilityRelease("api", "", "redis,postgres"),
+		capabilityRelease("batch", "", "kafka"),
+	}
+
+	got := Dependents(db, others)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 dependents, got %d", len(got))
+	}
+	for i, expect := range []Dependent{
+		{Release: others[3], Requires: []string{"postgres"}},
+		{Release: others[1], Requires: []string{"postgres"}},
+	} {
+		if got[i].Release.Name != expect.Release.Name || !reflect.DeepEqual(got[i].Requires, expect.Requires) {
+			t.Errorf("expected %s requiring %v, got %s requiring %v", expect.Release.Name, expect.Requires, got[i].Release.Name, got[i].Requires)
+		}
+	}
+
+	if got := Dependents(others[1], others); len(got) != 0 {
+		t.Errorf("expected a release providing nothing to have no dependents, got %d", len(got))
+	}
+}
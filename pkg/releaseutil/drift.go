@@ -0,0 +1,208 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Drift describes how an object in the cluster differs from the manifest it
+// was created from.
+type Drift struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Missing is set when the object was not found in the cluster.
+	Missing bool         `json:"missing,omitempty"`
+	Fields  []FieldDrift `json:"fields,omitempty"`
+}
+
+// FieldDrift is a single field whose live value differs from the manifest.
+type FieldDrift struct {
+	// Path is the path of the field, such as spec.template.spec.containers[0].image.
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// DetectDrift compares the objects of a release manifest with their live state,
+// as returned for a live manifest. Only the fields set in the manifest are
+// compared, so fields defaulted or populated by the cluster are ignored. The
+// result lists the drifted objects in the order of the manifest.
+func DetectDrift(manifest, live string) ([]Drift, error) {
+	want, err := manifestObjects(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parsing release manifest: %s", err)
+	}
+	got, err := manifestObjects(live)
+	if err != nil {
+		return nil, fmt.Errorf("parsing live manifest: %s", err)
+	}
+	liveObjects := make(map[string]map[string]interface{}, len(got))
+	for _, obj := range got {
+		liveObjects[objectKey(obj)] = obj
+	}
+
+	var drifts []Drift
+	for _, obj := range want {
+		d := Drift{Kind: objectKind(obj), Name: objectName(obj)}
+		liveObj, ok := liveObjects[objectKey(obj)]
+		if !ok {
+			d.Missing = true
+			drifts = append(drifts, d)
+			continue
+		}
+		compareFields("", obj, liveObj, &d.Fields)
+		if len(d.Fields) > 0 {
+			drifts = append(drifts, d)
+		}
+	}
+	return drifts, nil
+}
+
+// manifestObjects parses the documents of a manifest in order, skipping the
+// ones without an object.
+func manifestObjects(manifest string) ([]map[string]interface{}, error) {
+	docs := SplitManifests(manifest)
+	objs := make([]map[string]interface{}, 0, len(docs))
+	// SplitManifests numbers the documents in the order they were found
+	for i := 0; i < len(docs); i++ {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(docs[fmt.Sprintf("manifest-%d", i)]), &obj); err != nil {
+			return nil, err
+		}
+		if objectKind(obj) == "" {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func objectKind(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	return kind
+}
+
+func objectName(obj map[string]interface{}) string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return name
+}
+
+func objectKey(obj map[string]interface{}) string {
+	return objectKind(obj) + "/" + objectName(obj)
+}
+
+// compareFields records every field set in want whose value in got differs.
+func compareFields(path string, want, got interface{}, out *[]FieldDrift) {
+	switch w := want.(type) {
+	case nil:
+		// a null in the manifest leaves the field to the cluster
+		return
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			if got == nil && len(w) == 0 {
+				return
+			}
+			*out = append(*out, fieldDrift(path, want, got))
+			return
+		}
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			compareFields(p, w[k], g[k], out)
+		}
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			if got == nil && len(w) == 0 {
+				return
+			}
+			*out = append(*out, fieldDrift(path, want, got))
+			return
+		}
+		for i := range w {
+			compareFields(fmt.Sprintf("%s[%d]", path, i), w[i], g[i], out)
+		}
+	default:
+		if got == nil && isZero(want) {
+			// the cluster leaves out fields set to their zero value
+			return
+		}
+		if !equalValues(want, got) {
+			*out = append(*out, fieldDrift(path, want, got))
+		}
+	}
+}
+
+func isZero(v interface{}) bool {
+	return reflect.DeepEqual(v, reflect.Zero(reflect.TypeOf(v)).Interface())
+}
+
+// equalValues compares two scalars, treating quantities such as "0.5" and
+// "500m" that the cluster normalizes as equal.
+func equalValues(want, got interface{}) bool {
+	if reflect.DeepEqual(want, got) {
+		return true
+	}
+	ws, ok := want.(string)
+	if !ok {
+		return false
+	}
+	gs, ok := got.(string)
+	if !ok {
+		return false
+	}
+	wq, err := resource.ParseQuantity(ws)
+	if err != nil {
+		return false
+	}
+	gq, err := resource.ParseQuantity(gs)
+	if err != nil {
+		return false
+	}
+	return wq.Cmp(gq) == 0
+}
+
+func fieldDrift(path string, want, got interface{}) FieldDrift {
+	return FieldDrift{Path: path, Expected: formatValue(want), Actual: formatValue(got)}
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
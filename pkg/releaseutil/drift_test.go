@@ -0,0 +1,134 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"reflect"
+	"testing"
+)
+
+const driftManifest = `---
+# Source: chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 2
+  paused: false
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.15
+        resources:
+          limits:
+            cpu: "0.5"
+---
+# Source: chart/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+# Source: chart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  creationTimestamp: null
+data:
+  key: value
+`
+
+func TestDetectDrift(t *testing.T) {
+	live := `---
+# Source: chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  labels:
+    app: web
+spec:
+  replicas: 3
+  progressDeadlineSeconds: 600
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.16
+        imagePullPolicy: IfNotPresent
+        resources:
+          limits:
+            cpu: 500m
+---
+# Source: chart/templates/service.yaml
+# Service "web" was not found in namespace "default"
+---
+# Source: chart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: default
+data:
+  key: value
+`
+	drifts, err := DetectDrift(driftManifest, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []Drift{
+		{Kind: "Deployment", Name: "web", Fields: []FieldDrift{
+			{Path: "spec.replicas", Expected: "2", Actual: "3"},
+			{Path: "spec.template.spec.containers[0].image", Expected: `"nginx:1.15"`, Actual: `"nginx:1.16"`},
+		}},
+		{Kind: "Service", Name: "web", Missing: true},
+	}
+	if !reflect.DeepEqual(drifts, expect) {
+		t.Errorf("expected %+v, got %+v", expect, drifts)
+	}
+}
+
+func TestDetectDriftNone(t *testing.T) {
+	drifts, err := DetectDrift(driftManifest, driftManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("expected no drift, got %+v", drifts)
+	}
+}
+
+func TestDetectDriftChangedType(t *testing.T) {
+	manifest := "kind: ConfigMap\nmetadata:\n  name: a\ndata:\n  key: value\n"
+	live := "kind: ConfigMap\nmetadata:\n  name: a\ndata: null\n"
+	drifts, err := DetectDrift(manifest, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []Drift{{Kind: "ConfigMap", Name: "a", Fields: []FieldDrift{
+		{Path: "data", Expected: `{"key":"value"}`, Actual: "<unset>"},
+	}}}
+	if !reflect.DeepEqual(drifts, expect) {
+		t.Errorf("expected %+v, got %+v", expect, drifts)
+	}
+}
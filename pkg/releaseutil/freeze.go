@@ -0,0 +1,95 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"fmt"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// A freeze is recorded in the annotations of the latest revision of a release.
+const (
+	// FrozenAtAnno marks a release as frozen, holding the time it was frozen.
+	FrozenAtAnno = "helm.sh/frozen-at"
+	// FrozenUntilAnno holds the time a freeze expires. Without it, the freeze
+	// lasts until the release is unfrozen.
+	FrozenUntilAnno = "helm.sh/frozen-until"
+	// FrozenReasonAnno holds the reason given for a freeze.
+	FrozenReasonAnno = "helm.sh/frozen-reason"
+)
+
+// FreezeAnnotations lists every annotation used to record a freeze.
+var FreezeAnnotations = []string{FrozenAtAnno, FrozenUntilAnno, FrozenReasonAnno}
+
+// Freeze describes a change freeze on a release. While a release is frozen,
+// Tiller refuses to upgrade, roll back or delete it.
+type Freeze struct {
+	At     time.Time
+	Until  time.Time
+	Reason string
+}
+
+// Annotations returns the release annotations recording the freeze.
+func (f *Freeze) Annotations() map[string]string {
+	a := map[string]string{
+		FrozenAtAnno: f.At.UTC().Format(time.RFC3339),
+	}
+	if !f.Until.IsZero() {
+		a[FrozenUntilAnno] = f.Until.UTC().Format(time.RFC3339)
+	}
+	if f.Reason != "" {
+		a[FrozenReasonAnno] = f.Reason
+	}
+	return a
+}
+
+// Expired reports whether the freeze has ended at the given time.
+func (f *Freeze) Expired(now time.Time) bool {
+	return !f.Until.IsZero() && !now.Before(f.Until)
+}
+
+// Error returns the error given for operations refused by the freeze.
+func (f *Freeze) Error(name string) error {
+	msg := fmt.Sprintf("release %q is frozen", name)
+	if !f.Until.IsZero() {
+		msg += " until " + f.Until.UTC().Format(time.RFC3339)
+	}
+	if f.Reason != "" {
+		msg += ": " + f.Reason
+	}
+	return fmt.Errorf("%s (run 'helm unfreeze %s' to lift it)", msg, name)
+}
+
+// GetFreeze returns the freeze recorded on a release, or nil if it is not
+// frozen.
+//
+// Times that cannot be parsed are ignored rather than lifting the freeze, so a
+// malformed expiry leaves the release frozen until it is unfrozen.
+func GetFreeze(rel *rspb.Release) *Freeze {
+	at, ok := rel.Annotations[FrozenAtAnno]
+	if !ok {
+		return nil
+	}
+	f := &Freeze{Reason: rel.Annotations[FrozenReasonAnno]}
+	f.At, _ = time.Parse(time.RFC3339, at)
+	if until, ok := rel.Annotations[FrozenUntilAnno]; ok {
+		f.Until, _ = time.Parse(time.RFC3339, until)
+	}
+	return f
+}
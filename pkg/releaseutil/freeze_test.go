@@ -0,0 +1,81 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"testing"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestFreezeRoundTrip(t *testing.T) {
+	at := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	f := &Freeze{At: at, Until: at.Add(48 * time.Hour), Reason: "release week"}
+
+	got := GetFreeze(&rspb.Release{Annotations: f.Annotations()})
+	if got == nil {
+		t.Fatal("expected release to be frozen")
+	}
+	if !got.At.Equal(f.At) || !got.Until.Equal(f.Until) || got.Reason != f.Reason {
+		t.Errorf("expected %+v, got %+v", f, got)
+	}
+}
+
+func TestGetFreeze(t *testing.T) {
+	if f := GetFreeze(&rspb.Release{}); f != nil {
+		t.Errorf("expected release without annotations not to be frozen, got %+v", f)
+	}
+
+	f := GetFreeze(&rspb.Release{Annotations: map[string]string{
+		FrozenAtAnno:    "2019-05-01T12:00:00Z",
+		FrozenUntilAnno: "next tuesday",
+	}})
+	if f == nil {
+		t.Fatal("expected release to be frozen")
+	}
+	if f.Expired(time.Now().Add(24 * 365 * time.Hour)) {
+		t.Error("expected a malformed expiry to keep the release frozen")
+	}
+}
+
+func TestFreezeExpired(t *testing.T) {
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		until   time.Time
+		expired bool
+	}{
+		{time.Time{}, false},
+		{now.Add(time.Minute), false},
+		{now, true},
+		{now.Add(-time.Minute), true},
+	}
+	for _, tt := range tests {
+		f := &Freeze{At: now.Add(-time.Hour), Until: tt.until}
+		if got := f.Expired(now); got != tt.expired {
+			t.Errorf("until %s: expected expired to be %t, got %t", tt.until, tt.expired, got)
+		}
+	}
+}
+
+func TestFreezeError(t *testing.T) {
+	f := &Freeze{Until: time.Date(2019, 5, 3, 0, 0, 0, 0, time.UTC), Reason: "release week"}
+	expected := `release "nemo" is frozen until 2019-05-03T00:00:00Z: release week (run 'helm unfreeze nemo' to lift it)`
+	if err := f.Error("nemo"); err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ImageReference is a container image used by an object of a manifest.
+type ImageReference struct {
+	Image string `json:"image"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	// Container is the name of the container using the image.
+	Container string `json:"container"`
+	// Init is set for the init containers of a pod.
+	Init bool `json:"init,omitempty"`
+}
+
+// containerFields are the fields of a pod spec that list containers, and
+// whether they are init containers.
+var containerFields = map[string]bool{
+	"containers":          false,
+	"initContainers":      true,
+	"ephemeralContainers": false,
+}
+
+// FindImages returns the container images of the objects of a manifest, in
+// the order of the manifest. The pod specs are looked for at any depth, so the
+// images of the pod templates of workloads, of the job templates of CronJobs
+// and of custom resources embedding pod specs are all found.
+func FindImages(manifest string) ([]ImageReference, error) {
+	objs, err := manifestObjects(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parsing manifest: %s", err)
+	}
+	var refs []ImageReference
+	for _, obj := range objs {
+		findImages(obj, ImageReference{Kind: objectKind(obj), Name: objectName(obj)}, &refs)
+	}
+	return refs, nil
+}
+
+func findImages(v interface{}, ref ImageReference, out *[]ImageReference) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			init, ok := containerFields[k]
+			containers, isList := v[k].([]interface{})
+			if !ok || !isList {
+				findImages(v[k], ref, out)
+				continue
+			}
+			for _, c := range containers {
+				container, _ := c.(map[string]interface{})
+				image, _ := container["image"].(string)
+				if image == "" {
+					continue
+				}
+				r := ref
+				r.Image = image
+				r.Container, _ = container["name"].(string)
+				r.Init = init
+				*out = append(*out, r)
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			findImages(e, ref, out)
+		}
+	}
+}
+
+// UniqueImages returns the images of refs, sorted and without duplicates.
+func UniqueImages(refs []ImageReference) []string {
+	seen := map[string]bool{}
+	images := []string{}
+	for _, r := range refs {
+		if !seen[r.Image] {
+			seen[r.Image] = true
+			images = append(images, r.Image)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
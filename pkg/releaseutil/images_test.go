@@ -0,0 +1,84 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"reflect"
+	"testing"
+)
+
+const imagesManifest = `---
+# Source: chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: example.com/migrate:1.0
+      containers:
+      - name: web
+        image: nginx:1.15
+      - name: sidecar
+        image: example.com/proxy:2.1
+---
+# Source: chart/templates/cronjob.yaml
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  schedule: "0 * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: nginx:1.15
+---
+# Source: chart/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func TestFindImages(t *testing.T) {
+	refs, err := FindImages(imagesManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []ImageReference{
+		{Image: "nginx:1.15", Kind: "Deployment", Name: "web", Container: "web"},
+		{Image: "example.com/proxy:2.1", Kind: "Deployment", Name: "web", Container: "sidecar"},
+		{Image: "example.com/migrate:1.0", Kind: "Deployment", Name: "web", Container: "migrate", Init: true},
+		{Image: "nginx:1.15", Kind: "CronJob", Name: "backup", Container: "backup"},
+	}
+	if !reflect.DeepEqual(refs, expect) {
+		t.Errorf("expected %+v, got %+v", expect, refs)
+	}
+
+	images := UniqueImages(refs)
+	expectImages := []string{"example.com/migrate:1.0", "example.com/proxy:2.1", "nginx:1.15"}
+	if !reflect.DeepEqual(images, expectImages) {
+		t.Errorf("expected %v, got %v", expectImages, images)
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// A maintenance window of a release is recorded in the annotations of its
+// latest revision.
+const (
+	// WindowScheduleAnno holds the cron schedule the window opens on.
+	WindowScheduleAnno = "helm.sh/window-schedule"
+	// WindowDurationAnno holds how long the window stays open.
+	WindowDurationAnno = "helm.sh/window-duration"
+	// WindowTimezoneAnno holds the time zone the schedule is read in. Without
+	// it, the schedule is read in UTC.
+	WindowTimezoneAnno = "helm.sh/window-timezone"
+)
+
+// WindowAnnotations lists every annotation used to record a maintenance window.
+var WindowAnnotations = []string{WindowScheduleAnno, WindowDurationAnno, WindowTimezoneAnno}
+
+// maxWindowDuration bounds how long a window may stay open, which also bounds
+// the search for the opening of the window containing a time.
+const maxWindowDuration = 31 * 24 * time.Hour
+
+// Window is a recurring maintenance window. It opens at every time matched by
+// a cron schedule and stays open for a fixed duration.
+type Window struct {
+	// Schedule is a cron expression of five fields: minute, hour, day of
+	// month, month and day of week.
+	Schedule string
+	Duration time.Duration
+	// Timezone is the IANA name of the time zone the schedule is read in.
+	Timezone string
+
+	fields [5]uint64
+	loc    *time.Location
+}
+
+// The range of each field of a schedule.
+var scheduleBounds = [5]struct{ min, max int }{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, with 0 and 7 both Sunday
+}
+
+// ParseWindow parses a maintenance window. The duration is given as a Go
+// duration, such as "4h", and an empty time zone means UTC.
+func ParseWindow(schedule, duration, timezone string) (*Window, error) {
+	w := &Window{Schedule: schedule, Timezone: timezone}
+
+	parts := strings.Fields(schedule)
+	if len(parts) != len(w.fields) {
+		return nil, fmt.Errorf("invalid window schedule %q: expected 5 fields, got %d", schedule, len(parts))
+	}
+	for i, p := range parts {
+		bits, err := parseScheduleField(p, scheduleBounds[i].min, scheduleBounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window schedule %q: %s", schedule, err)
+		}
+		w.fields[i] = bits
+	}
+	// Sunday may be given as 7
+	if w.fields[4]&(1<<7) != 0 {
+		w.fields[4] |= 1
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window duration %q: %s", duration, err)
+	}
+	if d < time.Minute || d > maxWindowDuration {
+		return nil, fmt.Errorf("invalid window duration %q: must be between 1m and %s", duration, maxWindowDuration)
+	}
+	w.Duration = d
+
+	w.loc = time.UTC
+	if timezone != "" {
+		if w.loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid window time zone %q: %s", timezone, err)
+		}
+	}
+	return w, nil
+}
+
+// parseScheduleField parses a comma-separated list of values, ranges and
+// steps into a bit set of the matching values.
+func parseScheduleField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %q", item)
+			}
+			rng = item[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(rng[:i])
+			hi, err2 = strconv.Atoi(rng[i+1:])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", item)
+			}
+		default:
+			v, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", item)
+			}
+			lo = v
+			// "5/15" means every 15 from 5
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// matches reports whether the schedule fires at the minute of t.
+//
+// As with cron, when both the day of month and the day of week are
+// restricted, a day matching either of them matches.
+func (w *Window) matches(t time.Time) bool {
+	if w.fields[0]&(1<<uint(t.Minute())) == 0 ||
+		w.fields[1]&(1<<uint(t.Hour())) == 0 ||
+		w.fields[3]&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	return w.matchesDay(t)
+}
+
+func (w *Window) matchesDay(t time.Time) bool {
+	dom := w.fields[2]&(1<<uint(t.Day())) != 0
+	dow := w.fields[4]&(1<<uint(t.Weekday())) != 0
+	domAny := w.fields[2] == fullField(2)
+	dowAny := w.fields[4]|1<<7 == fullField(4)
+	switch {
+	case domAny && dowAny:
+		return true
+	case domAny:
+		return dow
+	case dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+func fullField(i int) uint64 {
+	var bits uint64
+	for v := scheduleBounds[i].min; v <= scheduleBounds[i].max; v++ {
+		bits |= 1 << uint(v)
+	}
+	return bits
+}
+
+// Contains reports whether the window is open at t.
+func (w *Window) Contains(t time.Time) bool {
+	t = t.In(w.loc).Truncate(time.Minute)
+	for open := t; t.Sub(open) < w.Duration; open = open.Add(-time.Minute) {
+		if w.matches(open) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the next time the window opens after t, or the zero time if
+// the schedule does not fire within the next five years.
+func (w *Window) Next(t time.Time) time.Time {
+	t = t.In(w.loc).Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(5, 0, 0)
+	for t.Before(end) {
+		switch {
+		case w.fields[3]&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, w.loc)
+		case !w.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, w.loc)
+		case w.fields[1]&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, w.loc)
+		case w.fields[0]&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// String describes the window, as in "0 2 * * 6 for 4h0m0s (Europe/Berlin)".
+func (w *Window) String() string {
+	tz := w.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	return fmt.Sprintf("%s for %s (%s)", w.Schedule, w.Duration, tz)
+}
+
+// Annotations returns the release annotations recording the window.
+func (w *Window) Annotations() map[string]string {
+	a := map[string]string{
+		WindowScheduleAnno: w.Schedule,
+		WindowDurationAnno: w.Duration.String(),
+	}
+	if w.Timezone != "" {
+		a[WindowTimezoneAnno] = w.Timezone
+	}
+	return a
+}
+
+// GetWindow returns the maintenance window recorded on a release, or nil if
+// it has none.
+func GetWindow(rel *rspb.Release) (*Window, error) {
+	schedule, ok := rel.Annotations[WindowScheduleAnno]
+	if !ok {
+		return nil, nil
+	}
+	return ParseWindow(schedule, rel.Annotations[WindowDurationAnno], rel.Annotations[WindowTimezoneAnno])
+}
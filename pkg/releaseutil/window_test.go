@@ -0,0 +1,129 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseutil // import "k8s.io/helm/pkg/releaseutil"
+
+import (
+	"testing"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		schedule, duration, timezone string
+		err                          bool
+	}{
+		{"0 2 * * 6", "4h", "", false},
+		{"*/15 9-17 1,15 * 1-5", "10m", "Europe/Berlin", false},
+		{"0 22 * * 7", "2h", "", false},
+		{"0 2 * *", "4h", "", true},
+		{"60 2 * * *", "4h", "", true},
+		{"0 5-2 * * *", "4h", "", true},
+		{"*/0 2 * * *", "4h", "", true},
+		{"0 2 * * *", "forever", "", true},
+		{"0 2 * * *", "30s", "", true},
+		{"0 2 * * *", "4h", "Mars/Olympus_Mons", true},
+	}
+	for _, tt := range tests {
+		_, err := ParseWindow(tt.schedule, tt.duration, tt.timezone)
+		if (err != nil) != tt.err {
+			t.Errorf("%q for %s: expected error to be %t, got %v", tt.schedule, tt.duration, tt.err, err)
+		}
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	// Saturdays from 02:00 to 06:00
+	w, err := ParseWindow("0 2 * * 6", "4h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sat := time.Date(2019, 5, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		open bool
+	}{
+		{sat.Add(2 * time.Hour), true},
+		{sat.Add(5*time.Hour + 59*time.Minute), true},
+		{sat.Add(6 * time.Hour), false},
+		{sat.Add(time.Hour + 59*time.Minute), false},
+		{sat.Add(-22 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := w.Contains(tt.t); got != tt.open {
+			t.Errorf("%s: expected open to be %t, got %t", tt.t, tt.open, got)
+		}
+	}
+}
+
+func TestWindowDays(t *testing.T) {
+	// the 1st of the month or any Monday, as with cron
+	w, err := ParseWindow("0 0 1 * 1", "1h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day, open := range map[int]bool{1: true, 6: true, 7: false} {
+		if got := w.Contains(time.Date(2019, 5, day, 0, 30, 0, 0, time.UTC)); got != open {
+			t.Errorf("May %d: expected open to be %t, got %t", day, open, got)
+		}
+	}
+}
+
+func TestWindowNext(t *testing.T) {
+	w, err := ParseWindow("30 2 * * 6", "4h", "Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wednesday, May 1st 2019
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	expected := time.Date(2019, 5, 4, 0, 30, 0, 0, time.UTC)
+	if got := w.Next(now); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	never, err := ParseWindow("0 0 30 2 *", "1h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := never.Next(now); !got.IsZero() {
+		t.Errorf("expected a schedule that never fires to have no next opening, got %s", got)
+	}
+}
+
+func TestGetWindow(t *testing.T) {
+	if w, err := GetWindow(&rspb.Release{}); w != nil || err != nil {
+		t.Errorf("expected no window, got %v, %v", w, err)
+	}
+
+	w, err := ParseWindow("0 2 * * 6", "4h", "Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetWindow(&rspb.Release{Annotations: w.Annotations()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != w.String() {
+		t.Errorf("expected %s, got %s", w, got)
+	}
+
+	rel := &rspb.Release{Annotations: map[string]string{WindowScheduleAnno: "0 2 * * 6"}}
+	if _, err := GetWindow(rel); err == nil {
+		t.Error("expected a window without a duration to be invalid")
+	}
+}
@@ -18,6 +18,7 @@ package renderutil
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/semver"
 
@@ -31,6 +32,14 @@ import (
 type Options struct {
 	ReleaseOptions chartutil.ReleaseOptions
 	KubeVersion    string
+	// RenderTime, if set, pins the template functions "now" and "ago".
+	RenderTime time.Time
+	// RandomSeed, if set, seeds the random template functions.
+	RandomSeed *int64
+	// Strict makes rendering fail on values missing from the templates.
+	Strict bool
+	// EnableDNSLookups lets the template function "getHostByName" resolve names.
+	EnableDNSLookups bool
 }
 
 // Render chart templates locally and display the output.
@@ -51,18 +60,6 @@ func Render(c *chart.Chart, config *chart.Config, opts Options) (map[string]stri
 		return nil, fmt.Errorf("cannot load requirements: %v", err)
 	}
 
-	err := chartutil.ProcessRequirementsEnabled(c, config)
-	if err != nil {
-		return nil, err
-	}
-	err = chartutil.ProcessRequirementsImportValues(c)
-	if err != nil {
-		return nil, err
-	}
-
-	// Set up engine.
-	renderer := engine.New()
-
 	caps := &chartutil.Capabilities{
 		APIVersions:   chartutil.DefaultVersionSet,
 		KubeVersion:   chartutil.DefaultKubeVersion,
@@ -79,6 +76,22 @@ func Render(c *chart.Chart, config *chart.Config, opts Options) (map[string]stri
 		caps.KubeVersion.GitVersion = fmt.Sprintf("v%d.%d.0", kv.Major(), kv.Minor())
 	}
 
+	err := chartutil.ProcessRequirementsEnabledCaps(c, config, caps)
+	if err != nil {
+		return nil, err
+	}
+	err = chartutil.ProcessRequirementsImportValues(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set up engine.
+	renderer := engine.New()
+	renderer.RenderTime = opts.RenderTime
+	renderer.RandomSeed = opts.RandomSeed
+	renderer.EnableDNSLookups = opts.EnableDNSLookups
+	renderer.Strict = opts.Strict
+
 	vals, err := chartutil.ToRenderValuesCaps(c, config, opts.ReleaseOptions, caps)
 	if err != nil {
 		return nil, err
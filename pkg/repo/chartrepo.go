@@ -17,18 +17,22 @@ limitations under the License.
 package repo // import "k8s.io/helm/pkg/repo"
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/trace"
 )
 
 // Entry represents a collection of parameters for chart repository
@@ -41,6 +45,39 @@ type Entry struct {
 	CertFile string `json:"certFile"`
 	KeyFile  string `json:"keyFile"`
 	CAFile   string `json:"caFile"`
+	// Proxy is the URL of the proxy to reach the repository through, instead
+	// of the one of the environment.
+	Proxy string `json:"proxy,omitempty"`
+	// ConnectTimeout and ReadTimeout are durations, such as "10s", limiting
+	// the time to connect to the repository and to wait for its responses.
+	ConnectTimeout string `json:"connectTimeout,omitempty"`
+	ReadTimeout    string `json:"readTimeout,omitempty"`
+	// Retries is the number of times failed requests to the repository are
+	// retried, 0 for the default and a negative number to never retry.
+	Retries int `json:"retries,omitempty"`
+}
+
+// httpOptions returns the connection options of the repository.
+func (e *Entry) httpOptions() (getter.HTTPOptions, error) {
+	o := getter.HTTPOptions{Proxy: e.Proxy, Retries: e.Retries}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"connectTimeout", e.ConnectTimeout, &o.ConnectTimeout},
+		{"readTimeout", e.ReadTimeout, &o.ReadTimeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return o, fmt.Errorf("invalid %s of repository %q: %s", d.name, e.Name, err)
+		}
+		*d.dst = v
+	}
+	return o, nil
 }
 
 // ChartRepository represents a chart repository
@@ -66,6 +103,15 @@ func NewChartRepository(cfg *Entry, getters getter.Providers) (*ChartRepository,
 	if err != nil {
 		return nil, fmt.Errorf("Could not construct protocol handler for: %s error: %v", u.Scheme, err)
 	}
+	if t, ok := client.(getter.HTTPConfigurer); ok {
+		o, err := cfg.httpOptions()
+		if err != nil {
+			return nil, err
+		}
+		if err := t.ConfigureHTTP(o); err != nil {
+			return nil, err
+		}
+	}
 
 	return &ChartRepository{
 		Config:    cfg,
@@ -97,7 +143,7 @@ func (r *ChartRepository) Load() error {
 					return nil
 				}
 				r.IndexFile = i
-			} else if strings.HasSuffix(f.Name(), ".tgz") {
+			} else if chartutil.IsArchive(f.Name()) {
 				r.ChartPaths = append(r.ChartPaths, path)
 			}
 		}
@@ -121,6 +167,9 @@ func (r *ChartRepository) DownloadIndexFile(cachePath string) error {
 	indexURL = parsedURL.String()
 
 	r.setCredentials()
+	if t, ok := r.Client.(getter.HeaderSetter); ok {
+		t.SetHeader(APIVersionHeader, strings.Join(SupportedAPIVersions, ", "))
+	}
 	resp, err := r.Client.Get(indexURL)
 	if err != nil {
 		return err
@@ -131,9 +180,13 @@ func (r *ChartRepository) DownloadIndexFile(cachePath string) error {
 		return err
 	}
 
-	if _, err := loadIndex(index); err != nil {
+	i, err := loadIndex(index)
+	if err != nil {
 		return err
 	}
+	if !i.SupportedAPIVersion() {
+		fmt.Fprintf(os.Stderr, "WARNING: the index of the %q repository has apiVersion %q, which this version of Helm does not support. The features it adds are ignored; upgrade Helm to use them.\n", r.Config.Name, i.APIVersion)
+	}
 
 	// In Helm 2.2.0 the config.cache was accidentally switched to an absolute
 	// path, which broke backward compatibility. This fixes it by prepending a
@@ -146,12 +199,65 @@ func (r *ChartRepository) DownloadIndexFile(cachePath string) error {
 		cp = filepath.Join(cachePath, cp)
 	}
 
-	return ioutil.WriteFile(cp, index, 0644)
+	if len(i.Shards) > 0 {
+		if index, err = r.downloadShards(i, ShardCacheDir(cp)); err != nil {
+			return err
+		}
+	}
+	return trace.ReplaceFile(cp, index, 0644)
+}
+
+// downloadShards completes a sharded index with the index files of its charts,
+// and returns the complete index. The index files are cached in dir, and only
+// downloaded again when their digest changes.
+func (r *ChartRepository) downloadShards(i *IndexFile, dir string) ([]byte, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	err := i.loadShards(func(name string, s *IndexShard) ([]byte, error) {
+		cached := filepath.Join(dir, name+".yaml")
+		if b, err := trace.ReadFile(cached); err == nil {
+			if digest, err := provenance.Digest(bytes.NewReader(b)); err == nil && digest == s.Digest {
+				return b, nil
+			}
+		}
+
+		u, err := ResolveReferenceURL(r.Config.URL, s.URL)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := r.Client.Get(u)
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(resp)
+		if err != nil {
+			return nil, err
+		}
+		return b, trace.ReplaceFile(cached, b, 0644)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Forget the charts removed from the repository
+	cached, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range cached {
+		if _, ok := i.Entries[strings.TrimSuffix(f.Name(), ".yaml")]; !ok {
+			os.Remove(filepath.Join(dir, f.Name()))
+		}
+	}
+
+	// The complete index can be large, and JSON is the fastest to load again.
+	return json.Marshal(i)
 }
 
-// If HttpGetter is used, this method sets the configured repository credentials on the HttpGetter.
+// setCredentials sets the configured repository credentials on getters that accept them.
 func (r *ChartRepository) setCredentials() {
-	if t, ok := r.Client.(*getter.HttpGetter); ok {
+	if t, ok := r.Client.(getter.CredentialSetter); ok {
 		t.SetCredentials(r.Config.Username, r.Config.Password)
 	}
 }
@@ -170,7 +276,7 @@ func (r *ChartRepository) saveIndexFile() error {
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(filepath.Join(r.Config.Name, indexPath), index, 0644)
+	return trace.WriteFile(filepath.Join(r.Config.Name, indexPath), index, 0644)
 }
 
 func (r *ChartRepository) generateIndex() error {
@@ -211,6 +317,7 @@ func FindChartInAuthRepoURL(repoURL, username, password, chartName, chartVersion
 		return "", fmt.Errorf("cannot write index file for repository requested")
 	}
 	defer os.Remove(tempIndexFile.Name())
+	defer os.RemoveAll(ShardCacheDir(tempIndexFile.Name()))
 
 	c := Entry{
 		URL:      repoURL,
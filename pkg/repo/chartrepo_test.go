@@ -70,6 +70,28 @@ func TestLoadChartRepository(t *testing.T) {
 	}
 }
 
+func TestNewChartRepositoryHTTPOptions(t *testing.T) {
+	if _, err := NewChartRepository(&Entry{
+		Name:           "proxied",
+		URL:            testURL,
+		Proxy:          "http://proxy.example.com:3128",
+		ConnectTimeout: "10s",
+		ReadTimeout:    "1m",
+		Retries:        5,
+	}, getter.All(environment.EnvSettings{})); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewChartRepository(&Entry{
+		Name:        "slow",
+		URL:         testURL,
+		ReadTimeout: "ten seconds",
+	}, getter.All(environment.EnvSettings{}))
+	if err == nil || !strings.Contains(err.Error(), `invalid readTimeout of repository "slow"`) {
+		t.Errorf("expected an error for the invalid read timeout, got %v", err)
+	}
+}
+
 func TestIndex(t *testing.T) {
 	r, err := NewChartRepository(&Entry{
 		Name: testRepository,
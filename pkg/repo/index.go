@@ -17,10 +17,10 @@ limitations under the License.
 package repo
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -34,6 +34,7 @@ import (
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/trace"
 	"k8s.io/helm/pkg/urlutil"
 )
 
@@ -81,6 +82,13 @@ type IndexFile struct {
 	Generated  time.Time                `json:"generated"`
 	Entries    map[string]ChartVersions `json:"entries"`
 	PublicKeys []string                 `json:"publicKeys,omitempty"`
+	// Shards references the index files holding every version of the charts
+	// of a sharded index. Entries only holds their latest version.
+	Shards map[string]*IndexShard `json:"shards,omitempty"`
+	// Extra holds the fields of the index that this version of Helm does not
+	// know, such as the ones added by newer index versions. They are written
+	// back when the index is saved.
+	Extra map[string]interface{} `json:"-"`
 }
 
 // NewIndexFile initializes an index.
@@ -94,28 +102,32 @@ func NewIndexFile() *IndexFile {
 }
 
 // LoadIndexFile takes a file at the given path and returns an IndexFile object
-func LoadIndexFile(path string) (*IndexFile, error) {
-	b, err := ioutil.ReadFile(path)
+//
+// The per-chart index files of a sharded index are read from the directory of
+// the index, so the returned index holds every version of the charts.
+func LoadIndexFile(filename string) (*IndexFile, error) {
+	b, err := trace.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	return loadIndex(b)
+	i, err := loadIndex(b)
+	if err != nil {
+		return i, err
+	}
+	return i, i.loadShards(func(name string, s *IndexShard) ([]byte, error) {
+		rel := path.Clean(s.URL)
+		if path.IsAbs(rel) || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("index of chart %s is outside of the repository: %s", name, s.URL)
+		}
+		return trace.ReadFile(filepath.Join(filepath.Dir(filename), filepath.FromSlash(rel)))
+	})
 }
 
 // Add adds a file to the index
 // This can leave the index in an unsorted state
 func (i IndexFile) Add(md *chart.Metadata, filename, baseURL, digest string) {
-	u := filename
-	if baseURL != "" {
-		var err error
-		_, file := filepath.Split(filename)
-		u, err = urlutil.URLJoin(baseURL, file)
-		if err != nil {
-			u = path.Join(baseURL, file)
-		}
-	}
 	cr := &ChartVersion{
-		URLs:     []string{u},
+		URLs:     []string{chartURL(filename, baseURL)},
 		Metadata: md,
 		Digest:   digest,
 		Created:  time.Now(),
@@ -127,6 +139,19 @@ func (i IndexFile) Add(md *chart.Metadata, filename, baseURL, digest string) {
 	}
 }
 
+// chartURL returns the URL of a chart archive added to an index.
+func chartURL(filename, baseURL string) string {
+	if baseURL == "" {
+		return filename
+	}
+	_, file := filepath.Split(filename)
+	u, err := urlutil.URLJoin(baseURL, file)
+	if err != nil {
+		u = path.Join(baseURL, file)
+	}
+	return u
+}
+
 // Has returns true if the index has an entry for a chart with the given name and exact version.
 func (i IndexFile) Has(name, version string) bool {
 	_, err := i.Get(name, version)
@@ -194,11 +219,30 @@ func (i IndexFile) Get(name, version string) (*ChartVersion, error) {
 //
 // The mode on the file is set to 'mode'.
 func (i IndexFile) WriteFile(dest string, mode os.FileMode) error {
-	b, err := yaml.Marshal(i)
+	b, err := i.marshal(false)
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(dest, b, mode)
+	return trace.WriteFile(dest, b, mode)
+}
+
+// WriteJSONFile writes an index file to the given destination path in JSON.
+//
+// JSON being a subset of YAML, every client reads the file, and it is much
+// faster to load for indexes of large repositories.
+func (i IndexFile) WriteJSONFile(dest string, mode os.FileMode) error {
+	b, err := i.marshal(true)
+	if err != nil {
+		return err
+	}
+	return trace.WriteFile(dest, b, mode)
+}
+
+func (i IndexFile) marshal(asJSON bool) ([]byte, error) {
+	if asJSON {
+		return json.Marshal(i)
+	}
+	return yaml.Marshal(i)
 }
 
 // Merge merges the given index file into this index.
@@ -206,7 +250,8 @@ func (i IndexFile) WriteFile(dest string, mode os.FileMode) error {
 // This merges by name and version.
 //
 // If one of the entries in the given index does _not_ already exist, it is added.
-// In all other cases, the existing record is preserved.
+// In all other cases, the existing record is preserved. The same goes for the
+// Extra fields of the index.
 //
 // This can leave the index in an unsorted state
 func (i *IndexFile) Merge(f *IndexFile) {
@@ -218,6 +263,15 @@ func (i *IndexFile) Merge(f *IndexFile) {
 			}
 		}
 	}
+	for k, v := range f.Extra {
+		if _, ok := i.Extra[k]; ok {
+			continue
+		}
+		if i.Extra == nil {
+			i.Extra = map[string]interface{}{}
+		}
+		i.Extra[k] = v
+	}
 }
 
 // Need both JSON and YAML annotations until we get rid of gopkg.in/yaml.v2
@@ -229,23 +283,47 @@ type ChartVersion struct {
 	Created time.Time `json:"created,omitempty"`
 	Removed bool      `json:"removed,omitempty"`
 	Digest  string    `json:"digest,omitempty"`
+	// Extra holds the fields of the chart version that this version of Helm
+	// does not know. They are written back when the index is saved.
+	Extra map[string]interface{} `json:"-"`
 }
 
 // IndexDirectory reads a (flat) directory and generates an index.
 //
-// It indexes only charts that have been packaged (*.tgz).
+// It indexes only charts that have been packaged (*.tgz or *.zip).
 //
 // The index returned will be in an unsorted state
 func IndexDirectory(dir, baseURL string) (*IndexFile, error) {
-	archives, err := filepath.Glob(filepath.Join(dir, "*.tgz"))
-	if err != nil {
-		return nil, err
+	return UpdateIndexDirectory(dir, baseURL, nil)
+}
+
+// UpdateIndexDirectory reads a directory and generates an index, like
+// IndexDirectory. The archives that the previous index already lists at the
+// same URL and with the same digest are not loaded again: their entries are
+// copied, so regenerating the index of a large repository only loads the new
+// and changed charts.
+//
+// The index returned will be in an unsorted state
+func UpdateIndexDirectory(dir, baseURL string, previous *IndexFile) (*IndexFile, error) {
+	known := map[string]*ChartVersion{}
+	if previous != nil {
+		for _, cvs := range previous.Entries {
+			for _, cv := range cvs {
+				if len(cv.URLs) > 0 {
+					known[cv.URLs[0]] = cv
+				}
+			}
+		}
 	}
-	moreArchives, err := filepath.Glob(filepath.Join(dir, "**/*.tgz"))
-	if err != nil {
-		return nil, err
+
+	var archives []string
+	for _, pattern := range []string{"*.tgz", "**/*.tgz", "*.zip", "**/*.zip"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, matches...)
 	}
-	archives = append(archives, moreArchives...)
 
 	index := NewIndexFile()
 	for _, arch := range archives {
@@ -263,14 +341,18 @@ func IndexDirectory(dir, baseURL string) (*IndexFile, error) {
 			parentURL = path.Join(baseURL, parentDir)
 		}
 
-		c, err := chartutil.Load(arch)
+		hash, err := provenance.DigestFile(arch)
 		if err != nil {
-			// Assume this is not a chart.
+			return index, err
+		}
+		if cv, ok := known[chartURL(fname, parentURL)]; ok && cv.Digest == hash && cv.Metadata != nil {
+			index.Entries[cv.Name] = append(index.Entries[cv.Name], cv)
 			continue
 		}
-		hash, err := provenance.DigestFile(arch)
+		c, err := chartutil.Load(arch)
 		if err != nil {
-			return index, err
+			// Assume this is not a chart.
+			continue
 		}
 		index.Add(c.Metadata, fname, parentURL, hash)
 	}
@@ -282,7 +364,15 @@ func IndexDirectory(dir, baseURL string) (*IndexFile, error) {
 // This will fail if API Version is not set (ErrNoAPIVersion) or if the unmarshal fails.
 func loadIndex(data []byte) (*IndexFile, error) {
 	i := &IndexFile{}
-	if err := yaml.Unmarshal(data, i); err != nil {
+	// Indexes written in JSON are decoded directly, which is much faster
+	// than converting them from YAML first.
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(data, i)
+	} else {
+		err = yaml.Unmarshal(data, i)
+	}
+	if err != nil {
 		return i, err
 	}
 	i.SortEntries()
@@ -0,0 +1,161 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SupportedAPIVersions are the index file versions this version of Helm
+// understands, from the oldest to the newest.
+var SupportedAPIVersions = []string{APIVersionV1}
+
+// APIVersionHeader is the request header listing SupportedAPIVersions when an
+// index is downloaded, so that a repository serving several versions of its
+// index can pick the newest one the client understands.
+const APIVersionHeader = "X-Helm-Index-Api-Versions"
+
+var (
+	indexFileFields    = jsonFields(reflect.TypeOf(IndexFile{}))
+	chartVersionFields = jsonFields(reflect.TypeOf(ChartVersion{}))
+)
+
+// SupportedAPIVersion returns whether this version of Helm understands the
+// version of the index. Newer indexes are still loaded, but the features they
+// add are only available through Extra.
+func (i *IndexFile) SupportedAPIVersion() bool {
+	for _, v := range SupportedAPIVersions {
+		if i.APIVersion == v {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON decodes an index, keeping the fields it does not know in Extra.
+func (i *IndexFile) UnmarshalJSON(data []byte) error {
+	type indexFile IndexFile
+	if err := json.Unmarshal(data, (*indexFile)(i)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, indexFileFields)
+	i.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes an index along with its Extra fields.
+func (i IndexFile) MarshalJSON() ([]byte, error) {
+	type indexFile IndexFile
+	return marshalWithExtra(indexFile(i), i.Extra)
+}
+
+// UnmarshalJSON decodes a chart version, keeping the fields it does not know
+// in Extra.
+func (c *ChartVersion) UnmarshalJSON(data []byte) error {
+	type chartVersion ChartVersion
+	if err := json.Unmarshal(data, (*chartVersion)(c)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, chartVersionFields)
+	c.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a chart version along with its Extra fields.
+func (c ChartVersion) MarshalJSON() ([]byte, error) {
+	type chartVersion ChartVersion
+	return marshalWithExtra(chartVersion(c), c.Extra)
+}
+
+// unknownFields returns the fields of the JSON object in data whose names are
+// not in known, or nil if there are none.
+func unknownFields(data []byte, known map[string]bool) (map[string]interface{}, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var extra map[string]interface{}
+	for name, raw := range fields {
+		// Like encoding/json, match the known fields regardless of case.
+		if known[strings.ToLower(name)] {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		if extra == nil {
+			extra = map[string]interface{}{}
+		}
+		extra[name] = v
+	}
+	return extra, nil
+}
+
+// marshalWithExtra encodes v, adding the extra fields that v does not set
+// itself.
+func marshalWithExtra(v interface{}, extra map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; ok {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// jsonFields returns the lower cased names of the JSON fields of the struct
+// type t, including the ones of its embedded structs.
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for n := 0; n < t.NumField(); n++ {
+		f := t.Field(n)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			for k := range jsonFields(ft) {
+				fields[k] = true
+			}
+			continue
+		}
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = true
+	}
+	return fields
+}
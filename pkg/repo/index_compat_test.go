@@ -0,0 +1,147 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+)
+
+const newerIndex = `apiVersion: v2
+generated: 2019-05-01T00:00:00Z
+channels:
+  stable: [nginx]
+entries:
+  nginx:
+  - name: nginx
+    version: 0.2.0
+    urls:
+    - https://example.com/nginx-0.2.0.tgz
+    digest: sha256:1234
+    advisories:
+    - id: CVE-2019-0001
+      severity: high
+`
+
+func TestLoadIndexUnknownFields(t *testing.T) {
+	i, err := loadIndex([]byte(newerIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyNewerIndex(t, i)
+
+	// The unknown fields survive saving the index, in YAML and in JSON.
+	for _, asJSON := range []bool{false, true} {
+		data, err := i.marshal(asJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reloaded, err := loadIndex(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifyNewerIndex(t, reloaded)
+	}
+}
+
+func verifyNewerIndex(t *testing.T, i *IndexFile) {
+	t.Helper()
+	if i.SupportedAPIVersion() {
+		t.Errorf("Expected apiVersion %q not to be supported", i.APIVersion)
+	}
+	if !reflect.DeepEqual(i.Extra, map[string]interface{}{"channels": map[string]interface{}{"stable": []interface{}{"nginx"}}}) {
+		t.Errorf("Unexpected index extra fields: %v", i.Extra)
+	}
+
+	cv, err := i.Get("nginx", "0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.Digest != "sha256:1234" || len(cv.URLs) != 1 {
+		t.Errorf("Known fields were not loaded: %+v", cv)
+	}
+	advisories, ok := cv.Extra["advisories"].([]interface{})
+	if !ok || len(advisories) != 1 {
+		t.Fatalf("Unexpected chart version extra fields: %v", cv.Extra)
+	}
+	if id := advisories[0].(map[string]interface{})["id"]; id != "CVE-2019-0001" {
+		t.Errorf("Expected advisory CVE-2019-0001, got %v", id)
+	}
+	if _, ok := cv.Extra["name"]; ok {
+		t.Error("Expected known fields not to be in Extra")
+	}
+}
+
+func TestLoadIndexWithoutUnknownFields(t *testing.T) {
+	i, err := LoadIndexFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !i.SupportedAPIVersion() {
+		t.Errorf("Expected apiVersion %q to be supported", i.APIVersion)
+	}
+	if i.Extra != nil {
+		t.Errorf("Expected no extra fields, got %v", i.Extra)
+	}
+	for _, cvs := range i.Entries {
+		for _, cv := range cvs {
+			if cv.Extra != nil {
+				t.Errorf("Expected no extra fields for %s, got %v", cv.Name, cv.Extra)
+			}
+		}
+	}
+}
+
+func TestDownloadIndexFileSendsAPIVersions(t *testing.T) {
+	var header string
+	srv, err := startLocalServerForTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(APIVersionHeader)
+		w.Write([]byte(newerIndex))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "helm-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	r, err := NewChartRepository(&Entry{
+		Name:  testRepo,
+		URL:   srv.URL,
+		Cache: filepath.Join(cacheDir, testRepo+"-index.yaml"),
+	}, getter.All(environment.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DownloadIndexFile(""); err != nil {
+		t.Fatal(err)
+	}
+	if header != APIVersionV1 {
+		t.Errorf("Expected %s header %q, got %q", APIVersionHeader, APIVersionV1, header)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/trace"
+)
+
+// shardsDir is the directory, next to a sharded index, holding the index files
+// of each chart.
+const shardsDir = "index"
+
+// IndexShard references the index file holding every version of a chart.
+//
+// Repositories with many charts, or many versions of each chart, are served as
+// sharded indexes, so that clients only download the index files of the charts
+// that changed since they last updated the repository.
+type IndexShard struct {
+	// URL is the URL of the index file, relative to the repository.
+	URL string `json:"url"`
+	// Digest is the SHA-256 digest of the index file.
+	Digest string `json:"digest"`
+}
+
+// WriteShardedFile writes the index to dest as a sharded index.
+//
+// Every version of each chart is written to an index file of its own, in the
+// index directory next to dest. The index written to dest only holds the latest
+// version of each chart, which is what older clients see of the repository.
+func (i IndexFile) WriteShardedFile(dest string, mode os.FileMode, asJSON bool) error {
+	dir := filepath.Join(filepath.Dir(dest), shardsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ext := ".yaml"
+	if asJSON {
+		ext = ".json"
+	}
+
+	i.SortEntries()
+	main := &IndexFile{
+		APIVersion: i.APIVersion,
+		Generated:  i.Generated,
+		Entries:    map[string]ChartVersions{},
+		PublicKeys: i.PublicKeys,
+		Shards:     map[string]*IndexShard{},
+	}
+	for name, versions := range i.Entries {
+		if len(versions) == 0 {
+			continue
+		}
+		// The shard only changes with the versions of the chart, so that its
+		// digest tells clients whether to download it again.
+		shard := &IndexFile{
+			APIVersion: i.APIVersion,
+			Generated:  lastCreated(versions),
+			Entries:    map[string]ChartVersions{name: versions},
+		}
+		b, err := shard.marshal(asJSON)
+		if err != nil {
+			return err
+		}
+		if err := trace.WriteFile(filepath.Join(dir, name+ext), b, mode); err != nil {
+			return err
+		}
+		digest, err := provenance.Digest(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		main.Entries[name] = versions[:1]
+		main.Shards[name] = &IndexShard{URL: path.Join(shardsDir, name+ext), Digest: digest}
+	}
+
+	b, err := main.marshal(asJSON)
+	if err != nil {
+		return err
+	}
+	return trace.WriteFile(dest, b, mode)
+}
+
+// lastCreated returns the creation time of the most recent chart version.
+func lastCreated(versions ChartVersions) time.Time {
+	var t time.Time
+	for _, v := range versions {
+		if v.Created.After(t) {
+			t = v.Created
+		}
+	}
+	return t
+}
+
+// loadShards replaces the entries of the charts of a sharded index with every
+// version of the charts, read from their index files with fetch.
+func (i *IndexFile) loadShards(fetch func(name string, s *IndexShard) ([]byte, error)) error {
+	if len(i.Shards) > 0 && i.Entries == nil {
+		i.Entries = map[string]ChartVersions{}
+	}
+	for name, s := range i.Shards {
+		b, err := fetch(name, s)
+		if err != nil {
+			return err
+		}
+		digest, err := provenance.Digest(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		if digest != s.Digest {
+			return fmt.Errorf("index of chart %s does not match its digest", name)
+		}
+		shard, err := loadIndex(b)
+		if err != nil {
+			return fmt.Errorf("cannot load index of chart %s: %s", name, err)
+		}
+		i.Entries[name] = shard.Entries[name]
+	}
+	i.Shards = nil
+	return nil
+}
+
+// ShardCacheDir returns the directory caching the per-chart index files of a
+// sharded index, for the index cached at indexFile.
+func ShardCacheDir(indexFile string) string {
+	return strings.TrimSuffix(indexFile, filepath.Ext(indexFile)) + "-shards"
+}
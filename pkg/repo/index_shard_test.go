@@ -0,0 +1,136 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/helm/pkg/getter"
+	"k8s.io/helm/pkg/helm/environment"
+)
+
+// writeShardedTestIndex writes the test index as a sharded index in a
+// temporary directory.
+func writeShardedTestIndex(t *testing.T, asJSON bool) string {
+	i, err := LoadIndexFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "helm-sharded-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := i.WriteShardedFile(filepath.Join(dir, "index.yaml"), 0644, asJSON); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestWriteShardedFile(t *testing.T) {
+	for _, asJSON := range []bool{false, true} {
+		dir := writeShardedTestIndex(t, asJSON)
+		defer os.RemoveAll(dir)
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, "index.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		main, err := loadIndex(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(main.Shards) != 3 {
+			t.Errorf("Expected 3 shards, got %d", len(main.Shards))
+		}
+		if vs := main.Entries["nginx"]; len(vs) != 1 || vs[0].Version != "0.2.0" {
+			t.Errorf("Expected the main index to hold the latest version only, got %v", vs)
+		}
+
+		// Loading the index reads the shards.
+		i, err := LoadIndexFile(filepath.Join(dir, "index.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.Shards != nil {
+			t.Errorf("Expected the shards to be loaded, got %v", i.Shards)
+		}
+		verifyLocalIndex(t, i)
+
+		// A tampered shard is rejected.
+		shard := filepath.Join(dir, filepath.FromSlash(main.Shards["nginx"].URL))
+		if err := ioutil.WriteFile(shard, []byte("apiVersion: v1\nentries: {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadIndexFile(filepath.Join(dir, "index.yaml")); err == nil || !strings.Contains(err.Error(), "digest") {
+			t.Errorf("Expected a digest mismatch, got %v", err)
+		}
+	}
+}
+
+func TestDownloadShardedIndexFile(t *testing.T) {
+	dir := writeShardedTestIndex(t, false)
+	defer os.RemoveAll(dir)
+
+	var shardRequests int32
+	files := http.FileServer(http.Dir(dir))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/index/") {
+			atomic.AddInt32(&shardRequests, 1)
+		}
+		files.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "helm-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	indexFilePath := filepath.Join(cacheDir, testRepo+"-index.yaml")
+	r, err := NewChartRepository(&Entry{
+		Name:  testRepo,
+		URL:   srv.URL,
+		Cache: indexFilePath,
+	}, getter.All(environment.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n := 0; n < 2; n++ {
+		if err := r.DownloadIndexFile(""); err != nil {
+			t.Fatal(err)
+		}
+		i, err := LoadIndexFile(indexFilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifyLocalIndex(t, i)
+	}
+	// The shards are cached after the first download.
+	if n := atomic.LoadInt32(&shardRequests); n != 3 {
+		t.Errorf("Expected the 3 shards to be downloaded once, got %d requests", n)
+	}
+}
@@ -18,10 +18,12 @@ package repo
 
 import (
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/helm/environment"
@@ -187,6 +189,58 @@ func TestDownloadIndexFile(t *testing.T) {
 	verifyLocalIndex(t, i)
 }
 
+func TestDownloadIndexFileKeepsCache(t *testing.T) {
+	srv, err := startLocalServerForTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("apiVersion: v1\nentries: {alpine: ["))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	dirName, err := ioutil.TempDir("", "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirName)
+
+	cached, err := ioutil.ReadFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexFilePath := filepath.Join(dirName, testRepo+"-index.yaml")
+	if err := ioutil.WriteFile(indexFilePath, cached, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewChartRepository(&Entry{
+		Name:  testRepo,
+		URL:   srv.URL,
+		Cache: indexFilePath,
+	}, getter.All(environment.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DownloadIndexFile(""); err == nil {
+		t.Error("expected an error for a truncated index")
+	}
+
+	b, err := ioutil.ReadFile(indexFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(cached) {
+		t.Error("expected the cached index to be left intact")
+	}
+	files, err := ioutil.ReadDir(dirName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected no file but the cached index, got %d files", len(files))
+	}
+}
+
 func verifyLocalIndex(t *testing.T, i *IndexFile) {
 	numEntries := len(i.Entries)
 	if numEntries != 3 {
@@ -324,6 +378,70 @@ func TestIndexDirectory(t *testing.T) {
 	}
 }
 
+func TestUpdateIndexDirectory(t *testing.T) {
+	dir := filepath.Join("testdata", "repository")
+	previous, err := IndexDirectory(dir, "http://localhost:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, cvs := range previous.Entries {
+		for _, cv := range cvs {
+			cv.Created = created
+		}
+	}
+	// A changed archive is loaded again.
+	frob, err := previous.Get("frobnitz", "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	frob.Digest = "changed"
+
+	index, err := UpdateIndexDirectory(dir, "http://localhost:8080", previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := len(index.Entries); l != 3 {
+		t.Fatalf("Expected 3 entries, got %d", l)
+	}
+	for name, cvs := range index.Entries {
+		for _, cv := range cvs {
+			if reused := cv.Created.Equal(created); reused == (name == "frobnitz") {
+				t.Errorf("Unexpected entry of %s-%s, reused: %t", name, cv.Version, reused)
+			}
+		}
+	}
+}
+
+func TestWriteJSONFile(t *testing.T) {
+	i, err := LoadIndexFile(testfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "helm-index-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if err := i.WriteJSONFile(f.Name(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "{") {
+		t.Errorf("Expected a JSON index, got %q", b)
+	}
+	i, err = LoadIndexFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyLocalIndex(t, i)
+}
+
 func TestLoadUnversionedIndex(t *testing.T) {
 	data, err := ioutil.ReadFile("testdata/unversioned-index.yaml")
 	if err != nil {
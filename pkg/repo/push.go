@@ -0,0 +1,301 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/provenance"
+	"k8s.io/helm/pkg/tlsutil"
+	"k8s.io/helm/pkg/urlutil"
+	"k8s.io/helm/pkg/version"
+)
+
+// ErrChartExists is returned when an uploaded chart version already exists
+// in the repository, and the upload is not forced.
+var ErrChartExists = errors.New("the chart version already exists in the repository, use --force to replace it")
+
+// Uploader uploads chart archives to a chart repository.
+type Uploader interface {
+	// Upload uploads a chart archive, along with its provenance file unless
+	// provPath is empty. Unless force is set, ErrChartExists is returned if
+	// the repository already has the version of the chart.
+	Upload(chartPath, provPath string, force bool) error
+}
+
+// The kinds of repositories charts can be uploaded to.
+const (
+	// UploaderChartMuseum uploads charts with the API of ChartMuseum.
+	UploaderChartMuseum = "chartmuseum"
+	// UploaderHTTP uploads charts with HTTP PUT requests, as supported by
+	// WebDAV servers, and updates the index.yaml of the repository.
+	UploaderHTTP = "http"
+)
+
+// NewUploader returns an uploader of the given kind for a repository.
+func NewUploader(kind string, cfg *Entry) (Uploader, error) {
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid chart URL format: %s", cfg.URL)
+	}
+	client, err := newUploadClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "", UploaderChartMuseum:
+		return &ChartMuseumUploader{Config: cfg, Client: client}, nil
+	case UploaderHTTP:
+		return &HTTPUploader{Config: cfg, Client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown repository kind %q, expected %s or %s", kind, UploaderChartMuseum, UploaderHTTP)
+}
+
+func newUploadClient(cfg *Entry) (*http.Client, error) {
+	tr := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if (cfg.CertFile != "" && cfg.KeyFile != "") || cfg.CAFile != "" {
+		tlsConf, err := tlsutil.NewTLSConfig(cfg.URL, cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't create TLS config: %s", err)
+		}
+		tr.TLSClientConfig = tlsConf
+	}
+	return &http.Client{Transport: tr}, nil
+}
+
+// newUploadRequest creates a request to the repository, with the credentials
+// of the repository.
+func newUploadRequest(cfg *Entry, method, href string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, href, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Helm/"+strings.TrimPrefix(version.GetVersion(), "v"))
+	if cfg.Username != "" && cfg.Password != "" {
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	return req, nil
+}
+
+// ChartMuseumUploader uploads charts to a ChartMuseum server.
+//
+// The charts are posted to the /api/charts endpoint. For a repository served
+// under a path, such as a tenant of a multitenant server, the path is inserted
+// after /api.
+type ChartMuseumUploader struct {
+	Config *Entry
+	Client *http.Client
+}
+
+// Upload posts a chart to ChartMuseum.
+func (u *ChartMuseumUploader) Upload(chartPath, provPath string, force bool) error {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	files := []struct{ field, path string }{{"chart", chartPath}, {"prov", provPath}}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if err := addFormFile(w, f.field, f.path); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	apiURL, err := url.Parse(u.Config.URL)
+	if err != nil {
+		return err
+	}
+	apiURL.Path = path.Join("/api", apiURL.Path, "charts")
+	if force {
+		apiURL.RawQuery = "force=true"
+	}
+	req, err := newUploadRequest(u.Config, "POST", apiURL.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return ErrChartExists
+	case resp.StatusCode >= 300:
+		// ChartMuseum describes the errors as {"error": "..."}
+		b, _ := ioutil.ReadAll(resp.Body)
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(b, &e) == nil && e.Error != "" {
+			return fmt.Errorf("failed to upload %s: %s: %s", filepath.Base(chartPath), resp.Status, e.Error)
+		}
+		return fmt.Errorf("failed to upload %s: %s", filepath.Base(chartPath), resp.Status)
+	}
+	return nil
+}
+
+func addFormFile(w *multipart.Writer, field, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	part, err := w.CreateFormFile(field, filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, f)
+	return err
+}
+
+// HTTPUploader uploads charts to a repository accepting HTTP PUT requests,
+// such as a WebDAV server.
+//
+// The chart archive and its provenance file are put next to the index.yaml of
+// the repository, then the chart is added to the index, which is put back.
+type HTTPUploader struct {
+	Config *Entry
+	Client *http.Client
+}
+
+// Upload puts a chart in the repository and adds it to the index.
+func (u *HTTPUploader) Upload(chartPath, provPath string, force bool) error {
+	c, err := chartutil.Load(chartPath)
+	if err != nil {
+		return err
+	}
+	digest, err := provenance.DigestFile(chartPath)
+	if err != nil {
+		return err
+	}
+
+	index, err := u.index()
+	if err != nil {
+		return err
+	}
+	if len(index.Shards) > 0 {
+		return errors.New("cannot add charts to a sharded index, regenerate it with 'helm repo index --shard' instead")
+	}
+	if index.Has(c.Metadata.Name, c.Metadata.Version) {
+		if !force {
+			return ErrChartExists
+		}
+		index.remove(c.Metadata.Name, c.Metadata.Version)
+	}
+
+	for _, p := range []string{chartPath, provPath} {
+		if p == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := u.put(filepath.Base(p), data); err != nil {
+			return err
+		}
+	}
+
+	index.Add(c.Metadata, filepath.Base(chartPath), "", digest)
+	index.SortEntries()
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return u.put(indexPath, data)
+}
+
+// index downloads the index of the repository. A missing index is empty.
+func (u *HTTPUploader) index() (*IndexFile, error) {
+	href, err := urlutil.URLJoin(u.Config.URL, indexPath)
+	if err != nil {
+		return nil, err
+	}
+	req, err := newUploadRequest(u.Config, "GET", href, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return NewIndexFile(), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", href, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return loadIndex(b)
+}
+
+func (u *HTTPUploader) put(name string, data []byte) error {
+	href, err := urlutil.URLJoin(u.Config.URL, name)
+	if err != nil {
+		return err
+	}
+	req, err := newUploadRequest(u.Config, "PUT", href, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload %s: %s", name, resp.Status)
+	}
+	return nil
+}
+
+// remove removes a version of a chart from the index.
+func (i IndexFile) remove(name, ver string) {
+	var kept ChartVersions
+	for _, cv := range i.Entries[name] {
+		if cv.Version != ver {
+			kept = append(kept, cv)
+		}
+	}
+	i.Entries[name] = kept
+}
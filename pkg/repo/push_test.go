@@ -0,0 +1,140 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const pushTestChart = "testdata/repository/frobnitz-1.2.3.tgz"
+
+func TestChartMuseumUploader(t *testing.T) {
+	var uploaded []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/tenant/charts" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, _ := r.BasicAuth(); user != "user" || pass != "secret" {
+			t.Errorf("Expected the credentials of the repository, got %q:%q", user, pass)
+		}
+		if len(uploaded) > 0 && r.URL.Query().Get("force") != "true" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		for field, files := range r.MultipartForm.File {
+			uploaded = append(uploaded, field+":"+files[0].Filename)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	u, err := NewUploader(UploaderChartMuseum, &Entry{URL: srv.URL + "/tenant", Username: "user", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Upload(pushTestChart, "", false); err != nil {
+		t.Fatalf("Failed to upload: %s", err)
+	}
+	if len(uploaded) != 1 || uploaded[0] != "chart:frobnitz-1.2.3.tgz" {
+		t.Errorf("Expected the chart to be uploaded, got %v", uploaded)
+	}
+	if err := u.Upload(pushTestChart, "", false); err != ErrChartExists {
+		t.Errorf("Expected %q, got %v", ErrChartExists, err)
+	}
+	if err := u.Upload(pushTestChart, "", true); err != nil {
+		t.Errorf("Failed to force the upload: %s", err)
+	}
+}
+
+func TestHTTPUploader(t *testing.T) {
+	var mu sync.Mutex
+	files := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "GET":
+			data, ok := files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case "PUT":
+			data, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			files[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := NewUploader(UploaderHTTP, &Entry{URL: srv.URL + "/charts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Upload(pushTestChart, "", false); err != nil {
+		t.Fatalf("Failed to upload: %s", err)
+	}
+	if _, ok := files["/charts/frobnitz-1.2.3.tgz"]; !ok {
+		t.Error("Expected the chart to be uploaded")
+	}
+	index, err := loadIndex(files["/charts/index.yaml"])
+	if err != nil {
+		t.Fatalf("Failed to load the uploaded index: %s", err)
+	}
+	cv, err := index.Get("frobnitz", "1.2.3")
+	if err != nil {
+		t.Fatalf("Expected the chart to be indexed: %s", err)
+	}
+	if len(cv.URLs) != 1 || cv.URLs[0] != "frobnitz-1.2.3.tgz" || cv.Digest == "" {
+		t.Errorf("Unexpected index entry %v", cv)
+	}
+
+	if err := u.Upload(pushTestChart, "", false); err != ErrChartExists {
+		t.Errorf("Expected %q, got %v", ErrChartExists, err)
+	}
+	if err := u.Upload(pushTestChart, "", true); err != nil {
+		t.Fatalf("Failed to force the upload: %s", err)
+	}
+	index, err = loadIndex(files["/charts/index.yaml"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(index.Entries["frobnitz"]); n != 1 {
+		t.Errorf("Expected the forced upload to replace the version, got %d versions", n)
+	}
+}
+
+func TestNewUploaderUnknownKind(t *testing.T) {
+	if _, err := NewUploader("ftp", &Entry{URL: "http://example.com"}); err == nil {
+		t.Error("Expected an error for an unknown kind of repository")
+	}
+}
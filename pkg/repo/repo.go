@@ -19,11 +19,12 @@ package repo // import "k8s.io/helm/pkg/repo"
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"time"
 
 	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/trace"
 )
 
 // ErrRepoOutOfDate indicates that the repository file is out of date, but
@@ -54,7 +55,7 @@ func NewRepoFile() *RepoFile {
 // If this returns ErrRepoOutOfDate, it also returns a recovered RepoFile that
 // can be saved as a replacement to the out of date file.
 func LoadRepositoriesFile(path string) (*RepoFile, error) {
-	b, err := ioutil.ReadFile(path)
+	b, err := trace.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf(
@@ -125,6 +126,16 @@ func (r *RepoFile) Has(name string) bool {
 	return false
 }
 
+// Get returns the entry of the repository with the given name, or nil.
+func (r *RepoFile) Get(name string) *Entry {
+	for _, rf := range r.Repositories {
+		if rf.Name == name {
+			return rf
+		}
+	}
+	return nil
+}
+
 // Remove removes the entry from the list of repositories.
 func (r *RepoFile) Remove(name string) bool {
 	cp := []*Entry{}
@@ -146,5 +157,5 @@ func (r *RepoFile) WriteFile(path string, perm os.FileMode) error {
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(path, data, perm)
+	return trace.WriteFile(path, data, perm)
 }
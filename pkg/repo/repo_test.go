@@ -147,6 +147,21 @@ func TestRemoveRepository(t *testing.T) {
 	}
 }
 
+func TestGetRepository(t *testing.T) {
+	sampleRepository := NewRepoFile()
+	sampleRepository.Add(&Entry{
+		Name: "stable",
+		URL:  "https://example.com/stable/charts",
+	})
+
+	if e := sampleRepository.Get("stable"); e == nil || e.URL != "https://example.com/stable/charts" {
+		t.Errorf("expected repository stable, got %v", e)
+	}
+	if e := sampleRepository.Get("incubator"); e != nil {
+		t.Errorf("expected no repository incubator, got %v", e)
+	}
+}
+
 func TestUpdateRepository(t *testing.T) {
 	sampleRepository := NewRepoFile()
 	sampleRepository.Add(
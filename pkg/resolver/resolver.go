@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -32,6 +33,8 @@ import (
 	"k8s.io/helm/pkg/repo"
 )
 
+var digestRe = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
 // Resolver resolves dependencies from semantic version ranges to a particular version.
 type Resolver struct {
 	chartpath string
@@ -53,7 +56,14 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 	locked := make([]*chartutil.Dependency, len(reqs.Dependencies))
 	missing := []string{}
 	for i, d := range reqs.Dependencies {
+		if d.Digest != "" && !digestRe.MatchString(d.Digest) {
+			return nil, fmt.Errorf("dependency %q has an invalid digest %q, it must be of the form sha256:<hex>", d.Name, d.Digest)
+		}
+
 		if strings.HasPrefix(d.Repository, "file://") {
+			if d.Digest != "" {
+				return nil, fmt.Errorf("dependency %q is a local directory and cannot be pinned to a digest", d.Name)
+			}
 
 			if _, err := GetLocalPath(d.Repository, r.chartpath); err != nil {
 				return nil, err
@@ -78,6 +88,7 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 				Name:       d.Name,
 				Repository: d.Repository,
 				Version:    d.Version,
+				Digest:     d.Digest,
 			}
 			continue
 		}
@@ -95,6 +106,7 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 		locked[i] = &chartutil.Dependency{
 			Name:       d.Name,
 			Repository: d.Repository,
+			Digest:     d.Digest,
 		}
 		found := false
 		// The version are already sorted and hence the first one to satisfy the constraint is used
@@ -104,6 +116,9 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 				// Not a legit entry.
 				continue
 			}
+			if d.Digest != "" && "sha256:"+ver.Digest != d.Digest {
+				continue
+			}
 			if constraint.Check(v) {
 				found = true
 				locked[i].Version = v.Original()
@@ -112,7 +127,11 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 		}
 
 		if !found {
-			missing = append(missing, d.Name)
+			if d.Digest != "" {
+				missing = append(missing, fmt.Sprintf("%s (digest %s)", d.Name, d.Digest))
+			} else {
+				missing = append(missing, d.Name)
+			}
 		}
 	}
 	if len(missing) > 0 {
@@ -68,6 +68,46 @@ func TestResolve(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "digest pinned lock",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "alpine", Repository: "http://example.com", Version: ">=0.1.0", Digest: "sha256:8f4a2e5c1d7b3a9e6f0c2b4d8a1e3f5c7b9d0a2c4e6f8a1b3d5c7e9f0a2b4c6d"},
+				},
+			},
+			expect: &chartutil.RequirementsLock{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "alpine", Repository: "http://example.com", Version: "0.1.0", Digest: "sha256:8f4a2e5c1d7b3a9e6f0c2b4d8a1e3f5c7b9d0a2c4e6f8a1b3d5c7e9f0a2b4c6d"},
+				},
+			},
+		},
+		{
+			name: "digest not found failure",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "alpine", Repository: "http://example.com", Version: ">=0.1.0", Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "invalid digest failure",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "alpine", Repository: "http://example.com", Version: ">=0.1.0", Digest: "8f4a2e5c"},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "digest pinned local path failure",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "signtest", Repository: "file://../../../../cmd/helm/testdata/testcharts/signtest", Version: "0.1.0", Digest: "sha256:8f4a2e5c1d7b3a9e6f0c2b4d8a1e3f5c7b9d0a2c4e6f8a1b3d5c7e9f0a2b4c6d"},
+				},
+			},
+			err: true,
+		},
 		{
 			name: "repo from valid local path",
 			req: &chartutil.Requirements{
@@ -133,6 +173,9 @@ func TestResolve(t *testing.T) {
 		if d0.Version != e0.Version {
 			t.Errorf("%s: expected version %s, got %s", tt.name, e0.Version, d0.Version)
 		}
+		if d0.Digest != e0.Digest {
+			t.Errorf("%s: expected digest %q, got %q", tt.name, e0.Digest, d0.Digest)
+		}
 	}
 }
 
@@ -0,0 +1,27 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package secretscan finds likely credentials in the files of a chart.
+
+Charts are shared through repositories, so a credential committed into
+values.yaml or a template leaks to everyone who can fetch the chart. The
+scanner looks for AWS access keys, private key blocks and long, high-entropy
+strings such as API tokens. It is a heuristic: findings that are not
+credentials, such as example keys, are allowed by an allowlist or by a
+"helmlint:allow-secret" comment on the same line.
+*/
+package secretscan // import "k8s.io/helm/pkg/secretscan"
@@ -0,0 +1,220 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretscan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// AllowComment marks a line whose findings are allowed.
+const AllowComment = "helmlint:allow-secret"
+
+// The kinds of credentials the scanner finds.
+const (
+	AWSAccessKey = "aws-access-key"
+	AWSSecretKey = "aws-secret-key"
+	PrivateKey   = "private-key"
+	HighEntropy  = "high-entropy-string"
+)
+
+const (
+	// minTokenLen and minEntropy bound the strings reported as high-entropy
+	// strings. Hexadecimal digests, such as image digests, stay below them.
+	minTokenLen = 32
+	minEntropy  = 4.3
+	// visiblePrefix is the number of characters of a credential left
+	// unmasked in findings.
+	visiblePrefix = 4
+)
+
+var descriptions = map[string]string{
+	AWSAccessKey: "AWS access key ID",
+	AWSSecretKey: "AWS secret access key",
+	PrivateKey:   "private key",
+	HighEntropy:  "high-entropy string",
+}
+
+var (
+	awsAccessKeyRegex = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+	awsSecretKeyRegex = regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)
+	privateKeyRegex   = regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)
+	tokenRegex        = regexp.MustCompile(`[A-Za-z0-9+/=_-]{` + fmt.Sprint(minTokenLen) + `,}`)
+)
+
+// Finding is a likely credential found in a file.
+type Finding struct {
+	// Path is the path of the file, relative to the chart.
+	Path string
+	// Line is the line number of the credential, starting at 1.
+	Line int
+	// Kind is the kind of credential, such as "private-key".
+	Kind string
+	// Match is the credential, masked but for its first characters, or the
+	// header of a private key block.
+	Match string
+}
+
+func (f Finding) Error() string {
+	return fmt.Sprintf("line %d: likely %s (%s)", f.Line, descriptions[f.Kind], f.Match)
+}
+
+// Allow allows the findings of the files matching Path, a glob such as
+// "templates/*.yaml", whose credential matches the regular expression
+// Match. An empty Path matches every file, and an empty Match every
+// credential.
+type Allow struct {
+	Path  string `json:"path,omitempty"`
+	Match string `json:"match,omitempty"`
+}
+
+// Validate checks the patterns of the allowlist entry.
+func (a Allow) Validate() error {
+	if a.Path == "" && a.Match == "" {
+		return errors.New("an allowlist entry needs a path or a match")
+	}
+	if _, err := path.Match(a.Path, ""); err != nil {
+		return fmt.Errorf("invalid path %q: %s", a.Path, err)
+	}
+	if _, err := regexp.Compile(a.Match); err != nil {
+		return fmt.Errorf("invalid match %q: %s", a.Match, err)
+	}
+	return nil
+}
+
+func (a Allow) allows(file, secret string) bool {
+	if a.Path != "" {
+		if ok, _ := path.Match(a.Path, file); !ok {
+			return false
+		}
+	}
+	if a.Match != "" {
+		if ok, _ := regexp.MatchString(a.Match, secret); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ScanChart scans values.yaml, the templates and the other files of a
+// chart and of its dependencies, whose paths are prefixed by
+// "charts/NAME/".
+func ScanChart(ch *chart.Chart, allow []Allow) []Finding {
+	return scanChart(ch, "", allow)
+}
+
+func scanChart(ch *chart.Chart, prefix string, allow []Allow) []Finding {
+	var findings []Finding
+	if ch.Values != nil {
+		findings = append(findings, Scan(prefix+chartutil.ValuesfileName, []byte(ch.Values.Raw), allow)...)
+	}
+	for _, t := range ch.Templates {
+		findings = append(findings, Scan(prefix+t.Name, t.Data, allow)...)
+	}
+	for _, f := range ch.Files {
+		findings = append(findings, Scan(prefix+f.TypeUrl, f.Value, allow)...)
+	}
+	for _, dep := range ch.Dependencies {
+		findings = append(findings, scanChart(dep, prefix+"charts/"+dep.Metadata.Name+"/", allow)...)
+	}
+	return findings
+}
+
+// Scan scans the content of the file at path. Binary files are skipped.
+func Scan(path string, data []byte, allow []Allow) []Finding {
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return nil
+	}
+
+	var findings []Finding
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, AllowComment) {
+			continue
+		}
+		add := func(kind, secret string) {
+			for _, a := range allow {
+				if a.allows(path, secret) {
+					return
+				}
+			}
+			if kind != PrivateKey {
+				secret = mask(secret)
+			}
+			findings = append(findings, Finding{Path: path, Line: i + 1, Kind: kind, Match: secret})
+		}
+
+		if m := privateKeyRegex.FindString(line); m != "" {
+			add(PrivateKey, m)
+			continue
+		}
+		reported := map[string]bool{}
+		for _, m := range awsAccessKeyRegex.FindAllString(line, -1) {
+			reported[m] = true
+			add(AWSAccessKey, m)
+		}
+		for _, m := range awsSecretKeyRegex.FindAllStringSubmatch(line, -1) {
+			reported[m[1]] = true
+			add(AWSSecretKey, m[1])
+		}
+		for _, m := range tokenRegex.FindAllString(line, -1) {
+			if !reported[m] && highEntropy(m) {
+				add(HighEntropy, m)
+			}
+		}
+	}
+	return findings
+}
+
+// highEntropy returns true if s looks random: it mixes letters and digits,
+// and its characters have a Shannon entropy of at least minEntropy bits.
+func highEntropy(s string) bool {
+	if !strings.ContainsAny(s, "0123456789") || strings.IndexFunc(s, isLetter) < 0 {
+		return false
+	}
+	counts := map[rune]float64{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, c := range counts {
+		p := c / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= minEntropy
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// mask hides a credential but for its first characters.
+func mask(s string) string {
+	if len(s) <= visiblePrefix {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:visiblePrefix] + strings.Repeat("*", 4)
+}
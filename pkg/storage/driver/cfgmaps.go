@@ -33,7 +33,10 @@ import (
 	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
-var _ Driver = (*ConfigMaps)(nil)
+var (
+	_ Driver = (*ConfigMaps)(nil)
+	_ Locker = (*ConfigMaps)(nil)
+)
 
 // ConfigMapsDriverName is the string name of the driver.
 const ConfigMapsDriverName = "ConfigMap"
@@ -257,3 +260,80 @@ func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*v1.ConfigM
 		Data: map[string]string{"release": s},
 	}, nil
 }
+
+// AcquireLease takes the lease on a release, held in a ConfigMap of its own. An
+// expired lease is deleted, provided it is still the same ConfigMap, and then
+// created again, so that a single Tiller can take it over.
+func (cfgmaps *ConfigMaps) AcquireLease(l *Lease) error {
+	obj := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: leaseKey(l.Release), Labels: leaseLabels(l)},
+		Data:       leaseData(l),
+	}
+	_, err := cfgmaps.impl.Create(obj)
+	if apierrors.IsAlreadyExists(err) {
+		var cur *v1.ConfigMap
+		if cur, err = cfgmaps.impl.Get(obj.Name, metav1.GetOptions{}); err != nil {
+			cfgmaps.Log("acquire: failed to get the lease of %q: %s", l.Release, err)
+			return err
+		}
+		held := decodeLease(l.Release, cur.Data)
+		if !held.Expired(time.Now()) {
+			return &LockedError{Lease: held}
+		}
+		cfgmaps.Log("acquire: taking over the expired lease of %q held by %s", l.Release, held.Holder)
+		opts := &metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(cur.UID))}
+		if err = cfgmaps.impl.Delete(obj.Name, opts); err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				return &LockedError{Lease: held}
+			}
+			return err
+		}
+		if _, err = cfgmaps.impl.Create(obj); apierrors.IsAlreadyExists(err) {
+			return &LockedError{Lease: held}
+		}
+	}
+	if err != nil {
+		cfgmaps.Log("acquire: failed to lock %q: %s", l.Release, err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseLease deletes the ConfigMap holding the lease on a release, if the lease
+// is held by holder or holder is empty.
+func (cfgmaps *ConfigMaps) ReleaseLease(release, holder string) (*Lease, error) {
+	cur, err := cfgmaps.impl.Get(leaseKey(release), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		cfgmaps.Log("release: failed to get the lease of %q: %s", release, err)
+		return nil, err
+	}
+	held := decodeLease(release, cur.Data)
+	if holder != "" && held.Holder != holder {
+		return nil, nil
+	}
+	opts := &metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(cur.UID))}
+	if err := cfgmaps.impl.Delete(cur.Name, opts); err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			return nil, nil
+		}
+		cfgmaps.Log("release: failed to unlock %q: %s", release, err)
+		return nil, err
+	}
+	return held, nil
+}
+
+// GetLease returns the lease on a release, or nil if there is none.
+func (cfgmaps *ConfigMaps) GetLease(release string) (*Lease, error) {
+	cur, err := cfgmaps.impl.Get(leaseKey(release), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		cfgmaps.Log("get: failed to get the lease of %q: %s", release, err)
+		return nil, err
+	}
+	return decodeLease(release, cur.Data), nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver_test
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/storage/driver/drivertest"
+)
+
+func TestConformance(t *testing.T) {
+	for _, name := range []string{"memory", "configmap", "secret"} {
+		t.Run(name, func(t *testing.T) {
+			drivertest.Run(t, func(t *testing.T) driver.Driver {
+				d, err := driver.New(name, driver.Options{
+					Namespace: "kube-system",
+					Clientset: fake.NewSimpleClientset(),
+				})
+				if err != nil {
+					t.Fatalf("Failed to create the %s driver: %s", name, err)
+				}
+				return d
+			})
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registered := driver.Registered()
+	for _, name := range []string{"configmap", "memory", "secret", "sql"} {
+		found := false
+		for _, r := range registered {
+			found = found || r == name
+		}
+		if !found {
+			t.Errorf("Expected driver %q to be registered, got %v", name, registered)
+		}
+	}
+
+	if _, err := driver.New("dynamodb", driver.Options{}); err == nil || !strings.Contains(err.Error(), `unknown storage driver "dynamodb"`) {
+		t.Errorf("Expected an unknown driver error, got %v", err)
+	}
+
+	if _, err := driver.New("configmap", driver.Options{}); err == nil {
+		t.Error("Expected the configmap driver to need a Kubernetes client")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a driver twice to panic")
+		}
+	}()
+	driver.Register("memory", func(driver.Options) (driver.Driver, error) { return driver.NewMemory(), nil })
+}
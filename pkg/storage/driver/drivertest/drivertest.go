@@ -0,0 +1,248 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package drivertest provides conformance tests for storage drivers.
+
+The authors of a storage driver run them from a test of their package:
+
+	func TestConformance(t *testing.T) {
+		drivertest.Run(t, func(t *testing.T) driver.Driver {
+			return newEmptyTestDriver(t)
+		})
+	}
+
+Each test starts from a new, empty driver.
+*/
+package drivertest // import "k8s.io/helm/pkg/storage/driver/drivertest"
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+// Run runs the conformance tests against the drivers returned by newDriver.
+// newDriver must return a new driver holding no release on every call.
+func Run(t *testing.T, newDriver func(t *testing.T) driver.Driver) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, driver.Driver)
+	}{
+		{"CreateGet", testCreateGet},
+		{"CreateExisting", testCreateExisting},
+		{"GetMissing", testGetMissing},
+		{"Update", testUpdate},
+		{"UpdateMissing", testUpdateMissing},
+		{"Delete", testDelete},
+		{"List", testList},
+		{"Query", testQuery},
+		{"Name", testName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newDriver(t))
+		})
+	}
+}
+
+// Key returns the storage key of a release revision, as Tiller computes it.
+func Key(name string, version int32) string {
+	return fmt.Sprintf("%s.v%d", name, version)
+}
+
+// Release returns a release for the conformance tests.
+func Release(name string, version int32, namespace string, code rspb.Status_Code) *rspb.Release {
+	return &rspb.Release{
+		Name:      name,
+		Version:   version,
+		Namespace: namespace,
+		Info:      &rspb.Info{Status: &rspb.Status{Code: code}},
+	}
+}
+
+func create(t *testing.T, d driver.Driver, rls *rspb.Release) {
+	t.Helper()
+	if err := d.Create(Key(rls.Name, rls.Version), rls); err != nil {
+		t.Fatalf("Failed to create release %s: %s", Key(rls.Name, rls.Version), err)
+	}
+}
+
+func testCreateGet(t *testing.T, d driver.Driver) {
+	rls := Release("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	create(t, d, rls)
+
+	got, err := d.Get(Key("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if got.Name != rls.Name || got.Version != rls.Version || got.Namespace != rls.Namespace {
+		t.Errorf("Expected release %s in %s, got %s in %s", Key(rls.Name, rls.Version), rls.Namespace, Key(got.Name, got.Version), got.Namespace)
+	}
+	if got.GetInfo().GetStatus().GetCode() != rspb.Status_DEPLOYED {
+		t.Errorf("Expected status DEPLOYED, got %s", got.GetInfo().GetStatus().GetCode())
+	}
+}
+
+func testCreateExisting(t *testing.T, d driver.Driver) {
+	rls := Release("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	create(t, d, rls)
+
+	err := d.Create(Key("smug-pigeon", 1), rls)
+	expect := storageerrors.ErrReleaseExists(Key("smug-pigeon", 1))
+	if err == nil || err.Error() != expect.Error() {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+}
+
+func testGetMissing(t *testing.T, d driver.Driver) {
+	_, err := d.Get(Key("smug-pigeon", 1))
+	expect := storageerrors.ErrReleaseNotFound(Key("smug-pigeon", 1))
+	if err == nil || err.Error() != expect.Error() {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+}
+
+func testUpdate(t *testing.T, d driver.Driver) {
+	create(t, d, Release("smug-pigeon", 1, "default", rspb.Status_DEPLOYED))
+
+	rls := Release("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED)
+	if err := d.Update(Key("smug-pigeon", 1), rls); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	got, err := d.Get(Key("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if got.GetInfo().GetStatus().GetCode() != rspb.Status_SUPERSEDED {
+		t.Errorf("Expected status SUPERSEDED, got %s", got.GetInfo().GetStatus().GetCode())
+	}
+}
+
+func testUpdateMissing(t *testing.T, d driver.Driver) {
+	if err := d.Update(Key("smug-pigeon", 1), Release("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)); err == nil {
+		t.Error("Expected an error updating a missing release")
+	}
+}
+
+func testDelete(t *testing.T, d driver.Driver) {
+	create(t, d, Release("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED))
+	create(t, d, Release("smug-pigeon", 2, "default", rspb.Status_DEPLOYED))
+
+	got, err := d.Delete(Key("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	if got == nil || got.Name != "smug-pigeon" || got.Version != 1 {
+		t.Errorf("Expected the deleted release to be returned, got %v", got)
+	}
+	if _, err := d.Get(Key("smug-pigeon", 1)); err == nil {
+		t.Error("Expected the deleted release to be gone")
+	}
+	if _, err := d.Get(Key("smug-pigeon", 2)); err != nil {
+		t.Errorf("Expected the other revision to be kept, got %s", err)
+	}
+	if _, err := d.Delete(Key("smug-pigeon", 1)); err == nil {
+		t.Error("Expected an error deleting a missing release")
+	}
+}
+
+func testList(t *testing.T, d driver.Driver) {
+	create(t, d, Release("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED))
+	create(t, d, Release("smug-pigeon", 2, "default", rspb.Status_DEPLOYED))
+	create(t, d, Release("dazzling-toad", 1, "other", rspb.Status_DEPLOYED))
+	create(t, d, Release("angry-bird", 1, "default", rspb.Status_FAILED))
+
+	all, err := d.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if got := keys(all); !equal(got, []string{"angry-bird.v1", "dazzling-toad.v1", "smug-pigeon.v1", "smug-pigeon.v2"}) {
+		t.Errorf("Expected all the releases, got %v", got)
+	}
+
+	deployed, err := d.List(func(rls *rspb.Release) bool {
+		return rls.GetInfo().GetStatus().GetCode() == rspb.Status_DEPLOYED
+	})
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if got := keys(deployed); !equal(got, []string{"dazzling-toad.v1", "smug-pigeon.v2"}) {
+		t.Errorf("Expected the deployed releases, got %v", got)
+	}
+}
+
+func testQuery(t *testing.T, d driver.Driver) {
+	create(t, d, Release("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED))
+	create(t, d, Release("smug-pigeon", 2, "default", rspb.Status_DEPLOYED))
+	create(t, d, Release("dazzling-toad", 1, "default", rspb.Status_DEPLOYED))
+
+	rls, err := d.Query(map[string]string{"NAME": "smug-pigeon", "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if got := keys(rls); !equal(got, []string{"smug-pigeon.v1", "smug-pigeon.v2"}) {
+		t.Errorf("Expected the revisions of smug-pigeon, got %v", got)
+	}
+
+	rls, err = d.Query(map[string]string{"NAME": "smug-pigeon", "OWNER": "TILLER", "STATUS": "DEPLOYED"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if got := keys(rls); !equal(got, []string{"smug-pigeon.v2"}) {
+		t.Errorf("Expected the deployed revision of smug-pigeon, got %v", got)
+	}
+
+	// a query matching nothing may either return no release or ErrReleaseNotFound
+	rls, err = d.Query(map[string]string{"NAME": "missing", "OWNER": "TILLER"})
+	if err != nil && err.Error() != storageerrors.ErrReleaseNotFound("missing").Error() {
+		t.Errorf("Expected no release or %q, got %s", storageerrors.ErrReleaseNotFound("missing"), err)
+	}
+	if len(rls) != 0 {
+		t.Errorf("Expected no release, got %v", keys(rls))
+	}
+}
+
+func testName(t *testing.T, d driver.Driver) {
+	if d.Name() == "" {
+		t.Error("Expected the driver to have a name")
+	}
+}
+
+func keys(rls []*rspb.Release) []string {
+	ks := make([]string, 0, len(rls))
+	for _, r := range rls {
+		ks = append(ks, Key(r.Name, r.Version))
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"fmt"
+	"time"
+)
+
+// lockOwner is the OWNER label of the records holding locks, which keeps them
+// out of the listings of releases.
+const lockOwner = "TILLER-LOCK"
+
+// Lease is the lock on a release, taken by the operation changing it. A lease
+// expires, so that a Tiller going away in the middle of an operation does not
+// lock the release forever.
+type Lease struct {
+	// Release is the name of the locked release.
+	Release string
+	// Holder identifies the operation holding the lease.
+	Holder string
+	// Operation is the kind of operation holding the lease, such as upgrade.
+	Operation string
+	// Acquired is the time the lease was taken.
+	Acquired time.Time
+	// Expires is the time the lease can be taken over by another operation.
+	Expires time.Time
+}
+
+// Expired reports whether the lease has ended at the given time.
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// LockedError is returned when a release is locked by another operation.
+type LockedError struct {
+	Lease *Lease
+}
+
+func (e *LockedError) Error() string {
+	l := e.Lease
+	return fmt.Sprintf("another operation (%s) is in progress on release %q since %s, held by %s until %s (run 'helm release unlock %s' if it is stuck)",
+		l.Operation, l.Release, l.Acquired.UTC().Format(time.RFC3339), l.Holder, l.Expires.UTC().Format(time.RFC3339), l.Release)
+}
+
+// Locker is implemented by drivers that can lock releases for every Tiller
+// sharing the storage.
+//
+// AcquireLease takes the lease on a release if it is free or expired, or
+// returns a *LockedError holding the current lease.
+//
+// ReleaseLease removes the lease on a release if it is held by holder, or
+// whoever holds it if holder is empty. It returns the removed lease, or nil if
+// there was none.
+//
+// GetLease returns the lease on a release, expired or not, or nil if there is
+// none.
+type Locker interface {
+	AcquireLease(l *Lease) error
+	ReleaseLease(release, holder string) (*Lease, error)
+	GetLease(release string) (*Lease, error)
+}
+
+// leaseKey returns the name of the record holding the lease on a release.
+// Release records are named NAME.vVERSION, so the two never collide.
+func leaseKey(release string) string {
+	return release + ".lock"
+}
+
+// leaseData encodes a lease into the data of a ConfigMap or Secret.
+func leaseData(l *Lease) map[string]string {
+	return map[string]string{
+		"holder":    l.Holder,
+		"operation": l.Operation,
+		"acquired":  l.Acquired.UTC().Format(time.RFC3339),
+		"expires":   l.Expires.UTC().Format(time.RFC3339),
+	}
+}
+
+// leaseLabels returns the labels of the record holding a lease.
+func leaseLabels(l *Lease) map[string]string {
+	return map[string]string{
+		"NAME":  l.Release,
+		"OWNER": lockOwner,
+	}
+}
+
+// decodeLease decodes the lease on a release from the data of a ConfigMap or
+// Secret. A lease that cannot be decoded is returned as expired, so that it
+// can be taken over.
+func decodeLease(release string, data map[string]string) *Lease {
+	l := &Lease{
+		Release:   release,
+		Holder:    data["holder"],
+		Operation: data["operation"],
+	}
+	l.Acquired, _ = time.Parse(time.RFC3339, data["acquired"])
+	l.Expires, _ = time.Parse(time.RFC3339, data["expires"])
+	return l
+}
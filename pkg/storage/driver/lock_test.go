@@ -0,0 +1,87 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"testing"
+	"time"
+)
+
+func testLocker(t *testing.T, locker Locker) {
+	now := time.Now()
+	lease := func(holder string, ttl time.Duration) *Lease {
+		return &Lease{
+			Release:   "rls-a",
+			Holder:    holder,
+			Operation: "upgrade",
+			Acquired:  now.Truncate(time.Second),
+			Expires:   now.Add(ttl).Truncate(time.Second),
+		}
+	}
+
+	if err := locker.AcquireLease(lease("tiller-1", time.Minute)); err != nil {
+		t.Fatalf("failed to acquire: %s", err)
+	}
+	err := locker.AcquireLease(lease("tiller-2", time.Minute))
+	locked, ok := err.(*LockedError)
+	if !ok || locked.Lease.Holder != "tiller-1" {
+		t.Fatalf("expected the lease to be held by tiller-1, got %v", err)
+	}
+
+	if l, err := locker.ReleaseLease("rls-a", "tiller-2"); l != nil || err != nil {
+		t.Errorf("expected tiller-2 not to release the lease, got %v, %v", l, err)
+	}
+	l, err := locker.ReleaseLease("rls-a", "tiller-1")
+	if err != nil || l == nil || l.Holder != "tiller-1" {
+		t.Fatalf("expected tiller-1 to release the lease, got %v, %v", l, err)
+	}
+	if l, err := locker.GetLease("rls-a"); l != nil || err != nil {
+		t.Errorf("expected no lease, got %v, %v", l, err)
+	}
+
+	// An expired lease is taken over.
+	if err := locker.AcquireLease(lease("tiller-1", -time.Minute)); err != nil {
+		t.Fatalf("failed to acquire: %s", err)
+	}
+	if err := locker.AcquireLease(lease("tiller-2", time.Minute)); err != nil {
+		t.Fatalf("failed to take over an expired lease: %s", err)
+	}
+	l, err = locker.GetLease("rls-a")
+	if err != nil || l == nil || l.Holder != "tiller-2" || l.Operation != "upgrade" {
+		t.Errorf("expected the lease of tiller-2, got %v, %v", l, err)
+	}
+	if !l.Expires.Equal(now.Add(time.Minute).Truncate(time.Second)) {
+		t.Errorf("unexpected expiry %s", l.Expires)
+	}
+
+	// Without a holder, any lease is released.
+	if l, err := locker.ReleaseLease("rls-a", ""); l == nil || err != nil {
+		t.Errorf("expected the lease to be released, got %v, %v", l, err)
+	}
+}
+
+func TestMemoryLocker(t *testing.T) {
+	testLocker(t, NewMemory())
+}
+
+func TestConfigMapsLocker(t *testing.T) {
+	testLocker(t, newTestFixtureCfgMaps(t))
+}
+
+func TestSecretsLocker(t *testing.T) {
+	testLocker(t, newTestFixtureSecrets(t))
+}
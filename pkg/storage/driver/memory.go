@@ -20,12 +20,16 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
-var _ Driver = (*Memory)(nil)
+var (
+	_ Driver = (*Memory)(nil)
+	_ Locker = (*Memory)(nil)
+)
 
 // MemoryDriverName is the string name of this driver.
 const MemoryDriverName = "Memory"
@@ -33,12 +37,13 @@ const MemoryDriverName = "Memory"
 // Memory is the in-memory storage driver implementation.
 type Memory struct {
 	sync.RWMutex
-	cache map[string]records
+	cache  map[string]records
+	leases map[string]*Lease
 }
 
 // NewMemory initializes a new memory driver.
 func NewMemory() *Memory {
-	return &Memory{cache: map[string]records{}}
+	return &Memory{cache: map[string]records{}, leases: map[string]*Lease{}}
 }
 
 // Name returns the name of the driver.
@@ -159,6 +164,38 @@ func (mem *Memory) Delete(key string) (*rspb.Release, error) {
 	return nil, storageerrors.ErrReleaseNotFound(key)
 }
 
+// AcquireLease takes the lease on a release, or returns a *LockedError if it
+// is held by another operation.
+func (mem *Memory) AcquireLease(l *Lease) error {
+	defer unlock(mem.wlock())
+
+	if held, ok := mem.leases[l.Release]; ok && !held.Expired(time.Now()) {
+		return &LockedError{Lease: held}
+	}
+	mem.leases[l.Release] = l
+	return nil
+}
+
+// ReleaseLease removes the lease on a release held by holder, or any lease if
+// holder is empty.
+func (mem *Memory) ReleaseLease(release, holder string) (*Lease, error) {
+	defer unlock(mem.wlock())
+
+	held, ok := mem.leases[release]
+	if !ok || (holder != "" && held.Holder != holder) {
+		return nil, nil
+	}
+	delete(mem.leases, release)
+	return held, nil
+}
+
+// GetLease returns the lease on a release, or nil if there is none.
+func (mem *Memory) GetLease(release string) (*Lease, error) {
+	defer unlock(mem.rlock())
+
+	return mem.leases[release], nil
+}
+
 // wlock locks mem for writing
 func (mem *Memory) wlock() func() {
 	mem.Lock()
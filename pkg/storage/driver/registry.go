@@ -0,0 +1,128 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options are the settings a Factory creates a driver with.
+type Options struct {
+	// Namespace is the namespace Tiller runs in.
+	Namespace string
+	// Clientset is Tiller's Kubernetes client.
+	Clientset kubernetes.Interface
+	// Config holds the driver specific settings, given to Tiller with
+	// --storage-opt or in the storage options of its configuration file.
+	Config map[string]string
+	// Log is the logger of the driver.
+	Log func(string, ...interface{})
+}
+
+// Factory creates a storage driver.
+type Factory func(opts Options) (Driver, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes a storage driver available to Tiller's --storage flag under
+// the given name. It is meant to be called from the init function of the
+// package implementing the driver, which is then linked into Tiller with a
+// blank import.
+//
+// Register panics if the name is empty, the factory is nil, or a driver is
+// already registered under the name.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if name == "" {
+		panic("storage driver: Register with an empty name")
+	}
+	if factory == nil {
+		panic("storage driver: Register factory is nil for " + name)
+	}
+	if _, dup := factories[name]; dup {
+		panic("storage driver: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// New creates the storage driver registered under the given name.
+func New(name string, opts Options) (Driver, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown storage driver %q (registered: %v)", name, Registered())
+	}
+	if opts.Log == nil {
+		opts.Log = func(_ string, _ ...interface{}) {}
+	}
+	if opts.Config == nil {
+		opts.Config = map[string]string{}
+	}
+	return factory(opts)
+}
+
+// Registered returns the sorted names of the registered storage drivers.
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register("memory", func(opts Options) (Driver, error) {
+		return NewMemory(), nil
+	})
+	Register("configmap", func(opts Options) (Driver, error) {
+		if opts.Clientset == nil {
+			return nil, fmt.Errorf("the configmap storage driver needs a Kubernetes client")
+		}
+		d := NewConfigMaps(opts.Clientset.CoreV1().ConfigMaps(opts.Namespace))
+		d.Log = opts.Log
+		return d, nil
+	})
+	Register("secret", func(opts Options) (Driver, error) {
+		if opts.Clientset == nil {
+			return nil, fmt.Errorf("the secret storage driver needs a Kubernetes client")
+		}
+		d := NewSecrets(opts.Clientset.CoreV1().Secrets(opts.Namespace))
+		d.Log = opts.Log
+		return d, nil
+	})
+	Register("sql", func(opts Options) (Driver, error) {
+		d, err := NewSQL(opts.Config["dialect"], opts.Config["connection-string"], opts.Log)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	})
+}
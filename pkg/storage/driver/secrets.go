@@ -33,7 +33,10 @@ import (
 	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
-var _ Driver = (*Secrets)(nil)
+var (
+	_ Driver = (*Secrets)(nil)
+	_ Locker = (*Secrets)(nil)
+)
 
 // SecretsDriverName is the string name of the driver.
 const SecretsDriverName = "Secret"
@@ -165,7 +168,7 @@ func (secrets *Secrets) Create(key string, rls *rspb.Release) error {
 	// push the secret object out into the kubiverse
 	if _, err := secrets.impl.Create(obj); err != nil {
 		if apierrors.IsAlreadyExists(err) {
-			return storageerrors.ErrReleaseExists(rls.Name)
+			return storageerrors.ErrReleaseExists(key)
 		}
 
 		secrets.Log("create: failed to create: %s", err)
@@ -257,3 +260,98 @@ func newSecretsObject(key string, rls *rspb.Release, lbs labels) (*v1.Secret, er
 		Data: map[string][]byte{"release": []byte(s)},
 	}, nil
 }
+
+// AcquireLease takes the lease on a release, held in a Secret of its own. An
+// expired lease is deleted, provided it is still the same Secret, and then
+// created again, so that a single Tiller can take it over.
+func (secrets *Secrets) AcquireLease(l *Lease) error {
+	obj := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: leaseKey(l.Release), Labels: leaseLabels(l)},
+		Data:       secretData(leaseData(l)),
+	}
+	_, err := secrets.impl.Create(obj)
+	if apierrors.IsAlreadyExists(err) {
+		var cur *v1.Secret
+		if cur, err = secrets.impl.Get(obj.Name, metav1.GetOptions{}); err != nil {
+			secrets.Log("acquire: failed to get the lease of %q: %s", l.Release, err)
+			return err
+		}
+		held := decodeLease(l.Release, stringData(cur.Data))
+		if !held.Expired(time.Now()) {
+			return &LockedError{Lease: held}
+		}
+		secrets.Log("acquire: taking over the expired lease of %q held by %s", l.Release, held.Holder)
+		opts := &metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(cur.UID))}
+		if err = secrets.impl.Delete(obj.Name, opts); err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				return &LockedError{Lease: held}
+			}
+			return err
+		}
+		if _, err = secrets.impl.Create(obj); apierrors.IsAlreadyExists(err) {
+			return &LockedError{Lease: held}
+		}
+	}
+	if err != nil {
+		secrets.Log("acquire: failed to lock %q: %s", l.Release, err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseLease deletes the Secret holding the lease on a release, if the lease
+// is held by holder or holder is empty.
+func (secrets *Secrets) ReleaseLease(release, holder string) (*Lease, error) {
+	cur, err := secrets.impl.Get(leaseKey(release), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		secrets.Log("release: failed to get the lease of %q: %s", release, err)
+		return nil, err
+	}
+	held := decodeLease(release, stringData(cur.Data))
+	if holder != "" && held.Holder != holder {
+		return nil, nil
+	}
+	opts := &metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(cur.UID))}
+	if err := secrets.impl.Delete(cur.Name, opts); err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			return nil, nil
+		}
+		secrets.Log("release: failed to unlock %q: %s", release, err)
+		return nil, err
+	}
+	return held, nil
+}
+
+// GetLease returns the lease on a release, or nil if there is none.
+func (secrets *Secrets) GetLease(release string) (*Lease, error) {
+	cur, err := secrets.impl.Get(leaseKey(release), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		secrets.Log("get: failed to get the lease of %q: %s", release, err)
+		return nil, err
+	}
+	return decodeLease(release, stringData(cur.Data)), nil
+}
+
+// secretData converts string data to the data of a Secret.
+func secretData(m map[string]string) map[string][]byte {
+	data := make(map[string][]byte, len(m))
+	for k, v := range m {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// stringData converts the data of a Secret to strings.
+func stringData(data map[string][]byte) map[string]string {
+	m := make(map[string]string, len(data))
+	for k, v := range data {
+		m[k] = string(v)
+	}
+	return m
+}
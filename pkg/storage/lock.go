@@ -0,0 +1,91 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// Lock takes the lock on a release for an operation, such as an upgrade, for
+// at most ttl. It returns a *driver.LockedError if another operation holds it,
+// or the function releasing the lock once the operation is over.
+//
+// Drivers that do not implement driver.Locker only lock releases within this
+// Tiller.
+func (s *Storage) Lock(name, operation string, ttl time.Duration) (func(), error) {
+	now := time.Now()
+	l := &driver.Lease{
+		Release:   name,
+		Holder:    newHolder(),
+		Operation: operation,
+		Acquired:  now,
+		Expires:   now.Add(ttl),
+	}
+	s.Log("locking release %q for %s until %s", name, operation, l.Expires.UTC().Format(time.RFC3339))
+	locker := s.locker()
+	if err := locker.AcquireLease(l); err != nil {
+		return nil, err
+	}
+	return func() {
+		if _, err := locker.ReleaseLease(name, l.Holder); err != nil {
+			s.Log("failed to unlock release %q: %s", name, err)
+		}
+	}, nil
+}
+
+// Unlock removes the lock on a release, whichever operation holds it. It
+// returns the removed lease, or nil if the release was not locked.
+func (s *Storage) Unlock(name string) (*driver.Lease, error) {
+	s.Log("unlocking release %q", name)
+	return s.locker().ReleaseLease(name, "")
+}
+
+// GetLock returns the lease on a release, or nil if it is not locked. The
+// lease may have expired.
+func (s *Storage) GetLock(name string) (*driver.Lease, error) {
+	return s.locker().GetLease(name)
+}
+
+// locker returns the driver holding the locks on releases.
+func (s *Storage) locker() driver.Locker {
+	d := s.Driver
+	if i, ok := d.(instrumented); ok {
+		d = i.Driver
+	}
+	if l, ok := d.(driver.Locker); ok {
+		return l
+	}
+	s.localOnce.Do(func() { s.local = driver.NewMemory() })
+	return s.local
+}
+
+// newHolder returns an identifier for the holder of a lock, made of the host
+// name of Tiller and a random suffix unique to the operation.
+func newHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "tiller"
+	}
+	b := make([]byte, 4)
+	rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
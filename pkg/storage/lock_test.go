@@ -0,0 +1,83 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestStorageLock(t *testing.T) {
+	storage := Init(driver.NewMemory())
+
+	unlock, err := storage.Lock("angry-beaver", "upgrade", time.Minute)
+	assertErrNil(t.Fatal, err, "Lock")
+
+	_, err = storage.Lock("angry-beaver", "rollback", time.Minute)
+	locked, ok := err.(*driver.LockedError)
+	if !ok {
+		t.Fatalf("Expected a LockedError, got %v", err)
+	}
+	if locked.Lease.Operation != "upgrade" {
+		t.Errorf("Expected the upgrade to hold the lock, got %q", locked.Lease.Operation)
+	}
+
+	unlock()
+	unlock, err = storage.Lock("angry-beaver", "rollback", time.Minute)
+	assertErrNil(t.Fatal, err, "Lock after unlock")
+	defer unlock()
+}
+
+func TestStorageLockExpired(t *testing.T) {
+	storage := Init(driver.NewMemory())
+
+	stale, err := storage.Lock("angry-beaver", "upgrade", -time.Second)
+	assertErrNil(t.Fatal, err, "Lock")
+	unlock, err := storage.Lock("angry-beaver", "rollback", time.Minute)
+	assertErrNil(t.Fatal, err, "Lock over an expired lease")
+
+	// The stale operation must not release the lock it lost.
+	stale()
+	if l, _ := storage.GetLock("angry-beaver"); l == nil || l.Operation != "rollback" {
+		t.Errorf("Expected the rollback to keep the lock, got %v", l)
+	}
+	unlock()
+	if l, _ := storage.GetLock("angry-beaver"); l != nil {
+		t.Errorf("Expected the release to be unlocked, got %v", l)
+	}
+}
+
+func TestStorageUnlock(t *testing.T) {
+	storage := Init(driver.NewMemory())
+
+	if l, err := storage.Unlock("angry-beaver"); l != nil || err != nil {
+		t.Errorf("Expected nothing to unlock, got %v, %v", l, err)
+	}
+
+	_, err := storage.Lock("angry-beaver", "install", time.Minute)
+	assertErrNil(t.Fatal, err, "Lock")
+	l, err := storage.Unlock("angry-beaver")
+	assertErrNil(t.Fatal, err, "Unlock")
+	if l == nil || l.Operation != "install" {
+		t.Errorf("Expected the install lease to be removed, got %v", l)
+	}
+	if _, err := storage.Lock("angry-beaver", "upgrade", time.Minute); err != nil {
+		t.Errorf("Expected the release to be unlocked, got %s", err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+var driverDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "tiller",
+	Subsystem: "storage",
+	Name:      "operation_duration_seconds",
+	Help:      "Latency of the storage backend, by driver and operation.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"driver", "operation"})
+
+func init() {
+	prometheus.MustRegister(driverDuration)
+}
+
+// instrumented is a driver.Driver recording the latency of the driver it wraps.
+type instrumented struct {
+	driver.Driver
+}
+
+func (d instrumented) observe(op string, start time.Time) {
+	driverDuration.WithLabelValues(d.Name(), op).Observe(time.Since(start).Seconds())
+}
+
+func (d instrumented) Create(key string, rls *rspb.Release) error {
+	defer d.observe("create", time.Now())
+	return d.Driver.Create(key, rls)
+}
+
+func (d instrumented) Update(key string, rls *rspb.Release) error {
+	defer d.observe("update", time.Now())
+	return d.Driver.Update(key, rls)
+}
+
+func (d instrumented) Delete(key string) (*rspb.Release, error) {
+	defer d.observe("delete", time.Now())
+	return d.Driver.Delete(key)
+}
+
+func (d instrumented) Get(key string) (*rspb.Release, error) {
+	defer d.observe("get", time.Now())
+	return d.Driver.Get(key)
+}
+
+func (d instrumented) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	defer d.observe("list", time.Now())
+	return d.Driver.List(filter)
+}
+
+func (d instrumented) Query(labels map[string]string) ([]*rspb.Release, error) {
+	defer d.observe("query", time.Now())
+	return d.Driver.Query(labels)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// Migration copies the release records of a storage driver to another, so
+// that Tiller can be moved to another storage backend with the history of its
+// releases.
+//
+// Records the target driver already holds unchanged are skipped, so that an
+// interrupted migration can be run again. Records it holds with different
+// contents are conflicts, and are left as they are. Every record copied is
+// read back from the target driver and compared with its source.
+type Migration struct {
+	From driver.Driver
+	To   driver.Driver
+	// DryRun lists the records to copy without writing them.
+	DryRun bool
+
+	Log func(string, ...interface{})
+}
+
+// MigrationResult lists the keys of the records of a migration, named
+// NAME.vVERSION.
+type MigrationResult struct {
+	// Copied are the records copied to the target driver, or that would be in
+	// a dry run.
+	Copied []string
+	// Skipped are the records the target driver already holds.
+	Skipped []string
+	// Conflicts are the records the target driver holds with different
+	// contents.
+	Conflicts []string
+}
+
+// Run migrates the records. It fails if a release is locked by an operation
+// in progress, as its records could change while they are copied.
+func (m *Migration) Run() (*MigrationResult, error) {
+	log := m.Log
+	if log == nil {
+		log = func(_ string, _ ...interface{}) {}
+	}
+
+	rels, err := m.From.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the releases of the %s driver: %s", m.From.Name(), err)
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		if rels[i].Name != rels[j].Name {
+			return rels[i].Name < rels[j].Name
+		}
+		return rels[i].Version < rels[j].Version
+	})
+	if err := m.checkLocks(rels); err != nil {
+		return nil, err
+	}
+
+	res := &MigrationResult{}
+	for _, rls := range rels {
+		key := makeKey(rls.Name, rls.Version)
+		existing, err := m.To.Get(key)
+		switch {
+		case err == nil && proto.Equal(existing, rls):
+			log("skipping %s, already in the %s driver", key, m.To.Name())
+			res.Skipped = append(res.Skipped, key)
+			continue
+		case err == nil:
+			log("conflict on %s, which differs in the %s driver", key, m.To.Name())
+			res.Conflicts = append(res.Conflicts, key)
+			continue
+		}
+
+		res.Copied = append(res.Copied, key)
+		if m.DryRun {
+			log("would copy %s", key)
+			continue
+		}
+		log("copying %s", key)
+		if err := m.To.Create(key, rls); err != nil {
+			return res, fmt.Errorf("cannot copy %s: %s", key, err)
+		}
+		copied, err := m.To.Get(key)
+		if err != nil {
+			return res, fmt.Errorf("cannot verify %s: %s", key, err)
+		}
+		if !proto.Equal(copied, rls) {
+			return res, fmt.Errorf("%s differs from its source once copied to the %s driver", key, m.To.Name())
+		}
+	}
+	return res, nil
+}
+
+// checkLocks fails if one of the releases is locked in the source driver.
+func (m *Migration) checkLocks(rels []*rspb.Release) error {
+	locker, ok := m.From.(driver.Locker)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	seen := map[string]bool{}
+	for _, rls := range rels {
+		if seen[rls.Name] {
+			continue
+		}
+		seen[rls.Name] = true
+		l, err := locker.GetLease(rls.Name)
+		if err != nil {
+			return err
+		}
+		if l != nil && !l.Expired(now) {
+			return &driver.LockedError{Lease: l}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func migrationFixture(t *testing.T) (from, to *driver.Memory) {
+	from, to = driver.NewMemory(), driver.NewMemory()
+	for _, tt := range []ReleaseTestData{
+		{Name: "happy-cats", Version: 2, Status: rspb.Status_DEPLOYED},
+		{Name: "angry-beaver", Version: 1, Status: rspb.Status_SUPERSEDED},
+		{Name: "happy-cats", Version: 1, Status: rspb.Status_SUPERSEDED},
+		{Name: "angry-beaver", Version: 2, Status: rspb.Status_DELETED},
+	} {
+		rls := tt.ToRelease()
+		assertErrNil(t.Fatal, from.Create(makeKey(rls.Name, rls.Version), rls), "Create")
+	}
+	return from, to
+}
+
+func TestMigration(t *testing.T) {
+	from, to := migrationFixture(t)
+
+	// an interrupted migration left the first record in the target
+	first, _ := from.Get("angry-beaver.v1")
+	assertErrNil(t.Fatal, to.Create("angry-beaver.v1", first), "Create")
+
+	res, err := (&Migration{From: from, To: to}).Run()
+	assertErrNil(t.Fatal, err, "Run")
+
+	if want := []string{"angry-beaver.v2", "happy-cats.v1", "happy-cats.v2"}; !reflect.DeepEqual(res.Copied, want) {
+		t.Errorf("Expected %v to be copied, got %v", want, res.Copied)
+	}
+	if want := []string{"angry-beaver.v1"}; !reflect.DeepEqual(res.Skipped, want) {
+		t.Errorf("Expected %v to be skipped, got %v", want, res.Skipped)
+	}
+	if len(res.Conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %v", res.Conflicts)
+	}
+
+	h, err := Init(to).History("happy-cats")
+	assertErrNil(t.Fatal, err, "History")
+	if len(h) != 2 {
+		t.Errorf("Expected 2 revisions of happy-cats, got %d", len(h))
+	}
+	if d, err := Init(to).Deployed("happy-cats"); err != nil || d.Version != 2 {
+		t.Errorf("Expected revision 2 of happy-cats to be deployed, got %v (%v)", d, err)
+	}
+}
+
+func TestMigrationDryRun(t *testing.T) {
+	from, to := migrationFixture(t)
+
+	res, err := (&Migration{From: from, To: to, DryRun: true}).Run()
+	assertErrNil(t.Fatal, err, "Run")
+	if len(res.Copied) != 4 {
+		t.Errorf("Expected 4 records to be copied, got %v", res.Copied)
+	}
+	if rels, _ := to.List(func(_ *rspb.Release) bool { return true }); len(rels) != 0 {
+		t.Errorf("Expected nothing to be written in a dry run, got %d records", len(rels))
+	}
+}
+
+func TestMigrationConflict(t *testing.T) {
+	from, to := migrationFixture(t)
+
+	other := ReleaseTestData{Name: "happy-cats", Version: 1, Manifest: "kind: ConfigMap", Status: rspb.Status_SUPERSEDED}.ToRelease()
+	assertErrNil(t.Fatal, to.Create("happy-cats.v1", other), "Create")
+
+	res, err := (&Migration{From: from, To: to}).Run()
+	assertErrNil(t.Fatal, err, "Run")
+	if want := []string{"happy-cats.v1"}; !reflect.DeepEqual(res.Conflicts, want) {
+		t.Errorf("Expected %v to conflict, got %v", want, res.Conflicts)
+	}
+	if rls, _ := to.Get("happy-cats.v1"); rls.Manifest != "kind: ConfigMap" {
+		t.Error("Expected the conflicting record to be left as it is")
+	}
+}
+
+func TestMigrationLocked(t *testing.T) {
+	from, to := migrationFixture(t)
+
+	lease := &driver.Lease{Release: "happy-cats", Holder: "tiller", Operation: "upgrade", Acquired: time.Now(), Expires: time.Now().Add(time.Minute)}
+	assertErrNil(t.Fatal, from.AcquireLease(lease), "AcquireLease")
+
+	if _, err := (&Migration{From: from, To: to}).Run(); err == nil {
+		t.Fatal("Expected the migration of a locked release to fail")
+	} else if _, ok := err.(*driver.LockedError); !ok {
+		t.Errorf("Expected a LockedError, got %v", err)
+	}
+}
@@ -19,6 +19,7 @@ package storage // import "k8s.io/helm/pkg/storage"
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -34,10 +35,17 @@ type Storage struct {
 
 	// MaxHistory specifies the maximum number of historical releases that will
 	// be retained, including the most recent release. Values of 0 or less are
-	// ignored (meaning no limits are imposed).
+	// ignored (meaning no limits are imposed). Once the storage is in use, it
+	// is changed with SetMaxHistory.
 	MaxHistory int
+	// mu guards MaxHistory.
+	mu sync.RWMutex
 
 	Log func(string, ...interface{})
+
+	// local holds the locks on releases when the driver cannot hold them.
+	local     *driver.Memory
+	localOnce sync.Once
 }
 
 // Get retrieves the release from storage. An error is returned
@@ -53,13 +61,24 @@ func (s *Storage) Get(name string, version int32) (*rspb.Release, error) {
 // release, or a release with identical key already exists.
 func (s *Storage) Create(rls *rspb.Release) error {
 	s.Log("creating release %q", makeKey(rls.Name, rls.Version))
-	if s.MaxHistory > 0 {
+	s.mu.RLock()
+	max := s.MaxHistory
+	s.mu.RUnlock()
+	if max > 0 {
 		// Want to make space for one more release.
-		s.removeLeastRecent(rls.Name, s.MaxHistory-1)
+		s.removeLeastRecent(rls.Name, max-1)
 	}
 	return s.Driver.Create(makeKey(rls.Name, rls.Version), rls)
 }
 
+// SetMaxHistory changes the maximum number of historical releases retained,
+// while the storage may be in use.
+func (s *Storage) SetMaxHistory(max int) {
+	s.mu.Lock()
+	s.MaxHistory = max
+	s.mu.Unlock()
+}
+
 // Update update the release in storage. An error is returned if the
 // storage backend fails to update the release or if the release
 // does not exist.
@@ -259,15 +278,15 @@ func makeKey(rlsname string, version int32) string {
 	return fmt.Sprintf("%s.v%d", rlsname, version)
 }
 
-// Init initializes a new storage backend with the driver d.
-// If d is nil, the default in-memory driver is used.
+// Init initializes a new storage backend with the driver d, recording its
+// latency. If d is nil, the default in-memory driver is used.
 func Init(d driver.Driver) *Storage {
 	// default driver is in memory
 	if d == nil {
 		d = driver.NewMemory()
 	}
 	return &Storage{
-		Driver: d,
+		Driver: instrumented{d},
 		Log:    func(_ string, _ ...interface{}) {},
 	}
 }
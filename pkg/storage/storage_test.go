@@ -21,6 +21,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/storage/driver"
 )
@@ -47,6 +50,28 @@ func TestStorageCreate(t *testing.T) {
 	}
 }
 
+func TestStorageLatency(t *testing.T) {
+	storage := Init(driver.NewMemory())
+	if name := storage.Name(); name != driver.MemoryDriverName {
+		t.Errorf("Expected driver %q, got %q", driver.MemoryDriverName, name)
+	}
+
+	gets := driverDuration.WithLabelValues(driver.MemoryDriverName, "get").(prometheus.Histogram)
+	count := func() uint64 {
+		m := &dto.Metric{}
+		if err := gets.Write(m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	before := count()
+	storage.Get("angry-beaver", 1)
+	if got := count() - before; got != 1 {
+		t.Errorf("Expected 1 timed get, got %d", got)
+	}
+}
+
 func TestStorageUpdate(t *testing.T) {
 	// initialize storage
 	storage := Init(driver.NewMemory())
@@ -0,0 +1,101 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strvals
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten converts values to set lines, one for each leaf value, in the form
+// path.to.key=value. The keys are sorted, and list items are written as
+// name[0]=value.
+//
+// The lines are escaped so that Parse reads them back, with the exception of
+// empty maps and lists, which a set line cannot express. They are written as
+// name={} and name=[] so that they still show up.
+func Flatten(vals map[string]interface{}) []string {
+	var lines []string
+	flattenMap("", vals, &lines)
+	return lines
+}
+
+func flattenMap(prefix string, m map[string]interface{}, lines *[]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := escape(k, ".=[,")
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		flattenValue(name, m[k], lines)
+	}
+}
+
+func flattenValue(name string, v interface{}, lines *[]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			*lines = append(*lines, name+"={}")
+			return
+		}
+		flattenMap(name, v, lines)
+	case []interface{}:
+		if len(v) == 0 {
+			*lines = append(*lines, name+"=[]")
+			return
+		}
+		for i, item := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", name, i), item, lines)
+		}
+	default:
+		*lines = append(*lines, name+"="+formatValue(v))
+	}
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return escape(v, ",")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return escape(fmt.Sprint(v), ",")
+	}
+}
+
+// escape puts a backslash before the backslashes and the given special
+// characters of s.
+func escape(s, special string) string {
+	if !strings.ContainsAny(s, special+`\`) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\\' || strings.ContainsRune(special, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strvals
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestFlatten(t *testing.T) {
+	doc := `
+name: alpine
+replicas: 2
+ratio: 0.5
+enabled: true
+empty: null
+labels: {}
+args: []
+image:
+  repository: alpine
+  tag: "3.9"
+hosts:
+- name: example.com
+  paths: [/, /api]
+"dotted.key": a,b
+`
+	vals := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(doc), &vals); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		"args=[]",
+		`dotted\.key=a\,b`,
+		"empty=null",
+		"enabled=true",
+		"hosts[0].name=example.com",
+		"hosts[0].paths[0]=/",
+		"hosts[0].paths[1]=/api",
+		"image.repository=alpine",
+		"image.tag=3.9",
+		"labels={}",
+		"name=alpine",
+		"ratio=0.5",
+		"replicas=2",
+	}
+	lines := Flatten(vals)
+	if !reflect.DeepEqual(lines, expect) {
+		t.Fatalf("expected\n%s\ngot\n%s", strings.Join(expect, "\n"), strings.Join(lines, "\n"))
+	}
+
+	// Apart from the empty map and list, the lines are read back to the same values.
+	var set []string
+	for _, l := range lines {
+		if !strings.HasSuffix(l, "={}") && !strings.HasSuffix(l, "=[]") {
+			set = append(set, l)
+		}
+	}
+	parsed, err := Parse(strings.Join(set, ","))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed["dotted.key"]; got != "a,b" {
+		t.Errorf("expected the escaped key to read back as %q, got %q", "a,b", got)
+	}
+	if got := parsed["image"].(map[string]interface{})["repository"]; got != "alpine" {
+		t.Errorf("expected image.repository to read back as alpine, got %v", got)
+	}
+	hosts := parsed["hosts"].([]interface{})
+	if got := hosts[0].(map[string]interface{})["paths"].([]interface{})[1]; got != "/api" {
+		t.Errorf("expected hosts[0].paths[1] to read back as /api, got %v", got)
+	}
+}
@@ -0,0 +1,389 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config describes Tiller's operational configuration.
+//
+// The configuration is read from a YAML file, usually a ConfigMap mounted into
+// the Tiller pod, and may be reloaded while Tiller is running.
+package config // import "k8s.io/helm/pkg/tiller/config"
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+// Config is the operational configuration of Tiller.
+type Config struct {
+	// HistoryMax is the maximum number of releases kept in release history,
+	// with 0 meaning no limit. A nil value leaves the command line setting
+	// untouched.
+	HistoryMax *int `json:"historyMax,omitempty"`
+	// Storage holds the options of the storage driver.
+	Storage Storage `json:"storage,omitempty"`
+	// Policy restricts where releases may be managed.
+	Policy Policy `json:"policy,omitempty"`
+	// Webhooks are notified after release operations complete.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+	// Hooks holds the options applied to hook resources.
+	Hooks Hooks `json:"hooks,omitempty"`
+	// Impersonation selects the service accounts releases are applied as.
+	Impersonation Impersonation `json:"impersonation,omitempty"`
+	// Limits caps what charts may render.
+	Limits Limits `json:"limits,omitempty"`
+	// Audit selects where the release operations are recorded.
+	Audit Audit `json:"audit,omitempty"`
+	// Windows restricts installs, upgrades, rollbacks and deletions to
+	// maintenance windows.
+	Windows []MaintenanceWindow `json:"windows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period during which releases may be
+// changed. When windows apply to a namespace, releases in it may only be
+// changed while one of them is open.
+type MaintenanceWindow struct {
+	Name string `json:"name"`
+	// Schedule is a cron expression of the times the window opens.
+	Schedule string `json:"schedule"`
+	// Duration is how long the window stays open, such as "4h".
+	Duration string `json:"duration"`
+	// Timezone is the time zone the schedule is read in, UTC by default.
+	Timezone string `json:"timezone,omitempty"`
+	// Namespaces limits the window to the given namespaces. An empty list
+	// applies it to every namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// The kinds of audit sinks.
+const (
+	AuditFile    = "file"
+	AuditEvents  = "events"
+	AuditWebhook = "webhook"
+)
+
+// Audit selects where the release operations are recorded.
+type Audit struct {
+	// Sinks each receive an entry for every install, upgrade, rollback and
+	// deletion. The first file or events sink is also read back to answer
+	// queries of the audit log.
+	Sinks []AuditSink `json:"sinks,omitempty"`
+}
+
+// AuditSink is a destination of the audit entries.
+type AuditSink struct {
+	// Type is one of file, events or webhook.
+	Type string `json:"type"`
+	// Path is the file the entries are appended to, as JSON lines.
+	Path string `json:"path,omitempty"`
+	// URL receives each entry, posted as JSON.
+	URL string `json:"url,omitempty"`
+}
+
+// Limits caps what a chart may render, so that a chart rendering far more
+// than expected fails before anything is applied to the cluster.
+type Limits struct {
+	// ChartLimits are the limits of every chart.
+	ChartLimits
+	// Charts overrides the limits of the charts with the given names.
+	Charts map[string]ChartLimits `json:"charts,omitempty"`
+}
+
+// ChartLimits are the limits applied to the releases of a chart. A zero
+// value means no limit.
+type ChartLimits struct {
+	// MaxManifestBytes is the maximum size of the rendered manifest and hooks.
+	MaxManifestBytes int64 `json:"maxManifestBytes,omitempty"`
+	// MaxObjects is the maximum number of objects in the rendered manifest.
+	MaxObjects int64 `json:"maxObjects,omitempty"`
+	// MaxHooks is the maximum number of hooks.
+	MaxHooks int64 `json:"maxHooks,omitempty"`
+}
+
+// Impersonation maps namespaces to the service accounts Tiller impersonates
+// to apply the releases in them, so each tenant's releases are applied with
+// the privileges of that tenant only.
+type Impersonation struct {
+	// ServiceAccounts maps namespaces to the name of a service account in
+	// that namespace. The "*" key applies to the namespaces not listed.
+	ServiceAccounts map[string]string `json:"serviceAccounts,omitempty"`
+	// AllowOverride lets clients select another service account of the
+	// namespace than the mapped one.
+	AllowOverride bool `json:"allowOverride,omitempty"`
+}
+
+// Hooks holds the options applied to hook resources.
+type Hooks struct {
+	// OwnerReferences makes the hook resources of common namespaced kinds,
+	// such as Pods and Jobs, owned by a per-release anchor ConfigMap, so
+	// Kubernetes garbage collects any left behind once the release is deleted.
+	OwnerReferences bool `json:"ownerReferences,omitempty"`
+}
+
+// Storage holds the storage driver options.
+//
+// The driver itself is selected at startup. A change of driver is reported
+// but only takes effect when Tiller is restarted.
+type Storage struct {
+	Driver              string `json:"driver,omitempty"`
+	SQLDialect          string `json:"sqlDialect,omitempty"`
+	SQLConnectionString string `json:"sqlConnectionString,omitempty"`
+	// Options are driver specific settings, as given with --storage-opt.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// Policy holds the allow and deny lists of namespaces releases may target.
+//
+// An empty allow list allows every namespace. The deny list always wins.
+type Policy struct {
+	AllowNamespaces []string `json:"allowNamespaces,omitempty"`
+	DenyNamespaces  []string `json:"denyNamespaces,omitempty"`
+	// RequireCreateNamespace makes installs into a namespace that does not
+	// exist fail, unless they ask for it to be created. By default the
+	// namespace is created implicitly.
+	RequireCreateNamespace bool `json:"requireCreateNamespace,omitempty"`
+}
+
+// Webhook is an endpoint that receives release events.
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Events limits the notifications to the named operations (install,
+	// upgrade, rollback, delete). An empty list receives every event.
+	Events []string `json:"events,omitempty"`
+}
+
+// Load reads and validates the configuration stored at path.
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses and validates a YAML configuration.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("could not parse tiller config: %s", err)
+	}
+	return c, c.Validate()
+}
+
+// Validate checks the configuration for errors.
+func (c *Config) Validate() error {
+	if c.HistoryMax != nil && *c.HistoryMax < 0 {
+		return fmt.Errorf("historyMax must not be negative, got %d", *c.HistoryMax)
+	}
+	if err := c.Limits.validate(""); err != nil {
+		return err
+	}
+	for name, l := range c.Limits.Charts {
+		if err := l.validate(name); err != nil {
+			return err
+		}
+	}
+	for ns, sa := range c.Impersonation.ServiceAccounts {
+		if sa == "" || strings.Contains(sa, ":") {
+			return fmt.Errorf("namespace %q is mapped to an invalid service account %q", ns, sa)
+		}
+	}
+	for i, sink := range c.Audit.Sinks {
+		switch sink.Type {
+		case AuditFile:
+			if sink.Path == "" {
+				return fmt.Errorf("audit sink %d is missing a path", i)
+			}
+		case AuditEvents:
+		case AuditWebhook:
+			u, err := url.Parse(sink.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				return fmt.Errorf("audit sink %d has an invalid URL %q", i, sink.URL)
+			}
+		default:
+			return fmt.Errorf("audit sink %d has an unknown type %q, expected file, events or webhook", i, sink.Type)
+		}
+	}
+	for _, w := range c.Windows {
+		if w.Name == "" {
+			return fmt.Errorf("maintenance window %q is missing a name", w.Schedule)
+		}
+		if _, err := w.Window(); err != nil {
+			return fmt.Errorf("maintenance window %q: %s", w.Name, err)
+		}
+	}
+	for _, w := range c.Webhooks {
+		if w.Name == "" {
+			return fmt.Errorf("webhook %q is missing a name", w.URL)
+		}
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("webhook %q has an invalid URL %q", w.Name, w.URL)
+		}
+	}
+	return nil
+}
+
+// AllowsNamespace returns an error if the policy forbids managing releases in
+// the given namespace.
+func (p Policy) AllowsNamespace(ns string) error {
+	for _, d := range p.DenyNamespaces {
+		if d == ns {
+			return fmt.Errorf("namespace %q is denied by tiller policy", ns)
+		}
+	}
+	if len(p.AllowNamespaces) == 0 {
+		return nil
+	}
+	for _, a := range p.AllowNamespaces {
+		if a == ns {
+			return nil
+		}
+	}
+	return fmt.Errorf("namespace %q is not allowed by tiller policy", ns)
+}
+
+// For returns the limits of the chart with the given name. Each limit set for
+// the chart replaces the one set for every chart.
+func (l Limits) For(chart string) ChartLimits {
+	limits := l.ChartLimits
+	c, ok := l.Charts[chart]
+	if !ok {
+		return limits
+	}
+	if c.MaxManifestBytes != 0 {
+		limits.MaxManifestBytes = c.MaxManifestBytes
+	}
+	if c.MaxObjects != 0 {
+		limits.MaxObjects = c.MaxObjects
+	}
+	if c.MaxHooks != 0 {
+		limits.MaxHooks = c.MaxHooks
+	}
+	return limits
+}
+
+// Check returns an error naming the first limit exceeded by a chart rendering
+// a manifest and hooks of the given size, objects and hooks.
+func (l ChartLimits) Check(chart string, manifestBytes, objects, hooks int64) error {
+	switch {
+	case l.MaxManifestBytes > 0 && manifestBytes > l.MaxManifestBytes:
+		return fmt.Errorf("chart %q renders %d bytes, more than the limit of %d bytes set by tiller", chart, manifestBytes, l.MaxManifestBytes)
+	case l.MaxObjects > 0 && objects > l.MaxObjects:
+		return fmt.Errorf("chart %q renders %d objects, more than the limit of %d objects set by tiller", chart, objects, l.MaxObjects)
+	case l.MaxHooks > 0 && hooks > l.MaxHooks:
+		return fmt.Errorf("chart %q renders %d hooks, more than the limit of %d hooks set by tiller", chart, hooks, l.MaxHooks)
+	}
+	return nil
+}
+
+func (l ChartLimits) validate(chart string) error {
+	if l.MaxManifestBytes < 0 || l.MaxObjects < 0 || l.MaxHooks < 0 {
+		if chart == "" {
+			return errors.New("limits must not be negative")
+		}
+		return fmt.Errorf("limits of chart %q must not be negative", chart)
+	}
+	return nil
+}
+
+// ServiceAccountFor returns the service account releases in namespace are
+// applied as, given the one requested by the client, if any. An empty result
+// means that Tiller applies the release with its own identity.
+func (i Impersonation) ServiceAccountFor(namespace, requested string) (string, error) {
+	mapped, ok := i.ServiceAccounts[namespace]
+	if !ok {
+		mapped = i.ServiceAccounts["*"]
+	}
+	switch {
+	case requested == "" || requested == mapped:
+		return mapped, nil
+	case mapped == "" || i.AllowOverride:
+		return requested, nil
+	}
+	return "", fmt.Errorf("service account %q is not allowed in namespace %q by tiller policy", requested, namespace)
+}
+
+// Window parses the schedule of the maintenance window.
+func (m MaintenanceWindow) Window() (*releaseutil.Window, error) {
+	return releaseutil.ParseWindow(m.Schedule, m.Duration, m.Timezone)
+}
+
+// AppliesTo returns true if the maintenance window restricts releases in the
+// given namespace.
+func (m MaintenanceWindow) AppliesTo(namespace string) bool {
+	if len(m.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range m.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Wants returns true if the webhook subscribes to the given event.
+func (w Webhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Store holds the current configuration and can be safely read while a reload
+// replaces it.
+type Store struct {
+	mu  sync.RWMutex
+	cfg *Config
+}
+
+// NewStore returns a store holding an empty configuration.
+func NewStore() *Store {
+	return &Store{cfg: &Config{}}
+}
+
+// Get returns the current configuration. It never returns nil.
+func (s *Store) Get() *Config {
+	if s == nil {
+		return &Config{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Set replaces the current configuration.
+func (s *Store) Set(c *Config) {
+	if c == nil {
+		c = &Config{}
+	}
+	s.mu.Lock()
+	s.cfg = c
+	s.mu.Unlock()
+}
@@ -0,0 +1,222 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `
+historyMax: 10
+policy:
+  allowNamespaces: [team-a, team-b]
+  denyNamespaces: [team-b]
+webhooks:
+- name: audit
+  url: https://example.com/hook
+  events: [install, delete]
+audit:
+  sinks:
+  - type: file
+    path: /var/log/tiller/audit.log
+  - type: events
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HistoryMax == nil || *c.HistoryMax != 10 {
+		t.Errorf("expected historyMax 10, got %v", c.HistoryMax)
+	}
+	if len(c.Webhooks) != 1 || c.Webhooks[0].Name != "audit" {
+		t.Errorf("unexpected webhooks: %v", c.Webhooks)
+	}
+	if len(c.Audit.Sinks) != 2 || c.Audit.Sinks[0].Path != "/var/log/tiller/audit.log" || c.Audit.Sinks[1].Type != AuditEvents {
+		t.Errorf("unexpected audit sinks: %v", c.Audit.Sinks)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"historyMax: -1",
+		"webhooks:\n- name: x\n  url: ftp://example.com",
+		"webhooks:\n- url: https://example.com",
+		"impersonation:\n  serviceAccounts:\n    team-a: system:serviceaccount:team-a:deployer",
+		"limits:\n  maxObjects: -1",
+		"limits:\n  charts:\n    big:\n      maxHooks: -2",
+		"audit:\n  sinks:\n  - type: file",
+		"audit:\n  sinks:\n  - type: webhook\n    url: example.com",
+		"audit:\n  sinks:\n  - type: syslog",
+		"windows:\n- schedule: 0 2 * * 6\n  duration: 4h",
+		"windows:\n- name: weekend\n  schedule: 0 2 * *\n  duration: 4h",
+		"windows:\n- name: weekend\n  schedule: 0 2 * * 6",
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt)); err == nil {
+			t.Errorf("expected error for %q", tt)
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	c, err := Parse([]byte("limits:\n  maxManifestBytes: 1000\n  maxObjects: 10\n  charts:\n    big:\n      maxObjects: 50\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := c.Limits.For("small"); l != (ChartLimits{MaxManifestBytes: 1000, MaxObjects: 10}) {
+		t.Errorf("unexpected limits for a chart without overrides: %+v", l)
+	}
+	big := c.Limits.For("big")
+	if big != (ChartLimits{MaxManifestBytes: 1000, MaxObjects: 50}) {
+		t.Errorf("unexpected limits for a chart with overrides: %+v", big)
+	}
+
+	if err := big.Check("big", 1000, 50, 100); err != nil {
+		t.Errorf("expected a chart at the limits to pass, got %s", err)
+	}
+	if err := big.Check("big", 1001, 1, 0); err == nil {
+		t.Error("expected a chart over the size limit to fail")
+	}
+	expect := `chart "big" renders 51 objects, more than the limit of 50 objects set by tiller`
+	if err := big.Check("big", 1, 51, 0); err == nil || err.Error() != expect {
+		t.Errorf("expected %q, got %v", expect, err)
+	}
+}
+
+func TestPolicyAllowsNamespace(t *testing.T) {
+	c, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"team-a":      true,
+		"team-b":      false,
+		"kube-system": false,
+	}
+	for ns, want := range tests {
+		if got := c.Policy.AllowsNamespace(ns) == nil; got != want {
+			t.Errorf("namespace %s: expected allowed=%t, got %t", ns, want, got)
+		}
+	}
+	if err := (Policy{}).AllowsNamespace("anything"); err != nil {
+		t.Errorf("empty policy should allow everything, got %s", err)
+	}
+}
+
+func TestImpersonationServiceAccountFor(t *testing.T) {
+	i := Impersonation{ServiceAccounts: map[string]string{"team-a": "deployer", "*": "restricted"}}
+	tests := []struct {
+		namespace, requested, want string
+		err                        bool
+	}{
+		{"team-a", "", "deployer", false},
+		{"team-a", "deployer", "deployer", false},
+		{"team-a", "admin", "", true},
+		{"team-b", "", "restricted", false},
+	}
+	for _, tt := range tests {
+		got, err := i.ServiceAccountFor(tt.namespace, tt.requested)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%s/%q: expected %q (error %t), got %q (%v)", tt.namespace, tt.requested, tt.want, tt.err, got, err)
+		}
+	}
+
+	i.AllowOverride = true
+	if got, err := i.ServiceAccountFor("team-a", "admin"); err != nil || got != "admin" {
+		t.Errorf("expected the override to be allowed, got %q (%v)", got, err)
+	}
+	if got, _ := (Impersonation{}).ServiceAccountFor("team-a", "admin"); got != "admin" {
+		t.Errorf("expected the requested service account without mapping, got %q", got)
+	}
+}
+
+func TestMaintenanceWindowAppliesTo(t *testing.T) {
+	all := MaintenanceWindow{Name: "all"}
+	some := MaintenanceWindow{Name: "some", Namespaces: []string{"prod"}}
+	for _, tt := range []struct {
+		w         MaintenanceWindow
+		namespace string
+		expected  bool
+	}{
+		{all, "dev", true},
+		{some, "prod", true},
+		{some, "dev", false},
+	} {
+		if got := tt.w.AppliesTo(tt.namespace); got != tt.expected {
+			t.Errorf("%s in %s: expected %t, got %t", tt.w.Name, tt.namespace, tt.expected, got)
+		}
+	}
+}
+
+func TestWebhookWants(t *testing.T) {
+	w := Webhook{Events: []string{"install"}}
+	if !w.Wants("install") || w.Wants("upgrade") {
+		t.Error("webhook should only want install events")
+	}
+	if !(Webhook{}).Wants("upgrade") {
+		t.Error("webhook without events should want every event")
+	}
+}
+
+func TestWatcherCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tiller-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+
+	var reloads int
+	store := NewStore()
+	w := NewWatcher(path, func(c *Config) {
+		reloads++
+		store.Set(c)
+	})
+
+	if err := ioutil.WriteFile(path, []byte("historyMax: 3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Check() {
+		t.Error("expected first check to load the config")
+	}
+	if w.Check() {
+		t.Error("expected unchanged config not to reload")
+	}
+	if err := ioutil.WriteFile(path, []byte("historyMax: -3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if w.Check() {
+		t.Error("expected invalid config to be ignored")
+	}
+	if err := ioutil.WriteFile(path, []byte("historyMax: 5"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Check()
+
+	if reloads != 2 {
+		t.Errorf("expected 2 reloads, got %d", reloads)
+	}
+	if hm := store.Get().HistoryMax; hm == nil || *hm != 5 {
+		t.Errorf("expected historyMax 5, got %v", hm)
+	}
+}
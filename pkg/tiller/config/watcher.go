@@ -0,0 +1,92 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"time"
+)
+
+// DefaultWatchInterval is how often a Watcher checks the file for changes.
+const DefaultWatchInterval = 10 * time.Second
+
+// Watcher polls a configuration file and calls OnChange whenever its content
+// changes.
+//
+// Files from a mounted ConfigMap are replaced by the kubelet through a symlink
+// swap, which file system notifications do not reliably report. Comparing the
+// content on an interval works for both plain files and ConfigMap volumes.
+type Watcher struct {
+	Path     string
+	Interval time.Duration
+	// OnChange is called with the new configuration after each change.
+	OnChange func(*Config)
+	Log      func(string, ...interface{})
+
+	sum []byte
+}
+
+// NewWatcher creates a watcher for the file at path.
+func NewWatcher(path string, onChange func(*Config)) *Watcher {
+	return &Watcher{
+		Path:     path,
+		Interval: DefaultWatchInterval,
+		OnChange: onChange,
+		Log:      func(_ string, _ ...interface{}) {},
+	}
+}
+
+// Check reads the file once and calls OnChange if the content differs from
+// the last successful read. An invalid configuration is logged and ignored so
+// the previous configuration stays in effect.
+func (w *Watcher) Check() bool {
+	b, err := ioutil.ReadFile(w.Path)
+	if err != nil {
+		w.Log("could not read config %s: %s", w.Path, err)
+		return false
+	}
+	sum := sha256.Sum256(b)
+	if bytes.Equal(sum[:], w.sum) {
+		return false
+	}
+	c, err := Parse(b)
+	if err != nil {
+		w.Log("ignoring invalid config %s: %s", w.Path, err)
+		return false
+	}
+	w.sum = sum[:]
+	w.OnChange(c)
+	return true
+}
+
+// Run checks the file on every interval until stop is closed.
+func (w *Watcher) Run(stop <-chan struct{}) {
+	t := time.NewTicker(w.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if w.Check() {
+				w.Log("reloaded config from %s", w.Path)
+			}
+		}
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/config"
 )
 
 const (
@@ -110,6 +111,12 @@ type KubeClient interface {
 	// by "\n---\n").
 	Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) error
 
+	// CreateWithOptions creates one or more resources.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	CreateWithOptions(namespace string, reader io.Reader, opts kube.CreateOptions) error
+
 	// Get gets one or more resources. Returned string hsa the format like kubectl
 	// provides with the column headers separating the resource types.
 	//
@@ -137,6 +144,14 @@ type KubeClient interface {
 	// by "\n---\n").
 	DeleteWithTimeout(namespace string, reader io.Reader, timeout int64, shouldWait bool) error
 
+	// DeleteWithOptions destroys one or more resources, as controlled by opts.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	DeleteWithOptions(namespace string, reader io.Reader, opts kube.DeleteOptions) error
+
 	// WatchUntilReady watch the resource in reader until it is "ready".
 	//
 	// For Jobs, "ready" means the job ran to completion (excited without error).
@@ -176,6 +191,28 @@ type KubeClient interface {
 	WaitAndGetCompletedPodPhase(namespace string, reader io.Reader, timeout time.Duration) (v1.PodPhase, error)
 
 	WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration) error
+
+	// GetPodLogs returns the logs of the pod in reader, or of the latest pod
+	// of the job in reader, keeping at most limitBytes from the end of the
+	// output.
+	GetPodLogs(namespace string, reader io.Reader, limitBytes int64) (string, error)
+
+	// GetLive returns the resources in reader as they are currently found in
+	// the cluster, as a YAML stream in the order of reader. Fields populated
+	// by the cluster, such as status, are left out.
+	GetLive(namespace string, reader io.Reader) (string, error)
+
+	// UpdateMetadata sets and removes labels and annotations of the resources
+	// in reader.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated by "\n---\n").
+	UpdateMetadata(namespace string, reader io.Reader, change kube.MetadataChange) error
+
+	// Health returns the current state of each resource in reader, as found
+	// in the cluster.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated by "\n---\n").
+	Health(namespace string, reader io.Reader) ([]kube.ReadinessEvent, error)
 }
 
 // PrintingKubeClient implements KubeClient, but simply prints the reader to
@@ -190,6 +227,12 @@ func (p *PrintingKubeClient) Create(ns string, r io.Reader, timeout int64, shoul
 	return err
 }
 
+// CreateWithOptions prints the values of what would be created with a real KubeClient.
+func (p *PrintingKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	_, err := io.Copy(p.Out, r)
+	return err
+}
+
 // Get prints the values of what would be created with a real KubeClient.
 func (p *PrintingKubeClient) Get(ns string, r io.Reader) (string, error) {
 	_, err := io.Copy(p.Out, r)
@@ -212,6 +255,14 @@ func (p *PrintingKubeClient) DeleteWithTimeout(ns string, r io.Reader, timeout i
 	return err
 }
 
+// DeleteWithOptions implements KubeClient DeleteWithOptions.
+//
+// It only prints out the content to be deleted.
+func (p *PrintingKubeClient) DeleteWithOptions(ns string, r io.Reader, opts kube.DeleteOptions) error {
+	_, err := io.Copy(p.Out, r)
+	return err
+}
+
 // WatchUntilReady implements KubeClient WatchUntilReady.
 func (p *PrintingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	_, err := io.Copy(p.Out, r)
@@ -255,12 +306,36 @@ func (p *PrintingKubeClient) WaitAndGetCompletedPodPhase(namespace string, reade
 	return v1.PodUnknown, err
 }
 
+// GetPodLogs implements KubeClient GetPodLogs.
+func (p *PrintingKubeClient) GetPodLogs(namespace string, reader io.Reader, limitBytes int64) (string, error) {
+	_, err := io.Copy(p.Out, reader)
+	return "", err
+}
+
+// GetLive implements KubeClient GetLive.
+func (p *PrintingKubeClient) GetLive(namespace string, reader io.Reader) (string, error) {
+	_, err := io.Copy(p.Out, reader)
+	return "", err
+}
+
 // WaitUntilCRDEstablished implements KubeClient WaitUntilCRDEstablished.
 func (p *PrintingKubeClient) WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration) error {
 	_, err := io.Copy(p.Out, reader)
 	return err
 }
 
+// UpdateMetadata implements KubeClient UpdateMetadata.
+func (p *PrintingKubeClient) UpdateMetadata(namespace string, reader io.Reader, change kube.MetadataChange) error {
+	_, err := io.Copy(p.Out, reader)
+	return err
+}
+
+// Health implements KubeClient Health.
+func (p *PrintingKubeClient) Health(namespace string, reader io.Reader) ([]kube.ReadinessEvent, error) {
+	_, err := io.Copy(p.Out, reader)
+	return []kube.ReadinessEvent{}, err
+}
+
 // Environment provides the context for executing a client request.
 //
 // All services in a context are concurrency safe.
@@ -271,6 +346,13 @@ type Environment struct {
 	Releases *storage.Storage
 	// KubeClient is a Kubernetes API client.
 	KubeClient KubeClient
+	// Impersonate returns a Kubernetes API client acting as the given user,
+	// member of the given groups. When nil, releases are always applied with
+	// KubeClient.
+	Impersonate func(user string, groups []string) KubeClient
+	// Config holds the operational configuration, which may be reloaded at
+	// any time.
+	Config *config.Store
 }
 
 // New returns an environment initialized with the defaults.
@@ -285,5 +367,6 @@ func New() *Environment {
 	return &Environment{
 		EngineYard: ey,
 		Releases:   storage.Init(driver.NewMemory()),
+		Config:     config.NewStore(),
 	}
 }
@@ -43,6 +43,9 @@ type mockKubeClient struct{}
 func (k *mockKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	return nil
 }
+func (k *mockKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	return nil
+}
 func (k *mockKubeClient) Get(ns string, r io.Reader) (string, error) {
 	return "", nil
 }
@@ -52,6 +55,9 @@ func (k *mockKubeClient) Delete(ns string, r io.Reader) error {
 func (k *mockKubeClient) DeleteWithTimeout(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	return nil
 }
+func (k *mockKubeClient) DeleteWithOptions(ns string, r io.Reader, opts kube.DeleteOptions) error {
+	return nil
+}
 func (k *mockKubeClient) Update(ns string, currentReader, modifiedReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error {
 	return nil
 }
@@ -74,6 +80,14 @@ func (k *mockKubeClient) WaitAndGetCompletedPodPhase(namespace string, reader io
 	return v1.PodUnknown, nil
 }
 
+func (k *mockKubeClient) GetPodLogs(namespace string, reader io.Reader, limitBytes int64) (string, error) {
+	return "", nil
+}
+
+func (k *mockKubeClient) GetLive(namespace string, reader io.Reader) (string, error) {
+	return "", nil
+}
+
 func (k *mockKubeClient) WaitAndGetCompletedPodStatus(namespace string, reader io.Reader, timeout time.Duration) (v1.PodPhase, error) {
 	return "", nil
 }
@@ -82,6 +96,14 @@ func (k *mockKubeClient) WaitUntilCRDEstablished(reader io.Reader, timeout time.
 	return nil
 }
 
+func (k *mockKubeClient) UpdateMetadata(namespace string, reader io.Reader, change kube.MetadataChange) error {
+	return nil
+}
+
+func (k *mockKubeClient) Health(namespace string, reader io.Reader) ([]kube.ReadinessEvent, error) {
+	return []kube.ReadinessEvent{}, nil
+}
+
 var _ Engine = &mockEngine{}
 var _ KubeClient = &mockKubeClient{}
 var _ KubeClient = &PrintingKubeClient{}
@@ -0,0 +1,91 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// maxHookLogBytes is how much of the end of the logs of a hook is kept in the
+// release.
+const maxHookLogBytes = 16 * 1024
+
+// defaultHookRetryBackoff is how long to wait before retrying a hook without
+// a helm.sh/hook-retry-backoff annotation.
+var defaultHookRetryBackoff = 5 * time.Second
+
+// hookRollbackError is the error of a hook with the rollback failure policy,
+// asking for the operation running it to be undone.
+type hookRollbackError struct {
+	err error
+}
+
+func (e *hookRollbackError) Error() string {
+	return e.err.Error()
+}
+
+// hookRollbackRequested reports whether err is the error of a hook asking for
+// the operation running it to be undone.
+func hookRollbackRequested(err error) bool {
+	_, ok := err.(*hookRollbackError)
+	return ok
+}
+
+// rollbackFailedInstall deletes a release whose install was failed by a hook
+// with the rollback failure policy. The history of the release is kept, so
+// that the logs of its hooks can be read.
+func (s *ReleaseServer) rollbackFailedInstall(c ctx.Context, rel *release.Release, timeout int64) {
+	if _, err := s.env.Releases.Get(rel.Name, rel.Version); err != nil {
+		// the release failed before anything else than its hooks was applied
+		return
+	}
+	s.Log("deleting %s after a failed hook", rel.Name)
+	_, err := s.UninstallRelease(c, &services.UninstallReleaseRequest{
+		Name:         rel.Name,
+		DisableHooks: true,
+		Timeout:      timeout,
+		Force:        true,
+		Description:  "Deleted after a failed hook: " + rel.Info.Description,
+	})
+	if err != nil {
+		s.Log("warning: could not delete %s after a failed hook: %s", rel.Name, err)
+	}
+}
+
+// rollbackFailedUpgrade rolls a release whose upgrade was failed by a hook
+// with the rollback failure policy back to the revision it was upgraded from.
+func (s *ReleaseServer) rollbackFailedUpgrade(c ctx.Context, current *release.Release, req *services.UpdateReleaseRequest) {
+	s.Log("rolling %s back to %d after a failed hook", current.Name, current.Version)
+	_, err := s.RollbackRelease(c, &services.RollbackReleaseRequest{
+		Name:         current.Name,
+		Version:      current.Version,
+		Timeout:      req.Timeout,
+		HookTimeout:  req.HookTimeout,
+		Wait:         req.Wait,
+		WaitTimeouts: req.WaitTimeouts,
+		Description:  fmt.Sprintf("Rollback to %d after a failed hook", current.Version),
+	})
+	if err != nil {
+		s.Log("warning: could not roll %s back after a failed hook: %s", current.Name, err)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hookAnchorPrefix prefixes the name of the ConfigMap owning the hook
+// resources of a release.
+const hookAnchorPrefix = "helm-hooks."
+
+// ownedHookKinds are the kinds hook owner references are added to. Owner
+// references are only valid between objects of the same namespace, so
+// cluster-scoped kinds must never be listed here: the garbage collector would
+// treat their owner as missing and delete them straight away.
+var ownedHookKinds = map[string]bool{
+	"ConfigMap":             true,
+	"CronJob":               true,
+	"DaemonSet":             true,
+	"Deployment":            true,
+	"Job":                   true,
+	"PersistentVolumeClaim": true,
+	"Pod":                   true,
+	"ReplicaSet":            true,
+	"Role":                  true,
+	"RoleBinding":           true,
+	"Secret":                true,
+	"Service":               true,
+	"ServiceAccount":        true,
+	"StatefulSet":           true,
+}
+
+func hookAnchorName(release string) string {
+	return hookAnchorPrefix + release
+}
+
+// ensureHookAnchor returns an owner reference to the anchor ConfigMap of a
+// release, creating the ConfigMap if needed.
+func (s *ReleaseServer) ensureHookAnchor(name, namespace string) (*metav1.OwnerReference, error) {
+	client := s.clientset.CoreV1().ConfigMaps(namespace)
+	anchor, err := client.Get(hookAnchorName(name), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		s.Log("creating hook anchor for %s", name)
+		anchor, err = client.Create(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: hookAnchorName(name),
+				Labels: map[string]string{
+					"NAME":  name,
+					"OWNER": "TILLER",
+				},
+			},
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get hook anchor for %s: %s", name, err)
+	}
+	return &metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       anchor.Name,
+		UID:        anchor.UID,
+	}, nil
+}
+
+// deleteHookAnchor deletes the anchor ConfigMap of a release, letting
+// Kubernetes garbage collect the hook resources it owns.
+func (s *ReleaseServer) deleteHookAnchor(name, namespace string) error {
+	policy := metav1.DeletePropagationBackground
+	err := s.clientset.CoreV1().ConfigMaps(namespace).Delete(hookAnchorName(name), &metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// setOwnerReference adds ref to the owner references of the resource in
+// manifest. Resources of other kinds or in another namespace are returned
+// unchanged.
+func setOwnerReference(manifest, namespace string, ref metav1.OwnerReference) (string, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil {
+		return manifest, err
+	}
+	if kind, _ := obj["kind"].(string); !ownedHookKinds[kind] {
+		return manifest, nil
+	}
+	meta, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return manifest, nil
+	}
+	if ns, _ := meta["namespace"].(string); ns != "" && ns != namespace {
+		return manifest, nil
+	}
+
+	refs, _ := meta["ownerReferences"].([]interface{})
+	meta["ownerReferences"] = append(refs, map[string]interface{}{
+		"apiVersion": ref.APIVersion,
+		"kind":       ref.Kind,
+		"name":       ref.Name,
+		"uid":        string(ref.UID),
+	})
+
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		return manifest, err
+	}
+	return string(b), nil
+}
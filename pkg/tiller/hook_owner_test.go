@@ -0,0 +1,116 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/config"
+)
+
+func TestSetOwnerReference(t *testing.T) {
+	ref := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "helm-hooks.flying-carp", UID: "1234"}
+
+	tests := []struct {
+		name     string
+		manifest string
+		owned    bool
+	}{
+		{"job", "kind: Job\nmetadata:\n  name: migrate\n", true},
+		{"same namespace", "kind: Pod\nmetadata:\n  name: migrate\n  namespace: river\n", true},
+		{"other namespace", "kind: Pod\nmetadata:\n  name: migrate\n  namespace: sea\n", false},
+		{"cluster-scoped", "kind: ClusterRole\nmetadata:\n  name: migrate\n", false},
+	}
+	for _, tt := range tests {
+		got, err := setOwnerReference(tt.manifest, "river", ref)
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if owned := strings.Contains(got, "uid: \"1234\""); owned != tt.owned {
+			t.Errorf("%s: expected owner reference to be set: %t, got:\n%s", tt.name, tt.owned, got)
+		}
+		if !tt.owned && got != tt.manifest {
+			t.Errorf("%s: expected manifest to be unchanged, got:\n%s", tt.name, got)
+		}
+	}
+}
+
+func TestExecHookOwnerReferences(t *testing.T) {
+	ctx := newDeletePolicyContext()
+	store := config.NewStore()
+	store.Set(&config.Config{Hooks: config.Hooks{OwnerReferences: true}})
+	ctx.ReleaseServer.env.Config = store
+	hook := deletePolicyHookStub(ctx.HookName, nil, nil)
+
+	if err := execHookShouldSucceed(ctx.ReleaseServer, hook, ctx.ReleaseName, ctx.Namespace, hooks.PreInstall); err != nil {
+		t.Fatal(err)
+	}
+
+	anchor, err := ctx.ReleaseServer.clientset.CoreV1().ConfigMaps(ctx.Namespace).Get(hookAnchorName(ctx.ReleaseName), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected hook anchor to be created: %s", err)
+	}
+
+	refs := ctx.KubeClient.Resources[hook.Name].Metadata.OwnerReferences
+	if len(refs) != 1 || refs[0].Kind != "ConfigMap" || refs[0].Name != anchor.Name || refs[0].UID != anchor.UID {
+		t.Errorf("expected hook to be owned by %s, got %v", anchor.Name, refs)
+	}
+	if hook.Manifest != deletePolicyHookStub(ctx.HookName, nil, nil).Manifest {
+		t.Error("expected the stored hook manifest to be unchanged")
+	}
+}
+
+func TestExecHookWithoutOwnerReferences(t *testing.T) {
+	ctx := newDeletePolicyContext()
+	hook := deletePolicyHookStub(ctx.HookName, nil, nil)
+
+	if err := execHookShouldSucceed(ctx.ReleaseServer, hook, ctx.ReleaseName, ctx.Namespace, hooks.PreInstall); err != nil {
+		t.Fatal(err)
+	}
+
+	if refs := ctx.KubeClient.Resources[hook.Name].Metadata.OwnerReferences; len(refs) != 0 {
+		t.Errorf("expected no owner references, got %v", refs)
+	}
+	if _, err := ctx.ReleaseServer.clientset.CoreV1().ConfigMaps(ctx.Namespace).Get(hookAnchorName(ctx.ReleaseName), metav1.GetOptions{}); err == nil {
+		t.Error("expected no hook anchor to be created")
+	}
+}
+
+func TestUninstallReleaseDeletesHookAnchor(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	if _, err := rs.ensureHookAnchor(rel.Name, rel.Namespace); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: rel.Name}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+
+	if _, err := rs.clientset.CoreV1().ConfigMaps(rel.Namespace).Get(hookAnchorName(rel.Name), metav1.GetOptions{}); err == nil {
+		t.Error("expected hook anchor to be deleted")
+	}
+}
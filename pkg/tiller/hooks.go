@@ -30,6 +30,7 @@ import (
 	"k8s.io/helm/pkg/manifest"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	util "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
 )
 
 var events = map[string]release.Hook_Event{
@@ -44,6 +45,8 @@ var events = map[string]release.Hook_Event{
 	hooks.ReleaseTestSuccess: release.Hook_RELEASE_TEST_SUCCESS,
 	hooks.ReleaseTestFailure: release.Hook_RELEASE_TEST_FAILURE,
 	hooks.CRDInstall:         release.Hook_CRD_INSTALL,
+	hooks.TestSetup:          release.Hook_TEST_SETUP,
+	hooks.TestTeardown:       release.Hook_TEST_TEARDOWN,
 }
 
 // deletePolices represents a mapping between the key in the annotation for label deleting policy and its real meaning
@@ -53,6 +56,13 @@ var deletePolices = map[string]release.Hook_DeletePolicy{
 	hooks.BeforeHookCreation: release.Hook_BEFORE_HOOK_CREATION,
 }
 
+// failurePolicies maps the values of the hook-failure-policy annotation to the policies
+var failurePolicies = map[string]release.Hook_FailurePolicy{
+	hooks.HookAbort:    release.Hook_ABORT,
+	hooks.HookIgnore:   release.Hook_IGNORE,
+	hooks.HookRollback: release.Hook_ROLLBACK,
+}
+
 // Timeout used when deleting resources with a hook-delete-policy.
 const defaultHookDeleteTimeoutInSeconds = int64(60)
 
@@ -200,13 +210,47 @@ func (file *manifestFile) sort(result *result) error {
 		if len(h.DeletePolicies) > 0 {
 			h.DeleteTimeout = defaultHookDeleteTimeoutInSeconds
 			operateAnnotationValues(entry, hooks.HookDeleteTimeoutAnno, func(value string) {
-				timeout, err := strconv.ParseInt(value, 10, 64)
-				if err != nil || timeout < 0 {
+				timeout, err := timeconv.ParseSeconds(value)
+				if err != nil {
 					log.Printf("info: ignoring invalid hook delete timeout value: %q", value)
+					return
 				}
 				h.DeleteTimeout = timeout
 			})
 		}
+
+		operateAnnotationValues(entry, hooks.HookTimeoutAnno, func(value string) {
+			timeout, err := timeconv.ParseSeconds(value)
+			if err != nil {
+				log.Printf("info: ignoring invalid hook timeout value: %q", value)
+				return
+			}
+			h.Timeout = timeout
+		})
+		operateAnnotationValues(entry, hooks.HookRetriesAnno, func(value string) {
+			retries, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || retries < 0 {
+				log.Printf("info: ignoring invalid hook retries value: %q", value)
+				return
+			}
+			h.Retries = int32(retries)
+		})
+		operateAnnotationValues(entry, hooks.HookRetryBackoffAnno, func(value string) {
+			backoff, err := timeconv.ParseSeconds(value)
+			if err != nil {
+				log.Printf("info: ignoring invalid hook retry backoff value: %q", value)
+				return
+			}
+			h.RetryBackoff = backoff
+		})
+		operateAnnotationValues(entry, hooks.HookFailurePolicyAnno, func(value string) {
+			policy, exist := failurePolicies[value]
+			if !exist {
+				log.Printf("info: ignoring unknown hook failure policy: %q", value)
+				return
+			}
+			h.FailurePolicy = policy
+		})
 	}
 	return nil
 }
@@ -335,6 +335,46 @@ func TestSortManifestsHookDeletion(t *testing.T) {
 	}
 }
 
+func TestSortManifestsHookRuns(t *testing.T) {
+	manifests := map[string]string{
+		"migrate": `kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: pre-upgrade
+    helm.sh/hook-timeout: 15m
+    helm.sh/hook-retries: "3"
+    helm.sh/hook-retry-backoff: "10"
+    helm.sh/hook-failure-policy: Rollback
+`,
+		"invalid": `kind: Job
+metadata:
+  name: invalid
+  annotations:
+    helm.sh/hook: pre-upgrade
+    helm.sh/hook-timeout: soon
+    helm.sh/hook-retries: "-1"
+    helm.sh/hook-failure-policy: retry
+`,
+	}
+
+	hs, _, err := sortManifests(manifests, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range hs {
+		var expect release.Hook
+		if h.Name == "migrate" {
+			expect = release.Hook{Timeout: 900, Retries: 3, RetryBackoff: 10, FailurePolicy: release.Hook_ROLLBACK}
+		}
+		if h.Timeout != expect.Timeout || h.Retries != expect.Retries || h.RetryBackoff != expect.RetryBackoff || h.FailurePolicy != expect.FailurePolicy {
+			t.Errorf("%s: expected timeout %d, %d retries, backoff %d and policy %s, got %d, %d, %d and %s", h.Name,
+				expect.Timeout, expect.Retries, expect.RetryBackoff, expect.FailurePolicy,
+				h.Timeout, h.Retries, h.RetryBackoff, h.FailurePolicy)
+		}
+	}
+}
+
 func TestVersionSet(t *testing.T) {
 	vs := chartutil.NewVersionSet("v1", "v1beta1", "extensions/alpha5", "batch/v1")
 
@@ -18,6 +18,7 @@ package tiller
 
 import (
 	"sort"
+	"strconv"
 )
 
 // SortOrder is an ordering of Kinds.
@@ -101,18 +102,30 @@ var UninstallOrder SortOrder = []string{
 	"Namespace",
 }
 
-// sortByKind does an in-place sort of manifests by Kind.
+// sortByKind does an in-place sort of manifests by weight and Kind.
 //
-// Results are sorted by 'ordering'
+// Results are sorted by ascending weight, then by 'ordering'
 func sortByKind(manifests []Manifest, ordering SortOrder) []Manifest {
 	ks := newKindSorter(manifests, ordering)
 	sort.Sort(ks)
 	return ks.manifests
 }
 
+// sortForUninstall does an in-place sort of manifests by weight and Kind,
+// undoing the weights of an install.
+//
+// Results are sorted by descending weight, then by 'ordering'
+func sortForUninstall(manifests []Manifest, ordering SortOrder) []Manifest {
+	ks := newKindSorter(manifests, ordering)
+	ks.descendingWeights = true
+	sort.Sort(ks)
+	return ks.manifests
+}
+
 type kindSorter struct {
-	ordering  map[string]int
-	manifests []Manifest
+	ordering          map[string]int
+	manifests         []Manifest
+	descendingWeights bool
 }
 
 func newKindSorter(m []Manifest, s SortOrder) *kindSorter {
@@ -134,6 +147,12 @@ func (k *kindSorter) Swap(i, j int) { k.manifests[i], k.manifests[j] = k.manifes
 func (k *kindSorter) Less(i, j int) bool {
 	a := k.manifests[i]
 	b := k.manifests[j]
+	if wa, wb := manifestWeight(a), manifestWeight(b); wa != wb {
+		if k.descendingWeights {
+			return wa > wb
+		}
+		return wa < wb
+	}
 	first, aok := k.ordering[a.Head.Kind]
 	second, bok := k.ordering[b.Head.Kind]
 
@@ -163,8 +182,22 @@ func (k *kindSorter) Less(i, j int) bool {
 
 // SortByKind sorts manifests in InstallOrder
 func SortByKind(manifests []Manifest) []Manifest {
-	ordering := InstallOrder
+	return SortByOrder(manifests, InstallOrder)
+}
+
+// SortByOrder sorts manifests by weight, then in the given install order
+func SortByOrder(manifests []Manifest, ordering SortOrder) []Manifest {
 	ks := newKindSorter(manifests, ordering)
 	sort.Sort(ks)
 	return ks.manifests
 }
+
+// manifestWeight returns the weight of the manifest given by its
+// helm.sh/weight annotation, or 0.
+func manifestWeight(m Manifest) int {
+	if m.Head == nil || m.Head.Metadata == nil {
+		return 0
+	}
+	w, _ := strconv.Atoi(m.Head.Metadata.Annotations[WeightAnno])
+	return w
+}
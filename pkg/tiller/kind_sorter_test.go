@@ -18,8 +18,11 @@ package tiller
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
+	"github.com/ghodss/yaml"
+
 	util "k8s.io/helm/pkg/releaseutil"
 )
 
@@ -268,3 +271,43 @@ func TestKindSorterNamespaceAgainstUnknown(t *testing.T) {
 		}
 	}
 }
+
+func weightedManifest(t *testing.T, name, kind string, weight int) Manifest {
+	var head util.SimpleHead
+	doc := fmt.Sprintf("kind: %s\nmetadata:\n  name: %s\n  annotations:\n    %s: %q\n", kind, name, WeightAnno, fmt.Sprint(weight))
+	if err := yaml.Unmarshal([]byte(doc), &head); err != nil {
+		t.Fatal(err)
+	}
+	return Manifest{Name: name, Head: &head}
+}
+
+func TestKindSorterWeights(t *testing.T) {
+	manifests := []Manifest{
+		weightedManifest(t, "a", "ConfigMap", 0),
+		weightedManifest(t, "b", "Deployment", -5),
+		weightedManifest(t, "c", "Service", 10),
+		{Name: "d", Head: &util.SimpleHead{Kind: "Namespace"}},
+		weightedManifest(t, "e", "Secret", 10),
+	}
+
+	for _, test := range []struct {
+		description string
+		sort        func([]Manifest, SortOrder) []Manifest
+		order       SortOrder
+		expected    string
+	}{
+		{"install", sortByKind, InstallOrder, "bdaec"},
+		{"uninstall", sortForUninstall, UninstallOrder, "ceadb"},
+		{"custom order", sortByKind, ParseSortOrder("Service,Secret,ConfigMap"), "badce"},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			var buf bytes.Buffer
+			for _, r := range test.sort(manifests, test.order) {
+				buf.WriteString(r.Name)
+			}
+			if got := buf.String(); got != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
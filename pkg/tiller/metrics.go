@@ -0,0 +1,88 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// The metrics are registered with the default Prometheus registry, which the
+// probes server exposes on /metrics next to the gRPC server metrics.
+var (
+	releaseOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tiller",
+		Name:      "release_operations_total",
+		Help:      "Number of release operations, by operation, namespace and outcome.",
+	}, []string{"operation", "namespace", "outcome"})
+
+	releaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "release_operation_duration_seconds",
+		Help:      "Duration of the release operations, by operation and namespace.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+	}, []string{"operation", "namespace"})
+
+	renderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "render_duration_seconds",
+		Help:      "Duration of the rendering of charts, by namespace.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"namespace"})
+
+	hookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "hook_duration_seconds",
+		Help:      "Duration of the execution of hooks, by event, namespace and outcome.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+	}, []string{"event", "namespace", "outcome"})
+)
+
+func init() {
+	prometheus.MustRegister(releaseOperations, releaseDuration, renderDuration, hookDuration)
+}
+
+// outcome labels the result of an operation.
+func outcome(err error) string {
+	if err != nil {
+		return auditFailure
+	}
+	return auditSuccess
+}
+
+// observeRelease records a release operation that started at start.
+func observeRelease(op, namespace string, start time.Time, err error) {
+	releaseOperations.WithLabelValues(op, namespace, outcome(err)).Inc()
+	releaseDuration.WithLabelValues(op, namespace).Observe(time.Since(start).Seconds())
+}
+
+// observeRender records the rendering of a chart with the given values.
+func observeRender(values chartutil.Values, start time.Time) {
+	var namespace string
+	if rel, ok := values["Release"].(map[string]interface{}); ok {
+		namespace, _ = rel["Namespace"].(string)
+	}
+	renderDuration.WithLabelValues(namespace).Observe(time.Since(start).Seconds())
+}
+
+// observeHook records the execution of a hook for the event.
+func observeHook(event, namespace string, start time.Time, err error) {
+	hookDuration.WithLabelValues(event, namespace, outcome(err)).Observe(time.Since(start).Seconds())
+}
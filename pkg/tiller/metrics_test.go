@@ -0,0 +1,83 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/hooks"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func sampleCount(t *testing.T, o prometheus.Observer) uint64 {
+	m := &dto.Metric{}
+	if err := o.(prometheus.Histogram).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestReleaseMetrics(t *testing.T) {
+	rs := rsFixture()
+	succeeded := releaseOperations.WithLabelValues(eventInstall, "spaced", auditSuccess)
+	failed := releaseOperations.WithLabelValues(eventInstall, "spaced", auditFailure)
+	durations := releaseDuration.WithLabelValues(eventInstall, "spaced")
+	renders := renderDuration.WithLabelValues("spaced")
+	hookRuns := hookDuration.WithLabelValues(hooks.PostInstall, "spaced", auditSuccess)
+
+	before := []float64{counterValue(t, succeeded), counterValue(t, failed)}
+	beforeCounts := []uint64{sampleCount(t, durations), sampleCount(t, renders), sampleCount(t, hookRuns)}
+
+	if _, err := rs.InstallRelease(helm.NewContext(), installRequest()); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if _, err := rs.InstallRelease(helm.NewContext(), installRequest(withChart(withTiller(">=99.0.0")))); err == nil {
+		t.Fatal("Expected the install of an incompatible chart to fail")
+	}
+	// dry runs are not counted
+	if _, err := rs.InstallRelease(helm.NewContext(), installRequest(withDryRun())); err != nil {
+		t.Fatalf("Failed dry run: %s", err)
+	}
+
+	if got := counterValue(t, succeeded) - before[0]; got != 1 {
+		t.Errorf("Expected 1 successful install, got %v", got)
+	}
+	if got := counterValue(t, failed) - before[1]; got != 1 {
+		t.Errorf("Expected 1 failed install, got %v", got)
+	}
+	if got := sampleCount(t, durations) - beforeCounts[0]; got != 2 {
+		t.Errorf("Expected the duration of 2 installs, got %d", got)
+	}
+	// the incompatible chart is rejected before rendering
+	if got := sampleCount(t, renders) - beforeCounts[1]; got != 2 {
+		t.Errorf("Expected 2 renders, got %d", got)
+	}
+	if got := sampleCount(t, hookRuns) - beforeCounts[2]; got != 1 {
+		t.Errorf("Expected 1 post-install hook run, got %d", got)
+	}
+}
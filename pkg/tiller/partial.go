@@ -0,0 +1,197 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/labels"
+
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+const sourcePrefix = "# Source: "
+
+// manifestDoc is a single document of a release manifest.
+type manifestDoc struct {
+	source  string
+	content string
+	head    util.SimpleHead
+	labels  labels.Set
+}
+
+// key identifies the resource described by the document.
+func (d manifestDoc) key() string {
+	name := ""
+	if d.head.Metadata != nil {
+		name = d.head.Metadata.Name
+	}
+	return d.head.Kind + "/" + name
+}
+
+// splitManifestDocs splits a release manifest into its documents, in order.
+func splitManifestDocs(manifest string) []manifestDoc {
+	entries := util.SplitManifests(manifest)
+	docs := make([]manifestDoc, 0, len(entries))
+	// SplitManifests numbers the documents in the order they were found
+	for i := 0; i < len(entries); i++ {
+		content := entries[fmt.Sprintf("manifest-%d", i)]
+		d := manifestDoc{content: content}
+		if strings.HasPrefix(content, sourcePrefix) {
+			lines := strings.SplitN(content, "\n", 2)
+			d.source = strings.TrimPrefix(lines[0], sourcePrefix)
+			d.content = ""
+			if len(lines) == 2 {
+				d.content = lines[1]
+			}
+		}
+		// documents that do not parse can still be matched by path
+		yaml.Unmarshal([]byte(d.content), &d.head)
+		d.labels = docLabels(d.content)
+		docs = append(docs, d)
+	}
+	return docs
+}
+
+// docLabels reads the labels of a document. Values that are not strings are
+// formatted rather than failing the whole document.
+func docLabels(content string) labels.Set {
+	var obj struct {
+		Metadata struct {
+			Labels map[string]interface{} `json:"labels"`
+		} `json:"metadata"`
+	}
+	yaml.Unmarshal([]byte(content), &obj)
+	set := labels.Set{}
+	for k, v := range obj.Metadata.Labels {
+		set[k] = fmt.Sprint(v)
+	}
+	return set
+}
+
+// partialSelector selects the documents of a partial upgrade, either by the
+// path of the template they were rendered from, by kind or by labels.
+type partialSelector struct {
+	path   string
+	kind   string
+	labels labels.Selector
+}
+
+func parsePartialSelectors(only []string) ([]partialSelector, error) {
+	sels := make([]partialSelector, 0, len(only))
+	for _, o := range only {
+		if !strings.Contains(o, "=") {
+			sels = append(sels, partialSelector{path: o})
+			continue
+		}
+		parts := strings.SplitN(o, "=", 2)
+		switch parts[0] {
+		case "kind":
+			if parts[1] != "" {
+				sels = append(sels, partialSelector{kind: parts[1]})
+				continue
+			}
+		case "labels":
+			sel, err := labels.Parse(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector %q: %s", o, err)
+			}
+			if !sel.Empty() {
+				sels = append(sels, partialSelector{labels: sel})
+				continue
+			}
+		}
+		return nil, fmt.Errorf("invalid selector %q: expected a template path, kind=KIND or labels=SELECTOR", o)
+	}
+	return sels, nil
+}
+
+// matches reports whether the selector selects d. Template paths match the
+// end of the source of the document, so "templates/web.yaml" selects
+// "mychart/templates/web.yaml".
+func (p partialSelector) matches(d manifestDoc) bool {
+	if p.kind != "" {
+		return d.head.Kind == p.kind
+	}
+	if p.labels != nil {
+		return p.labels.Matches(d.labels)
+	}
+	return d.source == p.path || strings.HasSuffix(d.source, "/"+p.path)
+}
+
+func selected(sels []partialSelector, d manifestDoc) bool {
+	for _, s := range sels {
+		if s.matches(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// partialManifest merges the selected documents of the updated manifest into
+// the current one, giving the manifest of a partial upgrade. Selected
+// resources are taken from the updated manifest, and selected resources the
+// chart no longer renders are removed. Every other resource is kept as it
+// currently is, and new resources that are not selected are left out.
+func partialManifest(current, updated string, only []string) (string, error) {
+	sels, err := parsePartialSelectors(only)
+	if err != nil {
+		return "", err
+	}
+
+	currentDocs := splitManifestDocs(current)
+	currentByKey := make(map[string]manifestDoc, len(currentDocs))
+	for _, d := range currentDocs {
+		currentByKey[d.key()] = d
+	}
+
+	var b bytes.Buffer
+	write := func(d manifestDoc) {
+		b.WriteString("\n---\n" + sourcePrefix + d.source + "\n")
+		b.WriteString(d.content)
+	}
+
+	matched := false
+	updatedKeys := map[string]bool{}
+	for _, d := range splitManifestDocs(updated) {
+		updatedKeys[d.key()] = true
+		if selected(sels, d) {
+			matched = true
+			write(d)
+		} else if cur, ok := currentByKey[d.key()]; ok {
+			write(cur)
+		}
+	}
+	for _, d := range currentDocs {
+		if updatedKeys[d.key()] {
+			continue
+		}
+		if selected(sels, d) {
+			matched = true
+			continue
+		}
+		write(d)
+	}
+
+	if !matched {
+		return "", fmt.Errorf("no resources of the release match %s", strings.Join(only, ", "))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+)
+
+const partialCurrent = `
+---
+# Source: app/templates/cm.yaml
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  version: "1"
+---
+# Source: app/templates/web.yaml
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+# Source: app/templates/old.yaml
+kind: Secret
+metadata:
+  name: old`
+
+const partialUpdated = `
+---
+# Source: app/templates/cm.yaml
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  version: "2"
+---
+# Source: app/templates/web.yaml
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+---
+# Source: app/templates/new.yaml
+kind: ConfigMap
+metadata:
+  name: new`
+
+func TestPartialManifest(t *testing.T) {
+	tests := []struct {
+		name   string
+		only   []string
+		expect string
+		err    bool
+	}{
+		{
+			name: "by path",
+			only: []string{"templates/web.yaml"},
+			expect: `
+---
+# Source: app/templates/cm.yaml
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  version: "1"
+---
+# Source: app/templates/web.yaml
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+---
+# Source: app/templates/old.yaml
+kind: Secret
+metadata:
+  name: old`,
+		},
+		{
+			name: "by kind",
+			only: []string{"kind=ConfigMap"},
+			expect: `
+---
+# Source: app/templates/cm.yaml
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  version: "2"
+---
+# Source: app/templates/web.yaml
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+# Source: app/templates/new.yaml
+kind: ConfigMap
+metadata:
+  name: new
+---
+# Source: app/templates/old.yaml
+kind: Secret
+metadata:
+  name: old`,
+		},
+		{
+			name: "removed resources",
+			only: []string{"kind=Secret"},
+			expect: `
+---
+# Source: app/templates/cm.yaml
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  version: "1"
+---
+# Source: app/templates/web.yaml
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1`,
+		},
+		{
+			name: "no match",
+			only: []string{"templates/missing.yaml"},
+			err:  true,
+		},
+		{
+			name: "invalid selector",
+			only: []string{"name=cm"},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := partialManifest(partialCurrent, partialUpdated, tt.only)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.err, err)
+			continue
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected manifest %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestPartialSelectorLabels(t *testing.T) {
+	sels, err := parsePartialSelectors([]string{"labels=tier in (web,api),!canary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := splitManifestDocs(`
+---
+kind: Service
+metadata:
+  name: web
+  labels:
+    tier: web
+---
+kind: Deployment
+metadata:
+  name: web-canary
+  labels:
+    tier: web
+    canary: "true"
+---
+kind: Secret
+metadata:
+  name: unlabeled
+---
+kind: ConfigMap
+metadata:
+  name: versioned
+  labels:
+    tier: api
+    version: 1.2`)
+	for i, expect := range []bool{true, false, false, true} {
+		if got := selected(sels, docs[i]); got != expect {
+			t.Errorf("%s: expected selected to be %t", docs[i].key(), expect)
+		}
+	}
+
+	for _, only := range []string{"labels=", "labels=tier in web"} {
+		if _, err := parsePartialSelectors([]string{only}); err == nil {
+			t.Errorf("Expected %q to be invalid", only)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/config"
+)
+
+// The outcomes of the operations recorded in the audit log.
+const (
+	auditSuccess = "success"
+	auditFailure = "failure"
+)
+
+// Labels and annotation of the Events recording audit entries.
+const (
+	auditLabel      = "helm.sh/audit"
+	auditNameLabel  = "helm.sh/release"
+	auditAnnotation = "helm.sh/audit-entry"
+)
+
+var errNoAuditLog = errors.New("no audit log can be queried, configure a file or events audit sink in tiller")
+
+// audit records the outcome of a release operation in the configured audit
+// sinks, along with the reason given to override the maintenance windows, if
+// any. As with webhooks, failures to record are only logged, so a broken sink
+// never fails a release operation.
+func (s *ReleaseServer) audit(c ctx.Context, op, name string, r *release.Release, override string, opErr error) {
+	sinks := s.env.Config.Get().Audit.Sinks
+	if len(sinks) == 0 {
+		return
+	}
+
+	e := &services.AuditEntry{
+		Time:           time.Now().UTC().Format(time.RFC3339),
+		Operation:      op,
+		Release:        name,
+		User:           s.AuditUser,
+		Outcome:        auditSuccess,
+		WindowOverride: override,
+	}
+	if p, ok := peer.FromContext(c); ok && p.Addr != nil {
+		e.Client = p.Addr.String()
+	}
+	if cn := certificateUser(c); cn != "" {
+		e.User = cn
+	}
+	if r != nil {
+		e.Release = r.Name
+		e.Namespace = r.Namespace
+		e.Revision = r.Version
+		if md := r.GetChart().GetMetadata(); md != nil {
+			e.Chart = fmt.Sprintf("%s-%s", md.Name, md.Version)
+		}
+		e.ValuesDigest = valuesDigest(r.Config)
+		if u := r.GetInfo().GetUser(); u != "" {
+			e.User = u
+		}
+	}
+	if opErr != nil {
+		e.Outcome = auditFailure
+		e.Error = opErr.Error()
+	}
+
+	for _, sink := range sinks {
+		var err error
+		switch sink.Type {
+		case config.AuditFile:
+			err = s.appendAuditFile(sink.Path, e)
+		case config.AuditEvents:
+			err = s.createAuditEvent(e)
+		case config.AuditWebhook:
+			var body []byte
+			if body, err = json.Marshal(e); err == nil {
+				go s.deliver(config.Webhook{Name: "audit", URL: sink.URL}, body)
+			}
+		}
+		if err != nil {
+			s.Log("warning: could not record the %s of %s in the %s audit sink: %s", op, e.Release, sink.Type, err)
+		}
+	}
+}
+
+// certificateUser returns the common name of the verified TLS client
+// certificate of the caller, if any.
+func certificateUser(c ctx.Context) string {
+	p, ok := peer.FromContext(c)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return info.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+// valuesDigest returns the SHA-256 digest of the values supplied for a release.
+// No values are digested as an empty document.
+func valuesDigest(cfg *chart.Config) string {
+	sum := sha256.Sum256([]byte(cfg.GetRaw()))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// appendAuditFile appends an entry to the audit file at path, as a line of JSON.
+func (s *ReleaseServer) appendAuditFile(path string, e *services.AuditEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// readAuditFile returns the entries of the audit file at path about the named
+// release.
+func (s *ReleaseServer) readAuditFile(path, name string) ([]*services.AuditEntry, error) {
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*services.AuditEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		e := &services.AuditEntry{}
+		if err := json.Unmarshal(sc.Bytes(), e); err != nil {
+			s.Log("warning: skipping an invalid entry of the audit file %s: %s", path, err)
+			continue
+		}
+		if e.Release == name {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// createAuditEvent records an entry as a Kubernetes Event in the namespace of
+// the release. The entry itself is kept in an annotation of the Event.
+func (s *ReleaseServer) createAuditEvent(e *services.AuditEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	ns := e.Namespace
+	if ns == "" {
+		ns = metav1.NamespaceDefault
+	}
+	// an install can fail before its release is named
+	prefix := e.Release
+	if prefix == "" {
+		prefix = "tiller"
+	}
+
+	reason := strings.Title(e.Operation)
+	eventType := v1.EventTypeNormal
+	if e.Outcome == auditFailure {
+		reason += "Failed"
+		eventType = v1.EventTypeWarning
+	}
+	msg := fmt.Sprintf("%s of %s revision %d", e.Operation, e.Release, e.Revision)
+	if e.User != "" {
+		msg += " by " + e.User
+	}
+	if e.Error != "" {
+		msg += ": " + e.Error
+	}
+	if e.WindowOverride != "" {
+		msg += fmt.Sprintf(" (outside the maintenance windows: %s)", e.WindowOverride)
+	}
+
+	now := metav1.Now()
+	_, err = s.clientset.CoreV1().Events(ns).Create(&v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			// Named like the Events of the Kubernetes event recorder.
+			Name:        fmt.Sprintf("%s.%x", prefix, now.UnixNano()),
+			Namespace:   ns,
+			Labels:      map[string]string{auditLabel: "true", auditNameLabel: e.Release},
+			Annotations: map[string]string{auditAnnotation: string(b)},
+		},
+		InvolvedObject: v1.ObjectReference{Kind: "Release", Name: e.Release, Namespace: ns},
+		Reason:         reason,
+		Message:        msg,
+		Type:           eventType,
+		Source:         v1.EventSource{Component: "tiller"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	})
+	return err
+}
+
+// readAuditEvents returns the entries recorded as Events about the named
+// release, in every namespace.
+func (s *ReleaseServer) readAuditEvents(name string) ([]*services.AuditEntry, error) {
+	list, err := s.clientset.CoreV1().Events(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: auditLabel + "=true," + auditNameLabel + "=" + name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*services.AuditEntry
+	for _, ev := range list.Items {
+		e := &services.AuditEntry{}
+		if err := json.Unmarshal([]byte(ev.Annotations[auditAnnotation]), e); err != nil {
+			s.Log("warning: skipping the invalid audit event %s/%s: %s", ev.Namespace, ev.Name, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time < entries[j].Time })
+	return entries, nil
+}
+
+// GetAuditLog returns the recorded operations on a release, read from the
+// first audit sink that can be queried.
+func (s *ReleaseServer) GetAuditLog(c ctx.Context, req *services.GetAuditLogRequest) (*services.GetAuditLogResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("getAuditLog: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	for _, sink := range s.env.Config.Get().Audit.Sinks {
+		var entries []*services.AuditEntry
+		var err error
+		switch sink.Type {
+		case config.AuditFile:
+			entries, err = s.readAuditFile(sink.Path, req.Name)
+		case config.AuditEvents:
+			entries, err = s.readAuditEvents(req.Name)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read the audit log: %s", err)
+		}
+		if req.Max > 0 && len(entries) > int(req.Max) {
+			entries = entries[len(entries)-int(req.Max):]
+		}
+		return &services.GetAuditLogResponse{Entries: entries}, nil
+	}
+	return nil, errNoAuditLog
+}
@@ -0,0 +1,169 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/config"
+)
+
+func TestAuditFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.AuditUser = "admin"
+	rs.env.Config.Set(&config.Config{
+		Audit: config.Audit{
+			Sinks: []config.AuditSink{{Type: config.AuditFile, Path: filepath.Join(dir, "audit.log")}},
+		},
+	})
+
+	req := installRequest()
+	req.DryRun = true
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed dry-run install: %s", err)
+	}
+	res, err := rs.InstallRelease(c, installRequest())
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	name := res.Release.Name
+
+	rs.env.KubeClient = newUpdateFailingKubeClient()
+	if _, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{Name: name, Chart: res.Release.Chart}); err == nil {
+		t.Fatal("Expected the upgrade to fail")
+	}
+
+	log, err := rs.GetAuditLog(c, &services.GetAuditLogRequest{Name: name})
+	if err != nil {
+		t.Fatalf("Failed to get the audit log: %s", err)
+	}
+	if len(log.Entries) != 2 {
+		t.Fatalf("Expected the install and the upgrade to be recorded, got %v", log.Entries)
+	}
+
+	install, upgrade := log.Entries[0], log.Entries[1]
+	if install.Operation != eventInstall || install.Outcome != auditSuccess || install.Revision != 1 {
+		t.Errorf("Unexpected install entry: %v", install)
+	}
+	if install.User != "admin" || install.Namespace != "spaced" || install.Chart != "hello-" {
+		t.Errorf("Unexpected install entry: %v", install)
+	}
+	if !strings.HasPrefix(install.ValuesDigest, "sha256:") {
+		t.Errorf("Expected a digest of the values, got %q", install.ValuesDigest)
+	}
+	if upgrade.Operation != eventUpgrade || upgrade.Outcome != auditFailure || upgrade.Revision != 2 {
+		t.Errorf("Unexpected upgrade entry: %v", upgrade)
+	}
+	if !strings.Contains(upgrade.Error, "Failed update in kube client") {
+		t.Errorf("Expected the error of the upgrade to be recorded, got %q", upgrade.Error)
+	}
+
+	log, err = rs.GetAuditLog(c, &services.GetAuditLogRequest{Name: name, Max: 1})
+	if err != nil {
+		t.Fatalf("Failed to get the audit log: %s", err)
+	}
+	if len(log.Entries) != 1 || log.Entries[0].Operation != eventUpgrade {
+		t.Errorf("Expected only the most recent entry, got %v", log.Entries)
+	}
+}
+
+func TestAuditWindowOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Audit: config.Audit{
+			Sinks: []config.AuditSink{{Type: config.AuditFile, Path: filepath.Join(dir, "audit.log")}},
+		},
+		Windows: []config.MaintenanceWindow{{Name: "nightly", Schedule: closedSchedule(), Duration: "1h"}},
+	})
+
+	req := installRequest()
+	req.OverrideWindow = "hotfix"
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	log, err := rs.GetAuditLog(c, &services.GetAuditLogRequest{Name: res.Release.Name})
+	if err != nil {
+		t.Fatalf("Failed to get the audit log: %s", err)
+	}
+	if len(log.Entries) != 1 || log.Entries[0].WindowOverride != "hotfix" {
+		t.Errorf("Expected the override to be recorded, got %v", log.Entries)
+	}
+}
+
+func TestAuditEvents(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Audit: config.Audit{Sinks: []config.AuditSink{{Type: config.AuditEvents}}},
+	})
+
+	res, err := rs.InstallRelease(c, installRequest())
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	events, err := rs.clientset.CoreV1().Events("spaced").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 1 || events.Items[0].Reason != "Install" || events.Items[0].InvolvedObject.Name != res.Release.Name {
+		t.Fatalf("Expected an Install event for the release, got %v", events.Items)
+	}
+
+	log, err := rs.GetAuditLog(c, &services.GetAuditLogRequest{Name: res.Release.Name})
+	if err != nil {
+		t.Fatalf("Failed to get the audit log: %s", err)
+	}
+	if len(log.Entries) != 1 || log.Entries[0].Operation != eventInstall || log.Entries[0].Outcome != auditSuccess {
+		t.Errorf("Unexpected audit log: %v", log.Entries)
+	}
+}
+
+func TestGetAuditLogWithoutSink(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Audit: config.Audit{Sinks: []config.AuditSink{{Type: config.AuditWebhook, URL: "https://example.com/audit"}}},
+	})
+
+	if _, err := rs.GetAuditLog(helm.NewContext(), &services.GetAuditLogRequest{Name: "angry-panda"}); err != errNoAuditLog {
+		t.Errorf("Expected %q, got %v", errNoAuditLog, err)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// operation is an install, upgrade or rollback in progress on a release.
+type operation struct {
+	name    string
+	started time.Time
+	ctx     ctx.Context
+	cancel  ctx.CancelFunc
+}
+
+// operations tracks the operations in progress on releases. The Kubernetes
+// clients of an operation stop waiting on the cluster once its context is
+// done: when the client calling Tiller goes away, its deadline passes, or the
+// operation is cancelled.
+type operations struct {
+	mu  sync.Mutex
+	ops map[string]*operation
+}
+
+// start records an operation on a release, bound to the context of the call
+// that requested it. The returned function must be called once the operation
+// is over.
+func (o *operations) start(c ctx.Context, rlsName, name string) func() {
+	opCtx, cancel := ctx.WithCancel(c)
+	op := &operation{name: name, started: time.Now(), ctx: opCtx, cancel: cancel}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.ops == nil {
+		o.ops = map[string]*operation{}
+	}
+	o.ops[rlsName] = op
+	return func() {
+		cancel()
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if o.ops[rlsName] == op {
+			delete(o.ops, rlsName)
+		}
+	}
+}
+
+// context returns the context of the operation in progress on a release, or
+// the background context if there is none.
+func (o *operations) context(rlsName string) ctx.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if op, ok := o.ops[rlsName]; ok {
+		return op.ctx
+	}
+	return ctx.Background()
+}
+
+// cancel cancels the operation in progress on a release, if any. The
+// operation is kept until it is over, so that its later steps see that it was
+// cancelled.
+func (o *operations) cancel(rlsName string) (*operation, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op, ok := o.ops[rlsName]
+	if ok {
+		op.cancel()
+	}
+	return op, ok
+}
+
+// cancelled returns an error if the operation in progress on a release is
+// over before its end, because it was cancelled or its caller went away.
+func (o *operations) cancelled(rlsName string) error {
+	if err := o.context(rlsName).Err(); err != nil {
+		return fmt.Errorf("the operation on release %s was stopped: %s", rlsName, err)
+	}
+	return nil
+}
+
+// withOperation returns a Kubernetes client that stops waiting on the cluster
+// once the operation in progress on the release is over.
+func (s *ReleaseServer) withOperation(cli environment.KubeClient, rlsName string) environment.KubeClient {
+	c, ok := cli.(*kube.Client)
+	if !ok {
+		return cli
+	}
+	opCtx := s.operations.context(rlsName)
+	if opCtx == ctx.Background() {
+		return cli
+	}
+	return c.WithContext(opCtx)
+}
+
+// CancelRelease cancels the install, upgrade or rollback in progress on a
+// release. The operation fails as soon as it notices, and the release is
+// marked as failed like for any other failure.
+func (s *ReleaseServer) CancelRelease(c ctx.Context, req *services.CancelReleaseRequest) (*services.CancelReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("cancelRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	if _, err := s.authenticateCaller(c); err != nil {
+		return nil, err
+	}
+	op, ok := s.operations.cancel(req.Name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no operation in progress", req.Name)
+	}
+	s.Log("cancelled the %s of %s, started at %s", op.name, req.Name, op.started.Format(time.RFC3339))
+	return &services.CancelReleaseResponse{
+		Operation: op.name,
+		Started:   op.started.Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestOperations(t *testing.T) {
+	var ops operations
+
+	if err := ops.cancelled("angry-panda"); err != nil {
+		t.Errorf("expected no error without an operation, got %s", err)
+	}
+
+	done := ops.start(ctx.Background(), "angry-panda", eventUpgrade)
+	if err := ops.cancelled("angry-panda"); err != nil {
+		t.Errorf("expected the operation to run, got %s", err)
+	}
+	op, ok := ops.cancel("angry-panda")
+	if !ok || op.name != eventUpgrade {
+		t.Fatalf("expected the upgrade to be cancelled, got %v", op)
+	}
+	if err := ops.cancelled("angry-panda"); err == nil {
+		t.Error("expected the operation to be stopped")
+	}
+
+	done()
+	if _, ok := ops.cancel("angry-panda"); ok {
+		t.Error("expected the operation to be over")
+	}
+}
+
+func TestOperationsCallerGone(t *testing.T) {
+	var ops operations
+	c, cancel := ctx.WithCancel(ctx.Background())
+	done := ops.start(c, "angry-panda", eventInstall)
+	defer done()
+
+	cancel()
+	if err := ops.cancelled("angry-panda"); err == nil {
+		t.Error("expected the operation to stop with its caller")
+	}
+}
+
+func TestOperationsRestart(t *testing.T) {
+	var ops operations
+	first := ops.start(ctx.Background(), "angry-panda", eventInstall)
+	second := ops.start(ctx.Background(), "angry-panda", eventUpgrade)
+	defer second()
+
+	first()
+	op, ok := ops.cancel("angry-panda")
+	if !ok || op.name != eventUpgrade {
+		t.Errorf("expected the upgrade to be kept, got %v", op)
+	}
+}
+
+func TestCancelRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	_, err := rs.CancelRelease(c, &services.CancelReleaseRequest{Name: "angry-panda"})
+	if err == nil || !strings.Contains(err.Error(), "no operation in progress") {
+		t.Errorf("expected no operation in progress, got %v", err)
+	}
+
+	done := rs.operations.start(c, "angry-panda", eventRollback)
+	defer done()
+	res, err := rs.CancelRelease(c, &services.CancelReleaseRequest{Name: "angry-panda"})
+	if err != nil {
+		t.Fatalf("Failed to cancel: %s", err)
+	}
+	if res.Operation != eventRollback || res.Started == "" {
+		t.Errorf("unexpected response: %v", res)
+	}
+	if err := rs.operations.cancelled("angry-panda"); err == nil {
+		t.Error("expected the rollback to be stopped")
+	}
+
+	if _, err := rs.CancelRelease(c, &services.CancelReleaseRequest{Name: "Invalid_Name"}); err == nil {
+		t.Error("expected an invalid release name to fail")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// DefaultCapabilitiesTTL is how long the capabilities of the cluster are cached.
+//
+// Discovering the API versions of a cluster serving many custom resources
+// takes seconds, so they are not discovered again for every release.
+const DefaultCapabilitiesTTL = 5 * time.Minute
+
+// capabilitiesCache holds the last capabilities discovered.
+type capabilitiesCache struct {
+	mu         sync.Mutex
+	caps       *chartutil.Capabilities
+	discovered time.Time
+}
+
+// capabilities returns the capabilities of the cluster. They are discovered
+// again if refresh is set, or if the cached ones are older than the
+// CapabilitiesTTL of the server.
+func (s *ReleaseServer) capabilities(refresh bool) (*chartutil.Capabilities, time.Time, error) {
+	c := &s.capsCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !refresh && c.caps != nil && time.Since(c.discovered) < s.CapabilitiesTTL {
+		return c.caps, c.discovered, nil
+	}
+	caps, err := capabilities(s.clientset.Discovery())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	c.caps, c.discovered = caps, time.Now()
+	return c.caps, c.discovered, nil
+}
+
+// GetCapabilities returns the capabilities of the cluster charts are rendered for.
+func (s *ReleaseServer) GetCapabilities(c ctx.Context, req *services.GetCapabilitiesRequest) (*services.GetCapabilitiesResponse, error) {
+	if req.Refresh {
+		s.Log("refreshing the capabilities of the cluster")
+	}
+	caps, discovered, err := s.capabilities(req.Refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := s.env.Config.Get().Limits.For(req.ChartName)
+	res := &services.GetCapabilitiesResponse{
+		KubeVersion:      caps.KubeVersion.String(),
+		Age:              int64(time.Since(discovered) / time.Second),
+		MaxManifestBytes: limits.MaxManifestBytes,
+		MaxObjects:       limits.MaxObjects,
+		MaxHooks:         limits.MaxHooks,
+	}
+	for v := range caps.APIVersions {
+		res.ApiVersions = append(res.ApiVersions, v)
+	}
+	sort.Strings(res.ApiVersions)
+	return res, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	hasWidgets := func(refresh bool) bool {
+		res, err := rs.GetCapabilities(c, &services.GetCapabilitiesRequest{Refresh: refresh})
+		if err != nil {
+			t.Fatalf("Failed to get capabilities: %s", err)
+		}
+		for _, v := range res.ApiVersions {
+			if v == "example.com/v1/Widget" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hasWidgets(false) {
+		t.Fatal("Expected the default API versions")
+	}
+
+	rs.clientset.(*fake.Clientset).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget"}},
+		},
+	}
+	if hasWidgets(false) {
+		t.Error("Expected the capabilities to be cached")
+	}
+	if !hasWidgets(true) {
+		t.Error("Expected the capabilities to be refreshed")
+	}
+
+	rs.clientset.(*fake.Clientset).Resources = nil
+	rs.CapabilitiesTTL = 0
+	if hasWidgets(false) {
+		t.Error("Expected the capabilities not to be cached without a TTL")
+	}
+}
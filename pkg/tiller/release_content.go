@@ -17,9 +17,15 @@ limitations under the License.
 package tiller
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+
 	ctx "golang.org/x/net/context"
 
+	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
 )
 
 // GetReleaseContent gets all of the stored information for the given release.
@@ -29,11 +35,50 @@ func (s *ReleaseServer) GetReleaseContent(c ctx.Context, req *services.GetReleas
 		return nil, err
 	}
 
+	var rel *release.Release
+	var err error
 	if req.Version <= 0 {
-		rel, err := s.env.Releases.Last(req.Name)
+		rel, err = s.env.Releases.Last(req.Name)
+	} else {
+		rel, err = s.env.Releases.Get(req.Name, req.Version)
+	}
+	if err != nil || !req.LiveManifest {
 		return &services.GetReleaseContentResponse{Release: rel}, err
 	}
 
-	rel, err := s.env.Releases.Get(req.Name, req.Version)
-	return &services.GetReleaseContentResponse{Release: rel}, err
+	live, err := s.liveManifest(rel)
+	if err != nil {
+		s.Log("releaseContent: could not get the live manifest of %s: %s", req.Name, err)
+		return nil, err
+	}
+	return &services.GetReleaseContentResponse{Release: rel, LiveManifest: live}, nil
+}
+
+// liveManifest reads the current state of each object in the manifest of rel
+// from the cluster. Documents keep their order and their leading comments, so
+// the result can be compared line by line with the stored manifest.
+func (s *ReleaseServer) liveManifest(rel *release.Release) (string, error) {
+	docs := relutil.SplitManifests(rel.Manifest)
+
+	var b bytes.Buffer
+	// SplitManifests numbers the documents in the order they were found
+	for i := 0; i < len(docs); i++ {
+		doc := docs[fmt.Sprintf("manifest-%d", i)]
+		live, err := s.env.KubeClient.GetLive(rel.Namespace, strings.NewReader(doc))
+		if err != nil {
+			return "", err
+		}
+		if live == "" {
+			continue
+		}
+		b.WriteString("---\n")
+		for _, line := range strings.Split(doc, "\n") {
+			if !strings.HasPrefix(line, "#") {
+				break
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(live + "\n")
+	}
+	return b.String(), nil
 }
@@ -17,10 +17,14 @@ limitations under the License.
 package tiller
 
 import (
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestGetReleaseContent(t *testing.T) {
@@ -40,3 +44,54 @@ func TestGetReleaseContent(t *testing.T) {
 		t.Errorf("Expected %q, got %q", rel.Chart.Metadata.Name, res.Release.Chart.Metadata.Name)
 	}
 }
+
+// liveKubeClient returns the objects it is given, minus comments, as their
+// live state.
+type liveKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (k *liveKubeClient) GetLive(namespace string, reader io.Reader) (string, error) {
+	b, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n") + "\nlive: true", nil
+}
+
+func TestGetReleaseContentLiveManifest(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &liveKubeClient{}
+	rel := releaseStub()
+	rel.Manifest = "---\n# Source: a.yaml\nkind: ConfigMap\nmetadata:\n  name: a\n---\n# Source: b.yaml\nkind: Secret\nmetadata:\n  name: b\n"
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	res, err := rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Error getting release content: %s", err)
+	}
+	if res.LiveManifest != "" {
+		t.Errorf("Expected no live manifest unless asked for, got %q", res.LiveManifest)
+	}
+
+	res, err = rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name, LiveManifest: true})
+	if err != nil {
+		t.Fatalf("Error getting release content: %s", err)
+	}
+	expect := "---\n# Source: a.yaml\nkind: ConfigMap\nmetadata:\n  name: a\nlive: true\n---\n# Source: b.yaml\nkind: Secret\nmetadata:\n  name: b\nlive: true\n"
+	if res.LiveManifest != expect {
+		t.Errorf("Expected live manifest %q, got %q", expect, res.LiveManifest)
+	}
+	if res.Release.Manifest != rel.Manifest {
+		t.Errorf("Expected the stored manifest to be returned unchanged, got %q", res.Release.Manifest)
+	}
+}
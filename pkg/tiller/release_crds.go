@@ -0,0 +1,57 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// installCRDs installs the custom resource definitions of the crds directory
+// of the chart of r, and waits for them to be established, so that the
+// resources of the release can use them.
+//
+// Definitions that already exist are left as they are. They are not part of
+// the manifest of the release, so upgrades, rollbacks and deletions of the
+// release never change them.
+func (s *ReleaseServer) installCRDs(r *release.Release, timeout int64) error {
+	crds := chartutil.CRDs(r.Chart)
+	if len(crds) == 0 {
+		return nil
+	}
+	kubeCli, err := s.kubeClientFor(r)
+	if err != nil {
+		return err
+	}
+
+	s.Log("installing %d CRD file(s) for %s", len(crds), r.Name)
+	for _, crd := range crds {
+		opts := kube.CreateOptions{SkipExisting: true}
+		if err := kubeCli.CreateWithOptions(r.Namespace, bytes.NewBuffer(crd.Data), opts); err != nil {
+			return fmt.Errorf("failed to install CRDs of %s: %s", crd.Name, err)
+		}
+		if err := kubeCli.WaitUntilCRDEstablished(bytes.NewBuffer(crd.Data), time.Duration(timeout)*time.Second); err != nil {
+			return fmt.Errorf("CRDs of %s were not established: %s", crd.Name, err)
+		}
+	}
+	return nil
+}
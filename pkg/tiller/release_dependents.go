@@ -0,0 +1,70 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// checkDependents refuses to delete a release providing capabilities that
+// other releases still require, unless no other release provides them.
+func (s *ReleaseServer) checkDependents(rel *release.Release) error {
+	if len(relutil.Provides(rel)) == 0 {
+		return nil
+	}
+	live, err := s.liveReleases()
+	if err != nil {
+		return err
+	}
+	dependents := relutil.Dependents(rel, live)
+	if len(dependents) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(dependents))
+	for _, d := range dependents {
+		names = append(names, fmt.Sprintf("%s (%s)", d.Release.Name, strings.Join(d.Requires, ", ")))
+	}
+	s.Log("refusing to delete release %s required by %s", rel.Name, strings.Join(names, ", "))
+	return fmt.Errorf("release %q is required by releases %s (use --force to delete it anyway)", rel.Name, strings.Join(names, ", "))
+}
+
+// liveReleases returns the latest revision of every release that is neither
+// deleted nor superseded.
+func (s *ReleaseServer) liveReleases() ([]*release.Release, error) {
+	rels, err := s.env.Releases.ListFilterAll(func(rel *release.Release) bool {
+		code := rel.GetInfo().GetStatus().GetCode()
+		return code != release.Status_DELETED && code != release.Status_SUPERSEDED
+	})
+	if err != nil {
+		return nil, err
+	}
+	latest := map[string]*release.Release{}
+	for _, rel := range rels {
+		if l, ok := latest[rel.Name]; !ok || rel.Version > l.Version {
+			latest[rel.Name] = rel
+		}
+	}
+	live := make([]*release.Release, 0, len(latest))
+	for _, rel := range latest {
+		live = append(live, rel)
+	}
+	return live, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	authv1 "k8s.io/api/authentication/v1"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// userTokenKey is the gRPC metadata key carrying the Kubernetes token of the
+// user calling Tiller, when Tiller impersonates its callers.
+const userTokenKey = "x-helm-user-token"
+
+var (
+	errNoImpersonation     = errors.New("this tiller cannot apply releases as a service account")
+	errNoUserImpersonation = errors.New("this tiller cannot apply releases as the calling user")
+	errMissingUserToken    = errors.New("this tiller applies releases as the calling user, but no user token was provided")
+)
+
+// caller is the Kubernetes identity of the user calling Tiller.
+type caller struct {
+	user   string
+	groups []string
+}
+
+// applyTo records the caller as the user the releases are applied as.
+func (c *caller) applyTo(rels ...*release.Release) {
+	if c == nil {
+		return
+	}
+	for _, r := range rels {
+		r.Info.User = c.user
+		r.Info.UserGroups = c.groups
+	}
+}
+
+// authenticateCaller returns the identity of the user calling Tiller, verified
+// by the Kubernetes API server from the token in the request metadata. When
+// Tiller does not impersonate its callers, it returns nil.
+func (s *ReleaseServer) authenticateCaller(c ctx.Context) (*caller, error) {
+	if !s.ImpersonateUsers {
+		return nil, nil
+	}
+	if _, remote := s.ReleaseModule.(*RemoteReleaseModule); remote || s.env.Impersonate == nil {
+		return nil, errNoUserImpersonation
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(c); ok {
+		if v := md[userTokenKey]; len(v) > 0 {
+			token = v[0]
+		}
+	}
+	if token == "" {
+		return nil, errMissingUserToken
+	}
+
+	review, err := s.clientset.AuthenticationV1().TokenReviews().Create(&authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{Token: token},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not verify the user token: %s", err)
+	}
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return nil, fmt.Errorf("the user token was rejected: %s", review.Status.Error)
+		}
+		return nil, errors.New("the user token was rejected")
+	}
+	s.Log("authenticated caller as %s", review.Status.User.Username)
+	return &caller{user: review.Status.User.Username, groups: review.Status.User.Groups}, nil
+}
+
+// kubeClientCache holds the clients impersonating service accounts, so their
+// discovery and mappings are not built again for every operation.
+type kubeClientCache struct {
+	mu      sync.Mutex
+	clients map[string]environment.KubeClient
+}
+
+// serviceAccountFor returns the service account a release in namespace is
+// applied as, given the one requested by the client, if any.
+func (s *ReleaseServer) serviceAccountFor(namespace, requested string) (string, error) {
+	sa, err := s.env.Config.Get().Impersonation.ServiceAccountFor(namespace, requested)
+	if err != nil {
+		return "", err
+	}
+	if sa == "" {
+		return "", nil
+	}
+	// Rudder applies the releases with its own client
+	if _, remote := s.ReleaseModule.(*RemoteReleaseModule); remote || s.env.Impersonate == nil {
+		return "", errNoImpersonation
+	}
+	return sa, nil
+}
+
+// upgradeServiceAccount returns the service account an upgrade of current is
+// applied as. Unless the client or the configuration selects one, the service
+// account of the current release is kept.
+func (s *ReleaseServer) upgradeServiceAccount(current *release.Release, requested string) (string, error) {
+	sa, err := s.serviceAccountFor(current.Namespace, requested)
+	if err != nil || sa != "" {
+		return sa, err
+	}
+	return current.Info.ServiceAccount, nil
+}
+
+// kubeClient returns the client applying the releases of a namespace as the
+// identity recorded in info: the calling user when Tiller impersonates its
+// callers, or else the service account. Without either, Tiller's own client
+// is used.
+func (s *ReleaseServer) kubeClient(namespace string, info *release.Info) (environment.KubeClient, error) {
+	if s.ImpersonateUsers && info.GetUser() != "" {
+		return s.impersonate(info.User, info.UserGroups)
+	}
+	if sa := info.GetServiceAccount(); sa != "" {
+		return s.impersonate(kube.ServiceAccountUser(namespace, sa), nil)
+	}
+	return s.env.KubeClient, nil
+}
+
+// impersonate returns the client acting as user, member of groups.
+func (s *ReleaseServer) impersonate(user string, groups []string) (environment.KubeClient, error) {
+	if s.env.Impersonate == nil {
+		return nil, errNoImpersonation
+	}
+
+	key := user
+	if len(groups) > 0 {
+		key += "/" + strings.Join(groups, ",")
+	}
+	c := &s.kubeClients
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cli, ok := c.clients[key]; ok {
+		return cli, nil
+	}
+	if c.clients == nil {
+		c.clients = map[string]environment.KubeClient{}
+	}
+	c.clients[key] = s.env.Impersonate(user, groups)
+	return c.clients[key], nil
+}
+
+// kubeClientFor returns the client applying a release, as the identity
+// recorded with it.
+func (s *ReleaseServer) kubeClientFor(r *release.Release) (environment.KubeClient, error) {
+	return s.kubeClient(r.Namespace, r.GetInfo())
+}
+
+// envFor returns the environment of the release modules for a release, whose
+// KubeClient applies the release as the identity recorded with it, and stops
+// waiting once the operation in progress on the release is over.
+func (s *ReleaseServer) envFor(r *release.Release) (*environment.Environment, error) {
+	if err := s.operations.cancelled(r.Name); err != nil {
+		return nil, err
+	}
+	cli, err := s.kubeClientFor(r)
+	if err != nil {
+		return nil, err
+	}
+	cli = s.withOperation(cli, r.Name)
+	if cli == s.env.KubeClient {
+		return s.env, nil
+	}
+	env := *s.env
+	env.KubeClient = cli
+	return &env, nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/config"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// impersonatingKubeClient records the users that applied manifests.
+type impersonatingKubeClient struct {
+	environment.PrintingKubeClient
+	user    string
+	groups  []string
+	applied *[]string
+}
+
+func (c *impersonatingKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	*c.applied = append(*c.applied, c.user)
+	return nil
+}
+
+func (c *impersonatingKubeClient) UpdateWithOptions(ns string, current, modified io.Reader, opts kube.UpdateOptions) error {
+	*c.applied = append(*c.applied, c.user)
+	return nil
+}
+
+func impersonatingFixture() (*ReleaseServer, *[]string) {
+	rs := rsFixture()
+	applied := &[]string{}
+	rs.env.Impersonate = func(user string, groups []string) environment.KubeClient {
+		return &impersonatingKubeClient{
+			PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+			user:               user,
+			groups:             groups,
+			applied:            applied,
+		}
+	}
+	return rs, applied
+}
+
+func TestInstallRelease_ServiceAccount(t *testing.T) {
+	c := helm.NewContext()
+	rs, applied := impersonatingFixture()
+
+	req := installRequest()
+	req.ServiceAccount = "deployer"
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if sa := res.Release.Info.ServiceAccount; sa != "deployer" {
+		t.Errorf("Expected the service account to be recorded, got %q", sa)
+	}
+
+	// The upgrade keeps the service account of the release.
+	upd, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:  res.Release.Name,
+		Chart: res.Release.Chart,
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if sa := upd.Release.Info.ServiceAccount; sa != "deployer" {
+		t.Errorf("Expected the upgrade to keep the service account, got %q", sa)
+	}
+
+	user := "system:serviceaccount:spaced:deployer"
+	if len(*applied) != 2 || (*applied)[0] != user || (*applied)[1] != user {
+		t.Errorf("Expected the release to be applied as %s, got %v", user, *applied)
+	}
+	if len(rs.kubeClients.clients) != 1 {
+		t.Errorf("Expected the impersonating client to be reused, got %d clients", len(rs.kubeClients.clients))
+	}
+}
+
+func TestInstallRelease_ServiceAccountPolicy(t *testing.T) {
+	c := helm.NewContext()
+	rs, applied := impersonatingFixture()
+	rs.env.Config.Set(&config.Config{
+		Impersonation: config.Impersonation{
+			ServiceAccounts: map[string]string{"spaced": "tenant"},
+		},
+	})
+
+	req := installRequest()
+	req.ServiceAccount = "admin"
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Error("Expected another service account than the mapped one to be refused")
+	}
+
+	res, err := rs.InstallRelease(c, installRequest())
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if sa := res.Release.Info.ServiceAccount; sa != "tenant" {
+		t.Errorf("Expected the mapped service account, got %q", sa)
+	}
+	if len(*applied) != 1 || (*applied)[0] != "system:serviceaccount:spaced:tenant" {
+		t.Errorf("Expected the release to be applied as the mapped service account, got %v", *applied)
+	}
+}
+
+func TestInstallRelease_ServiceAccountWithoutImpersonation(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest()
+	req.ServiceAccount = "deployer"
+	if _, err := rs.InstallRelease(c, req); err != errNoImpersonation {
+		t.Errorf("Expected %q, got %v", errNoImpersonation, err)
+	}
+}
+
+// userImpersonatingFixture returns a release server impersonating its callers,
+// whose API server only authenticates the token of alice.
+func userImpersonatingFixture() (*ReleaseServer, *[]string) {
+	rs, applied := impersonatingFixture()
+	rs.ImpersonateUsers = true
+	rs.clientset.(*fake.Clientset).PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.TokenReview)
+		if review.Spec.Token != "alice-token" {
+			return true, &authv1.TokenReview{Status: authv1.TokenReviewStatus{Error: "invalid bearer token"}}, nil
+		}
+		return true, &authv1.TokenReview{Status: authv1.TokenReviewStatus{
+			Authenticated: true,
+			User:          authv1.UserInfo{Username: "alice", Groups: []string{"dev", "system:authenticated"}},
+		}}, nil
+	})
+	return rs, applied
+}
+
+func userContext(token string) ctx.Context {
+	return metadata.NewIncomingContext(helm.NewContext(), metadata.Pairs(userTokenKey, token))
+}
+
+func TestInstallRelease_UserImpersonation(t *testing.T) {
+	rs, applied := userImpersonatingFixture()
+
+	req := installRequest()
+	req.ServiceAccount = "deployer"
+	res, err := rs.InstallRelease(userContext("alice-token"), req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if u := res.Release.Info.User; u != "alice" {
+		t.Errorf("Expected the user to be recorded, got %q", u)
+	}
+	groups := []string{"dev", "system:authenticated"}
+	if g := res.Release.Info.UserGroups; !reflect.DeepEqual(g, groups) {
+		t.Errorf("Expected the groups %v to be recorded, got %v", groups, g)
+	}
+	if len(*applied) != 1 || (*applied)[0] != "alice" {
+		t.Errorf("Expected the release to be applied as alice rather than the service account, got %v", *applied)
+	}
+	for _, cli := range rs.kubeClients.clients {
+		if g := cli.(*impersonatingKubeClient).groups; !reflect.DeepEqual(g, groups) {
+			t.Errorf("Expected the client to impersonate the groups %v, got %v", groups, g)
+		}
+	}
+}
+
+func TestInstallRelease_UserImpersonationRejected(t *testing.T) {
+	rs, applied := userImpersonatingFixture()
+
+	if _, err := rs.InstallRelease(helm.NewContext(), installRequest()); err != errMissingUserToken {
+		t.Errorf("Expected %q, got %v", errMissingUserToken, err)
+	}
+	if _, err := rs.InstallRelease(userContext("mallory-token"), installRequest()); err == nil {
+		t.Error("Expected an invalid token to be refused")
+	}
+	if len(*applied) != 0 {
+		t.Errorf("Expected nothing to be applied, got %v", *applied)
+	}
+}
+
+func TestUninstallRelease_UserImpersonation(t *testing.T) {
+	rs, _ := userImpersonatingFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	res, err := rs.UninstallRelease(userContext("alice-token"), &services.UninstallReleaseRequest{
+		Name:  "angry-panda",
+		Purge: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+	if u := res.Release.Info.User; u != "alice" {
+		t.Errorf("Expected the uninstall to be recorded as made by alice, got %q", u)
+	}
+	if _, ok := rs.kubeClients.clients["alice/dev,system:authenticated"]; !ok {
+		t.Error("Expected the resources to be deleted as alice")
+	}
+}
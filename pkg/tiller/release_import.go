@@ -0,0 +1,122 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// eventImport is the operation recorded in the audit log for imports.
+const eventImport = "import"
+
+// ImportRelease restores the revisions of a release exported from another
+// Tiller, keeping their revision numbers. Only the records of the release are
+// written: its resources are expected to exist in the cluster already, or to
+// be created by the next upgrade or rollback.
+func (s *ReleaseServer) ImportRelease(c ctx.Context, req *services.ImportReleaseRequest) (*services.ImportReleaseResponse, error) {
+	res, err := s.importRelease(c, req)
+	s.audit(c, eventImport, req.Name, res.GetRelease(), "", err)
+	return res, err
+}
+
+func (s *ReleaseServer) importRelease(c ctx.Context, req *services.ImportReleaseRequest) (*services.ImportReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("importRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	if err := s.validateImport(req); err != nil {
+		return nil, err
+	}
+	id, err := s.authenticateCaller(c)
+	if err != nil {
+		return nil, err
+	}
+	_, unlock, err := s.lockRelease(c, req.Name, eventImport, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if h, err := s.env.Releases.History(req.Name); err == nil && len(h) > 0 {
+		return nil, fmt.Errorf("release %s already exists, delete it with --purge before importing it", req.Name)
+	}
+
+	rels := append([]*release.Release(nil), req.Releases...)
+	relutil.SortByRevision(rels)
+	// The imported revisions are applied as the caller from now on, not as
+	// whoever applied them where they were exported from.
+	id.applyTo(rels...)
+
+	s.Log("importing %d revisions of %s", len(rels), req.Name)
+	for i, r := range rels {
+		if err := s.env.Releases.Create(r); err != nil {
+			s.Log("failed to import %s (v%d): %s", r.Name, r.Version, err)
+			for _, created := range rels[:i] {
+				if _, derr := s.env.Releases.Delete(created.Name, created.Version); derr != nil {
+					s.Log("warning: failed to remove imported %s (v%d): %s", created.Name, created.Version, derr)
+				}
+			}
+			return nil, err
+		}
+	}
+	last := rels[len(rels)-1]
+	s.Log("imported %s (v%d)", last.Name, last.Version)
+	return &services.ImportReleaseResponse{Release: last}, nil
+}
+
+// validateImport checks that the revisions of an import are those of the
+// release, and that its namespace and service accounts are allowed.
+func (s *ReleaseServer) validateImport(req *services.ImportReleaseRequest) error {
+	if len(req.Releases) == 0 {
+		return fmt.Errorf("no revisions of release %s to import", req.Name)
+	}
+	seen := map[int32]bool{}
+	deployed := 0
+	for _, r := range req.Releases {
+		if r.Name != req.Name {
+			return fmt.Errorf("cannot import revision %d of release %s as release %s", r.Version, r.Name, req.Name)
+		}
+		if r.Info == nil || r.Info.Status == nil || r.Chart == nil {
+			return fmt.Errorf("revision %d of release %s is incomplete", r.Version, r.Name)
+		}
+		if r.Version <= 0 || seen[r.Version] {
+			return fmt.Errorf("invalid or duplicate revision %d of release %s", r.Version, r.Name)
+		}
+		seen[r.Version] = true
+		if r.Namespace != req.Releases[0].Namespace {
+			return fmt.Errorf("revisions of release %s are in both %s and %s", r.Name, req.Releases[0].Namespace, r.Namespace)
+		}
+		if r.Info.Status.Code == release.Status_DEPLOYED {
+			deployed++
+		}
+		if sa := r.Info.ServiceAccount; sa != "" {
+			if _, err := s.serviceAccountFor(r.Namespace, sa); err != nil {
+				return err
+			}
+		}
+	}
+	if deployed > 1 {
+		return fmt.Errorf("release %s has %d deployed revisions", req.Name, deployed)
+	}
+	return s.checkPolicy(req.Releases[0].Namespace)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// importStub returns three revisions of a release, the last one deployed.
+func importStub(name string) []*release.Release {
+	first := namedReleaseStub(name, release.Status_SUPERSEDED)
+	second := namedReleaseStub(name, release.Status_FAILED)
+	second.Version = 2
+	third := namedReleaseStub(name, release.Status_DEPLOYED)
+	third.Version = 3
+	return []*release.Release{third, first, second}
+}
+
+func TestImportRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	res, err := rs.ImportRelease(c, &services.ImportReleaseRequest{Name: "angry-panda", Releases: importStub("angry-panda")})
+	if err != nil {
+		t.Fatalf("Failed import: %s", err)
+	}
+	if res.Release.Version != 3 {
+		t.Errorf("expected the last revision to be 3, got %d", res.Release.Version)
+	}
+
+	h, err := rs.env.Releases.History("angry-panda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(h))
+	}
+	for v := int32(1); v <= 3; v++ {
+		if _, err := rs.env.Releases.Get("angry-panda", v); err != nil {
+			t.Errorf("expected revision %d to be imported: %s", v, err)
+		}
+	}
+	if d, err := rs.env.Releases.Deployed("angry-panda"); err != nil || d.Version != 3 {
+		t.Errorf("expected revision 3 to be deployed, got %v (%v)", d, err)
+	}
+	if l, _ := rs.env.Releases.GetLock("angry-panda"); l != nil {
+		t.Errorf("expected the import to release its lock, got %v", l)
+	}
+
+	_, err = rs.ImportRelease(c, &services.ImportReleaseRequest{Name: "angry-panda", Releases: importStub("angry-panda")})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected importing an existing release to fail, got %v", err)
+	}
+}
+
+func TestImportReleaseInvalid(t *testing.T) {
+	c := helm.NewContext()
+
+	duplicate := importStub("angry-panda")
+	duplicate[2].Version = 1
+	renamed := importStub("angry-panda")
+	renamed[1].Name = "sad-panda"
+	moved := importStub("angry-panda")
+	moved[1].Namespace = "elsewhere"
+	deployed := importStub("angry-panda")
+	deployed[1].Info.Status.Code = release.Status_DEPLOYED
+
+	tests := []struct {
+		name string
+		rels []*release.Release
+		err  string
+	}{
+		{"no revisions", nil, "no revisions"},
+		{"duplicate revisions", duplicate, "duplicate revision 1"},
+		{"different release", renamed, "as release angry-panda"},
+		{"different namespaces", moved, "are in both"},
+		{"deployed twice", deployed, "2 deployed revisions"},
+	}
+	for _, tt := range tests {
+		rs := rsFixture()
+		_, err := rs.ImportRelease(c, &services.ImportReleaseRequest{Name: "angry-panda", Releases: tt.rels})
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.err, err)
+		}
+		if h, _ := rs.env.Releases.History("angry-panda"); len(h) > 0 {
+			t.Errorf("%s: expected nothing to be imported, got %d revisions", tt.name, len(h))
+		}
+	}
+}
@@ -19,11 +19,16 @@ package tiller
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -32,8 +37,31 @@ import (
 
 // InstallRelease installs a release and stores the release record.
 func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+	start := time.Now()
+	res, err := s.installRelease(c, req)
+	if !req.DryRun {
+		observeRelease(eventInstall, req.Namespace, start, err)
+		s.logOperation(eventInstall, req.Name, res.GetRelease(), start, err)
+		s.audit(c, eventInstall, req.Name, res.GetRelease(), req.OverrideWindow, err)
+	}
+	return res, err
+}
+
+func (s *ReleaseServer) installRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+	if err := validateReleaseLabels(req.Labels); err != nil {
+		return nil, err
+	}
+	if !req.DryRun {
+		if err := s.checkWindow(req.Name, req.Namespace, nil, req.OverrideWindow); err != nil {
+			return nil, err
+		}
+	}
+	id, err := s.authenticateCaller(c)
+	if err != nil {
+		return nil, err
+	}
 	s.Log("preparing install for %s", req.Name)
-	rel, err := s.prepareRelease(req)
+	rel, err := s.prepareRelease(req, nil)
 	if err != nil {
 		s.Log("failed install prepare step: %s", err)
 		res := &services.InstallReleaseResponse{Release: rel}
@@ -45,31 +73,66 @@ func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallRelea
 		}
 		return res, err
 	}
+	id.applyTo(rel)
 
+	if !req.DryRun {
+		lc, unlock, err := s.lockRelease(c, rel.Name, eventInstall, req.Timeout, req.HookTimeout)
+		if err != nil {
+			return &services.InstallReleaseResponse{Release: rel}, err
+		}
+		defer unlock()
+		c = lc
+		done := s.operations.start(c, rel.Name, eventInstall)
+		defer done()
+	}
 	s.Log("performing install for %s", req.Name)
 	res, err := s.performRelease(rel, req)
 	if err != nil {
 		s.Log("failed install perform step: %s", err)
+		if hookRollbackRequested(err) {
+			s.rollbackFailedInstall(c, rel, req.Timeout)
+		}
 	}
 	return res, err
 }
 
-// prepareRelease builds a release for an install operation.
-func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*release.Release, error) {
+// prepareRelease builds a release for an install operation. When previous is
+// not nil, the release replaces it and is rendered as its next revision.
+func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest, previous *release.Release) (*release.Release, error) {
 	if req.Chart == nil {
 		return nil, errMissingChart
 	}
 
+	if err := s.checkPolicy(req.Namespace); err != nil {
+		return nil, err
+	}
+
+	serviceAccount, err := s.serviceAccountFor(req.Namespace, req.ServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rudder creates the resources without looking at the request.
+	if _, remote := s.ReleaseModule.(*RemoteReleaseModule); remote && req.TakeOwnership {
+		return nil, errNoAdoption
+	}
+
 	name, err := s.uniqName(req.Name, req.ReuseName)
 	if err != nil {
 		return nil, err
 	}
 
-	caps, err := capabilities(s.clientset.Discovery())
+	caps, _, err := s.capabilities(false)
 	if err != nil {
 		return nil, err
 	}
 
+	// The client cannot evaluate the conditions of the requirements that
+	// depend on the capabilities of the cluster.
+	if err := chartutil.ProcessRequirementsEnabledCaps(req.Chart, req.Values, caps); err != nil {
+		return nil, err
+	}
+
 	revision := 1
 	ts := timeconv.Now()
 	options := chartutil.ReleaseOptions{
@@ -79,12 +142,24 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 		Revision:  revision,
 		IsInstall: true,
 	}
+	if previous != nil {
+		revision = int(previous.Version) + 1
+		options.Revision = revision
+		options.IsInstall = false
+		options.IsUpgrade = true
+		options.PreviousValues, err = previousValues(req.Chart, previous)
+		if err != nil {
+			return nil, err
+		}
+	}
 	valuesToRender, err := chartutil.ToRenderValuesCaps(req.Chart, req.Values, options, caps)
 	if err != nil {
 		return nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, req.SubNotes, caps.APIVersions)
+	annotations := withSortOrders(nil, req.InstallOrder, req.UninstallOrder)
+	installOrder, _ := SortOrders(req.Chart, annotations)
+	hooks, manifestDoc, notesTxt, outputs, err := s.renderResources(req.Chart, valuesToRender, req.SubNotes, req.Strict, caps.APIVersions, installOrder, req.Labels)
 	if err != nil {
 		// Return a release with partial data so that client can show debugging
 		// information.
@@ -114,18 +189,22 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 		Chart:     req.Chart,
 		Config:    req.Values,
 		Info: &release.Info{
-			FirstDeployed: ts,
-			LastDeployed:  ts,
-			Status:        &release.Status{Code: release.Status_PENDING_INSTALL},
-			Description:   "Initial install underway", // Will be overwritten.
+			FirstDeployed:  ts,
+			LastDeployed:   ts,
+			Status:         &release.Status{Code: release.Status_PENDING_INSTALL},
+			Description:    "Initial install underway", // Will be overwritten.
+			ServiceAccount: serviceAccount,
 		},
-		Manifest: manifestDoc.String(),
-		Hooks:    hooks,
-		Version:  int32(revision),
+		Manifest:    manifestDoc.String(),
+		Hooks:       hooks,
+		Version:     int32(revision),
+		Labels:      req.Labels,
+		Annotations: annotations,
 	}
 	if len(notesTxt) > 0 {
 		rel.Info.Status.Notes = notesTxt
 	}
+	rel.Info.Status.Outputs = outputs
 
 	return rel, nil
 }
@@ -146,6 +225,10 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 	res := &services.InstallReleaseResponse{Release: r}
 	manifestDoc := []byte(r.Manifest)
 
+	if err := s.ensureNamespace(r.Namespace, req.CreateNamespace, req.DryRun); err != nil {
+		return res, err
+	}
+
 	if req.DryRun {
 		s.Log("dry run for %s", r.Name)
 
@@ -154,6 +237,11 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 			res.Release.Info.Description = "Validation skipped because CRDs are not installed"
 			return res, nil
 		}
+		if !req.SkipCrds && len(chartutil.CRDs(r.Chart)) > 0 {
+			s.Log("validation skipped because the chart has CRDs")
+			res.Release.Info.Description = "Validation skipped because CRDs are not installed"
+			return res, nil
+		}
 
 		// Here's the problem with dry runs and CRDs: We can't install a CRD
 		// during a dry run, which means it cannot be validated.
@@ -165,9 +253,19 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		return res, nil
 	}
 
+	// CRDs of the crds directory, before the crd-install hooks that may
+	// depend on them
+	if !req.SkipCrds {
+		if err := s.installCRDs(r, req.Timeout); err != nil {
+			return res, err
+		}
+	} else {
+		s.Log("CRD install skipped for %s", req.Name)
+	}
+
 	// crd-install hooks
 	if !req.DisableHooks && !req.DisableCrdHook {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.CRDInstall, req.Timeout); err != nil {
+		if err := s.execHook(r.Hooks, r.Name, r.Namespace, r.Info, hooks.CRDInstall, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			fmt.Printf("Finished installing CRD: %s", err)
 			return res, err
 		}
@@ -175,6 +273,11 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		s.Log("CRD install hooks disabled for %s", req.Name)
 	}
 
+	env, err := s.envFor(r)
+	if err != nil {
+		return res, err
+	}
+
 	// Because the CRDs are installed, they are used for validation during this step.
 	if err := validateManifest(s.env.KubeClient, req.Namespace, manifestDoc); err != nil {
 		return res, fmt.Errorf("validation failed: %s", err)
@@ -182,7 +285,7 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 
 	// pre-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.PreInstall, req.Timeout); err != nil {
+		if err := s.execHook(r.Hooks, r.Name, r.Namespace, r.Info, hooks.PreInstall, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			return res, err
 		}
 	} else {
@@ -207,17 +310,20 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		// so as to append to the old release's history
 		r.Version = old.Version + 1
 		updateReq := &services.UpdateReleaseRequest{
-			Wait:     req.Wait,
-			Recreate: false,
-			Timeout:  req.Timeout,
+			Wait:           req.Wait,
+			Recreate:       false,
+			Timeout:        req.Timeout,
+			WaitTimeouts:   req.WaitTimeouts,
+			CleanupOnFail:  req.CleanupOnFail,
+			WaitForJobs:    req.WaitForJobs,
+			WaitConditions: req.WaitConditions,
 		}
 		s.recordRelease(r, false)
-		if err := s.ReleaseModule.Update(old, r, updateReq, s.env); err != nil {
+		if err := s.ReleaseModule.Update(old, r, updateReq, env); err != nil {
 			msg := fmt.Sprintf("Release replace %q failed: %s", r.Name, err)
 			s.Log("warning: %s", msg)
 			old.Info.Status.Code = release.Status_SUPERSEDED
-			r.Info.Status.Code = release.Status_FAILED
-			r.Info.Description = msg
+			markFailed(r, failedApply, msg, err)
 			s.recordRelease(old, true)
 			s.recordRelease(r, true)
 			return res, err
@@ -227,11 +333,10 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		// nothing to replace, create as normal
 		// regular manifests
 		s.recordRelease(r, false)
-		if err := s.ReleaseModule.Create(r, req, s.env); err != nil {
+		if err := s.ReleaseModule.Create(r, req, env); err != nil {
 			msg := fmt.Sprintf("Release %q failed: %s", r.Name, err)
 			s.Log("warning: %s", msg)
-			r.Info.Status.Code = release.Status_FAILED
-			r.Info.Description = msg
+			markFailed(r, failedApply, msg, err)
 			s.recordRelease(r, true)
 			return res, fmt.Errorf("release %s failed: %s", r.Name, err)
 		}
@@ -239,11 +344,10 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 
 	// post-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.PostInstall, req.Timeout); err != nil {
+		if err := s.execHook(r.Hooks, r.Name, r.Namespace, r.Info, hooks.PostInstall, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			msg := fmt.Sprintf("Release %q failed post-install: %s", r.Name, err)
 			s.Log("warning: %s", msg)
-			r.Info.Status.Code = release.Status_FAILED
-			r.Info.Description = msg
+			markFailed(r, failedHooks, msg, err)
 			s.recordRelease(r, true)
 			return res, err
 		}
@@ -263,6 +367,53 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 	// One possible strategy would be to do a timed retry to see if we can get
 	// this stored in the future.
 	s.recordRelease(r, true)
+	s.notify(eventInstall, r)
 
 	return res, nil
 }
+
+// ensureNamespace makes sure that the namespace of a release exists before
+// anything is applied to it, creating it if the request asks for it or if the
+// policy does not require that. Nothing is created on dry runs.
+func (s *ReleaseServer) ensureNamespace(namespace string, create, dryRun bool) error {
+	required := s.env.Config.Get().Policy.RequireCreateNamespace
+	if !create && !required {
+		// The namespace is created along with the resources, as it always was.
+		return nil
+	}
+	_, err := s.clientset.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+	if !create {
+		return fmt.Errorf("namespace %q does not exist; install with --create-namespace to create it", namespace)
+	}
+	if dryRun {
+		return nil
+	}
+	s.Log("creating namespace %s", namespace)
+	_, err = s.clientset.CoreV1().Namespaces().Create(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{"name": namespace},
+		},
+	})
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// ownershipMetadata returns the labels and annotations set on the existing
+// resources a release takes ownership of.
+func ownershipMetadata(r *release.Release) *kube.MetadataChange {
+	return &kube.MetadataChange{
+		Labels: map[string]string{
+			"app.kubernetes.io/managed-by": "Tiller",
+		},
+		Annotations: map[string]string{
+			kube.ReleaseNameAnno:      r.Name,
+			kube.ReleaseNamespaceAnno: r.Namespace,
+		},
+	}
+}
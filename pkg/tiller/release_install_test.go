@@ -18,13 +18,23 @@ package tiller
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/any"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/config"
+	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -114,6 +124,25 @@ func TestInstallRelease(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_OutsideWindow(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Windows: []config.MaintenanceWindow{{Name: "nightly", Schedule: closedSchedule(), Duration: "1h", Namespaces: []string{"spaced"}}},
+	})
+
+	_, err := rs.InstallRelease(c, installRequest())
+	if err == nil || !strings.Contains(err.Error(), "maintenance windows") {
+		t.Fatalf("Expected maintenance window error, got %v", err)
+	}
+
+	req := installRequest()
+	req.Namespace = "unrestricted"
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Errorf("Expected the window not to apply to other namespaces, got %v", err)
+	}
+}
+
 func TestInstallRelease_WithNotes(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -333,6 +362,37 @@ func TestInstallRelease_WithChartAndDependencyAllNotes(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_WithOutputs(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest(withChart(
+		withOutputs("url: http://{{ .Release.Name }}.example.com\nreplicas: 3\n"),
+		withDependency(withOutputs("child: true\n")),
+	))
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	rel, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
+	if err != nil {
+		t.Fatalf("Expected release for %s (%v).", res.Release.Name, rs.env.Releases)
+	}
+	expect := fmt.Sprintf("url: http://%s.example.com\nreplicas: 3\n", rel.Name)
+	if rel.Info.Status.Outputs != expect {
+		t.Errorf("Expected outputs %q, got %q", expect, rel.Info.Status.Outputs)
+	}
+	if strings.Contains(rel.Manifest, "outputs.yaml") {
+		t.Errorf("Expected the outputs not to be in the manifest:\n%s", rel.Manifest)
+	}
+
+	req = installRequest(withChart(withOutputs("- not\n- a map\n")))
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Error("Expected outputs which are not a map to be rejected")
+	}
+}
+
 func TestInstallRelease_DryRun(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -465,6 +525,63 @@ func TestInstallRelease_FailedHooks(t *testing.T) {
 	if hl := res.Release.Info.Status.Code; hl != release.Status_FAILED {
 		t.Errorf("Expected FAILED release. Got %d", hl)
 	}
+	if phase := res.Release.Info.FailurePhase; phase != failedHooks {
+		t.Errorf("Expected failure phase %q, got %q", failedHooks, phase)
+	}
+	if e := res.Release.Info.LastError; e != "Failed watch" {
+		t.Errorf("Expected last error %q, got %q", "Failed watch", e)
+	}
+}
+
+func TestInstallRelease_HookTimeout(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &timeoutRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	req := installRequest()
+	req.Timeout = 300
+	req.HookTimeout = 900
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(kc.hookTimeouts) == 0 {
+		t.Fatal("Expected hooks to be run")
+	}
+	for _, timeout := range kc.hookTimeouts {
+		if timeout != 900 {
+			t.Errorf("Expected hooks to run with a timeout of 900 seconds, got %d", timeout)
+		}
+	}
+	if kc.createTimeout != 300 {
+		t.Errorf("Expected resources to be created with a timeout of 300 seconds, got %d", kc.createTimeout)
+	}
+}
+
+func TestInstallRelease_FailedWait(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = newWaitFailingKubeClient()
+
+	req := installRequest()
+	res, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected failed install")
+	}
+
+	stored, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
+	if err != nil {
+		t.Fatalf("Expected the failed release to be recorded: %s", err)
+	}
+	if code := stored.Info.Status.Code; code != release.Status_FAILED {
+		t.Errorf("Expected FAILED release. Got %s", code)
+	}
+	if phase := stored.Info.FailurePhase; phase != failedWait {
+		t.Errorf("Expected failure phase %q, got %q", failedWait, phase)
+	}
+	if e := stored.Info.LastError; e != "timed out waiting for the condition" {
+		t.Errorf("Expected the wait error to be recorded, got %q", e)
+	}
 }
 
 func TestInstallRelease_ReuseName(t *testing.T) {
@@ -529,6 +646,58 @@ func TestInstallRelease_WrongKubeVersion(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_LibraryChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest(
+		withChart(withType(chartutil.ChartTypeLibrary)),
+	)
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected installing a library chart to fail")
+	}
+	expect := "chart hello is a library chart and cannot be installed"
+	if !strings.Contains(err.Error(), expect) {
+		t.Errorf("Expected %q to contain %q", err.Error(), expect)
+	}
+
+	req = installRequest(
+		withChart(withDependency(withType(chartutil.ChartTypeLibrary))),
+	)
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Expected a chart depending on a library chart to install, got %s", err)
+	}
+	if strings.Contains(res.Release.Manifest, "hello/charts/hello/templates") {
+		t.Errorf("Expected the templates of the library chart not to be rendered, got manifest:\n%s", res.Release.Manifest)
+	}
+}
+
+func TestInstallRelease_Strict(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest()
+	req.Chart.Templates = append(req.Chart.Templates, &chart.Template{Name: "templates/config", Data: []byte("mode: {{ .Values.mode }}")})
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	req = installRequest()
+	req.Chart.Templates = append(req.Chart.Templates, &chart.Template{Name: "templates/config", Data: []byte("mode: {{ .Values.mode }}")})
+	req.Strict = true
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected strict rendering to fail on a missing value")
+	}
+	for _, expect := range []string{"hello/templates/config:1:", `at <.Values.mode>: map has no entry for key "mode"`, "1 | mode: {{ .Values.mode }}"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("Expected %q to contain %q", err.Error(), expect)
+		}
+	}
+}
+
 func TestInstallRelease_Description(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -548,3 +717,215 @@ func TestInstallRelease_Description(t *testing.T) {
 		t.Errorf("Expected description %q. Got %q", customDescription, desc)
 	}
 }
+
+func TestInstallRelease_CreateNamespace(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Policy: config.Policy{RequireCreateNamespace: true},
+	})
+
+	_, err := rs.InstallRelease(c, installRequest())
+	if err == nil || !strings.Contains(err.Error(), "--create-namespace") {
+		t.Fatalf("Expected the install into a missing namespace to fail, got %v", err)
+	}
+
+	req := installRequest()
+	req.CreateNamespace = true
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if _, err := rs.clientset.CoreV1().Namespaces().Get("spaced", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the namespace to be created: %s", err)
+	}
+}
+
+func TestInstallRelease_Limits(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Limits: config.Limits{ChartLimits: config.ChartLimits{MaxObjects: 2}},
+	})
+
+	_, err := rs.InstallRelease(c, installRequest(withChart(withSampleTemplates())))
+	expect := `chart "hello" renders 3 objects, more than the limit of 2 objects set by tiller`
+	if err == nil || err.Error() != expect {
+		t.Fatalf("Expected %q, got %v", expect, err)
+	}
+	if rels, _ := rs.env.Releases.ListReleases(); len(rels) != 0 {
+		t.Errorf("Expected no release to be recorded, got %d", len(rels))
+	}
+
+	rs.env.Config.Set(&config.Config{
+		Limits: config.Limits{
+			ChartLimits: config.ChartLimits{MaxObjects: 2},
+			Charts:      map[string]config.ChartLimits{"hello": {MaxObjects: 3}},
+		},
+	})
+	if _, err := rs.InstallRelease(c, installRequest(withChart(withSampleTemplates()))); err != nil {
+		t.Fatalf("Expected the limits of the chart to allow the install, got %s", err)
+	}
+}
+
+type adoptRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	adopt *kube.MetadataChange
+}
+
+func (a *adoptRecordingKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	a.adopt = opts.Adopt
+	return nil
+}
+
+func TestInstallRelease_TakeOwnership(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &adoptRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	if _, err := rs.InstallRelease(c, installRequest()); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if kc.adopt != nil {
+		t.Error("Expected no adoption without TakeOwnership")
+	}
+
+	req := installRequest()
+	req.Name = "adopter"
+	req.TakeOwnership = true
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if kc.adopt == nil {
+		t.Fatal("Expected the existing resources to be adopted")
+	}
+	if name := kc.adopt.Annotations[kube.ReleaseNameAnno]; name != "adopter" {
+		t.Errorf("Expected adopted resources to be annotated with the release name, got %q", name)
+	}
+
+	rs.ReleaseModule = &RemoteReleaseModule{}
+	req = installRequest()
+	req.TakeOwnership = true
+	if _, err := rs.InstallRelease(c, req); err != errNoAdoption {
+		t.Errorf("Expected %q, got %v", errNoAdoption, err)
+	}
+}
+
+type crdRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	crds        []string
+	established int
+}
+
+func (k *crdRecordingKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	if opts.SkipExisting {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		k.crds = append(k.crds, string(b))
+	}
+	return nil
+}
+
+func (k *crdRecordingKubeClient) WaitUntilCRDEstablished(r io.Reader, timeout time.Duration) error {
+	k.established++
+	return nil
+}
+
+func TestInstallRelease_CRDs(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &crdRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	crd := "kind: CustomResourceDefinition\nmetadata:\n  name: crontabs.stable.example.com\n"
+	req := installRequest(withChart(withDependency()))
+	req.Chart.Dependencies[0].Files = []*any.Any{
+		{TypeUrl: "crds/crontab.yaml", Value: []byte(crd)},
+		{TypeUrl: "crds/README.md", Value: []byte("not a CRD")},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(kc.crds) != 1 || kc.crds[0] != crd || kc.established != 1 {
+		t.Errorf("Expected the CRD of the dependency to be installed and established, got %q and %d established", kc.crds, kc.established)
+	}
+	if strings.Contains(res.Release.Manifest, "CustomResourceDefinition") {
+		t.Errorf("Expected the CRDs not to be part of the manifest, got\n%s", res.Release.Manifest)
+	}
+
+	kc.crds, kc.established = nil, 0
+	req.Name = "skipper"
+	req.SkipCrds = true
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(kc.crds) != 0 || kc.established != 0 {
+		t.Errorf("Expected no CRD to be installed with SkipCrds, got %q", kc.crds)
+	}
+}
+
+func TestInstallRelease_SortOrder(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest(withChart())
+	req.Chart.Metadata.Annotations = map[string]string{InstallOrderAnno: "Service,ConfigMap"}
+	req.Chart.Templates = []*chart.Template{
+		{Name: "templates/cm", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\n")},
+		{Name: "templates/svc", Data: []byte("kind: Service\nmetadata:\n  name: svc\n")},
+		{Name: "templates/secret", Data: []byte("kind: Secret\nmetadata:\n  name: secret\n  annotations:\n    helm.sh/weight: \"-1\"\n")},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	secret, svc, cm := strings.Index(res.Release.Manifest, "templates/secret"), strings.Index(res.Release.Manifest, "templates/svc"), strings.Index(res.Release.Manifest, "templates/cm")
+	if !(secret < svc && svc < cm) {
+		t.Errorf("Expected the order of the chart, after the lighter secret, got\n%s", res.Release.Manifest)
+	}
+
+	req.Name = "reordered"
+	req.InstallOrder = "ConfigMap,Service"
+	res, err = rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if strings.Index(res.Release.Manifest, "templates/cm") > strings.Index(res.Release.Manifest, "templates/svc") {
+		t.Errorf("Expected the order of the request to override the chart, got\n%s", res.Release.Manifest)
+	}
+	if res.Release.Annotations[InstallOrderAnno] != "ConfigMap,Service" {
+		t.Errorf("Expected the order to be kept in the release annotations, got %v", res.Release.Annotations)
+	}
+}
+
+func TestInstallRelease_FailedHookRollback(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = newHookFailingKubeClient()
+
+	req := installRequest(withName("rolled-back"))
+	req.Chart.Templates[1].Data = []byte(`kind: Job
+metadata:
+  name: smoke-test
+  annotations:
+    "helm.sh/hook": post-install
+    "helm.sh/hook-failure-policy": rollback
+`)
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Fatal("Expected failed install")
+	}
+
+	rel, err := rs.env.Releases.Last("rolled-back")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Info.Status.Code != release.Status_DELETED {
+		t.Errorf("Expected the release to be deleted, got %s", rel.Info.Status.Code)
+	}
+	if !strings.HasPrefix(rel.Info.Description, "Deleted after a failed hook") {
+		t.Errorf("Unexpected description %q", rel.Info.Description)
+	}
+}
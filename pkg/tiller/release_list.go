@@ -18,6 +18,7 @@ package tiller
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 
 	"github.com/golang/protobuf/proto"
@@ -52,6 +53,13 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		}
 	}
 
+	if req.ChartName != "" || req.AppVersion != "" {
+		rels, err = filterByChart(req.ChartName, req.AppVersion, rels)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(req.Filter) != 0 {
 		rels, err = filterReleases(req.Filter, rels)
 		if err != nil {
@@ -59,6 +67,13 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		}
 	}
 
+	if len(req.Selector) != 0 {
+		rels, err = filterBySelector(req.Selector, rels)
+		if err != nil {
+			return err
+		}
+	}
+
 	total := int64(len(rels))
 
 	switch req.SortBy {
@@ -156,16 +171,46 @@ func (s *ReleaseServer) partition(rels []*release.Release, cap int) <-chan []*re
 	return chunks
 }
 
+// filterByNamespace keeps the releases whose namespace matches the glob namespace.
 func filterByNamespace(namespace string, rels []*release.Release) ([]*release.Release, error) {
+	if _, err := path.Match(namespace, ""); err != nil {
+		return rels, fmt.Errorf("invalid namespace pattern %q: %s", namespace, err)
+	}
 	matches := []*release.Release{}
 	for _, r := range rels {
-		if namespace == r.Namespace {
+		if ok, _ := path.Match(namespace, r.Namespace); ok {
 			matches = append(matches, r)
 		}
 	}
 	return matches, nil
 }
 
+// filterByChart keeps the releases whose chart name and app version match the
+// globs chartName and appVersion. An empty glob matches everything.
+func filterByChart(chartName, appVersion string, rels []*release.Release) ([]*release.Release, error) {
+	for _, pattern := range []string{chartName, appVersion} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return rels, fmt.Errorf("invalid chart pattern %q: %s", pattern, err)
+		}
+	}
+	matches := []*release.Release{}
+	for _, r := range rels {
+		md := r.GetChart().GetMetadata()
+		if chartName != "" {
+			if ok, _ := path.Match(chartName, md.GetName()); !ok {
+				continue
+			}
+		}
+		if appVersion != "" {
+			if ok, _ := path.Match(appVersion, md.GetAppVersion()); !ok {
+				continue
+			}
+		}
+		matches = append(matches, r)
+	}
+	return matches, nil
+}
+
 func filterReleases(filter string, rels []*release.Release) ([]*release.Release, error) {
 	preg, err := regexp.Compile(filter)
 	if err != nil {
@@ -275,6 +275,102 @@ func TestReleasesNamespace(t *testing.T) {
 	}
 }
 
+func TestReleasesNamespaceGlob(t *testing.T) {
+	rs := rsFixture()
+
+	namespaces := map[string]string{
+		"axon":     "team-a",
+		"dendrite": "team-b",
+		"neuron":   "default",
+	}
+	for name, ns := range namespaces {
+		rel := releaseStub()
+		rel.Name = name
+		rel.Namespace = ns
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	mrs := &mockListServer{}
+	req := &services.ListReleasesRequest{
+		Limit:     64,
+		Namespace: "team-*",
+		SortBy:    services.ListSort_NAME,
+	}
+	if err := rs.ListReleases(req, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(mrs.val.Releases))
+	}
+	if mrs.val.Releases[0].Name != "axon" || mrs.val.Releases[1].Name != "dendrite" {
+		t.Errorf("Unexpected releases: %v", mrs.val.Releases)
+	}
+
+	req.Namespace = "team-["
+	if err := rs.ListReleases(req, &mockListServer{}); err == nil {
+		t.Error("Expected an error for a malformed namespace pattern")
+	}
+}
+
+func TestListReleasesByChart(t *testing.T) {
+	rs := rsFixture()
+
+	charts := []struct {
+		release, chart, appVersion string
+	}{
+		{"axon", "nginx", "1.15.0"},
+		{"dendrite", "nginx-ingress", "0.24.1"},
+		{"neuron", "nginx", "1.16.0"},
+		{"ribosome", "mariadb", "10.3"},
+	}
+	for _, c := range charts {
+		rel := releaseStub()
+		rel.Name = c.release
+		rel.Chart = &chart.Chart{
+			Metadata: &chart.Metadata{Name: c.chart, AppVersion: c.appVersion},
+		}
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	tests := []struct {
+		chart, appVersion string
+		expect            []string
+	}{
+		{"nginx", "", []string{"axon", "neuron"}},
+		{"nginx*", "", []string{"axon", "dendrite", "neuron"}},
+		{"nginx", "1.15.*", []string{"axon"}},
+		{"", "10.*", []string{"ribosome"}},
+	}
+	for _, tt := range tests {
+		mrs := &mockListServer{}
+		req := &services.ListReleasesRequest{
+			Limit:      64,
+			ChartName:  tt.chart,
+			AppVersion: tt.appVersion,
+			SortBy:     services.ListSort_NAME,
+		}
+		if err := rs.ListReleases(req, mrs); err != nil {
+			t.Fatalf("Failed listing: %s", err)
+		}
+		var got []string
+		for _, r := range mrs.val.Releases {
+			got = append(got, r.Name)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.expect) {
+			t.Errorf("chart %q, app version %q: expected %v, got %v", tt.chart, tt.appVersion, tt.expect, got)
+		}
+	}
+
+	req := &services.ListReleasesRequest{ChartName: "nginx["}
+	if err := rs.ListReleases(req, &mockListServer{}); err == nil {
+		t.Error("Expected an error for a malformed chart pattern")
+	}
+}
+
 func TestReleasePartition(t *testing.T) {
 	var rl []*release.Release
 	rs := rsFixture()
@@ -0,0 +1,84 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// lockGrace is how long the lock on a release outlives the timeouts of the
+// operation holding it, which covers the steps not bound by a timeout.
+const lockGrace = 5 * time.Minute
+
+// heldLock is the context key of the name of the release locked by the
+// operation the context belongs to.
+type heldLock struct{}
+
+// lockRelease takes the lock on a release for an operation, so that concurrent
+// operations on the release fail fast instead of interleaving revisions. The
+// lock expires once the timeouts of the operation, and of its pre and post
+// hooks, have passed, should Tiller go away before releasing it.
+//
+// The returned context records the lock, so that the operations run as part
+// of the operation holding it, such as undoing it after a failed hook, do not
+// try to take it again.
+func (s *ReleaseServer) lockRelease(c ctx.Context, name, op string, timeout, hookTimeout int64) (ctx.Context, func(), error) {
+	if held, _ := c.Value(heldLock{}).(string); held == name {
+		return c, func() {}, nil
+	}
+	if hookTimeout == 0 {
+		hookTimeout = timeout
+	}
+	ttl := time.Duration(timeout+2*hookTimeout)*time.Second + lockGrace
+	unlock, err := s.env.Releases.Lock(name, op, ttl)
+	if err != nil {
+		s.Log("failed to lock %s for %s: %s", name, op, err)
+		return nil, nil, err
+	}
+	return ctx.WithValue(c, heldLock{}, name), unlock, nil
+}
+
+// UnlockRelease removes the lock on a release, for operations that did not
+// release it, such as when Tiller was killed in the middle of an upgrade.
+func (s *ReleaseServer) UnlockRelease(c ctx.Context, req *services.UnlockReleaseRequest) (*services.UnlockReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("unlockRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	if _, err := s.authenticateCaller(c); err != nil {
+		return nil, err
+	}
+	l, err := s.env.Releases.Unlock(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, fmt.Errorf("release %s is not locked", req.Name)
+	}
+	s.Log("unlocked %s, locked for %s by %s", req.Name, l.Operation, l.Holder)
+	return &services.UnlockReleaseResponse{
+		Operation: l.Operation,
+		Holder:    l.Holder,
+		Acquired:  l.Acquired.UTC().Format(time.RFC3339),
+		Expires:   l.Expires.UTC().Format(time.RFC3339),
+	}, nil
+}
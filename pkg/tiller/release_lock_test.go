@@ -0,0 +1,109 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestUpdateReleaseLocked(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	unlock, err := rs.env.Releases.Lock(rel.Name, eventRollback, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/hello", Data: []byte("hello: world")}},
+		},
+	}
+	_, err = rs.UpdateRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "another operation (rollback) is in progress") {
+		t.Fatalf("expected the upgrade to fail on the lock, got %v", err)
+	}
+
+	unlock()
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if l, _ := rs.env.Releases.GetLock(rel.Name); l != nil {
+		t.Errorf("expected the upgrade to release its lock, got %v", l)
+	}
+}
+
+func TestLockReleaseHeld(t *testing.T) {
+	rs := rsFixture()
+
+	c, unlock, err := rs.lockRelease(helm.NewContext(), "angry-panda", eventUpgrade, 300, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	l, _ := rs.env.Releases.GetLock("angry-panda")
+	if l == nil {
+		t.Fatal("expected the release to be locked")
+	}
+	if want := l.Acquired.Add(15*time.Minute + lockGrace); !l.Expires.Equal(want) {
+		t.Errorf("expected the lock to expire at %s, got %s", want, l.Expires)
+	}
+
+	// operations run on behalf of the holder do not lock the release again
+	if _, nested, err := rs.lockRelease(c, "angry-panda", eventRollback, 300, 0); err != nil {
+		t.Errorf("expected the lock to be held already, got %s", err)
+	} else {
+		nested()
+	}
+	if _, _, err := rs.lockRelease(helm.NewContext(), "angry-panda", eventRollback, 300, 0); err == nil {
+		t.Error("expected another operation to fail on the lock")
+	}
+}
+
+func TestUnlockRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	if _, err := rs.UnlockRelease(c, &services.UnlockReleaseRequest{Name: "angry-panda"}); err == nil || !strings.Contains(err.Error(), "not locked") {
+		t.Errorf("expected the release not to be locked, got %v", err)
+	}
+
+	if _, err := rs.env.Releases.Lock("angry-panda", eventUpgrade, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	res, err := rs.UnlockRelease(c, &services.UnlockReleaseRequest{Name: "angry-panda"})
+	if err != nil {
+		t.Fatalf("Failed to unlock: %s", err)
+	}
+	if res.Operation != eventUpgrade || res.Holder == "" {
+		t.Errorf("unexpected response: %v", res)
+	}
+	if l, _ := rs.env.Releases.GetLock("angry-panda"); l != nil {
+		t.Errorf("expected the release to be unlocked, got %v", l)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/kubeschema"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// MapReleaseAPIs replaces the apiVersions a Kubernetes version no longer
+// serves in the manifest of the latest revision of a release.
+//
+// Once Kubernetes stops serving an apiVersion, a release whose manifest still
+// uses it cannot be upgraded, as the resources of the current manifest cannot
+// be found. The stored manifest is changed in place, without creating a new
+// revision, and the resources themselves are left alone: the cluster already
+// serves them under the replacement apiVersion.
+func (s *ReleaseServer) MapReleaseAPIs(c ctx.Context, req *services.MapReleaseAPIsRequest) (*services.MapReleaseAPIsResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("mapAPIs: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	rel, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPolicy(rel.Namespace); err != nil {
+		return nil, err
+	}
+
+	kubeVersion := req.KubeVersion
+	if kubeVersion == "" {
+		caps, _, err := s.capabilities(false)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the Kubernetes version of the cluster: %s", err)
+		}
+		kubeVersion = fmt.Sprintf("%s.%s", caps.KubeVersion.Major, caps.KubeVersion.Minor)
+	}
+
+	manifest, mappings, err := kubeschema.MapAPIVersions(rel.Manifest, kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	res := &services.MapReleaseAPIsResponse{Release: rel}
+	for _, m := range mappings {
+		res.Mappings = append(res.Mappings, m.String())
+	}
+	if req.DryRun || len(mappings) == 0 {
+		return res, nil
+	}
+
+	rel.Manifest = manifest
+	rel.Info.Description = fmt.Sprintf("Kubernetes %s apiVersions mapped", kubeVersion)
+	s.Log("mapping %d apiVersions of %s (v%d) for Kubernetes %s", len(mappings), rel.Name, rel.Version, kubeVersion)
+	if err := s.env.Releases.Update(rel); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+const outdatedManifest = `---
+# Source: hello/templates/deployment.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: hello
+`
+
+func TestMapReleaseAPIs(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = outdatedManifest
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	res, err := rs.MapReleaseAPIs(c, &services.MapReleaseAPIsRequest{Name: rel.Name, KubeVersion: "1.16", DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed to map apiVersions: %s", err)
+	}
+	if len(res.Mappings) != 1 || res.Mappings[0] != `Deployment "hello": extensions/v1beta1 -> apps/v1` {
+		t.Errorf("unexpected mappings: %v", res.Mappings)
+	}
+	stored, err := rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Manifest != outdatedManifest {
+		t.Errorf("expected a dry run to leave the manifest alone, got\n%s", stored.Manifest)
+	}
+
+	if _, err := rs.MapReleaseAPIs(c, &services.MapReleaseAPIsRequest{Name: rel.Name, KubeVersion: "1.16"}); err != nil {
+		t.Fatalf("Failed to map apiVersions: %s", err)
+	}
+	stored, err = rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stored.Manifest, "apiVersion: apps/v1\n") {
+		t.Errorf("expected the manifest to use apps/v1, got\n%s", stored.Manifest)
+	}
+	if stored.Info.Description != "Kubernetes 1.16 apiVersions mapped" {
+		t.Errorf("unexpected description %q", stored.Info.Description)
+	}
+}
+
+func TestMapReleaseAPIsNothingToMap(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = outdatedManifest
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	res, err := rs.MapReleaseAPIs(c, &services.MapReleaseAPIsRequest{Name: rel.Name, KubeVersion: "1.15"})
+	if err != nil {
+		t.Fatalf("Failed to map apiVersions: %s", err)
+	}
+	if len(res.Mappings) != 0 {
+		t.Errorf("expected nothing to map for Kubernetes 1.15, got %v", res.Mappings)
+	}
+	if res.Release.Manifest != outdatedManifest {
+		t.Errorf("expected the manifest to be left alone, got\n%s", res.Release.Manifest)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	ctx "golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// UpdateReleaseMetadata changes the labels and annotations of a release.
+//
+// The changes are made to the stored release record in place, without
+// creating a new revision. If requested, they are also applied to the
+// resources of the release.
+func (s *ReleaseServer) UpdateReleaseMetadata(c ctx.Context, req *services.UpdateReleaseMetadataRequest) (*services.UpdateReleaseMetadataResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("updateMetadata: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	if err := validateMetadata(req); err != nil {
+		return nil, err
+	}
+
+	var rel *release.Release
+	var err error
+	if req.Version <= 0 {
+		rel, err = s.env.Releases.Last(req.Name)
+	} else {
+		rel, err = s.env.Releases.Get(req.Name, req.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPolicy(rel.Namespace); err != nil {
+		return nil, err
+	}
+
+	if req.Propagate {
+		if rel.Info.Status.Code == release.Status_DELETED {
+			return nil, fmt.Errorf("release %q has been deleted, its resources cannot be updated", rel.Name)
+		}
+		change := kube.MetadataChange{
+			Labels:            req.Labels,
+			Annotations:       req.Annotations,
+			RemoveLabels:      req.RemoveLabels,
+			RemoveAnnotations: req.RemoveAnnotations,
+		}
+		id, err := s.authenticateCaller(c)
+		if err != nil {
+			return nil, err
+		}
+		id.applyTo(rel)
+		kubeCli, err := s.kubeClientFor(rel)
+		if err != nil {
+			return nil, err
+		}
+		if err := kubeCli.UpdateMetadata(rel.Namespace, bytes.NewBufferString(rel.Manifest), change); err != nil {
+			s.Log("warning: failed to update metadata of resources of %s: %s", rel.Name, err)
+			return nil, err
+		}
+	}
+
+	rel.Labels = mergeMetadata(rel.Labels, req.Labels, req.RemoveLabels)
+	rel.Annotations = mergeMetadata(rel.Annotations, req.Annotations, req.RemoveAnnotations)
+
+	s.Log("updating metadata of %s (v%d)", rel.Name, rel.Version)
+	if err := s.env.Releases.Update(rel); err != nil {
+		return nil, err
+	}
+	return &services.UpdateReleaseMetadataResponse{Release: rel}, nil
+}
+
+// validateMetadata checks that labels and annotations follow the Kubernetes
+// rules, so they can be propagated to resources and used in selectors.
+func validateMetadata(req *services.UpdateReleaseMetadataRequest) error {
+	var errs []string
+	for k, v := range req.Labels {
+		errs = append(errs, validation.IsQualifiedName(k)...)
+		errs = append(errs, validation.IsValidLabelValue(v)...)
+	}
+	for k := range req.Annotations {
+		errs = append(errs, validation.IsQualifiedName(strings.ToLower(k))...)
+	}
+	for _, k := range append(req.RemoveLabels, req.RemoveAnnotations...) {
+		errs = append(errs, validation.IsQualifiedName(k)...)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid metadata: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// releaseLabelPrefix prefixes the labels of a release set on its resources.
+const releaseLabelPrefix = "release-label.helm.sh/"
+
+// validateReleaseLabels checks the labels given to an install or an upgrade.
+// Their keys cannot have a prefix of their own, as they are set on the
+// resources of the release under releaseLabelPrefix.
+func validateReleaseLabels(labels map[string]string) error {
+	var errs []string
+	for k, v := range labels {
+		if strings.Contains(k, "/") {
+			errs = append(errs, fmt.Sprintf("label %q cannot have a prefix", k))
+			continue
+		}
+		errs = append(errs, validation.IsQualifiedName(releaseLabelPrefix+k)...)
+		errs = append(errs, validation.IsValidLabelValue(v)...)
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("invalid labels: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// labelManifests sets the labels of a release on each of the manifests, under
+// releaseLabelPrefix. Labels with a prefix, which can only be set with
+// UpdateReleaseMetadata, are left out.
+func labelManifests(manifests []Manifest, labels map[string]string) error {
+	set := map[string]string{}
+	for k, v := range labels {
+		if !strings.Contains(k, "/") {
+			set[releaseLabelPrefix+k] = v
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	for i, m := range manifests {
+		j, err := yaml.YAMLToJSON([]byte(m.Content))
+		if err != nil {
+			return fmt.Errorf("YAML parse error on %s: %s", m.Name, err)
+		}
+		var obj map[string]interface{}
+		d := json.NewDecoder(bytes.NewReader(j))
+		d.UseNumber()
+		if err := d.Decode(&obj); err != nil || obj == nil {
+			continue
+		}
+		md, _ := obj["metadata"].(map[string]interface{})
+		if md == nil {
+			md = map[string]interface{}{}
+			obj["metadata"] = md
+		}
+		ls, _ := md["labels"].(map[string]interface{})
+		if ls == nil {
+			ls = map[string]interface{}{}
+			md["labels"] = ls
+		}
+		for k, v := range set {
+			ls[k] = v
+		}
+		if j, err = json.Marshal(obj); err != nil {
+			return err
+		}
+		out, err := yaml.JSONToYAML(j)
+		if err != nil {
+			return err
+		}
+		manifests[i].Content = string(out)
+	}
+	return nil
+}
+
+// mergeMetadata returns current with the keys in set added and the keys in
+// remove deleted.
+func mergeMetadata(current, set map[string]string, remove []string) map[string]string {
+	m := make(map[string]string, len(current)+len(set))
+	for k, v := range current {
+		m[k] = v
+	}
+	for _, k := range remove {
+		delete(m, k)
+	}
+	for k, v := range set {
+		m[k] = v
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func filterBySelector(selector string, rels []*release.Release) ([]*release.Release, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return rels, err
+	}
+	matches := []*release.Release{}
+	for _, r := range rels {
+		if sel.Matches(labels.Set(r.Labels)) {
+			matches = append(matches, r)
+		}
+	}
+	return matches, nil
+}
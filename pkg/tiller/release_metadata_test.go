@@ -0,0 +1,206 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestUpdateReleaseMetadata(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Labels = map[string]string{"team": "web", "owner": "alice"}
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	res, err := rs.UpdateReleaseMetadata(c, &services.UpdateReleaseMetadataRequest{
+		Name:         rel.Name,
+		Labels:       map[string]string{"frozen": "true"},
+		Annotations:  map[string]string{"incident": "INC-1234"},
+		RemoveLabels: []string{"owner"},
+		Propagate:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to update metadata: %s", err)
+	}
+	if res.Release.Version != rel.Version {
+		t.Errorf("expected revision %d to be updated in place, got %d", rel.Version, res.Release.Version)
+	}
+
+	stored, err := rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Labels) != 2 || stored.Labels["team"] != "web" || stored.Labels["frozen"] != "true" {
+		t.Errorf("unexpected labels: %v", stored.Labels)
+	}
+	if stored.Annotations["incident"] != "INC-1234" {
+		t.Errorf("unexpected annotations: %v", stored.Annotations)
+	}
+}
+
+func TestUpdateReleaseMetadataInvalid(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	_, err := rs.UpdateReleaseMetadata(c, &services.UpdateReleaseMetadataRequest{
+		Name:   rel.Name,
+		Labels: map[string]string{"bad key": "value"},
+	})
+	if err == nil {
+		t.Fatal("expected an invalid label key to be rejected")
+	}
+}
+
+func TestUpdateReleaseMetadataPropagateDeleted(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.Status.Code = release.Status_DELETED
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	_, err := rs.UpdateReleaseMetadata(c, &services.UpdateReleaseMetadataRequest{
+		Name:      rel.Name,
+		Labels:    map[string]string{"frozen": "true"},
+		Propagate: true,
+	})
+	if err == nil {
+		t.Fatal("expected propagation to a deleted release to fail")
+	}
+}
+
+func TestListReleasesBySelector(t *testing.T) {
+	rs := rsFixture()
+	frozen := namedReleaseStub("frozen", release.Status_DEPLOYED)
+	frozen.Labels = map[string]string{"frozen": "true"}
+	thawed := namedReleaseStub("thawed", release.Status_DEPLOYED)
+	for _, stub := range []*release.Release{frozen, thawed} {
+		if err := rs.env.Releases.Create(stub); err != nil {
+			t.Fatalf("Could not create stub: %s", err)
+		}
+	}
+
+	mrs := &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{Selector: "frozen=true"}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 1 || mrs.val.Releases[0].Name != "frozen" {
+		t.Errorf("expected only the frozen release, got %v", mrs.val.Releases)
+	}
+
+	if err := rs.ListReleases(&services.ListReleasesRequest{Selector: "frozen in ("}, mrs); err == nil {
+		t.Error("expected an invalid selector to fail")
+	}
+}
+
+func TestUpdateReleaseKeepsMetadata(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Labels = map[string]string{"frozen": "true"}
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+			},
+		},
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if res.Release.Labels["frozen"] != "true" {
+		t.Errorf("expected labels to be carried over, got %v", res.Release.Labels)
+	}
+}
+
+func TestInstallReleaseLabels(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest()
+	req.Labels = map[string]string{"team": "platform"}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Labels["team"] != "platform" {
+		t.Errorf("expected the labels to be stored with the release, got %v", res.Release.Labels)
+	}
+	if !strings.Contains(res.Release.Manifest, "release-label.helm.sh/team: platform") {
+		t.Errorf("expected the labels to be set on the resources, got:\n%s", res.Release.Manifest)
+	}
+
+	req = installRequest()
+	req.Name = "invalid"
+	req.Labels = map[string]string{"example.com/team": "platform"}
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Error("expected a label with a prefix to be rejected")
+	}
+}
+
+func TestUpdateReleaseLabels(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Labels = map[string]string{"team": "web", "example.com/owner": "alice"}
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:   rel.Name,
+		Labels: map[string]string{"env": "staging"},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/cm", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\n  labels:\n    app: hello\n")},
+			},
+		},
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if len(res.Release.Labels) != 3 || res.Release.Labels["env"] != "staging" || res.Release.Labels["team"] != "web" {
+		t.Errorf("expected the labels to be merged, got %v", res.Release.Labels)
+	}
+	for _, expect := range []string{"app: hello", "release-label.helm.sh/env: staging", "release-label.helm.sh/team: web"} {
+		if !strings.Contains(res.Release.Manifest, expect) {
+			t.Errorf("expected %q in the manifest, got:\n%s", expect, res.Release.Manifest)
+		}
+	}
+	if strings.Contains(res.Release.Manifest, "owner") {
+		t.Errorf("expected labels with a prefix not to be set on the resources, got:\n%s", res.Release.Manifest)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 
@@ -32,6 +33,7 @@ import (
 	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/rudder"
 	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/timeconv"
 )
 
 // ReleaseModule is an interface that allows ReleaseServer to run operations on release via either local implementation or Rudder service
@@ -46,37 +48,64 @@ type ReleaseModule interface {
 // LocalReleaseModule is a local implementation of ReleaseModule
 type LocalReleaseModule struct {
 	clientset kubernetes.Interface
+	// readiness, if set, is where the readiness reports of releases are
+	// streamed from while they are waited on.
+	readiness *readinessWatches
 }
 
 // Create creates a release via kubeclient from provided environment
 func (m *LocalReleaseModule) Create(r *release.Release, req *services.InstallReleaseRequest, env *environment.Environment) error {
+	defer m.readiness.begin(r.Name)()
 	b := bytes.NewBufferString(r.Manifest)
-	return env.KubeClient.Create(r.Namespace, b, req.Timeout, req.Wait)
+	opts := kube.CreateOptions{
+		Timeout:        req.Timeout,
+		ShouldWait:     req.Wait,
+		WaitTimeouts:   waitTimeouts(req.WaitTimeouts),
+		OnReadiness:    m.recordReadiness(r),
+		CleanupOnFail:  req.CleanupOnFail,
+		WaitForJobs:    req.WaitForJobs,
+		WaitConditions: req.WaitConditions,
+	}
+	if req.TakeOwnership {
+		opts.Adopt = ownershipMetadata(r)
+	}
+	return env.KubeClient.CreateWithOptions(r.Namespace, b, opts)
 }
 
 // Update performs an update from current to target release
 func (m *LocalReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
+	defer m.readiness.begin(target.Name)()
 	c := bytes.NewBufferString(current.Manifest)
 	t := bytes.NewBufferString(target.Manifest)
 	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
-		Force:         req.Force,
-		Recreate:      req.Recreate,
-		Timeout:       req.Timeout,
-		ShouldWait:    req.Wait,
-		CleanupOnFail: req.CleanupOnFail,
+		Force:          req.Force,
+		Recreate:       req.Recreate,
+		Timeout:        req.Timeout,
+		ShouldWait:     req.Wait,
+		CleanupOnFail:  req.CleanupOnFail,
+		WaitTimeouts:   waitTimeouts(req.WaitTimeouts),
+		OnReadiness:    m.recordReadiness(target),
+		WaitForJobs:    req.WaitForJobs,
+		WaitConditions: req.WaitConditions,
 	})
 }
 
 // Rollback performs a rollback from current to target release
 func (m *LocalReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
+	defer m.readiness.begin(target.Name)()
 	c := bytes.NewBufferString(current.Manifest)
 	t := bytes.NewBufferString(target.Manifest)
 	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
-		Force:         req.Force,
-		Recreate:      req.Recreate,
-		Timeout:       req.Timeout,
-		ShouldWait:    req.Wait,
-		CleanupOnFail: req.CleanupOnFail,
+		Force:          req.Force,
+		Recreate:       req.Recreate,
+		Timeout:        req.Timeout,
+		ShouldWait:     req.Wait,
+		CleanupOnFail:  req.CleanupOnFail,
+		WaitTimeouts:   waitTimeouts(req.WaitTimeouts),
+		OnReadiness:    m.recordReadiness(target),
+		WaitForJobs:    req.WaitForJobs,
+		WaitConditions: req.WaitConditions,
+		Rollback:       true,
 	})
 }
 
@@ -91,7 +120,37 @@ func (m *LocalReleaseModule) Delete(rel *release.Release, req *services.Uninstal
 	if err != nil {
 		return rel.Manifest, []error{fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)}
 	}
-	return DeleteRelease(rel, vs, env.KubeClient)
+	return DeleteRelease(rel, vs, env.KubeClient, req.GetCascade())
+}
+
+// waitTimeouts converts per-kind wait timeouts in seconds into durations.
+func waitTimeouts(seconds map[string]int64) map[string]time.Duration {
+	if len(seconds) == 0 {
+		return nil
+	}
+	timeouts := make(map[string]time.Duration, len(seconds))
+	for kind, s := range seconds {
+		timeouts[kind] = time.Duration(s) * time.Second
+	}
+	return timeouts
+}
+
+// recordReadiness returns a callback that appends readiness events to the
+// release, so they are returned to the client and stored with the release,
+// and streams them to the clients watching the release.
+func (m *LocalReleaseModule) recordReadiness(r *release.Release) func(kube.ReadinessEvent) {
+	return func(e kube.ReadinessEvent) {
+		report := &release.ResourceReadiness{
+			Kind:      e.Kind,
+			Namespace: e.Namespace,
+			Name:      e.Name,
+			Ready:     e.Ready,
+			Message:   e.Message,
+			Time:      timeconv.Timestamp(e.Time),
+		}
+		r.Info.Readiness = append(r.Info.Readiness, report)
+		m.readiness.report(r.Name, report)
+	}
 }
 
 // RemoteReleaseModule is a ReleaseModule which calls Rudder service to operate on a release
@@ -158,10 +217,12 @@ func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.Uninstall
 	return result, errs
 }
 
-// DeleteRelease is a helper that allows Rudder to delete a release without exposing most of Tiller inner functions
-func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient) (kept string, errs []error) {
+// DeleteRelease is a helper that allows Rudder to delete a release without exposing most of Tiller inner functions.
+// cascade is how the dependents of the deleted resources are handled, as in kube.DeleteOptions.
+func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient, cascade string) (kept string, errs []error) {
 	manifests := relutil.SplitManifests(rel.Manifest)
-	_, files, err := sortManifests(manifests, vs, UninstallOrder)
+	_, uninstallOrder := SortOrders(rel.Chart, rel.Annotations)
+	_, files, err := sortManifests(manifests, vs, uninstallOrder)
 	if err != nil {
 		// We could instead just delete everything in no particular order.
 		// FIXME: One way to delete at this point would be to try a label-based
@@ -170,7 +231,7 @@ func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient env
 		return rel.Manifest, []error{fmt.Errorf("corrupted release record. You must manually delete the resources: %s", err)}
 	}
 
-	filesToKeep, filesToDelete := filterManifestsToKeep(files)
+	filesToKeep, filesToDelete := filterManifestsToKeep(sortForUninstall(files, uninstallOrder))
 	if len(filesToKeep) > 0 {
 		kept = summarizeKeptManifests(filesToKeep, kubeClient, rel.Namespace)
 	}
@@ -181,7 +242,7 @@ func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient env
 		if b.Len() == 0 {
 			continue
 		}
-		if err := kubeClient.Delete(rel.Namespace, b); err != nil {
+		if err := kubeClient.DeleteWithOptions(rel.Namespace, b, kube.DeleteOptions{Cascade: cascade}); err != nil {
 			log.Printf("uninstall: Failed deletion of %q: %s", rel.Name, err)
 			if err == kube.ErrNoObjectsVisited {
 				// Rewrite the message from "no objects visited"
@@ -0,0 +1,107 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/config"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// Release events that are sent to the configured webhooks.
+const (
+	eventInstall  = "install"
+	eventUpgrade  = "upgrade"
+	eventRollback = "rollback"
+	eventDelete   = "delete"
+)
+
+// webhookTimeout bounds how long a single webhook delivery may take.
+const webhookTimeout = 10 * time.Second
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// releaseEvent is the payload posted to webhooks.
+type releaseEvent struct {
+	Event     string `json:"event"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Revision  int32  `json:"revision"`
+	Status    string `json:"status"`
+	Chart     string `json:"chart,omitempty"`
+	Time      string `json:"time"`
+}
+
+// checkPolicy returns an error if the current configuration does not allow
+// releases to be managed in namespace.
+func (s *ReleaseServer) checkPolicy(namespace string) error {
+	return s.env.Config.Get().Policy.AllowsNamespace(namespace)
+}
+
+// notify sends a release event to every webhook subscribed to it. Deliveries
+// happen in the background and failures are only logged, so a broken endpoint
+// never fails a release operation.
+func (s *ReleaseServer) notify(event string, r *release.Release) {
+	hooks := s.env.Config.Get().Webhooks
+	if len(hooks) == 0 || r == nil {
+		return
+	}
+
+	e := releaseEvent{
+		Event:     event,
+		Name:      r.Name,
+		Namespace: r.Namespace,
+		Revision:  r.Version,
+		Time:      timeconv.String(timeconv.Now()),
+	}
+	if r.Info != nil && r.Info.Status != nil {
+		e.Status = r.Info.Status.Code.String()
+	}
+	if md := r.GetChart().GetMetadata(); md != nil {
+		e.Chart = fmt.Sprintf("%s-%s", md.Name, md.Version)
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		s.Log("warning: could not encode %s event for %s: %s", event, r.Name, err)
+		return
+	}
+
+	for _, h := range hooks {
+		if !h.Wants(event) {
+			continue
+		}
+		go s.deliver(h, body)
+	}
+}
+
+func (s *ReleaseServer) deliver(h config.Webhook, body []byte) {
+	resp, err := webhookClient.Post(h.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		s.Log("warning: webhook %s failed: %s", h.Name, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		s.Log("warning: webhook %s returned %s", h.Name, resp.Status)
+	}
+}
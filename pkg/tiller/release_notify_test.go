@@ -0,0 +1,86 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/tiller/config"
+)
+
+func TestInstallReleaseDeniedByPolicy(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Policy: config.Policy{DenyNamespaces: []string{"spaced"}},
+	})
+
+	_, err := rs.InstallRelease(c, installRequest())
+	if err == nil {
+		t.Fatal("expected install into a denied namespace to fail")
+	}
+	if !strings.Contains(err.Error(), "denied by tiller policy") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestInstallReleaseNotifiesWebhook(t *testing.T) {
+	events := make(chan releaseEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e releaseEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("could not decode event: %s", err)
+		}
+		events <- e
+	}))
+	defer srv.Close()
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Config.Set(&config.Config{
+		Webhooks: []config.Webhook{
+			{Name: "upgrades", URL: srv.URL, Events: []string{eventUpgrade}},
+			{Name: "all", URL: srv.URL},
+		},
+	})
+
+	res, err := rs.InstallRelease(c, installRequest())
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	select {
+	case e := <-events:
+		if e.Event != eventInstall || e.Name != res.Release.Name || e.Status != "DEPLOYED" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for webhook")
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("unexpected second event: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
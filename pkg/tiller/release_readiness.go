@@ -0,0 +1,137 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"sync"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// readinessWatches holds the readiness reports of the releases being waited
+// on, so that clients can follow them while Tiller waits.
+type readinessWatches struct {
+	mu    sync.Mutex
+	waits map[string]*readinessWait
+	// changed is closed, and replaced, when a wait begins, ends or reports
+	// readiness.
+	changed chan struct{}
+}
+
+// readinessWait holds the readiness reports of a release being waited on.
+type readinessWait struct {
+	reports []*release.ResourceReadiness
+}
+
+// notify wakes up the watchers. It must be called with the lock held.
+func (w *readinessWatches) notify() {
+	if w.changed != nil {
+		close(w.changed)
+	}
+	w.changed = make(chan struct{})
+}
+
+// begin records that a release is being waited on, and returns the function
+// ending the wait.
+func (w *readinessWatches) begin(rlsName string) func() {
+	if w == nil {
+		return func() {}
+	}
+	wait := &readinessWait{}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.waits == nil {
+		w.waits = map[string]*readinessWait{}
+	}
+	w.waits[rlsName] = wait
+	w.notify()
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.waits[rlsName] == wait {
+			delete(w.waits, rlsName)
+			w.notify()
+		}
+	}
+}
+
+// report records a readiness report of a release being waited on.
+func (w *readinessWatches) report(rlsName string, r *release.ResourceReadiness) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if wait, ok := w.waits[rlsName]; ok {
+		wait.reports = append(wait.reports, r)
+		w.notify()
+	}
+}
+
+// since returns the wait on a release, or nil if there is none, its readiness
+// reports past the first n, and a channel closed on the next change.
+func (w *readinessWatches) since(rlsName string, n int) (*readinessWait, []*release.ResourceReadiness, <-chan struct{}) {
+	if w == nil {
+		return nil, nil, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.changed == nil {
+		w.changed = make(chan struct{})
+	}
+	wait, ok := w.waits[rlsName]
+	if !ok || n >= len(wait.reports) {
+		return wait, nil, w.changed
+	}
+	return wait, append([]*release.ResourceReadiness(nil), wait.reports[n:]...), w.changed
+}
+
+// WatchReadiness streams the readiness reports of the install, upgrade or
+// rollback in progress on a release, starting with the ones already made.
+// When the release is not being waited on, it waits for it to be. The stream
+// ends with the wait, or when the caller goes away.
+func (s *ReleaseServer) WatchReadiness(req *services.WatchReadinessRequest, stream services.ReleaseService_WatchReadinessServer) error {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("watchReadiness: Release name is invalid: %s", req.Name)
+		return err
+	}
+	if _, err := s.authenticateCaller(stream.Context()); err != nil {
+		return err
+	}
+
+	var watched *readinessWait
+	sent := 0
+	for {
+		wait, reports, changed := s.readiness.since(req.Name, sent)
+		if watched != nil && wait != watched {
+			return nil
+		}
+		watched = wait
+		for _, r := range reports {
+			if err := stream.Send(&services.WatchReadinessResponse{Readiness: r}); err != nil {
+				return err
+			}
+			sent++
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+type mockWatchReadinessServer struct {
+	mockRunReleaseTestServer
+	reports chan *release.ResourceReadiness
+}
+
+func (s mockWatchReadinessServer) Send(m *services.WatchReadinessResponse) error {
+	s.reports <- m.Readiness
+	return nil
+}
+
+func TestWatchReadiness(t *testing.T) {
+	rs := rsFixture()
+	done := rs.readiness.begin("angry-panda")
+	rs.readiness.report("angry-panda", &release.ResourceReadiness{Kind: "Deployment", Name: "web"})
+
+	stream := mockWatchReadinessServer{reports: make(chan *release.ResourceReadiness)}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- rs.WatchReadiness(&services.WatchReadinessRequest{Name: "angry-panda"}, stream)
+	}()
+
+	if r := <-stream.reports; r.Kind != "Deployment" {
+		t.Errorf("expected the report made before watching first, got %v", r)
+	}
+	rs.readiness.report("angry-panda", &release.ResourceReadiness{Kind: "Service", Name: "web", Ready: true})
+	if r := <-stream.reports; r.Kind != "Service" || !r.Ready {
+		t.Errorf("expected the report made while watching, got %v", r)
+	}
+
+	done()
+	if err := <-errc; err != nil {
+		t.Errorf("expected the stream to end with the wait, got %s", err)
+	}
+
+	if err := rs.WatchReadiness(&services.WatchReadinessRequest{}, stream); err == nil {
+		t.Error("expected a missing release name to fail")
+	}
+}
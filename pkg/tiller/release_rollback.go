@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"k8s.io/helm/pkg/storage"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
 
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -31,11 +33,43 @@ import (
 
 // RollbackRelease rolls back to a previous version of the given release.
 func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+	start := time.Now()
+	if !req.DryRun {
+		done := s.operations.start(c, req.Name, eventRollback)
+		defer done()
+	}
+	res, err := s.rollbackRelease(c, req)
+	if !req.DryRun {
+		observeRelease(eventRollback, res.GetRelease().GetNamespace(), start, err)
+		s.logOperation(eventRollback, req.Name, res.GetRelease(), start, err)
+		s.audit(c, eventRollback, req.Name, res.GetRelease(), req.OverrideWindow, err)
+	}
+	return res, err
+}
+
+func (s *ReleaseServer) rollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("rollbackRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	if !req.DryRun {
+		lc, unlock, err := s.lockRelease(c, req.Name, eventRollback, req.Timeout, req.HookTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+		c = lc
+	}
+	id, err := s.authenticateCaller(c)
+	if err != nil {
+		return nil, err
+	}
 	s.Log("preparing rollback of %s", req.Name)
 	currentRelease, targetRelease, err := s.prepareRollback(req)
 	if err != nil {
 		return nil, err
 	}
+	id.applyTo(targetRelease)
 
 	if !req.DryRun {
 		s.Log("creating rolled back release for %s", req.Name)
@@ -54,6 +88,7 @@ func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 		if err := s.env.Releases.Update(targetRelease); err != nil {
 			return res, err
 		}
+		s.notify(eventRollback, targetRelease)
 	}
 
 	return res, nil
@@ -76,6 +111,20 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 		return nil, nil, err
 	}
 
+	if err := s.checkPolicy(currentRelease.Namespace); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkFreeze(currentRelease); err != nil {
+		return nil, nil, err
+	}
+
+	if !req.DryRun {
+		if err := s.checkWindow(req.Name, currentRelease.Namespace, currentRelease, req.OverrideWindow); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	previousVersion := req.Version
 	if req.Version == 0 {
 		previousVersion = currentRelease.Version - 1
@@ -90,7 +139,7 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 
 	description := req.Description
 	if req.Description == "" {
-		description = fmt.Sprintf("Rollback to %d", previousVersion)
+		description = rollbackDescription(previousVersion, req)
 	}
 
 	// Store a new release object with previous release's configuration
@@ -103,21 +152,99 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 			FirstDeployed: currentRelease.Info.FirstDeployed,
 			LastDeployed:  timeconv.Now(),
 			Status: &release.Status{
-				Code:  release.Status_PENDING_ROLLBACK,
-				Notes: previousRelease.Info.Status.Notes,
+				Code:    release.Status_PENDING_ROLLBACK,
+				Notes:   previousRelease.Info.Status.Notes,
+				Outputs: previousRelease.Info.Status.Outputs,
 			},
 			// Because we lose the reference to previous version elsewhere, we set the
 			// message here, and only override it later if we experience failure.
-			Description: description,
+			Description:    description,
+			ServiceAccount: currentRelease.Info.ServiceAccount,
 		},
-		Version:  currentRelease.Version + 1,
-		Manifest: previousRelease.Manifest,
-		Hooks:    previousRelease.Hooks,
+		Version:     currentRelease.Version + 1,
+		Manifest:    previousRelease.Manifest,
+		Hooks:       previousRelease.Hooks,
+		Labels:      currentRelease.Labels,
+		Annotations: currentRelease.Annotations,
+	}
+
+	if req.ValuesOnly {
+		if err := s.renderValuesRollback(currentRelease, targetRelease); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(req.Only) > 0 {
+		// only the selected resources are rolled back
+		manifest, err := partialManifest(currentRelease.Manifest, targetRelease.Manifest, req.Only)
+		if err != nil {
+			return nil, nil, err
+		}
+		targetRelease.Manifest = manifest
+		targetRelease.Info.Partial = req.Only
 	}
 
 	return currentRelease, targetRelease, nil
 }
 
+// rollbackDescription describes a rollback to the given revision.
+func rollbackDescription(version int32, req *services.RollbackReleaseRequest) string {
+	what := "Rollback"
+	if req.ValuesOnly {
+		what = "Rollback of the values"
+	}
+	if len(req.Only) > 0 {
+		return fmt.Sprintf("Partial %s to %d: %s", strings.ToLower(what), version, strings.Join(req.Only, ", "))
+	}
+	return fmt.Sprintf("%s to %d", what, version)
+}
+
+// renderValuesRollback renders the chart of the current release again with
+// the values of the target release, so that only the values are rolled back.
+func (s *ReleaseServer) renderValuesRollback(currentRelease, targetRelease *release.Release) error {
+	options := chartutil.ReleaseOptions{
+		Name:      targetRelease.Name,
+		Time:      targetRelease.Info.LastDeployed,
+		Namespace: targetRelease.Namespace,
+		IsUpgrade: true,
+		Revision:  int(targetRelease.Version),
+	}
+	previous, err := previousValues(currentRelease.Chart, currentRelease)
+	if err != nil {
+		return err
+	}
+	options.PreviousValues = previous
+
+	caps, _, err := s.capabilities(false)
+	if err != nil {
+		return err
+	}
+	ch := currentRelease.Chart
+	if err := chartutil.ProcessRequirementsEnabledCaps(ch, targetRelease.Config, caps); err != nil {
+		return err
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(ch, targetRelease.Config, options, caps)
+	if err != nil {
+		return err
+	}
+
+	installOrder, _ := SortOrders(ch, targetRelease.Annotations)
+	hooks, manifestDoc, notesTxt, outputs, err := s.renderResources(ch, valuesToRender, false, false, caps.APIVersions, installOrder, targetRelease.Labels)
+	if err != nil {
+		return err
+	}
+	if err := validateManifest(s.env.KubeClient, targetRelease.Namespace, manifestDoc.Bytes()); err != nil {
+		return err
+	}
+
+	targetRelease.Chart = ch
+	targetRelease.Manifest = manifestDoc.String()
+	targetRelease.Hooks = hooks
+	targetRelease.Info.Status.Notes = notesTxt
+	targetRelease.Info.Status.Outputs = outputs
+	return nil
+}
+
 func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
 	res := &services.RollbackReleaseResponse{Release: targetRelease}
 
@@ -128,19 +255,26 @@ func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.R
 
 	// pre-rollback hooks
 	if !req.DisableHooks {
-		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PreRollback, req.Timeout); err != nil {
+		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, targetRelease.Info, hooks.PreRollback, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
+			msg := fmt.Sprintf("Rollback %q failed pre-rollback: %s", targetRelease.Name, err)
+			s.Log("warning: %s", msg)
+			markFailed(targetRelease, failedHooks, msg, err)
+			s.recordRelease(targetRelease, true)
 			return res, err
 		}
 	} else {
 		s.Log("rollback hooks disabled for %s", req.Name)
 	}
 
-	if err := s.ReleaseModule.Rollback(currentRelease, targetRelease, req, s.env); err != nil {
+	env, err := s.envFor(targetRelease)
+	if err != nil {
+		return res, err
+	}
+	if err := s.ReleaseModule.Rollback(currentRelease, targetRelease, req, env); err != nil {
 		msg := fmt.Sprintf("Rollback %q failed: %s", targetRelease.Name, err)
 		s.Log("warning: %s", msg)
 		currentRelease.Info.Status.Code = release.Status_SUPERSEDED
-		targetRelease.Info.Status.Code = release.Status_FAILED
-		targetRelease.Info.Description = msg
+		markFailed(targetRelease, failedApply, msg, err)
 		s.recordRelease(currentRelease, true)
 		s.recordRelease(targetRelease, true)
 		return res, err
@@ -148,7 +282,11 @@ func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.R
 
 	// post-rollback hooks
 	if !req.DisableHooks {
-		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PostRollback, req.Timeout); err != nil {
+		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, targetRelease.Info, hooks.PostRollback, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
+			msg := fmt.Sprintf("Rollback %q failed post-rollback: %s", targetRelease.Name, err)
+			s.Log("warning: %s", msg)
+			markFailed(targetRelease, failedHooks, msg, err)
+			s.recordRelease(targetRelease, true)
 			return res, err
 		}
 	}
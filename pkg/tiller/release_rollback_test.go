@@ -19,8 +19,12 @@ package tiller
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 )
@@ -350,3 +354,106 @@ func TestRollbackReleaseWithCustomDescription(t *testing.T) {
 		t.Errorf("Expected Description to be %q, got %q", customDescription, res.Release.Info.Description)
 	}
 }
+
+func TestRollbackReleaseFrozen(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	upgradedRel := freezeRelease(upgradeReleaseVersion(rel), time.Time{})
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	req := &services.RollbackReleaseRequest{Name: rel.Name}
+
+	_, err := rs.RollbackRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "is frozen") {
+		t.Fatalf("Expected frozen release error, got %v", err)
+	}
+}
+
+func TestRollbackReleaseValuesOnly(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Chart = buildChart()
+	upgradedRel.Chart.Templates = []*chart.Template{
+		{Name: "templates/cm.yaml", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  name: {{ .Values.name }}\n")},
+	}
+	upgradedRel.Config = &chart.Config{Raw: `name: other`}
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	req := &services.RollbackReleaseRequest{Name: rel.Name, Version: 1, ValuesOnly: true}
+	res, err := rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+
+	if !proto.Equal(res.Release.Chart, upgradedRel.Chart) {
+		t.Errorf("Expected the current chart to be kept, got %v", res.Release.Chart.Metadata)
+	}
+	if res.Release.Config.Raw != rel.Config.Raw {
+		t.Errorf("Expected the values of revision 1, got %q", res.Release.Config.Raw)
+	}
+	if !strings.Contains(res.Release.Manifest, "name: value") {
+		t.Errorf("Expected the chart to be rendered with the values of revision 1, got %q", res.Release.Manifest)
+	}
+	if expect := "Rollback of the values to 1"; res.Release.Info.Description != expect {
+		t.Errorf("Expected description %q, got %q", expect, res.Release.Info.Description)
+	}
+}
+
+func TestRollbackReleasePartial(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = `
+---
+# Source: hello/templates/web.yaml
+kind: ConfigMap
+metadata:
+  name: web
+  labels:
+    tier: frontend
+data:
+  version: "1"
+---
+# Source: hello/templates/db.yaml
+kind: ConfigMap
+metadata:
+  name: db
+  labels:
+    tier: backend
+data:
+  version: "1"`
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Manifest = strings.Replace(rel.Manifest, `version: "1"`, `version: "2"`, -1)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	req := &services.RollbackReleaseRequest{Name: rel.Name, Version: 1, Only: []string{"labels=tier=frontend"}}
+	res, err := rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+
+	docs := splitManifestDocs(res.Release.Manifest)
+	if len(docs) != 2 || !strings.Contains(docs[0].content, `version: "1"`) || !strings.Contains(docs[1].content, `version: "2"`) {
+		t.Errorf("Expected only the frontend to be rolled back, got %q", res.Release.Manifest)
+	}
+	if len(res.Release.Info.Partial) != 1 {
+		t.Errorf("Expected the rollback to be recorded as partial, got %v", res.Release.Info.Partial)
+	}
+	if expect := "Partial rollback to 1: labels=tier=frontend"; res.Release.Info.Description != expect {
+		t.Errorf("Expected description %q, got %q", expect, res.Release.Info.Description)
+	}
+
+	req.Only = []string{"labels=tier=cache"}
+	if _, err := rs.RollbackRelease(c, req); err == nil {
+		t.Error("Expected an error when no resource matches the selector")
+	}
+}
@@ -23,15 +23,20 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/technosophos/moniker"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -56,6 +61,13 @@ const (
 	notesFileSuffix = "NOTES.txt"
 )
 
+// The phases of an operation recorded on the releases it made fail.
+const (
+	failedHooks = "hooks"
+	failedApply = "apply"
+	failedWait  = "wait"
+)
+
 var (
 	// errMissingChart indicates that a chart was not provided.
 	errMissingChart = errors.New("no chart provided")
@@ -65,6 +77,8 @@ var (
 	errInvalidRevision = errors.New("invalid release revision")
 	//errInvalidName indicates that an invalid release name was provided
 	errInvalidName = errors.New("invalid release name, must match regex ^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])+$ and the length must not be longer than 53")
+	// errNoAdoption indicates that the release module cannot take ownership of existing resources.
+	errNoAdoption = errors.New("this tiller cannot take ownership of existing resources")
 )
 
 // ListDefaultLimit is the default limit for number of items returned in a list.
@@ -87,24 +101,48 @@ type ReleaseServer struct {
 	env       *environment.Environment
 	clientset kubernetes.Interface
 	Log       func(string, ...interface{})
+	// readiness holds the readiness reports of the releases being waited on.
+	readiness *readinessWatches
+	// Logger, when set, records every release operation as an entry with
+	// the release, namespace, chart and revision as fields.
+	Logger *logging.Logger
+	// CapabilitiesTTL is how long the capabilities of the cluster are cached,
+	// with 0 meaning that they are discovered for every release.
+	CapabilitiesTTL time.Duration
+	// ImpersonateUsers makes the changes to the resources of releases be
+	// applied as the users calling Tiller, whose Kubernetes token must be
+	// passed in the request metadata.
+	ImpersonateUsers bool
+	// AuditUser is recorded in the audit log as the user of the operations
+	// whose caller is not otherwise identified.
+	AuditUser string
+
+	capsCache   capabilitiesCache
+	kubeClients kubeClientCache
+	operations  operations
+	auditMu     sync.Mutex
 }
 
 // NewReleaseServer creates a new release server.
 func NewReleaseServer(env *environment.Environment, clientset kubernetes.Interface, useRemote bool) *ReleaseServer {
+	readiness := &readinessWatches{}
 	var releaseModule ReleaseModule
 	if useRemote {
 		releaseModule = &RemoteReleaseModule{}
 	} else {
 		releaseModule = &LocalReleaseModule{
 			clientset: clientset,
+			readiness: readiness,
 		}
 	}
 
 	return &ReleaseServer{
-		env:           env,
-		clientset:     clientset,
-		ReleaseModule: releaseModule,
-		Log:           func(_ string, _ ...interface{}) {},
+		env:             env,
+		clientset:       clientset,
+		ReleaseModule:   releaseModule,
+		Log:             func(_ string, _ ...interface{}) {},
+		readiness:       readiness,
+		CapabilitiesTTL: DefaultCapabilitiesTTL,
 	}
 }
 
@@ -334,12 +372,18 @@ func GetVersionSet(client discovery.ServerGroupsInterface) (chartutil.VersionSet
 	return chartutil.NewVersionSet(versions...), nil
 }
 
-func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, subNotes bool, vs chartutil.VersionSet) ([]*release.Hook, *bytes.Buffer, string, error) {
+// renderResources renders the chart, and returns its hooks, its manifest, its
+// notes and its outputs.
+func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, subNotes, strict bool, vs chartutil.VersionSet, order SortOrder, labels map[string]string) ([]*release.Hook, *bytes.Buffer, string, string, error) {
+	if err := chartutil.IsChartInstallable(ch); err != nil {
+		return nil, nil, "", "", err
+	}
+
 	// Guard to make sure Tiller is at the right version to handle this chart.
 	sver := version.GetVersion()
 	if ch.Metadata.TillerVersion != "" &&
 		!version.IsCompatibleRange(ch.Metadata.TillerVersion, sver) {
-		return nil, nil, "", fmt.Errorf("Chart incompatible with Tiller %s", sver)
+		return nil, nil, "", "", fmt.Errorf("Chart incompatible with Tiller %s", sver)
 	}
 
 	if ch.Metadata.KubeVersion != "" {
@@ -347,15 +391,27 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		gitVersion := cap.KubeVersion.String()
 		k8sVersion := strings.Split(gitVersion, "+")[0]
 		if !version.IsCompatibleRange(ch.Metadata.KubeVersion, k8sVersion) {
-			return nil, nil, "", fmt.Errorf("Chart requires kubernetesVersion: %s which is incompatible with Kubernetes %s", ch.Metadata.KubeVersion, k8sVersion)
+			return nil, nil, "", "", fmt.Errorf("Chart requires kubernetesVersion: %s which is incompatible with Kubernetes %s", ch.Metadata.KubeVersion, k8sVersion)
 		}
 	}
 
 	s.Log("rendering %s chart using values", ch.GetMetadata().Name)
 	renderer := s.engine(ch)
+	if strict {
+		e, ok := renderer.(*engine.Engine)
+		if !ok {
+			return nil, nil, "", "", fmt.Errorf("template engine of chart %s does not support strict rendering", ch.Metadata.Name)
+		}
+		// copy the engine, it is shared by all the requests
+		strictEngine := *e
+		strictEngine.Strict = true
+		renderer = &strictEngine
+	}
+	start := time.Now()
 	files, err := renderer.Render(ch, values)
+	observeRender(values, start)
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", "", err
 	}
 
 	// NOTES.txt gets rendered like all the other files, but because it's not a hook nor a resource,
@@ -380,10 +436,23 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 
 	notes := notesBuffer.String()
 
+	// outputs.yaml is pulled out the same way. Only the outputs of the chart
+	// itself are kept, they must be a map so that tools can read them.
+	outputsName := path.Join(ch.Metadata.Name, "templates", chartutil.OutputsName)
+	outputs := files[outputsName]
+	for k := range files {
+		if path.Base(k) == chartutil.OutputsName && strings.HasSuffix(path.Dir(k), "/templates") {
+			delete(files, k)
+		}
+	}
+	if err := validateOutputs(outputs); err != nil {
+		return nil, nil, "", "", fmt.Errorf("invalid %s: %s", outputsName, err)
+	}
+
 	// Sort hooks, manifests, and partials. Only hooks and manifests are returned,
 	// as partials are not used after renderer.Render. Empty manifests are also
 	// removed here.
-	hooks, manifests, err := sortManifests(files, vs, InstallOrder)
+	hooks, manifests, err := sortManifests(files, vs, order)
 	if err != nil {
 		// By catching parse errors here, we can prevent bogus releases from going
 		// to Kubernetes.
@@ -398,7 +467,11 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 			b.WriteString("\n---\n# Source: " + name + "\n")
 			b.WriteString(content)
 		}
-		return nil, b, "", err
+		return nil, b, "", "", err
+	}
+
+	if err := labelManifests(manifests, labels); err != nil {
+		return nil, nil, "", "", err
 	}
 
 	// Aggregate all valid manifests into one big doc.
@@ -408,7 +481,27 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		b.WriteString(m.Content)
 	}
 
-	return hooks, b, notes, nil
+	// Fail charts rendering more than the operator allows before anything is
+	// applied to the cluster.
+	size := int64(b.Len())
+	for _, h := range hooks {
+		size += int64(len(h.Manifest))
+	}
+	limits := s.env.Config.Get().Limits.For(ch.Metadata.Name)
+	if err := limits.Check(ch.Metadata.Name, size, int64(len(manifests)), int64(len(hooks))); err != nil {
+		return nil, nil, "", "", err
+	}
+
+	return hooks, b, notes, outputs, nil
+}
+
+// validateOutputs checks that the rendered outputs of a chart are a map.
+func validateOutputs(outputs string) error {
+	if strings.TrimSpace(outputs) == "" {
+		return nil
+	}
+	var m map[string]interface{}
+	return yaml.Unmarshal([]byte(outputs), &m)
 }
 
 // recordRelease with an update operation in case reuse has been set.
@@ -422,8 +515,57 @@ func (s *ReleaseServer) recordRelease(r *release.Release, reuse bool) {
 	}
 }
 
-func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook string, timeout int64) error {
-	kubeCli := s.env.KubeClient
+// markFailed marks a release as FAILED in the given phase of an operation. A
+// failure to apply the manifests that happened while waiting for the resources
+// is recorded as a wait failure.
+func markFailed(r *release.Release, phase, msg string, err error) {
+	if phase == failedApply && kube.IsWaitError(err) {
+		phase = failedWait
+	}
+	r.Info.Status.Code = release.Status_FAILED
+	r.Info.Description = msg
+	r.Info.FailurePhase = phase
+	r.Info.LastError = err.Error()
+}
+
+// logOperation records the outcome of a release operation that started at
+// start in the structured log.
+func (s *ReleaseServer) logOperation(op, name string, r *release.Release, start time.Time, err error) {
+	if s.Logger == nil {
+		return
+	}
+	fields := logging.Fields{
+		"operation": op,
+		"release":   name,
+		"duration":  time.Since(start).Round(time.Millisecond).String(),
+	}
+	if r != nil {
+		if r.Name != "" {
+			fields["release"] = r.Name
+		}
+		fields["namespace"] = r.Namespace
+		fields["revision"] = r.Version
+		if md := r.GetChart().GetMetadata(); md != nil {
+			fields["chart"] = fmt.Sprintf("%s-%s", md.Name, md.Version)
+		}
+	}
+	l := s.Logger.With(fields)
+	if err != nil {
+		l.Errorf("%s failed: %s", op, err)
+		return
+	}
+	l.Infof("%s succeeded", op)
+}
+
+func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace string, info *release.Info, hook string, timeout int64) error {
+	kubeCli, err := s.kubeClient(namespace, info)
+	if err != nil {
+		return err
+	}
+	if err := s.operations.cancelled(name); err != nil {
+		return err
+	}
+	kubeCli = s.withOperation(kubeCli, name)
 	code, ok := events[hook]
 	if !ok {
 		return fmt.Errorf("unknown hook %s", hook)
@@ -441,43 +583,60 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 
 	executingHooks = sortByHookWeight(executingHooks)
 
-	for _, h := range executingHooks {
-		if err := s.deleteHookByPolicy(h, hooks.BeforeHookCreation, name, namespace, hook, kubeCli); err != nil {
+	// CRDs are cluster-scoped, so they are never owned by the anchor
+	var owner *metav1.OwnerReference
+	if len(executingHooks) > 0 && hook != hooks.CRDInstall && s.env.Config.Get().Hooks.OwnerReferences {
+		if owner, err = s.ensureHookAnchor(name, namespace); err != nil {
 			return err
 		}
+	}
 
-		b := bytes.NewBufferString(h.Manifest)
-		if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
-			s.Log("warning: Release %s %s %s failed: %s", name, hook, h.Path, err)
+	ignored := map[*release.Hook]bool{}
+	for _, h := range executingHooks {
+		if err := s.deleteHookByPolicy(h, hooks.BeforeHookCreation, name, namespace, hook, kubeCli); err != nil {
 			return err
 		}
-		// No way to rewind a bytes.Buffer()?
-		b.Reset()
-		b.WriteString(h.Manifest)
-
-		// We can't watch CRDs, but need to wait until they reach the established state before continuing
-		if hook != hooks.CRDInstall {
-			if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
-				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
-				// If a hook is failed, checkout the annotation of the hook to determine whether the hook should be deleted
-				// under failed condition. If so, then clear the corresponding resource object in the hook
-				if err := s.deleteHookByPolicy(h, hooks.HookFailed, name, namespace, hook, kubeCli); err != nil {
-					return err
-				}
-				return err
-			}
-		} else {
-			if err := kubeCli.WaitUntilCRDEstablished(b, time.Duration(timeout)*time.Second); err != nil {
-				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
+
+		manifest := h.Manifest
+		if owner != nil {
+			var err error
+			if manifest, err = setOwnerReference(h.Manifest, namespace, *owner); err != nil {
+				s.Log("warning: Release %s %s %s could not be given an owner: %s", name, hook, h.Path, err)
 				return err
 			}
 		}
+
+		start := time.Now()
+		err := s.runHook(h, manifest, name, namespace, hook, timeout, kubeCli)
+		observeHook(hook, namespace, start, err)
+		s.captureHookLogs(h, manifest, namespace, kubeCli)
+		if err == nil {
+			continue
+		}
+		// If a hook is failed, checkout the annotation of the hook to determine whether the hook should be deleted
+		// under failed condition. If so, then clear the corresponding resource object in the hook
+		if err := s.deleteHookByPolicy(h, hooks.HookFailed, name, namespace, hook, kubeCli); err != nil {
+			return err
+		}
+		if h.FailurePolicy == release.Hook_IGNORE {
+			s.Log("warning: ignoring the failure of Release %s %s %s", name, hook, h.Path)
+			h.LastRun = timeconv.Now()
+			ignored[h] = true
+			continue
+		}
+		if h.FailurePolicy == release.Hook_ROLLBACK {
+			return &hookRollbackError{err: err}
+		}
+		return err
 	}
 
 	s.Log("hooks complete for %s %s", hook, name)
 	// If all hooks are succeeded, checkout the annotation of each hook to determine whether the hook should be deleted
 	// under succeeded condition. If so, then clear the corresponding resource object in each hook
 	for _, h := range executingHooks {
+		if ignored[h] {
+			continue
+		}
 		if err := s.deleteHookByPolicy(h, hooks.HookSucceeded, name, namespace, hook, kubeCli); err != nil {
 			return err
 		}
@@ -487,11 +646,167 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 	return nil
 }
 
+// hookTimeout returns the timeout hooks are run with: hookTimeout when it is
+// set, the timeout of the whole operation otherwise.
+func hookTimeout(timeout, hookTimeout int64) int64 {
+	if hookTimeout > 0 {
+		return hookTimeout
+	}
+	return timeout
+}
+
+// runHook creates the resources of a hook and waits for them to complete. A
+// failed hook is deleted and run again, after a backoff doubling each time,
+// as many times as its retries allow.
+func (s *ReleaseServer) runHook(h *release.Hook, manifest, name, namespace, hook string, timeout int64, kubeCli environment.KubeClient) error {
+	if h.Timeout > 0 {
+		timeout = h.Timeout
+	}
+	backoff := time.Duration(h.RetryBackoff) * time.Second
+	if h.RetryBackoff == 0 {
+		backoff = defaultHookRetryBackoff
+	}
+
+	var err error
+	for attempt := int32(0); ; attempt++ {
+		if err = s.runHookOnce(manifest, name, namespace, hook, h.Path, timeout, kubeCli); err == nil || attempt == h.Retries {
+			return err
+		}
+		s.Log("warning: Release %s %s %s failed, retrying in %s (%d/%d)", name, hook, h.Path, backoff, attempt+1, h.Retries)
+		opts := kube.DeleteOptions{Timeout: timeout, ShouldWait: true, Cascade: kube.CascadeForeground}
+		if err := kubeCli.DeleteWithOptions(namespace, bytes.NewBufferString(manifest), opts); err != nil {
+			s.Log("warning: Release %s %s %s could not be deleted before retrying: %s", name, hook, h.Path, err)
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.operations.context(name).Done():
+			return s.operations.cancelled(name)
+		}
+		backoff *= 2
+	}
+}
+
+// runHookOnce creates the resources of a hook and waits for them to complete.
+func (s *ReleaseServer) runHookOnce(manifest, name, namespace, hook, path string, timeout int64, kubeCli environment.KubeClient) error {
+	b := bytes.NewBufferString(manifest)
+	if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
+		s.Log("warning: Release %s %s %s failed: %s", name, hook, path, err)
+		return err
+	}
+	// No way to rewind a bytes.Buffer()?
+	b.Reset()
+	b.WriteString(manifest)
+
+	// We can't watch CRDs, but need to wait until they reach the established state before continuing
+	if hook == hooks.CRDInstall {
+		if err := kubeCli.WaitUntilCRDEstablished(b, time.Duration(timeout)*time.Second); err != nil {
+			s.Log("warning: Release %s %s %s could not complete: %s", name, hook, path, err)
+			return err
+		}
+		return nil
+	}
+	if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
+		s.Log("warning: Release %s %s %s could not complete: %s", name, hook, path, err)
+		return err
+	}
+	return nil
+}
+
+// captureHookLogs keeps the logs of the last run of a Pod or Job hook, so
+// that they can be read after the hook is deleted.
+func (s *ReleaseServer) captureHookLogs(h *release.Hook, manifest, namespace string, kubeCli environment.KubeClient) {
+	if h.Kind != "Pod" && h.Kind != "Job" {
+		return
+	}
+	logs, err := kubeCli.GetPodLogs(namespace, bytes.NewBufferString(manifest), maxHookLogBytes)
+	if err != nil {
+		s.Log("warning: could not get the logs of hook %s: %s", h.Path, err)
+		return
+	}
+	h.Logs = logs
+}
+
 func validateManifest(c environment.KubeClient, ns string, manifest []byte) error {
 	r := bytes.NewReader(manifest)
 	return c.Validate(ns, r)
 }
 
+// checkFreeze returns an error if rel is frozen. Expired freezes are ignored.
+func (s *ReleaseServer) checkFreeze(rel *release.Release) error {
+	f := relutil.GetFreeze(rel)
+	if f == nil || f.Expired(time.Now()) {
+		return nil
+	}
+	s.Log("refusing to change frozen release %s", rel.Name)
+	return f.Error(rel.Name)
+}
+
+// checkWindow returns an error if the release may not be changed now because
+// none of its maintenance windows is open. The window recorded on the current
+// revision of the release, if any, replaces the windows of the Tiller
+// configuration. A non-empty override lets the operation proceed anyway.
+func (s *ReleaseServer) checkWindow(name, namespace string, current *release.Release, override string) error {
+	windows, err := s.windowsFor(namespace, current)
+	if err != nil {
+		if override != "" {
+			return nil
+		}
+		return fmt.Errorf("%s (use --override-window REASON to proceed anyway)", err)
+	}
+	if len(windows) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var next time.Time
+	for _, w := range windows {
+		if w.Contains(now) {
+			return nil
+		}
+		if n := w.Next(now); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	if override != "" {
+		s.Log("changing %s outside of its maintenance windows: %s", name, override)
+		return nil
+	}
+
+	s.Log("refusing to change %s outside of its maintenance windows", name)
+	msg := fmt.Sprintf("release %q may only be changed during its maintenance windows", name)
+	if !next.IsZero() {
+		msg += ", the next one opens at " + next.UTC().Format(time.RFC3339)
+	}
+	return fmt.Errorf("%s (use --override-window REASON to proceed anyway)", msg)
+}
+
+// windowsFor returns the maintenance windows restricting changes to a release
+// in namespace.
+func (s *ReleaseServer) windowsFor(namespace string, current *release.Release) ([]*relutil.Window, error) {
+	if current != nil {
+		w, err := relutil.GetWindow(current)
+		if err != nil {
+			return nil, fmt.Errorf("release %q has an invalid maintenance window: %s", current.Name, err)
+		}
+		if w != nil {
+			return []*relutil.Window{w}, nil
+		}
+	}
+
+	var windows []*relutil.Window
+	for _, mw := range s.env.Config.Get().Windows {
+		if !mw.AppliesTo(namespace) {
+			continue
+		}
+		// the configuration was validated when it was loaded
+		if w, err := mw.Window(); err == nil {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
 func validateReleaseName(releaseName string) error {
 	if releaseName == "" {
 		return errMissingRelease
@@ -17,6 +17,8 @@ limitations under the License.
 package tiller
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -32,15 +34,18 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/metadata"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
 	"k8s.io/helm/pkg/tiller/environment"
@@ -166,6 +171,12 @@ func withTiller(version string) chartOption {
 	}
 }
 
+func withType(typ string) chartOption {
+	return func(opts *chartOptions) {
+		opts.Metadata.Type = typ
+	}
+}
+
 func withDependency(dependencyOpts ...chartOption) chartOption {
 	return func(opts *chartOptions) {
 		opts.Dependencies = append(opts.Dependencies, buildChart(dependencyOpts...))
@@ -181,6 +192,15 @@ func withNotes(notes string) chartOption {
 	}
 }
 
+func withOutputs(outputs string) chartOption {
+	return func(opts *chartOptions) {
+		opts.Templates = append(opts.Templates, &chart.Template{
+			Name: "templates/outputs.yaml",
+			Data: []byte(outputs),
+		})
+	}
+}
+
 func withSampleTemplates() chartOption {
 	return func(opts *chartOptions) {
 		sampleTemplates := []*chart.Template{
@@ -298,6 +318,29 @@ func namedReleaseStub(name string, status release.Status_Code) *release.Release
 	}
 }
 
+// freezeRelease freezes rel until until, or indefinitely if until is zero.
+func freezeRelease(rel *release.Release, until time.Time) *release.Release {
+	f := &relutil.Freeze{At: time.Now(), Until: until, Reason: "release week"}
+	rel.Annotations = f.Annotations()
+	return rel
+}
+
+// closedSchedule returns the schedule of a daily window of an hour that is
+// closed now.
+func closedSchedule() string {
+	return fmt.Sprintf("0 %d * * *", (time.Now().UTC().Hour()+12)%24)
+}
+
+// restrictWindow records a daily window of an hour that is closed now on rel.
+func restrictWindow(rel *release.Release) *release.Release {
+	w, err := relutil.ParseWindow(closedSchedule(), "1h", "")
+	if err != nil {
+		panic(err)
+	}
+	rel.Annotations = w.Annotations()
+	return rel
+}
+
 func upgradeReleaseVersion(rel *release.Release) *release.Release {
 	date := timestamp.Timestamp{Seconds: 242085845, Nanos: 0}
 
@@ -540,6 +583,38 @@ func (h *hookFailingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout
 	return errors.New("Failed watch")
 }
 
+// timeoutRecordingKubeClient records the timeouts hooks are watched and
+// resources are created with.
+type timeoutRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	hookTimeouts  []int64
+	createTimeout int64
+}
+
+func (kc *timeoutRecordingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	kc.hookTimeouts = append(kc.hookTimeouts, timeout)
+	return nil
+}
+
+func (kc *timeoutRecordingKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	kc.createTimeout = opts.Timeout
+	return nil
+}
+
+func newWaitFailingKubeClient() *waitFailingKubeClient {
+	return &waitFailingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+	}
+}
+
+type waitFailingKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (w *waitFailingKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	return &kube.WaitError{Err: errors.New("timed out waiting for the condition")}
+}
+
 func newDeleteFailingKubeClient() *deleteFailingKubeClient {
 	return &deleteFailingKubeClient{
 		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
@@ -558,6 +633,10 @@ func (d *deleteFailingKubeClient) DeleteWithTimeout(ns string, r io.Reader, time
 	return kube.ErrNoObjectsVisited
 }
 
+func (d *deleteFailingKubeClient) DeleteWithOptions(ns string, r io.Reader, opts kube.DeleteOptions) error {
+	return kube.ErrNoObjectsVisited
+}
+
 type mockListServer struct {
 	val *services.ListReleasesResponse
 }
@@ -588,12 +667,15 @@ func (rs mockRunReleaseTestServer) Context() context.Context       { return helm
 
 type mockHooksManifest struct {
 	Metadata struct {
-		Name        string
-		Annotations map[string]string
+		Name            string
+		Annotations     map[string]string
+		OwnerReferences []metav1.OwnerReference
 	}
 }
 type mockHooksKubeClient struct {
 	Resources map[string]*mockHooksManifest
+	// Runs counts the times each resource was watched
+	Runs map[string]int
 }
 
 var errResourceExists = errors.New("resource already exists")
@@ -626,6 +708,9 @@ func (kc *mockHooksKubeClient) Create(ns string, r io.Reader, timeout int64, sho
 
 	return nil
 }
+func (kc *mockHooksKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	return kc.Create(ns, r, opts.Timeout, opts.ShouldWait)
+}
 func (kc *mockHooksKubeClient) Get(ns string, r io.Reader) (string, error) {
 	return "", nil
 }
@@ -642,6 +727,9 @@ func (kc *mockHooksKubeClient) DeleteWithTimeout(ns string, r io.Reader, timeout
 
 	return nil
 }
+func (kc *mockHooksKubeClient) DeleteWithOptions(ns string, r io.Reader, opts kube.DeleteOptions) error {
+	return kc.DeleteWithTimeout(ns, r, opts.Timeout, opts.ShouldWait)
+}
 func (kc *mockHooksKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	paramManifest, err := kc.makeManifest(r)
 	if err != nil {
@@ -653,8 +741,18 @@ func (kc *mockHooksKubeClient) WatchUntilReady(ns string, r io.Reader, timeout i
 		return fmt.Errorf("mockHooksKubeClient.WatchUntilReady: no such resource %s found", paramManifest.Metadata.Name)
 	}
 
-	if manifest.Metadata.Annotations["mockHooksKubeClient/Emulate"] == "hook-failed" {
+	if kc.Runs == nil {
+		kc.Runs = map[string]int{}
+	}
+	kc.Runs[manifest.Metadata.Name]++
+
+	switch manifest.Metadata.Annotations["mockHooksKubeClient/Emulate"] {
+	case "hook-failed":
 		return fmt.Errorf("mockHooksKubeClient.WatchUntilReady: hook-failed")
+	case "hook-failed-once":
+		if kc.Runs[manifest.Metadata.Name] == 1 {
+			return fmt.Errorf("mockHooksKubeClient.WatchUntilReady: hook-failed")
+		}
 	}
 
 	return nil
@@ -678,10 +776,30 @@ func (kc *mockHooksKubeClient) WaitAndGetCompletedPodPhase(namespace string, rea
 	return v1.PodUnknown, nil
 }
 
+func (kc *mockHooksKubeClient) GetPodLogs(namespace string, reader io.Reader, limitBytes int64) (string, error) {
+	manifest, err := kc.makeManifest(reader)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("run %d of %s\n", kc.Runs[manifest.Metadata.Name], manifest.Metadata.Name), nil
+}
+
+func (kc *mockHooksKubeClient) GetLive(namespace string, reader io.Reader) (string, error) {
+	return "", nil
+}
+
 func (kc *mockHooksKubeClient) WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration) error {
 	return nil
 }
 
+func (kc *mockHooksKubeClient) UpdateMetadata(namespace string, reader io.Reader, change kube.MetadataChange) error {
+	return nil
+}
+
+func (kc *mockHooksKubeClient) Health(namespace string, reader io.Reader) ([]kube.ReadinessEvent, error) {
+	return []kube.ReadinessEvent{}, nil
+}
+
 func deletePolicyStub(kubeClient *mockHooksKubeClient) *ReleaseServer {
 	e := environment.New()
 	e.Releases = storage.Init(driver.NewMemory())
@@ -724,7 +842,7 @@ name: value`, hookName, extraAnnotationsStr),
 }
 
 func execHookShouldSucceed(rs *ReleaseServer, hook *release.Hook, releaseName string, namespace string, hookType string) error {
-	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, hookType, 600)
+	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, nil, hookType, 600)
 	if err != nil {
 		return fmt.Errorf("expected hook %s to be successful: %s", hook.Name, err)
 	}
@@ -732,7 +850,7 @@ func execHookShouldSucceed(rs *ReleaseServer, hook *release.Hook, releaseName st
 }
 
 func execHookShouldFail(rs *ReleaseServer, hook *release.Hook, releaseName string, namespace string, hookType string) error {
-	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, hookType, 600)
+	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, nil, hookType, 600)
 	if err == nil {
 		return fmt.Errorf("expected hook %s to be failed", hook.Name)
 	}
@@ -740,7 +858,7 @@ func execHookShouldFail(rs *ReleaseServer, hook *release.Hook, releaseName strin
 }
 
 func execHookShouldFailWithError(rs *ReleaseServer, hook *release.Hook, releaseName string, namespace string, hookType string, expectedError error) error {
-	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, hookType, 600)
+	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, nil, hookType, 600)
 	if err != expectedError {
 		return fmt.Errorf("expected hook %s to fail with error %v, got %v", hook.Name, expectedError, err)
 	}
@@ -1055,3 +1173,81 @@ func TestFailedThenSuccessfulHookWithMixedDeletePolicies(t *testing.T) {
 		t.Errorf("expected resource %s to be unexisting after hook succeeded", hook.Name)
 	}
 }
+
+func TestHookRetries(t *testing.T) {
+	defer func(backoff time.Duration) { defaultHookRetryBackoff = backoff }(defaultHookRetryBackoff)
+	defaultHookRetryBackoff = 0
+
+	ctx := newDeletePolicyContext()
+	hook := deletePolicyHookStub(ctx.HookName, map[string]string{"mockHooksKubeClient/Emulate": "hook-failed-once"}, nil)
+	hook.Retries = 2
+	if err := execHookShouldSucceed(ctx.ReleaseServer, hook, ctx.ReleaseName, ctx.Namespace, hooks.PreInstall); err != nil {
+		t.Error(err)
+	}
+	if runs := ctx.KubeClient.Runs[hook.Name]; runs != 2 {
+		t.Errorf("expected the hook to succeed on its first retry, got %d runs", runs)
+	}
+	if hook.Logs != "run 2 of migration-job\n" {
+		t.Errorf("expected the logs of the last run, got %q", hook.Logs)
+	}
+
+	ctx = newDeletePolicyContext()
+	hook = deletePolicyHookStub(ctx.HookName, map[string]string{"mockHooksKubeClient/Emulate": "hook-failed"}, nil)
+	hook.Retries = 2
+	if err := execHookShouldFail(ctx.ReleaseServer, hook, ctx.ReleaseName, ctx.Namespace, hooks.PreInstall); err != nil {
+		t.Error(err)
+	}
+	if runs := ctx.KubeClient.Runs[hook.Name]; runs != 3 {
+		t.Errorf("expected the hook to run 3 times, got %d runs", runs)
+	}
+}
+
+func TestHookFailurePolicies(t *testing.T) {
+	ctx := newDeletePolicyContext()
+	ignored := deletePolicyHookStub(ctx.HookName, map[string]string{"mockHooksKubeClient/Emulate": "hook-failed"}, nil)
+	ignored.FailurePolicy = release.Hook_IGNORE
+	next := deletePolicyHookStub("next-job", nil, nil)
+	if err := ctx.ReleaseServer.execHook([]*release.Hook{ignored, next}, ctx.ReleaseName, ctx.Namespace, nil, hooks.PreInstall, 600); err != nil {
+		t.Errorf("expected the failure of the hook to be ignored: %s", err)
+	}
+	if ctx.KubeClient.Runs["next-job"] != 1 {
+		t.Error("expected the hooks after an ignored one to run")
+	}
+
+	ctx = newDeletePolicyContext()
+	hook := deletePolicyHookStub(ctx.HookName, map[string]string{"mockHooksKubeClient/Emulate": "hook-failed"}, nil)
+	hook.FailurePolicy = release.Hook_ROLLBACK
+	err := ctx.ReleaseServer.execHook([]*release.Hook{hook}, ctx.ReleaseName, ctx.Namespace, nil, hooks.PreInstall, 600)
+	if !hookRollbackRequested(err) {
+		t.Errorf("expected a rollback to be requested, got %v", err)
+	}
+}
+
+func TestLogOperation(t *testing.T) {
+	var b bytes.Buffer
+	rs := rsFixture()
+	rs.Logger = logging.New(&b, logging.JSONFormat, logging.InfoLevel)
+
+	if _, err := rs.InstallRelease(helm.NewContext(), installRequest(withName("logged"))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %s", b.String(), err)
+	}
+	expect := map[string]interface{}{
+		"level":     "info",
+		"msg":       "install succeeded",
+		"operation": "install",
+		"release":   "logged",
+		"namespace": "spaced",
+		"revision":  float64(1),
+		"chart":     "hello-",
+	}
+	for k, v := range expect {
+		if entry[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, entry[k])
+		}
+	}
+}
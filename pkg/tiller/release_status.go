@@ -17,6 +17,7 @@ limitations under the License.
 package tiller
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -24,6 +25,7 @@ import (
 
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
 )
 
 // GetReleaseStatus gets the status information for a named release.
@@ -73,5 +75,23 @@ func (s *ReleaseServer) GetReleaseStatus(c ctx.Context, req *services.GetRelease
 		return nil, err
 	}
 	rel.Info.Status.Resources = resp
+
+	if req.Health {
+		events, err := s.env.KubeClient.Health(rel.Namespace, bytes.NewBufferString(rel.Manifest))
+		if err != nil {
+			s.Log("warning: health check for %s failed: %v", rel.Name, err)
+			return nil, err
+		}
+		for _, e := range events {
+			statusResp.Health = append(statusResp.Health, &release.ResourceReadiness{
+				Kind:      e.Kind,
+				Namespace: e.Namespace,
+				Name:      e.Name,
+				Ready:     e.Ready,
+				Message:   e.Message,
+				Time:      timeconv.Timestamp(e.Time),
+			})
+		}
+	}
 	return statusResp, nil
 }
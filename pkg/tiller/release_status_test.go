@@ -17,11 +17,14 @@ limitations under the License.
 package tiller
 
 import (
+	"io"
 	"testing"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestGetReleaseStatus(t *testing.T) {
@@ -63,3 +66,47 @@ func TestGetReleaseStatusDeleted(t *testing.T) {
 		t.Errorf("Expected %d, got %d", release.Status_DELETED, res.Info.Status.Code)
 	}
 }
+
+// healthKubeClient reports a ready deployment and a missing service.
+type healthKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (k *healthKubeClient) Health(namespace string, reader io.Reader) ([]kube.ReadinessEvent, error) {
+	return []kube.ReadinessEvent{
+		{Kind: "Deployment", Namespace: namespace, Name: "web", Ready: true, Message: "1/1 replicas ready"},
+		{Kind: "Service", Namespace: namespace, Name: "web", Message: "not found"},
+	}, nil
+}
+
+func TestGetReleaseStatusHealth(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &healthKubeClient{}
+	rel := releaseStub()
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	res, err := rs.GetReleaseStatus(c, &services.GetReleaseStatusRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	if len(res.Health) != 0 {
+		t.Errorf("Expected no health unless asked for, got %v", res.Health)
+	}
+
+	res, err = rs.GetReleaseStatus(c, &services.GetReleaseStatusRequest{Name: rel.Name, Health: true})
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	if len(res.Health) != 2 {
+		t.Fatalf("Expected 2 health reports, got %d", len(res.Health))
+	}
+	if h := res.Health[0]; h.Kind != "Deployment" || !h.Ready || h.Namespace != rel.Namespace {
+		t.Errorf("Unexpected health report %v", h)
+	}
+	if h := res.Health[1]; h.Kind != "Service" || h.Ready || h.Message != "not found" {
+		t.Errorf("Unexpected health report %v", h)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package tiller
 
 import (
+	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	reltesting "k8s.io/helm/pkg/releasetesting"
@@ -38,13 +39,28 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 		return err
 	}
 
+	parallelism := uint32(maxParallelism)
+	if req.Parallelism > 0 && req.Parallelism < parallelism {
+		parallelism = req.Parallelism
+	}
+
+	id, err := s.authenticateCaller(stream.Context())
+	if err != nil {
+		return err
+	}
+	id.applyTo(rel)
+	kubeCli, err := s.kubeClientFor(rel)
+	if err != nil {
+		return err
+	}
 	testEnv := &reltesting.Environment{
 		Namespace:   rel.Namespace,
-		KubeClient:  s.env.KubeClient,
+		KubeClient:  kubeCli,
 		Timeout:     req.Timeout,
 		Stream:      stream,
 		Parallel:    req.Parallel,
-		Parallelism: maxParallelism,
+		Parallelism: parallelism,
+		Logs:        req.Logs,
 	}
 	s.Log("running tests for release %s", rel.Name)
 	tSuite, err := reltesting.NewTestSuite(rel)
@@ -53,11 +69,26 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 		return err
 	}
 
-	if err := tSuite.Run(testEnv); err != nil {
-		s.Log("error running test suite for %s: %s", rel.Name, err)
+	if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, rel.Info, hooks.TestSetup, req.Timeout); err != nil {
+		s.Log("error running test setup hooks for %s: %s", rel.Name, err)
 		return err
 	}
 
+	runErr := tSuite.Run(testEnv)
+
+	// teardown hooks run whatever the outcome of the tests
+	if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, rel.Info, hooks.TestTeardown, req.Timeout); err != nil {
+		s.Log("error running test teardown hooks for %s: %s", rel.Name, err)
+		if runErr == nil {
+			runErr = err
+		}
+	}
+
+	if runErr != nil {
+		s.Log("error running test suite for %s: %s", rel.Name, runErr)
+		return runErr
+	}
+
 	rel.Info.Status.LastTestSuiteRun = &release.TestSuite{
 		StartedAt:   tSuite.StartedAt,
 		CompletedAt: tSuite.CompletedAt,
@@ -34,3 +34,43 @@ func TestRunReleaseTest(t *testing.T) {
 		t.Fatalf("failed to run release tests on %s: %s", rel.Name, err)
 	}
 }
+
+func TestRunReleaseTestSetupAndTeardown(t *testing.T) {
+	rs := rsFixture()
+	rel := namedReleaseStub("nemo", release.Status_DEPLOYED)
+	rel.Hooks = append(rel.Hooks,
+		&release.Hook{
+			Name:     "setup",
+			Kind:     "ConfigMap",
+			Path:     "setup",
+			Manifest: manifestWithHook,
+			Events:   []release.Hook_Event{release.Hook_TEST_SETUP},
+		},
+		&release.Hook{
+			Name:     "teardown",
+			Kind:     "ConfigMap",
+			Path:     "teardown",
+			Manifest: manifestWithHook,
+			Events:   []release.Hook_Event{release.Hook_TEST_TEARDOWN},
+		},
+	)
+	rs.env.Releases.Create(rel)
+
+	req := &services.TestReleaseRequest{Name: "nemo", Timeout: 2, Parallel: true, Parallelism: 1}
+	if err := rs.RunReleaseTest(req, mockRunReleaseTestServer{}); err != nil {
+		t.Fatalf("failed to run release tests on %s: %s", rel.Name, err)
+	}
+
+	res, err := rs.env.Releases.Get("nemo", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range res.Hooks[2:] {
+		if h.LastRun == nil {
+			t.Errorf("expected hook %s to have run", h.Name)
+		}
+	}
+	if res.Info.Status.LastTestSuiteRun == nil {
+		t.Error("expected the test suite run to be recorded")
+	}
+}
@@ -19,10 +19,12 @@ package tiller
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
 
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -31,10 +33,33 @@ import (
 
 // UninstallRelease deletes all of the resources associated with this release, and marks the release DELETED.
 func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
+	start := time.Now()
+	res, err := s.uninstallRelease(c, req)
+	observeRelease(eventDelete, res.GetRelease().GetNamespace(), start, err)
+	s.logOperation(eventDelete, req.Name, res.GetRelease(), start, err)
+	s.audit(c, eventDelete, req.Name, res.GetRelease(), req.OverrideWindow, err)
+	return res, err
+}
+
+func (s *ReleaseServer) uninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
 	if err := validateReleaseName(req.Name); err != nil {
 		s.Log("uninstallRelease: Release name is invalid: %s", req.Name)
 		return nil, err
 	}
+	if _, err := kube.PropagationPolicy(req.Cascade); err != nil {
+		return nil, err
+	}
+	lc, unlock, err := s.lockRelease(c, req.Name, eventDelete, req.Timeout, req.HookTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	c = lc
+
+	id, err := s.authenticateCaller(c)
+	if err != nil {
+		return nil, err
+	}
 
 	rels, err := s.env.Releases.History(req.Name)
 	if err != nil {
@@ -48,6 +73,18 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 	relutil.SortByRevision(rels)
 	rel := rels[len(rels)-1]
 
+	if err := s.checkPolicy(rel.Namespace); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkFreeze(rel); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkWindow(req.Name, rel.Namespace, rel, req.OverrideWindow); err != nil {
+		return nil, err
+	}
+
 	// TODO: Are there any cases where we want to force a delete even if it's
 	// already marked deleted?
 	if rel.Info.Status.Code == release.Status_DELETED {
@@ -61,14 +98,21 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		return nil, fmt.Errorf("the release named %q is already deleted", req.Name)
 	}
 
+	if !req.Force {
+		if err := s.checkDependents(rel); err != nil {
+			return nil, err
+		}
+	}
+
 	s.Log("uninstall: Deleting %s", req.Name)
+	id.applyTo(rel)
 	rel.Info.Status.Code = release.Status_DELETING
 	rel.Info.Deleted = timeconv.Now()
 	rel.Info.Description = "Deletion in progress (or silently failed)"
 	res := &services.UninstallReleaseResponse{Release: rel}
 
 	if !req.DisableHooks {
-		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, hooks.PreDelete, req.Timeout); err != nil {
+		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, rel.Info, hooks.PreDelete, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			return res, err
 		}
 	} else {
@@ -81,7 +125,11 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		s.Log("uninstall: Failed to store updated release: %s", err)
 	}
 
-	kept, errs := s.ReleaseModule.Delete(rel, req, s.env)
+	env, err := s.envFor(rel)
+	if err != nil {
+		return res, err
+	}
+	kept, errs := s.ReleaseModule.Delete(rel, req, env)
 	res.Info = kept
 
 	es := make([]string, 0, len(errs))
@@ -91,11 +139,16 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 	}
 
 	if !req.DisableHooks {
-		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, hooks.PostDelete, req.Timeout); err != nil {
+		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, rel.Info, hooks.PostDelete, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			es = append(es, err.Error())
 		}
 	}
 
+	if err := s.deleteHookAnchor(rel.Name, rel.Namespace); err != nil {
+		s.Log("uninstall: Failed to delete the hook anchor: %s", err)
+		es = append(es, err.Error())
+	}
+
 	rel.Info.Status.Code = release.Status_DELETED
 	if req.Description == "" {
 		rel.Info.Description = "Deletion complete"
@@ -103,6 +156,8 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		rel.Info.Description = req.Description
 	}
 
+	s.notify(eventDelete, rel)
+
 	if req.Purge {
 		s.Log("purge requested for %s", req.Name)
 		err := s.purgeReleases(rels...)
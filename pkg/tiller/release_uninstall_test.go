@@ -17,12 +17,18 @@ limitations under the License.
 package tiller
 
 import (
+	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestUninstallRelease(t *testing.T) {
@@ -201,6 +207,85 @@ func TestUninstallReleaseCustomDescription(t *testing.T) {
 	}
 }
 
+type cascadeRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	cascades []string
+}
+
+func (k *cascadeRecordingKubeClient) DeleteWithOptions(ns string, r io.Reader, opts kube.DeleteOptions) error {
+	k.cascades = append(k.cascades, opts.Cascade)
+	return nil
+}
+
+func TestUninstallReleaseCascade(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = `kind: ConfigMap
+metadata:
+  name: configmap-foo
+data:
+  name: value
+`
+	rs.env.Releases.Create(rel)
+	kc := &cascadeRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "angry-panda", Cascade: "sideways"}); err == nil {
+		t.Fatal("Expected an invalid cascade to fail")
+	}
+	if len(kc.cascades) != 0 {
+		t.Errorf("Expected nothing to be deleted, got %d deletions", len(kc.cascades))
+	}
+
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "angry-panda", Cascade: kube.CascadeOrphan}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+	if len(kc.cascades) == 0 {
+		t.Fatal("Expected the resources to be deleted")
+	}
+	for _, cascade := range kc.cascades {
+		if cascade != kube.CascadeOrphan {
+			t.Errorf("Expected cascade %q, got %q", kube.CascadeOrphan, cascade)
+		}
+	}
+}
+
+func TestUpdateReleaseForceDeletesWithDefaultCascade(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := namedReleaseStub("forceful-luke", release.Status_FAILED)
+	rel.Manifest = `kind: ConfigMap
+metadata:
+  name: configmap-foo
+data:
+  name: value
+`
+	rs.env.Releases.Create(rel)
+	kc := &cascadeRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	// A forced upgrade deletes the previous release without an uninstall
+	// request.
+	req := &services.UpdateReleaseRequest{
+		Name:         rel.Name,
+		DisableHooks: true,
+		Chart:        rel.Chart,
+		Force:        true,
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed forced upgrade: %s", err)
+	}
+	if len(kc.cascades) == 0 {
+		t.Fatal("Expected the resources of the previous release to be deleted")
+	}
+	for _, cascade := range kc.cascades {
+		if cascade != "" {
+			t.Errorf("Expected the default cascade, got %q", cascade)
+		}
+	}
+}
+
 func TestUninstallReleaseObjectNotFoundError(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -228,3 +313,65 @@ data:
 		t.Errorf("Expected delete error message to contain object name, got:" + err.Error())
 	}
 }
+
+func TestUninstallReleaseFrozen(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(freezeRelease(releaseStub(), time.Now().Add(time.Hour)))
+
+	req := &services.UninstallReleaseRequest{Name: "angry-panda", Purge: true}
+
+	_, err := rs.UninstallRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "is frozen until") {
+		t.Fatalf("Expected frozen release error, got %v", err)
+	}
+
+	rel, err := rs.env.Releases.Get("angry-panda", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected release to stay DEPLOYED, got %s", rel.Info.Status.Code)
+	}
+}
+
+func TestUninstallReleaseRequired(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	db := namedReleaseStub("shared-db", release.Status_DEPLOYED)
+	db.Annotations = map[string]string{relutil.ProvidesAnno: "postgres"}
+	app := namedReleaseStub("app", release.Status_DEPLOYED)
+	app.Annotations = map[string]string{relutil.RequiresAnno: "postgres, redis"}
+	gone := namedReleaseStub("gone", release.Status_DELETED)
+	gone.Annotations = map[string]string{relutil.RequiresAnno: "postgres"}
+	for _, rel := range []*release.Release{db, app, gone} {
+		rs.env.Releases.Create(rel)
+	}
+
+	_, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "shared-db"})
+	if err == nil {
+		t.Fatal("Expected a release required by another one not to be deleted")
+	}
+	if !strings.Contains(err.Error(), "app (postgres)") || strings.Contains(err.Error(), "gone") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	// Another provider of the capability lets the release be deleted.
+	replica := namedReleaseStub("replica-db", release.Status_DEPLOYED)
+	replica.Annotations = map[string]string{relutil.ProvidesAnno: "postgres"}
+	rs.env.Releases.Create(replica)
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "shared-db"}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "replica-db"}); err == nil {
+		t.Fatal("Expected the last provider of a capability not to be deleted")
+	}
+	res, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "replica-db", Force: true})
+	if err != nil {
+		t.Fatalf("Failed forced uninstall: %s", err)
+	}
+	if res.Release.Info.Status.Code != release.Status_DELETED {
+		t.Errorf("Expected status code to be DELETED, got %d", res.Release.Info.Status.Code)
+	}
+}
@@ -17,13 +17,16 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/timeconv"
@@ -31,10 +34,54 @@ import (
 
 // UpdateRelease takes an existing release and new information, and upgrades the release.
 func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+	start := time.Now()
+	if !req.DryRun {
+		done := s.operations.start(c, req.Name, eventUpgrade)
+		defer done()
+	}
+	res, err := s.updateRelease(c, req)
+	if !req.DryRun {
+		observeRelease(eventUpgrade, res.GetRelease().GetNamespace(), start, err)
+		s.logOperation(eventUpgrade, req.Name, res.GetRelease(), start, err)
+		s.audit(c, eventUpgrade, req.Name, res.GetRelease(), req.OverrideWindow, err)
+	}
+	return res, err
+}
+
+func (s *ReleaseServer) updateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	if err := validateReleaseName(req.Name); err != nil {
 		s.Log("updateRelease: Release name is invalid: %s", req.Name)
 		return nil, err
 	}
+	if !req.DryRun {
+		lc, unlock, err := s.lockRelease(c, req.Name, eventUpgrade, req.Timeout, req.HookTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+		c = lc
+	}
+	// a frozen release cannot be upgraded, even with --force
+	if lastRelease, err := s.env.Releases.Last(req.Name); err == nil {
+		if err := s.checkFreeze(lastRelease); err != nil {
+			return nil, err
+		}
+		if !req.DryRun {
+			if err := s.checkWindow(req.Name, lastRelease.Namespace, lastRelease, req.OverrideWindow); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(req.Only) > 0 && req.Force {
+		return nil, errors.New("a partial upgrade cannot be forced")
+	}
+	if err := validateReleaseLabels(req.Labels); err != nil {
+		return nil, err
+	}
+	id, err := s.authenticateCaller(c)
+	if err != nil {
+		return nil, err
+	}
 	s.Log("preparing update for %s", req.Name)
 	currentRelease, updatedRelease, err := s.prepareUpdate(req)
 	if err != nil {
@@ -42,10 +89,11 @@ func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 		if req.Force {
 			// Use the --force, Luke.
 			s.Log("performing force update for %s", req.Name)
-			return s.performUpdateForce(req)
+			return s.performUpdateForce(req, id)
 		}
 		return nil, err
 	}
+	id.applyTo(updatedRelease)
 
 	if !req.DryRun {
 		s.Log("creating updated release for %s", req.Name)
@@ -57,6 +105,9 @@ func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 	s.Log("performing update for %s", req.Name)
 	res, err := s.performUpdate(currentRelease, updatedRelease, req)
 	if err != nil {
+		if hookRollbackRequested(err) {
+			s.rollbackFailedUpgrade(c, currentRelease, req)
+		}
 		return res, err
 	}
 
@@ -65,11 +116,22 @@ func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 		if err := s.env.Releases.Update(updatedRelease); err != nil {
 			return res, err
 		}
+		s.notify(eventUpgrade, updatedRelease)
 	}
 
 	return res, nil
 }
 
+// previousValues returns the values that the chart ch sees as
+// .Release.PreviousValues when it replaces the release rel. Only the charts
+// that opt in get them.
+func previousValues(ch *chart.Chart, rel *release.Release) (chartutil.Values, error) {
+	if !chartutil.WantsPreviousValues(ch) || rel.Chart == nil {
+		return nil, nil
+	}
+	return chartutil.CoalesceValues(rel.Chart, rel.Config)
+}
+
 // prepareUpdate builds an updated release for an update operation.
 func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*release.Release, *release.Release, error) {
 	if req.Chart == nil {
@@ -82,6 +144,15 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		return nil, nil, err
 	}
 
+	if err := s.checkPolicy(currentRelease.Namespace); err != nil {
+		return nil, nil, err
+	}
+
+	serviceAccount, err := s.upgradeServiceAccount(currentRelease, req.ServiceAccount)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// determine if values will be reused
 	if err := s.reuseValues(req, currentRelease); err != nil {
 		return nil, nil, err
@@ -105,17 +176,29 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		IsUpgrade: true,
 		Revision:  int(revision),
 	}
+	options.PreviousValues, err = previousValues(req.Chart, currentRelease)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	caps, err := capabilities(s.clientset.Discovery())
+	caps, _, err := s.capabilities(false)
 	if err != nil {
 		return nil, nil, err
 	}
+	// The client cannot evaluate the conditions of the requirements that
+	// depend on the capabilities of the cluster.
+	if err := chartutil.ProcessRequirementsEnabledCaps(req.Chart, req.Values, caps); err != nil {
+		return nil, nil, err
+	}
 	valuesToRender, err := chartutil.ToRenderValuesCaps(req.Chart, req.Values, options, caps)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, req.SubNotes, caps.APIVersions)
+	labels := mergeMetadata(currentRelease.Labels, req.Labels, nil)
+	annotations := withSortOrders(currentRelease.Annotations, req.InstallOrder, req.UninstallOrder)
+	installOrder, _ := SortOrders(req.Chart, annotations)
+	hooks, manifestDoc, notesTxt, outputs, err := s.renderResources(req.Chart, valuesToRender, req.SubNotes, req.Strict, caps.APIVersions, installOrder, labels)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -127,25 +210,42 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		Chart:     req.Chart,
 		Config:    req.Values,
 		Info: &release.Info{
-			FirstDeployed: currentRelease.Info.FirstDeployed,
-			LastDeployed:  ts,
-			Status:        &release.Status{Code: release.Status_PENDING_UPGRADE},
-			Description:   "Preparing upgrade", // This should be overwritten later.
+			FirstDeployed:  currentRelease.Info.FirstDeployed,
+			LastDeployed:   ts,
+			Status:         &release.Status{Code: release.Status_PENDING_UPGRADE},
+			Description:    "Preparing upgrade", // This should be overwritten later.
+			ServiceAccount: serviceAccount,
 		},
-		Version:  revision,
-		Manifest: manifestDoc.String(),
-		Hooks:    hooks,
+		Version:     revision,
+		Manifest:    manifestDoc.String(),
+		Hooks:       hooks,
+		Labels:      labels,
+		Annotations: annotations,
 	}
 
 	if len(notesTxt) > 0 {
 		updatedRelease.Info.Status.Notes = notesTxt
 	}
-	err = validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes())
-	return currentRelease, updatedRelease, err
+	updatedRelease.Info.Status.Outputs = outputs
+	if err := validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes()); err != nil {
+		return currentRelease, updatedRelease, err
+	}
+
+	if len(req.Only) > 0 {
+		// the whole chart was rendered and validated, but only the selected
+		// resources are applied
+		manifest, err := partialManifest(currentRelease.Manifest, updatedRelease.Manifest, req.Only)
+		if err != nil {
+			return nil, nil, err
+		}
+		updatedRelease.Manifest = manifest
+		updatedRelease.Info.Partial = req.Only
+	}
+	return currentRelease, updatedRelease, nil
 }
 
 // performUpdateForce performs the same action as a `helm delete && helm install --replace`.
-func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest, id *caller) (*services.UpdateReleaseResponse, error) {
 	// find the last release with the given name
 	oldRelease, err := s.env.Releases.Last(req.Name)
 	if err != nil {
@@ -154,17 +254,26 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 
 	res := &services.UpdateReleaseResponse{}
 
+	serviceAccount, err := s.upgradeServiceAccount(oldRelease, req.ServiceAccount)
+	if err != nil {
+		return res, err
+	}
+	annotations := withSortOrders(oldRelease.Annotations, req.InstallOrder, req.UninstallOrder)
 	newRelease, err := s.prepareRelease(&services.InstallReleaseRequest{
-		Chart:        req.Chart,
-		Values:       req.Values,
-		DryRun:       req.DryRun,
-		Name:         req.Name,
-		DisableHooks: req.DisableHooks,
-		Namespace:    oldRelease.Namespace,
-		ReuseName:    true,
-		Timeout:      req.Timeout,
-		Wait:         req.Wait,
-	})
+		Chart:          req.Chart,
+		Values:         req.Values,
+		DryRun:         req.DryRun,
+		Name:           req.Name,
+		DisableHooks:   req.DisableHooks,
+		Namespace:      oldRelease.Namespace,
+		ReuseName:      true,
+		Timeout:        req.Timeout,
+		Wait:           req.Wait,
+		ServiceAccount: serviceAccount,
+		InstallOrder:   annotations[InstallOrderAnno],
+		UninstallOrder: annotations[UninstallOrderAnno],
+		Labels:         mergeMetadata(oldRelease.Labels, req.Labels, nil),
+	}, oldRelease)
 	if err != nil {
 		s.Log("failed update prepare step: %s", err)
 		// On dry run, append the manifest contents to a failed release. This is
@@ -175,8 +284,9 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 		return res, err
 	}
 
-	// update new release with next revision number so as to append to the old release's history
-	newRelease.Version = oldRelease.Version + 1
+	id.applyTo(oldRelease, newRelease)
+
+	newRelease.Annotations = annotations
 	res.Release = newRelease
 
 	if req.DryRun {
@@ -194,7 +304,7 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 
 	// pre-delete hooks
 	if !req.DisableHooks {
-		if err := s.execHook(oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, hooks.PreDelete, req.Timeout); err != nil {
+		if err := s.execHook(oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, oldRelease.Info, hooks.PreDelete, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			return res, err
 		}
 	} else {
@@ -202,7 +312,11 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 	}
 
 	// delete manifests from the old release
-	_, errs := s.ReleaseModule.Delete(oldRelease, nil, s.env)
+	oldEnv, err := s.envFor(oldRelease)
+	if err != nil {
+		return res, err
+	}
+	_, errs := s.ReleaseModule.Delete(oldRelease, nil, oldEnv)
 
 	oldRelease.Info.Status.Code = release.Status_DELETED
 	oldRelease.Info.Description = "Deletion complete"
@@ -219,35 +333,37 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 
 	// post-delete hooks
 	if !req.DisableHooks {
-		if err := s.execHook(oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, hooks.PostDelete, req.Timeout); err != nil {
+		if err := s.execHook(oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, oldRelease.Info, hooks.PostDelete, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			return res, err
 		}
 	}
 
 	// pre-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(newRelease.Hooks, newRelease.Name, newRelease.Namespace, hooks.PreInstall, req.Timeout); err != nil {
+		if err := s.execHook(newRelease.Hooks, newRelease.Name, newRelease.Namespace, newRelease.Info, hooks.PreInstall, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			return res, err
 		}
 	}
 
+	env, err := s.envFor(newRelease)
+	if err != nil {
+		return res, err
+	}
 	s.recordRelease(newRelease, false)
-	if err := s.ReleaseModule.Update(oldRelease, newRelease, req, s.env); err != nil {
+	if err := s.ReleaseModule.Update(oldRelease, newRelease, req, env); err != nil {
 		msg := fmt.Sprintf("Upgrade %q failed: %s", newRelease.Name, err)
 		s.Log("warning: %s", msg)
-		newRelease.Info.Status.Code = release.Status_FAILED
-		newRelease.Info.Description = msg
+		markFailed(newRelease, failedApply, msg, err)
 		s.recordRelease(newRelease, true)
 		return res, err
 	}
 
 	// post-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(newRelease.Hooks, newRelease.Name, newRelease.Namespace, hooks.PostInstall, req.Timeout); err != nil {
+		if err := s.execHook(newRelease.Hooks, newRelease.Name, newRelease.Namespace, newRelease.Info, hooks.PostInstall, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
 			msg := fmt.Sprintf("Release %q failed post-install: %s", newRelease.Name, err)
 			s.Log("warning: %s", msg)
-			newRelease.Info.Status.Code = release.Status_FAILED
-			newRelease.Info.Description = msg
+			markFailed(newRelease, failedHooks, msg, err)
 			s.recordRelease(newRelease, true)
 			return res, err
 		}
@@ -275,17 +391,24 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 
 	// pre-upgrade hooks
 	if !req.DisableHooks {
-		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PreUpgrade, req.Timeout); err != nil {
+		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, updatedRelease.Info, hooks.PreUpgrade, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
+			msg := fmt.Sprintf("Upgrade %q failed pre-upgrade: %s", updatedRelease.Name, err)
+			s.Log("warning: %s", msg)
+			markFailed(updatedRelease, failedHooks, msg, err)
+			s.recordRelease(updatedRelease, true)
 			return res, err
 		}
 	} else {
 		s.Log("update hooks disabled for %s", req.Name)
 	}
-	if err := s.ReleaseModule.Update(originalRelease, updatedRelease, req, s.env); err != nil {
+	env, err := s.envFor(updatedRelease)
+	if err != nil {
+		return res, err
+	}
+	if err := s.ReleaseModule.Update(originalRelease, updatedRelease, req, env); err != nil {
 		msg := fmt.Sprintf("Upgrade %q failed: %s", updatedRelease.Name, err)
 		s.Log("warning: %s", msg)
-		updatedRelease.Info.Status.Code = release.Status_FAILED
-		updatedRelease.Info.Description = msg
+		markFailed(updatedRelease, failedApply, msg, err)
 		s.recordRelease(originalRelease, true)
 		s.recordRelease(updatedRelease, true)
 		return res, err
@@ -293,7 +416,11 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 
 	// post-upgrade hooks
 	if !req.DisableHooks {
-		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PostUpgrade, req.Timeout); err != nil {
+		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, updatedRelease.Info, hooks.PostUpgrade, hookTimeout(req.Timeout, req.HookTimeout)); err != nil {
+			msg := fmt.Sprintf("Upgrade %q failed post-upgrade: %s", updatedRelease.Name, err)
+			s.Log("warning: %s", msg)
+			markFailed(updatedRelease, failedHooks, msg, err)
+			s.recordRelease(updatedRelease, true)
 			return res, err
 		}
 	}
@@ -302,7 +429,9 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 	s.recordRelease(originalRelease, true)
 
 	updatedRelease.Info.Status.Code = release.Status_DEPLOYED
-	if req.Description == "" {
+	if req.Description == "" && len(req.Only) > 0 {
+		updatedRelease.Info.Description = "Partial upgrade complete: " + strings.Join(req.Only, ", ")
+	} else if req.Description == "" {
 		updatedRelease.Info.Description = "Upgrade complete"
 	} else {
 		updatedRelease.Info.Description = req.Description
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -64,7 +65,7 @@ func TestUpdateRelease(t *testing.T) {
 		t.Errorf("Expected release namespace '%s', got '%s'.", rel.Namespace, res.Release.Namespace)
 	}
 
-	updated := compareStoredAndReturnedRelease(t, *rs, *res)
+	updated := compareStoredAndReturnedRelease(t, rs, *res)
 
 	if len(updated.Hooks) != 1 {
 		t.Fatalf("Expected 1 hook, got %d", len(updated.Hooks))
@@ -370,7 +371,7 @@ func TestUpdateRelease_ReuseValues(t *testing.T) {
 	if res.Release.Config != nil && res.Release.Config.Raw != expect {
 		t.Errorf("Expected request config to be %q, got %q", expect, res.Release.Config.Raw)
 	}
-	compareStoredAndReturnedRelease(t, *rs, *res)
+	compareStoredAndReturnedRelease(t, rs, *res)
 }
 
 func TestUpdateRelease_ResetReuseValues(t *testing.T) {
@@ -400,7 +401,7 @@ func TestUpdateRelease_ResetReuseValues(t *testing.T) {
 	if res.Release.Config != nil && res.Release.Config.Raw != "" {
 		t.Errorf("Expected chart config to be empty, got %q", res.Release.Config.Raw)
 	}
-	compareStoredAndReturnedRelease(t, *rs, *res)
+	compareStoredAndReturnedRelease(t, rs, *res)
 }
 
 func TestUpdateReleaseFailure(t *testing.T) {
@@ -431,12 +432,18 @@ func TestUpdateReleaseFailure(t *testing.T) {
 		t.Errorf("Expected FAILED release. Got %d", updatedStatus)
 	}
 
-	compareStoredAndReturnedRelease(t, *rs, *res)
+	compareStoredAndReturnedRelease(t, rs, *res)
 
 	expectedDescription := "Upgrade \"angry-panda\" failed: Failed update in kube client"
 	if got := res.Release.Info.Description; got != expectedDescription {
 		t.Errorf("Expected description %q, got %q", expectedDescription, got)
 	}
+	if got := res.Release.Info.FailurePhase; got != failedApply {
+		t.Errorf("Expected failure phase %q, got %q", failedApply, got)
+	}
+	if got := res.Release.Info.LastError; got != "Failed update in kube client" {
+		t.Errorf("Expected the last error of the kube client, got %q", got)
+	}
 
 	oldRelease, err := rs.env.Releases.Get(rel.Name, rel.Version)
 	if err != nil {
@@ -475,7 +482,7 @@ func TestUpdateReleaseFailure_Force(t *testing.T) {
 		t.Errorf("Expected DEPLOYED release. Got %d", updatedStatus)
 	}
 
-	compareStoredAndReturnedRelease(t, *rs, *res)
+	compareStoredAndReturnedRelease(t, rs, *res)
 
 	expectedDescription := "Upgrade complete"
 	if got := res.Release.Info.Description; got != expectedDescription {
@@ -559,7 +566,7 @@ func TestUpdateReleaseCustomDescription(t *testing.T) {
 	if res.Release.Info.Description != customDescription {
 		t.Errorf("Expected release description to be %q, got %q", customDescription, res.Release.Info.Description)
 	}
-	compareStoredAndReturnedRelease(t, *rs, *res)
+	compareStoredAndReturnedRelease(t, rs, *res)
 }
 
 func TestUpdateReleaseCustomDescription_Force(t *testing.T) {
@@ -584,7 +591,7 @@ func TestUpdateReleaseCustomDescription_Force(t *testing.T) {
 	if res.Release.Info.Description != customDescription {
 		t.Errorf("Expected release description to be %q, got %q", customDescription, res.Release.Info.Description)
 	}
-	compareStoredAndReturnedRelease(t, *rs, *res)
+	compareStoredAndReturnedRelease(t, rs, *res)
 }
 
 func TestUpdateReleasePendingInstall_Force(t *testing.T) {
@@ -611,7 +618,7 @@ func TestUpdateReleasePendingInstall_Force(t *testing.T) {
 	}
 }
 
-func compareStoredAndReturnedRelease(t *testing.T, rs ReleaseServer, res services.UpdateReleaseResponse) *release.Release {
+func compareStoredAndReturnedRelease(t *testing.T, rs *ReleaseServer, res services.UpdateReleaseResponse) *release.Release {
 	storedRelease, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
 	if err != nil {
 		t.Fatalf("Expected release for %s (%v).", res.Release.Name, rs.env.Releases)
@@ -623,3 +630,197 @@ func compareStoredAndReturnedRelease(t *testing.T, rs ReleaseServer, res service
 
 	return storedRelease
 }
+
+func TestUpdateReleaseFrozen(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := freezeRelease(releaseStub(), time.Time{})
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Force: true,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/hello", Data: []byte("hello: world")}},
+		},
+	}
+
+	_, err := rs.UpdateRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "is frozen: release week") {
+		t.Fatalf("Expected frozen release error, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err == nil {
+		t.Error("Expected no new revision to be stored")
+	}
+
+	// an expired freeze no longer applies
+	freezeRelease(rel, time.Now().Add(-time.Minute))
+	rs.env.Releases.Update(rel)
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Errorf("Expected update after the freeze expired, got %v", err)
+	}
+}
+
+func TestUpdateReleaseOutsideWindow(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := restrictWindow(releaseStub())
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/hello", Data: []byte("hello: world")}},
+		},
+	}
+
+	_, err := rs.UpdateRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "may only be changed during its maintenance windows, the next one opens at") {
+		t.Fatalf("Expected maintenance window error, got %v", err)
+	}
+
+	req.DryRun = true
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Errorf("Expected a dry run outside the window to succeed, got %v", err)
+	}
+
+	req.DryRun = false
+	req.OverrideWindow = "hotfix for CVE-2019-1234"
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Errorf("Expected the override to allow the update, got %v", err)
+	}
+}
+
+func TestUpdateReleasePartial(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = "\n---\n# Source: hello/templates/hello\nhello: world\n---\n# Source: hello/templates/cm\nkind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  a: \"1\""
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: mars")},
+				{Name: "templates/cm", Data: []byte("kind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  a: \"2\"")},
+			},
+		},
+		Only: []string{"templates/cm"},
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	updated := compareStoredAndReturnedRelease(t, rs, *res)
+	if !strings.Contains(updated.Manifest, "hello: world") || strings.Contains(updated.Manifest, "hello: mars") {
+		t.Errorf("Expected unselected resources to be kept, got manifest %q", updated.Manifest)
+	}
+	if !strings.Contains(updated.Manifest, `a: "2"`) {
+		t.Errorf("Expected selected resources to be updated, got manifest %q", updated.Manifest)
+	}
+	if len(updated.Info.Partial) != 1 || updated.Info.Partial[0] != "templates/cm" {
+		t.Errorf("Expected the revision to be flagged as partial, got %v", updated.Info.Partial)
+	}
+	if !strings.HasPrefix(updated.Info.Description, "Partial upgrade complete") {
+		t.Errorf("Expected a partial upgrade description, got %q", updated.Info.Description)
+	}
+
+	req.Force = true
+	if _, err := rs.UpdateRelease(c, req); err == nil {
+		t.Error("Expected a forced partial upgrade to fail")
+	}
+}
+
+func TestUpdateReleaseFailedHookRollback(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	rs.env.KubeClient = newHookFailingKubeClient()
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/hooks", Data: []byte(`kind: Job
+metadata:
+  name: smoke-test
+  annotations:
+    "helm.sh/hook": post-upgrade
+    "helm.sh/hook-failure-policy": rollback
+`)},
+			},
+		},
+	}
+	if _, err := rs.UpdateRelease(c, req); err == nil {
+		t.Fatal("Expected failed update")
+	}
+
+	last, err := rs.env.Releases.Last(rel.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Version != 3 || last.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected revision 3 to be deployed by the rollback, got revision %d %s", last.Version, last.Info.Status.Code)
+	}
+	if last.Info.Description != "Rollback to 1 after a failed hook" {
+		t.Errorf("Unexpected description %q", last.Info.Description)
+	}
+}
+
+func TestUpdateReleasePreviousValues(t *testing.T) {
+	tmpl := `previous: {{ with .Release.PreviousValues }}{{ .name }}{{ else }}none{{ end }}
+revision: {{ .Release.Revision }}
+upgrade: {{ .Release.IsUpgrade }}
+install: {{ .Release.IsInstall }}`
+	for _, tt := range []struct {
+		name     string
+		status   release.Status_Code
+		force    bool
+		optIn    bool
+		previous string
+	}{
+		{"opted-in", release.Status_DEPLOYED, false, true, "value"},
+		{"opted-out", release.Status_DEPLOYED, false, false, "none"},
+		{"forced", release.Status_FAILED, true, true, "value"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := rsFixture()
+			rel := namedReleaseStub("migrating-panda", tt.status)
+			rs.env.Releases.Create(rel)
+
+			md := &chart.Metadata{Name: "hello"}
+			if tt.optIn {
+				md.Annotations = map[string]string{chartutil.PreviousValuesAnnotation: "true"}
+			}
+			req := &services.UpdateReleaseRequest{
+				Name:         rel.Name,
+				DisableHooks: true,
+				Force:        tt.force,
+				Chart: &chart.Chart{
+					Metadata:  md,
+					Templates: []*chart.Template{{Name: "templates/hello", Data: []byte(tmpl)}},
+				},
+			}
+			res, err := rs.UpdateRelease(helm.NewContext(), req)
+			if err != nil {
+				t.Fatalf("Failed updated: %s", err)
+			}
+
+			expect := "previous: " + tt.previous + "\nrevision: 2\nupgrade: true\ninstall: false"
+			if !strings.Contains(res.Release.Manifest, expect) {
+				t.Errorf("Expected manifest to contain %q, got %q", expect, res.Release.Manifest)
+			}
+			if res.Release.Version != 2 {
+				t.Errorf("Expected revision 2, got %d", res.Release.Version)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const (
+	// InstallOrderAnno is the annotation of charts and releases replacing
+	// InstallOrder, as a comma separated list of kinds.
+	InstallOrderAnno = "helm.sh/install-order"
+
+	// UninstallOrderAnno is the annotation of charts and releases replacing
+	// UninstallOrder, as a comma separated list of kinds. When only the
+	// install order is replaced, the uninstall order is its reverse.
+	UninstallOrderAnno = "helm.sh/uninstall-order"
+
+	// WeightAnno is the annotation of resources ordering them before their
+	// kinds are: resources of lower weights are installed first and
+	// uninstalled last. Resources without it weigh 0.
+	WeightAnno = "helm.sh/weight"
+)
+
+// ParseSortOrder parses a comma separated list of kinds.
+func ParseSortOrder(kinds string) SortOrder {
+	var order SortOrder
+	for _, kind := range strings.Split(kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			order = append(order, kind)
+		}
+	}
+	return order
+}
+
+// Reverse returns the kinds of the order in reverse.
+func (o SortOrder) Reverse() SortOrder {
+	reversed := make(SortOrder, len(o))
+	for i, kind := range o {
+		reversed[len(o)-1-i] = kind
+	}
+	return reversed
+}
+
+// SortOrders returns the install and uninstall orders of the resources of a
+// release of the chart ch. The orders given by the annotations of the release
+// take precedence over those of the chart, which take precedence over
+// InstallOrder and UninstallOrder.
+func SortOrders(ch *chart.Chart, annotations map[string]string) (install, uninstall SortOrder) {
+	install, uninstall = InstallOrder, UninstallOrder
+	for _, a := range []map[string]string{ch.GetMetadata().GetAnnotations(), annotations} {
+		if order := ParseSortOrder(a[InstallOrderAnno]); len(order) > 0 {
+			install, uninstall = order, order.Reverse()
+		}
+		if order := ParseSortOrder(a[UninstallOrderAnno]); len(order) > 0 {
+			uninstall = order
+		}
+	}
+	return install, uninstall
+}
+
+// withSortOrders returns the annotations of a release with the install and
+// uninstall orders given, if any, replacing those of the annotations. The
+// annotations are not modified.
+func withSortOrders(annotations map[string]string, install, uninstall string) map[string]string {
+	if install == "" && uninstall == "" {
+		return annotations
+	}
+	merged := make(map[string]string, len(annotations)+2)
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	if install != "" {
+		merged[InstallOrderAnno] = install
+		// an uninstall order kept from before belongs to another install order
+		delete(merged, UninstallOrderAnno)
+	}
+	if uninstall != "" {
+		merged[UninstallOrderAnno] = uninstall
+	}
+	return merged
+}
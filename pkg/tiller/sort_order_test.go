@@ -0,0 +1,85 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	if got := ParseSortOrder(" Service, ,ConfigMap,"); !reflect.DeepEqual(got, SortOrder{"Service", "ConfigMap"}) {
+		t.Errorf("Unexpected order %v", got)
+	}
+	if got := ParseSortOrder(""); len(got) != 0 {
+		t.Errorf("Expected an empty order, got %v", got)
+	}
+	if got := ParseSortOrder("a,b,c").Reverse(); !reflect.DeepEqual(got, SortOrder{"c", "b", "a"}) {
+		t.Errorf("Unexpected reverse order %v", got)
+	}
+}
+
+func TestSortOrders(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{
+		Name: "ordered",
+		Annotations: map[string]string{
+			InstallOrderAnno:   "Secret,Service",
+			UninstallOrderAnno: "Service,Secret,Namespace",
+		},
+	}}
+
+	for _, test := range []struct {
+		description        string
+		chart              *chart.Chart
+		annotations        map[string]string
+		install, uninstall SortOrder
+	}{
+		{"defaults", &chart.Chart{}, nil, InstallOrder, UninstallOrder},
+		{"chart", ch, nil, SortOrder{"Secret", "Service"}, SortOrder{"Service", "Secret", "Namespace"}},
+		{"release install order", ch, map[string]string{InstallOrderAnno: "Pod,Job"}, SortOrder{"Pod", "Job"}, SortOrder{"Job", "Pod"}},
+		{"release uninstall order", ch, map[string]string{UninstallOrderAnno: "Pod"}, SortOrder{"Secret", "Service"}, SortOrder{"Pod"}},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			install, uninstall := SortOrders(test.chart, test.annotations)
+			if !reflect.DeepEqual(install, test.install) {
+				t.Errorf("Expected install order %v, got %v", test.install, install)
+			}
+			if !reflect.DeepEqual(uninstall, test.uninstall) {
+				t.Errorf("Expected uninstall order %v, got %v", test.uninstall, uninstall)
+			}
+		})
+	}
+}
+
+func TestWithSortOrders(t *testing.T) {
+	annotations := map[string]string{"owner": "ops", InstallOrderAnno: "Pod", UninstallOrderAnno: "Pod"}
+
+	if got := withSortOrders(annotations, "", ""); !reflect.DeepEqual(got, annotations) {
+		t.Errorf("Expected the annotations to be kept, got %v", got)
+	}
+
+	got := withSortOrders(annotations, "Job,Pod", "")
+	expect := map[string]string{"owner": "ops", InstallOrderAnno: "Job,Pod"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+	if annotations[InstallOrderAnno] != "Pod" {
+		t.Error("Expected the annotations not to be modified")
+	}
+}
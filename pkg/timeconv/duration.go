@@ -0,0 +1,54 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeconv
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ParseSeconds parses a timeout given either as a Go duration, such as "5m30s",
+// or as a bare number of seconds, such as "330", into a number of seconds.
+//
+// Timeouts are sent to Tiller in whole seconds, so a duration with a fraction
+// of a second is rounded up.
+func ParseSeconds(s string) (int64, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, fmt.Errorf("invalid timeout %q: must not be negative", s)
+		}
+		return secs, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: expected a duration such as 5m30s or a number of seconds", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must not be negative", s)
+	}
+	secs := int64(d / time.Second)
+	if d%time.Second != 0 {
+		secs++
+	}
+	return secs, nil
+}
+
+// FormatSeconds formats a number of seconds as a Go duration, such as "5m30s".
+func FormatSeconds(secs int64) string {
+	return (time.Duration(secs) * time.Second).String()
+}
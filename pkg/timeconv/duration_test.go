@@ -0,0 +1,58 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeconv
+
+import "testing"
+
+func TestParseSeconds(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect int64
+	}{
+		{"300", 300},
+		{"0", 0},
+		{"5m30s", 330},
+		{"1h", 3600},
+		{"1500ms", 2},
+		{"0s", 0},
+	}
+	for _, tt := range tests {
+		secs, err := ParseSeconds(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tt.in, err)
+			continue
+		}
+		if secs != tt.expect {
+			t.Errorf("%q: expected %d seconds, got %d", tt.in, tt.expect, secs)
+		}
+	}
+
+	for _, in := range []string{"", "-1", "-5m", "five minutes", "5x"} {
+		if _, err := ParseSeconds(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestFormatSeconds(t *testing.T) {
+	if s := FormatSeconds(330); s != "5m30s" {
+		t.Errorf("expected 5m30s, got %s", s)
+	}
+	if s := FormatSeconds(0); s != "0s" {
+		t.Errorf("expected 0s, got %s", s)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// Bundle holds the PEM encoded certificates and keys of a CA, of a server
+// and of a client, both signed by the CA.
+type Bundle struct {
+	CACert     []byte
+	CAKey      []byte
+	ServerCert []byte
+	ServerKey  []byte
+	ClientCert []byte
+	ClientKey  []byte
+}
+
+// GenerateBundle creates a new CA and uses it to sign a server certificate,
+// valid for hosts, and a client certificate. hosts may contain DNS names and
+// IP addresses. The certificates expire after validity.
+func GenerateBundle(hosts []string, validity time.Duration) (*Bundle, error) {
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(validity)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTmpl, err := certTemplate("helm-ca", notBefore, notAfter)
+	if err != nil {
+		return nil, err
+	}
+	caTmpl.IsCA = true
+	caTmpl.BasicConstraintsValid = true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not create CA certificate: %s", err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{CACert: pemCert(caDER)}
+	if b.CAKey, err = pemKey(caKey); err != nil {
+		return nil, err
+	}
+
+	serverTmpl, err := certTemplate("tiller-server", notBefore, notAfter)
+	if err != nil {
+		return nil, err
+	}
+	serverTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			serverTmpl.IPAddresses = append(serverTmpl.IPAddresses, ip)
+		} else {
+			serverTmpl.DNSNames = append(serverTmpl.DNSNames, h)
+		}
+	}
+	if b.ServerCert, b.ServerKey, err = signCert(serverTmpl, ca, caKey); err != nil {
+		return nil, err
+	}
+
+	clientTmpl, err := certTemplate("helm-client", notBefore, notAfter)
+	if err != nil {
+		return nil, err
+	}
+	clientTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if b.ClientCert, b.ClientKey, err = signCert(clientTmpl, ca, caKey); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func certTemplate(commonName string, notBefore, notAfter time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}, nil
+}
+
+// signCert creates a key and a certificate for it from tmpl, signed by the CA.
+func signCert(tmpl, ca *x509.Certificate, caKey crypto.Signer) (cert, key []byte, err error) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, k.Public(), caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create certificate %q: %s", tmpl.Subject.CommonName, err)
+	}
+	key, err = pemKey(k)
+	return pemCert(der), key, err
+}
+
+func pemCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func pemKey(k *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(k)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
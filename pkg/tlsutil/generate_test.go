@@ -0,0 +1,68 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+func TestGenerateBundle(t *testing.T) {
+	b, err := GenerateBundle([]string{"127.0.0.1", "localhost"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(b.CACert) {
+		t.Fatal("could not parse the CA certificate")
+	}
+	if _, err := tls.X509KeyPair(b.CACert, b.CAKey); err != nil {
+		t.Errorf("CA key pair: %s", err)
+	}
+
+	server, err := tls.X509KeyPair(b.ServerCert, b.ServerKey)
+	if err != nil {
+		t.Fatalf("server key pair: %s", err)
+	}
+	cert, err := x509.ParseCertificate(server.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"127.0.0.1", "localhost"} {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("server certificate for %s: %s", host, err)
+		}
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: roots}); err == nil {
+		t.Error("expected the server certificate not to be valid for example.com")
+	}
+
+	client, err := tls.X509KeyPair(b.ClientCert, b.ClientKey)
+	if err != nil {
+		t.Fatalf("client key pair: %s", err)
+	}
+	if cert, err = x509.ParseCertificate(client.Certificate[0]); err != nil {
+		t.Fatal(err)
+	}
+	opts := x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+	if _, err := cert.Verify(opts); err != nil {
+		t.Errorf("client certificate: %s", err)
+	}
+}
@@ -0,0 +1,25 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package trace records the file and network access of Helm, for the diagnosis
+of slow or failing commands, such as behind a proxy.
+
+Tracing is off until Enable is called. The files are read and written through
+ReadFile and WriteFile, and the HTTP clients use a Transport, so that each
+access is reported as an Event.
+*/
+package trace // import "k8s.io/helm/pkg/trace"
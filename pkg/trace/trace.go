@@ -0,0 +1,209 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a file access or a network request.
+type Event struct {
+	// Op is read or write for a file, and the method of a request.
+	Op string
+	// Path is the path of the file, or the URL of the request.
+	Path string
+	// Status is the status code of the response to a request.
+	Status int
+	// Bytes is the number of bytes read or written, or received in the
+	// body of the response.
+	Bytes int64
+	// Duration is the time the access took. For a request, it lasts until
+	// the body of the response is closed.
+	Duration time.Duration
+	// Err is the error of a failed access.
+	Err error
+}
+
+var (
+	mu      sync.RWMutex
+	root    string
+	handler func(Event)
+)
+
+// Enable reports the accesses to h. If dir is not empty, only the files under
+// dir are reported.
+func Enable(dir string, h func(Event)) {
+	if dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	root, handler = dir, h
+}
+
+// Disable stops reporting the accesses.
+func Disable() {
+	Enable("", nil)
+}
+
+// Enabled tells whether the accesses are reported.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return handler != nil
+}
+
+func report(e Event) {
+	mu.RLock()
+	h, dir := handler, root
+	mu.RUnlock()
+	if h == nil {
+		return
+	}
+	if dir != "" && (e.Op == "read" || e.Op == "write") && !under(dir, e.Path) {
+		return
+	}
+	h(e)
+}
+
+func under(dir, path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return abs == dir || strings.HasPrefix(abs, dir+string(filepath.Separator))
+}
+
+// ReadFile reads a file like ioutil.ReadFile, and reports it.
+func ReadFile(filename string) ([]byte, error) {
+	start := time.Now()
+	b, err := ioutil.ReadFile(filename)
+	report(Event{Op: "read", Path: filename, Bytes: int64(len(b)), Duration: time.Since(start), Err: err})
+	return b, err
+}
+
+// WriteFile writes a file like ioutil.WriteFile, and reports it.
+func WriteFile(filename string, data []byte, perm os.FileMode) error {
+	start := time.Now()
+	err := ioutil.WriteFile(filename, data, perm)
+	report(Event{Op: "write", Path: filename, Bytes: int64(len(data)), Duration: time.Since(start), Err: err})
+	return err
+}
+
+// ReplaceFile writes a file like WriteFile, through a temporary file renamed
+// over filename once complete. A failed write leaves the previous content of
+// filename intact, instead of a truncated file.
+func ReplaceFile(filename string, data []byte, perm os.FileMode) error {
+	start := time.Now()
+	err := replaceFile(filename, data, perm)
+	report(Event{Op: "write", Path: filename, Bytes: int64(len(data)), Duration: time.Since(start), Err: err})
+	return err
+}
+
+func replaceFile(filename string, data []byte, perm os.FileMode) error {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filename)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// Transport wraps rt to report the requests it makes. Its signature fits the
+// WrapTransport of a Kubernetes client configuration.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return &transport{rt: rt}
+}
+
+type transport struct {
+	rt http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(req)
+	if !Enabled() {
+		return resp, err
+	}
+	e := Event{Op: req.Method, Path: redact(req)}
+	if err != nil {
+		e.Duration, e.Err = time.Since(start), err
+		report(e)
+		return resp, err
+	}
+	e.Status = resp.StatusCode
+	resp.Body = &body{ReadCloser: resp.Body, start: start, event: e}
+	return resp, nil
+}
+
+// redact returns the URL of a request without its credentials.
+func redact(req *http.Request) string {
+	u := *req.URL
+	if u.User != nil {
+		u.User = nil
+	}
+	return u.String()
+}
+
+// body reports a request when the body of its response is closed.
+type body struct {
+	io.ReadCloser
+	start time.Time
+	event Event
+	once  sync.Once
+}
+
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.event.Bytes += int64(n)
+	return n, err
+}
+
+func (b *body) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.event.Duration = time.Since(b.start)
+		report(b.event)
+	})
+	return err
+}